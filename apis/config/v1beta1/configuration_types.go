@@ -76,6 +76,46 @@ type Configuration struct {
 
 	// Resources provides additional configuration options for handling the resources.
 	Resources *Resources `json:"resources,omitempty"`
+
+	// ClusterQueueInactiveGracePeriod is the time a ClusterQueue referencing
+	// a missing ResourceFlavor remains active before it is marked inactive.
+	// This avoids freezing the workloads of the ClusterQueue when the
+	// ResourceFlavor is only missing transiently, for example while it's
+	// being recreated.
+	// Defaults to 0, meaning that the ClusterQueue becomes inactive immediately.
+	ClusterQueueInactiveGracePeriod *metav1.Duration `json:"clusterQueueInactiveGracePeriod,omitempty"`
+
+	// AdmittedButUnschedulableThreshold is the time the pods of an admitted
+	// workload can remain unschedulable, according to their PodScheduled
+	// condition, after the job is started before the workload is marked with
+	// the AdmittedButUnschedulable condition.
+	// This helps detecting a mismatch between the quotas and the actual
+	// capacity of the cluster.
+	// The pods are listed by the labels of their job, without caching them,
+	// only while the pods of the job are not ready. Only the Job, JobSet,
+	// MPIJob, Kubeflow training jobs and RayCluster integrations are monitored.
+	// Defaults to nil, meaning that the pods are not monitored.
+	AdmittedButUnschedulableThreshold *metav1.Duration `json:"admittedButUnschedulableThreshold,omitempty"`
+
+	// EvictionOrdering controls the order in which the candidates for
+	// preemption are evicted.
+	EvictionOrdering *EvictionOrdering `json:"evictionOrdering,omitempty"`
+
+	// AdmissionChecks controls the behavior of the workloads that reserved
+	// quota while their admission checks are pending.
+	// +optional
+	AdmissionChecks *AdmissionChecks `json:"admissionChecks,omitempty"`
+
+	// Workloads controls the validation of the Workloads.
+	// +optional
+	Workloads *Workloads `json:"workloads,omitempty"`
+
+	// ExternalAdmission configures the external admission AdmissionCheck
+	// controller, which asks an external service to approve or deny the
+	// admission of the workloads in the ClusterQueues that use it.
+	// The controller is disabled when not set.
+	// +optional
+	ExternalAdmission *ExternalAdmission `json:"externalAdmission,omitempty"`
 }
 
 type ControllerManager struct {
@@ -335,6 +375,18 @@ type Integrations struct {
 	// during the workload creation and are not updated even if the labels of the
 	// underlying job are changed.
 	LabelKeysToCopy []string `json:"labelKeysToCopy,omitempty"`
+
+	// QueueNameEnforcement configures the namespaces in which jobs must
+	// specify a queue name. When not set, jobs without a queue name are
+	// accepted in all namespaces.
+	QueueNameEnforcement *QueueNameEnforcement `json:"queueNameEnforcement,omitempty"`
+}
+
+type QueueNameEnforcement struct {
+	// NamespaceSelector selects the namespaces in which the webhooks reject
+	// jobs that don't have a queue name. Plain Pods are only checked when they
+	// match the podOptions selectors of the pod integration.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 }
 
 type PodIntegrationOptions struct {
@@ -377,6 +429,76 @@ const (
 	LessThanInitialShare        PreemptionStrategy = "LessThanInitialShare"
 )
 
+// EvictionOrdering combines the priority and the elapsed runtime of the
+// candidates for preemption into a score. Candidates with a lower score are
+// evicted first.
+type EvictionOrdering struct {
+	// priorityWeight is the weight of the priority of a workload in the score.
+	// Defaults to 1.
+	PriorityWeight *int32 `json:"priorityWeight,omitempty"`
+
+	// runtimeWeight is the weight of every minute elapsed since a workload
+	// reserved quota in the score. A positive weight favors the eviction of
+	// the workloads that ran for less time, wasting less compute, even if they
+	// have a higher priority.
+	// Defaults to 0, meaning that the elapsed runtime is only used to break
+	// ties between workloads with the same priority.
+	RuntimeWeight *int32 `json:"runtimeWeight,omitempty"`
+}
+
+type AdmissionChecksTimeoutPolicy string
+
+const (
+	// AdmissionChecksTimeoutRequeue indicates that the workload is requeued.
+	AdmissionChecksTimeoutRequeue AdmissionChecksTimeoutPolicy = "Requeue"
+	// AdmissionChecksTimeoutDeactivate indicates that the workload is deactivated.
+	AdmissionChecksTimeoutDeactivate AdmissionChecksTimeoutPolicy = "Deactivate"
+)
+
+type AdmissionChecks struct {
+	// Timeout defines the time for a workload that reserved quota to have
+	// all its admission checks Ready. When the timeout is reached, the quota
+	// reservation is released, so that it doesn't hold the quota indefinitely
+	// if a check never completes, and the workload is handled according to
+	// the TimeoutPolicy. The time counts from the quota reservation of every
+	// workload.
+	// Defaults to nil, meaning that there is no timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TimeoutPolicy defines what happens to the workloads whose admission
+	// checks exceeded the timeout. The possible values are:
+	//
+	// - `Requeue` (default) indicates that the workload is requeued, and its
+	//   admission checks are evaluated again after it reserves quota.
+	// - `Deactivate` indicates that the workload is deactivated
+	//   (`.spec.active`=`false`).
+	//
+	// +optional
+	TimeoutPolicy *AdmissionChecksTimeoutPolicy `json:"timeoutPolicy,omitempty"`
+}
+
+type ExternalAdmission struct {
+	// URL is the HTTP or HTTPS endpoint that receives a POST request with the
+	// proposed admission of every workload, including the ClusterQueue and
+	// the assigned flavors, and responds whether the admission is allowed.
+	URL string `json:"url"`
+
+	// Timeout is the maximum time to wait for a response of the endpoint.
+	// Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+type Workloads struct {
+	// AllowZeroCountPodSets indicates whether Workloads can be created with
+	// podSets of count 0. Such podSets are placeholders that don't consume
+	// quota, for example, for the empty replicated jobs of a JobSet.
+	// When false, the creation of these Workloads is rejected.
+	// Defaults to true.
+	AllowZeroCountPodSets *bool `json:"allowZeroCountPodSets,omitempty"`
+}
+
 type FairSharing struct {
 	// enable indicates whether to enable fair sharing for all cohorts.
 	// Defaults to false.
@@ -49,6 +49,7 @@ const (
 	DefaultMultiKueueWorkerLostTimeout                  = 15 * time.Minute
 	DefaultRequeuingBackoffBaseSeconds                  = 60
 	DefaultRequeuingBackoffMaxSeconds                   = 3600
+	DefaultExternalAdmissionTimeout                     = 10 * time.Second
 )
 
 func getOperatorNamespace() string {
@@ -193,4 +194,13 @@ func SetDefaults_Configuration(cfg *Configuration) {
 	if fs := cfg.FairSharing; fs != nil && fs.Enable && len(fs.PreemptionStrategies) == 0 {
 		fs.PreemptionStrategies = []PreemptionStrategy{LessThanOrEqualToFinalShare, LessThanInitialShare}
 	}
+	if wl := cfg.Workloads; wl != nil && wl.AllowZeroCountPodSets == nil {
+		wl.AllowZeroCountPodSets = ptr.To(true)
+	}
+	if ac := cfg.AdmissionChecks; ac != nil && ac.TimeoutPolicy == nil {
+		ac.TimeoutPolicy = ptr.To(AdmissionChecksTimeoutRequeue)
+	}
+	if ea := cfg.ExternalAdmission; ea != nil && ea.Timeout == nil {
+		ea.Timeout = &metav1.Duration{Duration: DefaultExternalAdmissionTimeout}
+	}
 }
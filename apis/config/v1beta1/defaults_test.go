@@ -579,6 +579,78 @@ func TestSetDefaults_Configuration(t *testing.T) {
 				},
 			},
 		},
+		"add default admission checks timeout policy": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				AdmissionChecks: &AdmissionChecks{
+					Timeout: &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			want: &Configuration{
+				Namespace:         ptr.To(DefaultNamespace),
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+				QueueVisibility:  defaultQueueVisibility,
+				MultiKueue:       defaultMultiKueue,
+				AdmissionChecks: &AdmissionChecks{
+					Timeout:       &metav1.Duration{Duration: 10 * time.Minute},
+					TimeoutPolicy: ptr.To(AdmissionChecksTimeoutRequeue),
+				},
+			},
+		},
+		"add default allowance of zero count podSets": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				Workloads: &Workloads{},
+			},
+			want: &Configuration{
+				Namespace:         ptr.To(DefaultNamespace),
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+				QueueVisibility:  defaultQueueVisibility,
+				MultiKueue:       defaultMultiKueue,
+				Workloads: &Workloads{
+					AllowZeroCountPodSets: ptr.To(true),
+				},
+			},
+		},
+		"add default external admission timeout": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ExternalAdmission: &ExternalAdmission{
+					URL: "https://planner.example.com/admit",
+				},
+			},
+			want: &Configuration{
+				Namespace:         ptr.To(DefaultNamespace),
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+				QueueVisibility:  defaultQueueVisibility,
+				MultiKueue:       defaultMultiKueue,
+				ExternalAdmission: &ExternalAdmission{
+					URL:     "https://planner.example.com/admit",
+					Timeout: &metav1.Duration{Duration: DefaultExternalAdmissionTimeout},
+				},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -27,6 +27,31 @@ import (
 	timex "time"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionChecks) DeepCopyInto(out *AdmissionChecks) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TimeoutPolicy != nil {
+		in, out := &in.TimeoutPolicy, &out.TimeoutPolicy
+		*out = new(AdmissionChecksTimeoutPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionChecks.
+func (in *AdmissionChecks) DeepCopy() *AdmissionChecks {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionChecks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientConnection) DeepCopyInto(out *ClientConnection) {
 	*out = *in
@@ -117,6 +142,36 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(Resources)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClusterQueueInactiveGracePeriod != nil {
+		in, out := &in.ClusterQueueInactiveGracePeriod, &out.ClusterQueueInactiveGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AdmittedButUnschedulableThreshold != nil {
+		in, out := &in.AdmittedButUnschedulableThreshold, &out.AdmittedButUnschedulableThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.EvictionOrdering != nil {
+		in, out := &in.EvictionOrdering, &out.EvictionOrdering
+		*out = new(EvictionOrdering)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdmissionChecks != nil {
+		in, out := &in.AdmissionChecks, &out.AdmissionChecks
+		*out = new(AdmissionChecks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = new(Workloads)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalAdmission != nil {
+		in, out := &in.ExternalAdmission, &out.ExternalAdmission
+		*out = new(ExternalAdmission)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
@@ -242,6 +297,51 @@ func (in *ControllerWebhook) DeepCopy() *ControllerWebhook {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvictionOrdering) DeepCopyInto(out *EvictionOrdering) {
+	*out = *in
+	if in.PriorityWeight != nil {
+		in, out := &in.PriorityWeight, &out.PriorityWeight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RuntimeWeight != nil {
+		in, out := &in.RuntimeWeight, &out.RuntimeWeight
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvictionOrdering.
+func (in *EvictionOrdering) DeepCopy() *EvictionOrdering {
+	if in == nil {
+		return nil
+	}
+	out := new(EvictionOrdering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAdmission) DeepCopyInto(out *ExternalAdmission) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAdmission.
+func (in *ExternalAdmission) DeepCopy() *ExternalAdmission {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAdmission)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FairSharing) DeepCopyInto(out *FairSharing) {
 	*out = *in
@@ -285,6 +385,11 @@ func (in *Integrations) DeepCopyInto(out *Integrations) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.QueueNameEnforcement != nil {
+		in, out := &in.QueueNameEnforcement, &out.QueueNameEnforcement
+		*out = new(QueueNameEnforcement)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Integrations.
@@ -382,6 +487,26 @@ func (in *PodIntegrationOptions) DeepCopy() *PodIntegrationOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueNameEnforcement) DeepCopyInto(out *QueueNameEnforcement) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueNameEnforcement.
+func (in *QueueNameEnforcement) DeepCopy() *QueueNameEnforcement {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueNameEnforcement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QueueVisibility) DeepCopyInto(out *QueueVisibility) {
 	*out = *in
@@ -486,3 +611,23 @@ func (in *WaitForPodsReady) DeepCopy() *WaitForPodsReady {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Workloads) DeepCopyInto(out *Workloads) {
+	*out = *in
+	if in.AllowZeroCountPodSets != nil {
+		in, out := &in.AllowZeroCountPodSets, &out.AllowZeroCountPodSets
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workloads.
+func (in *Workloads) DeepCopy() *Workloads {
+	if in == nil {
+		return nil
+	}
+	out := new(Workloads)
+	in.DeepCopyInto(out)
+	return out
+}
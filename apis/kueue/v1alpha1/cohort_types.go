@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// CohortSpec defines the desired state of Cohort
+type CohortSpec struct {
+	// parent is the name of the cohort that contains this cohort.
+	// The ClusterQueues in a hierarchy of cohorts share their quota: a
+	// ClusterQueue borrows the unused quota of the ClusterQueues of its
+	// cohort first, and then the one of the ClusterQueues of each ancestor,
+	// up to the root cohort.
+	// The borrowingCaps, reclaimDelay and borrowingSafetyMarginPercent of a
+	// cohort apply to the ClusterQueues of its subtree.
+	// If the parents of a cohort form a cycle, the cohort is treated as a
+	// root.
+	// If empty, the cohort is a root.
+	//
+	// Validation of a parent name is equivalent to that of object names:
+	// subdomain in DNS (RFC 1123).
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
+	// +optional
+	Parent string `json:"parent,omitempty"`
+
+	// borrowingCaps limit, per flavor and resource, the total amount of quota
+	// that the ClusterQueues in the cohort can borrow at a given time.
+	// The quota borrowed by a ClusterQueue is its usage above its nominalQuota.
+	// Borrowing stops once the cap is reached, even if the individual
+	// ClusterQueues didn't reach their borrowingLimit.
+	// If a [flavor, resource] combination is not listed, the amount of quota
+	// that can be borrowed in the cohort is not capped.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	BorrowingCaps []FlavorBorrowingCaps `json:"borrowingCaps,omitempty"`
+
+	// reclaimDelay is the minimum duration that a workload borrowing quota
+	// in the cohort is protected from being preempted to reclaim the quota,
+	// counted from the time the workload got its quota reserved.
+	// It gives borrowers a stable window and avoids preemptions right after
+	// admission when the lender ClusterQueue gets new workloads.
+	// Preemptions within the ClusterQueue of the workload are not delayed.
+	// If not set or zero, borrowed quota can be reclaimed at any time.
+	//
+	// +optional
+	ReclaimDelay *metav1.Duration `json:"reclaimDelay,omitempty"`
+
+	// borrowingSafetyMarginPercent is the percentage of the quota of the
+	// cohort, for every flavor and resource, that can't be borrowed.
+	// A ClusterQueue can only borrow while the total usage in the cohort,
+	// including the borrowed quota, leaves the margin unused. This keeps
+	// some capacity free for the ClusterQueues that need their nominal quota
+	// back, reducing the preemptions to reclaim it when many ClusterQueues
+	// borrow at the same time.
+	// Usage within the nominal quota of a ClusterQueue is not affected.
+	// If not set or zero, all the unused quota can be borrowed.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	BorrowingSafetyMarginPercent *int32 `json:"borrowingSafetyMarginPercent,omitempty"`
+}
+
+type FlavorBorrowingCaps struct {
+	// name of the ResourceFlavor.
+	Name kueue.ResourceFlavorReference `json:"name"`
+
+	// resources is the list of caps for the resources of this flavor.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	Resources []ResourceBorrowingCap `json:"resources"`
+}
+
+type ResourceBorrowingCap struct {
+	// name of the resource.
+	Name corev1.ResourceName `json:"name"`
+
+	// cap is the maximum quantity of this resource that the ClusterQueues in
+	// the cohort can borrow in total.
+	// The cap must be non-negative.
+	Cap resource.Quantity `json:"cap"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster
+
+// Cohort is the Schema for the cohorts API. A Cohort configures the cohort
+// with the same name that ClusterQueues join through .spec.cohort.
+type Cohort struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CohortSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CohortList contains a list of Cohort
+type CohortList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Cohort `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Cohort{}, &CohortList{})
+}
@@ -80,6 +80,119 @@ type ClusterQueueSpec struct {
 	// +kubebuilder:default={}
 	FlavorFungibility *FlavorFungibility `json:"flavorFungibility,omitempty"`
 
+	// flavorSelectionStrategy determines which flavor is assigned to a workload
+	// that fits in the nominal quota of multiple flavors of a resource group.
+	// The possible values are:
+	//
+	// - `FirstFit` (default): assign the first flavor, in the order of the
+	//   resource group, in which the workload fits.
+	// - `BestFit`: assign the flavor in which the workload fits leaving the
+	//   least unused quota, keeping the larger flavors available for bigger
+	//   workloads.
+	// - `LeastContended`: assign the flavor in which the workload fits where
+	//   the other ClusterQueues in the cohort are borrowing the least quota,
+	//   reducing future reclaim conflicts.
+	//
+	// +kubebuilder:validation:Enum=FirstFit;BestFit;LeastContended
+	// +optional
+	FlavorSelectionStrategy FlavorSelectionStrategy `json:"flavorSelectionStrategy,omitempty"`
+
+	// readmissionFlavorPolicy determines which flavors are assigned to a
+	// workload that is admitted again after losing its quota reservation, for
+	// example, after being preempted. The possible values are:
+	//
+	// - `None` (default): the flavors are assigned as for any other workload.
+	// - `PreferPrevious`: assign the flavor that the workload had in its
+	//   previous admission, as recorded in `.status.previousFlavors`, if the
+	//   workload fits in it without borrowing. Otherwise, or if the flavor is
+	//   no longer in the ClusterQueue, assign another flavor. This preserves
+	//   data locality and warm caches.
+	//
+	// +kubebuilder:validation:Enum=None;PreferPrevious
+	// +optional
+	ReadmissionFlavorPolicy ReadmissionFlavorPolicy `json:"readmissionFlavorPolicy,omitempty"`
+
+	// workloadSizeOrdering breaks the ties in the queueing order between the
+	// pending workloads with the same priority and timestamp, based on their
+	// size, measured as their total number of pods. The possible values are:
+	//
+	// - `None` (default): no tie-break by size.
+	// - `SmallerFirst`: the smaller workloads go first, maximizing the number
+	//   of workloads admitted.
+	// - `LargerFirst`: the larger workloads go first, preventing them from
+	//   starving behind the smaller ones.
+	//
+	// Workloads of the same size are ordered by namespace and name, so that
+	// the order is deterministic.
+	//
+	// +kubebuilder:validation:Enum=None;SmallerFirst;LargerFirst
+	// +optional
+	WorkloadSizeOrdering WorkloadSizeOrdering `json:"workloadSizeOrdering,omitempty"`
+
+	// resourceAliases declares resource names that count against the quota
+	// of another resource in this ClusterQueue. This allows governing devices
+	// exposed under different names, for example by different vendors, with a
+	// single quota. The requests of the pods for an alias are accounted, and
+	// recorded in the admission of the Workloads, as requests for the
+	// resource that it aliases.
+	// An alias cannot be listed in more than one entry, nor be covered by the
+	// resourceGroups or aliased itself.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	ResourceAliases []ResourceAlias `json:"resourceAliases,omitempty"`
+
+	// resourceSlices declares resource names that are slices of another
+	// resource in this ClusterQueue, such as the MIG profiles of a GPU. The
+	// requests of the pods of a podSet for a slice are aggregated and
+	// accounted, and recorded in the admission of the Workloads, as requests
+	// for the resource that it slices, rounded up to whole units.
+	// A slice cannot be covered by the resourceGroups, be a resource alias
+	// or be sliced itself.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	ResourceSlices []ResourceSlice `json:"resourceSlices,omitempty"`
+
+	// resourceTransforms adjust the requests of the containers of the
+	// Workloads submitted to this ClusterQueue before they are accounted
+	// against the quota. They are applied after the requests are defaulted
+	// from the LimitRanges, the RuntimeClass overhead and the limits.
+	// This allows, for example, reserving additional memory for the
+	// system overhead of every container.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	ResourceTransforms []ResourceTransform `json:"resourceTransforms,omitempty"`
+
+	// integerResources are the resources that can only be allocated in whole
+	// units, such as GPUs. Their quotas must be integers, and the Workloads
+	// whose containers request a fractional quantity of them are not admitted,
+	// as their pods could never be scheduled.
+	//
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	IntegerResources []corev1.ResourceName `json:"integerResources,omitempty"`
+
+	// quotaWindows are periods, started on a cron schedule, during which the
+	// quotas of some flavors of the resourceGroups are replaced, for example,
+	// to provide a larger nominal quota at night. When several windows are
+	// active, the first one in the list applies. Outside of the windows, the
+	// quotas of the resourceGroups apply.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	QuotaWindows []QuotaWindow `json:"quotaWindows,omitempty"`
+
 	// preemption describes policies to preempt Workloads from this ClusterQueue
 	// or the ClusterQueue's cohort.
 	//
@@ -127,6 +240,91 @@ type ClusterQueueSpec struct {
 	FairSharing *FairSharing `json:"fairSharing,omitempty"`
 }
 
+type ResourceAlias struct {
+	// name of the resource whose quota is used by the aliases.
+	Name corev1.ResourceName `json:"name"`
+
+	// aliases is the list of resource names that count against the quota of
+	// the resource.
+	//
+	// +listType=set
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	Aliases []corev1.ResourceName `json:"aliases"`
+}
+
+type ResourceSlice struct {
+	// name of the resource requested by the pods, for example
+	// nvidia.com/mig-1g.5gb.
+	Name corev1.ResourceName `json:"name"`
+
+	// resource is the name of the resource whose quota is used by the slices,
+	// for example nvidia.com/gpu.
+	Resource corev1.ResourceName `json:"resource"`
+
+	// slicesPerUnit is the number of slices that make a whole unit of the
+	// resource. For example, 7 for the nvidia.com/mig-1g.5gb profile of an
+	// A100 GPU.
+	//
+	// +kubebuilder:validation:Minimum=1
+	SlicesPerUnit int32 `json:"slicesPerUnit"`
+}
+
+type QuotaWindow struct {
+	// name of the window.
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// schedule is the cron schedule, evaluated in UTC, at which the window
+	// starts. It uses the standard format of five fields: minute, hour, day
+	// of the month, month and day of the week. For example, "0 22 * * 1-5"
+	// starts the window at 22:00 from Monday to Friday.
+	// +kubebuilder:validation:MaxLength=128
+	Schedule string `json:"schedule"`
+
+	// duration is how long the window lasts each time it starts. It must be
+	// positive.
+	Duration metav1.Duration `json:"duration"`
+
+	// flavors are the quotas that replace those of the same flavors and
+	// resources in the resourceGroups during the window. Only the resources
+	// of the flavors are used; the resources not listed keep their quotas.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	Flavors []FlavorQuotas `json:"flavors"`
+}
+
+type ResourceTransform struct {
+	// name of the resource whose requests are transformed.
+	Name corev1.ResourceName `json:"name"`
+
+	// multiplier is the factor by which the requests of every container for
+	// the resource are multiplied, with a precision of up to three decimal
+	// places. It must be positive. The results are rounded up.
+	//
+	// +optional
+	Multiplier *resource.Quantity `json:"multiplier,omitempty"`
+
+	// minimum is the lowest request of every container requesting the
+	// resource. Requests below it are raised to it, while the containers
+	// not requesting the resource are left unchanged. The minimum is applied
+	// after the multiplier.
+	//
+	// +optional
+	Minimum *resource.Quantity `json:"minimum,omitempty"`
+
+	// podDefault is the request for the resource of the pods whose
+	// containers don't request it at all, which is added to their first
+	// container. This prevents admitting such pods without accounting any
+	// quota for the resource. It's applied after the multiplier and the
+	// minimum.
+	//
+	// +optional
+	PodDefault *resource.Quantity `json:"podDefault,omitempty"`
+}
+
 // AdmissionCheckStrategy defines a strategy for a AdmissionCheck.
 type AdmissionChecksStrategy struct {
 	// admissionChecks is a list of strategies for AdmissionChecks
@@ -195,6 +393,16 @@ type FlavorQuotas struct {
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:MaxItems=16
 	Resources []ResourceQuota `json:"resources"`
+
+	// warnThreshold is the percentage of the nominalQuota of each resource in
+	// this flavor that, once reached by the usage of the admitted Workloads,
+	// sets the ResourceNearCapacity condition of the ClusterQueue to True and
+	// emits a warning Event.
+	// The threshold is only observational and doesn't affect scheduling.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	WarnThreshold *int32 `json:"warnThreshold,omitempty"`
 }
 
 type ResourceQuota struct {
@@ -239,6 +447,19 @@ type ResourceQuota struct {
 	// enable the feature gate LendingLimit, which is disabled by default.
 	// +optional
 	LendingLimit *resource.Quantity `json:"lendingLimit,omitempty"`
+
+	// nonLendableQuota is the amount of the nominalQuota for the [flavor, resource]
+	// combination that is never lent to other ClusterQueues in the same cohort,
+	// even when it is unused, so that it's always immediately available for
+	// the Workloads of this ClusterQueue.
+	// It takes precedence over lendingLimit: at most nominalQuota - nonLendableQuota
+	// can be lent.
+	// If not null, it must be non-negative and less than or equal to the nominalQuota.
+	// nonLendableQuota must be null if spec.cohort is empty.
+	// This field is in alpha stage. To be able to use this field,
+	// enable the feature gate LendingLimit, which is disabled by default.
+	// +optional
+	NonLendableQuota *resource.Quantity `json:"nonLendableQuota,omitempty"`
 }
 
 // ResourceFlavorReference is the name of the ResourceFlavor.
@@ -296,6 +517,41 @@ type ClusterQueueStatus struct {
 	// FairSharing contains the information about the current status of fair sharing.
 	// +optional
 	FairSharing *FairSharingStatus `json:"fairSharing,omitempty"`
+
+	// headAdmissionEstimate is a coarse estimate of when the workload at the
+	// head of the ClusterQueue is likely to be admitted, based on the rate at
+	// which the quota was recently released.
+	// It is only reported when the HeadAdmissionEstimate feature is enabled
+	// and there are pending workloads.
+	// +optional
+	HeadAdmissionEstimate *HeadAdmissionEstimate `json:"headAdmissionEstimate,omitempty"`
+}
+
+type AdmissionEstimateState string
+
+const (
+	// AdmissionEstimateKnown means that there is enough recent data to
+	// estimate the admission time.
+	AdmissionEstimateKnown AdmissionEstimateState = "Known"
+
+	// AdmissionEstimateUnknown means that the quota wasn't released recently
+	// often enough, or at all, to estimate the admission time.
+	AdmissionEstimateUnknown AdmissionEstimateState = "Unknown"
+)
+
+type HeadAdmissionEstimate struct {
+	// workload is the workload at the head of the ClusterQueue.
+	Workload ClusterQueuePendingWorkload `json:"workload"`
+
+	// state is Known when the admission time could be estimated, or Unknown
+	// otherwise.
+	// +kubebuilder:validation:Enum=Known;Unknown
+	State AdmissionEstimateState `json:"state"`
+
+	// estimatedAdmissionTime is when the workload is likely to be admitted.
+	// It is only set when the state is Known.
+	// +optional
+	EstimatedAdmissionTime *metav1.Time `json:"estimatedAdmissionTime,omitempty"`
 }
 
 type ClusterQueuePendingWorkloadsStatus struct {
@@ -340,6 +596,13 @@ type ResourceUsage struct {
 	// Borrowed is quantity of quota that is borrowed from the cohort. In other
 	// words, it's the used quota that is over the nominalQuota.
 	Borrowed resource.Quantity `json:"borrowed,omitempty"`
+
+	// available is the quantity of quota that can still be reserved, from
+	// the unused nominalQuota and the quota that can be borrowed from the
+	// cohort, within the borrowingLimit. It's only reported in
+	// flavorsReservation.
+	// +optional
+	Available *resource.Quantity `json:"available,omitempty"`
 }
 
 type FairSharingStatus struct {
@@ -356,6 +619,14 @@ const (
 	// ClusterQueueActive indicates that the ClusterQueue can admit new workloads and its quota
 	// can be borrowed by other ClusterQueues in the same cohort.
 	ClusterQueueActive string = "Active"
+
+	// ClusterQueueResourceNearCapacity indicates that the usage of at least one
+	// resource reached the warnThreshold of its flavor.
+	ClusterQueueResourceNearCapacity string = "ResourceNearCapacity"
+
+	// ClusterQueueFlavorsCordoned indicates that some of the flavors of the
+	// ClusterQueue are cordoned, so no new workloads can be admitted in them.
+	ClusterQueueFlavorsCordoned string = "FlavorsCordoned"
 )
 
 type PreemptionPolicy string
@@ -375,6 +646,29 @@ const (
 	TryNextFlavor FlavorFungibilityPolicy = "TryNextFlavor"
 )
 
+type FlavorSelectionStrategy string
+
+const (
+	FirstFit       FlavorSelectionStrategy = "FirstFit"
+	BestFit        FlavorSelectionStrategy = "BestFit"
+	LeastContended FlavorSelectionStrategy = "LeastContended"
+)
+
+type WorkloadSizeOrdering string
+
+const (
+	WorkloadSizeOrderingNone         WorkloadSizeOrdering = "None"
+	WorkloadSizeOrderingSmallerFirst WorkloadSizeOrdering = "SmallerFirst"
+	WorkloadSizeOrderingLargerFirst  WorkloadSizeOrdering = "LargerFirst"
+)
+
+type ReadmissionFlavorPolicy string
+
+const (
+	ReadmissionFlavorPolicyNone           ReadmissionFlavorPolicy = "None"
+	ReadmissionFlavorPolicyPreferPrevious ReadmissionFlavorPolicy = "PreferPrevious"
+)
+
 // FlavorFungibility determines whether a workload should try the next flavor
 // before borrowing or preempting in current flavor.
 type FlavorFungibility struct {
@@ -440,6 +734,18 @@ type ClusterQueuePreemption struct {
 	// +kubebuilder:default=Never
 	// +kubebuilder:validation:Enum=Never;LowerPriority;LowerOrNewerEqualPriority
 	WithinClusterQueue PreemptionPolicy `json:"withinClusterQueue,omitempty"`
+
+	// reclaimCooldown is the minimum duration, after Workloads of this
+	// ClusterQueue were preempted to reclaim quota for other ClusterQueues
+	// in the cohort, during which its Workloads are not preempted again to
+	// reclaim quota. The preemptors look for other ClusterQueues to reclaim
+	// quota from in the meantime, avoiding to repeatedly preempt the same
+	// borrower.
+	// Preemptions within the ClusterQueue are not affected.
+	// If not set or zero, the quota can be reclaimed at any time.
+	//
+	// +optional
+	ReclaimCooldown *metav1.Duration `json:"reclaimCooldown,omitempty"`
 }
 
 type BorrowWithinCohortPolicy string
@@ -19,6 +19,48 @@ package v1beta1
 const (
 	ResourceInUseFinalizerName = "kueue.x-k8s.io/resource-in-use"
 	DefaultPodSetName          = "main"
+
+	// PodSetPlacementOrderAnnotation is the annotation key in the PodSet
+	// template that holds a non-negative integer hinting the order in which
+	// the scheduler assigns flavors to the PodSets of a Workload. PodSets
+	// with lower values are assigned first, followed by the PodSets without
+	// the annotation, in their original order.
+	// The hint is advisory and doesn't affect whether the Workload fits.
+	PodSetPlacementOrderAnnotation = "kueue.x-k8s.io/podset-placement-order"
+
+	// PodSetResourceClaimClassesAnnotation is the annotation key in the PodSet
+	// template that declares the device class of the pod resource claims, as
+	// a comma separated list of <claim name>=<device class> pairs.
+	// When the DynamicResourceAllocation feature is enabled, each declared
+	// claim counts as one unit per pod of a resource named after its device
+	// class, which can be limited with quotas in the ClusterQueues.
+	PodSetResourceClaimClassesAnnotation = "kueue.x-k8s.io/resource-claim-classes"
+
+	// PodSetOptionalAnnotation is the annotation key in the PodSet template
+	// that marks the PodSet as optional when set to "true".
+	// Optional PodSets are only admitted if there is spare capacity after
+	// assigning the required PodSets of the Workload; they never trigger
+	// preemptions. The optional PodSets that are not admitted are recorded
+	// in the Admission with a count of zero.
+	PodSetOptionalAnnotation = "kueue.x-k8s.io/podset-optional"
+
+	// PodSetPreferredNodeSelectorAnnotation is the annotation key in the
+	// PodSet template that holds a label selector, such as
+	// example.com/image-cache=warm, matched against the nodeLabels of the
+	// flavors. Among the flavors in which the PodSet fits without borrowing,
+	// the scheduler prefers the ones matching the selector, falling back to
+	// the others when none of them fits.
+	// The hint is advisory and doesn't affect whether the Workload fits.
+	PodSetPreferredNodeSelectorAnnotation = "kueue.x-k8s.io/preferred-node-selector"
+
+	// PodSetRequiredTopologyAnnotation is the annotation key in the PodSet
+	// template that holds a comma separated list of node label keys, such as
+	// nvidia.com/gpu.clique, identifying the topology domains, like NVLink
+	// domains, in which all the pods of the PodSet must run.
+	// The PodSet can only be assigned flavors whose nodeLabels set all the
+	// keys, so that the pods are pinned to a single domain. The Workload
+	// stays pending if no such flavor fits.
+	PodSetRequiredTopologyAnnotation = "kueue.x-k8s.io/podset-required-topology"
 )
 
 type StopPolicy string
@@ -23,6 +23,7 @@ import (
 )
 
 // LocalQueueSpec defines the desired state of LocalQueue
+// +kubebuilder:validation:XValidation:rule="!has(self.clusterQueueSelection) || !(self.clusterQueue in self.clusterQueueSelection.clusterQueues)", message="clusterQueueSelection.clusterQueues must not contain clusterQueue"
 type LocalQueueSpec struct {
 	// clusterQueue is a reference to a clusterQueue that backs this localQueue.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="field is immutable"
@@ -41,6 +42,63 @@ type LocalQueueSpec struct {
 	// +kubebuilder:validation:Enum=None;Hold;HoldAndDrain
 	// +kubebuilder:default="None"
 	StopPolicy *StopPolicy `json:"stopPolicy,omitempty"`
+
+	// maxConcurrency is the maximum number of workloads of the localQueue
+	// that can reserve quota in the clusterQueue at the same time, even if
+	// the clusterQueue has unused quota.
+	// If unset, the number of workloads is not limited.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrency *int32 `json:"maxConcurrency,omitempty"`
+
+	// clusterQueueSelection configures additional clusterQueues that can
+	// serve the workloads of the localQueue when clusterQueue doesn't have
+	// capacity for them.
+	// +optional
+	ClusterQueueSelection *ClusterQueueSelection `json:"clusterQueueSelection,omitempty"`
+}
+
+type ClusterQueueSelectionPolicy string
+
+const (
+	// ClusterQueueSelectionFirstWithCapacity selects the first clusterQueue,
+	// in order of preference, in which the workload fits in the quota that
+	// the clusterQueue can still reserve.
+	ClusterQueueSelectionFirstWithCapacity ClusterQueueSelectionPolicy = "FirstWithCapacity"
+
+	// ClusterQueueSelectionLeastLoaded selects, among the clusterQueues in
+	// which the workload fits, the one with the lowest fraction of its
+	// nominal quota in use.
+	ClusterQueueSelectionLeastLoaded ClusterQueueSelectionPolicy = "LeastLoaded"
+)
+
+// ClusterQueueSelection configures how the clusterQueue of a workload is
+// selected among the clusterQueues of a localQueue.
+type ClusterQueueSelection struct {
+	// clusterQueues are the candidate clusterQueues, in order of preference
+	// after the clusterQueue of the localQueue.
+	// +listType=set
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=8
+	ClusterQueues []ClusterQueueReference `json:"clusterQueues"`
+
+	// policy determines how the clusterQueue of a workload is selected when
+	// it's queued or requeued after failing to be admitted. The possible
+	// values are:
+	//
+	// - FirstWithCapacity: the first clusterQueue, starting with the
+	//   clusterQueue of the localQueue, in which the workload fits. If the
+	//   workload doesn't fit in any of them, it's queued in the clusterQueue
+	//   of the localQueue, or it stays in the clusterQueue where it was
+	//   queued when it's requeued.
+	// - LeastLoaded: among the clusterQueues in which the workload fits, the
+	//   one with the lowest fraction of its nominal quota in use. If the
+	//   workload doesn't fit in any of them, it's handled like with
+	//   FirstWithCapacity.
+	//
+	// +kubebuilder:validation:Enum=FirstWithCapacity;LeastLoaded
+	// +kubebuilder:default=FirstWithCapacity
+	Policy ClusterQueueSelectionPolicy `json:"policy,omitempty"`
 }
 
 // ClusterQueueReference is the name of the ClusterQueue.
@@ -52,6 +52,19 @@ type ResourceFlavorSpec struct {
 	// +kubebuilder:validation:MaxProperties=8
 	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
 
+	// nodeSelector associates the ResourceFlavor with the Nodes that match
+	// the matchExpressions of the term, such as Nodes whose instance type is
+	// in a set of values, in addition to the nodeLabels.
+	// When a podSet selects a value for a label key of the term in its
+	// nodeSelector, the ResourceFlavor can only be assigned to the podSet if
+	// the value satisfies the term.
+	// Once a ResourceFlavor is assigned to a podSet, the matchExpressions
+	// should be added to the required node affinity of the pods of the
+	// Workload by the controller that integrates with the Workload object.
+	//
+	// +optional
+	NodeSelector *corev1.NodeSelectorTerm `json:"nodeSelector,omitempty"`
+
 	// nodeTaints are taints that the nodes associated with this ResourceFlavor
 	// have.
 	// Workloads' podsets must have tolerations for these nodeTaints in order to
@@ -85,6 +98,29 @@ type ResourceFlavorSpec struct {
 	// +kubebuilder:validation:XValidation:rule="self.all(x, has(x.operator) && x.operator == 'Exists' ? !has(x.value) : true)", message="a value must be empty when 'operator' is 'Exists'"
 	// +kubebuilder:validation:XValidation:rule="self.all(x, !has(x.effect) || x.effect in ['NoSchedule', 'PreferNoSchedule', 'NoExecute'])", message="supported taint effect values: 'NoSchedule', 'PreferNoSchedule', 'NoExecute'"
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// evictOnNodeUnavailable indicates that the Nodes associated with this
+	// ResourceFlavor can be reclaimed at any time, such as spot or preemptible
+	// instances.
+	// When true, the Workloads admitted in this ResourceFlavor that have pods
+	// running on a Node matching the nodeLabels are evicted once the Node
+	// becomes NotReady or unschedulable, so that they can be admitted again,
+	// possibly in other ResourceFlavors.
+	//
+	// This field requires the NodeUnavailableEviction feature gate.
+	//
+	// +optional
+	EvictOnNodeUnavailable bool `json:"evictOnNodeUnavailable,omitempty"`
+
+	// cordoned indicates that no new Workloads can be admitted in this
+	// ResourceFlavor, for example, during the maintenance of its Nodes.
+	// The Workloads already admitted in the ResourceFlavor are not affected,
+	// and their quota is released as they finish.
+	// The ClusterQueues using the ResourceFlavor get the FlavorsCordoned
+	// condition.
+	//
+	// +optional
+	Cordoned bool `json:"cordoned,omitempty"`
 }
 
 // +kubebuilder:object:root=true
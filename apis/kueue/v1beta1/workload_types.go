@@ -75,6 +75,17 @@ type WorkloadSpec struct {
 	// Defaults to true
 	// +kubebuilder:default=true
 	Active *bool `json:"active,omitempty"`
+
+	// activeDeadlineSeconds is the duration in seconds, relative to the
+	// creation of the Workload, that it can remain pending. If the Workload
+	// doesn't have a quota reservation after the deadline, it's marked as
+	// Finished with the DeadlineExceeded reason, and the job that owns it is
+	// kept suspended and, if supported, failed.
+	// Defaults to nil, meaning that there is no deadline.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
 }
 
 type Admission struct {
@@ -204,6 +215,68 @@ type WorkloadStatus struct {
 	// +patchMergeKey=name
 	// +kubebuilder:validation:MaxItems=8
 	AdmissionChecks []AdmissionCheckState `json:"admissionChecks,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// candidateFlavors lists, for each podSet of a pending workload, the
+	// ResourceFlavors of the ClusterQueue that are not cordoned and that
+	// satisfy the podSet's node affinity, tolerations and requested
+	// resources, regardless of the available quota.
+	// An empty list of flavors for a podSet means that no flavor in the
+	// ClusterQueue can run it, while a non-empty list means that the
+	// workload is waiting for quota.
+	// The field is set by the scheduler and cleared when the workload
+	// gets a quota reservation.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	CandidateFlavors []PodSetCandidateFlavors `json:"candidateFlavors,omitempty"`
+
+	// previousFlavors are the flavors that were assigned to the resources of
+	// each podSet the last time that the workload lost its quota reservation,
+	// for example, when it was evicted.
+	// When the ClusterQueue has the readmissionFlavorPolicy PreferPrevious,
+	// the scheduler prefers the same flavors when the workload is admitted
+	// again.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	PreviousFlavors []PodSetFlavors `json:"previousFlavors,omitempty"`
+
+	// admissionAttempts is the number of times that the workload got a
+	// quota reservation, including the current one. It's never decreased,
+	// so the number of times that the workload lost its quota reservation,
+	// for example, because it was evicted, can be derived from it.
+	//
+	// +optional
+	AdmissionAttempts int32 `json:"admissionAttempts,omitempty"`
+}
+
+type PodSetCandidateFlavors struct {
+	// name is the name of the podSet. It should match one of the names in .spec.podSets.
+	// +kubebuilder:default=main
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern="^(?i)[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	Name string `json:"name"`
+
+	// flavors are the ResourceFlavors that could be assigned to the podSet,
+	// in the order in which they are listed in the ClusterQueue.
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=256
+	Flavors []ResourceFlavorReference `json:"flavors,omitempty"`
+}
+
+type PodSetFlavors struct {
+	// name is the name of the podSet. It should match one of the names in .spec.podSets.
+	// +kubebuilder:default=main
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern="^(?i)[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	Name string `json:"name"`
+
+	// flavors are the flavors assigned to each resource of the podSet.
+	Flavors map[corev1.ResourceName]ResourceFlavorReference `json:"flavors,omitempty"`
 }
 
 type RequeueState struct {
@@ -332,6 +405,12 @@ const (
 	// WorkloadDeactivationTarget means that the Workload should be deactivated.
 	// This condition is temporary, so it should be removed after deactivation.
 	WorkloadDeactivationTarget = "DeactivationTarget"
+
+	// WorkloadAdmittedButUnschedulable means that pods of the admitted
+	// Workload were still unschedulable after the configured threshold since
+	// the job was started. This usually indicates that the quota of the
+	// ClusterQueue doesn't match the actual capacity of the cluster.
+	WorkloadAdmittedButUnschedulable = "AdmittedButUnschedulable"
 )
 
 const (
@@ -339,6 +418,16 @@ const (
 	// due to LocalQueue or ClusterQueue doesn't exist or inactive.
 	WorkloadInadmissible = "Inadmissible"
 
+	// WorkloadRequestExceedsCapacity means that the Workload can't reserve
+	// quota because a pod requests more of a resource than any flavor of the
+	// ClusterQueue can provide.
+	WorkloadRequestExceedsCapacity = "RequestExceedsCapacity"
+
+	// WorkloadExceedsLimitRange means that the Workload can't reserve quota
+	// because the resources of its pods exceed the maximum of the LimitRanges
+	// in its namespace, so the pods would be rejected by the API server.
+	WorkloadExceedsLimitRange = "ExceedsLimitRange"
+
 	// WorkloadEvictedByPreemption indicates that the workload was evicted
 	// in order to free resources for a workload with a higher priority.
 	WorkloadEvictedByPreemption = "Preempted"
@@ -351,6 +440,11 @@ const (
 	// because at least one admission check transitioned to False.
 	WorkloadEvictedByAdmissionCheck = "AdmissionCheck"
 
+	// WorkloadEvictedByAdmissionChecksTimeout indicates that the workload was
+	// evicted because its admission checks didn't become Ready within the
+	// configured timeout.
+	WorkloadEvictedByAdmissionChecksTimeout = "AdmissionChecksTimeout"
+
 	// WorkloadEvictedByClusterQueueStopped indicates that the workload was evicted
 	// because the ClusterQueue is Stopped.
 	WorkloadEvictedByClusterQueueStopped = "ClusterQueueStopped"
@@ -363,6 +457,11 @@ const (
 	// because spec.active is set to false.
 	WorkloadEvictedByDeactivation = "InactiveWorkload"
 
+	// WorkloadEvictedByNodeUnavailable indicates that the workload was evicted
+	// because a Node of a ResourceFlavor with evictOnNodeUnavailable, where
+	// its pods were running, became unavailable.
+	WorkloadEvictedByNodeUnavailable = "NodeUnavailable"
+
 	// WorkloadReactivated indicates that the workload was requeued because
 	// spec.active is set to true after deactivation.
 	WorkloadReactivated = "Reactivated"
@@ -394,6 +493,10 @@ const (
 	// WorkloadFinishedReasonAdmissionChecksRejected indicates that the workload was rejected by admission checks.
 	WorkloadFinishedReasonAdmissionChecksRejected = "AdmissionChecksRejected"
 
+	// WorkloadFinishedReasonDeadlineExceeded indicates that the workload
+	// remained pending past its activeDeadlineSeconds.
+	WorkloadFinishedReasonDeadlineExceeded = "DeadlineExceeded"
+
 	// WorkloadFinishedReasonOutOfSync indicates that the prebuilt workload is not in sync with its parent job.
 	WorkloadFinishedReasonOutOfSync = "OutOfSync"
 )
@@ -357,6 +357,11 @@ func (in *ClusterQueuePreemption) DeepCopyInto(out *ClusterQueuePreemption) {
 		*out = new(BorrowWithinCohort)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReclaimCooldown != nil {
+		in, out := &in.ReclaimCooldown, &out.ReclaimCooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQueuePreemption.
@@ -369,6 +374,26 @@ func (in *ClusterQueuePreemption) DeepCopy() *ClusterQueuePreemption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQueueSelection) DeepCopyInto(out *ClusterQueueSelection) {
+	*out = *in
+	if in.ClusterQueues != nil {
+		in, out := &in.ClusterQueues, &out.ClusterQueues
+		*out = make([]ClusterQueueReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQueueSelection.
+func (in *ClusterQueueSelection) DeepCopy() *ClusterQueueSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQueueSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterQueueSpec) DeepCopyInto(out *ClusterQueueSpec) {
 	*out = *in
@@ -389,6 +414,37 @@ func (in *ClusterQueueSpec) DeepCopyInto(out *ClusterQueueSpec) {
 		*out = new(FlavorFungibility)
 		**out = **in
 	}
+	if in.ResourceAliases != nil {
+		in, out := &in.ResourceAliases, &out.ResourceAliases
+		*out = make([]ResourceAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceSlices != nil {
+		in, out := &in.ResourceSlices, &out.ResourceSlices
+		*out = make([]ResourceSlice, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceTransforms != nil {
+		in, out := &in.ResourceTransforms, &out.ResourceTransforms
+		*out = make([]ResourceTransform, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IntegerResources != nil {
+		in, out := &in.IntegerResources, &out.IntegerResources
+		*out = make([]corev1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	if in.QuotaWindows != nil {
+		in, out := &in.QuotaWindows, &out.QuotaWindows
+		*out = make([]QuotaWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Preemption != nil {
 		in, out := &in.Preemption, &out.Preemption
 		*out = new(ClusterQueuePreemption)
@@ -460,6 +516,11 @@ func (in *ClusterQueueStatus) DeepCopyInto(out *ClusterQueueStatus) {
 		*out = new(FairSharingStatus)
 		**out = **in
 	}
+	if in.HeadAdmissionEstimate != nil {
+		in, out := &in.HeadAdmissionEstimate, &out.HeadAdmissionEstimate
+		*out = new(HeadAdmissionEstimate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQueueStatus.
@@ -532,6 +593,11 @@ func (in *FlavorQuotas) DeepCopyInto(out *FlavorQuotas) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.WarnThreshold != nil {
+		in, out := &in.WarnThreshold, &out.WarnThreshold
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlavorQuotas.
@@ -566,6 +632,26 @@ func (in *FlavorUsage) DeepCopy() *FlavorUsage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeadAdmissionEstimate) DeepCopyInto(out *HeadAdmissionEstimate) {
+	*out = *in
+	out.Workload = in.Workload
+	if in.EstimatedAdmissionTime != nil {
+		in, out := &in.EstimatedAdmissionTime, &out.EstimatedAdmissionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadAdmissionEstimate.
+func (in *HeadAdmissionEstimate) DeepCopy() *HeadAdmissionEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(HeadAdmissionEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalQueue) DeepCopyInto(out *LocalQueue) {
 	*out = *in
@@ -671,6 +757,16 @@ func (in *LocalQueueSpec) DeepCopyInto(out *LocalQueueSpec) {
 		*out = new(StopPolicy)
 		**out = **in
 	}
+	if in.MaxConcurrency != nil {
+		in, out := &in.MaxConcurrency, &out.MaxConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ClusterQueueSelection != nil {
+		in, out := &in.ClusterQueueSelection, &out.ClusterQueueSelection
+		*out = new(ClusterQueueSelection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueSpec.
@@ -774,6 +870,48 @@ func (in *PodSetAssignment) DeepCopy() *PodSetAssignment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSetCandidateFlavors) DeepCopyInto(out *PodSetCandidateFlavors) {
+	*out = *in
+	if in.Flavors != nil {
+		in, out := &in.Flavors, &out.Flavors
+		*out = make([]ResourceFlavorReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetCandidateFlavors.
+func (in *PodSetCandidateFlavors) DeepCopy() *PodSetCandidateFlavors {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSetCandidateFlavors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSetFlavors) DeepCopyInto(out *PodSetFlavors) {
+	*out = *in
+	if in.Flavors != nil {
+		in, out := &in.Flavors, &out.Flavors
+		*out = make(map[corev1.ResourceName]ResourceFlavorReference, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetFlavors.
+func (in *PodSetFlavors) DeepCopy() *PodSetFlavors {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSetFlavors)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodSetUpdate) DeepCopyInto(out *PodSetUpdate) {
 	*out = *in
@@ -902,6 +1040,29 @@ func (in *ProvisioningRequestConfigSpec) DeepCopy() *ProvisioningRequestConfigSp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaWindow) DeepCopyInto(out *QuotaWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Flavors != nil {
+		in, out := &in.Flavors, &out.Flavors
+		*out = make([]FlavorQuotas, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaWindow.
+func (in *QuotaWindow) DeepCopy() *QuotaWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReclaimablePod) DeepCopyInto(out *ReclaimablePod) {
 	*out = *in
@@ -941,6 +1102,26 @@ func (in *RequeueState) DeepCopy() *RequeueState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceAlias) DeepCopyInto(out *ResourceAlias) {
+	*out = *in
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]corev1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceAlias.
+func (in *ResourceAlias) DeepCopy() *ResourceAlias {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceAlias)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceFlavor) DeepCopyInto(out *ResourceFlavor) {
 	*out = *in
@@ -1009,6 +1190,11 @@ func (in *ResourceFlavorSpec) DeepCopyInto(out *ResourceFlavorSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(corev1.NodeSelectorTerm)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NodeTaints != nil {
 		in, out := &in.NodeTaints, &out.NodeTaints
 		*out = make([]corev1.Taint, len(*in))
@@ -1076,6 +1262,11 @@ func (in *ResourceQuota) DeepCopyInto(out *ResourceQuota) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.NonLendableQuota != nil {
+		in, out := &in.NonLendableQuota, &out.NonLendableQuota
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuota.
@@ -1088,11 +1279,61 @@ func (in *ResourceQuota) DeepCopy() *ResourceQuota {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSlice) DeepCopyInto(out *ResourceSlice) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSlice.
+func (in *ResourceSlice) DeepCopy() *ResourceSlice {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSlice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTransform) DeepCopyInto(out *ResourceTransform) {
+	*out = *in
+	if in.Multiplier != nil {
+		in, out := &in.Multiplier, &out.Multiplier
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Minimum != nil {
+		in, out := &in.Minimum, &out.Minimum
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PodDefault != nil {
+		in, out := &in.PodDefault, &out.PodDefault
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTransform.
+func (in *ResourceTransform) DeepCopy() *ResourceTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceUsage) DeepCopyInto(out *ResourceUsage) {
 	*out = *in
 	out.Total = in.Total.DeepCopy()
 	out.Borrowed = in.Borrowed.DeepCopy()
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsage.
@@ -1241,6 +1482,11 @@ func (in *WorkloadSpec) DeepCopyInto(out *WorkloadSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpec.
@@ -1285,6 +1531,20 @@ func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CandidateFlavors != nil {
+		in, out := &in.CandidateFlavors, &out.CandidateFlavors
+		*out = make([]PodSetCandidateFlavors, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreviousFlavors != nil {
+		in, out := &in.PreviousFlavors, &out.PreviousFlavors
+		*out = make([]PodSetFlavors, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
@@ -0,0 +1,74 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CohortSpecApplyConfiguration represents an declarative configuration of the CohortSpec type for use
+// with apply.
+type CohortSpecApplyConfiguration struct {
+	Parent                       *string                                 `json:"parent,omitempty"`
+	BorrowingCaps                []FlavorBorrowingCapsApplyConfiguration `json:"borrowingCaps,omitempty"`
+	ReclaimDelay                 *v1.Duration                            `json:"reclaimDelay,omitempty"`
+	BorrowingSafetyMarginPercent *int32                                  `json:"borrowingSafetyMarginPercent,omitempty"`
+}
+
+// CohortSpecApplyConfiguration constructs an declarative configuration of the CohortSpec type for use with
+// apply.
+func CohortSpec() *CohortSpecApplyConfiguration {
+	return &CohortSpecApplyConfiguration{}
+}
+
+// WithParent sets the Parent field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Parent field is set to the value of the last call.
+func (b *CohortSpecApplyConfiguration) WithParent(value string) *CohortSpecApplyConfiguration {
+	b.Parent = &value
+	return b
+}
+
+// WithBorrowingCaps adds the given value to the BorrowingCaps field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the BorrowingCaps field.
+func (b *CohortSpecApplyConfiguration) WithBorrowingCaps(values ...*FlavorBorrowingCapsApplyConfiguration) *CohortSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithBorrowingCaps")
+		}
+		b.BorrowingCaps = append(b.BorrowingCaps, *values[i])
+	}
+	return b
+}
+
+// WithReclaimDelay sets the ReclaimDelay field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReclaimDelay field is set to the value of the last call.
+func (b *CohortSpecApplyConfiguration) WithReclaimDelay(value v1.Duration) *CohortSpecApplyConfiguration {
+	b.ReclaimDelay = &value
+	return b
+}
+
+// WithBorrowingSafetyMarginPercent sets the BorrowingSafetyMarginPercent field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BorrowingSafetyMarginPercent field is set to the value of the last call.
+func (b *CohortSpecApplyConfiguration) WithBorrowingSafetyMarginPercent(value int32) *CohortSpecApplyConfiguration {
+	b.BorrowingSafetyMarginPercent = &value
+	return b
+}
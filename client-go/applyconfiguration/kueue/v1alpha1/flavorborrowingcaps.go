@@ -0,0 +1,56 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// FlavorBorrowingCapsApplyConfiguration represents an declarative configuration of the FlavorBorrowingCaps type for use
+// with apply.
+type FlavorBorrowingCapsApplyConfiguration struct {
+	Name      *v1beta1.ResourceFlavorReference         `json:"name,omitempty"`
+	Resources []ResourceBorrowingCapApplyConfiguration `json:"resources,omitempty"`
+}
+
+// FlavorBorrowingCapsApplyConfiguration constructs an declarative configuration of the FlavorBorrowingCaps type for use with
+// apply.
+func FlavorBorrowingCaps() *FlavorBorrowingCapsApplyConfiguration {
+	return &FlavorBorrowingCapsApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *FlavorBorrowingCapsApplyConfiguration) WithName(value v1beta1.ResourceFlavorReference) *FlavorBorrowingCapsApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithResources adds the given value to the Resources field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Resources field.
+func (b *FlavorBorrowingCapsApplyConfiguration) WithResources(values ...*ResourceBorrowingCapApplyConfiguration) *FlavorBorrowingCapsApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithResources")
+		}
+		b.Resources = append(b.Resources, *values[i])
+	}
+	return b
+}
@@ -0,0 +1,52 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceBorrowingCapApplyConfiguration represents an declarative configuration of the ResourceBorrowingCap type for use
+// with apply.
+type ResourceBorrowingCapApplyConfiguration struct {
+	Name *v1.ResourceName   `json:"name,omitempty"`
+	Cap  *resource.Quantity `json:"cap,omitempty"`
+}
+
+// ResourceBorrowingCapApplyConfiguration constructs an declarative configuration of the ResourceBorrowingCap type for use with
+// apply.
+func ResourceBorrowingCap() *ResourceBorrowingCapApplyConfiguration {
+	return &ResourceBorrowingCapApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *ResourceBorrowingCapApplyConfiguration) WithName(value v1.ResourceName) *ResourceBorrowingCapApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithCap sets the Cap field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Cap field is set to the value of the last call.
+func (b *ResourceBorrowingCapApplyConfiguration) WithCap(value resource.Quantity) *ResourceBorrowingCapApplyConfiguration {
+	b.Cap = &value
+	return b
+}
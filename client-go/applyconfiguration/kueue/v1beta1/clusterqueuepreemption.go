@@ -18,6 +18,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 )
 
@@ -27,6 +28,7 @@ type ClusterQueuePreemptionApplyConfiguration struct {
 	ReclaimWithinCohort *v1beta1.PreemptionPolicy             `json:"reclaimWithinCohort,omitempty"`
 	BorrowWithinCohort  *BorrowWithinCohortApplyConfiguration `json:"borrowWithinCohort,omitempty"`
 	WithinClusterQueue  *v1beta1.PreemptionPolicy             `json:"withinClusterQueue,omitempty"`
+	ReclaimCooldown     *v1.Duration                          `json:"reclaimCooldown,omitempty"`
 }
 
 // ClusterQueuePreemptionApplyConfiguration constructs an declarative configuration of the ClusterQueuePreemption type for use with
@@ -58,3 +60,11 @@ func (b *ClusterQueuePreemptionApplyConfiguration) WithWithinClusterQueue(value
 	b.WithinClusterQueue = &value
 	return b
 }
+
+// WithReclaimCooldown sets the ReclaimCooldown field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReclaimCooldown field is set to the value of the last call.
+func (b *ClusterQueuePreemptionApplyConfiguration) WithReclaimCooldown(value v1.Duration) *ClusterQueuePreemptionApplyConfiguration {
+	b.ReclaimCooldown = &value
+	return b
+}
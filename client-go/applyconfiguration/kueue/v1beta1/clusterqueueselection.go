@@ -0,0 +1,53 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// ClusterQueueSelectionApplyConfiguration represents an declarative configuration of the ClusterQueueSelection type for use
+// with apply.
+type ClusterQueueSelectionApplyConfiguration struct {
+	ClusterQueues []v1beta1.ClusterQueueReference      `json:"clusterQueues,omitempty"`
+	Policy        *v1beta1.ClusterQueueSelectionPolicy `json:"policy,omitempty"`
+}
+
+// ClusterQueueSelectionApplyConfiguration constructs an declarative configuration of the ClusterQueueSelection type for use with
+// apply.
+func ClusterQueueSelection() *ClusterQueueSelectionApplyConfiguration {
+	return &ClusterQueueSelectionApplyConfiguration{}
+}
+
+// WithClusterQueues adds the given value to the ClusterQueues field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ClusterQueues field.
+func (b *ClusterQueueSelectionApplyConfiguration) WithClusterQueues(values ...v1beta1.ClusterQueueReference) *ClusterQueueSelectionApplyConfiguration {
+	for i := range values {
+		b.ClusterQueues = append(b.ClusterQueues, values[i])
+	}
+	return b
+}
+
+// WithPolicy sets the Policy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Policy field is set to the value of the last call.
+func (b *ClusterQueueSelectionApplyConfiguration) WithPolicy(value v1beta1.ClusterQueueSelectionPolicy) *ClusterQueueSelectionApplyConfiguration {
+	b.Policy = &value
+	return b
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 )
@@ -30,6 +31,14 @@ type ClusterQueueSpecApplyConfiguration struct {
 	QueueingStrategy        *kueuev1beta1.QueueingStrategy             `json:"queueingStrategy,omitempty"`
 	NamespaceSelector       *v1.LabelSelector                          `json:"namespaceSelector,omitempty"`
 	FlavorFungibility       *FlavorFungibilityApplyConfiguration       `json:"flavorFungibility,omitempty"`
+	FlavorSelectionStrategy *kueuev1beta1.FlavorSelectionStrategy      `json:"flavorSelectionStrategy,omitempty"`
+	ReadmissionFlavorPolicy *kueuev1beta1.ReadmissionFlavorPolicy      `json:"readmissionFlavorPolicy,omitempty"`
+	WorkloadSizeOrdering    *kueuev1beta1.WorkloadSizeOrdering         `json:"workloadSizeOrdering,omitempty"`
+	ResourceAliases         []ResourceAliasApplyConfiguration          `json:"resourceAliases,omitempty"`
+	ResourceSlices          []ResourceSliceApplyConfiguration          `json:"resourceSlices,omitempty"`
+	ResourceTransforms      []ResourceTransformApplyConfiguration      `json:"resourceTransforms,omitempty"`
+	IntegerResources        []corev1.ResourceName                      `json:"integerResources,omitempty"`
+	QuotaWindows            []QuotaWindowApplyConfiguration            `json:"quotaWindows,omitempty"`
 	Preemption              *ClusterQueuePreemptionApplyConfiguration  `json:"preemption,omitempty"`
 	AdmissionChecks         []string                                   `json:"admissionChecks,omitempty"`
 	AdmissionChecksStrategy *AdmissionChecksStrategyApplyConfiguration `json:"admissionChecksStrategy,omitempty"`
@@ -88,6 +97,92 @@ func (b *ClusterQueueSpecApplyConfiguration) WithFlavorFungibility(value *Flavor
 	return b
 }
 
+// WithFlavorSelectionStrategy sets the FlavorSelectionStrategy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FlavorSelectionStrategy field is set to the value of the last call.
+func (b *ClusterQueueSpecApplyConfiguration) WithFlavorSelectionStrategy(value kueuev1beta1.FlavorSelectionStrategy) *ClusterQueueSpecApplyConfiguration {
+	b.FlavorSelectionStrategy = &value
+	return b
+}
+
+// WithReadmissionFlavorPolicy sets the ReadmissionFlavorPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReadmissionFlavorPolicy field is set to the value of the last call.
+func (b *ClusterQueueSpecApplyConfiguration) WithReadmissionFlavorPolicy(value kueuev1beta1.ReadmissionFlavorPolicy) *ClusterQueueSpecApplyConfiguration {
+	b.ReadmissionFlavorPolicy = &value
+	return b
+}
+
+// WithWorkloadSizeOrdering sets the WorkloadSizeOrdering field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the WorkloadSizeOrdering field is set to the value of the last call.
+func (b *ClusterQueueSpecApplyConfiguration) WithWorkloadSizeOrdering(value kueuev1beta1.WorkloadSizeOrdering) *ClusterQueueSpecApplyConfiguration {
+	b.WorkloadSizeOrdering = &value
+	return b
+}
+
+// WithResourceAliases adds the given value to the ResourceAliases field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ResourceAliases field.
+func (b *ClusterQueueSpecApplyConfiguration) WithResourceAliases(values ...*ResourceAliasApplyConfiguration) *ClusterQueueSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithResourceAliases")
+		}
+		b.ResourceAliases = append(b.ResourceAliases, *values[i])
+	}
+	return b
+}
+
+// WithResourceSlices adds the given value to the ResourceSlices field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ResourceSlices field.
+func (b *ClusterQueueSpecApplyConfiguration) WithResourceSlices(values ...*ResourceSliceApplyConfiguration) *ClusterQueueSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithResourceSlices")
+		}
+		b.ResourceSlices = append(b.ResourceSlices, *values[i])
+	}
+	return b
+}
+
+// WithResourceTransforms adds the given value to the ResourceTransforms field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ResourceTransforms field.
+func (b *ClusterQueueSpecApplyConfiguration) WithResourceTransforms(values ...*ResourceTransformApplyConfiguration) *ClusterQueueSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithResourceTransforms")
+		}
+		b.ResourceTransforms = append(b.ResourceTransforms, *values[i])
+	}
+	return b
+}
+
+// WithIntegerResources adds the given value to the IntegerResources field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the IntegerResources field.
+func (b *ClusterQueueSpecApplyConfiguration) WithIntegerResources(values ...corev1.ResourceName) *ClusterQueueSpecApplyConfiguration {
+	for i := range values {
+		b.IntegerResources = append(b.IntegerResources, values[i])
+	}
+	return b
+}
+
+// WithQuotaWindows adds the given value to the QuotaWindows field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the QuotaWindows field.
+func (b *ClusterQueueSpecApplyConfiguration) WithQuotaWindows(values ...*QuotaWindowApplyConfiguration) *ClusterQueueSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithQuotaWindows")
+		}
+		b.QuotaWindows = append(b.QuotaWindows, *values[i])
+	}
+	return b
+}
+
 // WithPreemption sets the Preemption field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Preemption field is set to the value of the last call.
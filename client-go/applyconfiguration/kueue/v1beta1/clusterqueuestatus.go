@@ -32,6 +32,7 @@ type ClusterQueueStatusApplyConfiguration struct {
 	Conditions             []v1.Condition                                        `json:"conditions,omitempty"`
 	PendingWorkloadsStatus *ClusterQueuePendingWorkloadsStatusApplyConfiguration `json:"pendingWorkloadsStatus,omitempty"`
 	FairSharing            *FairSharingStatusApplyConfiguration                  `json:"fairSharing,omitempty"`
+	HeadAdmissionEstimate  *HeadAdmissionEstimateApplyConfiguration              `json:"headAdmissionEstimate,omitempty"`
 }
 
 // ClusterQueueStatusApplyConfiguration constructs an declarative configuration of the ClusterQueueStatus type for use with
@@ -115,3 +116,11 @@ func (b *ClusterQueueStatusApplyConfiguration) WithFairSharing(value *FairSharin
 	b.FairSharing = value
 	return b
 }
+
+// WithHeadAdmissionEstimate sets the HeadAdmissionEstimate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the HeadAdmissionEstimate field is set to the value of the last call.
+func (b *ClusterQueueStatusApplyConfiguration) WithHeadAdmissionEstimate(value *HeadAdmissionEstimateApplyConfiguration) *ClusterQueueStatusApplyConfiguration {
+	b.HeadAdmissionEstimate = value
+	return b
+}
@@ -24,8 +24,9 @@ import (
 // FlavorQuotasApplyConfiguration represents an declarative configuration of the FlavorQuotas type for use
 // with apply.
 type FlavorQuotasApplyConfiguration struct {
-	Name      *v1beta1.ResourceFlavorReference  `json:"name,omitempty"`
-	Resources []ResourceQuotaApplyConfiguration `json:"resources,omitempty"`
+	Name          *v1beta1.ResourceFlavorReference  `json:"name,omitempty"`
+	Resources     []ResourceQuotaApplyConfiguration `json:"resources,omitempty"`
+	WarnThreshold *int32                            `json:"warnThreshold,omitempty"`
 }
 
 // FlavorQuotasApplyConfiguration constructs an declarative configuration of the FlavorQuotas type for use with
@@ -54,3 +55,11 @@ func (b *FlavorQuotasApplyConfiguration) WithResources(values ...*ResourceQuotaA
 	}
 	return b
 }
+
+// WithWarnThreshold sets the WarnThreshold field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the WarnThreshold field is set to the value of the last call.
+func (b *FlavorQuotasApplyConfiguration) WithWarnThreshold(value int32) *FlavorQuotasApplyConfiguration {
+	b.WarnThreshold = &value
+	return b
+}
@@ -0,0 +1,61 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// HeadAdmissionEstimateApplyConfiguration represents an declarative configuration of the HeadAdmissionEstimate type for use
+// with apply.
+type HeadAdmissionEstimateApplyConfiguration struct {
+	Workload               *ClusterQueuePendingWorkloadApplyConfiguration `json:"workload,omitempty"`
+	State                  *kueuev1beta1.AdmissionEstimateState           `json:"state,omitempty"`
+	EstimatedAdmissionTime *v1.Time                                       `json:"estimatedAdmissionTime,omitempty"`
+}
+
+// HeadAdmissionEstimateApplyConfiguration constructs an declarative configuration of the HeadAdmissionEstimate type for use with
+// apply.
+func HeadAdmissionEstimate() *HeadAdmissionEstimateApplyConfiguration {
+	return &HeadAdmissionEstimateApplyConfiguration{}
+}
+
+// WithWorkload sets the Workload field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Workload field is set to the value of the last call.
+func (b *HeadAdmissionEstimateApplyConfiguration) WithWorkload(value *ClusterQueuePendingWorkloadApplyConfiguration) *HeadAdmissionEstimateApplyConfiguration {
+	b.Workload = value
+	return b
+}
+
+// WithState sets the State field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the State field is set to the value of the last call.
+func (b *HeadAdmissionEstimateApplyConfiguration) WithState(value kueuev1beta1.AdmissionEstimateState) *HeadAdmissionEstimateApplyConfiguration {
+	b.State = &value
+	return b
+}
+
+// WithEstimatedAdmissionTime sets the EstimatedAdmissionTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the EstimatedAdmissionTime field is set to the value of the last call.
+func (b *HeadAdmissionEstimateApplyConfiguration) WithEstimatedAdmissionTime(value v1.Time) *HeadAdmissionEstimateApplyConfiguration {
+	b.EstimatedAdmissionTime = &value
+	return b
+}
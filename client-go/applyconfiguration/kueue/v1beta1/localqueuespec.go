@@ -24,8 +24,10 @@ import (
 // LocalQueueSpecApplyConfiguration represents an declarative configuration of the LocalQueueSpec type for use
 // with apply.
 type LocalQueueSpecApplyConfiguration struct {
-	ClusterQueue *v1beta1.ClusterQueueReference `json:"clusterQueue,omitempty"`
-	StopPolicy   *v1beta1.StopPolicy            `json:"stopPolicy,omitempty"`
+	ClusterQueue          *v1beta1.ClusterQueueReference           `json:"clusterQueue,omitempty"`
+	StopPolicy            *v1beta1.StopPolicy                      `json:"stopPolicy,omitempty"`
+	MaxConcurrency        *int32                                   `json:"maxConcurrency,omitempty"`
+	ClusterQueueSelection *ClusterQueueSelectionApplyConfiguration `json:"clusterQueueSelection,omitempty"`
 }
 
 // LocalQueueSpecApplyConfiguration constructs an declarative configuration of the LocalQueueSpec type for use with
@@ -49,3 +51,19 @@ func (b *LocalQueueSpecApplyConfiguration) WithStopPolicy(value v1beta1.StopPoli
 	b.StopPolicy = &value
 	return b
 }
+
+// WithMaxConcurrency sets the MaxConcurrency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxConcurrency field is set to the value of the last call.
+func (b *LocalQueueSpecApplyConfiguration) WithMaxConcurrency(value int32) *LocalQueueSpecApplyConfiguration {
+	b.MaxConcurrency = &value
+	return b
+}
+
+// WithClusterQueueSelection sets the ClusterQueueSelection field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClusterQueueSelection field is set to the value of the last call.
+func (b *LocalQueueSpecApplyConfiguration) WithClusterQueueSelection(value *ClusterQueueSelectionApplyConfiguration) *LocalQueueSpecApplyConfiguration {
+	b.ClusterQueueSelection = value
+	return b
+}
@@ -0,0 +1,53 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// PodSetCandidateFlavorsApplyConfiguration represents an declarative configuration of the PodSetCandidateFlavors type for use
+// with apply.
+type PodSetCandidateFlavorsApplyConfiguration struct {
+	Name    *string                           `json:"name,omitempty"`
+	Flavors []v1beta1.ResourceFlavorReference `json:"flavors,omitempty"`
+}
+
+// PodSetCandidateFlavorsApplyConfiguration constructs an declarative configuration of the PodSetCandidateFlavors type for use with
+// apply.
+func PodSetCandidateFlavors() *PodSetCandidateFlavorsApplyConfiguration {
+	return &PodSetCandidateFlavorsApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *PodSetCandidateFlavorsApplyConfiguration) WithName(value string) *PodSetCandidateFlavorsApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithFlavors adds the given value to the Flavors field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Flavors field.
+func (b *PodSetCandidateFlavorsApplyConfiguration) WithFlavors(values ...v1beta1.ResourceFlavorReference) *PodSetCandidateFlavorsApplyConfiguration {
+	for i := range values {
+		b.Flavors = append(b.Flavors, values[i])
+	}
+	return b
+}
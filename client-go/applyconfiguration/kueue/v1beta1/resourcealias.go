@@ -0,0 +1,53 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// ResourceAliasApplyConfiguration represents an declarative configuration of the ResourceAlias type for use
+// with apply.
+type ResourceAliasApplyConfiguration struct {
+	Name    *v1.ResourceName  `json:"name,omitempty"`
+	Aliases []v1.ResourceName `json:"aliases,omitempty"`
+}
+
+// ResourceAliasApplyConfiguration constructs an declarative configuration of the ResourceAlias type for use with
+// apply.
+func ResourceAlias() *ResourceAliasApplyConfiguration {
+	return &ResourceAliasApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *ResourceAliasApplyConfiguration) WithName(value v1.ResourceName) *ResourceAliasApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithAliases adds the given value to the Aliases field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Aliases field.
+func (b *ResourceAliasApplyConfiguration) WithAliases(values ...v1.ResourceName) *ResourceAliasApplyConfiguration {
+	for i := range values {
+		b.Aliases = append(b.Aliases, values[i])
+	}
+	return b
+}
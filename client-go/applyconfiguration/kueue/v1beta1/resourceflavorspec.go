@@ -24,9 +24,12 @@ import (
 // ResourceFlavorSpecApplyConfiguration represents an declarative configuration of the ResourceFlavorSpec type for use
 // with apply.
 type ResourceFlavorSpecApplyConfiguration struct {
-	NodeLabels  map[string]string `json:"nodeLabels,omitempty"`
-	NodeTaints  []v1.Taint        `json:"nodeTaints,omitempty"`
-	Tolerations []v1.Toleration   `json:"tolerations,omitempty"`
+	NodeLabels             map[string]string    `json:"nodeLabels,omitempty"`
+	NodeSelector           *v1.NodeSelectorTerm `json:"nodeSelector,omitempty"`
+	NodeTaints             []v1.Taint           `json:"nodeTaints,omitempty"`
+	Tolerations            []v1.Toleration      `json:"tolerations,omitempty"`
+	EvictOnNodeUnavailable *bool                `json:"evictOnNodeUnavailable,omitempty"`
+	Cordoned               *bool                `json:"cordoned,omitempty"`
 }
 
 // ResourceFlavorSpecApplyConfiguration constructs an declarative configuration of the ResourceFlavorSpec type for use with
@@ -49,6 +52,14 @@ func (b *ResourceFlavorSpecApplyConfiguration) WithNodeLabels(entries map[string
 	return b
 }
 
+// WithNodeSelector sets the NodeSelector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NodeSelector field is set to the value of the last call.
+func (b *ResourceFlavorSpecApplyConfiguration) WithNodeSelector(value v1.NodeSelectorTerm) *ResourceFlavorSpecApplyConfiguration {
+	b.NodeSelector = &value
+	return b
+}
+
 // WithNodeTaints adds the given value to the NodeTaints field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the NodeTaints field.
@@ -68,3 +79,19 @@ func (b *ResourceFlavorSpecApplyConfiguration) WithTolerations(values ...v1.Tole
 	}
 	return b
 }
+
+// WithEvictOnNodeUnavailable sets the EvictOnNodeUnavailable field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the EvictOnNodeUnavailable field is set to the value of the last call.
+func (b *ResourceFlavorSpecApplyConfiguration) WithEvictOnNodeUnavailable(value bool) *ResourceFlavorSpecApplyConfiguration {
+	b.EvictOnNodeUnavailable = &value
+	return b
+}
+
+// WithCordoned sets the Cordoned field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Cordoned field is set to the value of the last call.
+func (b *ResourceFlavorSpecApplyConfiguration) WithCordoned(value bool) *ResourceFlavorSpecApplyConfiguration {
+	b.Cordoned = &value
+	return b
+}
@@ -25,10 +25,11 @@ import (
 // ResourceQuotaApplyConfiguration represents an declarative configuration of the ResourceQuota type for use
 // with apply.
 type ResourceQuotaApplyConfiguration struct {
-	Name           *v1.ResourceName   `json:"name,omitempty"`
-	NominalQuota   *resource.Quantity `json:"nominalQuota,omitempty"`
-	BorrowingLimit *resource.Quantity `json:"borrowingLimit,omitempty"`
-	LendingLimit   *resource.Quantity `json:"lendingLimit,omitempty"`
+	Name             *v1.ResourceName   `json:"name,omitempty"`
+	NominalQuota     *resource.Quantity `json:"nominalQuota,omitempty"`
+	BorrowingLimit   *resource.Quantity `json:"borrowingLimit,omitempty"`
+	LendingLimit     *resource.Quantity `json:"lendingLimit,omitempty"`
+	NonLendableQuota *resource.Quantity `json:"nonLendableQuota,omitempty"`
 }
 
 // ResourceQuotaApplyConfiguration constructs an declarative configuration of the ResourceQuota type for use with
@@ -68,3 +69,11 @@ func (b *ResourceQuotaApplyConfiguration) WithLendingLimit(value resource.Quanti
 	b.LendingLimit = &value
 	return b
 }
+
+// WithNonLendableQuota sets the NonLendableQuota field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NonLendableQuota field is set to the value of the last call.
+func (b *ResourceQuotaApplyConfiguration) WithNonLendableQuota(value resource.Quantity) *ResourceQuotaApplyConfiguration {
+	b.NonLendableQuota = &value
+	return b
+}
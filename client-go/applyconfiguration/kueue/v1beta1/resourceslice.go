@@ -0,0 +1,60 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// ResourceSliceApplyConfiguration represents an declarative configuration of the ResourceSlice type for use
+// with apply.
+type ResourceSliceApplyConfiguration struct {
+	Name          *v1.ResourceName `json:"name,omitempty"`
+	Resource      *v1.ResourceName `json:"resource,omitempty"`
+	SlicesPerUnit *int32           `json:"slicesPerUnit,omitempty"`
+}
+
+// ResourceSliceApplyConfiguration constructs an declarative configuration of the ResourceSlice type for use with
+// apply.
+func ResourceSlice() *ResourceSliceApplyConfiguration {
+	return &ResourceSliceApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *ResourceSliceApplyConfiguration) WithName(value v1.ResourceName) *ResourceSliceApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithResource sets the Resource field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Resource field is set to the value of the last call.
+func (b *ResourceSliceApplyConfiguration) WithResource(value v1.ResourceName) *ResourceSliceApplyConfiguration {
+	b.Resource = &value
+	return b
+}
+
+// WithSlicesPerUnit sets the SlicesPerUnit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SlicesPerUnit field is set to the value of the last call.
+func (b *ResourceSliceApplyConfiguration) WithSlicesPerUnit(value int32) *ResourceSliceApplyConfiguration {
+	b.SlicesPerUnit = &value
+	return b
+}
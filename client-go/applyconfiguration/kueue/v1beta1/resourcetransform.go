@@ -0,0 +1,70 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceTransformApplyConfiguration represents an declarative configuration of the ResourceTransform type for use
+// with apply.
+type ResourceTransformApplyConfiguration struct {
+	Name       *v1.ResourceName   `json:"name,omitempty"`
+	Multiplier *resource.Quantity `json:"multiplier,omitempty"`
+	Minimum    *resource.Quantity `json:"minimum,omitempty"`
+	PodDefault *resource.Quantity `json:"podDefault,omitempty"`
+}
+
+// ResourceTransformApplyConfiguration constructs an declarative configuration of the ResourceTransform type for use with
+// apply.
+func ResourceTransform() *ResourceTransformApplyConfiguration {
+	return &ResourceTransformApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *ResourceTransformApplyConfiguration) WithName(value v1.ResourceName) *ResourceTransformApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithMultiplier sets the Multiplier field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Multiplier field is set to the value of the last call.
+func (b *ResourceTransformApplyConfiguration) WithMultiplier(value resource.Quantity) *ResourceTransformApplyConfiguration {
+	b.Multiplier = &value
+	return b
+}
+
+// WithMinimum sets the Minimum field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Minimum field is set to the value of the last call.
+func (b *ResourceTransformApplyConfiguration) WithMinimum(value resource.Quantity) *ResourceTransformApplyConfiguration {
+	b.Minimum = &value
+	return b
+}
+
+// WithPodDefault sets the PodDefault field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PodDefault field is set to the value of the last call.
+func (b *ResourceTransformApplyConfiguration) WithPodDefault(value resource.Quantity) *ResourceTransformApplyConfiguration {
+	b.PodDefault = &value
+	return b
+}
@@ -25,9 +25,10 @@ import (
 // ResourceUsageApplyConfiguration represents an declarative configuration of the ResourceUsage type for use
 // with apply.
 type ResourceUsageApplyConfiguration struct {
-	Name     *v1.ResourceName   `json:"name,omitempty"`
-	Total    *resource.Quantity `json:"total,omitempty"`
-	Borrowed *resource.Quantity `json:"borrowed,omitempty"`
+	Name      *v1.ResourceName   `json:"name,omitempty"`
+	Total     *resource.Quantity `json:"total,omitempty"`
+	Borrowed  *resource.Quantity `json:"borrowed,omitempty"`
+	Available *resource.Quantity `json:"available,omitempty"`
 }
 
 // ResourceUsageApplyConfiguration constructs an declarative configuration of the ResourceUsage type for use with
@@ -59,3 +60,11 @@ func (b *ResourceUsageApplyConfiguration) WithBorrowed(value resource.Quantity)
 	b.Borrowed = &value
 	return b
 }
+
+// WithAvailable sets the Available field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Available field is set to the value of the last call.
+func (b *ResourceUsageApplyConfiguration) WithAvailable(value resource.Quantity) *ResourceUsageApplyConfiguration {
+	b.Available = &value
+	return b
+}
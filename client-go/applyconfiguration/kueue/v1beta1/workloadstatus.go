@@ -24,11 +24,14 @@ import (
 // WorkloadStatusApplyConfiguration represents an declarative configuration of the WorkloadStatus type for use
 // with apply.
 type WorkloadStatusApplyConfiguration struct {
-	Admission       *AdmissionApplyConfiguration            `json:"admission,omitempty"`
-	RequeueState    *RequeueStateApplyConfiguration         `json:"requeueState,omitempty"`
-	Conditions      []v1.Condition                          `json:"conditions,omitempty"`
-	ReclaimablePods []ReclaimablePodApplyConfiguration      `json:"reclaimablePods,omitempty"`
-	AdmissionChecks []AdmissionCheckStateApplyConfiguration `json:"admissionChecks,omitempty"`
+	Admission         *AdmissionApplyConfiguration               `json:"admission,omitempty"`
+	RequeueState      *RequeueStateApplyConfiguration            `json:"requeueState,omitempty"`
+	Conditions        []v1.Condition                             `json:"conditions,omitempty"`
+	ReclaimablePods   []ReclaimablePodApplyConfiguration         `json:"reclaimablePods,omitempty"`
+	AdmissionChecks   []AdmissionCheckStateApplyConfiguration    `json:"admissionChecks,omitempty"`
+	CandidateFlavors  []PodSetCandidateFlavorsApplyConfiguration `json:"candidateFlavors,omitempty"`
+	PreviousFlavors   []PodSetFlavorsApplyConfiguration          `json:"previousFlavors,omitempty"`
+	AdmissionAttempts *int32                                     `json:"admissionAttempts,omitempty"`
 }
 
 // WorkloadStatusApplyConfiguration constructs an declarative configuration of the WorkloadStatus type for use with
@@ -88,3 +91,37 @@ func (b *WorkloadStatusApplyConfiguration) WithAdmissionChecks(values ...*Admiss
 	}
 	return b
 }
+
+// WithCandidateFlavors adds the given value to the CandidateFlavors field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the CandidateFlavors field.
+func (b *WorkloadStatusApplyConfiguration) WithCandidateFlavors(values ...*PodSetCandidateFlavorsApplyConfiguration) *WorkloadStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithCandidateFlavors")
+		}
+		b.CandidateFlavors = append(b.CandidateFlavors, *values[i])
+	}
+	return b
+}
+
+// WithPreviousFlavors adds the given value to the PreviousFlavors field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the PreviousFlavors field.
+func (b *WorkloadStatusApplyConfiguration) WithPreviousFlavors(values ...*PodSetFlavorsApplyConfiguration) *WorkloadStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithPreviousFlavors")
+		}
+		b.PreviousFlavors = append(b.PreviousFlavors, *values[i])
+	}
+	return b
+}
+
+// WithAdmissionAttempts sets the AdmissionAttempts field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AdmissionAttempts field is set to the value of the last call.
+func (b *WorkloadStatusApplyConfiguration) WithAdmissionAttempts(value int32) *WorkloadStatusApplyConfiguration {
+	b.AdmissionAttempts = &value
+	return b
+}
@@ -32,6 +32,12 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=kueue.x-k8s.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("Cohort"):
+		return &kueuev1alpha1.CohortApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("CohortSpec"):
+		return &kueuev1alpha1.CohortSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FlavorBorrowingCaps"):
+		return &kueuev1alpha1.FlavorBorrowingCapsApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("KubeConfig"):
 		return &kueuev1alpha1.KubeConfigApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("MultiKueueCluster"):
@@ -44,6 +50,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &kueuev1alpha1.MultiKueueConfigApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("MultiKueueConfigSpec"):
 		return &kueuev1alpha1.MultiKueueConfigSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ResourceBorrowingCap"):
+		return &kueuev1alpha1.ResourceBorrowingCapApplyConfiguration{}
 
 		// Group=kueue.x-k8s.io, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithKind("Admission"):
@@ -72,6 +80,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &kueuev1beta1.ClusterQueuePendingWorkloadsStatusApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ClusterQueuePreemption"):
 		return &kueuev1beta1.ClusterQueuePreemptionApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterQueueSelection"):
+		return &kueuev1beta1.ClusterQueueSelectionApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ClusterQueueSpec"):
 		return &kueuev1beta1.ClusterQueueSpecApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ClusterQueueStatus"):
@@ -86,6 +96,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &kueuev1beta1.FlavorQuotasApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("FlavorUsage"):
 		return &kueuev1beta1.FlavorUsageApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("HeadAdmissionEstimate"):
+		return &kueuev1beta1.HeadAdmissionEstimateApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("LocalQueue"):
 		return &kueuev1beta1.LocalQueueApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("LocalQueueFlavorUsage"):
@@ -100,16 +112,24 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &kueuev1beta1.PodSetApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("PodSetAssignment"):
 		return &kueuev1beta1.PodSetAssignmentApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("PodSetCandidateFlavors"):
+		return &kueuev1beta1.PodSetCandidateFlavorsApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("PodSetFlavors"):
+		return &kueuev1beta1.PodSetFlavorsApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("PodSetUpdate"):
 		return &kueuev1beta1.PodSetUpdateApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ProvisioningRequestConfig"):
 		return &kueuev1beta1.ProvisioningRequestConfigApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ProvisioningRequestConfigSpec"):
 		return &kueuev1beta1.ProvisioningRequestConfigSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("QuotaWindow"):
+		return &kueuev1beta1.QuotaWindowApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ReclaimablePod"):
 		return &kueuev1beta1.ReclaimablePodApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("RequeueState"):
 		return &kueuev1beta1.RequeueStateApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ResourceAlias"):
+		return &kueuev1beta1.ResourceAliasApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ResourceFlavor"):
 		return &kueuev1beta1.ResourceFlavorApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ResourceFlavorSpec"):
@@ -118,6 +138,10 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &kueuev1beta1.ResourceGroupApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ResourceQuota"):
 		return &kueuev1beta1.ResourceQuotaApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ResourceSlice"):
+		return &kueuev1beta1.ResourceSliceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ResourceTransform"):
+		return &kueuev1beta1.ResourceTransformApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("ResourceUsage"):
 		return &kueuev1beta1.ResourceUsageApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("Workload"):
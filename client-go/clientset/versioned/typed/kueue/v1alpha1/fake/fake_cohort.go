@@ -0,0 +1,144 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1alpha1 "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
+	kueuev1alpha1 "sigs.k8s.io/kueue/client-go/applyconfiguration/kueue/v1alpha1"
+)
+
+// FakeCohorts implements CohortInterface
+type FakeCohorts struct {
+	Fake *FakeKueueV1alpha1
+}
+
+var cohortsResource = v1alpha1.SchemeGroupVersion.WithResource("cohorts")
+
+var cohortsKind = v1alpha1.SchemeGroupVersion.WithKind("Cohort")
+
+// Get takes name of the cohort, and returns the corresponding cohort object, and an error if there is any.
+func (c *FakeCohorts) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Cohort, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(cohortsResource, name), &v1alpha1.Cohort{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Cohort), err
+}
+
+// List takes label and field selectors, and returns the list of Cohorts that match those selectors.
+func (c *FakeCohorts) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.CohortList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(cohortsResource, cohortsKind, opts), &v1alpha1.CohortList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.CohortList{ListMeta: obj.(*v1alpha1.CohortList).ListMeta}
+	for _, item := range obj.(*v1alpha1.CohortList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cohorts.
+func (c *FakeCohorts) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(cohortsResource, opts))
+}
+
+// Create takes the representation of a cohort and creates it.  Returns the server's representation of the cohort, and an error, if there is any.
+func (c *FakeCohorts) Create(ctx context.Context, cohort *v1alpha1.Cohort, opts v1.CreateOptions) (result *v1alpha1.Cohort, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(cohortsResource, cohort), &v1alpha1.Cohort{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Cohort), err
+}
+
+// Update takes the representation of a cohort and updates it. Returns the server's representation of the cohort, and an error, if there is any.
+func (c *FakeCohorts) Update(ctx context.Context, cohort *v1alpha1.Cohort, opts v1.UpdateOptions) (result *v1alpha1.Cohort, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(cohortsResource, cohort), &v1alpha1.Cohort{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Cohort), err
+}
+
+// Delete takes name of the cohort and deletes it. Returns an error if one occurs.
+func (c *FakeCohorts) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(cohortsResource, name, opts), &v1alpha1.Cohort{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCohorts) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(cohortsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.CohortList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cohort.
+func (c *FakeCohorts) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Cohort, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(cohortsResource, name, pt, data, subresources...), &v1alpha1.Cohort{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Cohort), err
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied cohort.
+func (c *FakeCohorts) Apply(ctx context.Context, cohort *kueuev1alpha1.CohortApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Cohort, err error) {
+	if cohort == nil {
+		return nil, fmt.Errorf("cohort provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(cohort)
+	if err != nil {
+		return nil, err
+	}
+	name := cohort.Name
+	if name == nil {
+		return nil, fmt.Errorf("cohort.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(cohortsResource, *name, types.ApplyPatchType, data), &v1alpha1.Cohort{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Cohort), err
+}
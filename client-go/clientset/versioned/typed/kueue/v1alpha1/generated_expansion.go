@@ -17,6 +17,8 @@ limitations under the License.
 
 package v1alpha1
 
+type CohortExpansion interface{}
+
 type MultiKueueClusterExpansion interface{}
 
 type MultiKueueConfigExpansion interface{}
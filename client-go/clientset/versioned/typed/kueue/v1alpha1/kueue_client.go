@@ -27,6 +27,7 @@ import (
 
 type KueueV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	CohortsGetter
 	MultiKueueClustersGetter
 	MultiKueueConfigsGetter
 }
@@ -36,6 +37,10 @@ type KueueV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *KueueV1alpha1Client) Cohorts() CohortInterface {
+	return newCohorts(c)
+}
+
 func (c *KueueV1alpha1Client) MultiKueueClusters() MultiKueueClusterInterface {
 	return newMultiKueueClusters(c)
 }
@@ -23,6 +23,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Cohorts returns a CohortInformer.
+	Cohorts() CohortInformer
 	// MultiKueueClusters returns a MultiKueueClusterInformer.
 	MultiKueueClusters() MultiKueueClusterInformer
 	// MultiKueueConfigs returns a MultiKueueConfigInformer.
@@ -40,6 +42,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Cohorts returns a CohortInformer.
+func (v *version) Cohorts() CohortInformer {
+	return &cohortInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // MultiKueueClusters returns a MultiKueueClusterInformer.
 func (v *version) MultiKueueClusters() MultiKueueClusterInformer {
 	return &multiKueueClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -0,0 +1,67 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1alpha1 "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
+)
+
+// CohortLister helps list Cohorts.
+// All objects returned here must be treated as read-only.
+type CohortLister interface {
+	// List lists all Cohorts in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.Cohort, err error)
+	// Get retrieves the Cohort from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.Cohort, error)
+	CohortListerExpansion
+}
+
+// cohortLister implements the CohortLister interface.
+type cohortLister struct {
+	indexer cache.Indexer
+}
+
+// NewCohortLister returns a new CohortLister.
+func NewCohortLister(indexer cache.Indexer) CohortLister {
+	return &cohortLister{indexer: indexer}
+}
+
+// List lists all Cohorts in the indexer.
+func (s *cohortLister) List(selector labels.Selector) (ret []*v1alpha1.Cohort, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.Cohort))
+	})
+	return ret, err
+}
+
+// Get retrieves the Cohort from the index for a given name.
+func (s *cohortLister) Get(name string) (*v1alpha1.Cohort, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("cohort"), name)
+	}
+	return obj.(*v1alpha1.Cohort), nil
+}
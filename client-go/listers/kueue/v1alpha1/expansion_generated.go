@@ -17,6 +17,10 @@ limitations under the License.
 
 package v1alpha1
 
+// CohortListerExpansion allows custom methods to be added to
+// CohortLister.
+type CohortListerExpansion interface{}
+
 // MultiKueueClusterListerExpansion allows custom methods to be added to
 // MultiKueueClusterLister.
 type MultiKueueClusterListerExpansion interface{}
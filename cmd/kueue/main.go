@@ -43,6 +43,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 
 	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
 	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
@@ -50,8 +51,10 @@ import (
 	"sigs.k8s.io/kueue/pkg/cache"
 	"sigs.k8s.io/kueue/pkg/config"
 	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/admissionchecks/externaladmission"
 	"sigs.k8s.io/kueue/pkg/controller/admissionchecks/multikueue"
 	"sigs.k8s.io/kueue/pkg/controller/admissionchecks/provisioning"
+	controllerconsts "sigs.k8s.io/kueue/pkg/controller/constants"
 	"sigs.k8s.io/kueue/pkg/controller/core"
 	"sigs.k8s.io/kueue/pkg/controller/core/indexer"
 	"sigs.k8s.io/kueue/pkg/controller/jobframework"
@@ -60,6 +63,7 @@ import (
 	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
 	"sigs.k8s.io/kueue/pkg/scheduler"
+	"sigs.k8s.io/kueue/pkg/scheduler/decisions"
 	"sigs.k8s.io/kueue/pkg/util/cert"
 	"sigs.k8s.io/kueue/pkg/util/kubeversion"
 	"sigs.k8s.io/kueue/pkg/util/useragent"
@@ -107,6 +111,11 @@ func main() {
 	var featureGates string
 	flag.StringVar(&featureGates, "feature-gates", "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
 
+	var defaultQueueNamespaceLabel string
+	flag.StringVar(&defaultQueueNamespaceLabel, "default-queue-namespace-label", controllerconsts.DefaultQueueNamespaceLabel,
+		"The label key of the namespaces holding the name of the queue for the Jobs created in them without one. "+
+			"Set it to an empty string to disable the defaulting.")
+
 	opts := zap.Options{
 		TimeEncoder: zapcore.RFC3339NanoTimeEncoder,
 		ZapOpts:     []zaplog.Option{zaplog.AddCaller()},
@@ -141,6 +150,30 @@ func main() {
 	// for each API type.
 	kubeConfig.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(*cfg.ClientConnection.QPS, int(*cfg.ClientConnection.Burst))
 	setupLog.V(2).Info("K8S Client", "qps", *cfg.ClientConnection.QPS, "burst", *cfg.ClientConnection.Burst)
+	var cCache *cache.Cache
+	if options.Metrics.ExtraHandlers == nil {
+		options.Metrics.ExtraHandlers = make(map[string]http.Handler)
+	}
+	options.Metrics.ExtraHandlers[debugger.CohortGraphPath] = debugger.NewCohortGraphHandler(func() *cache.Cache { return cCache })
+	var queues *queue.Manager
+	options.Metrics.ExtraHandlers[debugger.QueuesPath] = debugger.NewQueuesHandler(func() *queue.Manager { return queues })
+	var sched *scheduler.Scheduler
+	// The metrics listener doesn't authenticate its clients, so the endpoints
+	// changing the state of the scheduler or running it on arbitrary input
+	// are only served to the requests authorized by the API server.
+	protectDebugHandler := setupDebugHandlerFilter(kubeConfig)
+	options.Metrics.ExtraHandlers[debugger.SchedulingPausePath] = protectDebugHandler(debugger.NewSchedulingPauseHandler(func() debugger.Pauser {
+		if sched == nil {
+			return nil
+		}
+		return sched
+	}))
+	options.Metrics.ExtraHandlers[debugger.CanFitPath] = protectDebugHandler(debugger.NewCanFitHandler(func() debugger.Fitter {
+		if sched == nil {
+			return nil
+		}
+		return sched
+	}))
 	mgr, err := ctrl.NewManager(kubeConfig, options)
 	if err != nil {
 		setupLog.Error(err, "Unable to start manager")
@@ -166,8 +199,12 @@ func main() {
 	if cfg.FairSharing != nil {
 		cacheOptions = append(cacheOptions, cache.WithFairSharing(cfg.FairSharing.Enable))
 	}
-	cCache := cache.New(mgr.GetClient(), cacheOptions...)
-	queues := queue.NewManager(mgr.GetClient(), cCache, queueOptions...)
+	if cfg.ClusterQueueInactiveGracePeriod != nil {
+		cacheOptions = append(cacheOptions, cache.WithInactiveGracePeriod(cfg.ClusterQueueInactiveGracePeriod.Duration))
+	}
+	cCache = cache.New(mgr.GetClient(), cacheOptions...)
+	metrics.RegisterClusterQueueUsageTrend(cCache.UsageTrends)
+	queues = queue.NewManager(mgr.GetClient(), cCache, queueOptions...)
 
 	ctx := ctrl.SetupSignalHandler()
 	if err := setupIndexes(ctx, mgr, &cfg); err != nil {
@@ -182,7 +219,7 @@ func main() {
 	// Cert won't be ready until manager starts, so start a goroutine here which
 	// will block until the cert is ready before setting up the controllers.
 	// Controllers who register after manager starts will start directly.
-	go setupControllers(mgr, cCache, queues, certsReady, &cfg, serverVersionFetcher)
+	go setupControllers(mgr, cCache, queues, certsReady, &cfg, serverVersionFetcher, defaultQueueNamespaceLabel)
 
 	go func() {
 		queues.CleanUpOnContext(ctx)
@@ -195,7 +232,7 @@ func main() {
 		go visibility.CreateAndStartVisibilityServer(ctx, queues)
 	}
 
-	setupScheduler(mgr, cCache, queues, &cfg)
+	sched = setupScheduler(mgr, cCache, queues, &cfg)
 
 	setupLog.Info("Starting manager")
 	if err := mgr.Start(ctx); err != nil {
@@ -220,6 +257,13 @@ func setupIndexes(ctx context.Context, mgr ctrl.Manager, cfg *configapi.Configur
 		}
 	}
 
+	if features.Enabled(features.NodeUnavailableEviction) {
+		if err := indexer.SetupPodNodeName(ctx, mgr.GetFieldIndexer()); err != nil {
+			setupLog.Error(err, "Could not setup pod nodeName indexer")
+			os.Exit(1)
+		}
+	}
+
 	if features.Enabled(features.MultiKueue) {
 		if err := multikueue.SetupIndexer(ctx, mgr.GetFieldIndexer(), *cfg.Namespace); err != nil {
 			setupLog.Error(err, "Could not setup multikueue indexer")
@@ -233,7 +277,7 @@ func setupIndexes(ctx context.Context, mgr ctrl.Manager, cfg *configapi.Configur
 	return jobframework.SetupIndexes(ctx, mgr.GetFieldIndexer(), opts...)
 }
 
-func setupControllers(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manager, certsReady chan struct{}, cfg *configapi.Configuration, serverVersionFetcher *kubeversion.ServerVersionFetcher) {
+func setupControllers(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manager, certsReady chan struct{}, cfg *configapi.Configuration, serverVersionFetcher *kubeversion.ServerVersionFetcher, defaultQueueNamespaceLabel string) {
 	// The controllers won't work until the webhooks are operating, and the webhook won't work until the
 	// certs are all in place.
 	cert.WaitForCertsReady(setupLog, certsReady)
@@ -269,7 +313,19 @@ func setupControllers(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manag
 		}
 	}
 
-	if failedWebhook, err := webhooks.Setup(mgr); err != nil {
+	if ea := cfg.ExternalAdmission; ea != nil {
+		eaController := externaladmission.NewController(mgr.GetClient(), mgr.GetEventRecorderFor("kueue-external-admission-controller"), ea.URL, ea.Timeout.Duration)
+		if err := eaController.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "Could not setup external admission controller")
+			os.Exit(1)
+		}
+	}
+
+	var webhookOpts []webhooks.Option
+	if cfg.Workloads != nil {
+		webhookOpts = append(webhookOpts, webhooks.WithAllowZeroCountPodSets(ptr.Deref(cfg.Workloads.AllowZeroCountPodSets, true)))
+	}
+	if failedWebhook, err := webhooks.Setup(mgr, webhookOpts...); err != nil {
 		setupLog.Error(err, "Unable to create webhook", "webhook", failedWebhook)
 		os.Exit(1)
 	}
@@ -285,6 +341,10 @@ func setupControllers(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manag
 		jobframework.WithLabelKeysToCopy(cfg.Integrations.LabelKeysToCopy),
 		jobframework.WithCache(cCache),
 		jobframework.WithQueues(queues),
+		jobframework.WithQueueNameEnforcement(cfg.Integrations.QueueNameEnforcement),
+		jobframework.WithDefaultQueueNamespaceLabel(defaultQueueNamespaceLabel),
+		jobframework.WithAdmittedButUnschedulableThreshold(cfg.AdmittedButUnschedulableThreshold),
+		jobframework.WithAPIReader(mgr.GetAPIReader()),
 	}
 	if err := jobframework.SetupControllers(mgr, setupLog, opts...); err != nil {
 		setupLog.Error(err, "Unable to create controller or webhook", "kubernetesVersion", serverVersionFetcher.GetServerVersion())
@@ -322,19 +382,52 @@ func setupProbeEndpoints(mgr ctrl.Manager, certsReady <-chan struct{}) {
 	}
 }
 
-func setupScheduler(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manager, cfg *configapi.Configuration) {
+// setupDebugHandlerFilter returns a function wrapping the debug handlers
+// to require the requests to carry a token that the API server authenticates
+// and authorizes for the verb and path of the request.
+func setupDebugHandlerFilter(kubeConfig *rest.Config) func(http.Handler) http.Handler {
+	httpClient, err := rest.HTTPClientFor(kubeConfig)
+	if err != nil {
+		setupLog.Error(err, "Unable to create the HTTP client for the debug endpoints")
+		os.Exit(1)
+	}
+	filter, err := filters.WithAuthenticationAndAuthorization(kubeConfig, httpClient)
+	if err != nil {
+		setupLog.Error(err, "Unable to set up the authorization of the debug endpoints")
+		os.Exit(1)
+	}
+	log := ctrl.Log.WithName("debugger")
+	return func(h http.Handler) http.Handler {
+		protected, err := filter(log, h)
+		if err != nil {
+			setupLog.Error(err, "Unable to protect a debug endpoint")
+			os.Exit(1)
+		}
+		return protected
+	}
+}
+
+func setupScheduler(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manager, cfg *configapi.Configuration) *scheduler.Scheduler {
+	opts := []scheduler.Option{
+		scheduler.WithPodsReadyRequeuingTimestamp(podsReadyRequeuingTimestamp(cfg)),
+		scheduler.WithFairSharing(cfg.FairSharing),
+		scheduler.WithEvictionOrdering(cfg.EvictionOrdering),
+	}
+	if features.Enabled(features.AdmissionDecisionPublishing) {
+		opts = append(opts, scheduler.WithDecisionPublisher(decisions.NewLeaseStore(mgr.GetClient(), mgr.GetAPIReader(), *cfg.Namespace)))
+	}
 	sched := scheduler.New(
 		queues,
 		cCache,
 		mgr.GetClient(),
 		mgr.GetEventRecorderFor(constants.AdmissionName),
-		scheduler.WithPodsReadyRequeuingTimestamp(podsReadyRequeuingTimestamp(cfg)),
-		scheduler.WithFairSharing(cfg.FairSharing),
+		opts...,
 	)
 	if err := mgr.Add(sched); err != nil {
 		setupLog.Error(err, "Unable to add scheduler to manager")
 		os.Exit(1)
 	}
+	return sched
 }
 
 func setupServerVersionFetcher(mgr ctrl.Manager, kubeConfig *rest.Config) *kubeversion.ServerVersionFetcher {
@@ -20,22 +20,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	utilindexer "sigs.k8s.io/kueue/pkg/controller/core/indexer"
 	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/resources"
+	utilcohort "sigs.k8s.io/kueue/pkg/util/cohort"
+	utillocalqueue "sigs.k8s.io/kueue/pkg/util/localqueue"
+	"sigs.k8s.io/kueue/pkg/util/quotawindow"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -43,6 +54,8 @@ var (
 	ErrCqNotFound          = errors.New("cluster queue not found")
 	errQNotFound           = errors.New("queue not found")
 	errWorkloadNotAdmitted = errors.New("workload not admitted by a ClusterQueue")
+
+	realClock = clock.RealClock{}
 )
 
 const (
@@ -55,6 +68,7 @@ type options struct {
 	workloadInfoOptions []workload.InfoOption
 	podsReadyTracking   bool
 	fairSharingEnabled  bool
+	inactiveGracePeriod time.Duration
 }
 
 // Option configures the reconciler.
@@ -81,6 +95,14 @@ func WithFairSharing(enabled bool) Option {
 	}
 }
 
+// WithInactiveGracePeriod sets the time a ClusterQueue referencing missing
+// ResourceFlavors remains active before it's marked inactive.
+func WithInactiveGracePeriod(d time.Duration) Option {
+	return func(o *options) {
+		o.inactiveGracePeriod = d
+	}
+}
+
 var defaultOptions = options{}
 
 // Cache keeps track of the Workloads that got admitted through ClusterQueues.
@@ -91,12 +113,15 @@ type Cache struct {
 	client              client.Client
 	clusterQueues       map[string]*ClusterQueue
 	cohorts             map[string]*Cohort
+	cohortConfigs       map[string]cohortConfig
 	assumedWorkloads    map[string]string
 	resourceFlavors     map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor
 	podsReadyTracking   bool
 	admissionChecks     map[string]AdmissionCheck
 	workloadInfoOptions []workload.InfoOption
 	fairSharingEnabled  bool
+	inactiveGracePeriod time.Duration
+	clock               clock.Clock
 }
 
 func New(client client.Client, opts ...Option) *Cache {
@@ -108,12 +133,15 @@ func New(client client.Client, opts ...Option) *Cache {
 		client:              client,
 		clusterQueues:       make(map[string]*ClusterQueue),
 		cohorts:             make(map[string]*Cohort),
+		cohortConfigs:       make(map[string]cohortConfig),
 		assumedWorkloads:    make(map[string]string),
 		resourceFlavors:     make(map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor),
 		admissionChecks:     make(map[string]AdmissionCheck),
 		podsReadyTracking:   options.podsReadyTracking,
 		workloadInfoOptions: options.workloadInfoOptions,
 		fairSharingEnabled:  options.fairSharingEnabled,
+		inactiveGracePeriod: options.inactiveGracePeriod,
+		clock:               realClock,
 	}
 	c.podsReadyCond.L = &c.RWMutex
 	return c
@@ -127,6 +155,8 @@ func (c *Cache) newClusterQueue(cq *kueue.ClusterQueue) (*ClusterQueue, error) {
 		localQueues:         make(map[string]*queue),
 		podsReadyTracking:   c.podsReadyTracking,
 		workloadInfoOptions: c.workloadInfoOptions,
+		inactiveGracePeriod: c.inactiveGracePeriod,
+		clock:               c.clock,
 	}
 	if err := cqImpl.update(cq, c.resourceFlavors, c.admissionChecks); err != nil {
 		return nil, err
@@ -213,6 +243,16 @@ func (c *Cache) updateClusterQueues() sets.Set[string] {
 func (c *Cache) AddOrUpdateResourceFlavor(rf *kueue.ResourceFlavor) sets.Set[string] {
 	c.Lock()
 	defer c.Unlock()
+	if oldRF, found := c.resourceFlavors[kueue.ResourceFlavorReference(rf.Name)]; found && !equality.Semantic.DeepEqual(oldRF.Spec, rf.Spec) {
+		// The flavor might now match workloads that it didn't match before,
+		// so the last assignments attempted in the ClusterQueues using it are
+		// no longer valid.
+		for _, cq := range c.clusterQueues {
+			if cq.flavorInUse(rf.Name) {
+				cq.AllocatableResourceGeneration++
+			}
+		}
+	}
 	c.resourceFlavors[kueue.ResourceFlavorReference(rf.Name)] = rf
 	return c.updateClusterQueues()
 }
@@ -244,6 +284,127 @@ func (c *Cache) DeleteAdmissionCheck(ac *kueue.AdmissionCheck) sets.Set[string]
 	return c.updateClusterQueues()
 }
 
+// AddOrUpdateCohort stores the configuration of the cohort and returns the
+// names of the ClusterQueues that are members of its hierarchy, or were
+// before the update.
+func (c *Cache) AddOrUpdateCohort(cohort *kueuealpha.Cohort) sets.Set[string] {
+	c.Lock()
+	defer c.Unlock()
+	cfg := cohortConfig{
+		parent:        cohort.Spec.Parent,
+		borrowingCaps: borrowingCaps(cohort.Spec.BorrowingCaps),
+	}
+	if cohort.Spec.ReclaimDelay != nil {
+		cfg.reclaimDelay = cohort.Spec.ReclaimDelay.Duration
+	}
+	cfg.borrowingSafetyMarginPercent = ptr.Deref(cohort.Spec.BorrowingSafetyMarginPercent, 0)
+	c.cohortConfigs[cohort.Name] = cfg
+	return c.updateCohortConfig(cohort.Name)
+}
+
+// DeleteCohort drops the configuration of the cohort and returns the
+// names of the ClusterQueues that are members of its hierarchy, or were
+// before the deletion.
+func (c *Cache) DeleteCohort(cohort *kueuealpha.Cohort) sets.Set[string] {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.cohortConfigs, cohort.Name)
+	return c.updateCohortConfig(cohort.Name)
+}
+
+// cohortConfig is the configuration of a cohort set through a Cohort object.
+type cohortConfig struct {
+	parent                       string
+	borrowingCaps                resources.FlavorResourceQuantities
+	reclaimDelay                 time.Duration
+	borrowingSafetyMarginPercent int32
+}
+
+func (c *Cache) updateCohortConfig(name string) sets.Set[string] {
+	affected := sets.New[*ClusterQueue]()
+	// A change of parent moves the ClusterQueues of the subtree to a
+	// different path of cohorts. They are removed from all the cohorts of
+	// their old path before they join the new one, so that no cohort keeps
+	// a stale parent.
+	var moved []*ClusterQueue
+	for _, cq := range c.clusterQueues {
+		if cq.Cohort == nil || slices.Equal(c.cohortPath(cq.cohortName), cq.cohortNames()) {
+			continue
+		}
+		affected = affected.Union(cq.Cohort.Members)
+		c.deleteClusterQueueFromCohort(cq)
+		moved = append(moved, cq)
+	}
+	for _, cq := range moved {
+		c.addClusterQueueToCohort(cq, cq.cohortName)
+		affected = affected.Union(cq.Cohort.Members)
+	}
+	if cohort, ok := c.cohorts[name]; ok {
+		c.cohortConfigs[name].applyTo(cohort)
+	}
+	if root, ok := c.cohorts[c.rootCohort(name)]; ok {
+		affected = affected.Union(root.Members)
+	}
+	cqs := sets.New[string]()
+	for cq := range affected {
+		// The caps and the quota of the hierarchy might allow or prevent
+		// borrowing that was not possible before, so the last assignments
+		// are no longer valid.
+		cq.AllocatableResourceGeneration++
+		cqs.Insert(cq.Name)
+	}
+	return cqs
+}
+
+// rootCohort returns the root of the hierarchy of the cohort, following the
+// parents set through Cohort objects.
+func (c *Cache) rootCohort(name string) string {
+	return utilcohort.Root(name, c.cohortParent)
+}
+
+// cohortPath returns the cohort followed by its ancestors, up to the root of
+// its hierarchy.
+func (c *Cache) cohortPath(name string) []string {
+	if name == "" {
+		return nil
+	}
+	return utilcohort.Path(name, c.cohortParent)
+}
+
+// CohortCycle returns the cohorts that form the cycle reached by following
+// the parents of the cohort, or nil if its hierarchy has a root. The cohorts
+// of a cycle are treated as roots.
+func (c *Cache) CohortCycle(name string) []string {
+	c.RLock()
+	defer c.RUnlock()
+	return utilcohort.Cycle(name, c.cohortParent)
+}
+
+func (c *Cache) cohortParent(name string) string {
+	return c.cohortConfigs[name].parent
+}
+
+func (cfg cohortConfig) applyTo(cohort *Cohort) {
+	cohort.BorrowingCaps = cfg.borrowingCaps
+	cohort.ReclaimDelay = cfg.reclaimDelay
+	cohort.BorrowingSafetyMarginPercent = cfg.borrowingSafetyMarginPercent
+}
+
+func borrowingCaps(in []kueuealpha.FlavorBorrowingCaps) resources.FlavorResourceQuantities {
+	if len(in) == 0 {
+		return nil
+	}
+	caps := make(resources.FlavorResourceQuantities, len(in))
+	for _, fCaps := range in {
+		rCaps := make(map[corev1.ResourceName]int64, len(fCaps.Resources))
+		for _, rCap := range fCaps.Resources {
+			rCaps[rCap.Name] = workload.ResourceValue(rCap.Name, rCap.Cap)
+		}
+		caps[fCaps.Name] = rCaps
+	}
+	return caps
+}
+
 func (c *Cache) AdmissionChecksForClusterQueue(cqName string) []AdmissionCheck {
 	c.RLock()
 	defer c.RUnlock()
@@ -264,6 +425,20 @@ func (c *Cache) ClusterQueueActive(name string) bool {
 	return c.clusterQueueInStatus(name, active)
 }
 
+// ValidateRequestsFitCapacity returns an error if a pod of the workload
+// requests more of a resource than any flavor of the ClusterQueue can
+// provide.
+func (c *Cache) ValidateRequestsFitCapacity(cqName string, wl *kueue.Workload) error {
+	c.RLock()
+	defer c.RUnlock()
+	cq := c.clusterQueues[cqName]
+	if cq == nil {
+		return nil
+	}
+	info := workload.NewInfo(wl, c.workloadInfoOptions...)
+	return cq.ValidateRequestsFitCapacity(cq.RequestsWithAliases(info.TotalRequests))
+}
+
 func (c *Cache) ClusterQueueTerminating(name string) bool {
 	return c.clusterQueueInStatus(name, terminating)
 }
@@ -282,6 +457,62 @@ func (c *Cache) ClusterQueueReadiness(name string) (metav1.ConditionStatus, stri
 	return metav1.ConditionFalse, reason, msg
 }
 
+// RecheckClusterQueueStatus updates the status of the ClusterQueue, marking
+// it inactive if the grace period for its missing flavors elapsed. It returns
+// the remaining time of the grace period, or 0 if the ClusterQueue isn't
+// within one.
+func (c *Cache) RecheckClusterQueueStatus(name string) time.Duration {
+	c.Lock()
+	defer c.Unlock()
+	cq := c.clusterQueues[name]
+	if cq == nil {
+		return 0
+	}
+	cq.updateQueueStatus()
+	return cq.inactiveGracePeriodRemaining()
+}
+
+// Now returns the current time of the clock of the cache, which decides the
+// active quota windows.
+func (c *Cache) Now() time.Time {
+	return c.clock.Now()
+}
+
+// RefreshQuotaWindow applies the quotas of the quota window of the
+// ClusterQueue that is active now. Returns whether the quotas changed and
+// the time until the next quota window starts or ends, or 0 if the
+// ClusterQueue has no quota windows.
+func (c *Cache) RefreshQuotaWindow(name string) (bool, time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	cq := c.clusterQueues[name]
+	if cq == nil || len(cq.quotaWindows) == 0 {
+		return false, 0
+	}
+	changed := cq.refreshQuotaWindow(c.resourceFlavors)
+	return changed, quotawindow.UntilNextBoundary(cq.quotaWindows, c.clock.Now())
+}
+
+// ClusterQueueCordonedFlavors returns the flavors of the ClusterQueue that
+// are cordoned, in the order in which they appear in the ClusterQueue.
+func (c *Cache) ClusterQueueCordonedFlavors(name string) []kueue.ResourceFlavorReference {
+	c.RLock()
+	defer c.RUnlock()
+	cq := c.clusterQueues[name]
+	if cq == nil {
+		return nil
+	}
+	var cordoned []kueue.ResourceFlavorReference
+	for _, rg := range cq.ResourceGroups {
+		for _, fq := range rg.Flavors {
+			if rf, found := c.resourceFlavors[fq.Name]; found && rf.Spec.Cordoned {
+				cordoned = append(cordoned, fq.Name)
+			}
+		}
+	}
+	return cordoned
+}
+
 func (c *Cache) clusterQueueInStatus(name string, status metrics.ClusterQueueStatus) bool {
 	c.RLock()
 	defer c.RUnlock()
@@ -332,16 +563,21 @@ func (c *Cache) AddClusterQueue(ctx context.Context, cq *kueue.ClusterQueue) err
 	// On controller restart, an add ClusterQueue event may come after
 	// add queue and workload, so here we explicitly list and add existing queues
 	// and workloads.
-	var queues kueue.LocalQueueList
+	var queues, candidateQueues kueue.LocalQueueList
 	if err := c.client.List(ctx, &queues, client.MatchingFields{utilindexer.QueueClusterQueueKey: cq.Name}); err != nil {
 		return fmt.Errorf("listing queues that match the clusterQueue: %w", err)
 	}
-	for _, q := range queues.Items {
+	if err := c.client.List(ctx, &candidateQueues, client.MatchingFields{utilindexer.QueueCandidateClusterQueueKey: cq.Name}); err != nil {
+		return fmt.Errorf("listing queues that can select the clusterQueue: %w", err)
+	}
+	for _, q := range append(queues.Items, candidateQueues.Items...) {
 		qKey := queueKey(&q)
 		qImpl := &queue{
 			key:                qKey,
+			clusterQueues:      utillocalqueue.ClusterQueueNames(&q),
 			reservingWorkloads: 0,
 			admittedWorkloads:  0,
+			maxConcurrency:     q.Spec.MaxConcurrency,
 			//TODO: rename this to better distinguish between reserved and in use quantities
 			usage:         make(resources.FlavorResourceQuantities),
 			admittedUsage: make(resources.FlavorResourceQuantities),
@@ -361,6 +597,9 @@ func (c *Cache) AddClusterQueue(ctx context.Context, cq *kueue.ClusterQueue) err
 		}
 		c.addOrUpdateWorkload(&workloads.Items[i])
 	}
+	// The existing workloads don't contribute to the usage trend.
+	cqImpl.usageSamples = nil
+	cqImpl.recordUsage()
 
 	return nil
 }
@@ -389,7 +628,7 @@ func (c *Cache) UpdateClusterQueue(cq *kueue.ClusterQueue) error {
 		return nil
 	}
 
-	if cqImpl.Cohort.Name != cq.Spec.Cohort {
+	if cqImpl.cohortName != cq.Spec.Cohort {
 		c.deleteClusterQueueFromCohort(cqImpl)
 		c.addClusterQueueToCohort(cqImpl, cq.Spec.Cohort)
 	}
@@ -411,40 +650,82 @@ func (c *Cache) DeleteClusterQueue(cq *kueue.ClusterQueue) {
 func (c *Cache) AddLocalQueue(q *kueue.LocalQueue) error {
 	c.Lock()
 	defer c.Unlock()
-	cq, ok := c.clusterQueues[string(q.Spec.ClusterQueue)]
-	if !ok {
-		return nil
+	for _, cqName := range utillocalqueue.ClusterQueueNames(q) {
+		if cq, ok := c.clusterQueues[cqName]; ok {
+			if err := cq.addLocalQueue(q); err != nil {
+				return err
+			}
+		}
 	}
-	return cq.addLocalQueue(q)
+	return nil
 }
 
 func (c *Cache) DeleteLocalQueue(q *kueue.LocalQueue) {
 	c.Lock()
 	defer c.Unlock()
-	cq, ok := c.clusterQueues[string(q.Spec.ClusterQueue)]
-	if !ok {
-		return
+	for _, cqName := range utillocalqueue.ClusterQueueNames(q) {
+		if cq, ok := c.clusterQueues[cqName]; ok {
+			cq.deleteLocalQueue(q)
+		}
 	}
-	cq.deleteLocalQueue(q)
 }
 
 func (c *Cache) UpdateLocalQueue(oldQ, newQ *kueue.LocalQueue) error {
-	if oldQ.Spec.ClusterQueue == newQ.Spec.ClusterQueue {
-		return nil
-	}
 	c.Lock()
 	defer c.Unlock()
-	cq, ok := c.clusterQueues[string(oldQ.Spec.ClusterQueue)]
-	if ok {
-		cq.deleteLocalQueue(oldQ)
+	newCQNames := sets.New(utillocalqueue.ClusterQueueNames(newQ)...)
+	for _, cqName := range utillocalqueue.ClusterQueueNames(oldQ) {
+		if cq, ok := c.clusterQueues[cqName]; ok && !newCQNames.Has(cqName) {
+			cq.deleteLocalQueue(oldQ)
+		}
 	}
-	cq, ok = c.clusterQueues[string(newQ.Spec.ClusterQueue)]
-	if ok {
-		return cq.addLocalQueue(newQ)
+	for cqName := range newCQNames {
+		cq, ok := c.clusterQueues[cqName]
+		if !ok {
+			continue
+		}
+		if _, tracked := cq.localQueues[queueKey(newQ)]; tracked {
+			cq.updateLocalQueue(newQ)
+		} else if err := cq.addLocalQueue(newQ); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// forEachLocalQueue calls f with the entry of the queue in each of the
+// clusterQueues that can serve its workloads.
+func (c *Cache) forEachLocalQueue(q *queue, f func(cq *ClusterQueue, qImpl *queue)) {
+	for _, cqName := range q.clusterQueues {
+		if cq, ok := c.clusterQueues[cqName]; ok {
+			if qImpl, ok := cq.localQueues[q.key]; ok {
+				f(cq, qImpl)
+			}
+		}
+	}
+}
+
+// LocalQueueMaxConcurrencyReached returns whether the LocalQueue of the
+// workload already has as many workloads reserving quota as its
+// maxConcurrency allows.
+func (c *Cache) LocalQueueMaxConcurrencyReached(w *workload.Info) bool {
+	c.RLock()
+	defer c.RUnlock()
+	cq, ok := c.clusterQueues[w.ClusterQueue]
+	if !ok {
+		return false
+	}
+	q, ok := cq.localQueues[workload.QueueKey(w.Obj)]
+	if !ok || q.maxConcurrency == nil {
+		return false
+	}
+	reserving := 0
+	c.forEachLocalQueue(q, func(_ *ClusterQueue, qImpl *queue) {
+		reserving += qImpl.reservingWorkloads
+	})
+	return reserving >= int(*q.maxConcurrency)
+}
+
 func (c *Cache) AddOrUpdateWorkload(w *kueue.Workload) bool {
 	c.Lock()
 	defer c.Unlock()
@@ -481,7 +762,11 @@ func (c *Cache) UpdateWorkload(oldWl, newWl *kueue.Workload) error {
 		if !ok {
 			return errors.New("old ClusterQueue doesn't exist")
 		}
-		cq.deleteWorkload(oldWl)
+		if workload.HasQuotaReservation(newWl) {
+			cq.deleteWorkload(oldWl)
+		} else {
+			cq.releaseWorkload(oldWl)
+		}
 	}
 	c.cleanupAssumedState(oldWl)
 
@@ -509,13 +794,81 @@ func (c *Cache) DeleteWorkload(w *kueue.Workload) error {
 
 	c.cleanupAssumedState(w)
 
-	cq.deleteWorkload(w)
+	cq.releaseWorkload(w)
 	if c.podsReadyTracking {
 		c.podsReadyCond.Broadcast()
 	}
 	return nil
 }
 
+// WorkloadOutOfSync returns whether the cache disagrees with the quota
+// reservation of the workload, either because a workload with a quota
+// reservation isn't tracked in its ClusterQueue, or because a workload is
+// tracked without one. Assumed workloads are never out of sync.
+func (c *Cache) WorkloadOutOfSync(w *kueue.Workload) bool {
+	c.RLock()
+	defer c.RUnlock()
+	_, outOfSync := c.workloadInconsistency(w)
+	return outOfSync
+}
+
+// SyncWorkload repairs the cache when it's out of sync with the quota
+// reservation of the workload, returning whether it was. The cache is only
+// compared with the workload while current returns true, which should
+// report whether the workload is still the latest version applied to the
+// cache; otherwise, the cache is left untouched.
+func (c *Cache) SyncWorkload(w *kueue.Workload, current func() bool) bool {
+	c.Lock()
+	defer c.Unlock()
+	if !current() {
+		return false
+	}
+	inconsistency, outOfSync := c.workloadInconsistency(w)
+	if !outOfSync {
+		return false
+	}
+	metrics.ReportWorkloadCacheInconsistency(inconsistency)
+	k := workload.Key(w)
+	for _, cq := range c.clusterQueues {
+		if _, tracked := cq.Workloads[k]; tracked {
+			cq.deleteWorkload(w)
+		}
+	}
+	if workload.HasQuotaReservation(w) {
+		c.addOrUpdateWorkload(w)
+	} else if c.podsReadyTracking {
+		c.podsReadyCond.Broadcast()
+	}
+	return true
+}
+
+func (c *Cache) workloadInconsistency(w *kueue.Workload) (metrics.WorkloadCacheInconsistency, bool) {
+	k := workload.Key(w)
+	if _, assumed := c.assumedWorkloads[k]; assumed {
+		return "", false
+	}
+	var expected *ClusterQueue
+	if workload.HasQuotaReservation(w) {
+		cq, exists := c.clusterQueues[string(w.Status.Admission.ClusterQueue)]
+		if !exists {
+			// The workload is added along with its ClusterQueue.
+			return "", false
+		}
+		expected = cq
+	}
+	for _, cq := range c.clusterQueues {
+		if _, tracked := cq.Workloads[k]; tracked && cq != expected {
+			return metrics.WorkloadCacheInconsistencyUnexpected, true
+		}
+	}
+	if expected != nil {
+		if _, tracked := expected.Workloads[k]; !tracked {
+			return metrics.WorkloadCacheInconsistencyMissing, true
+		}
+	}
+	return "", false
+}
+
 func (c *Cache) IsAssumedOrAdmittedWorkload(w workload.Info) bool {
 	c.RLock()
 	defer c.RUnlock()
@@ -601,9 +954,9 @@ func (c *Cache) Usage(cqObj *kueue.ClusterQueue) (*ClusterQueueUsageStats, error
 	}
 
 	stats := &ClusterQueueUsageStats{
-		ReservedResources:  getUsage(cq.Usage, cq.ResourceGroups, cq.Cohort),
+		ReservedResources:  getUsage(cq.Usage, cq.ResourceGroups, cq.Cohort, cq.availableQuota()),
 		ReservingWorkloads: len(cq.Workloads),
-		AdmittedResources:  getUsage(cq.AdmittedUsage, cq.ResourceGroups, cq.Cohort),
+		AdmittedResources:  getUsage(cq.AdmittedUsage, cq.ResourceGroups, cq.Cohort, nil),
 		AdmittedWorkloads:  cq.admittedWorkloadsCount,
 	}
 
@@ -615,7 +968,135 @@ func (c *Cache) Usage(cqObj *kueue.ClusterQueue) (*ClusterQueueUsageStats, error
 	return stats, nil
 }
 
-func getUsage(frq resources.FlavorResourceQuantities, rgs []ResourceGroup, cohort *Cohort) []kueue.FlavorUsage {
+// HeadAdmissionEstimate returns an estimate of when the head workload of the
+// ClusterQueue is likely to be admitted.
+func (c *Cache) HeadAdmissionEstimate(cqObj *kueue.ClusterQueue, head *workload.Info) (*kueue.HeadAdmissionEstimate, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	cq := c.clusterQueues[cqObj.Name]
+	if cq == nil {
+		return nil, ErrCqNotFound
+	}
+	return cq.headAdmissionEstimate(head), nil
+}
+
+// UsageTrends returns the average rate of change of the usage of the
+// ClusterQueues over the last minutes, per flavor and resource.
+func (c *Cache) UsageTrends() []metrics.UsageTrend {
+	c.RLock()
+	defer c.RUnlock()
+	var trends []metrics.UsageTrend
+	for _, cq := range c.clusterQueues {
+		trends = append(trends, cq.usageTrends()...)
+	}
+	return trends
+}
+
+// ClusterQueueHasCapacity returns whether the requests of the workload fit in
+// the quota that the ClusterQueue can still reserve, added up across flavors.
+func (c *Cache) ClusterQueueHasCapacity(name string, wl *workload.Info) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	cq := c.clusterQueues[name]
+	if cq == nil {
+		return false
+	}
+	available := make(workload.Requests)
+	for _, flvAvailable := range cq.availableQuota() {
+		for rName, v := range flvAvailable {
+			available[rName] += v
+		}
+	}
+	for rName, requested := range cq.totalRequests(wl.TotalRequests) {
+		if requested > available[rName] {
+			return false
+		}
+	}
+	return true
+}
+
+// ClusterQueueLoad returns the highest fraction of the nominal quota in use
+// among the flavors and resources of the ClusterQueue.
+func (c *Cache) ClusterQueueLoad(name string) float64 {
+	c.RLock()
+	defer c.RUnlock()
+
+	cq := c.clusterQueues[name]
+	if cq == nil {
+		return 0
+	}
+	var load float64
+	for _, rg := range cq.ResourceGroups {
+		for _, flvQuotas := range rg.Flavors {
+			for rName, rQuota := range flvQuotas.Resources {
+				if rQuota.Nominal > 0 {
+					load = max(load, float64(cq.Usage[flvQuotas.Name][rName])/float64(rQuota.Nominal))
+				}
+			}
+		}
+	}
+	return load
+}
+
+// ResourceQuotaSummary describes the quota of a resource in a flavor of a
+// ClusterQueue and how much of it is in use.
+type ResourceQuotaSummary struct {
+	Flavor   kueue.ResourceFlavorReference
+	Resource corev1.ResourceName
+	// Guaranteed is the nominal quota of the ClusterQueue.
+	Guaranteed resource.Quantity
+	Used       resource.Quantity
+	// Borrowed is the part of Used that exceeds the nominal quota.
+	Borrowed resource.Quantity
+	// Available is the quota that the ClusterQueue can still reserve,
+	// including what it can borrow from its cohort.
+	Available resource.Quantity
+}
+
+// ClusterQueueSummary returns the quota summary of the ClusterQueue, ordered
+// like the flavors in its resource groups and by resource name within a flavor.
+// The returned values are copies and can be used without holding the cache lock.
+func (c *Cache) ClusterQueueSummary(name string) ([]ResourceQuotaSummary, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	cq := c.clusterQueues[name]
+	if cq == nil {
+		return nil, ErrCqNotFound
+	}
+	available := cq.availableQuota()
+	var summary []ResourceQuotaSummary
+	for _, rg := range cq.ResourceGroups {
+		for _, flvQuotas := range rg.Flavors {
+			rNames := make([]corev1.ResourceName, 0, len(flvQuotas.Resources))
+			for rName := range flvQuotas.Resources {
+				rNames = append(rNames, rName)
+			}
+			slices.Sort(rNames)
+			for _, rName := range rNames {
+				nominal := flvQuotas.Resources[rName].Nominal
+				used := cq.Usage[flvQuotas.Name][rName]
+				var borrowed int64
+				if cq.Cohort != nil {
+					borrowed = max(0, used-nominal)
+				}
+				summary = append(summary, ResourceQuotaSummary{
+					Flavor:     flvQuotas.Name,
+					Resource:   rName,
+					Guaranteed: workload.ResourceQuantity(rName, nominal),
+					Used:       workload.ResourceQuantity(rName, used),
+					Borrowed:   workload.ResourceQuantity(rName, borrowed),
+					Available:  workload.ResourceQuantity(rName, available[flvQuotas.Name][rName]),
+				})
+			}
+		}
+	}
+	return summary, nil
+}
+
+func getUsage(frq resources.FlavorResourceQuantities, rgs []ResourceGroup, cohort *Cohort, available resources.FlavorResourceQuantities) []kueue.FlavorUsage {
 	usage := make([]kueue.FlavorUsage, 0, len(frq))
 	for _, rg := range rgs {
 		for _, flvQuotas := range rg.Flavors {
@@ -637,6 +1118,9 @@ func getUsage(frq resources.FlavorResourceQuantities, rgs []ResourceGroup, cohor
 						rUsage.Borrowed = workload.ResourceQuantity(rName, borrowed)
 					}
 				}
+				if available != nil {
+					rUsage.Available = ptr.To(workload.ResourceQuantity(rName, available[flvQuotas.Name][rName]))
+				}
 				outFlvUsage.Resources = append(outFlvUsage.Resources, rUsage)
 			}
 			// The resourceUsages should be in a stable order to avoid endless creation of update events.
@@ -669,12 +1153,15 @@ func (c *Cache) LocalQueueUsage(qObj *kueue.LocalQueue) (*LocalQueueUsageStats,
 		return nil, errQNotFound
 	}
 
-	return &LocalQueueUsageStats{
-		ReservedResources:  filterLocalQueueUsage(qImpl.usage, cqImpl.ResourceGroups),
-		ReservingWorkloads: qImpl.reservingWorkloads,
-		AdmittedResources:  filterLocalQueueUsage(qImpl.admittedUsage, cqImpl.ResourceGroups),
-		AdmittedWorkloads:  qImpl.admittedWorkloads,
-	}, nil
+	// The usage of a queue with multiple clusterQueues is added up across them.
+	stats := &LocalQueueUsageStats{}
+	c.forEachLocalQueue(qImpl, func(cqImpl *ClusterQueue, qImpl *queue) {
+		stats.ReservedResources = mergeLocalQueueUsage(stats.ReservedResources, filterLocalQueueUsage(qImpl.usage, cqImpl.ResourceGroups))
+		stats.ReservingWorkloads += qImpl.reservingWorkloads
+		stats.AdmittedResources = mergeLocalQueueUsage(stats.AdmittedResources, filterLocalQueueUsage(qImpl.admittedUsage, cqImpl.ResourceGroups))
+		stats.AdmittedWorkloads += qImpl.admittedWorkloads
+	})
+	return stats, nil
 }
 
 func filterLocalQueueUsage(orig resources.FlavorResourceQuantities, resourceGroups []ResourceGroup) []kueue.LocalQueueFlavorUsage {
@@ -702,6 +1189,34 @@ func filterLocalQueueUsage(orig resources.FlavorResourceQuantities, resourceGrou
 	return qFlvUsages
 }
 
+// mergeLocalQueueUsage adds the usage in src to the usage in dst, adding the
+// flavors and resources that are not in dst yet.
+func mergeLocalQueueUsage(dst, src []kueue.LocalQueueFlavorUsage) []kueue.LocalQueueFlavorUsage {
+	if dst == nil {
+		return src
+	}
+	for _, srcFlv := range src {
+		i := slices.IndexFunc(dst, func(f kueue.LocalQueueFlavorUsage) bool { return f.Name == srcFlv.Name })
+		if i < 0 {
+			dst = append(dst, srcFlv)
+			continue
+		}
+		dstFlv := &dst[i]
+		for _, srcRes := range srcFlv.Resources {
+			j := slices.IndexFunc(dstFlv.Resources, func(r kueue.LocalQueueResourceUsage) bool { return r.Name == srcRes.Name })
+			if j < 0 {
+				dstFlv.Resources = append(dstFlv.Resources, srcRes)
+				continue
+			}
+			dstFlv.Resources[j].Total.Add(srcRes.Total)
+		}
+		sort.Slice(dstFlv.Resources, func(i, j int) bool {
+			return dstFlv.Resources[i].Name < dstFlv.Resources[j].Name
+		})
+	}
+	return dst
+}
+
 func (c *Cache) cleanupAssumedState(w *kueue.Workload) {
 	k := workload.Key(w)
 	assumedCQName, assumed := c.assumedWorkloads[k]
@@ -730,27 +1245,39 @@ func (c *Cache) clusterQueueForWorkload(w *kueue.Workload) *ClusterQueue {
 	return nil
 }
 
+// addClusterQueueToCohort adds the ClusterQueue to the cohort and to all its
+// ancestors, creating the ones that didn't have members.
 func (c *Cache) addClusterQueueToCohort(cq *ClusterQueue, cohortName string) {
-	if cohortName == "" {
-		return
-	}
-	cohort, ok := c.cohorts[cohortName]
-	if !ok {
-		cohort = newCohort(cohortName, 1)
-		c.cohorts[cohortName] = cohort
+	cq.cohortName = cohortName
+	var child *Cohort
+	for _, name := range c.cohortPath(cohortName) {
+		cohort, ok := c.cohorts[name]
+		if !ok {
+			cohort = newCohort(name, 1)
+			c.cohortConfigs[name].applyTo(cohort)
+			c.cohorts[name] = cohort
+		}
+		cohort.Members.Insert(cq)
+		if child == nil {
+			cq.leafCohort = cohort
+		} else {
+			child.parent = cohort
+		}
+		child = cohort
 	}
-	cohort.Members.Insert(cq)
-	cq.Cohort = cohort
+	cq.Cohort = child
 }
 
+// deleteClusterQueueFromCohort removes the ClusterQueue from all the cohorts
+// of its hierarchy, dropping the ones that are left without members.
 func (c *Cache) deleteClusterQueueFromCohort(cq *ClusterQueue) {
-	if cq.Cohort == nil {
-		return
-	}
-	cq.Cohort.Members.Delete(cq)
-	if cq.Cohort.Members.Len() == 0 {
-		delete(c.cohorts, cq.Cohort.Name)
+	for _, cohort := range cq.cohortPath() {
+		cohort.Members.Delete(cq)
+		if cohort.Members.Len() == 0 {
+			delete(c.cohorts, cohort.Name)
+		}
 	}
+	cq.leafCohort = nil
 	cq.Cohort = nil
 }
 
@@ -780,6 +1307,24 @@ func (c *Cache) ClusterQueuesUsingAdmissionCheck(ac string) []string {
 	return cqs
 }
 
+// CohortPeers returns the names of the other ClusterQueues in the cohort
+// hierarchy of the ClusterQueue, whose available quota depends on its usage.
+func (c *Cache) CohortPeers(name string) []string {
+	c.RLock()
+	defer c.RUnlock()
+	cq, ok := c.clusterQueues[name]
+	if !ok || cq.Cohort == nil {
+		return nil
+	}
+	peers := make([]string, 0, cq.Cohort.Members.Len()-1)
+	for member := range cq.Cohort.Members {
+		if member != cq {
+			peers = append(peers, member.Name)
+		}
+	}
+	return peers
+}
+
 func (c *Cache) MatchingClusterQueues(nsLabels map[string]string) sets.Set[string] {
 	c.RLock()
 	defer c.RUnlock()
@@ -797,3 +1342,50 @@ func (c *Cache) MatchingClusterQueues(nsLabels map[string]string) sets.Set[strin
 func queueKey(q *kueue.LocalQueue) string {
 	return fmt.Sprintf("%s/%s", q.Namespace, q.Name)
 }
+
+// CohortGraph describes the membership of the ClusterQueues in cohorts.
+type CohortGraph struct {
+	Cohorts []CohortGraphNode `json:"cohorts"`
+}
+
+// CohortGraphNode describes a cohort, its parent and the ClusterQueues that
+// join it through .spec.cohort.
+type CohortGraphNode struct {
+	Name          string   `json:"name"`
+	Parent        string   `json:"parent,omitempty"`
+	ClusterQueues []string `json:"clusterQueues"`
+}
+
+// CohortGraph returns the cohorts that have member ClusterQueues, are
+// configured through a Cohort object or are the parent of one, sorted by name.
+func (c *Cache) CohortGraph() CohortGraph {
+	c.RLock()
+	defer c.RUnlock()
+	members := make(map[string][]string)
+	for _, cq := range c.clusterQueues {
+		if cq.cohortName != "" {
+			members[cq.cohortName] = append(members[cq.cohortName], cq.Name)
+		}
+	}
+	names := sets.KeySet(members)
+	for name, cfg := range c.cohortConfigs {
+		names.Insert(name)
+		if cfg.parent != "" {
+			names.Insert(cfg.parent)
+		}
+	}
+	graph := CohortGraph{Cohorts: make([]CohortGraphNode, 0, names.Len())}
+	for _, name := range sets.List(names) {
+		node := CohortGraphNode{
+			Name:          name,
+			Parent:        c.cohortConfigs[name].parent,
+			ClusterQueues: members[name],
+		}
+		if node.ClusterQueues == nil {
+			node.ClusterQueues = []string{}
+		}
+		sort.Strings(node.ClusterQueues)
+		graph.Cohorts = append(graph.Cohorts, node)
+	}
+	return graph
+}
@@ -20,7 +20,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -30,12 +32,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/component-base/metrics/testutil"
+	testingclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/features"
+	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/resources"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
 	"sigs.k8s.io/kueue/pkg/workload"
@@ -474,7 +480,7 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 			wantClusterQueues: map[string]*ClusterQueue{
 				"a": {
 					Name:                          "a",
-					AllocatableResourceGeneration: 2,
+					AllocatableResourceGeneration: 3,
 					ResourceGroups: []ResourceGroup{{
 						CoveredResources: sets.New(corev1.ResourceCPU),
 						Flavors: []FlavorQuotas{{
@@ -538,7 +544,7 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 				},
 				"e": {
 					Name:                          "e",
-					AllocatableResourceGeneration: 2,
+					AllocatableResourceGeneration: 3,
 					ResourceGroups: []ResourceGroup{{
 						CoveredResources: sets.New(corev1.ResourceCPU),
 						Flavors: []FlavorQuotas{{
@@ -1945,6 +1951,7 @@ func TestClusterQueueUsage(t *testing.T) {
 	}
 	cases := map[string]struct {
 		clusterQueue           *kueue.ClusterQueue
+		cohortPeers            []*kueue.ClusterQueue
 		workloads              []kueue.Workload
 		wantReservedResources  []kueue.FlavorUsage
 		wantReservingWorkloads int
@@ -1958,29 +1965,32 @@ func TestClusterQueueUsage(t *testing.T) {
 				{
 					Name: "default",
 					Resources: []kueue.ResourceUsage{{
-						Name:  corev1.ResourceCPU,
-						Total: resource.MustParse("8"),
+						Name:      corev1.ResourceCPU,
+						Total:     resource.MustParse("8"),
+						Available: ptr.To(resource.MustParse("2")),
 					}},
 				},
 				{
 					Name: "model_a",
 					Resources: []kueue.ResourceUsage{{
-						Name:  "example.com/gpu",
-						Total: resource.MustParse("5"),
+						Name:      "example.com/gpu",
+						Total:     resource.MustParse("5"),
+						Available: ptr.To(resource.MustParse("0")),
 					}},
 				},
 				{
 					Name: "model_b",
 					Resources: []kueue.ResourceUsage{{
-						Name: "example.com/gpu",
+						Name:      "example.com/gpu",
+						Available: ptr.To(resource.MustParse("5")),
 					}},
 				},
 				{
 					Name: "interconnect_a",
 					Resources: []kueue.ResourceUsage{
-						{Name: "example.com/vf-0"},
-						{Name: "example.com/vf-1"},
-						{Name: "example.com/vf-2"},
+						{Name: "example.com/vf-0", Available: ptr.To(resource.MustParse("5"))},
+						{Name: "example.com/vf-1", Available: ptr.To(resource.MustParse("5"))},
+						{Name: "example.com/vf-2", Available: ptr.To(resource.MustParse("5"))},
 					},
 				},
 			},
@@ -2024,32 +2034,35 @@ func TestClusterQueueUsage(t *testing.T) {
 				{
 					Name: "default",
 					Resources: []kueue.ResourceUsage{{
-						Name:     corev1.ResourceCPU,
-						Total:    resource.MustParse("13"),
-						Borrowed: resource.MustParse("3"),
+						Name:      corev1.ResourceCPU,
+						Total:     resource.MustParse("13"),
+						Borrowed:  resource.MustParse("3"),
+						Available: ptr.To(resource.MustParse("0")),
 					}},
 				},
 				{
 					Name: "model_a",
 					Resources: []kueue.ResourceUsage{{
-						Name:  "example.com/gpu",
-						Total: resource.MustParse("5"),
+						Name:      "example.com/gpu",
+						Total:     resource.MustParse("5"),
+						Available: ptr.To(resource.MustParse("0")),
 					}},
 				},
 				{
 					Name: "model_b",
 					Resources: []kueue.ResourceUsage{{
-						Name:     "example.com/gpu",
-						Total:    resource.MustParse("6"),
-						Borrowed: resource.MustParse("1"),
+						Name:      "example.com/gpu",
+						Total:     resource.MustParse("6"),
+						Borrowed:  resource.MustParse("1"),
+						Available: ptr.To(resource.MustParse("0")),
 					}},
 				},
 				{
 					Name: "interconnect_a",
 					Resources: []kueue.ResourceUsage{
-						{Name: "example.com/vf-0"},
-						{Name: "example.com/vf-1"},
-						{Name: "example.com/vf-2"},
+						{Name: "example.com/vf-0", Available: ptr.To(resource.MustParse("5"))},
+						{Name: "example.com/vf-1", Available: ptr.To(resource.MustParse("5"))},
+						{Name: "example.com/vf-2", Available: ptr.To(resource.MustParse("5"))},
 					},
 				},
 			},
@@ -2093,9 +2106,45 @@ func TestClusterQueueUsage(t *testing.T) {
 				{
 					Name: "default",
 					Resources: []kueue.ResourceUsage{{
-						Name:     corev1.ResourceCPU,
-						Total:    resource.MustParse("13"),
-						Borrowed: resource.MustParse("0"),
+						Name:      corev1.ResourceCPU,
+						Total:     resource.MustParse("13"),
+						Borrowed:  resource.MustParse("0"),
+						Available: ptr.To(resource.MustParse("0")),
+					}},
+				},
+				{
+					Name: "model_a",
+					Resources: []kueue.ResourceUsage{{
+						Name:      "example.com/gpu",
+						Total:     resource.MustParse("5"),
+						Available: ptr.To(resource.MustParse("0")),
+					}},
+				},
+				{
+					Name: "model_b",
+					Resources: []kueue.ResourceUsage{{
+						Name:      "example.com/gpu",
+						Total:     resource.MustParse("6"),
+						Borrowed:  resource.MustParse("0"),
+						Available: ptr.To(resource.MustParse("0")),
+					}},
+				},
+				{
+					Name: "interconnect_a",
+					Resources: []kueue.ResourceUsage{
+						{Name: "example.com/vf-0", Available: ptr.To(resource.MustParse("5"))},
+						{Name: "example.com/vf-1", Available: ptr.To(resource.MustParse("5"))},
+						{Name: "example.com/vf-2", Available: ptr.To(resource.MustParse("5"))},
+					},
+				},
+			},
+			wantReservingWorkloads: 2,
+			wantUsedResources: []kueue.FlavorUsage{
+				{
+					Name: "default",
+					Resources: []kueue.ResourceUsage{{
+						Name:  corev1.ResourceCPU,
+						Total: resource.MustParse("8"),
 					}},
 				},
 				{
@@ -2108,9 +2157,7 @@ func TestClusterQueueUsage(t *testing.T) {
 				{
 					Name: "model_b",
 					Resources: []kueue.ResourceUsage{{
-						Name:     "example.com/gpu",
-						Total:    resource.MustParse("6"),
-						Borrowed: resource.MustParse("0"),
+						Name: "example.com/gpu",
 					}},
 				},
 				{
@@ -2122,7 +2169,59 @@ func TestClusterQueueUsage(t *testing.T) {
 					},
 				},
 			},
-			wantReservingWorkloads: 2,
+			wantAdmittedWorkloads: 1,
+		},
+		"clusterQueue with cohort; available quota from the cohort": {
+			clusterQueue: cq,
+			cohortPeers: []*kueue.ClusterQueue{
+				utiltesting.MakeClusterQueue("bar").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("default").
+							Resource(corev1.ResourceCPU, "4").
+							Obj(),
+					).
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("model_a").
+							Resource("example.com/gpu", "2").
+							Obj(),
+					).
+					Cohort("one").Obj(),
+			},
+			workloads: workloads[:1],
+			wantReservedResources: []kueue.FlavorUsage{
+				{
+					Name: "default",
+					Resources: []kueue.ResourceUsage{{
+						Name:      corev1.ResourceCPU,
+						Total:     resource.MustParse("8"),
+						Available: ptr.To(resource.MustParse("6")),
+					}},
+				},
+				{
+					Name: "model_a",
+					Resources: []kueue.ResourceUsage{{
+						Name:      "example.com/gpu",
+						Total:     resource.MustParse("5"),
+						Available: ptr.To(resource.MustParse("2")),
+					}},
+				},
+				{
+					Name: "model_b",
+					Resources: []kueue.ResourceUsage{{
+						Name:      "example.com/gpu",
+						Available: ptr.To(resource.MustParse("5")),
+					}},
+				},
+				{
+					Name: "interconnect_a",
+					Resources: []kueue.ResourceUsage{
+						{Name: "example.com/vf-0", Available: ptr.To(resource.MustParse("5"))},
+						{Name: "example.com/vf-1", Available: ptr.To(resource.MustParse("5"))},
+						{Name: "example.com/vf-2", Available: ptr.To(resource.MustParse("5"))},
+					},
+				},
+			},
+			wantReservingWorkloads: 1,
 			wantUsedResources: []kueue.FlavorUsage{
 				{
 					Name: "default",
@@ -2160,6 +2259,11 @@ func TestClusterQueueUsage(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			cache := New(utiltesting.NewFakeClient())
 			ctx := context.Background()
+			for _, peer := range tc.cohortPeers {
+				if err := cache.AddClusterQueue(ctx, peer); err != nil {
+					t.Fatalf("Adding ClusterQueue: %v", err)
+				}
+			}
 			err := cache.AddClusterQueue(ctx, tc.clusterQueue)
 			if err != nil {
 				t.Fatalf("Adding ClusterQueue: %v", err)
@@ -2754,7 +2858,7 @@ func TestCacheQueueOperations(t *testing.T) {
 					cacheQueues[qKey] = cacheQ
 				}
 			}
-			if diff := cmp.Diff(tc.wantLocalQueues, cacheQueues, cmp.AllowUnexported(queue{})); diff != "" {
+			if diff := cmp.Diff(tc.wantLocalQueues, cacheQueues, cmp.AllowUnexported(queue{}), cmpopts.IgnoreFields(queue{}, "clusterQueues")); diff != "" {
 				t.Errorf("Unexpected localQueues (-want,+got):\n%s", diff)
 			}
 		})
@@ -2826,132 +2930,687 @@ func TestClusterQueuesUsingFlavor(t *testing.T) {
 	}
 }
 
-func TestMatchingClusterQueues(t *testing.T) {
-	clusterQueues := []*kueue.ClusterQueue{
-		utiltesting.MakeClusterQueue("matching1").
-			NamespaceSelector(&metav1.LabelSelector{}).Obj(),
-		utiltesting.MakeClusterQueue("not-matching").
-			NamespaceSelector(nil).Obj(),
-		utiltesting.MakeClusterQueue("matching2").
-			NamespaceSelector(&metav1.LabelSelector{
-				MatchExpressions: []metav1.LabelSelectorRequirement{
-					{
-						Key:      "dep",
-						Operator: metav1.LabelSelectorOpIn,
-						Values:   []string{"eng"},
-					},
-				},
-			}).Obj(),
+func TestResourceFlavorUpdate(t *testing.T) {
+	x86Rf := utiltesting.MakeResourceFlavor("x86").Obj()
+	aarch64Rf := utiltesting.MakeResourceFlavor("aarch64").Obj()
+	fooCq := utiltesting.MakeClusterQueue("fooCq").
+		ResourceGroup(
+			*utiltesting.MakeFlavorQuotas("x86").Resource("cpu", "5").Obj()).
+		Obj()
+	barCq := utiltesting.MakeClusterQueue("barCq").
+		ResourceGroup(
+			*utiltesting.MakeFlavorQuotas("aarch64").Resource("cpu", "5").Obj()).
+		Obj()
+
+	cache := New(utiltesting.NewFakeClient())
+	cache.AddOrUpdateResourceFlavor(x86Rf)
+	cache.AddOrUpdateResourceFlavor(aarch64Rf)
+	for _, cq := range []*kueue.ClusterQueue{fooCq, barCq} {
+		if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+			t.Fatalf("Failed to add clusterQueue %s: %v", cq.Name, err)
+		}
 	}
-	wantCQs := sets.New("matching1", "matching2")
+	generations := func() map[string]int64 {
+		return map[string]int64{
+			"fooCq": cache.clusterQueues["fooCq"].AllocatableResourceGeneration,
+			"barCq": cache.clusterQueues["barCq"].AllocatableResourceGeneration,
+		}
+	}
+	initial := generations()
+
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("x86").Label("arch", "x86").Obj())
+	wantGenerations := map[string]int64{
+		"fooCq": initial["fooCq"] + 1,
+		"barCq": initial["barCq"],
+	}
+	if diff := cmp.Diff(wantGenerations, generations()); diff != "" {
+		t.Errorf("Unexpected generations after updating the flavor labels (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(sets.New("arch"), cache.clusterQueues["fooCq"].ResourceGroups[0].LabelKeys); diff != "" {
+		t.Errorf("Unexpected label keys after adding flavor labels (-want,+got):\n%s", diff)
+	}
+
+	rfWithAnnotation := utiltesting.MakeResourceFlavor("x86").Label("arch", "x86").Obj()
+	rfWithAnnotation.Annotations = map[string]string{"foo": "bar"}
+	cache.AddOrUpdateResourceFlavor(rfWithAnnotation)
+	if diff := cmp.Diff(wantGenerations, generations()); diff != "" {
+		t.Errorf("Unexpected generations after updating the flavor metadata (-want,+got):\n%s", diff)
+	}
+
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("x86").Obj())
+	if labelKeys := cache.clusterQueues["fooCq"].ResourceGroups[0].LabelKeys; labelKeys != nil {
+		t.Errorf("Unexpected label keys after removing flavor labels: %v", sets.List(labelKeys))
+	}
+}
+
+func TestCohortBorrowingCaps(t *testing.T) {
+	cohort := &kueuealpha.Cohort{
+		ObjectMeta: metav1.ObjectMeta{Name: "cohort"},
+		Spec: kueuealpha.CohortSpec{
+			BorrowingCaps: []kueuealpha.FlavorBorrowingCaps{{
+				Name: "default",
+				Resources: []kueuealpha.ResourceBorrowingCap{{
+					Name: corev1.ResourceCPU,
+					Cap:  resource.MustParse("2"),
+				}},
+			}},
+		},
+	}
+	wantCaps := resources.FlavorResourceQuantitiesFlat{
+		{Flavor: "default", Resource: corev1.ResourceCPU}: 2_000,
+	}.Unflatten()
 
 	cache := New(utiltesting.NewFakeClient())
-	for _, cq := range clusterQueues {
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if cqs := cache.AddOrUpdateCohort(cohort); cqs.Len() != 0 {
+		t.Errorf("Unexpected ClusterQueues affected by a cohort without members: %v", sets.List(cqs))
+	}
+	for _, name := range []string{"a", "b"} {
+		cq := utiltesting.MakeClusterQueue(name).
+			Cohort("cohort").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "1").Obj()).
+			Obj()
 		if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
-			t.Errorf("failed to add clusterQueue %s", cq.Name)
+			t.Fatalf("Failed to add clusterQueue %s: %v", cq.Name, err)
 		}
 	}
+	if diff := cmp.Diff(wantCaps, cache.Snapshot().ClusterQueues["a"].Cohort.BorrowingCaps); diff != "" {
+		t.Errorf("Unexpected borrowing caps in snapshot (-want,+got):\n%s", diff)
+	}
 
-	gotCQs := cache.MatchingClusterQueues(map[string]string{"dep": "eng"})
-	if diff := cmp.Diff(wantCQs, gotCQs); diff != "" {
-		t.Errorf("Wrong ClusterQueues (-want,+got):\n%s", diff)
+	generation := cache.clusterQueues["a"].AllocatableResourceGeneration
+	if diff := cmp.Diff(sets.New("a", "b"), cache.DeleteCohort(cohort)); diff != "" {
+		t.Errorf("Unexpected ClusterQueues affected by deleting the cohort (-want,+got):\n%s", diff)
+	}
+	if caps := cache.Snapshot().ClusterQueues["a"].Cohort.BorrowingCaps; caps != nil {
+		t.Errorf("Unexpected borrowing caps in snapshot after deleting the cohort: %v", caps)
+	}
+	if got := cache.clusterQueues["a"].AllocatableResourceGeneration; got != generation+1 {
+		t.Errorf("Unexpected generation after deleting the cohort, got %d, want %d", got, generation+1)
 	}
 }
 
-// TestWaitForPodsReadyCancelled ensures that the WaitForPodsReady call does not block when the context is closed.
-func TestWaitForPodsReadyCancelled(t *testing.T) {
-	cache := New(utiltesting.NewFakeClient(), WithPodsReadyTracking(true))
-	ctx, cancel := context.WithCancel(context.Background())
-	log := ctrl.LoggerFrom(ctx)
+func TestValidateRequestsFitCapacity(t *testing.T) {
+	cases := map[string]struct {
+		cq      *kueue.ClusterQueue
+		wl      *kueue.Workload
+		wantErr string
+	}{
+		"fits in the nominal quota": {
+			cq: utiltesting.MakeClusterQueue("cq").
+				ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "4").Obj()).
+				Obj(),
+			wl: utiltesting.MakeWorkload("wl", "ns").
+				PodSets(*utiltesting.MakePodSet("main", 2).Request(corev1.ResourceCPU, "4").Obj()).
+				Obj(),
+		},
+		"exceeds the nominal quota without cohort": {
+			cq: utiltesting.MakeClusterQueue("cq").
+				ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "4").Obj()).
+				Obj(),
+			wl: utiltesting.MakeWorkload("wl", "ns").
+				PodSets(*utiltesting.MakePodSet("main", 2).Request(corev1.ResourceCPU, "5").Obj()).
+				Obj(),
+			wantErr: "cpu of podSet main requests 5 per pod, more than the maximum capacity of 4 of any flavor",
+		},
+		"largest flavor is used": {
+			cq: utiltesting.MakeClusterQueue("cq").
+				ResourceGroup(
+					*utiltesting.MakeFlavorQuotas("small").Resource(corev1.ResourceCPU, "2").Obj(),
+					*utiltesting.MakeFlavorQuotas("large").Resource(corev1.ResourceCPU, "8").Obj(),
+				).
+				Obj(),
+			wl: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "6").
+				Obj(),
+		},
+		"exceeds the borrowing limit in cohort": {
+			cq: utiltesting.MakeClusterQueue("cq").
+				Cohort("cohort").
+				ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "4", "2").Obj()).
+				Obj(),
+			wl: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "7").
+				Obj(),
+			wantErr: "cpu of podSet main requests 7 per pod, more than the maximum capacity of 6 of any flavor",
+		},
+		"no borrowing limit in cohort": {
+			cq: utiltesting.MakeClusterQueue("cq").
+				Cohort("cohort").
+				ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "4").Obj()).
+				Obj(),
+			wl: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "100").
+				Obj(),
+		},
+		"resource not covered by the ClusterQueue": {
+			cq: utiltesting.MakeClusterQueue("cq").
+				ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "4").Obj()).
+				Obj(),
+			wl: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceMemory, "1Gi").
+				Obj(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cache := New(utiltesting.NewFakeClient())
+			if err := cache.AddClusterQueue(context.Background(), tc.cq); err != nil {
+				t.Fatalf("Failed to add clusterQueue %s: %v", tc.cq.Name, err)
+			}
+			var gotErr string
+			if err := cache.ValidateRequestsFitCapacity(tc.cq.Name, tc.wl); err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tc.wantErr {
+				t.Errorf("Unexpected error, want %q, got %q", tc.wantErr, gotErr)
+			}
+		})
+	}
+}
 
-	go cache.CleanUpOnContext(ctx)
+func TestCohortHierarchy(t *testing.T) {
+	cache := New(utiltesting.NewFakeClient())
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	cache.AddOrUpdateCohort(utiltesting.MakeCohort("org").ReclaimDelay(time.Minute).Obj())
+	cache.AddOrUpdateCohort(utiltesting.MakeCohort("dept").Parent("org").Obj())
+	for name, cohort := range map[string]string{"a": "org", "b": "dept", "c": "team"} {
+		cq := utiltesting.MakeClusterQueue(name).
+			Cohort(cohort).
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "1").Obj()).
+			Obj()
+		if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+			t.Fatalf("Failed to add clusterQueue %s: %v", cq.Name, err)
+		}
+	}
 
-	cq := kueue.ClusterQueue{
-		ObjectMeta: metav1.ObjectMeta{Name: "one"},
+	snapshot := cache.Snapshot()
+	if got := snapshot.ClusterQueues["b"].Cohort.Name; got != "org" {
+		t.Errorf("Unexpected cohort of the ClusterQueue in a child cohort, want org, got %s", got)
 	}
-	if err := cache.AddClusterQueue(ctx, &cq); err != nil {
-		t.Fatalf("Failed adding clusterQueue: %v", err)
+	if got := snapshot.ClusterQueues["b"].Cohort.ReclaimDelay; got != time.Minute {
+		t.Errorf("Unexpected reclaim delay of the hierarchy, want %v, got %v", time.Minute, got)
+	}
+	if diff := cmp.Diff(sets.New("a", "b"), cohortMemberNames(snapshot.ClusterQueues["a"].Cohort)); diff != "" {
+		t.Errorf("Unexpected members of the hierarchy (-want,+got):\n%s", diff)
+	}
+	wantResources := resources.FlavorResourceQuantitiesFlat{
+		{Flavor: "default", Resource: corev1.ResourceCPU}: 2_000,
+	}.Unflatten()
+	if diff := cmp.Diff(wantResources, snapshot.ClusterQueues["a"].Cohort.RequestableResources); diff != "" {
+		t.Errorf("Unexpected requestable resources of the hierarchy (-want,+got):\n%s", diff)
 	}
 
-	wl := utiltesting.MakeWorkload("a", "").ReserveQuota(&kueue.Admission{
-		ClusterQueue: "one",
-	}).Obj()
-	if err := cache.AssumeWorkload(wl); err != nil {
-		t.Fatalf("Failed assuming the workload to block the further admission: %v", err)
+	wantLevels := map[string]sets.Set[string]{
+		"org":  sets.New("a", "b"),
+		"dept": sets.New("b"),
+		"team": sets.New("c"),
+	}
+	if diff := cmp.Diff(wantLevels, cohortLevels(cache)); diff != "" {
+		t.Errorf("Unexpected members of the cohorts (-want,+got):\n%s", diff)
 	}
 
-	if cache.PodsReadyForAllAdmittedWorkloads(log) {
-		t.Fatalf("Unexpected that all admitted workloads are in PodsReady condition")
+	cqs := cache.AddOrUpdateCohort(utiltesting.MakeCohort("team").Parent("dept").Obj())
+	if diff := cmp.Diff(sets.New("a", "b", "c"), cqs); diff != "" {
+		t.Errorf("Unexpected ClusterQueues affected by setting the parent (-want,+got):\n%s", diff)
+	}
+	snapshot = cache.Snapshot()
+	if got := snapshot.ClusterQueues["c"].Cohort.Name; got != "org" {
+		t.Errorf("Unexpected cohort of the ClusterQueue after setting the parent, want org, got %s", got)
+	}
+	if diff := cmp.Diff(sets.New("a", "b", "c"), cohortMemberNames(snapshot.ClusterQueues["a"].Cohort)); diff != "" {
+		t.Errorf("Unexpected members of the hierarchy after setting the parent (-want,+got):\n%s", diff)
 	}
 
-	// cancel the context so that the WaitForPodsReady is returns
-	go cancel()
+	wantLevels = map[string]sets.Set[string]{
+		"org":  sets.New("a", "b", "c"),
+		"dept": sets.New("b", "c"),
+		"team": sets.New("c"),
+	}
+	if diff := cmp.Diff(wantLevels, cohortLevels(cache)); diff != "" {
+		t.Errorf("Unexpected members of the cohorts after setting the parent (-want,+got):\n%s", diff)
+	}
 
-	cache.WaitForPodsReady(ctx)
+	cqs = cache.AddOrUpdateCohort(utiltesting.MakeCohort("dept").Obj())
+	if diff := cmp.Diff(sets.New("a", "b", "c"), cqs); diff != "" {
+		t.Errorf("Unexpected ClusterQueues affected by removing the parent (-want,+got):\n%s", diff)
+	}
+	snapshot = cache.Snapshot()
+	if diff := cmp.Diff(sets.New("a"), cohortMemberNames(snapshot.ClusterQueues["a"].Cohort)); diff != "" {
+		t.Errorf("Unexpected members of the root after removing the parent (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(sets.New("b", "c"), cohortMemberNames(snapshot.ClusterQueues["b"].Cohort)); diff != "" {
+		t.Errorf("Unexpected members of the new root after removing the parent (-want,+got):\n%s", diff)
+	}
+	if got := snapshot.ClusterQueues["b"].Cohort.ReclaimDelay; got != 0 {
+		t.Errorf("Unexpected reclaim delay of the new root, got %v", got)
+	}
 }
 
-// TestCachePodsReadyForAllAdmittedWorkloads verifies the condition used to determine whether to wait
-func TestCachePodsReadyForAllAdmittedWorkloads(t *testing.T) {
-	clusterQueues := []kueue.ClusterQueue{
-		{
-			ObjectMeta: metav1.ObjectMeta{Name: "one"},
-		},
-		{
-			ObjectMeta: metav1.ObjectMeta{Name: "two"},
-		},
+func TestCohortCycle(t *testing.T) {
+	cache := New(utiltesting.NewFakeClient())
+	cache.AddOrUpdateCohort(utiltesting.MakeCohort("a").Parent("b").Obj())
+	cache.AddOrUpdateCohort(utiltesting.MakeCohort("b").Parent("c").Obj())
+	if got := cache.CohortCycle("a"); got != nil {
+		t.Errorf("Unexpected cycle in a hierarchy with a root: %v", got)
 	}
+	cache.AddOrUpdateCohort(utiltesting.MakeCohort("c").Parent("b").Obj())
+	if diff := cmp.Diff([]string{"b", "c", "b"}, cache.CohortCycle("a")); diff != "" {
+		t.Errorf("Unexpected cycle (-want,+got):\n%s", diff)
+	}
+	if got := cache.rootCohort("a"); got != "a" {
+		t.Errorf("Unexpected root of a cohort below a cycle, want a, got %s", got)
+	}
+}
 
-	cl := utiltesting.NewFakeClient()
-
-	tests := []struct {
-		name      string
-		setup     func(cache *Cache) error
-		operation func(cache *Cache) error
-		wantReady bool
+func TestCohortHierarchyQuota(t *testing.T) {
+	cases := map[string]struct {
+		cohorts   []*kueuealpha.Cohort
+		workloads []*kueue.Workload
+		// wantAvailable is the quota that dept-a can use, without and
+		// while borrowing.
+		wantAvailable          int64
+		wantAvailableBorrowing int64
+		// wantCacheAvailable is the quota that dept-a can reserve,
+		// including the borrowing caps, as reported in its status.
+		wantCacheAvailable int64
+		wantDeptUsage      int64
+		wantFitsCaps       map[string]bool
+		wantReclaimDelays  map[string]time.Duration
 	}{
-		{
-			name:      "empty cache",
-			operation: func(cache *Cache) error { return nil },
-			wantReady: true,
-		},
-		{
-			name: "add Workload without PodsReady condition",
-			operation: func(cache *Cache) error {
-				wl := utiltesting.MakeWorkload("a", "").ReserveQuota(&kueue.Admission{
-					ClusterQueue: "one",
-				}).Obj()
-				cache.AddOrUpdateWorkload(wl)
-				return nil
+		"quota of the whole hierarchy": {
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("b", "ns").
+					Request(corev1.ResourceCPU, "2").
+					ReserveQuota(utiltesting.MakeAdmission("dept-b").Assignment(corev1.ResourceCPU, "default", "2").Obj()).
+					Obj(),
+				utiltesting.MakeWorkload("c", "ns").
+					Request(corev1.ResourceCPU, "1").
+					ReserveQuota(utiltesting.MakeAdmission("org-c").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+					Obj(),
 			},
-			wantReady: false,
+			wantAvailable:          3_000,
+			wantAvailableBorrowing: 3_000,
+			wantCacheAvailable:     3_000,
+			wantDeptUsage:          2_000,
+			wantFitsCaps:           map[string]bool{"dept-a": true, "org-c": true},
+			wantReclaimDelays:      map[string]time.Duration{"dept-b": 0, "org-c": 0},
 		},
-		{
-			name: "add Workload with PodsReady=False",
-			operation: func(cache *Cache) error {
-				wl := utiltesting.MakeWorkload("a", "").ReserveQuota(&kueue.Admission{
-					ClusterQueue: "one",
-				}).Condition(metav1.Condition{
-					Type:   kueue.WorkloadPodsReady,
-					Status: metav1.ConditionFalse,
-				}).Obj()
-				cache.AddOrUpdateWorkload(wl)
-				return nil
+		"borrowing safety margin of the child cohort": {
+			cohorts: []*kueuealpha.Cohort{
+				utiltesting.MakeCohort("dept").Parent("org").BorrowingSafetyMarginPercent(50).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("b", "ns").
+					Request(corev1.ResourceCPU, "1").
+					ReserveQuota(utiltesting.MakeAdmission("dept-b").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+					Obj(),
 			},
-			wantReady: false,
+			// 1 of the 3 unused in dept is outside of its safety margin of 2,
+			// and 2 are unused outside of dept.
+			wantAvailable:          5_000,
+			wantAvailableBorrowing: 3_000,
+			wantCacheAvailable:     3_000,
+			wantDeptUsage:          1_000,
+			wantFitsCaps:           map[string]bool{"dept-a": true, "org-c": true},
+			wantReclaimDelays:      map[string]time.Duration{"dept-b": 0, "org-c": 0},
 		},
-		{
-			name: "add Workload with PodsReady=True",
-			operation: func(cache *Cache) error {
-				wl := utiltesting.MakeWorkload("a", "").ReserveQuota(&kueue.Admission{
-					ClusterQueue: "one",
-				}).Condition(metav1.Condition{
-					Type:   kueue.WorkloadPodsReady,
-					Status: metav1.ConditionTrue,
-				}).Obj()
-				cache.AddOrUpdateWorkload(wl)
-				return nil
+		"borrowing safety margin of the root": {
+			cohorts: []*kueuealpha.Cohort{
+				utiltesting.MakeCohort("org").BorrowingSafetyMarginPercent(50).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("b", "ns").
+					Request(corev1.ResourceCPU, "1").
+					ReserveQuota(utiltesting.MakeAdmission("dept-b").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+					Obj(),
 			},
-			wantReady: true,
+			wantAvailable:          5_000,
+			wantAvailableBorrowing: 2_000,
+			wantCacheAvailable:     2_000,
+			wantDeptUsage:          1_000,
+			wantFitsCaps:           map[string]bool{"dept-a": true, "org-c": true},
+			wantReclaimDelays:      map[string]time.Duration{"dept-b": 0, "org-c": 0},
+		},
+		"child cohort borrowing from its parent": {
+			cohorts: []*kueuealpha.Cohort{
+				utiltesting.MakeCohort("dept").Parent("org").BorrowingSafetyMarginPercent(50).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("b", "ns").
+					Request(corev1.ResourceCPU, "5").
+					ReserveQuota(utiltesting.MakeAdmission("dept-b").Assignment(corev1.ResourceCPU, "default", "5").Obj()).
+					Obj(),
+			},
+			// The quota of dept is in use, only the unused quota of org-c is
+			// left.
+			wantAvailable:          1_000,
+			wantAvailableBorrowing: 1_000,
+			wantCacheAvailable:     1_000,
+			wantDeptUsage:          5_000,
+			wantFitsCaps:           map[string]bool{"dept-a": true, "org-c": true},
+			wantReclaimDelays:      map[string]time.Duration{"dept-b": 0, "org-c": 0},
+		},
+		"borrowing cap and reclaim delay of the child cohort": {
+			cohorts: []*kueuealpha.Cohort{
+				utiltesting.MakeCohort("org").ReclaimDelay(time.Second).Obj(),
+				utiltesting.MakeCohort("dept").
+					Parent("org").
+					BorrowingCap("default", corev1.ResourceCPU, "1").
+					ReclaimDelay(time.Minute).
+					Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("b", "ns").
+					Request(corev1.ResourceCPU, "3").
+					ReserveQuota(utiltesting.MakeAdmission("dept-b").Assignment(corev1.ResourceCPU, "default", "3").Obj()).
+					Obj(),
+			},
+			// dept-b borrows 1 and dept-a would borrow 1 more, above the cap,
+			// so dept-a can only reserve its nominal quota. The cap doesn't
+			// limit the ClusterQueues outside of dept.
+			wantAvailable:          3_000,
+			wantAvailableBorrowing: 3_000,
+			wantCacheAvailable:     2_000,
+			wantDeptUsage:          3_000,
+			wantFitsCaps:           map[string]bool{"dept-a": false, "org-c": true},
+			wantReclaimDelays:      map[string]time.Duration{"dept-b": time.Minute, "org-c": time.Second},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cache := New(utiltesting.NewFakeClient())
+			cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+			cache.AddOrUpdateCohort(utiltesting.MakeCohort("dept").Parent("org").Obj())
+			for _, cohort := range tc.cohorts {
+				cache.AddOrUpdateCohort(cohort)
+			}
+			for name, cohort := range map[string]string{"dept-a": "dept", "dept-b": "dept", "org-c": "org"} {
+				cq := utiltesting.MakeClusterQueue(name).
+					Cohort(cohort).
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj()
+				if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+					t.Fatalf("Failed to add clusterQueue %s: %v", cq.Name, err)
+				}
+			}
+			for _, wl := range tc.workloads {
+				if !cache.AddOrUpdateWorkload(wl) {
+					t.Fatalf("Failed to add workload %s", wl.Name)
+				}
+			}
+
+			snapshot := cache.Snapshot()
+			cq := snapshot.ClusterQueues["dept-a"]
+			if got := cq.CohortAvailable("default", corev1.ResourceCPU, false); got != tc.wantAvailable {
+				t.Errorf("Unexpected available quota, want %d, got %d", tc.wantAvailable, got)
+			}
+			if got := cq.CohortAvailable("default", corev1.ResourceCPU, true); got != tc.wantAvailableBorrowing {
+				t.Errorf("Unexpected available quota while borrowing, want %d, got %d", tc.wantAvailableBorrowing, got)
+			}
+			if got := cq.leafCohort.Usage["default"][corev1.ResourceCPU]; got != tc.wantDeptUsage {
+				t.Errorf("Unexpected usage of the child cohort, want %d, got %d", tc.wantDeptUsage, got)
+			}
+			if got := cache.clusterQueues["dept-a"].availableQuota()["default"][corev1.ResourceCPU]; got != tc.wantCacheAvailable {
+				t.Errorf("Unexpected available quota in the cache, want %d, got %d", tc.wantCacheAvailable, got)
+			}
+			for name, want := range tc.wantFitsCaps {
+				borrowing := resources.FlavorResourceQuantities{"default": {corev1.ResourceCPU: 1_000}}
+				if got := snapshot.ClusterQueues[name].FitInCohortBorrowingCaps(borrowing); got != want {
+					t.Errorf("Unexpected fit in the borrowing caps for %s, want %t, got %t", name, want, got)
+				}
+			}
+			for name, want := range tc.wantReclaimDelays {
+				if got := cq.ReclaimDelay(snapshot.ClusterQueues[name]); got != want {
+					t.Errorf("Unexpected reclaim delay for %s, want %v, got %v", name, want, got)
+				}
+			}
+		})
+	}
+}
+
+func cohortLevels(cache *Cache) map[string]sets.Set[string] {
+	levels := make(map[string]sets.Set[string], len(cache.cohorts))
+	for name, cohort := range cache.cohorts {
+		levels[name] = cohortMemberNames(cohort)
+	}
+	return levels
+}
+
+func cohortMemberNames(cohort *Cohort) sets.Set[string] {
+	names := sets.New[string]()
+	for cq := range cohort.Members {
+		names.Insert(cq.Name)
+	}
+	return names
+}
+
+func TestDeleteWorkloadReleasesCohortUsage(t *testing.T) {
+	cache := New(utiltesting.NewFakeClient())
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	for _, name := range []string{"a", "b"} {
+		cq := utiltesting.MakeClusterQueue(name).
+			Cohort("cohort").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "2").Obj()).
+			Obj()
+		if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+			t.Fatalf("Failed to add clusterQueue %s: %v", cq.Name, err)
+		}
+	}
+	borrowing := utiltesting.MakeWorkload("borrowing", "ns").
+		Request(corev1.ResourceCPU, "3").
+		ReserveQuota(utiltesting.MakeAdmission("a").Assignment(corev1.ResourceCPU, "default", "3").Obj()).
+		Obj()
+	if !cache.AddOrUpdateWorkload(borrowing) {
+		t.Fatal("Failed to add workload")
+	}
+	if borrowed := cache.Snapshot().ClusterQueues["b"].Cohort.Borrowed("default", corev1.ResourceCPU); borrowed != 1_000 {
+		t.Errorf("Unexpected borrowed quota in the cohort, got %d, want 1000", borrowed)
+	}
+	generation := cache.Snapshot().ClusterQueues["b"].Cohort.AllocatableResourceGeneration
+
+	// The second delete replays the event, as it happens when the delete
+	// state is unknown.
+	for i := 0; i < 2; i++ {
+		if err := cache.DeleteWorkload(borrowing); err != nil {
+			t.Fatalf("Failed to delete workload: %v", err)
+		}
+		snapshot := cache.Snapshot()
+		cohort := snapshot.ClusterQueues["b"].Cohort
+		if diff := cmp.Diff(resources.FlavorResourceQuantities{"default": {corev1.ResourceCPU: 0}}, cohort.Usage); diff != "" {
+			t.Errorf("Unexpected cohort usage after deleting the workload (-want,+got):\n%s", diff)
+		}
+		if borrowed := cohort.Borrowed("default", corev1.ResourceCPU); borrowed != 0 {
+			t.Errorf("Unexpected borrowed quota in the cohort after deleting the workload: %d", borrowed)
+		}
+		if cohort.AllocatableResourceGeneration != generation+1 {
+			t.Errorf("Unexpected cohort generation after deleting the workload, got %d, want %d", cohort.AllocatableResourceGeneration, generation+1)
+		}
+	}
+}
+
+func TestClusterQueueInactiveGracePeriod(t *testing.T) {
+	const gracePeriod = time.Minute
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	rf := utiltesting.MakeResourceFlavor("default").Obj()
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "1").Obj()).
+		Obj()
+
+	cache := New(utiltesting.NewFakeClient(), WithInactiveGracePeriod(gracePeriod))
+	cache.clock = fakeClock
+	cache.AddOrUpdateResourceFlavor(rf)
+	if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+		t.Fatalf("Failed to add clusterQueue: %v", err)
+	}
+	if !cache.ClusterQueueActive("cq") {
+		t.Fatal("ClusterQueue should be active")
+	}
+
+	// The flavor is briefly missing.
+	cache.DeleteResourceFlavor(rf)
+	if remaining := cache.RecheckClusterQueueStatus("cq"); remaining != gracePeriod {
+		t.Errorf("Unexpected remaining grace period, got %v, want %v", remaining, gracePeriod)
+	}
+	fakeClock.Step(gracePeriod / 2)
+	if remaining := cache.RecheckClusterQueueStatus("cq"); remaining != gracePeriod/2 {
+		t.Errorf("Unexpected remaining grace period, got %v, want %v", remaining, gracePeriod/2)
+	}
+	if !cache.ClusterQueueActive("cq") {
+		t.Error("ClusterQueue should remain active within the grace period")
+	}
+	cache.AddOrUpdateResourceFlavor(rf)
+	fakeClock.Step(gracePeriod)
+	if remaining := cache.RecheckClusterQueueStatus("cq"); remaining != 0 {
+		t.Errorf("Unexpected remaining grace period after the flavor was recreated: %v", remaining)
+	}
+	if !cache.ClusterQueueActive("cq") {
+		t.Error("ClusterQueue should be active after the flavor was recreated")
+	}
+
+	// The flavor is missing for longer than the grace period.
+	cache.DeleteResourceFlavor(rf)
+	fakeClock.Step(gracePeriod)
+	if remaining := cache.RecheckClusterQueueStatus("cq"); remaining != 0 {
+		t.Errorf("Unexpected remaining grace period after it elapsed: %v", remaining)
+	}
+	if cache.ClusterQueueActive("cq") {
+		t.Error("ClusterQueue should be inactive after the grace period elapsed")
+	}
+	if _, reason, _ := cache.ClusterQueueReadiness("cq"); reason != "FlavorNotFound" {
+		t.Errorf("Unexpected inactive reason %q, want FlavorNotFound", reason)
+	}
+	if cqs := cache.AddOrUpdateResourceFlavor(rf); !cqs.Has("cq") {
+		t.Error("ClusterQueue should become active after the flavor was recreated")
+	}
+}
+
+func TestMatchingClusterQueues(t *testing.T) {
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("matching1").
+			NamespaceSelector(&metav1.LabelSelector{}).Obj(),
+		utiltesting.MakeClusterQueue("not-matching").
+			NamespaceSelector(nil).Obj(),
+		utiltesting.MakeClusterQueue("matching2").
+			NamespaceSelector(&metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      "dep",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"eng"},
+					},
+				},
+			}).Obj(),
+	}
+	wantCQs := sets.New("matching1", "matching2")
+
+	cache := New(utiltesting.NewFakeClient())
+	for _, cq := range clusterQueues {
+		if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+			t.Errorf("failed to add clusterQueue %s", cq.Name)
+		}
+	}
+
+	gotCQs := cache.MatchingClusterQueues(map[string]string{"dep": "eng"})
+	if diff := cmp.Diff(wantCQs, gotCQs); diff != "" {
+		t.Errorf("Wrong ClusterQueues (-want,+got):\n%s", diff)
+	}
+}
+
+// TestWaitForPodsReadyCancelled ensures that the WaitForPodsReady call does not block when the context is closed.
+func TestWaitForPodsReadyCancelled(t *testing.T) {
+	cache := New(utiltesting.NewFakeClient(), WithPodsReadyTracking(true))
+	ctx, cancel := context.WithCancel(context.Background())
+	log := ctrl.LoggerFrom(ctx)
+
+	go cache.CleanUpOnContext(ctx)
+
+	cq := kueue.ClusterQueue{
+		ObjectMeta: metav1.ObjectMeta{Name: "one"},
+	}
+	if err := cache.AddClusterQueue(ctx, &cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+
+	wl := utiltesting.MakeWorkload("a", "").ReserveQuota(&kueue.Admission{
+		ClusterQueue: "one",
+	}).Obj()
+	if err := cache.AssumeWorkload(wl); err != nil {
+		t.Fatalf("Failed assuming the workload to block the further admission: %v", err)
+	}
+
+	if cache.PodsReadyForAllAdmittedWorkloads(log) {
+		t.Fatalf("Unexpected that all admitted workloads are in PodsReady condition")
+	}
+
+	// cancel the context so that the WaitForPodsReady is returns
+	go cancel()
+
+	cache.WaitForPodsReady(ctx)
+}
+
+// TestCachePodsReadyForAllAdmittedWorkloads verifies the condition used to determine whether to wait
+func TestCachePodsReadyForAllAdmittedWorkloads(t *testing.T) {
+	clusterQueues := []kueue.ClusterQueue{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "one"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "two"},
+		},
+	}
+
+	cl := utiltesting.NewFakeClient()
+
+	tests := []struct {
+		name      string
+		setup     func(cache *Cache) error
+		operation func(cache *Cache) error
+		wantReady bool
+	}{
+		{
+			name:      "empty cache",
+			operation: func(cache *Cache) error { return nil },
+			wantReady: true,
+		},
+		{
+			name: "add Workload without PodsReady condition",
+			operation: func(cache *Cache) error {
+				wl := utiltesting.MakeWorkload("a", "").ReserveQuota(&kueue.Admission{
+					ClusterQueue: "one",
+				}).Obj()
+				cache.AddOrUpdateWorkload(wl)
+				return nil
+			},
+			wantReady: false,
+		},
+		{
+			name: "add Workload with PodsReady=False",
+			operation: func(cache *Cache) error {
+				wl := utiltesting.MakeWorkload("a", "").ReserveQuota(&kueue.Admission{
+					ClusterQueue: "one",
+				}).Condition(metav1.Condition{
+					Type:   kueue.WorkloadPodsReady,
+					Status: metav1.ConditionFalse,
+				}).Obj()
+				cache.AddOrUpdateWorkload(wl)
+				return nil
+			},
+			wantReady: false,
+		},
+		{
+			name: "add Workload with PodsReady=True",
+			operation: func(cache *Cache) error {
+				wl := utiltesting.MakeWorkload("a", "").ReserveQuota(&kueue.Admission{
+					ClusterQueue: "one",
+				}).Condition(metav1.Condition{
+					Type:   kueue.WorkloadPodsReady,
+					Status: metav1.ConditionTrue,
+				}).Obj()
+				cache.AddOrUpdateWorkload(wl)
+				return nil
+			},
+			wantReady: true,
 		},
 		{
 			name: "assume Workload without PodsReady condition",
@@ -3421,13 +4080,20 @@ func TestClusterQueueReadiness(t *testing.T) {
 			wantMessage:      "Can admit new workloads",
 			wantActive:       true,
 		},
-		"stopped": {
+		"stopped with HoldAndDrain": {
 			clusterQueues:    []*kueue.ClusterQueue{utiltesting.MakeClusterQueue("queue1").StopPolicy(kueue.HoldAndDrain).Obj()},
 			clusterQueueName: "queue1",
 			wantStatus:       metav1.ConditionFalse,
 			wantReason:       "Stopped",
 			wantMessage:      "Can't admit new workloads: Stopped",
 		},
+		"stopped with Hold": {
+			clusterQueues:    []*kueue.ClusterQueue{utiltesting.MakeClusterQueue("queue1").StopPolicy(kueue.Hold).Obj()},
+			clusterQueueName: "queue1",
+			wantStatus:       metav1.ConditionFalse,
+			wantReason:       "Stopped",
+			wantMessage:      "Can't admit new workloads: Stopped",
+		},
 	}
 
 	for name, tc := range cases {
@@ -3471,3 +4137,822 @@ func TestClusterQueueReadiness(t *testing.T) {
 		})
 	}
 }
+
+func TestCohortGraph(t *testing.T) {
+	cache := New(utiltesting.NewFakeClient())
+	cache.AddOrUpdateCohort(&kueuealpha.Cohort{ObjectMeta: metav1.ObjectMeta{Name: "research"}})
+	cache.AddOrUpdateCohort(utiltesting.MakeCohort("sales").Parent("org").Obj())
+	cqs := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("eng-b").Cohort("eng").Obj(),
+		utiltesting.MakeClusterQueue("eng-a").Cohort("eng").Obj(),
+		utiltesting.MakeClusterQueue("research-a").Cohort("research").Obj(),
+		utiltesting.MakeClusterQueue("standalone").Obj(),
+	}
+	for _, cq := range cqs {
+		if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+			t.Fatalf("Failed to add clusterQueue %s: %v", cq.Name, err)
+		}
+	}
+	want := CohortGraph{
+		Cohorts: []CohortGraphNode{
+			{Name: "eng", ClusterQueues: []string{"eng-a", "eng-b"}},
+			{Name: "org", ClusterQueues: []string{}},
+			{Name: "research", ClusterQueues: []string{"research-a"}},
+			{Name: "sales", Parent: "org", ClusterQueues: []string{}},
+		},
+	}
+	if diff := cmp.Diff(want, cache.CohortGraph()); diff != "" {
+		t.Errorf("Unexpected cohort graph (-want,+got):\n%s", diff)
+	}
+
+	cache.DeleteClusterQueue(cqs[2])
+	cache.DeleteCohort(&kueuealpha.Cohort{ObjectMeta: metav1.ObjectMeta{Name: "research"}})
+	want = CohortGraph{
+		Cohorts: []CohortGraphNode{
+			{Name: "eng", ClusterQueues: []string{"eng-a", "eng-b"}},
+			{Name: "org", ClusterQueues: []string{}},
+			{Name: "sales", Parent: "org", ClusterQueues: []string{}},
+		},
+	}
+	if diff := cmp.Diff(want, cache.CohortGraph()); diff != "" {
+		t.Errorf("Unexpected cohort graph after deletions (-want,+got):\n%s", diff)
+	}
+}
+
+func TestCohortPeers(t *testing.T) {
+	cache := New(utiltesting.NewFakeClient())
+	cache.AddOrUpdateCohort(utiltesting.MakeCohort("eng").Parent("org").Obj())
+	cqs := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("eng-a").Cohort("eng").Obj(),
+		utiltesting.MakeClusterQueue("eng-b").Cohort("eng").Obj(),
+		utiltesting.MakeClusterQueue("org-a").Cohort("org").Obj(),
+		utiltesting.MakeClusterQueue("research-a").Cohort("research").Obj(),
+		utiltesting.MakeClusterQueue("standalone").Obj(),
+	}
+	for _, cq := range cqs {
+		if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+			t.Fatalf("Failed to add clusterQueue %s: %v", cq.Name, err)
+		}
+	}
+	cases := map[string][]string{
+		"eng-a":      {"eng-b", "org-a"},
+		"org-a":      {"eng-a", "eng-b"},
+		"research-a": {},
+		"standalone": nil,
+		"missing":    nil,
+	}
+	for name, want := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cache.CohortPeers(name)
+			if diff := cmp.Diff(want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("Unexpected peers (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeadAdmissionEstimate(t *testing.T) {
+	start := time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC)
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "3").Obj()).
+		Obj()
+	runningWorkload := func(i int) *kueue.Workload {
+		return utiltesting.MakeWorkload(fmt.Sprintf("running-%d", i), "ns").
+			Request(corev1.ResourceCPU, "1").
+			ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+			Obj()
+	}
+	cases := map[string]struct {
+		running int
+		// releases is the number of running workloads that finish, one per
+		// minute, each replaced by a new running workload.
+		releases         int
+		sinceLastRelease time.Duration
+		head             *kueue.Workload
+		want             *kueue.HeadAdmissionEstimate
+	}{
+		"head fits": {
+			running:          2,
+			sinceLastRelease: time.Minute,
+			head:             utiltesting.MakeWorkload("head", "ns").Creation(start).Request(corev1.ResourceCPU, "1").Obj(),
+			want: &kueue.HeadAdmissionEstimate{
+				Workload:               kueue.ClusterQueuePendingWorkload{Name: "head", Namespace: "ns"},
+				State:                  kueue.AdmissionEstimateKnown,
+				EstimatedAdmissionTime: ptr.To(metav1.NewTime(start)),
+			},
+		},
+		"idle saturated queue": {
+			running: 3,
+			head:    utiltesting.MakeWorkload("head", "ns").Request(corev1.ResourceCPU, "2").Obj(),
+			want: &kueue.HeadAdmissionEstimate{
+				Workload: kueue.ClusterQueuePendingWorkload{Name: "head", Namespace: "ns"},
+				State:    kueue.AdmissionEstimateUnknown,
+			},
+		},
+		"steady completions": {
+			running:  3,
+			releases: 3,
+			head:     utiltesting.MakeWorkload("head", "ns").Request(corev1.ResourceCPU, "2").Obj(),
+			want: &kueue.HeadAdmissionEstimate{
+				Workload:               kueue.ClusterQueuePendingWorkload{Name: "head", Namespace: "ns"},
+				State:                  kueue.AdmissionEstimateKnown,
+				EstimatedAdmissionTime: ptr.To(metav1.NewTime(start.Add(4 * time.Minute))),
+			},
+		},
+		"steady completions, estimated later": {
+			running:          3,
+			releases:         3,
+			sinceLastRelease: time.Minute,
+			head:             utiltesting.MakeWorkload("head", "ns").Request(corev1.ResourceCPU, "2").Obj(),
+			want: &kueue.HeadAdmissionEstimate{
+				Workload:               kueue.ClusterQueuePendingWorkload{Name: "head", Namespace: "ns"},
+				State:                  kueue.AdmissionEstimateKnown,
+				EstimatedAdmissionTime: ptr.To(metav1.NewTime(start.Add(4 * time.Minute))),
+			},
+		},
+		"steady completions, head created after the last release": {
+			running:  3,
+			releases: 3,
+			head:     utiltesting.MakeWorkload("head", "ns").Creation(start.Add(3*time.Minute)).Request(corev1.ResourceCPU, "2").Obj(),
+			want: &kueue.HeadAdmissionEstimate{
+				Workload:               kueue.ClusterQueuePendingWorkload{Name: "head", Namespace: "ns"},
+				State:                  kueue.AdmissionEstimateKnown,
+				EstimatedAdmissionTime: ptr.To(metav1.NewTime(start.Add(5 * time.Minute))),
+			},
+		},
+		"not enough completions": {
+			running:  3,
+			releases: 2,
+			head:     utiltesting.MakeWorkload("head", "ns").Request(corev1.ResourceCPU, "2").Obj(),
+			want: &kueue.HeadAdmissionEstimate{
+				Workload: kueue.ClusterQueuePendingWorkload{Name: "head", Namespace: "ns"},
+				State:    kueue.AdmissionEstimateUnknown,
+			},
+		},
+		"completions outside of the window": {
+			running:          3,
+			releases:         3,
+			sinceLastRelease: admissionEstimateWindow + time.Minute,
+			head:             utiltesting.MakeWorkload("head", "ns").Request(corev1.ResourceCPU, "2").Obj(),
+			want: &kueue.HeadAdmissionEstimate{
+				Workload: kueue.ClusterQueuePendingWorkload{Name: "head", Namespace: "ns"},
+				State:    kueue.AdmissionEstimateUnknown,
+			},
+		},
+		"completions don't release the missing resource": {
+			running:  3,
+			releases: 3,
+			head:     utiltesting.MakeWorkload("head", "ns").Request("example.com/gpu", "1").Obj(),
+			want: &kueue.HeadAdmissionEstimate{
+				Workload: kueue.ClusterQueuePendingWorkload{Name: "head", Namespace: "ns"},
+				State:    kueue.AdmissionEstimateUnknown,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer features.SetFeatureGateDuringTest(t, features.HeadAdmissionEstimate, true)()
+			ctx, _ := utiltesting.ContextWithLog(t)
+			fakeClock := testingclock.NewFakeClock(start)
+			cache := New(utiltesting.NewFakeClient())
+			cache.clock = fakeClock
+			cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+			if err := cache.AddClusterQueue(ctx, cq); err != nil {
+				t.Fatalf("Failed to add ClusterQueue: %v", err)
+			}
+			for i := 0; i < tc.running; i++ {
+				cache.AddOrUpdateWorkload(runningWorkload(i))
+			}
+			for i := 0; i < tc.releases; i++ {
+				if i > 0 {
+					fakeClock.Step(time.Minute)
+				}
+				if err := cache.DeleteWorkload(runningWorkload(i)); err != nil {
+					t.Fatalf("Failed to delete workload: %v", err)
+				}
+				cache.AddOrUpdateWorkload(runningWorkload(tc.running + i))
+			}
+			fakeClock.Step(tc.sinceLastRelease)
+			got, err := cache.HeadAdmissionEstimate(cq, workload.NewInfo(tc.head))
+			if err != nil {
+				t.Fatalf("Failed to estimate the admission: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected estimate (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuotaWindows(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	fakeClock := testingclock.NewFakeClock(time.Date(2024, time.March, 1, 21, 0, 0, 0, time.UTC))
+	cache := New(utiltesting.NewFakeClient())
+	cache.clock = fakeClock
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+			Resource(corev1.ResourceCPU, "10").
+			Resource(corev1.ResourceMemory, "10Gi").
+			Obj()).
+		QuotaWindow("night", "0 22 * * *", 8*time.Hour, *utiltesting.MakeFlavorQuotas("default").
+			Resource(corev1.ResourceCPU, "20").
+			Obj()).
+		Obj()
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Label("instance-type", "default").Obj())
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add ClusterQueue: %v", err)
+	}
+	wantNominal := func(resource corev1.ResourceName, want int64) {
+		t.Helper()
+		got := cache.clusterQueues["cq"].ResourceGroups[0].Flavors[0].Resources[resource].Nominal
+		if got != want {
+			t.Errorf("Unexpected nominal quota for %s, want=%d, got=%d", resource, want, got)
+		}
+	}
+
+	wantNominal(corev1.ResourceCPU, 10_000)
+	changed, untilNext := cache.RefreshQuotaWindow("cq")
+	if changed || untilNext != time.Hour {
+		t.Errorf("Unexpected refresh before the window, want changed=false and %v until the next window, got changed=%t and %v", time.Hour, changed, untilNext)
+	}
+
+	fakeClock.Step(time.Hour)
+	changed, untilNext = cache.RefreshQuotaWindow("cq")
+	if !changed || untilNext != 8*time.Hour {
+		t.Errorf("Unexpected refresh at the start of the window, want changed=true and %v until the next window, got changed=%t and %v", 8*time.Hour, changed, untilNext)
+	}
+	wantNominal(corev1.ResourceCPU, 20_000)
+	wantNominal(corev1.ResourceMemory, 10*1024*1024*1024)
+	if got := cache.clusterQueues["cq"].ResourceGroups[0].LabelKeys; !got.Has("instance-type") {
+		t.Error("The label keys of the resource group should be kept after applying the window")
+	}
+
+	fakeClock.Step(8 * time.Hour)
+	if changed, _ = cache.RefreshQuotaWindow("cq"); !changed {
+		t.Error("The quotas should change at the end of the window")
+	}
+	wantNominal(corev1.ResourceCPU, 10_000)
+}
+
+func TestLocalQueueMaxConcurrencyReached(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cache := New(utiltesting.NewFakeClient())
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add ClusterQueue: %v", err)
+	}
+	lq := utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").MaxConcurrency(1).Obj()
+	if err := cache.AddLocalQueue(lq); err != nil {
+		t.Fatalf("Failed to add LocalQueue: %v", err)
+	}
+	pending := workload.NewInfo(utiltesting.MakeWorkload("pending", "ns").Queue("lq").Request(corev1.ResourceCPU, "1").Obj())
+	pending.ClusterQueue = "cq"
+	if cache.LocalQueueMaxConcurrencyReached(pending) {
+		t.Error("The maxConcurrency of an empty LocalQueue shouldn't be reached")
+	}
+
+	cache.AddOrUpdateWorkload(utiltesting.MakeWorkload("running", "ns").
+		Queue("lq").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+		Obj())
+	if !cache.LocalQueueMaxConcurrencyReached(pending) {
+		t.Error("The maxConcurrency of the LocalQueue should be reached")
+	}
+
+	updatedLq := utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").MaxConcurrency(2).Obj()
+	if err := cache.UpdateLocalQueue(lq, updatedLq); err != nil {
+		t.Fatalf("Failed to update LocalQueue: %v", err)
+	}
+	if cache.LocalQueueMaxConcurrencyReached(pending) {
+		t.Error("The maxConcurrency of the LocalQueue shouldn't be reached after increasing it")
+	}
+}
+
+func TestLocalQueueWithMultipleClusterQueues(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cache := New(utiltesting.NewFakeClient())
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	for _, name := range []string{"cq-a", "cq-b"} {
+		cq := utiltesting.MakeClusterQueue(name).
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+			Obj()
+		if err := cache.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Failed to add ClusterQueue %s: %v", name, err)
+		}
+	}
+	lq := utiltesting.MakeLocalQueue("lq", "ns").
+		ClusterQueue("cq-a").
+		ClusterQueueSelection(kueue.ClusterQueueSelectionFirstWithCapacity, "cq-b").
+		MaxConcurrency(2).
+		Obj()
+	if err := cache.AddLocalQueue(lq); err != nil {
+		t.Fatalf("Failed to add LocalQueue: %v", err)
+	}
+	for cqName, cpu := range map[string]string{"cq-a": "1", "cq-b": "2"} {
+		cache.AddOrUpdateWorkload(utiltesting.MakeWorkload("wl-"+cqName, "ns").
+			Queue("lq").
+			Request(corev1.ResourceCPU, cpu).
+			ReserveQuota(utiltesting.MakeAdmission(cqName).Assignment(corev1.ResourceCPU, "default", cpu).Obj()).
+			Obj())
+	}
+
+	pending := workload.NewInfo(utiltesting.MakeWorkload("pending", "ns").Queue("lq").Request(corev1.ResourceCPU, "1").Obj())
+	pending.ClusterQueue = "cq-a"
+	if !cache.LocalQueueMaxConcurrencyReached(pending) {
+		t.Error("The maxConcurrency of the LocalQueue should be reached by the workloads in both ClusterQueues")
+	}
+
+	stats, err := cache.LocalQueueUsage(lq)
+	if err != nil {
+		t.Fatalf("Failed to get the LocalQueue usage: %v", err)
+	}
+	wantReserved := []kueue.LocalQueueFlavorUsage{{
+		Name: "default",
+		Resources: []kueue.LocalQueueResourceUsage{{
+			Name:  corev1.ResourceCPU,
+			Total: resource.MustParse("3"),
+		}},
+	}}
+	if diff := cmp.Diff(wantReserved, stats.ReservedResources); diff != "" {
+		t.Errorf("Unexpected reserved resources of the LocalQueue (-want,+got):\n%s", diff)
+	}
+	if stats.ReservingWorkloads != 2 {
+		t.Errorf("Unexpected reserving workloads of the LocalQueue, want 2, got %d", stats.ReservingWorkloads)
+	}
+
+	// Dropping a candidate ClusterQueue stops counting its workloads.
+	updatedLq := utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq-a").MaxConcurrency(2).Obj()
+	if err := cache.UpdateLocalQueue(lq, updatedLq); err != nil {
+		t.Fatalf("Failed to update LocalQueue: %v", err)
+	}
+	if cache.LocalQueueMaxConcurrencyReached(pending) {
+		t.Error("The maxConcurrency of the LocalQueue shouldn't be reached after removing a candidate ClusterQueue")
+	}
+}
+
+func TestAdmittedButUnschedulableWorkloads(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cache := New(utiltesting.NewFakeClient())
+	cq := utiltesting.MakeClusterQueue("cq-unschedulable").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add ClusterQueue: %v", err)
+	}
+	wantMetric := func(want int) {
+		t.Helper()
+		got, err := testutil.GetGaugeMetricValue(metrics.AdmittedButUnschedulableWorkloads.WithLabelValues("cq-unschedulable"))
+		if err != nil {
+			t.Fatalf("Failed to get the metric: %v", err)
+		}
+		if int(got) != want {
+			t.Errorf("Unexpected admitted_but_unschedulable metric, want %d, got %v", want, got)
+		}
+	}
+
+	admitted := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(utiltesting.MakeAdmission("cq-unschedulable").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+		Admitted(true).
+		Obj()
+	cache.AddOrUpdateWorkload(admitted)
+	wantMetric(0)
+
+	unschedulable := admitted.DeepCopy()
+	apimeta.SetStatusCondition(&unschedulable.Status.Conditions, metav1.Condition{
+		Type:   kueue.WorkloadAdmittedButUnschedulable,
+		Status: metav1.ConditionTrue,
+		Reason: "PodsUnschedulable",
+	})
+	if err := cache.UpdateWorkload(admitted, unschedulable); err != nil {
+		t.Fatalf("Failed to update the workload: %v", err)
+	}
+	wantMetric(1)
+
+	if err := cache.DeleteWorkload(unschedulable); err != nil {
+		t.Fatalf("Failed to delete the workload: %v", err)
+	}
+	wantMetric(0)
+}
+
+func TestEvictingWorkloadsMetric(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cache := New(utiltesting.NewFakeClient())
+	cq := utiltesting.MakeClusterQueue("cq-evicting").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add ClusterQueue: %v", err)
+	}
+	wantMetric := func(want int) {
+		t.Helper()
+		got, err := testutil.GetGaugeMetricValue(metrics.EvictingWorkloads.WithLabelValues("cq-evicting"))
+		if err != nil {
+			t.Fatalf("Failed to get the metric: %v", err)
+		}
+		if int(got) != want {
+			t.Errorf("Unexpected evicting_workloads metric, want %d, got %v", want, got)
+		}
+	}
+
+	admitted := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(utiltesting.MakeAdmission("cq-evicting").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+		Admitted(true).
+		Obj()
+	cache.AddOrUpdateWorkload(admitted)
+	wantMetric(0)
+
+	evicted := admitted.DeepCopy()
+	apimeta.SetStatusCondition(&evicted.Status.Conditions, metav1.Condition{
+		Type:   kueue.WorkloadEvicted,
+		Status: metav1.ConditionTrue,
+		Reason: kueue.WorkloadEvictedByPreemption,
+	})
+	if err := cache.UpdateWorkload(admitted, evicted); err != nil {
+		t.Fatalf("Failed to update the workload: %v", err)
+	}
+	wantMetric(1)
+	stats, err := cache.Usage(cq)
+	if err != nil {
+		t.Fatalf("Failed to get the usage: %v", err)
+	}
+	if stats.ReservingWorkloads != 1 {
+		t.Errorf("The evicted workload should keep reserving quota until its pods terminate, got %d reserving workloads", stats.ReservingWorkloads)
+	}
+
+	if err := cache.DeleteWorkload(evicted); err != nil {
+		t.Fatalf("Failed to delete the workload: %v", err)
+	}
+	wantMetric(0)
+}
+
+func TestOldestAdmittedWorkloadMetric(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cache := New(utiltesting.NewFakeClient())
+	cq := utiltesting.MakeClusterQueue("cq-oldest").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add ClusterQueue: %v", err)
+	}
+	now := time.Now().Truncate(time.Second)
+	admittedAt := func(name string, admissionTime time.Time) *kueue.Workload {
+		return utiltesting.MakeWorkload(name, "ns").
+			Request(corev1.ResourceCPU, "1").
+			ReserveQuota(utiltesting.MakeAdmission("cq-oldest").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+			Condition(metav1.Condition{
+				Type:               kueue.WorkloadAdmitted,
+				Status:             metav1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(admissionTime),
+				Reason:             "ByTest",
+			}).
+			Obj()
+	}
+	wantMetric := func(want time.Time) {
+		t.Helper()
+		got, err := testutil.GetGaugeMetricValue(metrics.OldestAdmittedWorkloadTimestamp.WithLabelValues("cq-oldest"))
+		if err != nil {
+			t.Fatalf("Failed to get the metric: %v", err)
+		}
+		if int64(got) != want.Unix() {
+			t.Errorf("Unexpected oldest admitted workload timestamp, want %d, got %v", want.Unix(), got)
+		}
+	}
+
+	recent := admittedAt("recent", now.Add(-time.Minute))
+	cache.AddOrUpdateWorkload(recent)
+	wantMetric(now.Add(-time.Minute))
+
+	old := admittedAt("old", now.Add(-48*time.Hour))
+	cache.AddOrUpdateWorkload(old)
+	wantMetric(now.Add(-48 * time.Hour))
+
+	// Workloads that only reserve quota are not admitted yet.
+	cache.AddOrUpdateWorkload(utiltesting.MakeWorkload("reserving", "ns").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(utiltesting.MakeAdmission("cq-oldest").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+		Obj())
+	wantMetric(now.Add(-48 * time.Hour))
+
+	if err := cache.DeleteWorkload(old); err != nil {
+		t.Fatalf("Failed to delete the workload: %v", err)
+	}
+	wantMetric(now.Add(-time.Minute))
+}
+
+func TestClusterQueueCapacity(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cache := New(utiltesting.NewFakeClient())
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add ClusterQueue: %v", err)
+	}
+	cache.AddOrUpdateWorkload(utiltesting.MakeWorkload("admitted", "ns").
+		Request(corev1.ResourceCPU, "6").
+		ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "6").Obj()).
+		Obj())
+
+	cases := map[string]struct {
+		wl   *kueue.Workload
+		want bool
+	}{
+		"fits in the unused quota": {
+			wl:   utiltesting.MakeWorkload("fits", "ns").Request(corev1.ResourceCPU, "4").Obj(),
+			want: true,
+		},
+		"doesn't fit in the unused quota": {
+			wl: utiltesting.MakeWorkload("too-big", "ns").Request(corev1.ResourceCPU, "5").Obj(),
+		},
+		"requests a resource without quota": {
+			wl: utiltesting.MakeWorkload("gpu", "ns").Request("example.com/gpu", "1").Obj(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := cache.ClusterQueueHasCapacity("cq", workload.NewInfo(tc.wl)); got != tc.want {
+				t.Errorf("Unexpected ClusterQueueHasCapacity, want %v, got %v", tc.want, got)
+			}
+		})
+	}
+	if got := cache.ClusterQueueLoad("cq"); got != 0.6 {
+		t.Errorf("Unexpected ClusterQueueLoad, want 0.6, got %v", got)
+	}
+}
+
+func TestUsageTrends(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	start := time.Now().Truncate(time.Second)
+	fakeClock := testingclock.NewFakeClock(start)
+	cache := New(utiltesting.NewFakeClient())
+	cache.clock = fakeClock
+	cq := utiltesting.MakeClusterQueue("cq-trend").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add ClusterQueue: %v", err)
+	}
+	wantMetric := func(want float64) {
+		t.Helper()
+		var got float64
+		for _, trend := range cache.UsageTrends() {
+			if trend.ClusterQueue == "cq-trend" && trend.Flavor == "default" && trend.Resource == string(corev1.ResourceCPU) {
+				got = trend.Value
+			}
+		}
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("Unexpected usage trend, want %v, got %v", want, got)
+		}
+	}
+	wantMetric(0)
+
+	var wls []*kueue.Workload
+	for i := range 3 {
+		fakeClock.Step(time.Minute)
+		wl := utiltesting.MakeWorkload(fmt.Sprintf("wl-%d", i), "ns").
+			Request(corev1.ResourceCPU, "1").
+			ReserveQuota(utiltesting.MakeAdmission("cq-trend").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+			Obj()
+		cache.AddOrUpdateWorkload(wl)
+		wls = append(wls, wl)
+	}
+	// 3 CPUs were admitted within the last 5 minutes.
+	wantMetric(3.0 / 300)
+
+	fakeClock.Step(10 * time.Minute)
+	for i, wl := range wls[:2] {
+		fakeClock.Step(time.Minute)
+		if err := cache.DeleteWorkload(wl); err != nil {
+			t.Fatalf("Failed to delete the workload: %v", err)
+		}
+		// The usage at the start of the window was 3 CPUs.
+		wantMetric(-float64(i+1) / 300)
+	}
+
+	// The trend decays when the usage stops changing.
+	fakeClock.Step(3 * time.Minute)
+	wantMetric(-2.0 / 300)
+	fakeClock.Step(3 * time.Minute)
+	wantMetric(0)
+}
+
+func TestSyncWorkload(t *testing.T) {
+	admitted := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(utiltesting.MakeAdmission("cq-a").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+		Obj()
+	pending := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "1").
+		Obj()
+	cases := map[string]struct {
+		cached           *kueue.Workload
+		assumed          bool
+		workload         *kueue.Workload
+		wantOutOfSync    bool
+		wantInconsistent metrics.WorkloadCacheInconsistency
+		wantWorkloads    map[string]sets.Set[string]
+	}{
+		"admitted workload in the cache": {
+			cached:        admitted,
+			workload:      admitted,
+			wantWorkloads: map[string]sets.Set[string]{"cq-a": sets.New("ns/wl")},
+		},
+		"pending workload not in the cache": {
+			workload:      pending,
+			wantWorkloads: map[string]sets.Set[string]{},
+		},
+		"admitted workload missing from the cache": {
+			workload:         admitted,
+			wantOutOfSync:    true,
+			wantInconsistent: metrics.WorkloadCacheInconsistencyMissing,
+			wantWorkloads:    map[string]sets.Set[string]{"cq-a": sets.New("ns/wl")},
+		},
+		"pending workload in the cache": {
+			cached:           admitted,
+			workload:         pending,
+			wantOutOfSync:    true,
+			wantInconsistent: metrics.WorkloadCacheInconsistencyUnexpected,
+			wantWorkloads:    map[string]sets.Set[string]{},
+		},
+		"admitted workload in another ClusterQueue": {
+			cached: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "1").
+				ReserveQuota(utiltesting.MakeAdmission("cq-b").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+				Obj(),
+			workload:         admitted,
+			wantOutOfSync:    true,
+			wantInconsistent: metrics.WorkloadCacheInconsistencyUnexpected,
+			wantWorkloads:    map[string]sets.Set[string]{"cq-a": sets.New("ns/wl")},
+		},
+		"assumed workload": {
+			cached:        admitted,
+			assumed:       true,
+			workload:      pending,
+			wantWorkloads: map[string]sets.Set[string]{"cq-a": sets.New("ns/wl")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := utiltesting.ContextWithLog(t)
+			cache := New(utiltesting.NewFakeClient())
+			cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+			for _, name := range []string{"cq-a", "cq-b"} {
+				cq := utiltesting.MakeClusterQueue(name).
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+					Obj()
+				if err := cache.AddClusterQueue(ctx, cq); err != nil {
+					t.Fatalf("Failed to add ClusterQueue: %v", err)
+				}
+			}
+			if tc.cached != nil {
+				if tc.assumed {
+					if err := cache.AssumeWorkload(tc.cached); err != nil {
+						t.Fatalf("Failed to assume the workload: %v", err)
+					}
+				} else if !cache.AddOrUpdateWorkload(tc.cached) {
+					t.Fatalf("Failed to add the workload")
+				}
+			}
+			var before float64
+			if tc.wantOutOfSync {
+				var err error
+				if before, err = testutil.GetCounterMetricValue(metrics.WorkloadCacheInconsistenciesTotal.WithLabelValues(string(tc.wantInconsistent))); err != nil {
+					t.Fatalf("Failed to get the metric: %v", err)
+				}
+			}
+
+			if got := cache.WorkloadOutOfSync(tc.workload); got != tc.wantOutOfSync {
+				t.Errorf("Unexpected WorkloadOutOfSync, want %t, got %t", tc.wantOutOfSync, got)
+			}
+			if cache.SyncWorkload(tc.workload, func() bool { return false }) {
+				t.Error("SyncWorkload repaired the cache for a workload that is not current")
+			}
+			if got := cache.SyncWorkload(tc.workload, func() bool { return true }); got != tc.wantOutOfSync {
+				t.Errorf("Unexpected SyncWorkload, want %t, got %t", tc.wantOutOfSync, got)
+			}
+			if cache.WorkloadOutOfSync(tc.workload) {
+				t.Error("The workload is still out of sync after syncing it")
+			}
+			gotWorkloads := make(map[string]sets.Set[string])
+			for name, cq := range cache.clusterQueues {
+				for k := range cq.Workloads {
+					if gotWorkloads[name] == nil {
+						gotWorkloads[name] = sets.New[string]()
+					}
+					gotWorkloads[name].Insert(k)
+				}
+			}
+			if diff := cmp.Diff(tc.wantWorkloads, gotWorkloads); diff != "" {
+				t.Errorf("Unexpected workloads in the cache (-want,+got):\n%s", diff)
+			}
+			if tc.wantOutOfSync {
+				got, err := testutil.GetCounterMetricValue(metrics.WorkloadCacheInconsistenciesTotal.WithLabelValues(string(tc.wantInconsistent)))
+				if err != nil {
+					t.Fatalf("Failed to get the metric: %v", err)
+				}
+				if got != before+1 {
+					t.Errorf("Unexpected inconsistencies metric, want %v, got %v", before+1, got)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterQueueSummary(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cache := New(utiltesting.NewFakeClient())
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("spot").Obj())
+	cqs := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("cq").
+			Cohort("all").
+			ResourceGroup(
+				*utiltesting.MakeFlavorQuotas("default").
+					Resource(corev1.ResourceCPU, "10", "5").
+					Resource(corev1.ResourceMemory, "10Gi").
+					Obj(),
+				*utiltesting.MakeFlavorQuotas("spot").
+					Resource(corev1.ResourceCPU, "4").
+					Resource(corev1.ResourceMemory, "4Gi").
+					Obj(),
+			).
+			Obj(),
+		utiltesting.MakeClusterQueue("peer").
+			Cohort("all").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+				Resource(corev1.ResourceCPU, "10").
+				Resource(corev1.ResourceMemory, "10Gi").
+				Obj()).
+			Obj(),
+	}
+	for _, cq := range cqs {
+		if err := cache.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Failed to add ClusterQueue: %v", err)
+		}
+	}
+	cache.AddOrUpdateWorkload(utiltesting.MakeWorkload("borrowing", "ns").
+		Request(corev1.ResourceCPU, "12").
+		Request(corev1.ResourceMemory, "2Gi").
+		ReserveQuota(utiltesting.MakeAdmission("cq").
+			Assignment(corev1.ResourceCPU, "default", "12").
+			Assignment(corev1.ResourceMemory, "default", "2Gi").
+			Obj()).
+		Obj())
+
+	got, err := cache.ClusterQueueSummary("cq")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []ResourceQuotaSummary{
+		{
+			Flavor:     "default",
+			Resource:   corev1.ResourceCPU,
+			Guaranteed: resource.MustParse("10"),
+			Used:       resource.MustParse("12"),
+			Borrowed:   resource.MustParse("2"),
+			Available:  resource.MustParse("3"),
+		},
+		{
+			Flavor:     "default",
+			Resource:   corev1.ResourceMemory,
+			Guaranteed: resource.MustParse("10Gi"),
+			Used:       resource.MustParse("2Gi"),
+			Borrowed:   resource.MustParse("0"),
+			Available:  resource.MustParse("18Gi"),
+		},
+		{
+			Flavor:     "spot",
+			Resource:   corev1.ResourceCPU,
+			Guaranteed: resource.MustParse("4"),
+			Used:       resource.MustParse("0"),
+			Borrowed:   resource.MustParse("0"),
+			Available:  resource.MustParse("4"),
+		},
+		{
+			Flavor:     "spot",
+			Resource:   corev1.ResourceMemory,
+			Guaranteed: resource.MustParse("4Gi"),
+			Used:       resource.MustParse("0"),
+			Borrowed:   resource.MustParse("0"),
+			Available:  resource.MustParse("4Gi"),
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected summary (-want,+got):\n%s", diff)
+	}
+
+	if _, err := cache.ClusterQueueSummary("missing"); !errors.Is(err, ErrCqNotFound) {
+		t.Errorf("Unexpected error for a missing ClusterQueue: %v", err)
+	}
+}
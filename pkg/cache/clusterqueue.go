@@ -18,8 +18,12 @@ package cache
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"math"
+	"slices"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -28,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
 	"k8s.io/utils/ptr"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
@@ -35,6 +40,10 @@ import (
 	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/resources"
 	utilac "sigs.k8s.io/kueue/pkg/util/admissioncheck"
+	"sigs.k8s.io/kueue/pkg/util/heap"
+	utillocalqueue "sigs.k8s.io/kueue/pkg/util/localqueue"
+	"sigs.k8s.io/kueue/pkg/util/quotawindow"
+	utilresource "sigs.k8s.io/kueue/pkg/util/resource"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -43,20 +52,42 @@ var (
 	oneQuantity           = resource.MustParse("1")
 )
 
+const (
+	// admissionEstimateWindow is how long the released quota is taken into
+	// account to estimate the admission time of the head workload.
+	admissionEstimateWindow = 30 * time.Minute
+	// admissionEstimateMinReleases is the minimum number of workloads that
+	// need to release quota within the window to estimate the admission time.
+	admissionEstimateMinReleases = 3
+	// usageTrendWindow is the period over which the rate of change of the
+	// usage of the ClusterQueue is averaged.
+	usageTrendWindow = 5 * time.Minute
+)
+
 // ClusterQueue is the internal implementation of kueue.ClusterQueue that
 // holds admitted workloads.
 type ClusterQueue struct {
-	Name              string
-	Cohort            *Cohort
-	ResourceGroups    []ResourceGroup
-	RGByResource      map[corev1.ResourceName]*ResourceGroup
-	Usage             resources.FlavorResourceQuantities
-	Workloads         map[string]*workload.Info
-	WorkloadsNotReady sets.Set[string]
-	NamespaceSelector labels.Selector
-	Preemption        kueue.ClusterQueuePreemption
-	FairWeight        resource.Quantity
-	FlavorFungibility kueue.FlavorFungibility
+	Name                    string
+	Cohort                  *Cohort
+	ResourceGroups          []ResourceGroup
+	RGByResource            map[corev1.ResourceName]*ResourceGroup
+	Usage                   resources.FlavorResourceQuantities
+	Workloads               map[string]*workload.Info
+	WorkloadsNotReady       sets.Set[string]
+	NamespaceSelector       labels.Selector
+	Preemption              kueue.ClusterQueuePreemption
+	FairWeight              resource.Quantity
+	FlavorFungibility       kueue.FlavorFungibility
+	FlavorSelectionStrategy kueue.FlavorSelectionStrategy
+	ReadmissionFlavorPolicy kueue.ReadmissionFlavorPolicy
+	// ResourceAliases maps the resource aliases to the resource whose quota
+	// they use.
+	ResourceAliases map[corev1.ResourceName]corev1.ResourceName
+	// ResourceSlices maps the resource slices to their definition.
+	ResourceSlices map[corev1.ResourceName]kueue.ResourceSlice
+	// IntegerResources are the resources that can only be requested in
+	// whole units.
+	IntegerResources sets.Set[corev1.ResourceName]
 	// Aggregates AdmissionChecks from both .spec.AdmissionChecks and .spec.AdmissionCheckStrategy
 	// Sets hold ResourceFlavors to which an AdmissionCheck should apply.
 	// In case its empty, it means an AdmissionCheck should apply to all ResourceFlavor
@@ -72,9 +103,17 @@ type ClusterQueue struct {
 	// Lendable holds the total lendable quota for the resources of the ClusterQueue, independent of the flavor.
 	Lendable map[corev1.ResourceName]int64
 
+	// leafCohort is the cohort that the ClusterQueue joins through
+	// .spec.cohort, which is the Cohort itself unless it has a parent.
+	leafCohort *Cohort
+
 	// The following fields are not populated in a snapshot.
 
 	AdmittedUsage resources.FlavorResourceQuantities
+	// cohortName is the cohort that the ClusterQueue joins through
+	// .spec.cohort. The Cohort of the ClusterQueue is the root of the
+	// hierarchy of this cohort.
+	cohortName string
 	// localQueues by (namespace/name).
 	localQueues                                        map[string]*queue
 	podsReadyTracking                                  bool
@@ -83,14 +122,68 @@ type ClusterQueue struct {
 	hasMultipleSingleInstanceControllersChecks         bool
 	hasFlavorIndependentAdmissionCheckAppliedPerFlavor bool
 	admittedWorkloadsCount                             int
-	isStopped                                          bool
-	workloadInfoOptions                                []workload.InfoOption
+	admittedButUnschedulableCount                      int
+	// admissionTimes are the admission times of the admitted workloads, the
+	// oldest first.
+	admissionTimes         *heap.Heap[admissionTime]
+	evictingWorkloadsCount int
+	isStopped              bool
+	workloadInfoOptions    []workload.InfoOption
+	// inactiveGracePeriod is the time the ClusterQueue stays active while
+	// referencing missing flavors.
+	inactiveGracePeriod time.Duration
+	// missingFlavorsSince is the time at which the flavors went missing while
+	// the ClusterQueue was active.
+	missingFlavorsSince time.Time
+	clock               clock.Clock
+	// releases are the quotas released by the workloads that left the
+	// ClusterQueue recently, oldest first. They are only recorded when
+	// the HeadAdmissionEstimate feature is enabled.
+	releases []quotaRelease
+	// usageSamples are the usage of the ClusterQueue after its recent
+	// changes, oldest first, including the last change before the usage
+	// trend window.
+	usageSamples []usageSample
+	// specResourceGroups are the resourceGroups of the ClusterQueue, before
+	// the quotas of the active quota window are applied.
+	specResourceGroups []kueue.ResourceGroup
+	quotaWindows       []kueue.QuotaWindow
+	// activeQuotaWindow is the name of the quota window whose quotas apply,
+	// or empty if none does.
+	activeQuotaWindow string
+}
+
+// usageSample is the usage of the ClusterQueue at a point in time.
+type usageSample struct {
+	time  time.Time
+	usage resources.FlavorResourceQuantities
+}
+
+// quotaRelease is the quota released by a workload leaving the ClusterQueue.
+type quotaRelease struct {
+	time     time.Time
+	requests workload.Requests
 }
 
 // Cohort is a set of ClusterQueues that can borrow resources from each other.
+// The members of a cohort are the ClusterQueues of its subtree in the
+// hierarchy of cohorts, and the Cohort of a ClusterQueue is the root of the
+// hierarchy. A ClusterQueue borrows the unused quota of the cohort it joins
+// first, and then the one of each ancestor, up to the root.
 type Cohort struct {
 	Name    string
 	Members sets.Set[*ClusterQueue]
+	// parent is the parent of the cohort in the hierarchy, nil for a root.
+	parent *Cohort
+	// BorrowingCaps limit the total quota, per flavor and resource, that
+	// the members can borrow. Resources without a cap can be borrowed freely.
+	BorrowingCaps resources.FlavorResourceQuantities
+	// ReclaimDelay is the minimum time since their quota reservation during
+	// which the workloads borrowing quota are protected from reclaim.
+	ReclaimDelay time.Duration
+	// BorrowingSafetyMarginPercent is the percentage of the quota of the
+	// cohort, per flavor and resource, that can't be borrowed.
+	BorrowingSafetyMarginPercent int32
 
 	// The next fields are only populated for a snapshot.
 
@@ -125,9 +218,14 @@ type ResourceQuota struct {
 }
 
 type queue struct {
-	key                string
+	key string
+	// clusterQueues are the names of the clusterQueues that can serve the
+	// workloads of the queue. The queue is tracked in each of them, with
+	// the workloads reserving quota in that clusterQueue.
+	clusterQueues      []string
 	reservingWorkloads int
 	admittedWorkloads  int
+	maxConcurrency     *int32
 	//TODO: rename this to better distinguish between reserved and "in use" quantities
 	usage         resources.FlavorResourceQuantities
 	admittedUsage resources.FlavorResourceQuantities
@@ -154,8 +252,7 @@ func (c *ClusterQueue) FitInCohort(q resources.FlavorResourceQuantities) bool {
 	for flavor, qResources := range q {
 		if _, flavorFound := c.Cohort.RequestableResources[flavor]; flavorFound {
 			for resource, value := range qResources {
-				available := c.RequestableCohortQuota(flavor, resource) - c.UsedCohortQuota(flavor, resource)
-				if available < value {
+				if c.CohortAvailable(flavor, resource, false) < value {
 					return false
 				}
 			}
@@ -166,6 +263,220 @@ func (c *ClusterQueue) FitInCohort(q resources.FlavorResourceQuantities) bool {
 	return true
 }
 
+// FitInCohortBorrowableQuota returns whether the quantities, for the flavors
+// and resources that are borrowed, fit in the quota of the cohorts that can
+// be borrowed, that is, excluding their borrowing safety margins.
+func (c *ClusterQueue) FitInCohortBorrowableQuota(q, borrowing resources.FlavorResourceQuantities) bool {
+	if !slices.ContainsFunc(c.cohortPath(), func(cohort *Cohort) bool { return cohort.BorrowingSafetyMarginPercent > 0 }) {
+		return true
+	}
+	for flavor, bResources := range borrowing {
+		for resource := range bResources {
+			if q[flavor][resource] > c.CohortAvailable(flavor, resource, true) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CohortAvailable returns the quota of the flavor and resource that the
+// ClusterQueue can still use from its cohorts, including the quota of the
+// ClusterQueue itself. When borrowing, the borrowing safety margins of the
+// cohorts can't be used.
+func (c *ClusterQueue) CohortAvailable(fName kueue.ResourceFlavorReference, rName corev1.ResourceName, borrowing bool) int64 {
+	return c.cohortAvailable(c.cohortPath(), fName, rName, borrowing)
+}
+
+// cohortAvailable returns the quota of the flavor and resource that the
+// ClusterQueue can still use from the cohorts of the path, which hold the
+// accumulated quota and usage of their members.
+// The ClusterQueue uses the unused quota of the nearest cohort first, and
+// then the unused quota of each ancestor outside of its child in the path.
+// What is available in a cohort is bounded by what is available in its
+// parent, as the parent holds the quota of all its descendants. When
+// borrowing, the ClusterQueue can't use the part of the quota of a cohort
+// that is within its borrowing safety margin, but it can still borrow from
+// the ancestors.
+func (c *ClusterQueue) cohortAvailable(path []*Cohort, fName kueue.ResourceFlavorReference, rName corev1.ResourceName, borrowing bool) int64 {
+	// When feature LendingLimit is enabled, the quota and usage of a cohort
+	// exclude the guaranteed quota of its members, but the ClusterQueue can
+	// use its own.
+	guaranteed := c.guaranteedQuota(fName, rName)
+	guaranteedUsed := min(c.Usage[fName][rName], guaranteed)
+	var available int64
+	for i := len(path) - 1; i >= 0; i-- {
+		cohort := path[i]
+		quota := cohort.RequestableResources[fName][rName] + guaranteed
+		unused := quota - cohort.Usage[fName][rName] - guaranteedUsed
+		var margin int64
+		if borrowing {
+			margin = cohort.borrowingSafetyMargin(quota)
+		}
+		if i == len(path)-1 {
+			available = unused - margin
+			continue
+		}
+		outside := max(0, available-max(0, unused))
+		available = min(available, max(0, unused-margin)+outside)
+	}
+	return available
+}
+
+// Borrowed returns the sum of the usage above the nominal quota of its
+// members for the flavor and resource.
+func (c *Cohort) Borrowed(fName kueue.ResourceFlavorReference, rName corev1.ResourceName) int64 {
+	var borrowed int64
+	for member := range c.Members {
+		borrowed += member.borrowed(fName, rName, 0)
+	}
+	return borrowed
+}
+
+// PeersBorrowed returns the sum of the usage above the nominal quota of the
+// other members of the cohort for the flavor and resource.
+func (c *ClusterQueue) PeersBorrowed(fName kueue.ResourceFlavorReference, rName corev1.ResourceName) int64 {
+	if c.Cohort == nil {
+		return 0
+	}
+	return c.Cohort.Borrowed(fName, rName) - c.borrowed(fName, rName, 0)
+}
+
+// Borrowing returns the quota above the nominal quota that the ClusterQueue
+// would additionally borrow if the usage q was added to it.
+func (c *ClusterQueue) Borrowing(q resources.FlavorResourceQuantities) resources.FlavorResourceQuantities {
+	borrowing := make(resources.FlavorResourceQuantities)
+	for flavor, qResources := range q {
+		for resource, value := range qResources {
+			if b := c.borrowed(flavor, resource, value) - c.borrowed(flavor, resource, 0); b > 0 {
+				if borrowing[flavor] == nil {
+					borrowing[flavor] = make(map[corev1.ResourceName]int64)
+				}
+				borrowing[flavor][resource] = b
+			}
+		}
+	}
+	return borrowing
+}
+
+// FitInCohortBorrowingCaps returns whether the borrowing caps of the cohorts
+// of the ClusterQueue allow their members to borrow the quota b, in addition
+// to what they are already borrowing.
+func (c *ClusterQueue) FitInCohortBorrowingCaps(b resources.FlavorResourceQuantities) bool {
+	for _, cohort := range c.cohortPath() {
+		for flavor, bResources := range b {
+			for resource, value := range bResources {
+				borrowingCap, capped := cohort.BorrowingCaps[flavor][resource]
+				if capped && cohort.Borrowed(flavor, resource)+value > borrowingCap {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// ReclaimDelay returns the time since their quota reservation during which
+// the workloads of the other ClusterQueue are protected from reclaim by this
+// one. It's the longest reclaim delay among the cohorts that both
+// ClusterQueues belong to.
+func (c *ClusterQueue) ReclaimDelay(other *ClusterQueue) time.Duration {
+	var delay time.Duration
+	for _, cohort := range c.cohortPath() {
+		if cohort.Members.Has(other) {
+			delay = max(delay, cohort.ReclaimDelay)
+		}
+	}
+	return delay
+}
+
+// cohortPath returns the cohort that the ClusterQueue joins followed by its
+// ancestors, up to the Cohort of the ClusterQueue.
+func (c *ClusterQueue) cohortPath() []*Cohort {
+	if c.leafCohort == nil {
+		// The ClusterQueue was built without the hierarchy.
+		if c.Cohort == nil {
+			return nil
+		}
+		return []*Cohort{c.Cohort}
+	}
+	var path []*Cohort
+	for cohort := c.leafCohort; cohort != nil; cohort = cohort.parent {
+		path = append(path, cohort)
+	}
+	return path
+}
+
+// cohortNames returns the names of the cohorts of the path of the
+// ClusterQueue.
+func (c *ClusterQueue) cohortNames() []string {
+	path := c.cohortPath()
+	names := make([]string, len(path))
+	for i, cohort := range path {
+		names[i] = cohort.Name
+	}
+	return names
+}
+
+// availableQuota returns, for every flavor and resource, the quota that the
+// ClusterQueue can still reserve, considering its unused nominal quota, the
+// quota that the cohorts can lend, the borrowing limit and the cohort
+// borrowing caps.
+func (c *ClusterQueue) availableQuota() resources.FlavorResourceQuantities {
+	path := c.cohortPath()
+	// Accumulate the resources of the members like a snapshot does, without
+	// modifying the cohorts of the cache.
+	accumulated := make([]*Cohort, len(path))
+	for i, cohort := range path {
+		accumulated[i] = &Cohort{BorrowingSafetyMarginPercent: cohort.BorrowingSafetyMarginPercent}
+		for member := range cohort.Members {
+			member.accumulateResources(accumulated[i])
+		}
+	}
+	available := make(resources.FlavorResourceQuantities)
+	for _, rg := range c.ResourceGroups {
+		for _, flvQuotas := range rg.Flavors {
+			fName := flvQuotas.Name
+			available[fName] = make(map[corev1.ResourceName]int64, len(flvQuotas.Resources))
+			for rName, rQuota := range flvQuotas.Resources {
+				used := c.Usage[fName][rName]
+				val := rQuota.Nominal - used
+				if len(path) > 0 {
+					val = min(c.cohortAvailable(accumulated, fName, rName, false),
+						max(rQuota.Nominal-used, c.cohortAvailable(accumulated, fName, rName, true)))
+					if rQuota.BorrowingLimit != nil {
+						val = min(val, rQuota.Nominal+*rQuota.BorrowingLimit-used)
+					}
+					for _, cohort := range path {
+						if borrowingCap, capped := cohort.BorrowingCaps[fName][rName]; capped {
+							val = min(val, max(rQuota.Nominal, used)-used+borrowingCap-cohort.Borrowed(fName, rName))
+						}
+					}
+				}
+				available[fName][rName] = max(val, 0)
+			}
+		}
+	}
+	return available
+}
+
+// borrowed returns the usage above the nominal quota of the ClusterQueue
+// for the flavor and resource, if the extra quantity was added to it.
+func (c *ClusterQueue) borrowed(fName kueue.ResourceFlavorReference, rName corev1.ResourceName, extra int64) int64 {
+	used := c.Usage[fName][rName] + extra
+	for _, rg := range c.ResourceGroups {
+		for _, flvQuotas := range rg.Flavors {
+			if flvQuotas.Name != fName {
+				continue
+			}
+			if rQuota, found := flvQuotas.Resources[rName]; found {
+				return max(0, used-rQuota.Nominal)
+			}
+		}
+	}
+	return 0
+}
+
 func (c *ClusterQueue) IsBorrowing() bool {
 	if c.Cohort == nil || len(c.Usage) == 0 {
 		return false
@@ -197,7 +508,9 @@ var defaultPreemption = kueue.ClusterQueuePreemption{
 var defaultFlavorFungibility = kueue.FlavorFungibility{WhenCanBorrow: kueue.Borrow, WhenCanPreempt: kueue.TryNextFlavor}
 
 func (c *ClusterQueue) update(in *kueue.ClusterQueue, resourceFlavors map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor, admissionChecks map[string]AdmissionCheck) error {
-	c.updateResourceGroups(in.Spec.ResourceGroups)
+	c.specResourceGroups = in.Spec.ResourceGroups
+	c.quotaWindows = in.Spec.QuotaWindows
+	c.updateResourceGroups(c.resourceGroupsInActiveWindow())
 	nsSelector, err := metav1.LabelSelectorAsSelector(in.Spec.NamespaceSelector)
 	if err != nil {
 		return err
@@ -231,6 +544,32 @@ func (c *ClusterQueue) update(in *kueue.ClusterQueue, resourceFlavors map[kueue.
 		c.FlavorFungibility = defaultFlavorFungibility
 	}
 
+	c.FlavorSelectionStrategy = in.Spec.FlavorSelectionStrategy
+	c.ReadmissionFlavorPolicy = in.Spec.ReadmissionFlavorPolicy
+
+	c.ResourceAliases = nil
+	for _, ra := range in.Spec.ResourceAliases {
+		if c.ResourceAliases == nil {
+			c.ResourceAliases = make(map[corev1.ResourceName]corev1.ResourceName)
+		}
+		for _, alias := range ra.Aliases {
+			c.ResourceAliases[alias] = ra.Name
+		}
+	}
+
+	c.ResourceSlices = nil
+	for _, rs := range in.Spec.ResourceSlices {
+		if c.ResourceSlices == nil {
+			c.ResourceSlices = make(map[corev1.ResourceName]kueue.ResourceSlice)
+		}
+		c.ResourceSlices[rs.Name] = rs
+	}
+
+	c.IntegerResources = nil
+	if len(in.Spec.IntegerResources) > 0 {
+		c.IntegerResources = sets.New(in.Spec.IntegerResources...)
+	}
+
 	c.FairWeight = oneQuantity
 	if fs := in.Spec.FairSharing; fs != nil && fs.Weight != nil {
 		c.FairWeight = *fs.Weight
@@ -274,6 +613,34 @@ func filterFlavorQuantities(orig resources.FlavorResourceQuantities, resourceGro
 	return ret
 }
 
+// resourceGroupsInActiveWindow records the quota window that is active now
+// and returns the resourceGroups with its quotas applied.
+func (c *ClusterQueue) resourceGroupsInActiveWindow() []kueue.ResourceGroup {
+	c.activeQuotaWindow = ""
+	if len(c.quotaWindows) == 0 {
+		return c.specResourceGroups
+	}
+	active := quotawindow.Active(c.quotaWindows, c.clock.Now())
+	if active != nil {
+		c.activeQuotaWindow = active.Name
+	}
+	return quotawindow.ApplyQuotas(c.specResourceGroups, active)
+}
+
+// refreshQuotaWindow applies the quotas of the quota window that is active
+// now, if it's not the one applied already. Returns whether the quotas
+// changed.
+func (c *ClusterQueue) refreshQuotaWindow(resourceFlavors map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor) bool {
+	prevWindow := c.activeQuotaWindow
+	resourceGroups := c.resourceGroupsInActiveWindow()
+	if c.activeQuotaWindow == prevWindow {
+		return false
+	}
+	c.updateResourceGroups(resourceGroups)
+	c.UpdateWithFlavors(resourceFlavors)
+	return true
+}
+
 func (c *ClusterQueue) updateResourceGroups(in []kueue.ResourceGroup) {
 	oldRG := c.ResourceGroups
 	c.ResourceGroups = make([]ResourceGroup, len(in))
@@ -300,6 +667,16 @@ func (c *ClusterQueue) updateResourceGroups(in []kueue.ResourceGroup) {
 				}
 				if features.Enabled(features.LendingLimit) && rIn.LendingLimit != nil {
 					rQuota.LendingLimit = ptr.To(workload.ResourceValue(rIn.Name, *rIn.LendingLimit))
+				}
+				if features.Enabled(features.LendingLimit) && rIn.NonLendableQuota != nil {
+					// The non-lendable quota is folded into the lending limit, so that
+					// it's accounted as guaranteed quota.
+					lendable := max(nominal-workload.ResourceValue(rIn.Name, *rIn.NonLendableQuota), 0)
+					if rQuota.LendingLimit == nil || *rQuota.LendingLimit > lendable {
+						rQuota.LendingLimit = &lendable
+					}
+				}
+				if rQuota.LendingLimit != nil {
 					c.Lendable[rIn.Name] += *rQuota.LendingLimit
 				} else {
 					c.Lendable[rIn.Name] += nominal
@@ -328,7 +705,7 @@ func (c *ClusterQueue) UpdateRGByResource() {
 
 func (c *ClusterQueue) updateQueueStatus() {
 	status := active
-	if c.hasMissingFlavors || c.hasMissingOrInactiveAdmissionChecks || c.isStopped || c.hasMultipleSingleInstanceControllersChecks || c.hasFlavorIndependentAdmissionCheckAppliedPerFlavor {
+	if c.hasMissingFlavorsBeyondGracePeriod() || c.hasMissingOrInactiveAdmissionChecks || c.isStopped || c.hasMultipleSingleInstanceControllersChecks || c.hasFlavorIndependentAdmissionCheckAppliedPerFlavor {
 		status = pending
 	}
 	if c.Status == terminating {
@@ -340,6 +717,22 @@ func (c *ClusterQueue) updateQueueStatus() {
 	}
 }
 
+// hasMissingFlavorsBeyondGracePeriod returns whether the ClusterQueue should
+// be inactive due to missing flavors. An active ClusterQueue remains active
+// until the flavors are missing for longer than the inactive grace period.
+func (c *ClusterQueue) hasMissingFlavorsBeyondGracePeriod() bool {
+	return c.hasMissingFlavors && (c.Status != active || c.inactiveGracePeriodRemaining() == 0)
+}
+
+// inactiveGracePeriodRemaining returns the time left before an active
+// ClusterQueue with missing flavors is marked inactive.
+func (c *ClusterQueue) inactiveGracePeriodRemaining() time.Duration {
+	if !c.hasMissingFlavors || c.inactiveGracePeriod <= 0 || c.Status != active {
+		return 0
+	}
+	return max(0, c.inactiveGracePeriod-c.clock.Since(c.missingFlavorsSince))
+}
+
 func (c *ClusterQueue) inactiveReason() (string, string) {
 	switch c.Status {
 	case terminating:
@@ -377,6 +770,12 @@ func (c *ClusterQueue) inactiveReason() (string, string) {
 // Exported only for testing.
 func (c *ClusterQueue) UpdateWithFlavors(flavors map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor) {
 	c.hasMissingFlavors = c.updateLabelKeys(flavors)
+	switch {
+	case !c.hasMissingFlavors:
+		c.missingFlavorsSince = time.Time{}
+	case c.inactiveGracePeriod > 0 && c.missingFlavorsSince.IsZero():
+		c.missingFlavorsSince = c.clock.Now()
+	}
 	c.updateQueueStatus()
 }
 
@@ -399,6 +798,7 @@ func (c *ClusterQueue) updateLabelKeys(flavors map[kueue.ResourceFlavorReference
 			}
 		}
 
+		rg.LabelKeys = nil
 		if keys.Len() > 0 {
 			rg.LabelKeys = keys
 		}
@@ -470,6 +870,7 @@ func (c *ClusterQueue) addWorkload(w *kueue.Workload) error {
 		c.WorkloadsNotReady.Insert(k)
 	}
 	c.reportActiveWorkloads()
+	c.recordUsage()
 	return nil
 }
 
@@ -489,11 +890,117 @@ func (c *ClusterQueue) deleteWorkload(w *kueue.Workload) {
 
 	delete(c.Workloads, k)
 	c.reportActiveWorkloads()
+	c.recordUsage()
+}
+
+// recordUsage records the current usage of the ClusterQueue for its usage
+// trend, dropping the samples that are no longer needed.
+func (c *ClusterQueue) recordUsage() {
+	now := c.clock.Now()
+	windowStart := now.Add(-usageTrendWindow)
+	// Keep the last sample before the window, as it was the usage at the
+	// start of the window.
+	if inWindow := slices.IndexFunc(c.usageSamples, func(s usageSample) bool {
+		return s.time.After(windowStart)
+	}); inWindow == -1 {
+		c.usageSamples = slices.Delete(c.usageSamples, 0, max(len(c.usageSamples)-1, 0))
+	} else if inWindow > 1 {
+		c.usageSamples = slices.Delete(c.usageSamples, 0, inWindow-1)
+	}
+	usage := make(resources.FlavorResourceQuantities, len(c.Usage))
+	for fName, rUsage := range c.Usage {
+		usage[fName] = maps.Clone(rUsage)
+	}
+	c.usageSamples = append(c.usageSamples, usageSample{time: now, usage: usage})
+}
+
+// usageTrends returns the average rate of change of the usage of the
+// ClusterQueue over the usage trend window ending now. The usage before the
+// first sample is assumed to be the usage of the first sample.
+func (c *ClusterQueue) usageTrends() []metrics.UsageTrend {
+	if len(c.usageSamples) == 0 {
+		return nil
+	}
+	windowStart := c.clock.Now().Add(-usageTrendWindow)
+	start := c.usageSamples[0].usage
+	for _, s := range c.usageSamples[1:] {
+		if s.time.After(windowStart) {
+			break
+		}
+		start = s.usage
+	}
+	var trends []metrics.UsageTrend
+	for fName, rUsage := range c.usageSamples[len(c.usageSamples)-1].usage {
+		for rName, v := range rUsage {
+			delta := workload.ResourceQuantity(rName, v-start[fName][rName])
+			trends = append(trends, metrics.UsageTrend{
+				ClusterQueue: c.Name,
+				Flavor:       string(fName),
+				Resource:     string(rName),
+				Value:        utilresource.QuantityToFloat(&delta) / usageTrendWindow.Seconds(),
+			})
+		}
+	}
+	return trends
+}
+
+// releaseWorkload removes the workload from the ClusterQueue, recording the
+// quota that it releases for the admission estimates.
+func (c *ClusterQueue) releaseWorkload(w *kueue.Workload) {
+	if wi, exist := c.Workloads[workload.Key(w)]; exist && features.Enabled(features.HeadAdmissionEstimate) {
+		now := c.clock.Now()
+		c.releases = append(slices.Delete(c.releases, 0, c.firstRecentRelease(now)), quotaRelease{
+			time:     now,
+			requests: c.totalRequests(wi.TotalRequests),
+		})
+	}
+	c.deleteWorkload(w)
 }
 
 func (c *ClusterQueue) reportActiveWorkloads() {
 	metrics.AdmittedActiveWorkloads.WithLabelValues(c.Name).Set(float64(c.admittedWorkloadsCount))
+	metrics.AdmittedButUnschedulableWorkloads.WithLabelValues(c.Name).Set(float64(c.admittedButUnschedulableCount))
+	metrics.EvictingWorkloads.WithLabelValues(c.Name).Set(float64(c.evictingWorkloadsCount))
 	metrics.ReservingActiveWorkloads.WithLabelValues(c.Name).Set(float64(len(c.Workloads)))
+	metrics.ReportOldestAdmittedWorkload(c.Name, c.oldestAdmissionTime())
+}
+
+// admissionTime is when an admitted workload was admitted.
+type admissionTime struct {
+	key  string
+	time time.Time
+}
+
+// oldestAdmissionTime returns the time when the oldest of the admitted
+// workloads was admitted, or nil if there are no admitted workloads.
+func (c *ClusterQueue) oldestAdmissionTime() *time.Time {
+	if c.admissionTimes == nil {
+		return nil
+	}
+	if oldest := c.admissionTimes.Peek(); oldest != nil {
+		return &oldest.time
+	}
+	return nil
+}
+
+// trackAdmissionTime adds or removes the admission time of the admitted
+// workload, depending on the sign of m.
+func (c *ClusterQueue) trackAdmissionTime(wi *workload.Info, m int64) {
+	key := workload.Key(wi.Obj)
+	if m < 0 {
+		if c.admissionTimes != nil {
+			c.admissionTimes.Delete(key)
+		}
+		return
+	}
+	if c.admissionTimes == nil {
+		c.admissionTimes = heap.New(
+			func(t *admissionTime) string { return t.key },
+			func(a, b *admissionTime) bool { return a.time.Before(b.time) },
+		)
+	}
+	cond := apimeta.FindStatusCondition(wi.Obj.Status.Conditions, kueue.WorkloadAdmitted)
+	c.admissionTimes.PushOrUpdate(&admissionTime{key: key, time: cond.LastTransitionTime.Time})
 }
 
 // updateWorkloadUsage updates the usage of the ClusterQueue for the workload
@@ -501,9 +1008,16 @@ func (c *ClusterQueue) reportActiveWorkloads() {
 func (c *ClusterQueue) updateWorkloadUsage(wi *workload.Info, m int64) {
 	admitted := workload.IsAdmitted(wi.Obj)
 	updateFlavorUsage(wi, c.Usage, m)
+	if apimeta.IsStatusConditionTrue(wi.Obj.Status.Conditions, kueue.WorkloadEvicted) {
+		c.evictingWorkloadsCount += int(m)
+	}
 	if admitted {
 		updateFlavorUsage(wi, c.AdmittedUsage, m)
 		c.admittedWorkloadsCount += int(m)
+		c.trackAdmissionTime(wi, m)
+		if apimeta.IsStatusConditionTrue(wi.Obj.Status.Conditions, kueue.WorkloadAdmittedButUnschedulable) {
+			c.admittedButUnschedulableCount += int(m)
+		}
 	}
 	qKey := workload.QueueKey(wi.Obj)
 	if lq, ok := c.localQueues[qKey]; ok {
@@ -530,11 +1044,11 @@ func updateFlavorUsage(wi *workload.Info, flvUsage resources.FlavorResourceQuant
 	}
 }
 
-func updateCohortUsage(wi *workload.Info, cq *ClusterQueue, m int64) {
+func updateCohortUsage(wi *workload.Info, cq *ClusterQueue, cohortUsage resources.FlavorResourceQuantities, m int64) {
 	for _, ps := range wi.TotalRequests {
 		for wlRes, wlResFlv := range ps.Flavors {
 			v, wlResExist := ps.Requests[wlRes]
-			flv, flvExist := cq.Cohort.Usage[wlResFlv]
+			flv, flvExist := cohortUsage[wlResFlv]
 			if flvExist && wlResExist {
 				if _, exists := flv[wlRes]; exists {
 					after := cq.Usage[wlResFlv][wlRes] - cq.guaranteedQuota(wlResFlv, wlRes)
@@ -562,7 +1076,9 @@ func (c *ClusterQueue) addLocalQueue(q *kueue.LocalQueue) error {
 	// receiving the queue add event.
 	qImpl := &queue{
 		key:                qKey,
+		clusterQueues:      utillocalqueue.ClusterQueueNames(q),
 		reservingWorkloads: 0,
+		maxConcurrency:     q.Spec.MaxConcurrency,
 		usage:              make(resources.FlavorResourceQuantities),
 	}
 	if err := qImpl.resetFlavorsAndResources(c.Usage, c.AdmittedUsage); err != nil {
@@ -582,6 +1098,13 @@ func (c *ClusterQueue) addLocalQueue(q *kueue.LocalQueue) error {
 	return nil
 }
 
+func (c *ClusterQueue) updateLocalQueue(q *kueue.LocalQueue) {
+	if qImpl, ok := c.localQueues[queueKey(q)]; ok {
+		qImpl.clusterQueues = utillocalqueue.ClusterQueueNames(q)
+		qImpl.maxConcurrency = q.Spec.MaxConcurrency
+	}
+}
+
 func (c *ClusterQueue) deleteLocalQueue(q *kueue.LocalQueue) {
 	qKey := queueKey(q)
 	delete(c.localQueues, qKey)
@@ -639,6 +1162,19 @@ func (c *ClusterQueue) RequestableCohortQuota(fName kueue.ResourceFlavorReferenc
 	return requestableCohortQuota
 }
 
+// BorrowableCohortQuota returns the quota of the cohort for the flavor and
+// resource that can be used while borrowing, excluding the borrowing safety
+// margin of the cohort.
+func (c *ClusterQueue) BorrowableCohortQuota(fName kueue.ResourceFlavorReference, rName corev1.ResourceName) int64 {
+	requestable := c.RequestableCohortQuota(fName, rName)
+	return requestable - c.Cohort.borrowingSafetyMargin(requestable)
+}
+
+// borrowingSafetyMargin returns the part of the quota that can't be borrowed.
+func (c *Cohort) borrowingSafetyMargin(quota int64) int64 {
+	return quota * int64(c.BorrowingSafetyMarginPercent) / 100
+}
+
 func (c *ClusterQueue) guaranteedQuota(fName kueue.ResourceFlavorReference, rName corev1.ResourceName) (val int64) {
 	if !features.Enabled(features.LendingLimit) {
 		return 0
@@ -649,31 +1185,6 @@ func (c *ClusterQueue) guaranteedQuota(fName kueue.ResourceFlavorReference, rNam
 	return c.GuaranteedQuota[fName][rName]
 }
 
-// UsedCohortQuota returns the used quota by the flavor and resource name in the cohort.
-// Note that when LendingLimit enabled, the usage is not equal to the total used quota but the one
-// minus the guaranteed resources, this is only for judging whether workloads fit in the cohort.
-func (c *ClusterQueue) UsedCohortQuota(fName kueue.ResourceFlavorReference, rName corev1.ResourceName) (val int64) {
-	if c.Cohort.Usage == nil || c.Cohort.Usage[fName] == nil {
-		return 0
-	}
-
-	cohortUsage := c.Cohort.Usage[fName][rName]
-
-	// When feature LendingLimit enabled, cohortUsage is the sum of usage in LendingLimit.
-	// If cqUsage < c.guaranteedQuota, it means the cq is not using all its guaranteedQuota,
-	// need to count the cqUsage in, otherwise need to count the guaranteedQuota in.
-	if features.Enabled(features.LendingLimit) {
-		cqUsage := c.Usage[fName][rName]
-		if cqUsage < c.guaranteedQuota(fName, rName) {
-			cohortUsage += cqUsage
-		} else {
-			cohortUsage += c.guaranteedQuota(fName, rName)
-		}
-	}
-
-	return cohortUsage
-}
-
 // DominantResourceShare returns a value from 0 to 1,000,000 representing the maximum of the ratios
 // of usage above nominal quota to the lendable resources in the cohort, among all the resources
 // provided by the ClusterQueue, and divided by the weight.
@@ -736,3 +1247,167 @@ func (c *ClusterQueue) dominantResourceShare(wlReq resources.FlavorResourceQuant
 	dws := drs * 1000 / c.FairWeight.MilliValue()
 	return int(dws), dRes
 }
+
+// ValidateRequestsFitCapacity returns an error if a pod of any pod set
+// requests more of a resource than the maximum capacity of every flavor of the
+// resource, so the workload can never be admitted in the ClusterQueue.
+// The maximum capacity of a flavor is its nominal quota plus its borrowing
+// limit. A flavor without borrowing limit in a ClusterQueue that belongs to a
+// cohort has no maximum capacity.
+func (c *ClusterQueue) ValidateRequestsFitCapacity(requests []workload.PodSetResources) error {
+	for _, psr := range requests {
+		if psr.Count == 0 {
+			continue
+		}
+		for _, name := range sets.List(sets.KeySet(psr.Requests)) {
+			rg := c.RGByResource[name]
+			if rg == nil {
+				continue
+			}
+			perPod := psr.Requests[name] / int64(psr.Count)
+			capacity, bounded := c.maxCapacity(rg, name)
+			if bounded && perPod > capacity {
+				perPodQuantity := workload.ResourceQuantity(name, perPod)
+				capacityQuantity := workload.ResourceQuantity(name, capacity)
+				return fmt.Errorf("%s of podSet %s requests %s per pod, more than the maximum capacity of %s of any flavor",
+					name, psr.Name, perPodQuantity.String(), capacityQuantity.String())
+			}
+		}
+	}
+	return nil
+}
+
+// maxCapacity returns the largest maximum capacity for the resource among the
+// flavors of the resource group, and whether it's bounded.
+func (c *ClusterQueue) maxCapacity(rg *ResourceGroup, name corev1.ResourceName) (int64, bool) {
+	var capacity int64
+	for _, flavor := range rg.Flavors {
+		quota := flavor.Resources[name]
+		if quota == nil {
+			continue
+		}
+		if quota.BorrowingLimit == nil && c.Cohort != nil {
+			return 0, false
+		}
+		capacity = max(capacity, quota.Nominal+ptr.Deref(quota.BorrowingLimit, 0))
+	}
+	return capacity, true
+}
+
+// RequestsWithAliases returns the requests of the pod sets with the
+// resource aliases and slices replaced by the resources whose quota they use.
+// The slices of every pod set are rounded up to whole units of the resource.
+// The requests are returned unchanged if none of the resources is aliased or
+// sliced.
+func (c *ClusterQueue) RequestsWithAliases(requests []workload.PodSetResources) []workload.PodSetResources {
+	if len(c.ResourceAliases) == 0 && len(c.ResourceSlices) == 0 || !slices.ContainsFunc(requests, c.hasAliases) {
+		return requests
+	}
+	out := make([]workload.PodSetResources, len(requests))
+	for i, psr := range requests {
+		out[i] = psr
+		if !c.hasAliases(psr) {
+			continue
+		}
+		out[i].Requests = make(workload.Requests, len(psr.Requests))
+		for name, v := range psr.Requests {
+			if logical, found := c.ResourceAliases[name]; found {
+				name = logical
+			} else if rs, found := c.ResourceSlices[name]; found {
+				name = rs.Resource
+				units := (v + int64(rs.SlicesPerUnit) - 1) / int64(rs.SlicesPerUnit)
+				v = workload.ResourceValue(name, *resource.NewQuantity(units, resource.DecimalSI))
+			}
+			out[i].Requests[name] += v
+		}
+	}
+	return out
+}
+
+func (c *ClusterQueue) hasAliases(psr workload.PodSetResources) bool {
+	for name := range psr.Requests {
+		if _, found := c.ResourceAliases[name]; found {
+			return true
+		}
+		if _, found := c.ResourceSlices[name]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// firstRecentRelease returns the index of the first release within the
+// admission estimate window, or the number of releases if there is none.
+func (c *ClusterQueue) firstRecentRelease(now time.Time) int {
+	if first := slices.IndexFunc(c.releases, func(r quotaRelease) bool {
+		return now.Sub(r.time) <= admissionEstimateWindow
+	}); first >= 0 {
+		return first
+	}
+	return len(c.releases)
+}
+
+// totalRequests returns the requests of all the pod sets, with the resource
+// aliases replaced by the resources whose quota they use.
+func (c *ClusterQueue) totalRequests(requests []workload.PodSetResources) workload.Requests {
+	total := make(workload.Requests)
+	for _, psr := range c.RequestsWithAliases(requests) {
+		for name, v := range psr.Requests {
+			total[name] += v
+		}
+	}
+	return total
+}
+
+// headAdmissionEstimate estimates when the head workload is likely to be
+// admitted, assuming that the quota it is missing keeps being released at
+// the rate observed within the admission estimate window.
+// The estimate is counted from the last release, so that it only changes
+// when the quota is released or the head changes, and not on every
+// reconcile.
+func (c *ClusterQueue) headAdmissionEstimate(head *workload.Info) *kueue.HeadAdmissionEstimate {
+	estimate := &kueue.HeadAdmissionEstimate{
+		Workload: kueue.ClusterQueuePendingWorkload{
+			Name:      head.Obj.Name,
+			Namespace: head.Obj.Namespace,
+		},
+		State: kueue.AdmissionEstimateUnknown,
+	}
+	available := make(workload.Requests)
+	for _, flvAvailable := range c.availableQuota() {
+		for rName, v := range flvAvailable {
+			available[rName] += v
+		}
+	}
+	recent := c.releases[c.firstRecentRelease(c.clock.Now()):]
+	// The head can't be admitted before it was created or before the last
+	// release it accounts for.
+	since := head.Obj.CreationTimestamp.Time
+	if len(recent) > 0 && recent[len(recent)-1].time.After(since) {
+		since = recent[len(recent)-1].time
+	}
+	var wait time.Duration
+	for rName, requested := range c.totalRequests(head.TotalRequests) {
+		missing := requested - available[rName]
+		if missing <= 0 {
+			continue
+		}
+		if len(recent) < admissionEstimateMinReleases {
+			return estimate
+		}
+		// The quota released by the oldest release doesn't count towards
+		// the rate, as it was released at the start of the elapsed time.
+		elapsed := recent[len(recent)-1].time.Sub(recent[0].time)
+		var released int64
+		for _, r := range recent[1:] {
+			released += r.requests[rName]
+		}
+		if elapsed <= 0 || released == 0 {
+			return estimate
+		}
+		wait = max(wait, time.Duration(float64(missing)/float64(released)*float64(elapsed)))
+	}
+	estimate.State = kueue.AdmissionEstimateKnown
+	estimate.EstimatedAdmissionTime = ptr.To(metav1.NewTime(since.Add(wait).Truncate(time.Second)))
+	return estimate
+}
@@ -55,11 +55,11 @@ func (s *Snapshot) AddWorkload(wl *workload.Info) {
 
 func (c *ClusterQueue) addOrRemoveWorkload(wl *workload.Info, m int64) {
 	updateFlavorUsage(wl, c.Usage, m)
-	if c.Cohort != nil {
+	for _, cohort := range c.cohortPath() {
 		if features.Enabled(features.LendingLimit) {
-			updateCohortUsage(wl, c, m)
+			updateCohortUsage(wl, c, cohort.Usage, m)
 		} else {
-			updateFlavorUsage(wl, c.Cohort.Usage, m)
+			updateFlavorUsage(wl, cohort.Usage, m)
 		}
 	}
 }
@@ -110,14 +110,30 @@ func (c *Cache) Snapshot() Snapshot {
 		// Shallow copy is enough
 		snap.ResourceFlavors[name] = rf
 	}
+	// Every cohort of the hierarchies is copied, with the quota and usage of
+	// the members of its subtree.
+	cohorts := make(map[*Cohort]*Cohort, len(c.cohorts))
 	for _, cohort := range c.cohorts {
 		cohortCopy := newCohort(cohort.Name, cohort.Members.Len())
 		cohortCopy.AllocatableResourceGeneration = 0
+		// Shallow copy is enough, the caps are replaced on update.
+		cohortCopy.BorrowingCaps = cohort.BorrowingCaps
+		cohortCopy.ReclaimDelay = cohort.ReclaimDelay
+		cohortCopy.BorrowingSafetyMarginPercent = cohort.BorrowingSafetyMarginPercent
+		cohorts[cohort] = cohortCopy
+	}
+	for cohort, cohortCopy := range cohorts {
+		cohortCopy.parent = cohorts[cohort.parent]
 		for cq := range cohort.Members {
 			if cq.Active() {
 				cqCopy := snap.ClusterQueues[cq.Name]
 				cqCopy.accumulateResources(cohortCopy)
-				cqCopy.Cohort = cohortCopy
+				if cq.Cohort == cohort {
+					cqCopy.Cohort = cohortCopy
+				}
+				if cq.leafCohort == cohort {
+					cqCopy.leafCohort = cohortCopy
+				}
 				cohortCopy.Members.Insert(cqCopy)
 				cohortCopy.AllocatableResourceGeneration += cqCopy.AllocatableResourceGeneration
 				cohortCopy.Lendable = cohortCopy.CalculateLendable()
@@ -135,6 +151,11 @@ func (c *ClusterQueue) snapshot() *ClusterQueue {
 		ResourceGroups:                c.ResourceGroups, // Shallow copy is enough.
 		RGByResource:                  c.RGByResource,   // Shallow copy is enough.
 		FlavorFungibility:             c.FlavorFungibility,
+		FlavorSelectionStrategy:       c.FlavorSelectionStrategy,
+		ReadmissionFlavorPolicy:       c.ReadmissionFlavorPolicy,
+		ResourceAliases:               c.ResourceAliases,  // Shallow copy is enough.
+		ResourceSlices:                c.ResourceSlices,   // Shallow copy is enough.
+		IntegerResources:              c.IntegerResources, // Shallow copy is enough.
 		FairWeight:                    c.FairWeight,
 		AllocatableResourceGeneration: c.AllocatableResourceGeneration,
 		Usage:                         make(resources.FlavorResourceQuantities, len(c.Usage)),
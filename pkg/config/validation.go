@@ -18,6 +18,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"slices"
 	"strings"
 	"unsafe"
@@ -51,12 +52,18 @@ var (
 	integrationsExternalFrameworkPath = integrationsPath.Child("externalFrameworks")
 	podOptionsPath                    = integrationsPath.Child("podOptions")
 	namespaceSelectorPath             = podOptionsPath.Child("namespaceSelector")
+	queueNameEnforcementPath          = integrationsPath.Child("queueNameEnforcement")
 	waitForPodsReadyPath              = field.NewPath("waitForPodsReady")
 	requeuingStrategyPath             = waitForPodsReadyPath.Child("requeuingStrategy")
 	multiKueuePath                    = field.NewPath("multiKueue")
 	fsPreemptionStrategiesPath        = field.NewPath("fairSharing", "preemptionStrategies")
 	internalCertManagementPath        = field.NewPath("internalCertManagement")
 	queueVisibilityPath               = field.NewPath("queueVisibility")
+	cqInactiveGracePeriodPath         = field.NewPath("clusterQueueInactiveGracePeriod")
+	admittedButUnschedulablePath      = field.NewPath("admittedButUnschedulableThreshold")
+	evictionOrderingPath              = field.NewPath("evictionOrdering")
+	admissionChecksPath               = field.NewPath("admissionChecks")
+	externalAdmissionPath             = field.NewPath("externalAdmission")
 )
 
 func validate(c *configapi.Configuration, scheme *runtime.Scheme) field.ErrorList {
@@ -67,6 +74,80 @@ func validate(c *configapi.Configuration, scheme *runtime.Scheme) field.ErrorLis
 	allErrs = append(allErrs, validateMultiKueue(c)...)
 	allErrs = append(allErrs, validateFairSharing(c)...)
 	allErrs = append(allErrs, validateInternalCertManagement(c)...)
+	allErrs = append(allErrs, validateClusterQueueInactiveGracePeriod(c)...)
+	allErrs = append(allErrs, validateAdmittedButUnschedulableThreshold(c)...)
+	allErrs = append(allErrs, validateEvictionOrdering(c)...)
+	allErrs = append(allErrs, validateAdmissionChecks(c)...)
+	allErrs = append(allErrs, validateExternalAdmission(c)...)
+	return allErrs
+}
+
+func validateClusterQueueInactiveGracePeriod(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.ClusterQueueInactiveGracePeriod != nil && c.ClusterQueueInactiveGracePeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(cqInactiveGracePeriodPath,
+			c.ClusterQueueInactiveGracePeriod.Duration, constants.IsNegativeErrorMsg))
+	}
+	return allErrs
+}
+
+func validateAdmittedButUnschedulableThreshold(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.AdmittedButUnschedulableThreshold != nil && c.AdmittedButUnschedulableThreshold.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(admittedButUnschedulablePath,
+			c.AdmittedButUnschedulableThreshold.Duration, constants.IsNegativeErrorMsg))
+	}
+	return allErrs
+}
+
+func validateEvictionOrdering(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	eo := c.EvictionOrdering
+	if eo == nil {
+		return allErrs
+	}
+	if eo.PriorityWeight != nil && *eo.PriorityWeight < 0 {
+		allErrs = append(allErrs, field.Invalid(evictionOrderingPath.Child("priorityWeight"),
+			*eo.PriorityWeight, constants.IsNegativeErrorMsg))
+	}
+	if eo.RuntimeWeight != nil && *eo.RuntimeWeight < 0 {
+		allErrs = append(allErrs, field.Invalid(evictionOrderingPath.Child("runtimeWeight"),
+			*eo.RuntimeWeight, constants.IsNegativeErrorMsg))
+	}
+	return allErrs
+}
+
+func validateAdmissionChecks(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	ac := c.AdmissionChecks
+	if ac == nil {
+		return allErrs
+	}
+	if ac.Timeout != nil && ac.Timeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(admissionChecksPath.Child("timeout"),
+			ac.Timeout.Duration, constants.IsNegativeErrorMsg))
+	}
+	if ac.TimeoutPolicy != nil &&
+		*ac.TimeoutPolicy != configapi.AdmissionChecksTimeoutRequeue && *ac.TimeoutPolicy != configapi.AdmissionChecksTimeoutDeactivate {
+		allErrs = append(allErrs, field.NotSupported(admissionChecksPath.Child("timeoutPolicy"),
+			ac.TimeoutPolicy, []configapi.AdmissionChecksTimeoutPolicy{configapi.AdmissionChecksTimeoutRequeue, configapi.AdmissionChecksTimeoutDeactivate}))
+	}
+	return allErrs
+}
+
+func validateExternalAdmission(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	ea := c.ExternalAdmission
+	if ea == nil {
+		return allErrs
+	}
+	if u, err := url.Parse(ea.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(externalAdmissionPath.Child("url"), ea.URL, "must be an absolute http or https URL"))
+	}
+	if ea.Timeout != nil && ea.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(externalAdmissionPath.Child("timeout"),
+			ea.Timeout.Duration, "must be greater than 0"))
+	}
 	return allErrs
 }
 
@@ -193,9 +274,21 @@ func validateIntegrations(c *configapi.Configuration, scheme *runtime.Scheme) fi
 	}
 
 	allErrs = append(allErrs, validatePodIntegrationOptions(c)...)
+	allErrs = append(allErrs, validateQueueNameEnforcement(c)...)
 	return allErrs
 }
 
+func validateQueueNameEnforcement(c *configapi.Configuration) field.ErrorList {
+	if c.Integrations.QueueNameEnforcement == nil {
+		return nil
+	}
+	selectorPath := queueNameEnforcementPath.Child("namespaceSelector")
+	if c.Integrations.QueueNameEnforcement.NamespaceSelector == nil {
+		return field.ErrorList{field.Required(selectorPath, "a namespace selector is required")}
+	}
+	return validation.ValidateLabelSelector(c.Integrations.QueueNameEnforcement.NamespaceSelector, validation.LabelSelectorValidationOptions{}, selectorPath)
+}
+
 func validatePodIntegrationOptions(c *configapi.Configuration) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -236,6 +236,21 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		"nil QueueNameEnforcement.NamespaceSelector": {
+			cfg: &configapi.Configuration{
+				QueueVisibility: defaultQueueVisibility,
+				Integrations: &configapi.Integrations{
+					Frameworks:           []string{"batch/job"},
+					QueueNameEnforcement: &configapi.QueueNameEnforcement{},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "integrations.queueNameEnforcement.namespaceSelector",
+				},
+			},
+		},
 		"emptyLabelSelector": {
 			cfg: &configapi.Configuration{
 				Namespace:       ptr.To("kueue-system"),
@@ -459,6 +474,91 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		"negative clusterQueueInactiveGracePeriod": {
+			cfg: &configapi.Configuration{
+				Integrations: defaultIntegrations,
+				ClusterQueueInactiveGracePeriod: &metav1.Duration{
+					Duration: -time.Second,
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "clusterQueueInactiveGracePeriod",
+				},
+			},
+		},
+		"negative admittedButUnschedulableThreshold": {
+			cfg: &configapi.Configuration{
+				Integrations: defaultIntegrations,
+				AdmittedButUnschedulableThreshold: &metav1.Duration{
+					Duration: -time.Second,
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "admittedButUnschedulableThreshold",
+				},
+			},
+		},
+		"negative evictionOrdering weights": {
+			cfg: &configapi.Configuration{
+				Integrations: defaultIntegrations,
+				EvictionOrdering: &configapi.EvictionOrdering{
+					PriorityWeight: ptr.To[int32](-1),
+					RuntimeWeight:  ptr.To[int32](-1),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "evictionOrdering.priorityWeight",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "evictionOrdering.runtimeWeight",
+				},
+			},
+		},
+		"invalid admissionChecks timeout and policy": {
+			cfg: &configapi.Configuration{
+				Integrations: defaultIntegrations,
+				AdmissionChecks: &configapi.AdmissionChecks{
+					Timeout:       &metav1.Duration{Duration: -time.Second},
+					TimeoutPolicy: ptr.To[configapi.AdmissionChecksTimeoutPolicy]("Fail"),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "admissionChecks.timeout",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeNotSupported,
+					Field: "admissionChecks.timeoutPolicy",
+				},
+			},
+		},
+		"invalid externalAdmission url and timeout": {
+			cfg: &configapi.Configuration{
+				Integrations: defaultIntegrations,
+				ExternalAdmission: &configapi.ExternalAdmission{
+					URL:     "planner.example.com/admit",
+					Timeout: &metav1.Duration{},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "externalAdmission.url",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "externalAdmission.timeout",
+				},
+			},
+		},
 		"invalid .multiKueue.origin label value": {
 			cfg: &configapi.Configuration{
 				Integrations: defaultIntegrations,
@@ -0,0 +1,309 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaladmission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/util/admissioncheck"
+	"sigs.k8s.io/kueue/pkg/util/api"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+const (
+	ControllerName = "kueue.x-k8s.io/external-admission"
+
+	// DeniedMessage is the message of the admission checks when the external
+	// service denies the admission without a reason.
+	DeniedMessage = "The admission was denied by the external service"
+
+	// decisionTTL is how long a decision of the external service is reused
+	// for the same proposed admission of a workload.
+	decisionTTL = time.Minute
+)
+
+// AdmissionRequest is the body of the request sent to the external service
+// with the proposed admission of a workload.
+type AdmissionRequest struct {
+	// Workload identifies the workload.
+	Workload WorkloadReference `json:"workload"`
+	// Admission holds the ClusterQueue and the flavors assigned to the
+	// podSets of the workload.
+	Admission kueue.Admission `json:"admission"`
+}
+
+// WorkloadReference identifies a workload.
+type WorkloadReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+}
+
+// AdmissionResponse is the body of the response of the external service.
+type AdmissionResponse struct {
+	// Allowed indicates whether the workload can be admitted.
+	Allowed bool `json:"allowed"`
+	// Reason explains the decision. It's set as the message of the
+	// admission checks.
+	Reason string `json:"reason,omitempty"`
+}
+
+type Controller struct {
+	client     client.Client
+	record     record.EventRecorder
+	httpClient *http.Client
+	url        string
+	clock      clock.Clock
+
+	// decisions holds the recent decisions of the external service, keyed
+	// by the body of the request, so that the service is not asked again
+	// when the workload is reconciled before its checks are updated, or
+	// when it gets the same admission after being requeued.
+	decisionsLock sync.Mutex
+	decisions     map[string]cachedDecision
+}
+
+type cachedDecision struct {
+	response  AdmissionResponse
+	expiresAt time.Time
+}
+
+var _ reconcile.Reconciler = (*Controller)(nil)
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=admissionchecks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=admissionchecks/status,verbs=get;update;patch
+
+func NewController(client client.Client, record record.EventRecorder, url string, timeout time.Duration) *Controller {
+	return &Controller{
+		client:     client,
+		record:     record,
+		httpClient: &http.Client{Timeout: timeout},
+		url:        url,
+		clock:      clock.RealClock{},
+		decisions:  make(map[string]cachedDecision),
+	}
+}
+
+// Reconcile asks the external service to approve the admission of the
+// workload, once it reserved quota, and sets the state of its admission
+// checks managed by this controller accordingly. A denied admission sets the
+// checks to Retry, with the reason of the denial as their message, so that
+// the quota reservation is released and the workload stays pending. Once the
+// reservation is released, the checks are set back to Pending, keeping their
+// message, before the workload reserves quota again.
+func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	wl := &kueue.Workload{}
+	if err := c.client.Get(ctx, req.NamespacedName, wl); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if workload.IsFinished(wl) || workload.IsAdmitted(wl) {
+		return reconcile.Result{}, nil
+	}
+
+	relevantChecks, err := admissioncheck.FilterForController(ctx, c.client, wl.Status.AdmissionChecks, ControllerName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !workload.HasQuotaReservation(wl) {
+		if !workload.IsActive(wl) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, c.resetDecidedChecks(ctx, wl, relevantChecks)
+	}
+	var pendingChecks []string
+	for _, name := range relevantChecks {
+		if check := workload.FindAdmissionCheck(wl.Status.AdmissionChecks, name); check.State == kueue.CheckStatePending {
+			pendingChecks = append(pendingChecks, name)
+		}
+	}
+	if len(pendingChecks) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	resp, err := c.review(ctx, wl)
+	if err != nil {
+		log.V(2).Error(err, "Failed to request the admission to the external service")
+		return reconcile.Result{}, err
+	}
+	log.V(3).Info("Obtained the decision of the external service", "allowed", resp.Allowed, "reason", resp.Reason)
+
+	state := kueue.CheckStateReady
+	message := resp.Reason
+	if !resp.Allowed {
+		state = kueue.CheckStateRetry
+		if message == "" {
+			message = DeniedMessage
+		}
+	}
+	wlPatch := workload.BaseSSAWorkload(wl)
+	for _, name := range pendingChecks {
+		workload.SetAdmissionCheckState(&wlPatch.Status.AdmissionChecks, kueue.AdmissionCheckState{
+			Name:               name,
+			State:              state,
+			Message:            api.TruncateConditionMessage(message),
+			LastTransitionTime: metav1.NewTime(c.clock.Now()),
+		})
+	}
+	if err := c.client.Status().Patch(ctx, wlPatch, client.Apply, client.FieldOwner(ControllerName), client.ForceOwnership); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	eventType := corev1.EventTypeNormal
+	if !resp.Allowed {
+		eventType = corev1.EventTypeWarning
+	}
+	c.record.Event(wl, eventType, "AdmissionCheckUpdated", api.TruncateEventMessage(
+		fmt.Sprintf("Admission checks %v updated state to %s with message %s", pendingChecks, state, message)))
+	return reconcile.Result{}, nil
+}
+
+// resetDecidedChecks sets the checks of the workload that were decided for a
+// previous quota reservation back to Pending, keeping their messages, so
+// that the external service is asked again for the next one.
+func (c *Controller) resetDecidedChecks(ctx context.Context, wl *kueue.Workload, checks []string) error {
+	var decidedChecks []string
+	for _, name := range checks {
+		if check := workload.FindAdmissionCheck(wl.Status.AdmissionChecks, name); check.State != kueue.CheckStatePending {
+			decidedChecks = append(decidedChecks, name)
+		}
+	}
+	if len(decidedChecks) == 0 {
+		return nil
+	}
+	wlPatch := workload.BaseSSAWorkload(wl)
+	for _, name := range decidedChecks {
+		workload.SetAdmissionCheckState(&wlPatch.Status.AdmissionChecks, kueue.AdmissionCheckState{
+			Name:               name,
+			State:              kueue.CheckStatePending,
+			Message:            workload.FindAdmissionCheck(wl.Status.AdmissionChecks, name).Message,
+			LastTransitionTime: metav1.NewTime(c.clock.Now()),
+		})
+	}
+	return client.IgnoreNotFound(c.client.Status().Patch(ctx, wlPatch, client.Apply, client.FieldOwner(ControllerName), client.ForceOwnership))
+}
+
+// review returns the decision of the external service for the proposed
+// admission of the workload, reusing a recent decision for the same one.
+func (c *Controller) review(ctx context.Context, wl *kueue.Workload) (*AdmissionResponse, error) {
+	body, err := json.Marshal(AdmissionRequest{
+		Workload: WorkloadReference{
+			Namespace: wl.Namespace,
+			Name:      wl.Name,
+			UID:       string(wl.UID),
+		},
+		Admission: *wl.Status.Admission,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp, found := c.cachedDecision(string(body)); found {
+		return resp, nil
+	}
+	resp, err := c.send(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	c.storeDecision(string(body), resp)
+	return resp, nil
+}
+
+// send posts the request body to the external service and decodes its
+// response.
+func (c *Controller) send(ctx context.Context, body []byte) (*AdmissionResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status %d from the external service: %s", httpResp.StatusCode, msg)
+	}
+	resp := &AdmissionResponse{}
+	if err := json.NewDecoder(httpResp.Body).Decode(resp); err != nil {
+		return nil, fmt.Errorf("decoding the response of the external service: %w", err)
+	}
+	return resp, nil
+}
+
+func (c *Controller) cachedDecision(key string) (*AdmissionResponse, bool) {
+	c.decisionsLock.Lock()
+	defer c.decisionsLock.Unlock()
+	d, found := c.decisions[key]
+	if !found || !c.clock.Now().Before(d.expiresAt) {
+		return nil, false
+	}
+	resp := d.response
+	return &resp, true
+}
+
+func (c *Controller) storeDecision(key string, resp *AdmissionResponse) {
+	c.decisionsLock.Lock()
+	defer c.decisionsLock.Unlock()
+	now := c.clock.Now()
+	for k, d := range c.decisions {
+		if !now.Before(d.expiresAt) {
+			delete(c.decisions, k)
+		}
+	}
+	c.decisions[key] = cachedDecision{response: *resp, expiresAt: now.Add(decisionTTL)}
+}
+
+func (c *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	// The reconciler waits for the external service, up to the timeout, so
+	// the workloads are reviewed concurrently.
+	concurrency := mgr.GetControllerOptions().GroupKindConcurrency[kueue.GroupVersion.WithKind("Workload").GroupKind().String()]
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("external-admission").
+		For(&kueue.Workload{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: concurrency,
+		}).
+		Complete(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("external-admission-check").
+		For(&kueue.AdmissionCheck{}).
+		Complete(&acReconciler{client: c.client})
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaladmission
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestReconcile(t *testing.T) {
+	admission := utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").Obj()
+	baseWorkload := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(admission).
+		AdmissionChecks(
+			kueue.AdmissionCheckState{Name: "external", State: kueue.CheckStatePending},
+			kueue.AdmissionCheckState{Name: "other", State: kueue.CheckStatePending},
+		)
+	checks := []kueue.AdmissionCheck{
+		*utiltesting.MakeAdmissionCheck("external").ControllerName(ControllerName).Obj(),
+		*utiltesting.MakeAdmissionCheck("other").ControllerName("other-controller").Obj(),
+	}
+
+	cases := map[string]struct {
+		workload       *kueue.Workload
+		response       *AdmissionResponse
+		statusCode     int
+		wantRequest    *AdmissionRequest
+		wantChecks     []kueue.AdmissionCheckState
+		wantErr        bool
+		wantEventTypes []string
+	}{
+		"allowed": {
+			workload: baseWorkload.Clone().Obj(),
+			response: &AdmissionResponse{Allowed: true, Reason: "capacity is planned"},
+			wantRequest: &AdmissionRequest{
+				Workload:  WorkloadReference{Namespace: "ns", Name: "wl"},
+				Admission: *admission,
+			},
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "external", State: kueue.CheckStateReady, Message: "capacity is planned"},
+				{Name: "other", State: kueue.CheckStatePending},
+			},
+			wantEventTypes: []string{corev1.EventTypeNormal},
+		},
+		"denied": {
+			workload: baseWorkload.Clone().Obj(),
+			response: &AdmissionResponse{Allowed: false, Reason: "no capacity until tomorrow"},
+			wantRequest: &AdmissionRequest{
+				Workload:  WorkloadReference{Namespace: "ns", Name: "wl"},
+				Admission: *admission,
+			},
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "external", State: kueue.CheckStateRetry, Message: "no capacity until tomorrow"},
+				{Name: "other", State: kueue.CheckStatePending},
+			},
+			wantEventTypes: []string{corev1.EventTypeWarning},
+		},
+		"denied without reason": {
+			workload: baseWorkload.Clone().Obj(),
+			response: &AdmissionResponse{},
+			wantRequest: &AdmissionRequest{
+				Workload:  WorkloadReference{Namespace: "ns", Name: "wl"},
+				Admission: *admission,
+			},
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "external", State: kueue.CheckStateRetry, Message: DeniedMessage},
+				{Name: "other", State: kueue.CheckStatePending},
+			},
+			wantEventTypes: []string{corev1.EventTypeWarning},
+		},
+		"service error": {
+			workload:   baseWorkload.Clone().Obj(),
+			statusCode: http.StatusInternalServerError,
+			wantRequest: &AdmissionRequest{
+				Workload:  WorkloadReference{Namespace: "ns", Name: "wl"},
+				Admission: *admission,
+			},
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "external", State: kueue.CheckStatePending},
+				{Name: "other", State: kueue.CheckStatePending},
+			},
+			wantErr: true,
+		},
+		"check already decided": {
+			workload: baseWorkload.Clone().
+				AdmissionChecks(
+					kueue.AdmissionCheckState{Name: "external", State: kueue.CheckStateReady},
+					kueue.AdmissionCheckState{Name: "other", State: kueue.CheckStatePending},
+				).
+				Obj(),
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "external", State: kueue.CheckStateReady},
+				{Name: "other", State: kueue.CheckStatePending},
+			},
+		},
+		"without quota reservation": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				AdmissionChecks(kueue.AdmissionCheckState{Name: "external", State: kueue.CheckStatePending}).
+				Obj(),
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "external", State: kueue.CheckStatePending},
+			},
+		},
+		"requeued after a denial": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				AdmissionChecks(
+					kueue.AdmissionCheckState{Name: "external", State: kueue.CheckStateRetry, Message: "no capacity until tomorrow"},
+					kueue.AdmissionCheckState{Name: "other", State: kueue.CheckStateRetry},
+				).
+				Obj(),
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "external", State: kueue.CheckStatePending, Message: "no capacity until tomorrow"},
+				{Name: "other", State: kueue.CheckStateRetry},
+			},
+		},
+		"deactivated after a denial": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Active(false).
+				AdmissionChecks(kueue.AdmissionCheckState{Name: "external", State: kueue.CheckStateRetry, Message: "no capacity until tomorrow"}).
+				Obj(),
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "external", State: kueue.CheckStateRetry, Message: "no capacity until tomorrow"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotRequest *AdmissionRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRequest = &AdmissionRequest{}
+				if err := json.NewDecoder(r.Body).Decode(gotRequest); err != nil {
+					t.Errorf("Failed to decode the request: %v", err)
+				}
+				if tc.statusCode != 0 {
+					w.WriteHeader(tc.statusCode)
+					return
+				}
+				if err := json.NewEncoder(w).Encode(tc.response); err != nil {
+					t.Errorf("Failed to encode the response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			ctx, _ := utiltesting.ContextWithLog(t)
+			cl := utiltesting.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: utiltesting.TreatSSAAsStrategicMerge}).
+				WithObjects(tc.workload).
+				WithStatusSubresource(tc.workload).
+				WithLists(&kueue.AdmissionCheckList{Items: checks}).
+				Build()
+			recorder := &utiltesting.EventRecorder{}
+			controller := NewController(cl, recorder, server.URL, time.Second)
+
+			_, err := controller.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(tc.workload)})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Unexpected error, want error %t, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.wantRequest, gotRequest, cmpopts.IgnoreFields(WorkloadReference{}, "UID")); diff != "" {
+				t.Errorf("Unexpected request to the external service (-want,+got):\n%s", diff)
+			}
+
+			gotWl := &kueue.Workload{}
+			if err := cl.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "wl"}, gotWl); err != nil {
+				t.Fatalf("Failed to get the workload: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantChecks, gotWl.Status.AdmissionChecks, cmpopts.IgnoreFields(kueue.AdmissionCheckState{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected admission checks (-want,+got):\n%s", diff)
+			}
+			var gotEventTypes []string
+			for _, e := range recorder.RecordedEvents {
+				gotEventTypes = append(gotEventTypes, e.EventType)
+			}
+			if diff := cmp.Diff(tc.wantEventTypes, gotEventTypes); diff != "" {
+				t.Errorf("Unexpected events (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileReusesDecisions(t *testing.T) {
+	admission := utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").Obj()
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(admission).
+		AdmissionChecks(kueue.AdmissionCheckState{Name: "external", State: kueue.CheckStatePending}).
+		Obj()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := json.NewEncoder(w).Encode(&AdmissionResponse{Reason: "no capacity until tomorrow"}); err != nil {
+			t.Errorf("Failed to encode the response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cl := utiltesting.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: utiltesting.TreatSSAAsStrategicMerge}).
+		WithObjects(wl).
+		WithStatusSubresource(wl).
+		WithLists(&kueue.AdmissionCheckList{Items: []kueue.AdmissionCheck{
+			*utiltesting.MakeAdmissionCheck("external").ControllerName(ControllerName).Obj(),
+		}}).
+		Build()
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	controller := NewController(cl, &utiltesting.EventRecorder{}, server.URL, time.Second)
+	controller.clock = fakeClock
+
+	reconcileWithPendingChecks := func() {
+		t.Helper()
+		gotWl := &kueue.Workload{}
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(wl), gotWl); err != nil {
+			t.Fatalf("Failed to get the workload: %v", err)
+		}
+		gotWl.Status.AdmissionChecks = wl.Status.AdmissionChecks
+		if err := cl.Status().Update(ctx, gotWl); err != nil {
+			t.Fatalf("Failed to reset the admission checks: %v", err)
+		}
+		if _, err := controller.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(wl)}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	reconcileWithPendingChecks()
+	reconcileWithPendingChecks()
+	if requests != 1 {
+		t.Errorf("Unexpected number of requests to the external service within the TTL, want 1, got %d", requests)
+	}
+
+	fakeClock.Step(decisionTTL)
+	reconcileWithPendingChecks()
+	if requests != 2 {
+		t.Errorf("Unexpected number of requests to the external service after the TTL, want 2, got %d", requests)
+	}
+}
+
+func TestAdmissionCheckReconcile(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	ac := utiltesting.MakeAdmissionCheck("external").ControllerName(ControllerName).Obj()
+	cl := utiltesting.NewClientBuilder().WithObjects(ac).WithStatusSubresource(ac).Build()
+	reconciler := &acReconciler{client: cl}
+	if _, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(ac)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gotAc := &kueue.AdmissionCheck{}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(ac), gotAc); err != nil {
+		t.Fatalf("Failed to get the admission check: %v", err)
+	}
+	wantConditions := []metav1.Condition{{
+		Type:    kueue.AdmissionCheckActive,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Active",
+		Message: "The admission check is active",
+	}}
+	if diff := cmp.Diff(wantConditions, gotAc.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("Unexpected conditions (-want,+got):\n%s", diff)
+	}
+}
@@ -25,6 +25,10 @@ const (
 	// Deprecated: Use QueueLabel as a label key.
 	QueueAnnotation = QueueLabel
 
+	// DefaultQueueNamespaceLabel is the default label key of the namespaces
+	// holding the name of the queue for the jobs created in them without one.
+	DefaultQueueNamespaceLabel = "kueue.x-k8s.io/default-queue"
+
 	// PrebuiltWorkloadLabel is the label key of the job holding the name of the pre-built workload to use.
 	PrebuiltWorkloadLabel = "kueue.x-k8s.io/prebuilt-workload-name"
 
@@ -32,11 +36,20 @@ const (
 	// the owner job.
 	JobUIDLabel = "kueue.x-k8s.io/job-uid"
 
+	// PodSetsHashLabel is the label key in the workload that holds a hash of
+	// its podSets, used to reject duplicates of the workloads of the same owner.
+	PodSetsHashLabel = "kueue.x-k8s.io/podsets-hash"
+
 	// WorkloadPriorityClassLabel is the label key in the workload that holds the
 	// workloadPriorityClass name.
 	// This label is always mutable because it might be useful for the preemption.
 	WorkloadPriorityClassLabel = "kueue.x-k8s.io/priority-class"
 
+	// ActiveDeadlineSecondsAnnotation is the annotation key of the job holding
+	// the activeDeadlineSeconds of its workload, the time it can remain pending
+	// since the creation of the job.
+	ActiveDeadlineSecondsAnnotation = "kueue.x-k8s.io/active-deadline-seconds"
+
 	// ProvReqAnnotationPrefix is the prefix for annotations that should be pass to ProvisioningRequest as Parameters.
 	ProvReqAnnotationPrefix = "provreq.kueue.x-k8s.io/"
 )
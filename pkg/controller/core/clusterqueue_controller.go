@@ -18,6 +18,8 @@ package core
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -28,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
@@ -46,6 +49,8 @@ import (
 	"sigs.k8s.io/kueue/pkg/features"
 	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
+	"sigs.k8s.io/kueue/pkg/resources"
+	"sigs.k8s.io/kueue/pkg/util/quotawindow"
 	"sigs.k8s.io/kueue/pkg/util/resource"
 	"sigs.k8s.io/kueue/pkg/util/slices"
 	"sigs.k8s.io/kueue/pkg/workload"
@@ -63,6 +68,7 @@ type ClusterQueueReconciler struct {
 	log                                  logr.Logger
 	qManager                             *queue.Manager
 	cache                                *cache.Cache
+	recorder                             record.EventRecorder
 	snapshotsQueue                       workqueue.Interface
 	wlUpdateCh                           chan event.GenericEvent
 	rfUpdateCh                           chan event.GenericEvent
@@ -126,6 +132,7 @@ func NewClusterQueueReconciler(
 	client client.Client,
 	qMgr *queue.Manager,
 	cache *cache.Cache,
+	recorder record.EventRecorder,
 	opts ...ClusterQueueReconcilerOption,
 ) *ClusterQueueReconciler {
 	options := defaultCQOptions
@@ -137,6 +144,7 @@ func NewClusterQueueReconciler(
 		log:                                  ctrl.Log.WithName("cluster-queue-reconciler"),
 		qManager:                             qMgr,
 		cache:                                cache,
+		recorder:                             recorder,
 		snapshotsQueue:                       workqueue.New(),
 		wlUpdateCh:                           make(chan event.GenericEvent, updateChBuffer),
 		rfUpdateCh:                           make(chan event.GenericEvent, updateChBuffer),
@@ -193,12 +201,30 @@ func (r *ClusterQueueReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}
 
+	// If the ClusterQueue is within the grace period for its missing flavors,
+	// reconcile again once it elapses, to mark the ClusterQueue inactive.
+	requeueAfter := r.cache.RecheckClusterQueueStatus(cqObj.Name)
+	// Apply the quotas of the active quota window, and reconcile again once
+	// the next window starts or ends.
+	quotasChanged, untilNextWindow := r.cache.RefreshQuotaWindow(cqObj.Name)
+	if quotasChanged {
+		log.V(2).Info("Applied the quotas of the active quota window")
+		if r.reportResourceMetrics {
+			recordResourceMetrics(&cqObj, r.cache.Now())
+		}
+		// The quotas of the ClusterQueue can be borrowed by the rest of its
+		// cohort.
+		r.qManager.QueueInadmissibleWorkloads(ctx, sets.New(append(r.cache.CohortPeers(cqObj.Name), cqObj.Name)...))
+	}
+	if untilNextWindow > 0 && (requeueAfter == 0 || untilNextWindow < requeueAfter) {
+		requeueAfter = untilNextWindow
+	}
 	newCQObj := cqObj.DeepCopy()
 	cqCondition, reason, msg := r.cache.ClusterQueueReadiness(newCQObj.Name)
 	if err := r.updateCqStatusIfChanged(ctx, newCQObj, cqCondition, reason, msg); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *ClusterQueueReconciler) NotifyWorkloadUpdate(oldWl, newWl *kueue.Workload) {
@@ -220,7 +246,8 @@ func (r *ClusterQueueReconciler) notifyWatchers(oldCQ, newCQ *kueue.ClusterQueue
 	}
 }
 
-// NotifyResourceFlavorUpdate ignores updates since they have no impact on the ClusterQueue's readiness.
+// NotifyResourceFlavorUpdate ignores the updates that don't cordon or uncordon
+// the flavor, since they have no impact on the ClusterQueue's conditions.
 func (r *ClusterQueueReconciler) NotifyResourceFlavorUpdate(oldRF, newRF *kueue.ResourceFlavor) {
 	// if oldRF is nil, it's a create event.
 	if oldRF == nil {
@@ -233,6 +260,10 @@ func (r *ClusterQueueReconciler) NotifyResourceFlavorUpdate(oldRF, newRF *kueue.
 		r.rfUpdateCh <- event.GenericEvent{Object: oldRF}
 		return
 	}
+
+	if oldRF.Spec.Cordoned != newRF.Spec.Cordoned {
+		r.rfUpdateCh <- event.GenericEvent{Object: newRF}
+	}
 }
 
 func (r *ClusterQueueReconciler) NotifyAdmissionCheckUpdate(oldAc, newAc *kueue.AdmissionCheck) {
@@ -267,7 +298,7 @@ func (r *ClusterQueueReconciler) Create(e event.CreateEvent) bool {
 	}
 
 	if r.reportResourceMetrics {
-		recordResourceMetrics(cq)
+		recordResourceMetrics(cq, r.cache.Now())
 	}
 
 	return true
@@ -321,7 +352,7 @@ func (r *ClusterQueueReconciler) Update(e event.UpdateEvent) bool {
 	}
 
 	if r.reportResourceMetrics {
-		updateResourceMetrics(oldCq, newCq)
+		updateResourceMetrics(oldCq, newCq, r.cache.Now())
 	}
 	return true
 }
@@ -331,9 +362,12 @@ func (r *ClusterQueueReconciler) Generic(e event.GenericEvent) bool {
 	return true
 }
 
-func recordResourceMetrics(cq *kueue.ClusterQueue) {
-	for rgi := range cq.Spec.ResourceGroups {
-		rg := &cq.Spec.ResourceGroups[rgi]
+// recordResourceMetrics reports the quotas of the ClusterQueue, with the
+// quota window that is active at the given time applied, and its usage.
+func recordResourceMetrics(cq *kueue.ClusterQueue, now time.Time) {
+	resourceGroups := quotawindow.ApplyQuotas(cq.Spec.ResourceGroups, quotawindow.Active(cq.Spec.QuotaWindows, now))
+	for rgi := range resourceGroups {
+		rg := &resourceGroups[rgi]
 		for fqi := range rg.Flavors {
 			fq := &rg.Flavors[fqi]
 			for ri := range fq.Resources {
@@ -346,24 +380,32 @@ func recordResourceMetrics(cq *kueue.ClusterQueue) {
 		}
 	}
 
-	for fri := range cq.Status.FlavorsReservation {
-		fr := &cq.Status.FlavorsReservation[fri]
-		for ri := range fr.Resources {
-			r := &fr.Resources[ri]
-			metrics.ReportClusterQueueResourceReservations(cq.Spec.Cohort, cq.Name, string(fr.Name), string(r.Name), resource.QuantityToFloat(&r.Total))
-		}
-	}
-
+	usage := make(map[resources.FlavorResource]float64)
 	for fui := range cq.Status.FlavorsUsage {
 		fu := &cq.Status.FlavorsUsage[fui]
 		for ri := range fu.Resources {
 			r := &fu.Resources[ri]
-			metrics.ReportClusterQueueResourceUsage(cq.Spec.Cohort, cq.Name, string(fu.Name), string(r.Name), resource.QuantityToFloat(&r.Total))
+			total := resource.QuantityToFloat(&r.Total)
+			usage[resources.FlavorResource{Flavor: fu.Name, Resource: r.Name}] = total
+			metrics.ReportClusterQueueResourceUsage(cq.Spec.Cohort, cq.Name, string(fu.Name), string(r.Name), total)
+		}
+	}
+
+	for fri := range cq.Status.FlavorsReservation {
+		fr := &cq.Status.FlavorsReservation[fri]
+		for ri := range fr.Resources {
+			r := &fr.Resources[ri]
+			total := resource.QuantityToFloat(&r.Total)
+			metrics.ReportClusterQueueResourceReservations(cq.Spec.Cohort, cq.Name, string(fr.Name), string(r.Name), total)
+			// The quota reserved by workloads that are still waiting for their
+			// admission checks is not part of the admitted usage.
+			reserved := max(total-usage[resources.FlavorResource{Flavor: fr.Name, Resource: r.Name}], 0)
+			metrics.ReportClusterQueueReservedResources(cq.Spec.Cohort, cq.Name, string(fr.Name), string(r.Name), reserved)
 		}
 	}
 }
 
-func updateResourceMetrics(oldCq, newCq *kueue.ClusterQueue) {
+func updateResourceMetrics(oldCq, newCq *kueue.ClusterQueue, now time.Time) {
 	// if the cohort changed, drop all the old metrics
 	if oldCq.Spec.Cohort != newCq.Spec.Cohort {
 		metrics.ClearClusterQueueResourceMetrics(oldCq.Name)
@@ -371,7 +413,7 @@ func updateResourceMetrics(oldCq, newCq *kueue.ClusterQueue) {
 		// selective remove
 		clearOldResourceQuotas(oldCq, newCq)
 	}
-	recordResourceMetrics(newCq)
+	recordResourceMetrics(newCq, now)
 }
 
 func clearOldResourceQuotas(oldCq, newCq *kueue.ClusterQueue) {
@@ -446,10 +488,13 @@ func clearOldResourceQuotas(oldCq, newCq *kueue.ClusterQueue) {
 
 // cqWorkloadHandler signals the controller to reconcile the ClusterQueue
 // associated to the workload in the event.
+// When the workload holds a quota reservation, the other ClusterQueues in the
+// cohort are reconciled too, as the quota available to them changed.
 // Since the events come from a channel Source, only the Generic handler will
 // receive events.
 type cqWorkloadHandler struct {
 	qManager *queue.Manager
+	cache    *cache.Cache
 }
 
 func (h *cqWorkloadHandler) Create(context.Context, event.CreateEvent, workqueue.RateLimitingInterface) {
@@ -464,8 +509,18 @@ func (h *cqWorkloadHandler) Delete(context.Context, event.DeleteEvent, workqueue
 func (h *cqWorkloadHandler) Generic(_ context.Context, e event.GenericEvent, q workqueue.RateLimitingInterface) {
 	w := e.Object.(*kueue.Workload)
 	req := h.requestForWorkloadClusterQueue(w)
-	if req != nil {
-		q.AddAfter(*req, constants.UpdatesBatchPeriod)
+	if req == nil {
+		return
+	}
+	q.AddAfter(*req, constants.UpdatesBatchPeriod)
+	if !workload.HasQuotaReservation(w) {
+		return
+	}
+	for _, peer := range h.cache.CohortPeers(req.Name) {
+		q.AddAfter(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: peer,
+			}}, constants.UpdatesBatchPeriod)
 	}
 }
 
@@ -611,6 +666,7 @@ func (h *cqSnapshotHandler) Generic(_ context.Context, e event.GenericEvent, q w
 func (r *ClusterQueueReconciler) SetupWithManager(mgr ctrl.Manager, cfg *config.Configuration) error {
 	wHandler := cqWorkloadHandler{
 		qManager: r.qManager,
+		cache:    r.cache,
 	}
 	nsHandler := cqNamespaceHandler{
 		qManager: r.qManager,
@@ -662,6 +718,7 @@ func (r *ClusterQueueReconciler) updateCqStatusIfChanged(
 	cq.Status.AdmittedWorkloads = int32(stats.AdmittedWorkloads)
 	cq.Status.PendingWorkloads = int32(pendingWorkloads)
 	cq.Status.PendingWorkloadsStatus = r.getWorkloadsStatus(cq)
+	cq.Status.HeadAdmissionEstimate = r.getHeadAdmissionEstimate(cq)
 	meta.SetStatusCondition(&cq.Status.Conditions, metav1.Condition{
 		Type:               kueue.ClusterQueueActive,
 		Status:             conditionStatus,
@@ -669,6 +726,14 @@ func (r *ClusterQueueReconciler) updateCqStatusIfChanged(
 		Message:            msg,
 		ObservedGeneration: cq.Generation,
 	})
+	usageRatios := resourceUsageRatios(cq)
+	if r.reportResourceMetrics {
+		for fr, ratio := range usageRatios {
+			metrics.ReportClusterQueueResourceUsageRatio(cq.Name, string(fr.Flavor), string(fr.Resource), ratio)
+		}
+	}
+	reachedCapacity := setResourceNearCapacityCondition(cq, usageRatios)
+	setFlavorsCordonedCondition(cq, r.cache.ClusterQueueCordonedFlavors(cq.Name))
 	if r.fairSharingEnabled {
 		if r.reportResourceMetrics {
 			metrics.ReportClusterQueueWeightedShare(cq.Name, stats.WeightedShare)
@@ -681,16 +746,124 @@ func (r *ClusterQueueReconciler) updateCqStatusIfChanged(
 		cq.Status.FairSharing = nil
 	}
 	if !equality.Semantic.DeepEqual(cq.Status, oldStatus) {
-		return r.client.Status().Update(ctx, cq)
+		if err := r.client.Status().Update(ctx, cq); err != nil {
+			return err
+		}
+		if reachedCapacity {
+			cond := meta.FindStatusCondition(cq.Status.Conditions, kueue.ClusterQueueResourceNearCapacity)
+			r.recorder.Eventf(cq, corev1.EventTypeWarning, kueue.ClusterQueueResourceNearCapacity, "%s", cond.Message)
+		}
 	}
 	return nil
 }
 
+// resourceUsageRatios returns the ratio of the usage to the nominal quota of
+// each resource with a non-zero nominal quota.
+func resourceUsageRatios(cq *kueue.ClusterQueue) map[resources.FlavorResource]float64 {
+	usage := make(map[resources.FlavorResource]float64)
+	for _, fu := range cq.Status.FlavorsUsage {
+		for _, ru := range fu.Resources {
+			usage[resources.FlavorResource{Flavor: fu.Name, Resource: ru.Name}] = resource.QuantityToFloat(&ru.Total)
+		}
+	}
+	ratios := make(map[resources.FlavorResource]float64)
+	for _, rg := range cq.Spec.ResourceGroups {
+		for _, fq := range rg.Flavors {
+			for _, rq := range fq.Resources {
+				nominal := resource.QuantityToFloat(&rq.NominalQuota)
+				if nominal == 0 {
+					continue
+				}
+				fr := resources.FlavorResource{Flavor: fq.Name, Resource: rq.Name}
+				ratios[fr] = usage[fr] / nominal
+			}
+		}
+	}
+	return ratios
+}
+
+// setResourceNearCapacityCondition sets the ResourceNearCapacity condition
+// based on the warnThreshold of the flavors, or removes it if no flavor has
+// a threshold. It returns whether the condition just became true.
+func setResourceNearCapacityCondition(cq *kueue.ClusterQueue, usageRatios map[resources.FlavorResource]float64) bool {
+	var hasThreshold bool
+	var reached []string
+	for _, rg := range cq.Spec.ResourceGroups {
+		for _, fq := range rg.Flavors {
+			if fq.WarnThreshold == nil {
+				continue
+			}
+			hasThreshold = true
+			for _, rq := range fq.Resources {
+				ratio, found := usageRatios[resources.FlavorResource{Flavor: fq.Name, Resource: rq.Name}]
+				if found && ratio*100 >= float64(*fq.WarnThreshold) {
+					reached = append(reached, fmt.Sprintf("%s of flavor %s is at %d%% of the nominal quota", rq.Name, fq.Name, int(ratio*100)))
+				}
+			}
+		}
+	}
+	if !hasThreshold {
+		meta.RemoveStatusCondition(&cq.Status.Conditions, kueue.ClusterQueueResourceNearCapacity)
+		return false
+	}
+	wasNearCapacity := meta.IsStatusConditionTrue(cq.Status.Conditions, kueue.ClusterQueueResourceNearCapacity)
+	cond := metav1.Condition{
+		Type:               kueue.ClusterQueueResourceNearCapacity,
+		Status:             metav1.ConditionFalse,
+		Reason:             "BelowThreshold",
+		Message:            "The usage of the resources is below the warnThreshold of their flavors",
+		ObservedGeneration: cq.Generation,
+	}
+	if len(reached) > 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "ThresholdReached"
+		cond.Message = strings.Join(reached, "; ")
+	}
+	meta.SetStatusCondition(&cq.Status.Conditions, cond)
+	return !wasNearCapacity && len(reached) > 0
+}
+
+// setFlavorsCordonedCondition sets the FlavorsCordoned condition when some
+// of the flavors of the ClusterQueue are cordoned, or removes it otherwise.
+func setFlavorsCordonedCondition(cq *kueue.ClusterQueue, cordoned []kueue.ResourceFlavorReference) {
+	if len(cordoned) == 0 {
+		meta.RemoveStatusCondition(&cq.Status.Conditions, kueue.ClusterQueueFlavorsCordoned)
+		return
+	}
+	names := make([]string, len(cordoned))
+	for i, name := range cordoned {
+		names[i] = string(name)
+	}
+	meta.SetStatusCondition(&cq.Status.Conditions, metav1.Condition{
+		Type:               kueue.ClusterQueueFlavorsCordoned,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Cordoned",
+		Message:            fmt.Sprintf("No new workloads can be admitted in the cordoned flavors: %s", strings.Join(names, ", ")),
+		ObservedGeneration: cq.Generation,
+	})
+}
+
 // Taking snapshot of cluster queue is enabled when maxcount non-zero
 func (r *ClusterQueueReconciler) isVisibilityEnabled() bool {
 	return features.Enabled(features.QueueVisibility) && r.queueVisibilityClusterQueuesMaxCount > 0
 }
 
+func (r *ClusterQueueReconciler) getHeadAdmissionEstimate(cq *kueue.ClusterQueue) *kueue.HeadAdmissionEstimate {
+	if !features.Enabled(features.HeadAdmissionEstimate) {
+		return nil
+	}
+	head := r.qManager.PendingHead(cq.Name)
+	if head == nil {
+		return nil
+	}
+	estimate, err := r.cache.HeadAdmissionEstimate(cq, head)
+	if err != nil {
+		r.log.Error(err, "Failed estimating the admission of the head workload")
+		return nil
+	}
+	return estimate
+}
+
 func (r *ClusterQueueReconciler) getWorkloadsStatus(cq *kueue.ClusterQueue) *kueue.ClusterQueuePendingWorkloadsStatus {
 	if !r.isVisibilityEnabled() {
 		return nil
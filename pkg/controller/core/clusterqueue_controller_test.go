@@ -18,6 +18,7 @@ package core
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -26,7 +27,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/component-base/metrics/testutil"
 	"k8s.io/utils/ptr"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
@@ -247,6 +250,227 @@ func TestUpdateCqStatusIfChanged(t *testing.T) {
 	}
 }
 
+func TestResourceNearCapacity(t *testing.T) {
+	cqKey := types.NamespacedName{Name: "cq"}
+	testCases := map[string]struct {
+		warnThreshold  *int32
+		cqStatus       kueue.ClusterQueueStatus
+		wantConditions []metav1.Condition
+		wantEvents     []utiltesting.EventRecord
+	}{
+		"no threshold": {
+			wantConditions: []metav1.Condition{activeCondition()},
+		},
+		"usage below the threshold": {
+			warnThreshold: ptr.To[int32](90),
+			wantConditions: []metav1.Condition{
+				activeCondition(),
+				{
+					Type:               kueue.ClusterQueueResourceNearCapacity,
+					Status:             metav1.ConditionFalse,
+					Reason:             "BelowThreshold",
+					Message:            "The usage of the resources is below the warnThreshold of their flavors",
+					ObservedGeneration: 1,
+				},
+			},
+		},
+		"usage reaches the threshold": {
+			warnThreshold: ptr.To[int32](80),
+			wantConditions: []metav1.Condition{
+				activeCondition(),
+				{
+					Type:               kueue.ClusterQueueResourceNearCapacity,
+					Status:             metav1.ConditionTrue,
+					Reason:             "ThresholdReached",
+					Message:            "cpu of flavor default is at 80% of the nominal quota",
+					ObservedGeneration: 1,
+				},
+			},
+			wantEvents: []utiltesting.EventRecord{{
+				Key:       cqKey,
+				EventType: corev1.EventTypeWarning,
+				Reason:    kueue.ClusterQueueResourceNearCapacity,
+				Message:   "cpu of flavor default is at 80% of the nominal quota",
+			}},
+		},
+		"usage was already above the threshold": {
+			warnThreshold: ptr.To[int32](80),
+			cqStatus: kueue.ClusterQueueStatus{
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ClusterQueueResourceNearCapacity,
+					Status:  metav1.ConditionTrue,
+					Reason:  "ThresholdReached",
+					Message: "cpu of flavor default is at 90% of the nominal quota",
+				}},
+			},
+			wantConditions: []metav1.Condition{
+				{
+					Type:               kueue.ClusterQueueResourceNearCapacity,
+					Status:             metav1.ConditionTrue,
+					Reason:             "ThresholdReached",
+					Message:            "cpu of flavor default is at 80% of the nominal quota",
+					ObservedGeneration: 1,
+				},
+				activeCondition(),
+			},
+		},
+		"threshold removed": {
+			cqStatus: kueue.ClusterQueueStatus{
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ClusterQueueResourceNearCapacity,
+					Status:  metav1.ConditionTrue,
+					Reason:  "ThresholdReached",
+					Message: "cpu of flavor default is at 80% of the nominal quota",
+				}},
+			},
+			wantConditions: []metav1.Condition{activeCondition()},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fq := utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10")
+			if tc.warnThreshold != nil {
+				fq.WarnThreshold(*tc.warnThreshold)
+			}
+			cq := utiltesting.MakeClusterQueue(cqKey.Name).
+				ResourceGroup(*fq.Obj()).
+				Generation(1).
+				Obj()
+			cq.Status = tc.cqStatus
+			wl := utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "8").
+				ReserveQuota(utiltesting.MakeAdmission(cq.Name).Assignment(corev1.ResourceCPU, "default", "8").Obj()).
+				Admitted(true).
+				Obj()
+			ctx, log := utiltesting.ContextWithLog(t)
+			cl := utiltesting.NewClientBuilder().WithObjects(cq).WithStatusSubresource(cq).Build()
+			cqCache := cache.New(cl)
+			qManager := queue.NewManager(cl, cqCache)
+			if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+				t.Fatalf("Inserting clusterQueue in cache: %v", err)
+			}
+			if err := qManager.AddClusterQueue(ctx, cq); err != nil {
+				t.Fatalf("Inserting clusterQueue in manager: %v", err)
+			}
+			cqCache.AddOrUpdateWorkload(wl)
+			recorder := &utiltesting.EventRecorder{}
+			r := &ClusterQueueReconciler{
+				client:                cl,
+				log:                   log,
+				cache:                 cqCache,
+				qManager:              qManager,
+				recorder:              recorder,
+				reportResourceMetrics: true,
+			}
+			defer metrics.ClearClusterQueueResourceMetrics(cq.Name)
+
+			if err := r.updateCqStatusIfChanged(ctx, cq, metav1.ConditionTrue, "Ready", "Can admit new workloads"); err != nil {
+				t.Fatalf("Updating the ClusterQueue status: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantConditions, cq.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected conditions (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantEvents, recorder.RecordedEvents, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected events (-want,+got):\n%s", diff)
+			}
+			gotRatio, err := testutil.GetGaugeMetricValue(metrics.ClusterQueueResourceUsageRatio.WithLabelValues(cq.Name, "default", "cpu"))
+			if err != nil {
+				t.Fatalf("Getting the usage ratio: %v", err)
+			}
+			if gotRatio != 0.8 {
+				t.Errorf("Unexpected usage ratio, want 0.8, got %v", gotRatio)
+			}
+		})
+	}
+}
+
+func TestFlavorsCordoned(t *testing.T) {
+	testCases := map[string]struct {
+		cordoned       []string
+		cqStatus       kueue.ClusterQueueStatus
+		wantConditions []metav1.Condition
+	}{
+		"no cordoned flavors": {
+			wantConditions: []metav1.Condition{activeCondition()},
+		},
+		"cordoned flavors": {
+			cordoned: []string{"on-demand", "spot"},
+			wantConditions: []metav1.Condition{
+				activeCondition(),
+				{
+					Type:               kueue.ClusterQueueFlavorsCordoned,
+					Status:             metav1.ConditionTrue,
+					Reason:             "Cordoned",
+					Message:            "No new workloads can be admitted in the cordoned flavors: on-demand, spot",
+					ObservedGeneration: 1,
+				},
+			},
+		},
+		"flavors uncordoned": {
+			cqStatus: kueue.ClusterQueueStatus{
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ClusterQueueFlavorsCordoned,
+					Status:  metav1.ConditionTrue,
+					Reason:  "Cordoned",
+					Message: "No new workloads can be admitted in the cordoned flavors: spot",
+				}},
+			},
+			wantConditions: []metav1.Condition{activeCondition()},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cq := utiltesting.MakeClusterQueue("cq").
+				ResourceGroup(
+					*utiltesting.MakeFlavorQuotas("on-demand").Resource(corev1.ResourceCPU, "10").Obj(),
+					*utiltesting.MakeFlavorQuotas("spot").Resource(corev1.ResourceCPU, "10").Obj(),
+				).
+				Generation(1).
+				Obj()
+			cq.Status = tc.cqStatus
+			ctx, log := utiltesting.ContextWithLog(t)
+			cl := utiltesting.NewClientBuilder().WithObjects(cq).WithStatusSubresource(cq).Build()
+			cqCache := cache.New(cl)
+			qManager := queue.NewManager(cl, cqCache)
+			for _, flavor := range []string{"on-demand", "spot"} {
+				cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor(flavor).Cordoned(slices.Contains(tc.cordoned, flavor)).Obj())
+			}
+			if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+				t.Fatalf("Inserting clusterQueue in cache: %v", err)
+			}
+			if err := qManager.AddClusterQueue(ctx, cq); err != nil {
+				t.Fatalf("Inserting clusterQueue in manager: %v", err)
+			}
+			r := &ClusterQueueReconciler{
+				client:   cl,
+				log:      log,
+				cache:    cqCache,
+				qManager: qManager,
+				recorder: &utiltesting.EventRecorder{},
+			}
+
+			if err := r.updateCqStatusIfChanged(ctx, cq, metav1.ConditionTrue, "Ready", "Can admit new workloads"); err != nil {
+				t.Fatalf("Updating the ClusterQueue status: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantConditions, cq.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected conditions (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func activeCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:               kueue.ClusterQueueActive,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Ready",
+		Message:            "Can admit new workloads",
+		ObservedGeneration: 1,
+	}
+}
+
 type cqMetrics struct {
 	NominalDPs   []testingmetrics.MetricDataPoint
 	BorrowingDPs []testingmetrics.MetricDataPoint
@@ -494,14 +718,14 @@ func TestRecordResourceMetrics(t *testing.T) {
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
-			recordResourceMetrics(tc.queue)
+			recordResourceMetrics(tc.queue, time.Now())
 			gotMetrics := allMetricsForQueue(tc.queue.Name)
 			if diff := cmp.Diff(tc.wantMetrics, gotMetrics, opts...); len(diff) != 0 {
 				t.Errorf("Unexpected metrics (-want,+got):\n%s", diff)
 			}
 
 			if tc.updatedQueue != nil {
-				updateResourceMetrics(tc.queue, tc.updatedQueue)
+				updateResourceMetrics(tc.queue, tc.updatedQueue, time.Now())
 				gotMetricsAfterUpdate := allMetricsForQueue(tc.queue.Name)
 				if diff := cmp.Diff(tc.wantUpdatedMetrics, gotMetricsAfterUpdate, opts...); len(diff) != 0 {
 					t.Errorf("Unexpected metrics (-want,+got):\n%s", diff)
@@ -517,6 +741,83 @@ func TestRecordResourceMetrics(t *testing.T) {
 	}
 }
 
+func TestRecordReservedResourcesMetrics(t *testing.T) {
+	cq := utiltesting.MakeClusterQueue("name").
+		Cohort("cohort").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("flavor").Resource(corev1.ResourceCPU, "5").Obj()).
+		Obj()
+	withUsage := func(reservation, usage string) *kueue.ClusterQueue {
+		ret := cq.DeepCopy()
+		ret.Status.FlavorsReservation = []kueue.FlavorUsage{{
+			Name:      "flavor",
+			Resources: []kueue.ResourceUsage{{Name: corev1.ResourceCPU, Total: resource.MustParse(reservation)}},
+		}}
+		ret.Status.FlavorsUsage = []kueue.FlavorUsage{{
+			Name:      "flavor",
+			Resources: []kueue.ResourceUsage{{Name: corev1.ResourceCPU, Total: resource.MustParse(usage)}},
+		}}
+		return ret
+	}
+	reservedDPs := func() []testingmetrics.MetricDataPoint {
+		return testingmetrics.CollectFilteredGaugeVec(metrics.ClusterQueueReservedResources, map[string]string{"cluster_queue": "name"})
+	}
+	defer metrics.ClearClusterQueueResourceMetrics("name")
+
+	waitingForChecks := withUsage("3", "1")
+	recordResourceMetrics(waitingForChecks, time.Now())
+	if diff := cmp.Diff([]testingmetrics.MetricDataPoint{
+		resourceDataPoint("cohort", "name", "flavor", string(corev1.ResourceCPU), 2),
+	}, reservedDPs()); diff != "" {
+		t.Errorf("Unexpected reserved resources (-want,+got):\n%s", diff)
+	}
+
+	allAdmitted := withUsage("3", "3")
+	updateResourceMetrics(waitingForChecks, allAdmitted, time.Now())
+	if diff := cmp.Diff([]testingmetrics.MetricDataPoint{
+		resourceDataPoint("cohort", "name", "flavor", string(corev1.ResourceCPU), 0),
+	}, reservedDPs()); diff != "" {
+		t.Errorf("Unexpected reserved resources after admission (-want,+got):\n%s", diff)
+	}
+
+	updateResourceMetrics(allAdmitted, cq, time.Now())
+	if diff := cmp.Diff([]testingmetrics.MetricDataPoint(nil), reservedDPs(), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Unexpected reserved resources after the workloads finished (-want,+got):\n%s", diff)
+	}
+}
+
+func TestRecordResourceMetricsInQuotaWindow(t *testing.T) {
+	cq := utiltesting.MakeClusterQueue("name").
+		Cohort("cohort").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("flavor").Resource(corev1.ResourceCPU, "5").Obj()).
+		QuotaWindow("night", "0 22 * * *", 8*time.Hour, *utiltesting.MakeFlavorQuotas("flavor").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	defer metrics.ClearClusterQueueResourceMetrics("name")
+
+	cases := map[string]struct {
+		now  time.Time
+		want float64
+	}{
+		"outside the window": {
+			now:  time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+			want: 5,
+		},
+		"in the window": {
+			now:  time.Date(2024, time.March, 1, 23, 0, 0, 0, time.UTC),
+			want: 10,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			recordResourceMetrics(cq, tc.now)
+			if diff := cmp.Diff([]testingmetrics.MetricDataPoint{
+				resourceDataPoint("cohort", "name", "flavor", string(corev1.ResourceCPU), tc.want),
+			}, allMetricsForQueue("name").NominalDPs); diff != "" {
+				t.Errorf("Unexpected nominal quota (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestClusterQueuePendingWorkloadsStatus(t *testing.T) {
 	cqName := "test-cq"
 	lqName := "test-lq"
@@ -582,6 +883,7 @@ func TestClusterQueuePendingWorkloadsStatus(t *testing.T) {
 				cl,
 				qManager,
 				cCache,
+				&utiltesting.EventRecorder{},
 				WithQueueVisibilityUpdateInterval(tc.queueVisibilityUpdateInterval),
 				WithQueueVisibilityClusterQueuesMaxCount(tc.queueVisibilityClusterQueuesMaxCount),
 			)
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	config "sigs.k8s.io/kueue/apis/config/v1beta1"
+	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/queue"
+)
+
+// CohortReconciler reconciles a Cohort object
+type CohortReconciler struct {
+	log      logr.Logger
+	qManager *queue.Manager
+	cache    *cache.Cache
+	client   client.Client
+}
+
+func NewCohortReconciler(
+	client client.Client,
+	qMgr *queue.Manager,
+	cache *cache.Cache,
+) *CohortReconciler {
+	return &CohortReconciler{
+		log:      ctrl.Log.WithName("cohort-reconciler"),
+		cache:    cache,
+		client:   client,
+		qManager: qMgr,
+	}
+}
+
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=cohorts,verbs=get;list;watch
+
+func (r *CohortReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// The cache is kept up to date by the event handlers, there is nothing
+	// else to reconcile.
+	return ctrl.Result{}, nil
+}
+
+func (r *CohortReconciler) Create(e event.CreateEvent) bool {
+	cohort, match := e.Object.(*kueuealpha.Cohort)
+	if !match {
+		return false
+	}
+	r.log.V(2).Info("Cohort create event", "cohort", klog.KObj(cohort))
+	r.qManager.AddOrUpdateCohort(cohort)
+	if cqNames := r.cache.AddOrUpdateCohort(cohort.DeepCopy()); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+	}
+	r.logCycle(cohort)
+	return false
+}
+
+func (r *CohortReconciler) Delete(e event.DeleteEvent) bool {
+	cohort, match := e.Object.(*kueuealpha.Cohort)
+	if !match {
+		return false
+	}
+	r.log.V(2).Info("Cohort delete event", "cohort", klog.KObj(cohort))
+	r.qManager.DeleteCohort(cohort)
+	if cqNames := r.cache.DeleteCohort(cohort); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+	}
+	return false
+}
+
+func (r *CohortReconciler) Update(e event.UpdateEvent) bool {
+	oldCohort, match := e.ObjectOld.(*kueuealpha.Cohort)
+	if !match {
+		return false
+	}
+	newCohort, match := e.ObjectNew.(*kueuealpha.Cohort)
+	if !match {
+		return false
+	}
+	if equality.Semantic.DeepEqual(oldCohort.Spec, newCohort.Spec) {
+		return false
+	}
+	r.log.V(2).Info("Cohort update event", "cohort", klog.KObj(newCohort))
+	r.qManager.AddOrUpdateCohort(newCohort)
+	// Raising the borrowing caps might make the inadmissible workloads fit.
+	if cqNames := r.cache.AddOrUpdateCohort(newCohort.DeepCopy()); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+	}
+	r.logCycle(newCohort)
+	return false
+}
+
+// logCycle reports when the parents of the cohort form a cycle, which the
+// webhook rejects, but which can still be reached when it's disabled or
+// through concurrent updates. The cohorts of the cycle are treated as roots.
+func (r *CohortReconciler) logCycle(cohort *kueuealpha.Cohort) {
+	if cycle := r.cache.CohortCycle(cohort.Name); cycle != nil {
+		r.log.Error(nil, "The parents of the cohort form a cycle, treating its cohorts as roots", "cohort", klog.KObj(cohort), "cycle", cycle)
+	}
+}
+
+func (r *CohortReconciler) Generic(e event.GenericEvent) bool {
+	r.log.V(2).Info("Got generic event", "obj", klog.KObj(e.Object), "kind", e.Object.GetObjectKind().GroupVersionKind())
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CohortReconciler) SetupWithManager(mgr ctrl.Manager, cfg *config.Configuration) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kueuealpha.Cohort{}).
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)}).
+		WithEventFilter(r).
+		Complete(WithLeadingManager(mgr, r, &kueuealpha.Cohort{}, cfg))
+}
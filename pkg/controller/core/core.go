@@ -19,11 +19,13 @@ package core
 import (
 	"time"
 
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
 	"sigs.k8s.io/kueue/pkg/cache"
 	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/features"
 	"sigs.k8s.io/kueue/pkg/queue"
 )
 
@@ -42,6 +44,10 @@ func SetupControllers(mgr ctrl.Manager, qManager *queue.Manager, cc *cache.Cache
 	if err := acRec.SetupWithManager(mgr, cfg); err != nil {
 		return "AdmissionCheck", err
 	}
+	cohortRec := NewCohortReconciler(mgr.GetClient(), qManager, cc)
+	if err := cohortRec.SetupWithManager(mgr, cfg); err != nil {
+		return "Cohort", err
+	}
 	qRec := NewLocalQueueReconciler(mgr.GetClient(), qManager, cc)
 	if err := qRec.SetupWithManager(mgr, cfg); err != nil {
 		return "LocalQueue", err
@@ -56,6 +62,7 @@ func SetupControllers(mgr ctrl.Manager, qManager *queue.Manager, cc *cache.Cache
 		mgr.GetClient(),
 		qManager,
 		cc,
+		mgr.GetEventRecorderFor(constants.ClusterQueueControllerName),
 		WithQueueVisibilityUpdateInterval(queueVisibilityUpdateInterval(cfg)),
 		WithQueueVisibilityClusterQueuesMaxCount(queueVisibilityClusterQueuesMaxCount(cfg)),
 		WithReportResourceMetrics(cfg.Metrics.EnableClusterQueueResources),
@@ -75,9 +82,18 @@ func SetupControllers(mgr ctrl.Manager, qManager *queue.Manager, cc *cache.Cache
 		mgr.GetEventRecorderFor(constants.WorkloadControllerName),
 		WithWorkloadUpdateWatchers(qRec, cqRec),
 		WithWaitForPodsReady(waitForPodsReady(cfg.WaitForPodsReady)),
+		WithAdmissionChecksTimeout(admissionChecksTimeout(cfg.AdmissionChecks)),
 	).SetupWithManager(mgr, cfg); err != nil {
 		return "Workload", err
 	}
+
+	if features.Enabled(features.NodeUnavailableEviction) {
+		if err := NewNodeReconciler(mgr.GetClient(), mgr.GetAPIReader(),
+			mgr.GetEventRecorderFor(constants.NodeControllerName),
+		).SetupWithManager(mgr); err != nil {
+			return "Node", err
+		}
+	}
 	return "", nil
 }
 
@@ -97,6 +113,16 @@ func waitForPodsReady(cfg *configapi.WaitForPodsReady) *waitForPodsReadyConfig {
 	return &result
 }
 
+func admissionChecksTimeout(cfg *configapi.AdmissionChecks) *admissionChecksTimeoutConfig {
+	if cfg == nil || cfg.Timeout == nil || cfg.Timeout.Duration == 0 {
+		return nil
+	}
+	return &admissionChecksTimeoutConfig{
+		timeout: cfg.Timeout.Duration,
+		policy:  ptr.Deref(cfg.TimeoutPolicy, configapi.AdmissionChecksTimeoutRequeue),
+	}
+}
+
 func queueVisibilityUpdateInterval(cfg *configapi.Configuration) time.Duration {
 	if cfg.QueueVisibility != nil {
 		return time.Duration(cfg.QueueVisibility.UpdateIntervalSeconds) * time.Second
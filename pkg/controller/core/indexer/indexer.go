@@ -38,6 +38,9 @@ const (
 	WorkloadQuotaReservedKey   = "status.quotaReserved"
 	WorkloadRuntimeClassKey    = "spec.runtimeClass"
 	OwnerReferenceUID          = "metadata.ownerReferences.uid"
+	PodNodeNameKey             = "spec.nodeName"
+
+	QueueCandidateClusterQueueKey = "spec.clusterQueueSelection.clusterQueues"
 )
 
 func IndexQueueClusterQueue(obj client.Object) []string {
@@ -48,6 +51,18 @@ func IndexQueueClusterQueue(obj client.Object) []string {
 	return []string{string(q.Spec.ClusterQueue)}
 }
 
+func IndexQueueCandidateClusterQueues(obj client.Object) []string {
+	q, ok := obj.(*kueue.LocalQueue)
+	if !ok || q.Spec.ClusterQueueSelection == nil {
+		return nil
+	}
+	names := make([]string, 0, len(q.Spec.ClusterQueueSelection.ClusterQueues))
+	for _, cq := range q.Spec.ClusterQueueSelection.ClusterQueues {
+		names = append(names, string(cq))
+	}
+	return names
+}
+
 func IndexWorkloadQueue(obj client.Object) []string {
 	wl, ok := obj.(*kueue.Workload)
 	if !ok {
@@ -81,6 +96,14 @@ func IndexLimitRangeHasContainerType(obj client.Object) []string {
 	return nil
 }
 
+func IndexPodNodeName(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil
+	}
+	return []string{pod.Spec.NodeName}
+}
+
 func IndexWorkloadQuotaReserved(obj client.Object) []string {
 	wl, ok := obj.(*kueue.Workload)
 	if !ok {
@@ -133,6 +156,9 @@ func Setup(ctx context.Context, indexer client.FieldIndexer) error {
 	if err := indexer.IndexField(ctx, &kueue.LocalQueue{}, QueueClusterQueueKey, IndexQueueClusterQueue); err != nil {
 		return fmt.Errorf("setting index on clusterQueue for localQueue: %w", err)
 	}
+	if err := indexer.IndexField(ctx, &kueue.LocalQueue{}, QueueCandidateClusterQueueKey, IndexQueueCandidateClusterQueues); err != nil {
+		return fmt.Errorf("setting index on candidate clusterQueues for localQueue: %w", err)
+	}
 	if err := indexer.IndexField(ctx, &corev1.LimitRange{}, LimitRangeHasContainerType, IndexLimitRangeHasContainerType); err != nil {
 		return fmt.Errorf("setting index on hasContainerType for limitRange: %w", err)
 	}
@@ -141,3 +167,12 @@ func Setup(ctx context.Context, indexer client.FieldIndexer) error {
 	}
 	return nil
 }
+
+// SetupPodNodeName sets the index on the Node name of the pods, which is only
+// needed to evict the workloads running on unavailable Nodes.
+func SetupPodNodeName(ctx context.Context, indexer client.FieldIndexer) error {
+	if err := indexer.IndexField(ctx, &corev1.Pod{}, PodNodeNameKey, IndexPodNodeName); err != nil {
+		return fmt.Errorf("setting index on nodeName for Pod: %w", err)
+	}
+	return nil
+}
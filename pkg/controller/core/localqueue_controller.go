@@ -18,6 +18,7 @@ package core
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -25,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
@@ -44,14 +46,16 @@ import (
 )
 
 const (
-	localQueueIsInactiveMsg   = "LocalQueue is stopped"
-	clusterQueueIsInactiveMsg = "Can't submit new workloads to clusterQueue"
-	failedUpdateLqStatusMsg   = "Failed to retrieve localQueue status"
+	localQueueIsInactiveMsg     = "LocalQueue is stopped"
+	clusterQueueDoesNotExistMsg = "ClusterQueue %s doesn't exist"
+	clusterQueueIsInactiveMsg   = "ClusterQueue %s is inactive"
+	failedUpdateLqStatusMsg     = "Failed to retrieve localQueue status"
 )
 
 const (
-	StoppedReason                = "Stopped"
-	clusterQueueIsInactiveReason = "ClusterQueueIsInactive"
+	StoppedReason                  = "Stopped"
+	clusterQueueDoesNotExistReason = "ClusterQueueDoesNotExist"
+	clusterQueueIsInactiveReason   = "ClusterQueueIsInactive"
 )
 
 // LocalQueueReconciler reconciles a LocalQueue object
@@ -110,15 +114,30 @@ func (r *LocalQueueReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	err := r.client.Get(ctx, client.ObjectKey{Name: string(queueObj.Spec.ClusterQueue)}, &cq)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			err = r.UpdateStatusIfChanged(ctx, &queueObj, metav1.ConditionFalse, "ClusterQueueDoesNotExist", clusterQueueIsInactiveMsg)
+			err = r.UpdateStatusIfChanged(ctx, &queueObj, metav1.ConditionFalse, clusterQueueDoesNotExistReason, fmt.Sprintf(clusterQueueDoesNotExistMsg, queueObj.Spec.ClusterQueue))
 		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	if sel := queueObj.Spec.ClusterQueueSelection; sel != nil {
+		for _, name := range sel.ClusterQueues {
+			if err := r.client.Get(ctx, client.ObjectKey{Name: string(name)}, &kueue.ClusterQueue{}); err != nil {
+				if apierrors.IsNotFound(err) {
+					err = r.UpdateStatusIfChanged(ctx, &queueObj, metav1.ConditionFalse, clusterQueueDoesNotExistReason, fmt.Sprintf(clusterQueueDoesNotExistMsg, name))
+				}
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+		}
+	}
 	if meta.IsStatusConditionTrue(cq.Status.Conditions, kueue.ClusterQueueActive) {
 		err = r.UpdateStatusIfChanged(ctx, &queueObj, metav1.ConditionTrue, "Ready", "Can submit new workloads to clusterQueue")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	err = r.UpdateStatusIfChanged(ctx, &queueObj, metav1.ConditionFalse, clusterQueueIsInactiveReason, clusterQueueIsInactiveMsg)
+	// Explain why the ClusterQueue is inactive with the message of its condition.
+	msg := fmt.Sprintf(clusterQueueIsInactiveMsg, cq.Name)
+	if cond := meta.FindStatusCondition(cq.Status.Conditions, kueue.ClusterQueueActive); cond != nil && cond.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, cond.Message)
+	}
+	err = r.UpdateStatusIfChanged(ctx, &queueObj, metav1.ConditionFalse, clusterQueueIsInactiveReason, msg)
 	return ctrl.Result{}, client.IgnoreNotFound(err)
 }
 
@@ -179,6 +198,12 @@ func (r *LocalQueueReconciler) Update(e event.UpdateEvent) bool {
 		if err := r.cache.UpdateLocalQueue(oldLq, newLq); err != nil {
 			log.Error(err, "Failed to update localQueue in the cache")
 		}
+		if newStopPolicy == kueue.None && !equality.Semantic.DeepEqual(oldLq.Spec.MaxConcurrency, newLq.Spec.MaxConcurrency) {
+			// The workloads of the localQueue could have been inadmissible
+			// because of the previous maxConcurrency.
+			ctx := logr.NewContext(context.Background(), log)
+			r.queues.QueueInadmissibleWorkloads(ctx, sets.New(string(newLq.Spec.ClusterQueue)))
+		}
 		return true
 	}
 
@@ -275,14 +300,16 @@ func (h *qCQHandler) addLocalQueueToWorkQueue(ctx context.Context, cq *kueue.Clu
 	log := ctrl.LoggerFrom(ctx).WithValues("clusterQueue", klog.KObj(cq))
 	ctx = ctrl.LoggerInto(ctx, log)
 
-	var queues kueue.LocalQueueList
-	err := h.client.List(ctx, &queues, client.MatchingFields{indexer.QueueClusterQueueKey: cq.Name})
-	if err != nil {
-		log.Error(err, "Could not list queues that match the clusterQueue")
-		return
-	}
-	for _, q := range queues.Items {
-		wq.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&q)})
+	for _, key := range []string{indexer.QueueClusterQueueKey, indexer.QueueCandidateClusterQueueKey} {
+		var queues kueue.LocalQueueList
+		err := h.client.List(ctx, &queues, client.MatchingFields{key: cq.Name})
+		if err != nil {
+			log.Error(err, "Could not list queues that match the clusterQueue")
+			return
+		}
+		for _, q := range queues.Items {
+			wq.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&q)})
+		}
 	}
 }
 
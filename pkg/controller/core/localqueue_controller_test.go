@@ -106,7 +106,76 @@ func TestLocalQueueReconcile(t *testing.T) {
 					kueue.LocalQueueActive,
 					metav1.ConditionFalse,
 					clusterQueueIsInactiveReason,
-					clusterQueueIsInactiveMsg,
+					"ClusterQueue test-cluster-queue is inactive",
+					1,
+				).
+				Obj(),
+			wantError: nil,
+		},
+		"cluster queue is inactive with a reason": {
+			clusterQueue: utiltesting.MakeClusterQueue("test-cluster-queue").
+				Condition(kueue.ClusterQueueActive, metav1.ConditionFalse, "FlavorNotFound", "Can't admit new workloads: FlavorNotFound").
+				Obj(),
+			localQueue: utiltesting.MakeLocalQueue("test-queue", "default").
+				ClusterQueue("test-cluster-queue").
+				PendingWorkloads(1).
+				Generation(1).
+				Obj(),
+			wantLocalQueue: utiltesting.MakeLocalQueue("test-queue", "default").
+				ClusterQueue("test-cluster-queue").
+				PendingWorkloads(0).
+				Generation(1).
+				Condition(
+					kueue.LocalQueueActive,
+					metav1.ConditionFalse,
+					clusterQueueIsInactiveReason,
+					"ClusterQueue test-cluster-queue is inactive: Can't admit new workloads: FlavorNotFound",
+					1,
+				).
+				Obj(),
+			wantError: nil,
+		},
+		"cluster queue doesn't exist": {
+			clusterQueue: utiltesting.MakeClusterQueue("test-cluster-queue").
+				Obj(),
+			localQueue: utiltesting.MakeLocalQueue("test-queue", "default").
+				ClusterQueue("missing-cluster-queue").
+				PendingWorkloads(1).
+				Generation(1).
+				Obj(),
+			wantLocalQueue: utiltesting.MakeLocalQueue("test-queue", "default").
+				ClusterQueue("missing-cluster-queue").
+				PendingWorkloads(0).
+				Generation(1).
+				Condition(
+					kueue.LocalQueueActive,
+					metav1.ConditionFalse,
+					clusterQueueDoesNotExistReason,
+					"ClusterQueue missing-cluster-queue doesn't exist",
+					1,
+				).
+				Obj(),
+			wantError: nil,
+		},
+		"candidate cluster queue doesn't exist": {
+			clusterQueue: utiltesting.MakeClusterQueue("test-cluster-queue").
+				Obj(),
+			localQueue: utiltesting.MakeLocalQueue("test-queue", "default").
+				ClusterQueue("test-cluster-queue").
+				ClusterQueueSelection(kueue.ClusterQueueSelectionFirstWithCapacity, "missing-cluster-queue").
+				PendingWorkloads(1).
+				Generation(1).
+				Obj(),
+			wantLocalQueue: utiltesting.MakeLocalQueue("test-queue", "default").
+				ClusterQueue("test-cluster-queue").
+				ClusterQueueSelection(kueue.ClusterQueueSelectionFirstWithCapacity, "missing-cluster-queue").
+				PendingWorkloads(0).
+				Generation(1).
+				Condition(
+					kueue.LocalQueueActive,
+					metav1.ConditionFalse,
+					"ClusterQueueDoesNotExist",
+					"ClusterQueue missing-cluster-queue doesn't exist",
 					1,
 				).
 				Obj(),
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/controller/core/indexer"
+	utilpod "sigs.k8s.io/kueue/pkg/util/pod"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// NodeReconciler evicts the workloads running on Nodes that become
+// unavailable, when the Nodes belong to a ResourceFlavor with
+// evictOnNodeUnavailable.
+// Finding the pods running on a Node starts a cluster-wide Pod informer,
+// which is why the reconciler is only set up when the
+// NodeUnavailableEviction feature is enabled.
+type NodeReconciler struct {
+	client client.Client
+	// apiReader reads the controllers of the pods without caching their
+	// types.
+	apiReader client.Reader
+	recorder  record.EventRecorder
+}
+
+func NewNodeReconciler(client client.Client, apiReader client.Reader, recorder record.EventRecorder) *NodeReconciler {
+	return &NodeReconciler{
+		client:    client,
+		apiReader: apiReader,
+		recorder:  recorder,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+
+func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var node corev1.Node
+	if err := r.client.Get(ctx, req.NamespacedName, &node); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if nodeAvailable(&node) {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("node", klog.KObj(&node))
+	ctx = ctrl.LoggerInto(ctx, log)
+	log.V(2).Info("Reconciling unavailable Node")
+
+	flavors, err := r.evictableFlavors(ctx, &node)
+	if err != nil || len(flavors) == 0 {
+		return ctrl.Result{}, err
+	}
+
+	var pods corev1.PodList
+	if err := r.client.List(ctx, &pods, client.MatchingFields{indexer.PodNodeNameKey: node.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	evicted := sets.New[types.NamespacedName]()
+	// The pods of a workload usually share their controller, whose chain is
+	// only resolved once.
+	controllerWorkloads := make(map[types.UID][]kueue.Workload)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		wls, err := r.podWorkloads(ctx, pod, controllerWorkloads)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		for j := range wls {
+			wl := &wls[j]
+			key := client.ObjectKeyFromObject(wl)
+			if evicted.Has(key) || !workload.HasQuotaReservation(wl) || apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadEvicted) {
+				continue
+			}
+			flavor, found := assignedFlavorIn(wl, flavors)
+			if !found {
+				continue
+			}
+			evicted.Insert(key)
+			log.V(2).Info("Evicting the workload because its Node is unavailable", "workload", klog.KObj(wl), "resourceFlavor", flavor)
+			message := fmt.Sprintf("Node %s of ResourceFlavor %s is unavailable", node.Name, flavor)
+			workload.SetEvictedCondition(wl, kueue.WorkloadEvictedByNodeUnavailable, message)
+			if err := workload.ApplyAdmissionStatus(ctx, r.client, wl, true); err != nil {
+				if client.IgnoreNotFound(err) != nil {
+					return ctrl.Result{}, err
+				}
+				continue
+			}
+			workload.ReportEvictedWorkload(r.recorder, wl, string(wl.Status.Admission.ClusterQueue), kueue.WorkloadEvictedByNodeUnavailable, message)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// evictableFlavors returns the ResourceFlavors with evictOnNodeUnavailable
+// whose nodeLabels match the labels of the Node.
+func (r *NodeReconciler) evictableFlavors(ctx context.Context, node *corev1.Node) (sets.Set[kueue.ResourceFlavorReference], error) {
+	var flavors kueue.ResourceFlavorList
+	if err := r.client.List(ctx, &flavors); err != nil {
+		return nil, err
+	}
+	result := sets.New[kueue.ResourceFlavorReference]()
+	for _, rf := range flavors.Items {
+		if !rf.Spec.EvictOnNodeUnavailable || len(rf.Spec.NodeLabels) == 0 {
+			continue
+		}
+		if labels.SelectorFromSet(rf.Spec.NodeLabels).Matches(labels.Set(node.Labels)) {
+			result.Insert(kueue.ResourceFlavorReference(rf.Name))
+		}
+	}
+	return result, nil
+}
+
+// assignedFlavorIn returns the first flavor assigned to the workload that
+// is in the given set.
+func assignedFlavorIn(wl *kueue.Workload, flavors sets.Set[kueue.ResourceFlavorReference]) (kueue.ResourceFlavorReference, bool) {
+	for _, psa := range wl.Status.Admission.PodSetAssignments {
+		for _, flavor := range psa.Flavors {
+			if flavors.Has(flavor) {
+				return flavor, true
+			}
+		}
+	}
+	return "", false
+}
+
+// podWorkloads returns the Workloads of the pod: the ones owned by the pod
+// itself, for plain pods, or by the closest controller in the chain of
+// controllers of the pod that owns Workloads, like the JobSet of the Job
+// of the pod. controllerWorkloads caches the Workloads found through the
+// controller of the pod, which are shared by its sibling pods.
+func (r *NodeReconciler) podWorkloads(ctx context.Context, pod *corev1.Pod, controllerWorkloads map[types.UID][]kueue.Workload) ([]kueue.Workload, error) {
+	wls, err := r.ownedWorkloads(ctx, pod.Namespace, pod.UID)
+	if err != nil || len(wls) > 0 {
+		return wls, err
+	}
+	controller := metav1.GetControllerOfNoCopy(pod)
+	if controller == nil {
+		return nil, nil
+	}
+	if wls, found := controllerWorkloads[controller.UID]; found {
+		return wls, nil
+	}
+	uids, err := utilpod.ControllerUIDs(ctx, r.apiReader, pod, utilpod.MaxControllerDepth)
+	if err != nil {
+		return nil, err
+	}
+	for _, uid := range uids {
+		if wls, err = r.ownedWorkloads(ctx, pod.Namespace, uid); err != nil {
+			return nil, err
+		}
+		if len(wls) > 0 {
+			break
+		}
+	}
+	controllerWorkloads[controller.UID] = wls
+	return wls, nil
+}
+
+func (r *NodeReconciler) ownedWorkloads(ctx context.Context, namespace string, uid types.UID) ([]kueue.Workload, error) {
+	var wls kueue.WorkloadList
+	if err := r.client.List(ctx, &wls, client.InNamespace(namespace), client.MatchingFields{indexer.OwnerReferenceUID: string(uid)}); err != nil {
+		return nil, err
+	}
+	return wls.Items, nil
+}
+
+func nodeAvailable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (r *NodeReconciler) Create(e event.CreateEvent) bool {
+	node, isNode := e.Object.(*corev1.Node)
+	return isNode && !nodeAvailable(node)
+}
+
+func (r *NodeReconciler) Update(e event.UpdateEvent) bool {
+	oldNode, isNode := e.ObjectOld.(*corev1.Node)
+	if !isNode {
+		return false
+	}
+	newNode, isNode := e.ObjectNew.(*corev1.Node)
+	if !isNode {
+		return false
+	}
+	// Only react to Nodes becoming unavailable, ignoring the status
+	// heartbeats of the Nodes that already were.
+	return nodeAvailable(oldNode) && !nodeAvailable(newNode)
+}
+
+func (r *NodeReconciler) Delete(event.DeleteEvent) bool {
+	return false
+}
+
+func (r *NodeReconciler) Generic(event.GenericEvent) bool {
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Node{}).
+		WithEventFilter(r).
+		Complete(r)
+}
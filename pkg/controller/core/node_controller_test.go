@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/controller/core/indexer"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestNodeReconcile(t *testing.T) {
+	spotFlavor := utiltesting.MakeResourceFlavor("spot").
+		Label("instance", "spot").
+		EvictOnNodeUnavailable(true).
+		Obj()
+	onDemandFlavor := utiltesting.MakeResourceFlavor("on-demand").
+		Label("instance", "on-demand").
+		Obj()
+	makeNode := func(labels map[string]string, ready, unschedulable bool) *corev1.Node {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: labels},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			},
+		}
+	}
+	runningPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "ns",
+			UID:       "pod-uid",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "batch/v1",
+				Kind:       "Job",
+				Name:       "job",
+				UID:        "job-uid",
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec:   corev1.PodSpec{NodeName: "node"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	succeededPod := runningPod.DeepCopy()
+	succeededPod.Status.Phase = corev1.PodSucceeded
+	baseWorkload := utiltesting.MakeWorkload("wl", "ns").
+		OwnerReference(batchv1.SchemeGroupVersion.WithKind("Job"), "job", "job-uid").
+		ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "spot", "1").Obj()).
+		Admitted(true)
+
+	// The Job of the pod is controlled by a CronJob, which stands for the
+	// owners of the Workloads that create Jobs, like JobSets.
+	controlledJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job",
+			Namespace: "ns",
+			UID:       "job-uid",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "batch/v1",
+				Kind:       "CronJob",
+				Name:       "cronjob",
+				UID:        "cronjob-uid",
+				Controller: ptr.To(true),
+			}},
+		},
+	}
+
+	cases := map[string]struct {
+		node         *corev1.Node
+		flavors      []*kueue.ResourceFlavor
+		pods         []*corev1.Pod
+		owners       []client.Object
+		workload     *kueue.Workload
+		wantWorkload *kueue.Workload
+		wantEvents   []utiltesting.EventRecord
+	}{
+		"evict the workload running on a NotReady node of the flavor": {
+			node:     makeNode(map[string]string{"instance": "spot"}, false, false),
+			flavors:  []*kueue.ResourceFlavor{spotFlavor, onDemandFlavor},
+			pods:     []*corev1.Pod{runningPod},
+			workload: baseWorkload.Clone().Obj(),
+			wantWorkload: baseWorkload.Clone().
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionTrue,
+					Reason:  kueue.WorkloadEvictedByNodeUnavailable,
+					Message: "Node node of ResourceFlavor spot is unavailable",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: corev1.EventTypeNormal,
+					Reason:    "EvictedDueToNodeUnavailable",
+					Message:   "Node node of ResourceFlavor spot is unavailable",
+				},
+			},
+		},
+		"evict the workload running on an unschedulable node of the flavor": {
+			node:     makeNode(map[string]string{"instance": "spot"}, true, true),
+			flavors:  []*kueue.ResourceFlavor{spotFlavor},
+			pods:     []*corev1.Pod{runningPod},
+			workload: baseWorkload.Clone().Obj(),
+			wantWorkload: baseWorkload.Clone().
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionTrue,
+					Reason:  kueue.WorkloadEvictedByNodeUnavailable,
+					Message: "Node node of ResourceFlavor spot is unavailable",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: corev1.EventTypeNormal,
+					Reason:    "EvictedDueToNodeUnavailable",
+					Message:   "Node node of ResourceFlavor spot is unavailable",
+				},
+			},
+		},
+		"keep the workload when the node is available": {
+			node:         makeNode(map[string]string{"instance": "spot"}, true, false),
+			flavors:      []*kueue.ResourceFlavor{spotFlavor},
+			pods:         []*corev1.Pod{runningPod},
+			workload:     baseWorkload.Clone().Obj(),
+			wantWorkload: baseWorkload.Clone().Obj(),
+		},
+		"keep the workload when the flavor of the node doesn't evict": {
+			node:         makeNode(map[string]string{"instance": "on-demand"}, false, false),
+			flavors:      []*kueue.ResourceFlavor{spotFlavor, onDemandFlavor},
+			pods:         []*corev1.Pod{runningPod},
+			workload:     baseWorkload.Clone().Obj(),
+			wantWorkload: baseWorkload.Clone().Obj(),
+		},
+		"keep the workload admitted in another flavor": {
+			node:    makeNode(map[string]string{"instance": "spot"}, false, false),
+			flavors: []*kueue.ResourceFlavor{spotFlavor, onDemandFlavor},
+			pods:    []*corev1.Pod{runningPod},
+			workload: baseWorkload.Clone().
+				ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj()).
+				Obj(),
+			wantWorkload: baseWorkload.Clone().
+				ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj()).
+				Obj(),
+		},
+		"evict the workload owned by the controller of the controller of the pod": {
+			node:    makeNode(map[string]string{"instance": "spot"}, false, false),
+			flavors: []*kueue.ResourceFlavor{spotFlavor},
+			pods:    []*corev1.Pod{runningPod},
+			owners:  []client.Object{controlledJob},
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				OwnerReference(batchv1.SchemeGroupVersion.WithKind("CronJob"), "cronjob", "cronjob-uid").
+				ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "spot", "1").Obj()).
+				Admitted(true).
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				OwnerReference(batchv1.SchemeGroupVersion.WithKind("CronJob"), "cronjob", "cronjob-uid").
+				ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "spot", "1").Obj()).
+				Admitted(true).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionTrue,
+					Reason:  kueue.WorkloadEvictedByNodeUnavailable,
+					Message: "Node node of ResourceFlavor spot is unavailable",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: corev1.EventTypeNormal,
+					Reason:    "EvictedDueToNodeUnavailable",
+					Message:   "Node node of ResourceFlavor spot is unavailable",
+				},
+			},
+		},
+		"keep the workload whose pods on the node finished": {
+			node:         makeNode(map[string]string{"instance": "spot"}, false, false),
+			flavors:      []*kueue.ResourceFlavor{spotFlavor},
+			pods:         []*corev1.Pod{succeededPod},
+			workload:     baseWorkload.Clone().Obj(),
+			wantWorkload: baseWorkload.Clone().Obj(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs := []client.Object{tc.node, tc.workload}
+			for _, rf := range tc.flavors {
+				objs = append(objs, rf)
+			}
+			for _, pod := range tc.pods {
+				objs = append(objs, pod)
+			}
+			objs = append(objs, tc.owners...)
+			cl := utiltesting.NewClientBuilder().
+				WithObjects(objs...).
+				WithStatusSubresource(tc.workload).
+				WithIndex(&corev1.Pod{}, indexer.PodNodeNameKey, indexer.IndexPodNodeName).
+				WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: utiltesting.TreatSSAAsStrategicMerge}).
+				Build()
+			recorder := &utiltesting.EventRecorder{}
+			reconciler := NewNodeReconciler(cl, cl, recorder)
+
+			ctx, _ := utiltesting.ContextWithLog(t)
+			if _, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(tc.node)}); err != nil {
+				t.Fatalf("Unexpected reconcile error: %v", err)
+			}
+
+			var gotWorkload kueue.Workload
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(tc.workload), &gotWorkload); err != nil {
+				t.Fatalf("Could not get the workload after reconcile: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantWorkload, &gotWorkload, workloadCmpOpts...); diff != "" {
+				t.Errorf("Workload after reconcile (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantEvents, recorder.RecordedEvents); diff != "" {
+				t.Errorf("Unexpected events (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/workqueue"
@@ -176,7 +177,13 @@ func (r *ResourceFlavorReconciler) Update(e event.UpdateEvent) bool {
 		return true
 	}
 
-	if cqNames := r.cache.AddOrUpdateResourceFlavor(newFlv.DeepCopy()); len(cqNames) > 0 {
+	cqNames := r.cache.AddOrUpdateResourceFlavor(newFlv.DeepCopy())
+	if !equality.Semantic.DeepEqual(oldFlv.Spec, newFlv.Spec) {
+		// Changes in the labels, taints or tolerations of the flavor might
+		// make the inadmissible workloads in the ClusterQueues using it fit.
+		cqNames.Insert(r.cache.ClusterQueuesUsingFlavor(newFlv.Name)...)
+	}
+	if len(cqNames) > 0 {
 		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
 	}
 	return false
@@ -19,8 +19,10 @@ package core
 import (
 	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -48,10 +50,12 @@ import (
 	config "sigs.k8s.io/kueue/apis/config/v1beta1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/controller/core/indexer"
 	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
 	utilac "sigs.k8s.io/kueue/pkg/util/admissioncheck"
+	"sigs.k8s.io/kueue/pkg/util/api"
 	utilslices "sigs.k8s.io/kueue/pkg/util/slices"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
@@ -68,9 +72,15 @@ type waitForPodsReadyConfig struct {
 	requeuingBackoffJitter      float64
 }
 
+type admissionChecksTimeoutConfig struct {
+	timeout time.Duration
+	policy  config.AdmissionChecksTimeoutPolicy
+}
+
 type options struct {
-	watchers               []WorkloadUpdateWatcher
-	waitForPodsReadyConfig *waitForPodsReadyConfig
+	watchers                     []WorkloadUpdateWatcher
+	waitForPodsReadyConfig       *waitForPodsReadyConfig
+	admissionChecksTimeoutConfig *admissionChecksTimeoutConfig
 }
 
 // Option configures the reconciler.
@@ -83,6 +93,14 @@ func WithWaitForPodsReady(value *waitForPodsReadyConfig) Option {
 	}
 }
 
+// WithAdmissionChecksTimeout indicates the time for the admission checks of
+// a workload to become Ready after it reserved quota.
+func WithAdmissionChecksTimeout(value *admissionChecksTimeoutConfig) Option {
+	return func(o *options) {
+		o.admissionChecksTimeoutConfig = value
+	}
+}
+
 // WithWorkloadUpdateWatchers allows to specify the workload update watchers
 func WithWorkloadUpdateWatchers(value ...WorkloadUpdateWatcher) Option {
 	return func(o *options) {
@@ -98,14 +116,20 @@ type WorkloadUpdateWatcher interface {
 
 // WorkloadReconciler reconciles a Workload object
 type WorkloadReconciler struct {
-	log              logr.Logger
-	queues           *queue.Manager
-	cache            *cache.Cache
-	client           client.Client
-	watchers         []WorkloadUpdateWatcher
-	waitForPodsReady *waitForPodsReadyConfig
-	recorder         record.EventRecorder
-	clock            clock.Clock
+	log                    logr.Logger
+	queues                 *queue.Manager
+	cache                  *cache.Cache
+	client                 client.Client
+	watchers               []WorkloadUpdateWatcher
+	waitForPodsReady       *waitForPodsReadyConfig
+	admissionChecksTimeout *admissionChecksTimeoutConfig
+	recorder               record.EventRecorder
+	clock                  clock.Clock
+
+	// handledVersions holds, for each workload, the resourceVersion of the
+	// last event that the event handlers finished applying to the cache and
+	// the queues. It's cleared while an event is being handled.
+	handledVersions sync.Map
 }
 
 func NewWorkloadReconciler(client client.Client, queues *queue.Manager, cache *cache.Cache, recorder record.EventRecorder, opts ...Option) *WorkloadReconciler {
@@ -115,14 +139,15 @@ func NewWorkloadReconciler(client client.Client, queues *queue.Manager, cache *c
 	}
 
 	return &WorkloadReconciler{
-		log:              ctrl.Log.WithName("workload-reconciler"),
-		client:           client,
-		queues:           queues,
-		cache:            cache,
-		watchers:         options.watchers,
-		waitForPodsReady: options.waitForPodsReadyConfig,
-		recorder:         recorder,
-		clock:            realClock,
+		log:                    ctrl.Log.WithName("workload-reconciler"),
+		client:                 client,
+		queues:                 queues,
+		cache:                  cache,
+		watchers:               options.watchers,
+		waitForPodsReady:       options.waitForPodsReadyConfig,
+		admissionChecksTimeout: options.admissionChecksTimeoutConfig,
+		recorder:               recorder,
+		clock:                  realClock,
 	}
 }
 
@@ -151,6 +176,14 @@ func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, nil
 	}
 
+	if r.eventHandled(&wl) && r.cache.WorkloadOutOfSync(&wl) {
+		wlCopy := wl.DeepCopy()
+		workload.AdjustResources(ctx, r.client, wlCopy)
+		if r.cache.SyncWorkload(wlCopy, func() bool { return r.eventHandled(&wl) }) {
+			log.Info("Repaired the cache, which was out of sync with the quota reservation of the workload", "quotaReserved", workload.HasQuotaReservation(&wl))
+		}
+	}
+
 	if workload.IsActive(&wl) {
 		if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadDeactivationTarget) {
 			wl.Spec.Active = ptr.To(false)
@@ -255,7 +288,7 @@ func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			queuedWaitTime := workload.QueuedWaitTime(&wl)
 			quotaReservedCondition := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadQuotaReserved)
 			quotaReservedWaitTime := r.clock.Since(quotaReservedCondition.LastTransitionTime.Time)
-			r.recorder.Eventf(&wl, corev1.EventTypeNormal, "Admitted", "Admitted by ClusterQueue %v, wait time since reservation was %.0fs", wl.Status.Admission.ClusterQueue, quotaReservedWaitTime.Seconds())
+			r.recorder.Event(&wl, corev1.EventTypeNormal, "Admitted", workload.AdmittedEventMessage(wl.Status.Admission, quotaReservedWaitTime))
 			metrics.AdmittedWorkload(kueue.ClusterQueueReference(cqName), queuedWaitTime)
 			metrics.AdmissionChecksWaitTime(kueue.ClusterQueueReference(cqName), quotaReservedWaitTime)
 		}
@@ -275,37 +308,82 @@ func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			return ctrl.Result{}, err
 		}
 
+		if !workload.IsAdmitted(&wl) {
+			return r.reconcileAdmissionChecksTimeout(ctx, &wl)
+		}
 		return r.reconcileNotReadyTimeout(ctx, req, &wl)
 	}
 
+	deadlineExceeded, recheckAfter, err := r.reconcileActiveDeadline(ctx, &wl)
+	if deadlineExceeded || err != nil {
+		return ctrl.Result{}, err
+	}
+	result := ctrl.Result{RequeueAfter: recheckAfter}
+
 	switch {
 	case !lqExists:
 		log.V(3).Info("Workload is inadmissible because of missing LocalQueue", "localQueue", klog.KRef(wl.Namespace, wl.Spec.QueueName))
-		if workload.UnsetQuotaReservationWithCondition(&wl, kueue.WorkloadInadmissible, fmt.Sprintf("LocalQueue %s doesn't exist", wl.Spec.QueueName)) {
-			err := workload.ApplyAdmissionStatus(ctx, r.client, &wl, true)
-			return ctrl.Result{}, client.IgnoreNotFound(err)
-		}
+		return result, r.setInadmissible(ctx, &wl, kueue.WorkloadInadmissible, fmt.Sprintf("LocalQueue %s doesn't exist", wl.Spec.QueueName))
 	case !lqActive:
 		log.V(3).Info("Workload is inadmissible because of stopped LocalQueue", "localQueue", klog.KRef(wl.Namespace, wl.Spec.QueueName))
-		if workload.UnsetQuotaReservationWithCondition(&wl, kueue.WorkloadInadmissible, fmt.Sprintf("LocalQueue %s is inactive", wl.Spec.QueueName)) {
-			err := workload.ApplyAdmissionStatus(ctx, r.client, &wl, true)
-			return ctrl.Result{}, client.IgnoreNotFound(err)
-		}
+		return result, r.setInadmissible(ctx, &wl, kueue.WorkloadInadmissible, fmt.Sprintf("LocalQueue %s is inactive", wl.Spec.QueueName))
 	case !cqOk:
 		log.V(3).Info("Workload is inadmissible because of missing ClusterQueue", "clusterQueue", klog.KRef("", cqName))
-		if workload.UnsetQuotaReservationWithCondition(&wl, kueue.WorkloadInadmissible, fmt.Sprintf("ClusterQueue %s doesn't exist", cqName)) {
-			err := workload.ApplyAdmissionStatus(ctx, r.client, &wl, true)
-			return ctrl.Result{}, client.IgnoreNotFound(err)
-		}
+		return result, r.setInadmissible(ctx, &wl, kueue.WorkloadInadmissible, fmt.Sprintf("ClusterQueue %s doesn't exist", cqName))
 	case !r.cache.ClusterQueueActive(cqName):
 		log.V(3).Info("Workload is inadmissible because ClusterQueue is inactive", "clusterQueue", klog.KRef("", cqName))
-		if workload.UnsetQuotaReservationWithCondition(&wl, kueue.WorkloadInadmissible, fmt.Sprintf("ClusterQueue %s is inactive", cqName)) {
-			err := workload.ApplyAdmissionStatus(ctx, r.client, &wl, true)
-			return ctrl.Result{}, client.IgnoreNotFound(err)
+		return result, r.setInadmissible(ctx, &wl, kueue.WorkloadInadmissible, fmt.Sprintf("ClusterQueue %s is inactive", cqName))
+	}
+	if err := r.cache.ValidateRequestsFitCapacity(cqName, &wl); err != nil {
+		log.V(3).Info("Workload is inadmissible because it requests more than the capacity of the ClusterQueue", "clusterQueue", klog.KRef("", cqName), "reason", err.Error())
+		return result, r.setInadmissible(ctx, &wl, kueue.WorkloadRequestExceedsCapacity, err.Error())
+	}
+	wlCopy := wl.DeepCopy()
+	workload.AdjustResources(ctx, r.client, wlCopy)
+	if err := workload.ValidateLimitRange(ctx, r.client, wlCopy); err != nil {
+		if !errors.Is(err, workload.ErrExceedsLimitRange) {
+			return result, err
 		}
+		log.V(3).Info("Workload is inadmissible because its pods exceed the LimitRange maximum", "reason", err.Error())
+		return result, r.setInadmissible(ctx, &wl, kueue.WorkloadExceedsLimitRange, err.Error())
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// reconcileActiveDeadline marks the pending workload as finished if it
+// exceeded its activeDeadlineSeconds. Otherwise, it returns the time after
+// which the deadline should be checked again, if any.
+func (r *WorkloadReconciler) reconcileActiveDeadline(ctx context.Context, wl *kueue.Workload) (bool, time.Duration, error) {
+	if wl.Spec.ActiveDeadlineSeconds == nil {
+		return false, 0, nil
+	}
+	deadline := time.Duration(*wl.Spec.ActiveDeadlineSeconds) * time.Second
+	if recheckAfter := deadline - r.clock.Since(wl.CreationTimestamp.Time); recheckAfter > 0 {
+		return false, recheckAfter, nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	log.V(2).Info("Workload is finished because it exceeded its active deadline", "activeDeadlineSeconds", *wl.Spec.ActiveDeadlineSeconds)
+	message := fmt.Sprintf("The workload remained pending past its active deadline of %s", deadline)
+	err := workload.UpdateStatus(ctx, r.client, wl, kueue.WorkloadFinished, metav1.ConditionTrue, kueue.WorkloadFinishedReasonDeadlineExceeded, message, constants.WorkloadControllerName)
+	if err != nil {
+		return false, 0, client.IgnoreNotFound(err)
+	}
+	r.recorder.Event(wl, corev1.EventTypeWarning, kueue.WorkloadFinishedReasonDeadlineExceeded, message)
+	return true, 0, nil
+}
+
+// setInadmissible unsets the quota reservation of the workload with the
+// reason and records an event with the message.
+func (r *WorkloadReconciler) setInadmissible(ctx context.Context, wl *kueue.Workload, reason, message string) error {
+	if !workload.UnsetQuotaReservationWithCondition(wl, reason, message) {
+		return nil
+	}
+	if err := workload.ApplyAdmissionStatus(ctx, r.client, wl, true); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.recorder.Event(wl, corev1.EventTypeNormal, reason, api.TruncateEventMessage(message))
+	return nil
 }
 
 // isDisabledRequeuedByClusterQueueStopped returns true if the workload is unset requeued by cluster queue stopped.
@@ -483,6 +561,36 @@ func syncAdmissionCheckConditions(conds []kueue.AdmissionCheckState, admissionCh
 	return conds, shouldUpdate
 }
 
+// reconcileAdmissionChecksTimeout releases the quota reservation of the
+// workload if its admission checks didn't become Ready within the timeout,
+// requeueing or deactivating the workload according to the timeout policy.
+func (r *WorkloadReconciler) reconcileAdmissionChecksTimeout(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	if r.admissionChecksTimeout == nil || len(wl.Status.AdmissionChecks) == 0 ||
+		!workload.IsActive(wl) || apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadEvicted) {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	quotaReservedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadQuotaReserved)
+	if recheckAfter := r.admissionChecksTimeout.timeout - r.clock.Since(quotaReservedCond.LastTransitionTime.Time); recheckAfter > 0 {
+		log.V(4).Info("Workload admission checks not yet Ready and did not exceed their timeout", "recheckAfter", recheckAfter)
+		return ctrl.Result{RequeueAfter: recheckAfter}, nil
+	}
+	message := fmt.Sprintf("Exceeded the admission checks timeout %s", r.admissionChecksTimeout.timeout)
+	if r.admissionChecksTimeout.policy == config.AdmissionChecksTimeoutDeactivate {
+		log.V(2).Info("Start the deactivation of the workload due to exceeding the admission checks timeout")
+		workload.SetDeactivationTarget(wl, kueue.WorkloadEvictedByAdmissionChecksTimeout, message)
+		return ctrl.Result{}, client.IgnoreNotFound(workload.ApplyAdmissionStatus(ctx, r.client, wl, true))
+	}
+	log.V(2).Info("Start the eviction of the workload due to exceeding the admission checks timeout")
+	workload.SetEvictedCondition(wl, kueue.WorkloadEvictedByAdmissionChecksTimeout, message)
+	err := workload.ApplyAdmissionStatus(ctx, r.client, wl, true)
+	if err == nil {
+		cqName, _ := r.queues.ClusterQueueForWorkload(wl)
+		workload.ReportEvictedWorkload(r.recorder, wl, cqName, kueue.WorkloadEvictedByAdmissionChecksTimeout, message)
+	}
+	return ctrl.Result{}, client.IgnoreNotFound(err)
+}
+
 func (r *WorkloadReconciler) reconcileNotReadyTimeout(ctx context.Context, req ctrl.Request, wl *kueue.Workload) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -557,6 +665,8 @@ func (r *WorkloadReconciler) Create(e event.CreateEvent) bool {
 		// this event will be handled by the LimitRange/RuntimeClass handle
 		return true
 	}
+	r.handledVersions.Delete(workload.Key(wl))
+	defer r.handledVersions.Store(workload.Key(wl), wl.ResourceVersion)
 	defer r.notifyWatchers(nil, wl)
 	status := workload.Status(wl)
 	log := r.log.WithValues("workload", klog.KObj(wl), "queue", wl.Spec.QueueName, "status", status)
@@ -589,6 +699,7 @@ func (r *WorkloadReconciler) Delete(e event.DeleteEvent) bool {
 		// this event will be handled by the LimitRange/RuntimeClass handle
 		return true
 	}
+	defer r.handledVersions.Delete(workload.Key(wl))
 	defer r.notifyWatchers(wl, nil)
 	status := "unknown"
 	if !e.DeleteStateUnknown {
@@ -629,6 +740,8 @@ func (r *WorkloadReconciler) Update(e event.UpdateEvent) bool {
 		return true
 	}
 	wl := e.ObjectNew.(*kueue.Workload)
+	r.handledVersions.Delete(workload.Key(wl))
+	defer r.handledVersions.Store(workload.Key(wl), wl.ResourceVersion)
 	defer r.notifyWatchers(oldWl, wl)
 
 	status := workload.Status(wl)
@@ -721,7 +834,10 @@ func (r *WorkloadReconciler) Update(e event.UpdateEvent) bool {
 				}
 			})
 		}
-	case prevStatus == workload.StatusAdmitted && status == workload.StatusAdmitted && !equality.Semantic.DeepEqual(oldWl.Status.ReclaimablePods, wl.Status.ReclaimablePods):
+	case prevStatus == workload.StatusAdmitted && status == workload.StatusAdmitted &&
+		(!equality.Semantic.DeepEqual(oldWl.Status.ReclaimablePods, wl.Status.ReclaimablePods) || !equality.Semantic.DeepEqual(oldWl.Status.Admission, wl.Status.Admission)):
+		// the usage of the workload decreased, because some of its pods became
+		// reclaimable or it was scaled down.
 		// trigger the move of associated inadmissibleWorkloads, if there are any.
 		r.queues.QueueAssociatedInadmissibleWorkloadsAfter(ctx, wl, func() {
 			// Update the workload from cache while holding the queues lock
@@ -743,6 +859,15 @@ func (r *WorkloadReconciler) Update(e event.UpdateEvent) bool {
 	return true
 }
 
+// eventHandled returns whether the event handlers finished applying the
+// current version of the workload to the cache. The informer stores a new
+// version before the handlers run, so until then the cache is expected to
+// disagree with it.
+func (r *WorkloadReconciler) eventHandled(wl *kueue.Workload) bool {
+	version, found := r.handledVersions.Load(workload.Key(wl))
+	return found && version == wl.ResourceVersion
+}
+
 func (r *WorkloadReconciler) Generic(e event.GenericEvent) bool {
 	r.log.V(3).Info("Ignore generic event", "obj", klog.KObj(e.Object), "kind", e.Object.GetObjectKind().GroupVersionKind())
 	return false
@@ -853,6 +978,7 @@ func (h *resourceUpdatesHandler) queueReconcileForPending(ctx context.Context, _
 		log.Error(err, "Could not list pending workloads")
 	}
 	log.V(4).Info("Updating pending workload requests", "count", len(lst.Items))
+	exceedingLimitRangeCQs := sets.New[string]()
 	for _, w := range lst.Items {
 		wlCopy := w.DeepCopy()
 		log := log.WithValues("workload", klog.KObj(wlCopy))
@@ -861,7 +987,16 @@ func (h *resourceUpdatesHandler) queueReconcileForPending(ctx context.Context, _
 		if !h.r.queues.AddOrUpdateWorkload(wlCopy) {
 			log.V(2).Info("Queue for workload didn't exist")
 		}
+		if cond := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadQuotaReserved); cond != nil && cond.Reason == kueue.WorkloadExceedsLimitRange {
+			if cqName, ok := h.r.queues.ClusterQueueForWorkload(wlCopy); ok {
+				exceedingLimitRangeCQs.Insert(cqName)
+			}
+		}
 	}
+	// The workloads exceeding the maximum of the LimitRanges stay inadmissible
+	// when their requests don't change, so requeue them explicitly, as the
+	// LimitRanges might allow them now.
+	h.r.queues.QueueInadmissibleWorkloads(ctx, exceedingLimitRangeCQs)
 }
 
 type workloadQueueHandler struct {
@@ -876,6 +1011,9 @@ func (w *workloadQueueHandler) Create(ctx context.Context, ev event.CreateEvent,
 		log := ctrl.LoggerFrom(ctx).WithValues("clusterQueue", klog.KObj(cq))
 		ctx = ctrl.LoggerInto(ctx, log)
 		w.queueReconcileForWorkloadsOfClusterQueue(ctx, cq.Name, wq)
+		if len(cq.Spec.ResourceTransforms) > 0 {
+			w.updatePendingWorkloadsOfClusterQueue(ctx, cq.Name)
+		}
 		return
 	}
 	if lq, isLq := ev.Object.(*kueue.LocalQueue); isLq {
@@ -900,6 +1038,9 @@ func (w *workloadQueueHandler) Update(ctx context.Context, ev event.UpdateEvent,
 			!ptr.Equal(oldCq.Spec.StopPolicy, newCq.Spec.StopPolicy) {
 			w.queueReconcileForWorkloadsOfClusterQueue(ctx, newCq.Name, wq)
 		}
+		if !equality.Semantic.DeepEqual(oldCq.Spec.ResourceTransforms, newCq.Spec.ResourceTransforms) {
+			w.updatePendingWorkloadsOfClusterQueue(ctx, newCq.Name)
+		}
 		return
 	}
 
@@ -949,6 +1090,39 @@ func (w *workloadQueueHandler) queueReconcileForWorkloadsOfClusterQueue(ctx cont
 	}
 }
 
+// updatePendingWorkloadsOfClusterQueue adjusts again the requests of the
+// pending workloads of the LocalQueues that can send workloads to the
+// ClusterQueue, and updates them in the queues, so that they use the
+// current resourceTransforms of the ClusterQueue.
+func (w *workloadQueueHandler) updatePendingWorkloadsOfClusterQueue(ctx context.Context, cqName string) {
+	log := ctrl.LoggerFrom(ctx)
+	for _, key := range []string{indexer.QueueClusterQueueKey, indexer.QueueCandidateClusterQueueKey} {
+		lqs := kueue.LocalQueueList{}
+		if err := w.r.client.List(ctx, &lqs, client.MatchingFields{key: cqName}); err != nil {
+			log.Error(err, "Could not list cluster queues local queues")
+			continue
+		}
+		for _, lq := range lqs.Items {
+			wls := kueue.WorkloadList{}
+			if err := w.r.client.List(ctx, &wls, client.InNamespace(lq.Namespace), client.MatchingFields{indexer.WorkloadQueueKey: lq.Name}); err != nil {
+				log.Error(err, "Could not list local queue workloads", "localQueue", klog.KObj(&lq))
+				continue
+			}
+			for i := range wls.Items {
+				if workload.HasQuotaReservation(&wls.Items[i]) || workload.IsFinished(&wls.Items[i]) {
+					continue
+				}
+				wlCopy := wls.Items[i].DeepCopy()
+				log := log.WithValues("workload", klog.KObj(wlCopy))
+				workload.AdjustResources(ctrl.LoggerInto(ctx, log), w.r.client, wlCopy)
+				if !w.r.queues.AddOrUpdateWorkload(wlCopy) {
+					log.V(2).Info("Queue for workload didn't exist")
+				}
+			}
+		}
+	}
+}
+
 func (w *workloadQueueHandler) queueReconcileForWorkloadsOfLocalQueue(ctx context.Context, lq *kueue.LocalQueue, wq workqueue.RateLimitingInterface) {
 	log := ctrl.LoggerFrom(ctx)
 	lst := kueue.WorkloadList{}
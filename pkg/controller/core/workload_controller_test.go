@@ -30,16 +30,22 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/component-base/metrics/testutil"
 	testingclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	config "sigs.k8s.io/kueue/apis/config/v1beta1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/controller/core/indexer"
+	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	"sigs.k8s.io/kueue/pkg/workload"
 )
 
 func TestAdmittedNotReadyWorkload(t *testing.T) {
@@ -324,6 +330,7 @@ func TestReconcile(t *testing.T) {
 		workload       *kueue.Workload
 		cq             *kueue.ClusterQueue
 		lq             *kueue.LocalQueue
+		limitRanges    []*corev1.LimitRange
 		wantWorkload   *kueue.Workload
 		wantError      error
 		wantEvents     []utiltesting.EventRecord
@@ -522,6 +529,40 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
+		"deactivated workload with rejected checks gets evicted": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				ControllerReference(batchv1.SchemeGroupVersion.WithKind("Job"), "ownername", "owneruid").
+				ReserveQuota(utiltesting.MakeAdmission("q1").Obj()).
+				Active(false).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check",
+					State: kueue.CheckStateRejected,
+				}).
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				ControllerReference(batchv1.SchemeGroupVersion.WithKind("Job"), "ownername", "owneruid").
+				ReserveQuota(utiltesting.MakeAdmission("q1").Obj()).
+				Active(false).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check",
+					State: kueue.CheckStateRejected,
+				}).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionTrue,
+					Reason:  kueue.WorkloadEvictedByDeactivation,
+					Message: "The workload is deactivated",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: corev1.EventTypeNormal,
+					Reason:    "EvictedDueToInactiveWorkload",
+					Message:   "The workload is deactivated",
+				},
+			},
+		},
 		"admitted workload with retry checks": {
 			workload: utiltesting.MakeWorkload("wl", "ns").
 				ReserveQuota(utiltesting.MakeAdmission("q1").Obj()).
@@ -610,6 +651,178 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
+		"finish the pending workload that exceeded its active deadline": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				Creation(testStartTime.Add(-2 * time.Hour).Truncate(time.Second)).
+				ActiveDeadlineSeconds(3600).
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				Creation(testStartTime.Add(-2 * time.Hour).Truncate(time.Second)).
+				ActiveDeadlineSeconds(3600).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadFinished,
+					Status:  metav1.ConditionTrue,
+					Reason:  kueue.WorkloadFinishedReasonDeadlineExceeded,
+					Message: "The workload remained pending past its active deadline of 1h0m0s",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "wl", Namespace: "ns"},
+					EventType: corev1.EventTypeWarning,
+					Reason:    kueue.WorkloadFinishedReasonDeadlineExceeded,
+					Message:   "The workload remained pending past its active deadline of 1h0m0s",
+				},
+			},
+		},
+		"don't finish the pending workload before its active deadline": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				Creation(testStartTime.Add(-30 * time.Minute).Truncate(time.Second)).
+				ActiveDeadlineSeconds(3600).
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				Creation(testStartTime.Add(-30 * time.Minute).Truncate(time.Second)).
+				ActiveDeadlineSeconds(3600).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadQuotaReserved,
+					Status:  metav1.ConditionFalse,
+					Reason:  kueue.WorkloadInadmissible,
+					Message: "LocalQueue lq doesn't exist",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "wl", Namespace: "ns"},
+					EventType: corev1.EventTypeNormal,
+					Reason:    kueue.WorkloadInadmissible,
+					Message:   "LocalQueue lq doesn't exist",
+				},
+			},
+		},
+		"don't finish the workload with quota reserved after its active deadline": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Creation(testStartTime.Add(-2 * time.Hour).Truncate(time.Second)).
+				ActiveDeadlineSeconds(3600).
+				ReserveQuota(utiltesting.MakeAdmission("q1").Obj()).
+				Admitted(true).
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				Creation(testStartTime.Add(-2 * time.Hour).Truncate(time.Second)).
+				ActiveDeadlineSeconds(3600).
+				ReserveQuota(utiltesting.MakeAdmission("q1").Obj()).
+				Admitted(true).
+				Obj(),
+		},
+		"evict the workload when the admission checks exceed the timeout": {
+			reconcilerOpts: []Option{
+				WithAdmissionChecksTimeout(&admissionChecksTimeoutConfig{
+					timeout: 5 * time.Minute,
+					policy:  config.AdmissionChecksTimeoutRequeue,
+				}),
+			},
+			cq: utiltesting.MakeClusterQueue("q1").AdmissionChecks("check").Obj(),
+			lq: utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("q1").Obj(),
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				ReserveQuotaAt(utiltesting.MakeAdmission("q1").Obj(), testStartTime.Add(-10*time.Minute)).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check",
+					State: kueue.CheckStatePending,
+				}).
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				ReserveQuota(utiltesting.MakeAdmission("q1").Obj()).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check",
+					State: kueue.CheckStatePending,
+				}).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionTrue,
+					Reason:  kueue.WorkloadEvictedByAdmissionChecksTimeout,
+					Message: "Exceeded the admission checks timeout 5m0s",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "wl", Namespace: "ns"},
+					EventType: corev1.EventTypeNormal,
+					Reason:    "EvictedDueToAdmissionChecksTimeout",
+					Message:   "Exceeded the admission checks timeout 5m0s",
+				},
+			},
+		},
+		"deactivate the workload when the admission checks exceed the timeout": {
+			reconcilerOpts: []Option{
+				WithAdmissionChecksTimeout(&admissionChecksTimeoutConfig{
+					timeout: 5 * time.Minute,
+					policy:  config.AdmissionChecksTimeoutDeactivate,
+				}),
+			},
+			cq: utiltesting.MakeClusterQueue("q1").AdmissionChecks("check").Obj(),
+			lq: utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("q1").Obj(),
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				ReserveQuotaAt(utiltesting.MakeAdmission("q1").Obj(), testStartTime.Add(-10*time.Minute)).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check",
+					State: kueue.CheckStatePending,
+				}).
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				ReserveQuota(utiltesting.MakeAdmission("q1").Obj()).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check",
+					State: kueue.CheckStatePending,
+				}).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadDeactivationTarget,
+					Status:  metav1.ConditionTrue,
+					Reason:  kueue.WorkloadEvictedByAdmissionChecksTimeout,
+					Message: "Exceeded the admission checks timeout 5m0s",
+				}).
+				Obj(),
+		},
+		"keep the quota reservation while the admission checks are within the timeout": {
+			reconcilerOpts: []Option{
+				WithAdmissionChecksTimeout(&admissionChecksTimeoutConfig{
+					timeout: 5 * time.Minute,
+					policy:  config.AdmissionChecksTimeoutRequeue,
+				}),
+			},
+			cq: utiltesting.MakeClusterQueue("q1").AdmissionChecks("check", "check2").Obj(),
+			lq: utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("q1").Obj(),
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				ReserveQuotaAt(utiltesting.MakeAdmission("q1").Obj(), testStartTime.Add(-time.Minute)).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check",
+					State: kueue.CheckStateReady,
+				}).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check2",
+					State: kueue.CheckStatePending,
+				}).
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				Queue("lq").
+				ReserveQuota(utiltesting.MakeAdmission("q1").Obj()).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check",
+					State: kueue.CheckStateReady,
+				}).
+				AdmissionCheck(kueue.AdmissionCheckState{
+					Name:  "check2",
+					State: kueue.CheckStatePending,
+				}).
+				Obj(),
+		},
 		"trigger deactivation of workload when reaching backoffLimitCount": {
 			reconcilerOpts: []Option{
 				WithWaitForPodsReady(&waitForPodsReadyConfig{
@@ -1244,6 +1457,14 @@ func TestReconcile(t *testing.T) {
 					Message: "LocalQueue lq doesn't exist",
 				}).
 				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: "Normal",
+					Reason:    kueue.WorkloadInadmissible,
+					Message:   "LocalQueue lq doesn't exist",
+				},
+			},
 		},
 		"should set status QuotaReserved conditions to False with reason Inadmissible if quota not reserved LocalQueue StopPolicy=Hold": {
 			lq: utiltesting.MakeLocalQueue("lq", "ns").StopPolicy(kueue.Hold).Obj(),
@@ -1261,6 +1482,14 @@ func TestReconcile(t *testing.T) {
 					Message: "LocalQueue lq is inactive",
 				}).
 				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: "Normal",
+					Reason:    kueue.WorkloadInadmissible,
+					Message:   "LocalQueue lq is inactive",
+				},
+			},
 		},
 		"should set status QuotaReserved conditions to False with reason Inadmissible if quota not reserved LocalQueue StopPolicy=HoldAndDrain": {
 			lq: utiltesting.MakeLocalQueue("lq", "ns").StopPolicy(kueue.HoldAndDrain).Obj(),
@@ -1278,6 +1507,14 @@ func TestReconcile(t *testing.T) {
 					Message: "LocalQueue lq is inactive",
 				}).
 				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: "Normal",
+					Reason:    kueue.WorkloadInadmissible,
+					Message:   "LocalQueue lq is inactive",
+				},
+			},
 		},
 		"should set status QuotaReserved conditions to False with reason Inadmissible if quota not reserved ClusterQueue is not created": {
 			lq: utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").StopPolicy(kueue.None).Obj(),
@@ -1295,6 +1532,14 @@ func TestReconcile(t *testing.T) {
 					Message: "ClusterQueue cq doesn't exist",
 				}).
 				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: "Normal",
+					Reason:    kueue.WorkloadInadmissible,
+					Message:   "ClusterQueue cq doesn't exist",
+				},
+			},
 		},
 		"should set status QuotaReserved conditions to False with reason Inadmissible if quota not reserved ClusterQueue StopPolicy=Hold": {
 			lq: utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").StopPolicy(kueue.None).Obj(),
@@ -1313,11 +1558,85 @@ func TestReconcile(t *testing.T) {
 					Message: "ClusterQueue cq is inactive",
 				}).
 				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: "Normal",
+					Reason:    kueue.WorkloadInadmissible,
+					Message:   "ClusterQueue cq is inactive",
+				},
+			},
+		},
+		"should set status QuotaReserved conditions to False with reason RequestExceedsCapacity if a pod requests more than the ClusterQueue can provide": {
+			lq: utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").StopPolicy(kueue.None).Obj(),
+			cq: utiltesting.MakeClusterQueue("cq").
+				ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "2").Obj()).
+				Obj(),
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Active(true).
+				Queue("lq").
+				Request(corev1.ResourceCPU, "3").
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				Active(true).
+				Queue("lq").
+				Request(corev1.ResourceCPU, "3").
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadQuotaReserved,
+					Status:  metav1.ConditionFalse,
+					Reason:  kueue.WorkloadRequestExceedsCapacity,
+					Message: "cpu of podSet main requests 3 per pod, more than the maximum capacity of 2 of any flavor",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: "Normal",
+					Reason:    kueue.WorkloadRequestExceedsCapacity,
+					Message:   "cpu of podSet main requests 3 per pod, more than the maximum capacity of 2 of any flavor",
+				},
+			},
+		},
+		"should set status QuotaReserved conditions to False with reason ExceedsLimitRange if a pod exceeds the LimitRange maximum": {
+			lq: utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").StopPolicy(kueue.None).Obj(),
+			cq: utiltesting.MakeClusterQueue("cq").
+				ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+				Obj(),
+			limitRanges: []*corev1.LimitRange{
+				utiltesting.MakeLimitRange("limits", "ns").Max(corev1.ResourceCPU, "2").Obj(),
+			},
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Active(true).
+				Queue("lq").
+				Request(corev1.ResourceCPU, "3").
+				Obj(),
+			wantWorkload: utiltesting.MakeWorkload("wl", "ns").
+				Active(true).
+				Queue("lq").
+				Request(corev1.ResourceCPU, "3").
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadQuotaReserved,
+					Status:  metav1.ConditionFalse,
+					Reason:  kueue.WorkloadExceedsLimitRange,
+					Message: "exceeds the LimitRange maximum: cpu of podSets.main.containers[0] is 3, more than the LimitRange maximum of 2",
+				}).
+				Obj(),
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "ns", Name: "wl"},
+					EventType: "Normal",
+					Reason:    kueue.WorkloadExceedsLimitRange,
+					Message:   "exceeds the LimitRange maximum: cpu of podSets.main.containers[0] is 3, more than the LimitRange maximum of 2",
+				},
+			},
 		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			objs := []client.Object{tc.workload}
+			for _, lr := range tc.limitRanges {
+				objs = append(objs, lr)
+			}
 			clientBuilder := utiltesting.NewClientBuilder().WithObjects(objs...).WithStatusSubresource(objs...).WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: utiltesting.TreatSSAAsStrategicMerge})
 			cl := clientBuilder.Build()
 			recorder := &utiltesting.EventRecorder{}
@@ -1339,6 +1658,10 @@ func TestReconcile(t *testing.T) {
 				if err := qManager.AddClusterQueue(ctx, tc.cq); err != nil {
 					t.Errorf("couldn't add the cluster queue to the cache: %v", err)
 				}
+				cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+				if err := cqCache.AddClusterQueue(ctx, tc.cq); err != nil {
+					t.Errorf("couldn't add the cluster queue to the cache: %v", err)
+				}
 			}
 
 			if tc.lq != nil {
@@ -1372,3 +1695,187 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileRepairsCache(t *testing.T) {
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Queue("queue").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+		Admitted(true).
+		Obj()
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	cl := utiltesting.NewClientBuilder().WithObjects(wl, cq).WithStatusSubresource(wl).Build()
+	cqCache := cache.New(cl)
+	qManager := queue.NewManager(cl, cqCache)
+	reconciler := NewWorkloadReconciler(cl, qManager, cqCache, &utiltesting.EventRecorder{})
+
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add the ClusterQueue to the cache: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(wl), wl); err != nil {
+		t.Fatalf("Failed to get the workload: %v", err)
+	}
+	reconciler.Create(event.CreateEvent{Object: wl})
+	// Simulate the cache losing the admitted workload after its event was
+	// handled.
+	if err := cqCache.DeleteWorkload(wl); err != nil {
+		t.Fatalf("Failed to delete the workload from the cache: %v", err)
+	}
+	if !cqCache.WorkloadOutOfSync(wl) {
+		t.Fatal("The workload should be out of sync with the cache")
+	}
+
+	if _, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(wl)}); err != nil {
+		t.Fatalf("Unexpected reconcile error: %v", err)
+	}
+
+	if cqCache.WorkloadOutOfSync(wl) {
+		t.Error("The workload is still out of sync with the cache after reconciling")
+	}
+	stats, err := cqCache.Usage(cq)
+	if err != nil {
+		t.Fatalf("Failed to get the usage of the ClusterQueue: %v", err)
+	}
+	if stats.ReservingWorkloads != 1 {
+		t.Errorf("Unexpected reserving workloads in the cache, want 1, got %d", stats.ReservingWorkloads)
+	}
+}
+
+func TestReconcileBeforeEvictionIsHandled(t *testing.T) {
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Queue("queue").
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+		Admitted(true).
+		Obj()
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	cl := utiltesting.NewClientBuilder().
+		WithObjects(wl, cq).
+		WithStatusSubresource(wl).
+		WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: utiltesting.TreatSSAAsStrategicMerge}).
+		Build()
+	cqCache := cache.New(cl)
+	qManager := queue.NewManager(cl, cqCache)
+	reconciler := NewWorkloadReconciler(cl, qManager, cqCache, &utiltesting.EventRecorder{})
+
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add the ClusterQueue to the cache: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(wl), wl); err != nil {
+		t.Fatalf("Failed to get the workload: %v", err)
+	}
+	reconciler.Create(event.CreateEvent{Object: wl})
+
+	// Evict the workload. The reconciler sees the new version before the
+	// event handler removes the workload from the cache.
+	evicted := wl.DeepCopy()
+	workload.SetEvictedCondition(evicted, kueue.WorkloadEvictedByPreemption, "Preempted")
+	workload.UnsetQuotaReservationWithCondition(evicted, "Pending", "Preempted")
+	if err := cl.Status().Update(ctx, evicted); err != nil {
+		t.Fatalf("Failed to evict the workload: %v", err)
+	}
+	before := map[metrics.WorkloadCacheInconsistency]float64{}
+	for _, reason := range []metrics.WorkloadCacheInconsistency{metrics.WorkloadCacheInconsistencyMissing, metrics.WorkloadCacheInconsistencyUnexpected} {
+		value, err := testutil.GetCounterMetricValue(metrics.WorkloadCacheInconsistenciesTotal.WithLabelValues(string(reason)))
+		if err != nil {
+			t.Fatalf("Failed to get the metric: %v", err)
+		}
+		before[reason] = value
+	}
+
+	if _, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(wl)}); err != nil {
+		t.Fatalf("Unexpected reconcile error: %v", err)
+	}
+	stats, err := cqCache.Usage(cq)
+	if err != nil {
+		t.Fatalf("Failed to get the usage of the ClusterQueue: %v", err)
+	}
+	if stats.ReservingWorkloads != 1 {
+		t.Errorf("The reconciler changed the cache before the event was handled, want 1 reserving workload, got %d", stats.ReservingWorkloads)
+	}
+
+	// The reconciler might have updated the workload too, so the handler gets
+	// the latest version.
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(wl), evicted); err != nil {
+		t.Fatalf("Failed to get the workload: %v", err)
+	}
+	reconciler.Update(event.UpdateEvent{ObjectOld: wl, ObjectNew: evicted})
+	if _, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(wl)}); err != nil {
+		t.Fatalf("Unexpected reconcile error: %v", err)
+	}
+	if stats, err = cqCache.Usage(cq); err != nil {
+		t.Fatalf("Failed to get the usage of the ClusterQueue: %v", err)
+	}
+	if stats.ReservingWorkloads != 0 {
+		t.Errorf("Unexpected reserving workloads after the eviction, want 0, got %d", stats.ReservingWorkloads)
+	}
+	for reason, value := range before {
+		got, err := testutil.GetCounterMetricValue(metrics.WorkloadCacheInconsistenciesTotal.WithLabelValues(string(reason)))
+		if err != nil {
+			t.Fatalf("Failed to get the metric: %v", err)
+		}
+		if got != value {
+			t.Errorf("Unexpected %s inconsistencies reported for a regular eviction, want %v, got %v", reason, value, got)
+		}
+	}
+}
+
+func TestLimitRangeUpdateRequeuesWorkloadsExceedingIt(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	lq := utiltesting.MakeLocalQueue("queue", "ns").ClusterQueue("cq").Obj()
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Queue("queue").
+		Request(corev1.ResourceCPU, "3").
+		Condition(metav1.Condition{
+			Type:    kueue.WorkloadQuotaReserved,
+			Status:  metav1.ConditionFalse,
+			Reason:  kueue.WorkloadExceedsLimitRange,
+			Message: "exceeds the LimitRange maximum: cpu of podSets.main.containers[0] is 3, more than the LimitRange maximum of 2",
+		}).
+		Obj()
+	limitRange := utiltesting.MakeLimitRange("limits", "ns").WithValue("Max", corev1.ResourceCPU, "4").Obj()
+	cl := utiltesting.NewClientBuilder().
+		WithObjects(wl, lq, limitRange, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}).
+		WithIndex(&kueue.Workload{}, indexer.WorkloadQuotaReservedKey, indexer.IndexWorkloadQuotaReserved).
+		WithIndex(&corev1.LimitRange{}, indexer.LimitRangeHasContainerType, indexer.IndexLimitRangeHasContainerType).
+		Build()
+	cqCache := cache.New(cl)
+	qManager := queue.NewManager(cl, cqCache)
+	reconciler := NewWorkloadReconciler(cl, qManager, cqCache, &utiltesting.EventRecorder{})
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add the ClusterQueue to the cache: %v", err)
+	}
+	if err := qManager.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed to add the ClusterQueue: %v", err)
+	}
+	if err := qManager.AddLocalQueue(ctx, lq); err != nil {
+		t.Fatalf("Failed to add the LocalQueue: %v", err)
+	}
+	// The scheduler found the workload inadmissible.
+	heads := qManager.Heads(ctx)
+	if len(heads) != 1 {
+		t.Fatalf("Unexpected heads of the queues: %v", heads)
+	}
+	qManager.RequeueWorkload(ctx, &heads[0], queue.RequeueReasonGeneric)
+	if diff := cmp.Diff(map[string][]string{"cq": {"ns/wl"}}, qManager.DumpInadmissible()); diff != "" {
+		t.Fatalf("Unexpected inadmissible workloads (-want,+got):\n%s", diff)
+	}
+
+	h := &resourceUpdatesHandler{r: reconciler}
+	h.Update(ctx, event.UpdateEvent{ObjectOld: limitRange, ObjectNew: limitRange}, nil)
+	if diff := cmp.Diff(map[string][]string{"cq": {"ns/wl"}}, qManager.Dump()); diff != "" {
+		t.Errorf("Unexpected active workloads after the LimitRange update (-want,+got):\n%s", diff)
+	}
+}
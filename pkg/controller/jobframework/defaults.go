@@ -14,7 +14,14 @@ limitations under the License.
 package jobframework
 
 import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/kueue/pkg/controller/constants"
 )
 
 func ApplyDefaultForSuspend(job GenericJob, manageJobsWithoutQueueName bool) {
@@ -29,3 +36,30 @@ func ApplyDefaultForSuspend(job GenericJob, manageJobsWithoutQueueName bool) {
 		}
 	}
 }
+
+// ApplyDefaultQueueName sets the queue name of a job that doesn't have one
+// to the value of the nsLabelKey label of its namespace, if present.
+func ApplyDefaultQueueName(ctx context.Context, c client.Client, job GenericJob, nsLabelKey string) error {
+	if nsLabelKey == "" || QueueName(job) != "" {
+		return nil
+	}
+	// Do not default the queue name of a job whose owner is already managed by Kueue
+	if owner := metav1.GetControllerOf(job.Object()); owner != nil && IsOwnerManagedByKueue(owner) {
+		return nil
+	}
+	var ns corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKey{Name: job.Object().GetNamespace()}, &ns); err != nil {
+		return fmt.Errorf("failed to get namespace: %w", err)
+	}
+	queueName := ns.Labels[nsLabelKey]
+	if queueName == "" {
+		return nil
+	}
+	labels := job.Object().GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[constants.QueueLabel] = queueName
+	job.Object().SetLabels(labels)
+	return nil
+}
@@ -15,13 +15,16 @@ package jobframework
 
 // JobReconciler event reason list
 const (
-	ReasonStarted               = "Started"
-	ReasonSuspended             = "Suspended"
-	ReasonStopped               = "Stopped"
-	ReasonCreatedWorkload       = "CreatedWorkload"
-	ReasonDeletedWorkload       = "DeletedWorkload"
-	ReasonUpdatedWorkload       = "UpdatedWorkload"
-	ReasonFinishedWorkload      = "FinishedWorkload"
-	ReasonErrWorkloadCompose    = "ErrWorkloadCompose"
-	ReasonUpdatedAdmissionCheck = "UpdatedAdmissionCheck"
+	ReasonStarted                  = "Started"
+	ReasonSuspended                = "Suspended"
+	ReasonStopped                  = "Stopped"
+	ReasonCreatedWorkload          = "CreatedWorkload"
+	ReasonDeletedWorkload          = "DeletedWorkload"
+	ReasonUpdatedWorkload          = "UpdatedWorkload"
+	ReasonFinishedWorkload         = "FinishedWorkload"
+	ReasonErrWorkloadCompose       = "ErrWorkloadCompose"
+	ReasonUpdatedAdmissionCheck    = "UpdatedAdmissionCheck"
+	ReasonAdmittedButUnschedulable = "AdmittedButUnschedulable"
+	ReasonDeadlineExceeded         = "DeadlineExceeded"
+	ReasonScaledDown               = "ScaledDown"
 )
@@ -15,12 +15,15 @@ package jobframework
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
@@ -71,6 +74,7 @@ const (
 	StopReasonWorkloadEvicted    StopReason = "WorkloadEvicted"
 	StopReasonNoMatchingWorkload StopReason = "NoMatchingWorkload"
 	StopReasonNotAdmitted        StopReason = "NotAdmitted"
+	StopReasonDeadlineExceeded   StopReason = "DeadlineExceeded"
 )
 
 type JobWithCustomStop interface {
@@ -86,6 +90,15 @@ type JobWithFinalize interface {
 	Finalize(ctx context.Context, c client.Client) error
 }
 
+// JobWithFail interface should be implemented by generic jobs that can be
+// failed by Kueue, when their workload finishes before the job starts, for
+// example because it exceeded its activeDeadlineSeconds.
+type JobWithFail interface {
+	// Fail marks the job as failed with the given reason and message.
+	// The function should be idempotent: not do any API calls if the job is already failed.
+	Fail(ctx context.Context, c client.Client, reason, message string) error
+}
+
 // JobWithSkip interface should be implemented by generic jobs,
 // when reconciliation should be skipped depending on the job's state
 type JobWithSkip interface {
@@ -124,6 +137,32 @@ type JobWithCustomWorkloadConditions interface {
 	CustomWorkloadConditions(wl *kueue.Workload) ([]metav1.Condition, bool)
 }
 
+// JobWithAdmittedCondition interface should be implemented by generic jobs
+// that mirror the admission state of their workload in their own status.
+type JobWithAdmittedCondition interface {
+	// SyncAdmittedCondition updates the condition of the job reflecting the
+	// admission state of the workload. Returns whether the status changed.
+	SyncAdmittedCondition(wl *kueue.Workload) bool
+}
+
+// JobWithScaleDown interface should be implemented by generic jobs that can
+// run with fewer pods without being suspended, so that Kueue can scale them
+// down when part of their quota is reclaimed in the cohort.
+type JobWithScaleDown interface {
+	// ScaleDown reduces the counts of the running podSets to the counts in
+	// podSetsInfo, when they are lower. Returns whether any change was done.
+	ScaleDown(podSetsInfo []podset.PodSetInfo) bool
+}
+
+// JobWithPodLabels interface should be implemented by generic jobs whose pods
+// can be selected by labels, so that Kueue can monitor the pods of the
+// admitted job without watching all the pods in the cluster.
+type JobWithPodLabels interface {
+	// PodLabels returns the labels that select the pods of the job in its
+	// namespace.
+	PodLabels() map[string]string
+}
+
 func QueueName(job GenericJob) string {
 	return QueueNameForObject(job.Object())
 }
@@ -136,6 +175,35 @@ func QueueNameForObject(object client.Object) string {
 	return object.GetAnnotations()[constants.QueueAnnotation]
 }
 
+// activeDeadlineSeconds returns the activeDeadlineSeconds of the workload
+// of the job, from its annotation, or nil if it's not set or invalid.
+func activeDeadlineSeconds(job GenericJob) *int64 {
+	value, found := job.Object().GetAnnotations()[constants.ActiveDeadlineSecondsAnnotation]
+	if !found {
+		return nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return nil
+	}
+	return &seconds
+}
+
+// workloadActiveDeadlineSeconds returns the activeDeadlineSeconds of the
+// workload of the job, so that the deadline is measured from the creation of
+// the job rather than of the workload, which can be recreated.
+func workloadActiveDeadlineSeconds(job GenericJob, now time.Time) *int64 {
+	seconds := activeDeadlineSeconds(job)
+	created := job.Object().GetCreationTimestamp()
+	if seconds == nil || created.IsZero() {
+		return seconds
+	}
+	remaining := *seconds - int64(now.Sub(created.Time)/time.Second)
+	// The workload finishes right away if the job is already past its
+	// deadline.
+	return ptr.To(max(remaining, 1))
+}
+
 func workloadPriorityClassName(job GenericJob) string {
 	object := job.Object()
 	if workloadPriorityClassLabel := object.GetLabels()[constants.WorkloadPriorityClassLabel]; workloadPriorityClassLabel != "" {
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -48,6 +49,7 @@ import (
 	"sigs.k8s.io/kueue/pkg/util/equality"
 	"sigs.k8s.io/kueue/pkg/util/kubeversion"
 	"sigs.k8s.io/kueue/pkg/util/maps"
+	utilpod "sigs.k8s.io/kueue/pkg/util/pod"
 	utilpriority "sigs.k8s.io/kueue/pkg/util/priority"
 	"sigs.k8s.io/kueue/pkg/util/slices"
 	"sigs.k8s.io/kueue/pkg/workload"
@@ -55,6 +57,19 @@ import (
 
 const (
 	FailedToStartFinishedReason = "FailedToStart"
+
+	// PodsUnschedulableReason is the reason of the AdmittedButUnschedulable
+	// condition when pods are still unschedulable after the threshold.
+	PodsUnschedulableReason = "PodsUnschedulable"
+	// PodsReadyReason is the reason of the AdmittedButUnschedulable
+	// condition when the pods became ready.
+	PodsReadyReason = "PodsReady"
+	// PodsScheduledReason is the reason of the AdmittedButUnschedulable
+	// condition when no pod is unschedulable anymore.
+	PodsScheduledReason = "PodsScheduled"
+	// ReadmittedReason is the reason of the AdmittedButUnschedulable
+	// condition when it was set during a previous admission.
+	ReadmittedReason = "Readmitted"
 )
 
 var (
@@ -71,6 +86,13 @@ type JobReconciler struct {
 	manageJobsWithoutQueueName bool
 	waitForPodsReady           bool
 	labelKeysToCopy            []string
+	// apiReader lists the pods of the jobs whose pods are not ready, so that
+	// the manager doesn't cache the pods of the cluster.
+	apiReader client.Reader
+	// admittedButUnschedulableThreshold is the time the pods of a started
+	// job can remain unschedulable before the workload is marked as
+	// AdmittedButUnschedulable. Zero disables the monitoring.
+	admittedButUnschedulableThreshold time.Duration
 }
 
 type Options struct {
@@ -85,6 +107,19 @@ type Options struct {
 	LabelKeysToCopy           []string
 	Queues                    *queue.Manager
 	Cache                     *cache.Cache
+	// QueueNameRequiredNamespaceSelector selects the namespaces in which jobs
+	// without a queue name are rejected.
+	QueueNameRequiredNamespaceSelector *metav1.LabelSelector
+	// DefaultQueueNamespaceLabel is the label key of the namespaces holding
+	// the queue name for the jobs created without one.
+	DefaultQueueNamespaceLabel string
+	// AdmittedButUnschedulableThreshold is the time the pods of a started
+	// job can remain unschedulable before the workload is marked as
+	// AdmittedButUnschedulable.
+	AdmittedButUnschedulableThreshold time.Duration
+	// APIReader reads objects without the manager cache. Defaults to the
+	// client of the reconciler.
+	APIReader client.Reader
 }
 
 // Option configures the reconciler.
@@ -180,6 +215,43 @@ func WithCache(c *cache.Cache) Option {
 	}
 }
 
+// WithQueueNameEnforcement sets the namespaces in which the webhooks reject
+// jobs that don't have a queue name.
+func WithQueueNameEnforcement(e *configapi.QueueNameEnforcement) Option {
+	return func(o *Options) {
+		if e != nil {
+			o.QueueNameRequiredNamespaceSelector = e.NamespaceSelector
+		}
+	}
+}
+
+// WithDefaultQueueNamespaceLabel sets the label key of the namespaces holding
+// the queue name that the webhooks set in the jobs created without one.
+func WithDefaultQueueNamespaceLabel(key string) Option {
+	return func(o *Options) {
+		o.DefaultQueueNamespaceLabel = key
+	}
+}
+
+// WithAdmittedButUnschedulableThreshold sets the time the pods of a started
+// job can remain unschedulable before the workload is marked as
+// AdmittedButUnschedulable.
+func WithAdmittedButUnschedulableThreshold(d *metav1.Duration) Option {
+	return func(o *Options) {
+		if d != nil {
+			o.AdmittedButUnschedulableThreshold = d.Duration
+		}
+	}
+}
+
+// WithAPIReader sets the reader used to read objects without the manager
+// cache.
+func WithAPIReader(r client.Reader) Option {
+	return func(o *Options) {
+		o.APIReader = r
+	}
+}
+
 var defaultOptions = Options{}
 
 func NewReconciler(
@@ -187,6 +259,10 @@ func NewReconciler(
 	record record.EventRecorder,
 	opts ...Option) *JobReconciler {
 	options := ProcessOptions(opts...)
+	apiReader := options.APIReader
+	if apiReader == nil {
+		apiReader = client
+	}
 
 	return &JobReconciler{
 		client:                     client,
@@ -194,6 +270,9 @@ func NewReconciler(
 		manageJobsWithoutQueueName: options.ManageJobsWithoutQueueName,
 		waitForPodsReady:           options.WaitForPodsReady,
 		labelKeysToCopy:            options.LabelKeysToCopy,
+		apiReader:                  apiReader,
+
+		admittedButUnschedulableThreshold: options.AdmittedButUnschedulableThreshold,
 	}
 }
 
@@ -325,6 +404,9 @@ func (r *JobReconciler) ReconcileGenericJob(ctx context.Context, req ctrl.Reques
 	}
 
 	if wl != nil && apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
+		if err := r.failJobIfDeadlineExceeded(ctx, job, wl); err != nil {
+			return ctrl.Result{}, err
+		}
 		if err := r.finalizeJob(ctx, job); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -404,6 +486,15 @@ func (r *JobReconciler) ReconcileGenericJob(ctx context.Context, req ctrl.Reques
 		}
 	}
 
+	// 4.1 mirror the admission state of the workload in the job status, if implemented by the job
+	if jobAdm, ok := job.(JobWithAdmittedCondition); ok && features.Enabled(features.JobAdmittedCondition) && jobAdm.SyncAdmittedCondition(wl) {
+		log.V(3).Info("Updating the admitted condition of the job")
+		if err := r.client.Status().Update(ctx, object); err != nil {
+			log.Error(err, "Updating the admitted condition of the job")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// 5. handle WaitForPodsReady only for a standalone job.
 	// handle a job when waitForPodsReady is enabled, and it is the main job
 	if r.waitForPodsReady {
@@ -429,8 +520,10 @@ func (r *JobReconciler) ReconcileGenericJob(ctx context.Context, req ctrl.Reques
 		if workload.HasQuotaReservation(wl) {
 			if !job.IsActive() {
 				log.V(6).Info("The job is no longer active, clear the workloads admission")
-				// The requeued condition status set to true only on EvictedByPreemption or EvictedByAdmissionCheck
-				setRequeued := evCond.Reason == kueue.WorkloadEvictedByPreemption || evCond.Reason == kueue.WorkloadEvictedByAdmissionCheck
+				// The requeued condition status set to true only on EvictedByPreemption, EvictedByAdmissionCheck
+				// or EvictedByAdmissionChecksTimeout
+				setRequeued := evCond.Reason == kueue.WorkloadEvictedByPreemption || evCond.Reason == kueue.WorkloadEvictedByAdmissionCheck ||
+					evCond.Reason == kueue.WorkloadEvictedByAdmissionChecksTimeout
 				workload.SetRequeuedCondition(wl, evCond.Reason, evCond.Message, setRequeued)
 				_ = workload.UnsetQuotaReservationWithCondition(wl, "Pending", evCond.Message)
 				err := workload.ApplyAdmissionStatus(ctx, r.client, wl, true)
@@ -491,11 +584,105 @@ func (r *JobReconciler) ReconcileGenericJob(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	// 9. scale down the running job if its admitted counts were reduced.
+	if jobScaleDown, ok := job.(JobWithScaleDown); ok && features.Enabled(features.PartialAdmissionScaleDown) {
+		podSetsInfo, err := getPodSetsInfoFromStatus(ctx, r.client, wl)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if jobScaleDown.ScaleDown(podSetsInfo) {
+			log.V(2).Info("Scaling down the job to the admitted counts")
+			if err := r.client.Update(ctx, object); err != nil {
+				log.Error(err, "Scaling down the job")
+				return ctrl.Result{}, err
+			}
+			r.record.Eventf(object, corev1.EventTypeNormal, ReasonScaledDown, "Scaled down to %s to release the quota reclaimed in the cohort", podSetsCountsMessage(podSetsInfo))
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// 10. report the pods that don't become ready after the job is started.
+	if jobWithPodLabels, ok := job.(JobWithPodLabels); ok && r.admittedButUnschedulableThreshold > 0 {
+		return r.reconcileAdmittedButUnschedulable(ctx, job, jobWithPodLabels.PodLabels(), wl)
+	}
+
 	// workload is admitted and job is running, nothing to do.
 	log.V(3).Info("Job running with admitted workload, nothing to do")
 	return ctrl.Result{}, nil
 }
 
+// reconcileAdmittedButUnschedulable sets the AdmittedButUnschedulable
+// condition of the workload when pods of the running job are unschedulable
+// beyond the threshold after the workload is admitted, and clears it when
+// they are scheduled. The pods are checked again every threshold while the
+// job has pods that are not ready.
+func (r *JobReconciler) reconcileAdmittedButUnschedulable(ctx context.Context, job GenericJob, podLabels map[string]string, wl *kueue.Workload) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	admittedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmitted)
+	cond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmittedButUnschedulable)
+	// The condition is stale if it was set during a previous admission.
+	unschedulable := cond != nil && cond.Status == metav1.ConditionTrue && !cond.LastTransitionTime.Before(&admittedCond.LastTransitionTime)
+
+	if job.PodsReady() {
+		if unschedulable {
+			log.V(2).Info("The pods of the job became ready")
+			return ctrl.Result{}, workload.UpdateStatus(ctx, r.client, wl, kueue.WorkloadAdmittedButUnschedulable, metav1.ConditionFalse,
+				PodsReadyReason, "The pods are ready", constants.JobControllerName)
+		}
+		return ctrl.Result{}, nil
+	}
+	if !unschedulable && cond != nil && cond.Status == metav1.ConditionTrue {
+		log.V(2).Info("Clearing the AdmittedButUnschedulable condition of a previous admission")
+		return ctrl.Result{}, workload.UpdateStatus(ctx, r.client, wl, kueue.WorkloadAdmittedButUnschedulable, metav1.ConditionFalse,
+			ReadmittedReason, "The workload was admitted again", constants.JobControllerName)
+	}
+	if remaining := r.admittedButUnschedulableThreshold - time.Since(admittedCond.LastTransitionTime.Time); remaining > 0 {
+		log.V(3).Info("Waiting for the pods of the job to be scheduled", "remaining", remaining)
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	count, err := r.unschedulablePods(ctx, job.Object().GetNamespace(), podLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if unschedulable {
+		if count == 0 {
+			log.V(2).Info("The pods of the job are no longer unschedulable")
+			return ctrl.Result{}, workload.UpdateStatus(ctx, r.client, wl, kueue.WorkloadAdmittedButUnschedulable, metav1.ConditionFalse,
+				PodsScheduledReason, "No pod is unschedulable", constants.JobControllerName)
+		}
+		return ctrl.Result{RequeueAfter: r.admittedButUnschedulableThreshold}, nil
+	}
+	if count == 0 {
+		return ctrl.Result{RequeueAfter: r.admittedButUnschedulableThreshold}, nil
+	}
+	message := fmt.Sprintf("Pods are unschedulable %s after the workload was admitted: %d", r.admittedButUnschedulableThreshold, count)
+	log.V(2).Info("Pods of the job are unschedulable beyond the threshold", "count", count, "threshold", r.admittedButUnschedulableThreshold)
+	if err := workload.UpdateStatus(ctx, r.client, wl, kueue.WorkloadAdmittedButUnschedulable, metav1.ConditionTrue,
+		PodsUnschedulableReason, message, constants.JobControllerName); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.record.Event(job.Object(), corev1.EventTypeWarning, ReasonAdmittedButUnschedulable, message)
+	return ctrl.Result{RequeueAfter: r.admittedButUnschedulableThreshold}, nil
+}
+
+// unschedulablePods returns the number of pods of the job whose
+// PodScheduled condition is False with the Unschedulable reason. The pods are
+// listed through the API reader, only when the pods of the job are not ready
+// after the threshold, so that no informer is started for the pods.
+func (r *JobReconciler) unschedulablePods(ctx context.Context, namespace string, podLabels map[string]string) (int, error) {
+	var pods corev1.PodList
+	if err := r.apiReader.List(ctx, &pods, client.InNamespace(namespace), client.MatchingLabels(podLabels)); err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := range pods.Items {
+		if utilpod.Unschedulable(&pods.Items[i]) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (r *JobReconciler) recordAdmissionCheckUpdate(wl *kueue.Workload, job GenericJob) {
 	message := ""
 	object := job.Object()
@@ -733,7 +920,7 @@ func expectedRunningPodSets(ctx context.Context, c client.Client, wl *kueue.Work
 		if err != nil {
 			return nil
 		}
-		if canBePartiallyAdmitted && ps.MinCount != nil {
+		if (canBePartiallyAdmitted && ps.MinCount != nil) || workload.IsOptional(ps) {
 			// update the expected running count
 			ps.Count = psi.Count
 		}
@@ -756,6 +943,12 @@ func equivalentToWorkload(ctx context.Context, c client.Client, job GenericJob,
 		if equality.ComparePodSetSlices(jobPodSets, runningPodSets, workload.IsAdmitted(wl)) {
 			return true
 		}
+		// A running job that can be scaled down is still equivalent while it
+		// runs more pods than admitted, until it's scaled down.
+		if _, canScaleDown := job.(JobWithScaleDown); canScaleDown && features.Enabled(features.PartialAdmissionScaleDown) && !job.IsSuspended() &&
+			equality.ComparePodSetSlices(jobPodSets, podSetsPendingScaleDown(jobPodSets, runningPodSets, wl.Spec.PodSets), workload.IsAdmitted(wl)) {
+			return true
+		}
 		// If the workload is admitted but the job is suspended, do the check
 		// against the non-running info.
 		// This might allow some violating jobs to pass equivalency checks, but their
@@ -766,6 +959,33 @@ func equivalentToWorkload(ctx context.Context, c client.Client, job GenericJob,
 	return equality.ComparePodSetSlices(jobPodSets, wl.Spec.PodSets, workload.IsAdmitted(wl))
 }
 
+// podSetsPendingScaleDown returns a copy of the expected running podSets that
+// takes the counts of the job for the podSets where the job runs more pods
+// than admitted, but not more than requested by the workload.
+func podSetsPendingScaleDown(jobPodSets, runningPodSets, specPodSets []kueue.PodSet) []kueue.PodSet {
+	if len(jobPodSets) != len(runningPodSets) || len(specPodSets) != len(runningPodSets) {
+		return runningPodSets
+	}
+	ret := make([]kueue.PodSet, len(runningPodSets))
+	for i := range runningPodSets {
+		ret[i] = runningPodSets[i]
+		if jobCount := jobPodSets[i].Count; jobCount > ret[i].Count && jobCount <= specPodSets[i].Count {
+			ret[i].Count = jobCount
+		}
+	}
+	return ret
+}
+
+// podSetsCountsMessage returns a human readable list of the counts of every
+// podSet.
+func podSetsCountsMessage(podSetsInfo []podset.PodSetInfo) string {
+	counts := make([]string, 0, len(podSetsInfo))
+	for _, info := range podSetsInfo {
+		counts = append(counts, fmt.Sprintf("%s=%d", info.Name, info.Count))
+	}
+	return strings.Join(counts, ", ")
+}
+
 func (r *JobReconciler) updateWorkloadToMatchJob(ctx context.Context, job GenericJob, object client.Object, wl *kueue.Workload) (*kueue.Workload, error) {
 	newWl, err := r.constructWorkload(ctx, job, object)
 	if err != nil {
@@ -866,6 +1086,29 @@ func (r *JobReconciler) finalizeJob(ctx context.Context, job GenericJob) error {
 	return nil
 }
 
+// failJobIfDeadlineExceeded keeps the job suspended if its workload finished
+// because it exceeded its activeDeadlineSeconds, and fails it if the job
+// supports it. The reason is recorded as an event.
+func (r *JobReconciler) failJobIfDeadlineExceeded(ctx context.Context, job GenericJob, wl *kueue.Workload) error {
+	cond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadFinished)
+	if cond.Reason != kueue.WorkloadFinishedReasonDeadlineExceeded {
+		return nil
+	}
+	if _, _, finished := job.Finished(); finished {
+		return nil
+	}
+	if err := r.stopJob(ctx, job, wl, StopReasonDeadlineExceeded, cond.Message); err != nil {
+		return err
+	}
+	if jf, implements := job.(JobWithFail); implements {
+		if err := jf.Fail(ctx, r.client, cond.Reason, cond.Message); err != nil {
+			return err
+		}
+	}
+	r.record.Event(job.Object(), corev1.EventTypeWarning, ReasonDeadlineExceeded, cond.Message)
+	return nil
+}
+
 // constructWorkload will derive a workload from the corresponding job.
 func (r *JobReconciler) constructWorkload(ctx context.Context, job GenericJob, object client.Object) (*kueue.Workload, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -889,8 +1132,9 @@ func (r *JobReconciler) constructWorkload(ctx context.Context, job GenericJob, o
 			Annotations: admissioncheck.FilterProvReqAnnotations(job.Object().GetAnnotations()),
 		},
 		Spec: kueue.WorkloadSpec{
-			PodSets:   podSets,
-			QueueName: QueueName(job),
+			PodSets:               podSets,
+			QueueName:             QueueName(job),
+			ActiveDeadlineSeconds: workloadActiveDeadlineSeconds(job, time.Now()),
 		},
 	}
 	if wl.Labels == nil {
@@ -14,14 +14,19 @@ limitations under the License.
 package jobframework
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	jobset "sigs.k8s.io/jobset/api/jobset/v1alpha2"
 
 	"sigs.k8s.io/kueue/pkg/controller/constants"
@@ -38,7 +43,9 @@ var (
 
 // ValidateJobOnCreate encapsulates all GenericJob validations that must be performed on a Create operation
 func ValidateJobOnCreate(job GenericJob) field.ErrorList {
-	return validateCreateForQueueName(job)
+	allErrs := validateCreateForQueueName(job)
+	allErrs = append(allErrs, validateActiveDeadlineSeconds(job)...)
+	return allErrs
 }
 
 // ValidateJobOnUpdate encapsulates all GenericJob validations that must be performed on a Update operation
@@ -64,6 +71,30 @@ func validateCreateForQueueName(job GenericJob) field.ErrorList {
 	return allErrs
 }
 
+// ValidateQueueNameRequired rejects jobs without a queue name in the namespaces
+// matching nsSelector. Jobs owned by another job managed by Kueue are exempt,
+// as they are queued through their owner.
+func ValidateQueueNameRequired(ctx context.Context, c client.Client, nsSelector *metav1.LabelSelector, job GenericJob) (field.ErrorList, error) {
+	if nsSelector == nil || QueueName(job) != "" {
+		return nil, nil
+	}
+	if owner := metav1.GetControllerOf(job.Object()); owner != nil && IsOwnerManagedByKueue(owner) {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(nsSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse namespace selector: %w", err)
+	}
+	var ns corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKey{Name: job.Object().GetNamespace()}, &ns); err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	if !selector.Matches(labels.Set(ns.Labels)) {
+		return nil, nil
+	}
+	return field.ErrorList{field.Required(queueNameLabelPath, fmt.Sprintf("a queue name is required in namespace %q", ns.Name))}, nil
+}
+
 func ValidateAnnotationAsCRDName(job GenericJob, crdNameAnnotation string) field.ErrorList {
 	var allErrs field.ErrorList
 	if value, exists := job.Object().GetAnnotations()[crdNameAnnotation]; exists {
@@ -74,6 +105,14 @@ func ValidateAnnotationAsCRDName(job GenericJob, crdNameAnnotation string) field
 	return allErrs
 }
 
+func validateActiveDeadlineSeconds(job GenericJob) field.ErrorList {
+	var allErrs field.ErrorList
+	if value, exists := job.Object().GetAnnotations()[constants.ActiveDeadlineSecondsAnnotation]; exists && activeDeadlineSeconds(job) == nil {
+		allErrs = append(allErrs, field.Invalid(annotationsPath.Key(constants.ActiveDeadlineSecondsAnnotation), value, "must be a positive integer"))
+	}
+	return allErrs
+}
+
 func ValidateLabelAsCRDName(job GenericJob, crdNameLabel string) field.ErrorList {
 	var allErrs field.ErrorList
 	if value, exists := job.Object().GetLabels()[crdNameLabel]; exists {
@@ -23,6 +23,7 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -52,6 +53,10 @@ var (
 const (
 	JobMinParallelismAnnotation              = "kueue.x-k8s.io/job-min-parallelism"
 	JobCompletionsEqualParallelismAnnotation = "kueue.x-k8s.io/job-completions-equal-parallelism"
+
+	// JobAdmitted is the type of the Job condition that mirrors the
+	// admission state of the Job's workload.
+	JobAdmitted batchv1.JobConditionType = "kueue.x-k8s.io/Admitted"
 )
 
 func init() {
@@ -141,6 +146,9 @@ type Job batchv1.Job
 var _ jobframework.GenericJob = (*Job)(nil)
 var _ jobframework.JobWithReclaimablePods = (*Job)(nil)
 var _ jobframework.JobWithCustomStop = (*Job)(nil)
+var _ jobframework.JobWithScaleDown = (*Job)(nil)
+var _ jobframework.JobWithPodLabels = (*Job)(nil)
+var _ jobframework.JobWithFail = (*Job)(nil)
 
 func (j *Job) Object() client.Object {
 	return (*batchv1.Job)(j)
@@ -278,6 +286,17 @@ func (j *Job) RestorePodSetsInfo(podSetsInfo []podset.PodSetInfo) bool {
 	return changed
 }
 
+func (j *Job) ScaleDown(podSetsInfo []podset.PodSetInfo) bool {
+	if len(podSetsInfo) != 1 || j.minPodsCount() == nil || ptr.Deref(j.Spec.Parallelism, 1) <= podSetsInfo[0].Count {
+		return false
+	}
+	j.Spec.Parallelism = ptr.To(podSetsInfo[0].Count)
+	if j.syncCompletionWithParallelism() {
+		j.Spec.Completions = j.Spec.Parallelism
+	}
+	return true
+}
+
 func (j *Job) Finished() (message string, success, finished bool) {
 	for _, c := range j.Status.Conditions {
 		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
@@ -288,11 +307,73 @@ func (j *Job) Finished() (message string, success, finished bool) {
 	return "", true, false
 }
 
+// Fail adds the Failed condition to the suspended job, so that it's
+// considered finished by the job controller without ever starting.
+func (j *Job) Fail(ctx context.Context, c client.Client, reason, message string) error {
+	if _, _, finished := j.Finished(); finished {
+		return nil
+	}
+	now := metav1.Now()
+	j.Status.Conditions = append(j.Status.Conditions, batchv1.JobCondition{
+		Type:               batchv1.JobFailed,
+		Status:             corev1.ConditionTrue,
+		LastProbeTime:      now,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	})
+	return c.Status().Update(ctx, j.Object())
+}
+
+// SyncAdmittedCondition sets the JobAdmitted condition of the job to match
+// the Admitted condition of the workload, or its QuotaReserved condition
+// while the workload doesn't have one. The job is not changed until the
+// workload has any of them.
+func (j *Job) SyncAdmittedCondition(wl *kueue.Workload) bool {
+	var status corev1.ConditionStatus
+	var reason, message string
+	if c := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmitted); c != nil {
+		status, reason, message = corev1.ConditionStatus(c.Status), c.Reason, c.Message
+	} else if c := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadQuotaReserved); c != nil {
+		status, reason, message = corev1.ConditionFalse, c.Reason, c.Message
+	} else {
+		return false
+	}
+	now := metav1.Now()
+	for i := range j.Status.Conditions {
+		c := &j.Status.Conditions[i]
+		if c.Type != JobAdmitted {
+			continue
+		}
+		if c.Status == status && c.Reason == reason && c.Message == message {
+			return false
+		}
+		if c.Status != status {
+			c.LastTransitionTime = now
+		}
+		c.Status, c.Reason, c.Message, c.LastProbeTime = status, reason, message, now
+		return true
+	}
+	j.Status.Conditions = append(j.Status.Conditions, batchv1.JobCondition{
+		Type:               JobAdmitted,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastProbeTime:      now,
+		LastTransitionTime: now,
+	})
+	return true
+}
+
 func (j *Job) PodsReady() bool {
 	ready := ptr.Deref(j.Status.Ready, 0)
 	return j.Status.Succeeded+ready >= j.podsCount()
 }
 
+func (j *Job) PodLabels() map[string]string {
+	return map[string]string{batchv1.ControllerUidLabel: string(j.UID)}
+}
+
 func (j *Job) podsCount() int32 {
 	// parallelism is always set as it is otherwise defaulted by k8s to 1
 	podsCount := *(j.Spec.Parallelism)
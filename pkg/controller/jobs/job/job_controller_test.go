@@ -17,6 +17,7 @@ limitations under the License.
 package job
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -37,9 +38,11 @@ import (
 	"sigs.k8s.io/kueue/pkg/constants"
 	controllerconsts "sigs.k8s.io/kueue/pkg/controller/constants"
 	"sigs.k8s.io/kueue/pkg/controller/jobframework"
+	"sigs.k8s.io/kueue/pkg/features"
 	"sigs.k8s.io/kueue/pkg/podset"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
 	utiltestingjob "sigs.k8s.io/kueue/pkg/util/testingjobs/job"
+	utiltestingpod "sigs.k8s.io/kueue/pkg/util/testingjobs/pod"
 )
 
 func TestPodsReady(t *testing.T) {
@@ -395,7 +398,24 @@ var (
 	}
 )
 
+var unschedulableCondition = corev1.PodCondition{
+	Type:   corev1.PodScheduled,
+	Status: corev1.ConditionFalse,
+	Reason: corev1.PodReasonUnschedulable,
+}
+
+func admittedCondition(t time.Time) metav1.Condition {
+	return metav1.Condition{
+		Type:               kueue.WorkloadAdmitted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(t),
+		Reason:             "ByTest",
+		Message:            "Admitted by ClusterQueue cq",
+	}
+}
+
 func TestReconciler(t *testing.T) {
+	twoHoursAgo := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
 	baseJobWrapper := utiltestingjob.MakeJob("job", "ns").
 		Suspend(true).
 		Queue("foo").
@@ -408,6 +428,23 @@ func TestReconciler(t *testing.T) {
 		PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 10).Request(corev1.ResourceCPU, "1").Obj()).
 		ReserveQuota(utiltesting.MakeAdmission("cq").AssignmentPodCount(10).Obj())
 
+	evictedWorkloadWrapper := utiltesting.MakeWorkload("wl", "ns").
+		Queue("foo").
+		Finalizers(kueue.ResourceInUseFinalizerName).
+		PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 10).Request(corev1.ResourceCPU, "1").Obj()).
+		Condition(metav1.Condition{
+			Type:    kueue.WorkloadAdmitted,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoReservation",
+			Message: "The workload has no reservation",
+		}).
+		Condition(metav1.Condition{
+			Type:    kueue.WorkloadQuotaReserved,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Pending",
+			Message: "Preempted",
+		})
+
 	baseWPCWrapper := utiltesting.MakeWorkloadPriorityClass("test-wpc").
 		PriorityValue(100)
 
@@ -415,15 +452,19 @@ func TestReconciler(t *testing.T) {
 		PriorityValue(200)
 
 	cases := map[string]struct {
-		reconcilerOptions []jobframework.Option
-		job               batchv1.Job
-		workloads         []kueue.Workload
-		otherJobs         []batchv1.Job
-		priorityClasses   []client.Object
-		wantJob           batchv1.Job
-		wantWorkloads     []kueue.Workload
-		wantEvents        []utiltesting.EventRecord
-		wantErr           error
+		reconcilerOptions          []jobframework.Option
+		enableJobAdmittedCondition bool
+		enableScaleDown            bool
+		ignoreJobConditionTimes    bool
+		job                        batchv1.Job
+		workloads                  []kueue.Workload
+		otherJobs                  []batchv1.Job
+		pods                       []corev1.Pod
+		priorityClasses            []client.Object
+		wantJob                    batchv1.Job
+		wantWorkloads              []kueue.Workload
+		wantEvents                 []utiltesting.EventRecord
+		wantErr                    error
 	}{
 		"when workload is created, it has its owner ProvReq annotations": {
 			job: *baseJobWrapper.Clone().
@@ -457,6 +498,66 @@ func TestReconciler(t *testing.T) {
 				},
 			},
 		},
+		"when workload is created, it has the active deadline of its owner": {
+			job: *baseJobWrapper.Clone().
+				SetAnnotation(controllerconsts.ActiveDeadlineSecondsAnnotation, "3600").
+				UID("test-uid").
+				Obj(),
+			wantJob: *baseJobWrapper.Clone().
+				SetAnnotation(controllerconsts.ActiveDeadlineSecondsAnnotation, "3600").
+				UID("test-uid").
+				Suspend(true).
+				Obj(),
+			wantWorkloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("job", "ns").
+					Finalizers(kueue.ResourceInUseFinalizerName).
+					PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 10).Request(corev1.ResourceCPU, "1").Obj()).
+					Queue("foo").
+					Priority(0).
+					ActiveDeadlineSeconds(3600).
+					Labels(map[string]string{controllerconsts.JobUIDLabel: "test-uid"}).
+					Obj(),
+			},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Normal",
+					Reason:    "CreatedWorkload",
+					Message:   "Created Workload: ns/" + GetWorkloadNameForJob(baseJobWrapper.Name, types.UID("test-uid")),
+				},
+			},
+		},
+		"when workload is created after the active deadline of its owner, the deadline is measured from the creation of the owner": {
+			job: *baseJobWrapper.Clone().
+				SetAnnotation(controllerconsts.ActiveDeadlineSecondsAnnotation, "3600").
+				CreationTimestamp(twoHoursAgo).
+				UID("test-uid").
+				Obj(),
+			wantJob: *baseJobWrapper.Clone().
+				SetAnnotation(controllerconsts.ActiveDeadlineSecondsAnnotation, "3600").
+				CreationTimestamp(twoHoursAgo).
+				UID("test-uid").
+				Suspend(true).
+				Obj(),
+			wantWorkloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("job", "ns").
+					Finalizers(kueue.ResourceInUseFinalizerName).
+					PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 10).Request(corev1.ResourceCPU, "1").Obj()).
+					Queue("foo").
+					Priority(0).
+					ActiveDeadlineSeconds(1).
+					Labels(map[string]string{controllerconsts.JobUIDLabel: "test-uid"}).
+					Obj(),
+			},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Normal",
+					Reason:    "CreatedWorkload",
+					Message:   "Created Workload: ns/" + GetWorkloadNameForJob(baseJobWrapper.Name, types.UID("test-uid")),
+				},
+			},
+		},
 		"when workload is created, it has correct labels set": {
 			job: *baseJobWrapper.Clone().
 				Label("toCopyKey", "toCopyValue").
@@ -624,6 +725,52 @@ func TestReconciler(t *testing.T) {
 				},
 			},
 		},
+		"when workload is evicted after an admission check was rejected, job stays suspended and quota is unset": {
+			job:     *baseJobWrapper.Clone().Obj(),
+			wantJob: *baseJobWrapper.Clone().Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Active(false).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadEvicted,
+						Status:  metav1.ConditionTrue,
+						Reason:  kueue.WorkloadEvictedByDeactivation,
+						Message: "The workload is deactivated",
+					}).
+					AdmissionCheck(kueue.AdmissionCheckState{
+						Name:  "check",
+						State: kueue.CheckStateRejected,
+					}).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Active(false).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadQuotaReserved,
+						Status:  metav1.ConditionFalse,
+						Reason:  "Pending",
+						Message: "The workload is deactivated",
+					}).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadRequeued,
+						Status:  metav1.ConditionFalse,
+						Reason:  kueue.WorkloadEvictedByDeactivation,
+						Message: "The workload is deactivated",
+					}).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadEvicted,
+						Status:  metav1.ConditionTrue,
+						Reason:  kueue.WorkloadEvictedByDeactivation,
+						Message: "The workload is deactivated",
+					}).
+					AdmissionCheck(kueue.AdmissionCheckState{
+						Name:  "check",
+						State: kueue.CheckStateRejected,
+					}).
+					Obj(),
+			},
+		},
 		"when workload is evicted due to pods ready timeout, job gets suspended and quota is unset": {
 			job: *baseJobWrapper.Clone().
 				Suspend(false).
@@ -760,6 +907,50 @@ func TestReconciler(t *testing.T) {
 				},
 			},
 		},
+		"when workload is evicted due to admission checks timeout, the quota reservation is released": {
+			job:     *baseJobWrapper.Clone().Obj(),
+			wantJob: *baseJobWrapper.Clone().Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadEvicted,
+						Status:  metav1.ConditionTrue,
+						Reason:  kueue.WorkloadEvictedByAdmissionChecksTimeout,
+						Message: "Exceeded the admission checks timeout 5m0s",
+					}).
+					AdmissionCheck(kueue.AdmissionCheckState{
+						Name:  "check",
+						State: kueue.CheckStatePending,
+					}).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadQuotaReserved,
+						Status:  metav1.ConditionFalse,
+						Reason:  "Pending",
+						Message: "Exceeded the admission checks timeout 5m0s",
+					}).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadRequeued,
+						Status:  metav1.ConditionTrue,
+						Reason:  kueue.WorkloadEvictedByAdmissionChecksTimeout,
+						Message: "Exceeded the admission checks timeout 5m0s",
+					}).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadEvicted,
+						Status:  metav1.ConditionTrue,
+						Reason:  kueue.WorkloadEvictedByAdmissionChecksTimeout,
+						Message: "Exceeded the admission checks timeout 5m0s",
+					}).
+					AdmissionCheck(kueue.AdmissionCheckState{
+						Name:  "check",
+						State: kueue.CheckStatePending,
+					}).
+					Obj(),
+			},
+		},
 		"when workload is evicted due to cluster queue stopped, job gets suspended": {
 			job: *baseJobWrapper.Clone().
 				Suspend(false).
@@ -1466,6 +1657,314 @@ func TestReconciler(t *testing.T) {
 				},
 			},
 		},
+		"admitted workload with unschedulable pods beyond the threshold is marked as admitted but unschedulable": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithAdmittedButUnschedulableThreshold(&metav1.Duration{Duration: 5 * time.Minute}),
+			},
+			job: *baseJobWrapper.Clone().
+				UID("job").
+				Suspend(false).
+				Active(10).
+				Obj(),
+			pods: []corev1.Pod{
+				*utiltestingpod.MakePod("unschedulable", "ns").
+					Label(batchv1.ControllerUidLabel, "job").
+					StatusConditions(unschedulableCondition).
+					Obj(),
+				*utiltestingpod.MakePod("scheduled", "ns").
+					Label(batchv1.ControllerUidLabel, "job").
+					StatusConditions(corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}).
+					Obj(),
+				*utiltestingpod.MakePod("other-unschedulable", "ns").
+					Label(batchv1.ControllerUidLabel, "other-job").
+					StatusConditions(unschedulableCondition).
+					Obj(),
+			},
+			wantJob: *baseJobWrapper.Clone().
+				UID("job").
+				Suspend(false).
+				Active(10).
+				Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Hour))).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Hour))).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadAdmittedButUnschedulable,
+						Status:  metav1.ConditionTrue,
+						Reason:  jobframework.PodsUnschedulableReason,
+						Message: "Pods are unschedulable 5m0s after the workload was admitted: 1",
+					}).
+					Obj(),
+			},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Warning",
+					Reason:    jobframework.ReasonAdmittedButUnschedulable,
+					Message:   "Pods are unschedulable 5m0s after the workload was admitted: 1",
+				},
+			},
+		},
+		"admitted workload whose pods are scheduled but not ready beyond the threshold is not marked": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithAdmittedButUnschedulableThreshold(&metav1.Duration{Duration: 5 * time.Minute}),
+			},
+			job: *baseJobWrapper.Clone().
+				UID("job").
+				Suspend(false).
+				Active(10).
+				Obj(),
+			pods: []corev1.Pod{
+				*utiltestingpod.MakePod("scheduled", "ns").
+					Label(batchv1.ControllerUidLabel, "job").
+					StatusConditions(corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}).
+					Obj(),
+			},
+			wantJob: *baseJobWrapper.Clone().
+				UID("job").
+				Suspend(false).
+				Active(10).
+				Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Hour))).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Hour))).
+					Obj(),
+			},
+		},
+		"admitted workload whose pods are unschedulable within the threshold is not marked": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithAdmittedButUnschedulableThreshold(&metav1.Duration{Duration: 5 * time.Minute}),
+			},
+			job: *baseJobWrapper.Clone().
+				UID("job").
+				Suspend(false).
+				Active(10).
+				Obj(),
+			pods: []corev1.Pod{
+				*utiltestingpod.MakePod("unschedulable", "ns").
+					Label(batchv1.ControllerUidLabel, "job").
+					StatusConditions(unschedulableCondition).
+					Obj(),
+			},
+			wantJob: *baseJobWrapper.Clone().
+				UID("job").
+				Suspend(false).
+				Active(10).
+				Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Admitted(true).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Admitted(true).
+					Obj(),
+			},
+		},
+		"admitted but unschedulable workload whose pods are scheduled is unmarked": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithAdmittedButUnschedulableThreshold(&metav1.Duration{Duration: 5 * time.Minute}),
+			},
+			job: *baseJobWrapper.Clone().
+				UID("job").
+				Suspend(false).
+				Active(10).
+				Obj(),
+			pods: []corev1.Pod{
+				*utiltestingpod.MakePod("scheduled", "ns").
+					Label(batchv1.ControllerUidLabel, "job").
+					StatusConditions(corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}).
+					Obj(),
+			},
+			wantJob: *baseJobWrapper.Clone().
+				UID("job").
+				Suspend(false).
+				Active(10).
+				Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Hour))).
+					Condition(metav1.Condition{
+						Type:               kueue.WorkloadAdmittedButUnschedulable,
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-30 * time.Minute)),
+						Reason:             jobframework.PodsUnschedulableReason,
+						Message:            "Pods are unschedulable 5m0s after the workload was admitted: 1",
+					}).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Hour))).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadAdmittedButUnschedulable,
+						Status:  metav1.ConditionFalse,
+						Reason:  jobframework.PodsScheduledReason,
+						Message: "No pod is unschedulable",
+					}).
+					Obj(),
+			},
+		},
+		"admitted but unschedulable workload whose pods become ready is unmarked": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithAdmittedButUnschedulableThreshold(&metav1.Duration{Duration: 5 * time.Minute}),
+			},
+			job: *baseJobWrapper.Clone().
+				Suspend(false).
+				Active(10).
+				Ready(10).
+				Obj(),
+			wantJob: *baseJobWrapper.Clone().
+				Suspend(false).
+				Active(10).
+				Ready(10).
+				Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Hour))).
+					Condition(metav1.Condition{
+						Type:               kueue.WorkloadAdmittedButUnschedulable,
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-30 * time.Minute)),
+						Reason:             jobframework.PodsUnschedulableReason,
+						Message:            "Pods are unschedulable 5m0s after the workload was admitted: 1",
+					}).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Hour))).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadAdmittedButUnschedulable,
+						Status:  metav1.ConditionFalse,
+						Reason:  jobframework.PodsReadyReason,
+						Message: "The pods are ready",
+					}).
+					Obj(),
+			},
+		},
+		"admitted but unschedulable condition of a previous admission is cleared": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithAdmittedButUnschedulableThreshold(&metav1.Duration{Duration: 5 * time.Minute}),
+			},
+			job: *baseJobWrapper.Clone().
+				Suspend(false).
+				Active(10).
+				Obj(),
+			wantJob: *baseJobWrapper.Clone().
+				Suspend(false).
+				Active(10).
+				Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Minute))).
+					Condition(metav1.Condition{
+						Type:               kueue.WorkloadAdmittedButUnschedulable,
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+						Reason:             jobframework.PodsUnschedulableReason,
+						Message:            "Pods are unschedulable 5m0s after the workload was admitted: 1",
+					}).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Condition(admittedCondition(time.Now().Add(-time.Minute))).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadAdmittedButUnschedulable,
+						Status:  metav1.ConditionFalse,
+						Reason:  jobframework.ReadmittedReason,
+						Message: "The workload was admitted again",
+					}).
+					Obj(),
+			},
+		},
+		"admitted workload is mirrored in the job condition": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithManageJobsWithoutQueueName(true),
+			},
+			enableJobAdmittedCondition: true,
+			job:                        *baseJobWrapper.DeepCopy(),
+			wantJob: *baseJobWrapper.Clone().
+				Suspend(false).
+				Condition(batchv1.JobCondition{
+					Type:    JobAdmitted,
+					Status:  corev1.ConditionTrue,
+					Reason:  "ByTest",
+					Message: "Admitted by ClusterQueue cq",
+				}).
+				Obj(),
+			workloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Admitted(true).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*baseWorkloadWrapper.Clone().
+					Admitted(true).
+					Obj(),
+			},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Normal",
+					Reason:    "Started",
+					Message:   "Admitted by clusterQueue cq",
+				},
+			},
+		},
+		"evicted workload is mirrored in the job condition": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithManageJobsWithoutQueueName(true),
+			},
+			enableJobAdmittedCondition: true,
+			job: *baseJobWrapper.Clone().
+				Condition(batchv1.JobCondition{
+					Type:    JobAdmitted,
+					Status:  corev1.ConditionTrue,
+					Reason:  "ByTest",
+					Message: "Admitted by ClusterQueue cq",
+				}).
+				Obj(),
+			wantJob: *baseJobWrapper.Clone().
+				Condition(batchv1.JobCondition{
+					Type:    JobAdmitted,
+					Status:  corev1.ConditionFalse,
+					Reason:  "NoReservation",
+					Message: "The workload has no reservation",
+				}).
+				Obj(),
+			workloads: []kueue.Workload{
+				*evictedWorkloadWrapper.Clone().Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*evictedWorkloadWrapper.Clone().Obj(),
+			},
+		},
+		"workload conditions are not mirrored in the job without JobAdmittedCondition": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithManageJobsWithoutQueueName(true),
+			},
+			job:     *baseJobWrapper.DeepCopy(),
+			wantJob: *baseJobWrapper.DeepCopy(),
+			workloads: []kueue.Workload{
+				*evictedWorkloadWrapper.Clone().Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*evictedWorkloadWrapper.Clone().Obj(),
+			},
+		},
 		"non-matching admitted workload is deleted": {
 			reconcilerOptions: []jobframework.Option{
 				jobframework.WithManageJobsWithoutQueueName(true),
@@ -1518,6 +2017,37 @@ func TestReconciler(t *testing.T) {
 				},
 			},
 		},
+		"non-admitted workload is updated when the requests of the suspended job change": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithManageJobsWithoutQueueName(true),
+			},
+			job:     *baseJobWrapper.Clone().Request(corev1.ResourceCPU, "2").Obj(),
+			wantJob: *baseJobWrapper.Clone().Request(corev1.ResourceCPU, "2").Obj(),
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "ns").
+					Finalizers(kueue.ResourceInUseFinalizerName).
+					PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 10).Request(corev1.ResourceCPU, "1").Obj()).
+					Queue("foo").
+					Priority(0).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "ns").
+					Finalizers(kueue.ResourceInUseFinalizerName).
+					PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 10).Request(corev1.ResourceCPU, "2").Obj()).
+					Queue("foo").
+					Priority(0).
+					Obj(),
+			},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Normal",
+					Reason:    "UpdatedWorkload",
+					Message:   "Updated not matching Workload for suspended job: ns/a",
+				},
+			},
+		},
 		"suspended job with partial admission and admitted workload is unsuspended": {
 			reconcilerOptions: []jobframework.Option{
 				jobframework.WithManageJobsWithoutQueueName(true),
@@ -1600,6 +2130,46 @@ func TestReconciler(t *testing.T) {
 				},
 			},
 		},
+		"unsuspended job with partial admission is scaled down when its admission count was reduced": {
+			reconcilerOptions: []jobframework.Option{
+				jobframework.WithManageJobsWithoutQueueName(true),
+			},
+			enableScaleDown: true,
+			job: *baseJobWrapper.Clone().
+				SetAnnotation(JobMinParallelismAnnotation, "5").
+				Suspend(false).
+				Parallelism(8).
+				Obj(),
+			wantJob: *baseJobWrapper.Clone().
+				SetAnnotation(JobMinParallelismAnnotation, "5").
+				Suspend(false).
+				Parallelism(5).
+				Obj(),
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "ns").
+					Finalizers(kueue.ResourceInUseFinalizerName).
+					PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 10).SetMinimumCount(5).Request(corev1.ResourceCPU, "1").Obj()).
+					ReserveQuota(utiltesting.MakeAdmission("cq").AssignmentPodCount(5).Obj()).
+					Admitted(true).
+					Obj(),
+			},
+			wantWorkloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "ns").
+					Finalizers(kueue.ResourceInUseFinalizerName).
+					PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 10).SetMinimumCount(5).Request(corev1.ResourceCPU, "1").Obj()).
+					ReserveQuota(utiltesting.MakeAdmission("cq").AssignmentPodCount(5).Obj()).
+					Admitted(true).
+					Obj(),
+			},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Normal",
+					Reason:    "ScaledDown",
+					Message:   "Scaled down to main=5 to release the quota reclaimed in the cohort",
+				},
+			},
+		},
 		"the workload is created when queue name is set": {
 			job: *baseJobWrapper.
 				Clone().
@@ -2104,6 +2674,61 @@ func TestReconciler(t *testing.T) {
 				},
 			},
 		},
+		"when the workload exceeded its active deadline, the job is suspended and failed": {
+			ignoreJobConditionTimes: true,
+			job:                     *baseJobWrapper.Clone().Suspend(false).Obj(),
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "ns").
+					Finalizers(kueue.ResourceInUseFinalizerName).
+					PodSets(*utiltesting.MakePodSet("main", 10).Request(corev1.ResourceCPU, "1").Obj()).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadFinished,
+						Status:  metav1.ConditionTrue,
+						Reason:  kueue.WorkloadFinishedReasonDeadlineExceeded,
+						Message: "The workload remained pending past its active deadline of 1h0m0s",
+					}).
+					Obj(),
+			},
+			wantJob: *baseJobWrapper.Clone().
+				Condition(batchv1.JobCondition{
+					Type:    batchv1.JobFailed,
+					Status:  corev1.ConditionTrue,
+					Reason:  kueue.WorkloadFinishedReasonDeadlineExceeded,
+					Message: "The workload remained pending past its active deadline of 1h0m0s",
+				}).
+				Obj(),
+			wantWorkloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "ns").
+					PodSets(*utiltesting.MakePodSet("main", 10).Request(corev1.ResourceCPU, "1").Obj()).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadFinished,
+						Status:  metav1.ConditionTrue,
+						Reason:  kueue.WorkloadFinishedReasonDeadlineExceeded,
+						Message: "The workload remained pending past its active deadline of 1h0m0s",
+					}).
+					Obj(),
+			},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Normal",
+					Reason:    "Stopped",
+					Message:   "The workload remained pending past its active deadline of 1h0m0s",
+				},
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Warning",
+					Reason:    "DeadlineExceeded",
+					Message:   "The workload remained pending past its active deadline of 1h0m0s",
+				},
+				{
+					Key:       types.NamespacedName{Name: "job", Namespace: "ns"},
+					EventType: "Normal",
+					Reason:    "FinishedWorkload",
+					Message:   "Workload 'ns/a' is declared finished",
+				},
+			},
+		},
 		"the workload is created when queue name is set, with workloadPriorityClass": {
 			job: *baseJobWrapper.
 				Clone().
@@ -2712,6 +3337,8 @@ func TestReconciler(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
+			defer features.SetFeatureGateDuringTest(t, features.JobAdmittedCondition, tc.enableJobAdmittedCondition)()
+			defer features.SetFeatureGateDuringTest(t, features.PartialAdmissionScaleDown, tc.enableScaleDown)()
 			ctx, _ := utiltesting.ContextWithLog(t)
 			clientBuilder := utiltesting.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: utiltesting.TreatSSAAsStrategicMerge})
 			if err := SetupIndexes(ctx, utiltesting.AsIndexer(clientBuilder)); err != nil {
@@ -2724,6 +3351,9 @@ func TestReconciler(t *testing.T) {
 			if len(tc.otherJobs) > 0 {
 				kcBuilder = kcBuilder.WithLists(&batchv1.JobList{Items: tc.otherJobs})
 			}
+			if len(tc.pods) > 0 {
+				kcBuilder = kcBuilder.WithLists(&corev1.PodList{Items: tc.pods})
+			}
 
 			for i := range tc.workloads {
 				kcBuilder = kcBuilder.WithStatusSubresource(&tc.workloads[i])
@@ -2760,7 +3390,11 @@ func TestReconciler(t *testing.T) {
 			if err := kClient.Get(ctx, jobKey, &gotJob); err != nil {
 				t.Fatalf("Could not get Job after reconcile: %v", err)
 			}
-			if diff := cmp.Diff(tc.wantJob, gotJob, jobCmpOpts...); diff != "" {
+			cmpOpts := jobCmpOpts
+			if tc.enableJobAdmittedCondition || tc.ignoreJobConditionTimes {
+				cmpOpts = append(slices.Clone(jobCmpOpts), cmpopts.IgnoreFields(batchv1.JobCondition{}, "LastProbeTime", "LastTransitionTime"))
+			}
+			if diff := cmp.Diff(tc.wantJob, gotJob, cmpOpts...); diff != "" {
 				t.Errorf("Job after reconcile (-want,+got):\n%s", diff)
 			}
 			var gotWorkloads kueue.WorkloadList
@@ -23,11 +23,13 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -46,20 +48,26 @@ var (
 )
 
 type JobWebhook struct {
-	manageJobsWithoutQueueName bool
-	kubeServerVersion          *kubeversion.ServerVersionFetcher
-	queues                     *queue.Manager
-	cache                      *cache.Cache
+	client                             client.Client
+	manageJobsWithoutQueueName         bool
+	queueNameRequiredNamespaceSelector *metav1.LabelSelector
+	defaultQueueNamespaceLabel         string
+	kubeServerVersion                  *kubeversion.ServerVersionFetcher
+	queues                             *queue.Manager
+	cache                              *cache.Cache
 }
 
 // SetupWebhook configures the webhook for batchJob.
 func SetupWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
 	options := jobframework.ProcessOptions(opts...)
 	wh := &JobWebhook{
-		manageJobsWithoutQueueName: options.ManageJobsWithoutQueueName,
-		kubeServerVersion:          options.KubeServerVersion,
-		queues:                     options.Queues,
-		cache:                      options.Cache,
+		client:                             mgr.GetClient(),
+		manageJobsWithoutQueueName:         options.ManageJobsWithoutQueueName,
+		queueNameRequiredNamespaceSelector: options.QueueNameRequiredNamespaceSelector,
+		defaultQueueNamespaceLabel:         options.DefaultQueueNamespaceLabel,
+		kubeServerVersion:                  options.KubeServerVersion,
+		queues:                             options.Queues,
+		cache:                              options.Cache,
 	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&batchv1.Job{}).
@@ -78,6 +86,9 @@ func (w *JobWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	log := ctrl.LoggerFrom(ctx).WithName("job-webhook")
 	log.V(5).Info("Applying defaults", "job", klog.KObj(job))
 
+	if err := jobframework.ApplyDefaultQueueName(ctx, w.client, job, w.defaultQueueNamespaceLabel); err != nil {
+		return err
+	}
 	jobframework.ApplyDefaultForSuspend(job, w.manageJobsWithoutQueueName)
 
 	if canDefaultManagedBy(job.Spec.ManagedBy) {
@@ -116,7 +127,13 @@ func (w *JobWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (ad
 	job := fromObject(obj)
 	log := ctrl.LoggerFrom(ctx).WithName("job-webhook")
 	log.V(5).Info("Validating create", "job", klog.KObj(job))
-	return nil, w.validateCreate(job).ToAggregate()
+	allErrs := w.validateCreate(job)
+	queueNameErrs, err := jobframework.ValidateQueueNameRequired(ctx, w.client, w.queueNameRequiredNamespaceSelector, job)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, queueNameErrs...)
+	return nil, allErrs.ToAggregate()
 }
 
 func (w *JobWebhook) validateCreate(job *Job) field.ErrorList {
@@ -169,7 +186,15 @@ func (w *JobWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.
 }
 
 func (w *JobWebhook) validateUpdate(oldJob, newJob *Job) field.ErrorList {
-	allErrs := w.validateCreate(newJob)
+	validatedJob := newJob
+	if isScaleDown(oldJob, newJob) {
+		// The job can be scaled down to its minimum parallelism, validate it
+		// with the counts it had before the scale down.
+		validatedJob = (*Job)((*batchv1.Job)(newJob).DeepCopy())
+		validatedJob.Spec.Parallelism = oldJob.Spec.Parallelism
+		validatedJob.Spec.Completions = oldJob.Spec.Completions
+	}
+	allErrs := w.validateCreate(validatedJob)
 	allErrs = append(allErrs, jobframework.ValidateJobOnUpdate(oldJob, newJob)...)
 	allErrs = append(allErrs, validatePartialAdmissionUpdate(oldJob, newJob)...)
 	return allErrs
@@ -178,7 +203,7 @@ func (w *JobWebhook) validateUpdate(oldJob, newJob *Job) field.ErrorList {
 func validatePartialAdmissionUpdate(oldJob, newJob *Job) field.ErrorList {
 	var allErrs field.ErrorList
 	if _, found := oldJob.Annotations[JobMinParallelismAnnotation]; found {
-		if !oldJob.IsSuspended() && ptr.Deref(oldJob.Spec.Parallelism, 1) != ptr.Deref(newJob.Spec.Parallelism, 1) {
+		if !oldJob.IsSuspended() && ptr.Deref(oldJob.Spec.Parallelism, 1) != ptr.Deref(newJob.Spec.Parallelism, 1) && !isScaleDown(oldJob, newJob) {
 			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "parallelism"), "cannot change when partial admission is enabled and the job is not suspended"))
 		}
 	}
@@ -188,6 +213,18 @@ func validatePartialAdmissionUpdate(oldJob, newJob *Job) field.ErrorList {
 	return allErrs
 }
 
+// isScaleDown returns whether the update reduces the parallelism of the job
+// to a value not lower than its minimum parallelism, as done by Kueue when
+// part of the quota of the job is reclaimed.
+func isScaleDown(oldJob, newJob *Job) bool {
+	if !features.Enabled(features.PartialAdmissionScaleDown) {
+		return false
+	}
+	newParallelism := ptr.Deref(newJob.Spec.Parallelism, 1)
+	minCount := newJob.minPodsCount()
+	return minCount != nil && newParallelism < ptr.Deref(oldJob.Spec.Parallelism, 1) && newParallelism >= *minCount
+}
+
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
 func (w *JobWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	return nil, nil
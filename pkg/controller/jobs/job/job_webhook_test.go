@@ -81,6 +81,16 @@ func TestValidateCreate(t *testing.T) {
 			job:     testingutil.MakeJob("job", "default").QueueNameAnnotation("queue name").Obj(),
 			wantErr: field.ErrorList{field.Invalid(queueNameAnnotationsPath, "queue name", invalidRFC1123Message)},
 		},
+		{
+			name: "invalid active deadline seconds annotation",
+			job: testingutil.MakeJob("job", "default").
+				Queue("queue").
+				SetAnnotation(constants.ActiveDeadlineSecondsAnnotation, "0").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(annotationsPath.Key(constants.ActiveDeadlineSecondsAnnotation), "0", "must be a positive integer"),
+			},
+		},
 		{
 			name: "invalid partial admission annotation (format)",
 			job: testingutil.MakeJob("job", "default").
@@ -243,12 +253,159 @@ func TestValidateCreate(t *testing.T) {
 	}
 }
 
+func TestValidateCreateQueueNameEnforcement(t *testing.T) {
+	enforcedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "enforced",
+			Labels: map[string]string{"queue-name-required": "true"},
+		},
+	}
+	otherNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+	}
+	testcases := map[string]struct {
+		job               *batchv1.Job
+		namespaceSelector *metav1.LabelSelector
+		wantErr           error
+	}{
+		"unqueued job in an enforced namespace": {
+			job: testingutil.MakeJob("job", "enforced").Obj(),
+			namespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"queue-name-required": "true"},
+			},
+			wantErr: field.ErrorList{
+				field.Required(queueNameLabelPath, `a queue name is required in namespace "enforced"`),
+			}.ToAggregate(),
+		},
+		"queued job in an enforced namespace": {
+			job: testingutil.MakeJob("job", "enforced").Queue("queue").Obj(),
+			namespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"queue-name-required": "true"},
+			},
+		},
+		"job queued with the deprecated annotation in an enforced namespace": {
+			job: testingutil.MakeJob("job", "enforced").QueueNameAnnotation("queue").Obj(),
+			namespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"queue-name-required": "true"},
+			},
+		},
+		"unqueued job owned by a job managed by kueue in an enforced namespace": {
+			job: testingutil.MakeJob("job", "enforced").
+				OwnerReference("parent", batchv1.SchemeGroupVersion.WithKind("Job")).
+				Obj(),
+			namespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"queue-name-required": "true"},
+			},
+		},
+		"unqueued job in a namespace that isn't enforced": {
+			job: testingutil.MakeJob("job", "other").Obj(),
+			namespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"queue-name-required": "true"},
+			},
+		},
+		"unqueued job without enforcement": {
+			job: testingutil.MakeJob("job", "enforced").Obj(),
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := utiltesting.ContextWithLog(t)
+			jw := &JobWebhook{
+				client:                             utiltesting.NewClientBuilder().WithObjects(enforcedNamespace, otherNamespace).Build(),
+				queueNameRequiredNamespaceSelector: tc.namespaceSelector,
+			}
+			fakeDiscoveryClient, _ := fakeclient.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+			jw.kubeServerVersion = kubeversion.NewServerVersionFetcher(fakeDiscoveryClient)
+
+			_, gotErr := jw.ValidateCreate(ctx, tc.job)
+			if diff := cmp.Diff(tc.wantErr, gotErr); diff != "" {
+				t.Errorf("ValidateCreate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultQueueName(t *testing.T) {
+	labeledNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "labeled",
+			Labels: map[string]string{constants.DefaultQueueNamespaceLabel: "team-queue"},
+		},
+	}
+	otherNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+	}
+	testcases := map[string]struct {
+		job      *batchv1.Job
+		labelKey string
+		want     *batchv1.Job
+	}{
+		"unqueued job in a labeled namespace": {
+			job:      testingutil.MakeJob("job", "labeled").Suspend(false).Obj(),
+			labelKey: constants.DefaultQueueNamespaceLabel,
+			want:     testingutil.MakeJob("job", "labeled").Queue("team-queue").Obj(),
+		},
+		"queued job in a labeled namespace": {
+			job:      testingutil.MakeJob("job", "labeled").Queue("queue").Suspend(false).Obj(),
+			labelKey: constants.DefaultQueueNamespaceLabel,
+			want:     testingutil.MakeJob("job", "labeled").Queue("queue").Obj(),
+		},
+		"job queued with the deprecated annotation in a labeled namespace": {
+			job:      testingutil.MakeJob("job", "labeled").QueueNameAnnotation("queue").Suspend(false).Obj(),
+			labelKey: constants.DefaultQueueNamespaceLabel,
+			want:     testingutil.MakeJob("job", "labeled").QueueNameAnnotation("queue").Obj(),
+		},
+		"unqueued job owned by a job managed by kueue in a labeled namespace": {
+			job: testingutil.MakeJob("job", "labeled").
+				OwnerReference("parent", batchv1.SchemeGroupVersion.WithKind("Job")).
+				Suspend(false).
+				Obj(),
+			labelKey: constants.DefaultQueueNamespaceLabel,
+			want: testingutil.MakeJob("job", "labeled").
+				OwnerReference("parent", batchv1.SchemeGroupVersion.WithKind("Job")).
+				Suspend(false).
+				Obj(),
+		},
+		"unqueued job in a namespace that isn't labeled": {
+			job:      testingutil.MakeJob("job", "other").Suspend(false).Obj(),
+			labelKey: constants.DefaultQueueNamespaceLabel,
+			want:     testingutil.MakeJob("job", "other").Suspend(false).Obj(),
+		},
+		"unqueued job without defaulting": {
+			job:  testingutil.MakeJob("job", "labeled").Suspend(false).Obj(),
+			want: testingutil.MakeJob("job", "labeled").Suspend(false).Obj(),
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := utiltesting.ContextWithLog(t)
+			cl := utiltesting.NewClientBuilder().WithObjects(labeledNamespace, otherNamespace).Build()
+			cqCache := cache.New(cl)
+			w := &JobWebhook{
+				client:                     cl,
+				defaultQueueNamespaceLabel: tc.labelKey,
+				queues:                     queue.NewManager(cl, cqCache),
+				cache:                      cqCache,
+			}
+			if err := w.Default(ctx, tc.job); err != nil {
+				t.Fatalf("Unexpected Default() error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, tc.job); diff != "" {
+				t.Errorf("Default() mismatch (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestValidateUpdate(t *testing.T) {
 	testcases := []struct {
-		name    string
-		oldJob  *batchv1.Job
-		newJob  *batchv1.Job
-		wantErr field.ErrorList
+		name            string
+		oldJob          *batchv1.Job
+		newJob          *batchv1.Job
+		enableScaleDown bool
+		wantErr         field.ErrorList
 	}{
 		{
 			name:    "normal update",
@@ -322,6 +479,41 @@ func TestValidateUpdate(t *testing.T) {
 				Obj(),
 			wantErr: nil,
 		},
+		{
+			name: "parallelism can be scaled down to the minimum while unsuspended with partial admission scale down enabled",
+			oldJob: testingutil.MakeJob("job", "default").
+				Suspend(false).
+				Parallelism(4).
+				Completions(6).
+				SetAnnotation(JobMinParallelismAnnotation, "3").
+				Obj(),
+			newJob: testingutil.MakeJob("job", "default").
+				Suspend(false).
+				Parallelism(3).
+				Completions(6).
+				SetAnnotation(JobMinParallelismAnnotation, "3").
+				Obj(),
+			enableScaleDown: true,
+			wantErr:         nil,
+		},
+		{
+			name: "parallelism cannot be scaled down while unsuspended with partial admission scale down disabled",
+			oldJob: testingutil.MakeJob("job", "default").
+				Suspend(false).
+				Parallelism(4).
+				Completions(6).
+				SetAnnotation(JobMinParallelismAnnotation, "2").
+				Obj(),
+			newJob: testingutil.MakeJob("job", "default").
+				Suspend(false).
+				Parallelism(3).
+				Completions(6).
+				SetAnnotation(JobMinParallelismAnnotation, "2").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "parallelism"), "cannot change when partial admission is enabled and the job is not suspended"),
+			},
+		},
 		{
 			name: "immutable sync completion annotation while unsuspended",
 			oldJob: testingutil.MakeJob("job", "default").
@@ -380,6 +572,7 @@ func TestValidateUpdate(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
+			defer features.SetFeatureGateDuringTest(t, features.PartialAdmissionScaleDown, tc.enableScaleDown)()
 			gotErr := new(JobWebhook).validateUpdate((*Job)(tc.oldJob), (*Job)(tc.newJob))
 			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.IgnoreFields(field.Error{})); diff != "" {
 				t.Errorf("validateUpdate() mismatch (-want +got):\n%s", diff)
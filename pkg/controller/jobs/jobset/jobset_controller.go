@@ -74,6 +74,7 @@ type JobSet jobsetapi.JobSet
 
 var _ jobframework.GenericJob = (*JobSet)(nil)
 var _ jobframework.JobWithReclaimablePods = (*JobSet)(nil)
+var _ jobframework.JobWithPodLabels = (*JobSet)(nil)
 
 func fromObject(obj runtime.Object) *JobSet {
 	return (*JobSet)(obj.(*jobsetapi.JobSet))
@@ -130,6 +131,10 @@ func (j *JobSet) RunWithPodSetsInfo(podSetsInfo []podset.PodSetInfo) error {
 		if err := podset.Merge(&template.ObjectMeta, &template.Spec, info); err != nil {
 			return nil
 		}
+		// Optional replicated jobs that were not admitted don't run.
+		if info.Count == 0 {
+			j.Spec.ReplicatedJobs[index].Replicas = 0
+		}
 	}
 	return nil
 }
@@ -143,6 +148,11 @@ func (j *JobSet) RestorePodSetsInfo(podSetsInfo []podset.PodSetInfo) bool {
 		replica := &j.Spec.ReplicatedJobs[index].Template.Spec.Template
 		info := podSetsInfo[index]
 		changed = podset.RestorePodSpec(&replica.ObjectMeta, &replica.Spec, info) || changed
+		rj := &j.Spec.ReplicatedJobs[index]
+		if rj.Replicas == 0 && info.Count > 0 {
+			rj.Replicas = info.Count / podsCountPerReplica(rj)
+			changed = true
+		}
 	}
 	return changed
 }
@@ -169,6 +179,10 @@ func (j *JobSet) PodsReady() bool {
 	return replicas == readyReplicas
 }
 
+func (j *JobSet) PodLabels() map[string]string {
+	return map[string]string{jobsetapi.JobSetNameKey: j.Name}
+}
+
 func (j *JobSet) ReclaimablePods() ([]kueue.ReclaimablePod, error) {
 	if len(j.Status.ReplicatedJobsStatus) == 0 {
 		return nil, nil
@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	jobset "sigs.k8s.io/jobset/api/jobset/v1alpha2"
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/kueue/pkg/constants"
 	controllerconsts "sigs.k8s.io/kueue/pkg/controller/constants"
 	"sigs.k8s.io/kueue/pkg/controller/jobframework"
+	"sigs.k8s.io/kueue/pkg/podset"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
 	testingjobset "sigs.k8s.io/kueue/pkg/util/testingjobs/jobset"
 )
@@ -211,6 +213,52 @@ var (
 	}
 )
 
+func TestRunWithPodSetsInfoDroppedPodSet(t *testing.T) {
+	makeJobSet := func(workersReplicas int32) *jobset.JobSet {
+		return testingjobset.MakeJobSet("jobset", "ns").ReplicatedJobs(
+			testingjobset.ReplicatedJobRequirements{
+				Name:        "driver",
+				Replicas:    1,
+				Parallelism: 1,
+				Completions: 1,
+			},
+			testingjobset.ReplicatedJobRequirements{
+				Name:        "workers",
+				Replicas:    workersReplicas,
+				Parallelism: 2,
+				Completions: 2,
+			},
+		).Obj()
+	}
+	job := (*JobSet)(makeJobSet(3))
+	job.Spec.Suspend = ptr.To(true)
+
+	if err := job.RunWithPodSetsInfo([]podset.PodSetInfo{
+		{Name: "driver", Count: 1},
+		{Name: "workers", Count: 0},
+	}); err != nil {
+		t.Fatalf("Failed to run the JobSet: %v", err)
+	}
+	wantRunning := makeJobSet(0)
+	wantRunning.Spec.Suspend = ptr.To(false)
+	if diff := cmp.Diff(wantRunning.Spec, job.Spec); diff != "" {
+		t.Errorf("Unexpected running JobSet spec (-want,+got):\n%s", diff)
+	}
+
+	job.Suspend()
+	if changed := job.RestorePodSetsInfo([]podset.PodSetInfo{
+		{Name: "driver", Count: 1},
+		{Name: "workers", Count: 6},
+	}); !changed {
+		t.Error("Expected the JobSet to be changed when restoring")
+	}
+	wantRestored := makeJobSet(3)
+	wantRestored.Spec.Suspend = ptr.To(true)
+	if diff := cmp.Diff(wantRestored.Spec, job.Spec); diff != "" {
+		t.Errorf("Unexpected restored JobSet spec (-want,+got):\n%s", diff)
+	}
+}
+
 func TestReconciler(t *testing.T) {
 	baseWPCWrapper := utiltesting.MakeWorkloadPriorityClass("test-wpc").
 		PriorityValue(100)
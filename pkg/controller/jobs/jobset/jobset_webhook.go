@@ -19,10 +19,12 @@ package jobset
 import (
 	"context"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	jobsetapi "sigs.k8s.io/jobset/api/jobset/v1alpha2"
@@ -36,18 +38,22 @@ import (
 )
 
 type JobSetWebhook struct {
-	manageJobsWithoutQueueName bool
-	queues                     *queue.Manager
-	cache                      *cache.Cache
+	client                             client.Client
+	manageJobsWithoutQueueName         bool
+	queueNameRequiredNamespaceSelector *metav1.LabelSelector
+	queues                             *queue.Manager
+	cache                              *cache.Cache
 }
 
 // SetupJobSetWebhook configures the webhook for kubeflow JobSet.
 func SetupJobSetWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
 	options := jobframework.ProcessOptions(opts...)
 	wh := &JobSetWebhook{
-		manageJobsWithoutQueueName: options.ManageJobsWithoutQueueName,
-		queues:                     options.Queues,
-		cache:                      options.Cache,
+		client:                             mgr.GetClient(),
+		manageJobsWithoutQueueName:         options.ManageJobsWithoutQueueName,
+		queueNameRequiredNamespaceSelector: options.QueueNameRequiredNamespaceSelector,
+		queues:                             options.Queues,
+		cache:                              options.Cache,
 	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&jobsetapi.JobSet{}).
@@ -104,7 +110,13 @@ func (w *JobSetWebhook) ValidateCreate(ctx context.Context, obj runtime.Object)
 	jobSet := fromObject(obj)
 	log := ctrl.LoggerFrom(ctx).WithName("jobset-webhook")
 	log.Info("Validating create", "jobset", klog.KObj(jobSet))
-	return nil, jobframework.ValidateJobOnCreate(jobSet).ToAggregate()
+	allErrs := jobframework.ValidateJobOnCreate(jobSet)
+	queueNameErrs, err := jobframework.ValidateQueueNameRequired(ctx, w.client, w.queueNameRequiredNamespaceSelector, jobSet)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, queueNameErrs...)
+	return nil, allErrs.ToAggregate()
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
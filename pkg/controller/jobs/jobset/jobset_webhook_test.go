@@ -83,6 +83,53 @@ func TestValidateCreate(t *testing.T) {
 	}
 }
 
+func TestValidateCreateQueueNameEnforcement(t *testing.T) {
+	enforcedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "enforced",
+			Labels: map[string]string{"queue-name-required": "true"},
+		},
+	}
+	testcases := map[string]struct {
+		job               *jobset.JobSet
+		namespaceSelector *metav1.LabelSelector
+		wantErr           error
+	}{
+		"unqueued jobset in an enforced namespace": {
+			job: testingutil.MakeJobSet("job", "enforced").Obj(),
+			namespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"queue-name-required": "true"},
+			},
+			wantErr: field.ErrorList{
+				field.Required(queueNameLabelPath, `a queue name is required in namespace "enforced"`),
+			}.ToAggregate(),
+		},
+		"queued jobset in an enforced namespace": {
+			job: testingutil.MakeJobSet("job", "enforced").Queue("queue").Obj(),
+			namespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"queue-name-required": "true"},
+			},
+		},
+		"unqueued jobset without enforcement": {
+			job: testingutil.MakeJobSet("job", "enforced").Obj(),
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := utiltesting.ContextWithLog(t)
+			jsw := &JobSetWebhook{
+				client:                             utiltesting.NewClientBuilder().WithObjects(enforcedNamespace).Build(),
+				queueNameRequiredNamespaceSelector: tc.namespaceSelector,
+			}
+			_, gotErr := jsw.ValidateCreate(ctx, tc.job)
+			if diff := cmp.Diff(tc.wantErr, gotErr); diff != "" {
+				t.Errorf("ValidateCreate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDefault(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -20,10 +20,12 @@ import (
 	"context"
 
 	kftraining "github.com/kubeflow/training-operator/pkg/apis/kubeflow.org/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -31,14 +33,18 @@ import (
 )
 
 type MXJobWebhook struct {
-	manageJobsWithoutQueueName bool
+	client                             client.Client
+	manageJobsWithoutQueueName         bool
+	queueNameRequiredNamespaceSelector *metav1.LabelSelector
 }
 
 // SetupMXJobWebhook configures the webhook for kubeflow MXJob.
 func SetupMXJobWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
 	options := jobframework.ProcessOptions(opts...)
 	wh := &MXJobWebhook{
-		manageJobsWithoutQueueName: options.ManageJobsWithoutQueueName,
+		client:                             mgr.GetClient(),
+		manageJobsWithoutQueueName:         options.ManageJobsWithoutQueueName,
+		queueNameRequiredNamespaceSelector: options.QueueNameRequiredNamespaceSelector,
 	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&kftraining.MXJob{}).
@@ -69,7 +75,13 @@ func (w *MXJobWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (
 	job := fromObject(obj)
 	log := ctrl.LoggerFrom(ctx).WithName("mxjob-webhook")
 	log.V(5).Info("Validating create", "mxjob", klog.KObj(job.Object()))
-	return nil, validateCreate(job).ToAggregate()
+	allErrs := validateCreate(job)
+	queueNameErrs, err := jobframework.ValidateQueueNameRequired(ctx, w.client, w.queueNameRequiredNamespaceSelector, job)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, queueNameErrs...)
+	return nil, allErrs.ToAggregate()
 }
 
 func validateCreate(job jobframework.GenericJob) field.ErrorList {
@@ -36,6 +36,7 @@ type KubeflowJob struct {
 
 var _ jobframework.GenericJob = (*KubeflowJob)(nil)
 var _ jobframework.JobWithPriorityClass = (*KubeflowJob)(nil)
+var _ jobframework.JobWithPodLabels = (*KubeflowJob)(nil)
 
 func (j *KubeflowJob) Object() client.Object {
 	return j.KFJobControl.Object()
@@ -65,6 +66,10 @@ func (j *KubeflowJob) RunWithPodSetsInfo(podSetsInfo []podset.PodSetInfo) error
 		if err := podset.Merge(&replica.ObjectMeta, &replica.Spec, info); err != nil {
 			return err
 		}
+		// Optional replicas that were not admitted don't run.
+		if podset.IsDropped(&replica.ObjectMeta, info) {
+			j.KFJobControl.ReplicaSpecs()[replicaType].Replicas = ptr.To[int32](0)
+		}
 	}
 	return nil
 }
@@ -76,6 +81,10 @@ func (j *KubeflowJob) RestorePodSetsInfo(podSetsInfo []podset.PodSetInfo) bool {
 		replicaType := orderedReplicaTypes[index]
 		replica := &j.KFJobControl.ReplicaSpecs()[replicaType].Template
 		changed = podset.RestorePodSpec(&replica.ObjectMeta, &replica.Spec, info) || changed
+		if replicaSpecs := j.KFJobControl.ReplicaSpecs(); podsCount(replicaSpecs, replicaType) == 0 && info.Count > 0 {
+			replicaSpecs[replicaType].Replicas = ptr.To(info.Count)
+			changed = true
+		}
 	}
 	return changed
 }
@@ -125,6 +134,10 @@ func (j *KubeflowJob) PodsReady() bool {
 	return false
 }
 
+func (j *KubeflowJob) PodLabels() map[string]string {
+	return map[string]string{kftraining.JobNameLabel: j.Object().GetName()}
+}
+
 func (j *KubeflowJob) GVK() schema.GroupVersionKind {
 	return j.KFJobControl.GVK()
 }
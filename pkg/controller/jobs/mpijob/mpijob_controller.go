@@ -73,6 +73,7 @@ type MPIJob kubeflow.MPIJob
 
 var _ jobframework.GenericJob = (*MPIJob)(nil)
 var _ jobframework.JobWithPriorityClass = (*MPIJob)(nil)
+var _ jobframework.JobWithPodLabels = (*MPIJob)(nil)
 
 func (j *MPIJob) Object() client.Object {
 	return (*kubeflow.MPIJob)(j)
@@ -133,6 +134,10 @@ func (j *MPIJob) RunWithPodSetsInfo(podSetsInfo []podset.PodSetInfo) error {
 		if err := podset.Merge(&replica.ObjectMeta, &replica.Spec, info); err != nil {
 			return err
 		}
+		// Optional replicas that were not admitted don't run.
+		if podset.IsDropped(&replica.ObjectMeta, info) {
+			j.Spec.MPIReplicaSpecs[replicaType].Replicas = ptr.To[int32](0)
+		}
 	}
 	return nil
 }
@@ -144,6 +149,10 @@ func (j *MPIJob) RestorePodSetsInfo(podSetsInfo []podset.PodSetInfo) bool {
 		replicaType := orderedReplicaTypes[index]
 		replica := &j.Spec.MPIReplicaSpecs[replicaType].Template
 		changed = podset.RestorePodSpec(&replica.ObjectMeta, &replica.Spec, info) || changed
+		if replicaSpec := j.Spec.MPIReplicaSpecs[replicaType]; podsCount(&j.Spec, replicaType) == 0 && info.Count > 0 {
+			replicaSpec.Replicas = ptr.To(info.Count)
+			changed = true
+		}
 	}
 	return changed
 }
@@ -185,6 +194,13 @@ func (j *MPIJob) PodsReady() bool {
 	return false
 }
 
+func (j *MPIJob) PodLabels() map[string]string {
+	return map[string]string{
+		kubeflow.OperatorNameLabel: kubeflow.OperatorName,
+		kubeflow.JobNameLabel:      j.Name,
+	}
+}
+
 func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
 	return jobframework.SetupWorkloadOwnerIndex(ctx, indexer, gvk)
 }
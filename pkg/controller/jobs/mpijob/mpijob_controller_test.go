@@ -25,12 +25,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/controller/jobframework"
+	"sigs.k8s.io/kueue/pkg/podset"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
 	testingmpijob "sigs.k8s.io/kueue/pkg/util/testingjobs/mpijob"
 )
@@ -194,6 +196,39 @@ var (
 	}
 )
 
+func TestRunWithPodSetsInfoDroppedPodSet(t *testing.T) {
+	mpiJob := testingmpijob.MakeMPIJob("job", "ns").
+		PodAnnotation(kubeflow.MPIReplicaTypeWorker, kueue.PodSetOptionalAnnotation, "true").
+		Obj()
+	mpiJob.Spec.MPIReplicaSpecs[kubeflow.MPIReplicaTypeWorker].Replicas = ptr.To[int32](3)
+	job := (*MPIJob)(mpiJob)
+
+	if err := job.RunWithPodSetsInfo([]podset.PodSetInfo{
+		{Name: "launcher", Count: 1},
+		{Name: "worker", Count: 0},
+	}); err != nil {
+		t.Fatalf("Failed to run the MPIJob: %v", err)
+	}
+	gotReplicas := map[kubeflow.MPIReplicaType]int32{
+		kubeflow.MPIReplicaTypeLauncher: podsCount(&job.Spec, kubeflow.MPIReplicaTypeLauncher),
+		kubeflow.MPIReplicaTypeWorker:   podsCount(&job.Spec, kubeflow.MPIReplicaTypeWorker),
+	}
+	if diff := cmp.Diff(map[kubeflow.MPIReplicaType]int32{kubeflow.MPIReplicaTypeLauncher: 1, kubeflow.MPIReplicaTypeWorker: 0}, gotReplicas); diff != "" {
+		t.Errorf("Unexpected replicas of the running MPIJob (-want,+got):\n%s", diff)
+	}
+
+	job.Suspend()
+	if changed := job.RestorePodSetsInfo([]podset.PodSetInfo{
+		{Name: "launcher", Count: 1},
+		{Name: "worker", Count: 3},
+	}); !changed {
+		t.Error("Expected the MPIJob to be changed when restoring")
+	}
+	if got := podsCount(&job.Spec, kubeflow.MPIReplicaTypeWorker); got != 3 {
+		t.Errorf("Unexpected worker replicas after restoring, want 3, got %d", got)
+	}
+}
+
 func TestReconciler(t *testing.T) {
 	baseWPCWrapper := utiltesting.MakeWorkloadPriorityClass("test-wpc").
 		PriorityValue(100)
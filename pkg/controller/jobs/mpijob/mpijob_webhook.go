@@ -20,25 +20,32 @@ import (
 	"context"
 
 	kubeflow "github.com/kubeflow/mpi-operator/pkg/apis/kubeflow/v2beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/controller/jobframework"
 )
 
 type MPIJobWebhook struct {
-	manageJobsWithoutQueueName bool
+	client                             client.Client
+	manageJobsWithoutQueueName         bool
+	queueNameRequiredNamespaceSelector *metav1.LabelSelector
 }
 
 // SetupMPIJobWebhook configures the webhook for kubeflow MPIJob.
 func SetupMPIJobWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
 	options := jobframework.ProcessOptions(opts...)
 	wh := &MPIJobWebhook{
-		manageJobsWithoutQueueName: options.ManageJobsWithoutQueueName,
+		client:                             mgr.GetClient(),
+		manageJobsWithoutQueueName:         options.ManageJobsWithoutQueueName,
+		queueNameRequiredNamespaceSelector: options.QueueNameRequiredNamespaceSelector,
 	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&kubeflow.MPIJob{}).
@@ -70,11 +77,23 @@ func (w *MPIJobWebhook) ValidateCreate(ctx context.Context, obj runtime.Object)
 	job := fromObject(obj)
 	log := ctrl.LoggerFrom(ctx).WithName("mpijob-webhook")
 	log.Info("Validating create", "job", klog.KObj(job))
-	return nil, validateCreate(job).ToAggregate()
+	allErrs := validateCreate(job)
+	queueNameErrs, err := jobframework.ValidateQueueNameRequired(ctx, w.client, w.queueNameRequiredNamespaceSelector, job)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, queueNameErrs...)
+	return nil, allErrs.ToAggregate()
 }
 
-func validateCreate(job jobframework.GenericJob) field.ErrorList {
-	return jobframework.ValidateJobOnCreate(job)
+func validateCreate(job *MPIJob) field.ErrorList {
+	allErrs := jobframework.ValidateJobOnCreate(job)
+	// The MPIJob can't run without its launcher.
+	if launcher := job.Spec.MPIReplicaSpecs[kubeflow.MPIReplicaTypeLauncher]; launcher != nil && launcher.Template.Annotations[kueue.PodSetOptionalAnnotation] == "true" {
+		launcherPath := field.NewPath("spec", "mpiReplicaSpecs").Key(string(kubeflow.MPIReplicaTypeLauncher))
+		allErrs = append(allErrs, field.Forbidden(launcherPath.Child("template", "metadata", "annotations").Key(kueue.PodSetOptionalAnnotation), "the launcher can't be optional"))
+	}
+	return allErrs
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
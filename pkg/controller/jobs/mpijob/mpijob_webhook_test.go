@@ -22,7 +22,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	kubeflow "github.com/kubeflow/mpi-operator/pkg/apis/kubeflow/v2beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	testingutil "sigs.k8s.io/kueue/pkg/util/testingjobs/mpijob"
 )
 
@@ -54,3 +56,33 @@ func TestDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCreate(t *testing.T) {
+	testcases := map[string]struct {
+		job     *kubeflow.MPIJob
+		wantErr error
+	}{
+		"optional worker": {
+			job: testingutil.MakeMPIJob("job", "default").Queue("queue").
+				PodAnnotation(kubeflow.MPIReplicaTypeWorker, kueue.PodSetOptionalAnnotation, "true").
+				Obj(),
+		},
+		"optional launcher": {
+			job: testingutil.MakeMPIJob("job", "default").Queue("queue").
+				PodAnnotation(kubeflow.MPIReplicaTypeLauncher, kueue.PodSetOptionalAnnotation, "true").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "mpiReplicaSpecs").Key("Launcher").Child("template", "metadata", "annotations").Key(kueue.PodSetOptionalAnnotation), "the launcher can't be optional"),
+			}.ToAggregate(),
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			w := &MPIJobWebhook{}
+			_, gotErr := w.ValidateCreate(context.Background(), tc.job)
+			if diff := cmp.Diff(tc.wantErr, gotErr); diff != "" {
+				t.Errorf("ValidateCreate() mismatch (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
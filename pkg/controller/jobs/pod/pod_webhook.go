@@ -61,10 +61,11 @@ var (
 )
 
 type PodWebhook struct {
-	client                     client.Client
-	manageJobsWithoutQueueName bool
-	namespaceSelector          *metav1.LabelSelector
-	podSelector                *metav1.LabelSelector
+	client                             client.Client
+	manageJobsWithoutQueueName         bool
+	queueNameRequiredNamespaceSelector *metav1.LabelSelector
+	namespaceSelector                  *metav1.LabelSelector
+	podSelector                        *metav1.LabelSelector
 }
 
 // SetupWebhook configures the webhook for pods.
@@ -75,10 +76,11 @@ func SetupWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
 		return err
 	}
 	wh := &PodWebhook{
-		client:                     mgr.GetClient(),
-		manageJobsWithoutQueueName: options.ManageJobsWithoutQueueName,
-		namespaceSelector:          podOpts.NamespaceSelector,
-		podSelector:                podOpts.PodSelector,
+		client:                             mgr.GetClient(),
+		manageJobsWithoutQueueName:         options.ManageJobsWithoutQueueName,
+		queueNameRequiredNamespaceSelector: options.QueueNameRequiredNamespaceSelector,
+		namespaceSelector:                  podOpts.NamespaceSelector,
+		podSelector:                        podOpts.PodSelector,
 	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&corev1.Pod{}).
@@ -142,31 +144,9 @@ func (w *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 		return nil
 	}
 
-	// Check for pod label selector match
-	podSelector, err := metav1.LabelSelectorAsSelector(w.podSelector)
-	if err != nil {
-		return fmt.Errorf("failed to parse pod selector: %w", err)
-	}
-	if !podSelector.Matches(labels.Set(pod.pod.GetLabels())) {
-		return nil
-	}
-
-	// Get pod namespace and check for namespace label selector match
-	ns := corev1.Namespace{}
-	err = w.client.Get(ctx, client.ObjectKey{Name: pod.pod.GetNamespace()}, &ns)
-	if err != nil {
-		return fmt.Errorf("failed to run mutating webhook on pod %s, error while getting namespace: %w",
-			pod.pod.GetName(),
-			err,
-		)
-	}
-	log.V(5).Info("Found pod namespace", "Namespace.Name", ns.GetName())
-	nsSelector, err := metav1.LabelSelectorAsSelector(w.namespaceSelector)
-	if err != nil {
-		return fmt.Errorf("failed to parse namespace selector: %w", err)
-	}
-	if !nsSelector.Matches(labels.Set(ns.GetLabels())) {
-		return nil
+	selected, err := w.selectsPod(ctx, pod)
+	if err != nil || !selected {
+		return err
 	}
 
 	if jobframework.QueueName(pod) != "" || w.manageJobsWithoutQueueName {
@@ -194,6 +174,37 @@ func (w *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	return nil
 }
 
+// selectsPod returns whether the pod matches the pod and namespace selectors
+// of the pod integration.
+func (w *PodWebhook) selectsPod(ctx context.Context, pod *Pod) (bool, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("pod-webhook").WithValues("pod", klog.KObj(&pod.pod))
+
+	// Check for pod label selector match
+	podSelector, err := metav1.LabelSelectorAsSelector(w.podSelector)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse pod selector: %w", err)
+	}
+	if !podSelector.Matches(labels.Set(pod.pod.GetLabels())) {
+		return false, nil
+	}
+
+	// Get pod namespace and check for namespace label selector match
+	ns := corev1.Namespace{}
+	err = w.client.Get(ctx, client.ObjectKey{Name: pod.pod.GetNamespace()}, &ns)
+	if err != nil {
+		return false, fmt.Errorf("failed to run webhook on pod %s, error while getting namespace: %w",
+			pod.pod.GetName(),
+			err,
+		)
+	}
+	log.V(5).Info("Found pod namespace", "Namespace.Name", ns.GetName())
+	nsSelector, err := metav1.LabelSelectorAsSelector(w.namespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse namespace selector: %w", err)
+	}
+	return nsSelector.Matches(labels.Set(ns.GetLabels())), nil
+}
+
 // +kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create;update,versions=v1,name=vpod.kb.io,admissionReviewVersions=v1
 
 var _ webhook.CustomValidator = &PodWebhook{}
@@ -210,6 +221,20 @@ func (w *PodWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (ad
 
 	allErrs = append(allErrs, validatePodGroupMetadata(pod)...)
 
+	if w.queueNameRequiredNamespaceSelector != nil {
+		selected, err := w.selectsPod(ctx, pod)
+		if err != nil {
+			return nil, err
+		}
+		if selected {
+			queueNameErrs, err := jobframework.ValidateQueueNameRequired(ctx, w.client, w.queueNameRequiredNamespaceSelector, pod)
+			if err != nil {
+				return nil, err
+			}
+			allErrs = append(allErrs, queueNameErrs...)
+		}
+	}
+
 	if warn := warningForPodManagedLabel(pod); warn != "" {
 		warnings = append(warnings, warn)
 	}
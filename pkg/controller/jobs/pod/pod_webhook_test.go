@@ -475,6 +475,68 @@ func TestValidateCreate(t *testing.T) {
 	}
 }
 
+func TestValidateCreateQueueNameEnforcement(t *testing.T) {
+	enforcedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "enforced",
+			Labels: map[string]string{"queue-name-required": "true"},
+		},
+	}
+	queueNameRequiredSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"queue-name-required": "true"},
+	}
+	testCases := map[string]struct {
+		pod         *corev1.Pod
+		podSelector *metav1.LabelSelector
+		wantErr     error
+	}{
+		"unqueued pod selected by the pod integration": {
+			podSelector: &metav1.LabelSelector{},
+			pod:         testingpod.MakePod("test-pod", "enforced").Obj(),
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "metadata.labels[kueue.x-k8s.io/queue-name]",
+				},
+			}.ToAggregate(),
+		},
+		"queued pod selected by the pod integration": {
+			podSelector: &metav1.LabelSelector{},
+			pod:         testingpod.MakePod("test-pod", "enforced").Queue("test-queue").Obj(),
+		},
+		"unqueued pod not selected by the pod integration": {
+			pod: testingpod.MakePod("test-pod", "enforced").Obj(),
+			podSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "batch"},
+			},
+		},
+		"unqueued pod owned by a job managed by kueue": {
+			podSelector: &metav1.LabelSelector{},
+			pod: testingpod.MakePod("test-pod", "enforced").
+				OwnerReference("parent-job", batchv1.SchemeGroupVersion.WithKind("Job")).
+				Obj(),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			w := &PodWebhook{
+				client:                             utiltesting.NewClientBuilder().WithObjects(enforcedNamespace).Build(),
+				queueNameRequiredNamespaceSelector: queueNameRequiredSelector,
+				namespaceSelector:                  &metav1.LabelSelector{},
+				podSelector:                        tc.podSelector,
+			}
+
+			ctx, _ := utiltesting.ContextWithLog(t)
+
+			_, err := w.ValidateCreate(ctx, tc.pod)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.IgnoreFields(field.Error{}, "BadValue", "Detail")); diff != "" {
+				t.Errorf("Unexpected error (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestValidateUpdate(t *testing.T) {
 	testCases := map[string]struct {
 		oldPod    *corev1.Pod
@@ -38,6 +38,10 @@ var (
 const (
 	headGroupPodSetName = "head"
 	FrameworkName       = "ray.io/raycluster"
+
+	// rayClusterLabel is the label that KubeRay sets in the pods of a
+	// RayCluster, with its name.
+	rayClusterLabel = "ray.io/cluster"
 )
 
 func init() {
@@ -66,6 +70,7 @@ var NewReconciler = jobframework.NewGenericReconcilerFactory(func() jobframework
 type RayCluster rayv1.RayCluster
 
 var _ jobframework.GenericJob = (*RayCluster)(nil)
+var _ jobframework.JobWithPodLabels = (*RayCluster)(nil)
 
 func (j *RayCluster) Object() client.Object {
 	return (*rayv1.RayCluster)(j)
@@ -137,6 +142,13 @@ func (j *RayCluster) RunWithPodSetsInfo(podSetsInfo []podset.PodSetInfo) error {
 		if err := podset.Merge(&workerPod.ObjectMeta, &workerPod.Spec, info); err != nil {
 			return err
 		}
+		// Optional worker groups that were not admitted don't run. The
+		// minReplicas are dropped too, as they take precedence over the replicas.
+		if podset.IsDropped(&workerPod.ObjectMeta, info) {
+			wgs := &j.Spec.WorkerGroupSpecs[index]
+			wgs.Replicas = ptr.To[int32](0)
+			wgs.MinReplicas = ptr.To[int32](0)
+		}
 	}
 	return nil
 }
@@ -156,6 +168,10 @@ func (j *RayCluster) RestorePodSetsInfo(podSetsInfo []podset.PodSetInfo) bool {
 		workerPod := &j.Spec.WorkerGroupSpecs[index].Template
 		info := podSetsInfo[index+1]
 		changed = podset.RestorePodSpec(&workerPod.ObjectMeta, &workerPod.Spec, info) || changed
+		if wgs := &j.Spec.WorkerGroupSpecs[index]; ptr.Deref(wgs.Replicas, 1) == 0 && info.Count > 0 {
+			wgs.Replicas = ptr.To(info.Count)
+			changed = true
+		}
 	}
 	return changed
 }
@@ -169,6 +185,10 @@ func (j *RayCluster) PodsReady() bool {
 	return j.Status.State == rayv1.Ready
 }
 
+func (j *RayCluster) PodLabels() map[string]string {
+	return map[string]string{rayClusterLabel: j.Name}
+}
+
 func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
 	return jobframework.SetupWorkloadOwnerIndex(ctx, indexer, gvk)
 }
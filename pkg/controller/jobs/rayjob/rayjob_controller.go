@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
@@ -137,6 +138,13 @@ func (j *RayJob) RunWithPodSetsInfo(podSetsInfo []podset.PodSetInfo) error {
 		if err := podset.Merge(&workerPod.ObjectMeta, &workerPod.Spec, info); err != nil {
 			return err
 		}
+		// Optional worker groups that were not admitted don't run. The
+		// minReplicas are dropped too, as they take precedence over the replicas.
+		if podset.IsDropped(&workerPod.ObjectMeta, info) {
+			wgs := &j.Spec.RayClusterSpec.WorkerGroupSpecs[index]
+			wgs.Replicas = ptr.To[int32](0)
+			wgs.MinReplicas = ptr.To[int32](0)
+		}
 	}
 	return nil
 }
@@ -156,6 +164,10 @@ func (j *RayJob) RestorePodSetsInfo(podSetsInfo []podset.PodSetInfo) bool {
 		workerPod := &j.Spec.RayClusterSpec.WorkerGroupSpecs[index].Template
 		info := podSetsInfo[index+1]
 		changed = podset.RestorePodSpec(&workerPod.ObjectMeta, &workerPod.Spec, info) || changed
+		if wgs := &j.Spec.RayClusterSpec.WorkerGroupSpecs[index]; ptr.Deref(wgs.Replicas, 1) == 0 && info.Count > 0 {
+			wgs.Replicas = ptr.To(info.Count)
+			changed = true
+		}
 	}
 	return changed
 }
@@ -268,3 +268,33 @@ func TestNodeSelectors(t *testing.T) {
 		})
 	}
 }
+
+func TestRunWithPodSetsInfoDroppedPodSet(t *testing.T) {
+	rayJob := testingrayutil.MakeJob("job", "ns").Obj()
+	workers := &rayJob.Spec.RayClusterSpec.WorkerGroupSpecs[0]
+	workers.Replicas = ptr.To[int32](3)
+	workers.MinReplicas = ptr.To[int32](3)
+	workers.Template.Annotations = map[string]string{kueue.PodSetOptionalAnnotation: "true"}
+	job := (*RayJob)(rayJob)
+
+	if err := job.RunWithPodSetsInfo([]podset.PodSetInfo{
+		{Name: "head", Count: 1},
+		{Name: "workers-group-0", Count: 0},
+	}); err != nil {
+		t.Fatalf("Failed to run the RayJob: %v", err)
+	}
+	if diff := cmp.Diff([]*int32{ptr.To[int32](0), ptr.To[int32](0)}, []*int32{workers.Replicas, workers.MinReplicas}); diff != "" {
+		t.Errorf("Unexpected replicas and minReplicas of the dropped worker group (-want,+got):\n%s", diff)
+	}
+
+	job.Suspend()
+	if changed := job.RestorePodSetsInfo([]podset.PodSetInfo{
+		{Name: "head", Count: 1},
+		{Name: "workers-group-0", Count: 3},
+	}); !changed {
+		t.Error("Expected the RayJob to be changed when restoring")
+	}
+	if got := ptr.Deref(workers.Replicas, 0); got != 3 {
+		t.Errorf("Unexpected worker replicas after restoring, want 3, got %d", got)
+	}
+}
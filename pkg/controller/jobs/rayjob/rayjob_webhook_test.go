@@ -23,10 +23,13 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	corev1 "k8s.io/api/core/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/controller/constants"
 	testingrayutil "sigs.k8s.io/kueue/pkg/util/testingjobs/rayjob"
 )
@@ -145,6 +148,20 @@ func TestValidateCreate(t *testing.T) {
 				field.TooMany(field.NewPath("spec", "rayClusterSpec", "workerGroupSpecs"), 8, 7),
 			}.ToAggregate(),
 		},
+		"optional head group": {
+			job: testingrayutil.MakeJob("job", "ns").Queue("queue").
+				WithHeadGroupSpec(rayv1.HeadGroupSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{kueue.PodSetOptionalAnnotation: "true"},
+						},
+					},
+				}).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "rayClusterSpec", "headGroupSpec", "template", "metadata", "annotations").Key(kueue.PodSetOptionalAnnotation), "the head group can't be optional"),
+			}.ToAggregate(),
+		},
 		"worker group uses head name": {
 			job: testingrayutil.MakeJob("job", "ns").Queue("queue").
 				WithWorkerGroups(rayv1.WorkerGroupSpec{
@@ -18,12 +18,15 @@ package debugger
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/cache"
 	"sigs.k8s.io/kueue/pkg/queue"
 )
@@ -62,3 +65,138 @@ func (d *Dumper) DumpAll(ctx context.Context) {
 	d.queues.LogDump(log)
 	log.Info("Ended dump")
 }
+
+// CohortGraphPath is the path of the endpoint, in the metrics server,
+// serving the cohort graph.
+const CohortGraphPath = "/debug/cohorts"
+
+// NewCohortGraphHandler returns a read-only handler serving, as JSON, the
+// cohort graph of the cache returned by getCache.
+func NewCohortGraphHandler(getCache func() *cache.Cache) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		c := getCache()
+		if c == nil {
+			http.Error(w, "cache not initialized", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(c.CohortGraph()); err != nil {
+			ctrl.LoggerFrom(r.Context()).Error(err, "Failed to encode the cohort graph")
+		}
+	})
+}
+
+// QueuesPath is the path of the endpoint, in the metrics server, serving
+// the pending workloads of each ClusterQueue in scheduling order.
+const QueuesPath = "/debug/queues"
+
+// NewQueuesHandler returns a read-only handler serving, as JSON, the ordered
+// pending workloads of the queue manager returned by getQueues.
+func NewQueuesHandler(getQueues func() *queue.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		q := getQueues()
+		if q == nil {
+			http.Error(w, "queue manager not initialized", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(q.DumpOrdered()); err != nil {
+			ctrl.LoggerFrom(r.Context()).Error(err, "Failed to encode the queues")
+		}
+	})
+}
+
+// SchedulingPausePath is the path of the endpoint, in the metrics server,
+// used to pause and resume the admission of workloads.
+const SchedulingPausePath = "/debug/scheduling/pause"
+
+// Pauser is implemented by the scheduler.
+type Pauser interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+type pauseStatus struct {
+	Paused bool `json:"paused"`
+}
+
+// NewSchedulingPauseHandler returns a handler to control the Pauser returned
+// by getPauser. A PUT pauses the admission of workloads, a DELETE resumes it
+// and a GET reports whether it is paused.
+func NewSchedulingPauseHandler(getPauser func() Pauser) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := getPauser()
+		if p == nil {
+			http.Error(w, "scheduler not initialized", http.StatusServiceUnavailable)
+			return
+		}
+		log := ctrl.LoggerFrom(r.Context())
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			p.Pause()
+			log.Info("Paused the admission of workloads")
+		case http.MethodDelete:
+			p.Resume()
+			log.Info("Resumed the admission of workloads")
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(pauseStatus{Paused: p.Paused()}); err != nil {
+			log.Error(err, "Failed to encode the pause status")
+		}
+	})
+}
+
+// CanFitPath is the path of the endpoint, in the metrics server, used to
+// simulate the admission of a workload.
+const CanFitPath = "/debug/scheduling/can-fit"
+
+// Fitter is implemented by the scheduler.
+type Fitter interface {
+	CanFit(ctx context.Context, wl *kueue.Workload) (bool, string)
+}
+
+type canFitResult struct {
+	Fits    bool   `json:"fits"`
+	Message string `json:"message"`
+}
+
+// NewCanFitHandler returns a handler that reports whether the Workload
+// POSTed as JSON would be admitted by the Fitter returned by getFitter.
+// The workload is not created nor queued.
+func NewCanFitHandler(getFitter func() Fitter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		f := getFitter()
+		if f == nil {
+			http.Error(w, "scheduler not initialized", http.StatusServiceUnavailable)
+			return
+		}
+		var wl kueue.Workload
+		if err := json.NewDecoder(r.Body).Decode(&wl); err != nil {
+			http.Error(w, "decoding the workload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		var res canFitResult
+		res.Fits, res.Message = f.CanFit(r.Context(), &wl)
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			ctrl.LoggerFrom(r.Context()).Error(err, "Failed to encode the admission simulation")
+		}
+	})
+}
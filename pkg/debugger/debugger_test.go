@@ -0,0 +1,363 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/queue"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestCohortGraphHandler(t *testing.T) {
+	ctx := context.Background()
+	c := cache.New(utiltesting.NewFakeClient())
+	c.AddOrUpdateCohort(utiltesting.MakeCohort("eng").Parent("org").Obj())
+	for _, cq := range []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("eng-b").Cohort("eng").Obj(),
+		utiltesting.MakeClusterQueue("eng-a").Cohort("eng").Obj(),
+		utiltesting.MakeClusterQueue("research-a").Cohort("research").Obj(),
+		utiltesting.MakeClusterQueue("standalone").Obj(),
+	} {
+		if err := c.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Failed to add clusterQueue %s: %v", cq.Name, err)
+		}
+	}
+
+	cases := map[string]struct {
+		method     string
+		cache      *cache.Cache
+		wantStatus int
+		wantBody   string
+	}{
+		"get": {
+			method:     http.MethodGet,
+			cache:      c,
+			wantStatus: http.StatusOK,
+			wantBody: `{"cohorts":[` +
+				`{"name":"eng","parent":"org","clusterQueues":["eng-a","eng-b"]},` +
+				`{"name":"org","clusterQueues":[]},` +
+				`{"name":"research","clusterQueues":["research-a"]}` +
+				`]}` + "\n",
+		},
+		"post is not allowed": {
+			method:     http.MethodPost,
+			cache:      c,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   "method not allowed\n",
+		},
+		"cache not initialized": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "cache not initialized\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewCohortGraphHandler(func() *cache.Cache { return tc.cache })
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, CohortGraphPath, nil))
+			if rec.Code != tc.wantStatus {
+				t.Errorf("Unexpected status code, want %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if diff := cmp.Diff(tc.wantBody, rec.Body.String()); diff != "" {
+				t.Errorf("Unexpected body (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueuesHandler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	manager := queue.NewManager(utiltesting.NewFakeClient(), nil)
+	for _, cq := range []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("cq").Obj(),
+		utiltesting.MakeClusterQueue("empty-cq").Obj(),
+	} {
+		if err := manager.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Failed adding clusterQueue %s: %v", cq.Name, err)
+		}
+	}
+	q := utiltesting.MakeLocalQueue("foo", "earth").ClusterQueue("cq").Obj()
+	if err := manager.AddLocalQueue(ctx, q); err != nil {
+		t.Fatalf("Failed adding queue %s: %v", q.Name, err)
+	}
+	for _, wl := range []*kueue.Workload{
+		utiltesting.MakeWorkload("low", "earth").Queue("foo").Creation(now.Add(-time.Minute)).Obj(),
+		utiltesting.MakeWorkload("high", "earth").Queue("foo").Priority(10).Creation(now).Obj(),
+	} {
+		if !manager.AddOrUpdateWorkload(wl) {
+			t.Fatalf("Failed adding workload %s", wl.Name)
+		}
+	}
+
+	cases := map[string]struct {
+		method     string
+		queues     *queue.Manager
+		wantStatus int
+		wantDump   map[string][]queue.DumpedWorkload
+		wantBody   string
+	}{
+		"get": {
+			method:     http.MethodGet,
+			queues:     manager,
+			wantStatus: http.StatusOK,
+			wantDump: map[string][]queue.DumpedWorkload{
+				"cq": {
+					{Key: "earth/high", Priority: 10, CreationTimestamp: metav1.NewTime(now)},
+					{Key: "earth/low", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+				},
+				"empty-cq": {},
+			},
+		},
+		"delete is not allowed": {
+			method:     http.MethodDelete,
+			queues:     manager,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   "method not allowed\n",
+		},
+		"queue manager not initialized": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "queue manager not initialized\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewQueuesHandler(func() *queue.Manager { return tc.queues })
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, QueuesPath, nil))
+			if rec.Code != tc.wantStatus {
+				t.Errorf("Unexpected status code, want %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if tc.wantDump == nil {
+				if diff := cmp.Diff(tc.wantBody, rec.Body.String()); diff != "" {
+					t.Errorf("Unexpected body (-want,+got):\n%s", diff)
+				}
+				return
+			}
+			var gotDump map[string][]queue.DumpedWorkload
+			if err := json.Unmarshal(rec.Body.Bytes(), &gotDump); err != nil {
+				t.Fatalf("Failed decoding the body %q: %v", rec.Body.String(), err)
+			}
+			if diff := cmp.Diff(tc.wantDump, gotDump); diff != "" {
+				t.Errorf("Unexpected queues (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type fakeFitter struct {
+	got *kueue.Workload
+}
+
+func (f *fakeFitter) CanFit(_ context.Context, wl *kueue.Workload) (bool, string) {
+	f.got = wl
+	if wl.Spec.QueueName == "" {
+		return false, "workload has no queue"
+	}
+	return true, "fits in ClusterQueue cq"
+}
+
+func TestCanFitHandler(t *testing.T) {
+	cases := map[string]struct {
+		method     string
+		body       string
+		noFitter   bool
+		wantStatus int
+		wantBody   string
+		wantQueue  string
+	}{
+		"fits": {
+			method:     http.MethodPost,
+			body:       `{"metadata":{"name":"wl","namespace":"ns"},"spec":{"queueName":"lq"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"fits":true,"message":"fits in ClusterQueue cq"}` + "\n",
+			wantQueue:  "lq",
+		},
+		"doesn't fit": {
+			method:     http.MethodPost,
+			body:       `{"metadata":{"name":"wl","namespace":"ns"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"fits":false,"message":"workload has no queue"}` + "\n",
+		},
+		"malformed json": {
+			method:     http.MethodPost,
+			body:       `{"metadata":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "decoding the workload: unexpected EOF\n",
+		},
+		"not an object": {
+			method:     http.MethodPost,
+			body:       `[]`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "decoding the workload: json: cannot unmarshal array into Go value of type v1beta1.Workload\n",
+		},
+		"empty body": {
+			method:     http.MethodPost,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "decoding the workload: EOF\n",
+		},
+		"get is not allowed": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   "method not allowed\n",
+		},
+		"put is not allowed": {
+			method:     http.MethodPut,
+			body:       `{"metadata":{"name":"wl","namespace":"ns"}}`,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   "method not allowed\n",
+		},
+		"scheduler not initialized": {
+			method:     http.MethodPost,
+			body:       `{"metadata":{"name":"wl","namespace":"ns"}}`,
+			noFitter:   true,
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "scheduler not initialized\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fitter := &fakeFitter{}
+			handler := NewCanFitHandler(func() Fitter {
+				if tc.noFitter {
+					return nil
+				}
+				return fitter
+			})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, CanFitPath, strings.NewReader(tc.body)))
+			if rec.Code != tc.wantStatus {
+				t.Errorf("Unexpected status code, want %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if diff := cmp.Diff(tc.wantBody, rec.Body.String()); diff != "" {
+				t.Errorf("Unexpected body (-want,+got):\n%s", diff)
+			}
+			var gotQueue string
+			if fitter.got != nil {
+				gotQueue = fitter.got.Spec.QueueName
+			}
+			if gotQueue != tc.wantQueue {
+				t.Errorf("Unexpected queue of the simulated workload, want %q, got %q", tc.wantQueue, gotQueue)
+			}
+		})
+	}
+}
+
+type fakePauser struct {
+	paused bool
+}
+
+func (p *fakePauser) Pause()       { p.paused = true }
+func (p *fakePauser) Resume()      { p.paused = false }
+func (p *fakePauser) Paused() bool { return p.paused }
+
+func TestSchedulingPauseHandler(t *testing.T) {
+	cases := map[string]struct {
+		method     string
+		paused     bool
+		noPauser   bool
+		wantStatus int
+		wantBody   string
+		wantPaused bool
+	}{
+		"get running": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"paused":false}` + "\n",
+		},
+		"get paused": {
+			method:     http.MethodGet,
+			paused:     true,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"paused":true}` + "\n",
+			wantPaused: true,
+		},
+		"put pauses": {
+			method:     http.MethodPut,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"paused":true}` + "\n",
+			wantPaused: true,
+		},
+		"put when already paused": {
+			method:     http.MethodPut,
+			paused:     true,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"paused":true}` + "\n",
+			wantPaused: true,
+		},
+		"delete resumes": {
+			method:     http.MethodDelete,
+			paused:     true,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"paused":false}` + "\n",
+		},
+		"post is not allowed": {
+			method:     http.MethodPost,
+			paused:     true,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   "method not allowed\n",
+			wantPaused: true,
+		},
+		"patch is not allowed": {
+			method:     http.MethodPatch,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   "method not allowed\n",
+		},
+		"scheduler not initialized": {
+			method:     http.MethodPut,
+			noPauser:   true,
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "scheduler not initialized\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pauser := &fakePauser{paused: tc.paused}
+			handler := NewSchedulingPauseHandler(func() Pauser {
+				if tc.noPauser {
+					return nil
+				}
+				return pauser
+			})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, SchedulingPausePath, nil))
+			if rec.Code != tc.wantStatus {
+				t.Errorf("Unexpected status code, want %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if got := rec.Body.String(); got != tc.wantBody {
+				t.Errorf("Unexpected body, want %q, got %q", tc.wantBody, got)
+			}
+			if pauser.paused != tc.wantPaused {
+				t.Errorf("Unexpected paused state, want %t, got %t", tc.wantPaused, pauser.paused)
+			}
+		})
+	}
+}
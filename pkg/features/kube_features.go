@@ -90,6 +90,49 @@ const (
 	//
 	// Enable the usage of batch.Job spec.managedBy field its MultiKueue integration.
 	MultiKueueBatchJobWithManagedBy featuregate.Feature = "MultiKueueBatchJobWithManagedBy"
+
+	// alpha: v0.8
+	//
+	// Enables the accounting of the resource claims of the PodSets by device class.
+	DynamicResourceAllocation featuregate.Feature = "DynamicResourceAllocation"
+
+	// alpha: v0.8
+	//
+	// Enables mirroring the admission state of the workloads in a condition
+	// of the jobs that support it.
+	JobAdmittedCondition featuregate.Feature = "JobAdmittedCondition"
+
+	// alpha: v0.8
+	//
+	// Enables publishing the admission decisions of the scheduler to Leases,
+	// so that they can be aggregated across clusters.
+	AdmissionDecisionPublishing featuregate.Feature = "AdmissionDecisionPublishing"
+
+	// alpha: v0.8
+	//
+	// Enables reporting an estimate of when the head workload of a
+	// ClusterQueue is likely to be admitted.
+	HeadAdmissionEstimate featuregate.Feature = "HeadAdmissionEstimate"
+
+	// alpha: v0.8
+	//
+	// Enables scaling down the admitted workloads that can be partially
+	// admitted, instead of evicting them, when their quota is reclaimed
+	// in the cohort.
+	PartialAdmissionScaleDown featuregate.Feature = "PartialAdmissionScaleDown"
+
+	// alpha: v0.8
+	//
+	// Enables evicting the workloads admitted in ResourceFlavors with
+	// evictOnNodeUnavailable when their Nodes become unavailable.
+	// It starts a cluster-wide Pod informer.
+	NodeUnavailableEviction featuregate.Feature = "NodeUnavailableEviction"
+
+	// alpha: v0.8
+	//
+	// Enables rejecting the creation of Workloads with the same owner and
+	// podSets as an existing Workload.
+	RejectDuplicateWorkloads featuregate.Feature = "RejectDuplicateWorkloads"
 )
 
 func init() {
@@ -112,6 +155,13 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	MultiKueue:                      {Default: false, PreRelease: featuregate.Alpha},
 	LendingLimit:                    {Default: false, PreRelease: featuregate.Alpha},
 	MultiKueueBatchJobWithManagedBy: {Default: false, PreRelease: featuregate.Alpha},
+	DynamicResourceAllocation:       {Default: false, PreRelease: featuregate.Alpha},
+	JobAdmittedCondition:            {Default: false, PreRelease: featuregate.Alpha},
+	AdmissionDecisionPublishing:     {Default: false, PreRelease: featuregate.Alpha},
+	HeadAdmissionEstimate:           {Default: false, PreRelease: featuregate.Alpha},
+	PartialAdmissionScaleDown:       {Default: false, PreRelease: featuregate.Alpha},
+	NodeUnavailableEviction:         {Default: false, PreRelease: featuregate.Alpha},
+	RejectDuplicateWorkloads:        {Default: false, PreRelease: featuregate.Alpha},
 }
 
 func SetFeatureGateDuringTest(tb testing.TB, f featuregate.Feature, value bool) func() {
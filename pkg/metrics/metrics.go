@@ -17,6 +17,7 @@ limitations under the License.
 package metrics
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,12 +29,22 @@ import (
 )
 
 type AdmissionResult string
+type WorkloadAdmissionResult string
 type ClusterQueueStatus string
+type WorkloadCacheInconsistency string
 
 const (
 	AdmissionResultSuccess      AdmissionResult = "success"
 	AdmissionResultInadmissible AdmissionResult = "inadmissible"
 
+	WorkloadAdmissionResultAdmitted  WorkloadAdmissionResult = "admitted"
+	WorkloadAdmissionResultPending   WorkloadAdmissionResult = "pending"
+	WorkloadAdmissionResultPreempted WorkloadAdmissionResult = "preempted"
+	WorkloadAdmissionResultError     WorkloadAdmissionResult = "error"
+
+	WorkloadCacheInconsistencyMissing    WorkloadCacheInconsistency = "missing"
+	WorkloadCacheInconsistencyUnexpected WorkloadCacheInconsistency = "unexpected"
+
 	PendingStatusActive       = "active"
 	PendingStatusInadmissible = "inadmissible"
 
@@ -67,6 +78,30 @@ The label 'result' can have the following values:
 		}, []string{"result"},
 	)
 
+	WorkloadAdmissionAttemptsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: constants.KueueName,
+			Name:      "workload_admission_attempts_total",
+			Help: `The total number of attempts to admit a workload, per 'cluster_queue' and 'result'.
+The label 'result' can have the following values:
+- 'admitted' means that the workload got quota reserved,
+- 'preempted' means that the workload issued preemptions to make room for it,
+- 'pending' means that the workload didn't fit and stays pending,
+- 'error' means that reserving the quota for the workload failed.`,
+		}, []string{"cluster_queue", "result"},
+	)
+
+	WorkloadCacheInconsistenciesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: constants.KueueName,
+			Name:      "workload_cache_inconsistencies_total",
+			Help: `The total number of workloads found out of sync with the cache, which were repaired.
+The label 'reason' can have the following values:
+- 'missing' means that the workload had a quota reservation but wasn't in the cache,
+- 'unexpected' means that the workload was in the cache without a quota reservation, or in another ClusterQueue.`,
+		}, []string{"reason"},
+	)
+
 	admissionAttemptDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Subsystem: constants.KueueName,
@@ -91,6 +126,15 @@ The label 'result' can have the following values:
 		}, []string{"cluster_queue", "status"},
 	)
 
+	PendingWorkloadsByPriority = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cluster_queue_pending_by_priority",
+			Help: `The number of pending workloads, per 'cluster_queue' and 'priority_bucket'.
+'priority_bucket' is the range of priorities of the workloads, one of "<0", "0", "1-9", "10-99", ..., "1000000+"`,
+		}, []string{"cluster_queue", "priority_bucket"},
+	)
+
 	QuotaReservedWorkloadsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Subsystem: constants.KueueName,
@@ -116,7 +160,7 @@ The label 'result' can have the following values:
 		}, []string{"cluster_queue"},
 	)
 
-	admissionWaitTime = prometheus.NewHistogramVec(
+	AdmissionWaitTime = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Subsystem: constants.KueueName,
 			Name:      "admission_wait_time_seconds",
@@ -148,6 +192,17 @@ The label 'reason' can have the following values:
 		}, []string{"cluster_queue", "reason"},
 	)
 
+	PreemptedWorkloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: constants.KueueName,
+			Name:      "preempted_workloads_total",
+			Help: `The number of preempted workloads per 'preempting_cluster_queue',
+The label 'reason' can have the following values:
+- "InClusterQueue" means that the workload was preempted by a workload in the same ClusterQueue.
+- "InCohort" means that the workload was preempted by a workload in another ClusterQueue of the cohort, to reclaim its quota.`,
+		}, []string{"preempting_cluster_queue", "reason"},
+	)
+
 	// Metrics tied to the cache.
 
 	ReservingActiveWorkloads = prometheus.NewGaugeVec(
@@ -166,6 +221,38 @@ The label 'reason' can have the following values:
 		}, []string{"cluster_queue"},
 	)
 
+	AdmittedButUnschedulableWorkloads = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "admitted_but_unschedulable",
+			Help:      "The number of admitted Workloads with pods still unschedulable after the configured threshold since the job was started, per 'cluster_queue'",
+		}, []string{"cluster_queue"},
+	)
+
+	EvictingWorkloads = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "evicting_workloads",
+			Help:      "The number of evicted Workloads that are still reserving quota while their pods terminate, per 'cluster_queue'",
+		}, []string{"cluster_queue"},
+	)
+
+	OldestAdmittedWorkloadTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cluster_queue_oldest_admitted_workload_timestamp_seconds",
+			Help: `The time, in seconds since the epoch, when the oldest active Workload of the 'cluster_queue' was admitted.
+The age of the Workload can be computed as time() minus the value of the metric.`,
+		}, []string{"cluster_queue"},
+	)
+
+	clusterQueueUsageTrendDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(constants.KueueName, "", "cluster_queue_usage_trend"),
+		`The average rate of change, per second, of the resource reservation of the 'cluster_queue' over the last 5 minutes, per 'flavor' and 'resource'.
+It's computed when the metrics are scraped. A positive value means that the reservation is growing.`,
+		[]string{"cluster_queue", "flavor", "resource"}, nil,
+	)
+
 	ClusterQueueByStatus = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Subsystem: constants.KueueName,
@@ -184,6 +271,14 @@ For a ClusterQueue, the metric only reports a value of 1 for one of the statuses
 		}, []string{"cohort", "cluster_queue", "flavor", "resource"},
 	)
 
+	ClusterQueueReservedResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cluster_queue_reserved_resources",
+			Help:      `Reports the cluster_queue's resources that are reserved by workloads that are not admitted yet, within all the flavors`,
+		}, []string{"cohort", "cluster_queue", "flavor", "resource"},
+	)
+
 	ClusterQueueResourceUsage = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Subsystem: constants.KueueName,
@@ -192,6 +287,14 @@ For a ClusterQueue, the metric only reports a value of 1 for one of the statuses
 		}, []string{"cohort", "cluster_queue", "flavor", "resource"},
 	)
 
+	ClusterQueueResourceUsageRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cluster_queue_resource_usage_ratio",
+			Help:      `Reports the ratio of the cluster_queue's resource usage to its nominal quota, for the resources with a non-zero nominal quota`,
+		}, []string{"cluster_queue", "flavor", "resource"},
+	)
+
 	ClusterQueueResourceNominalQuota = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Subsystem: constants.KueueName,
@@ -239,6 +342,14 @@ func AdmissionAttempt(result AdmissionResult, duration time.Duration) {
 	admissionAttemptDuration.WithLabelValues(string(result)).Observe(duration.Seconds())
 }
 
+func WorkloadAdmissionAttempt(cqName string, result WorkloadAdmissionResult) {
+	WorkloadAdmissionAttemptsTotal.WithLabelValues(cqName, string(result)).Inc()
+}
+
+func ReportWorkloadCacheInconsistency(reason WorkloadCacheInconsistency) {
+	WorkloadCacheInconsistenciesTotal.WithLabelValues(string(reason)).Inc()
+}
+
 func QuotaReservedWorkload(cqName kueue.ClusterQueueReference, waitTime time.Duration) {
 	QuotaReservedWorkloadsTotal.WithLabelValues(string(cqName)).Inc()
 	quotaReservedWaitTime.WithLabelValues(string(cqName)).Observe(waitTime.Seconds())
@@ -246,7 +357,7 @@ func QuotaReservedWorkload(cqName kueue.ClusterQueueReference, waitTime time.Dur
 
 func AdmittedWorkload(cqName kueue.ClusterQueueReference, waitTime time.Duration) {
 	AdmittedWorkloadsTotal.WithLabelValues(string(cqName)).Inc()
-	admissionWaitTime.WithLabelValues(string(cqName)).Observe(waitTime.Seconds())
+	AdmissionWaitTime.WithLabelValues(string(cqName)).Observe(waitTime.Seconds())
 }
 
 func AdmissionChecksWaitTime(cqName kueue.ClusterQueueReference, waitTime time.Duration) {
@@ -258,19 +369,68 @@ func ReportPendingWorkloads(cqName string, active, inadmissible int) {
 	PendingWorkloads.WithLabelValues(cqName, PendingStatusInadmissible).Set(float64(inadmissible))
 }
 
+// priorityBucketBounds are the lower bounds of the priority buckets, after
+// the buckets for negative and zero priorities. Bucketing the priorities
+// bounds the cardinality of the PendingWorkloadsByPriority metric.
+var priorityBucketBounds = []int32{1, 10, 100, 1_000, 10_000, 100_000, 1_000_000}
+
+// PriorityBuckets lists the possible values of the 'priority_bucket' label.
+var PriorityBuckets = func() []string {
+	buckets := []string{"<0", "0"}
+	for i, lower := range priorityBucketBounds {
+		if i == len(priorityBucketBounds)-1 {
+			buckets = append(buckets, strconv.Itoa(int(lower))+"+")
+		} else {
+			buckets = append(buckets, strconv.Itoa(int(lower))+"-"+strconv.Itoa(int(priorityBucketBounds[i+1]-1)))
+		}
+	}
+	return buckets
+}()
+
+// PriorityBucket returns the bucket of the 'priority_bucket' label for the
+// priority.
+func PriorityBucket(priority int32) string {
+	switch {
+	case priority < 0:
+		return PriorityBuckets[0]
+	case priority == 0:
+		return PriorityBuckets[1]
+	}
+	idx := 0
+	for idx+1 < len(priorityBucketBounds) && priority >= priorityBucketBounds[idx+1] {
+		idx++
+	}
+	return PriorityBuckets[idx+2]
+}
+
+// ReportPendingWorkloadsByPriority reports the number of pending workloads
+// for all the priority buckets, the buckets missing in counts are reported as 0.
+func ReportPendingWorkloadsByPriority(cqName string, counts map[string]int) {
+	for _, bucket := range PriorityBuckets {
+		PendingWorkloadsByPriority.WithLabelValues(cqName, bucket).Set(float64(counts[bucket]))
+	}
+}
+
 func ReportEvictedWorkloads(cqName, reason string) {
 	EvictedWorkloadsTotal.WithLabelValues(cqName, reason).Inc()
 }
 
+func ReportPreemption(preemptingCqName, reason string) {
+	PreemptedWorkloadsTotal.WithLabelValues(preemptingCqName, reason).Inc()
+}
+
 func ClearQueueSystemMetrics(cqName string) {
 	PendingWorkloads.DeleteLabelValues(cqName, PendingStatusActive)
 	PendingWorkloads.DeleteLabelValues(cqName, PendingStatusInadmissible)
+	PendingWorkloadsByPriority.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
 	QuotaReservedWorkloadsTotal.DeleteLabelValues(cqName)
 	quotaReservedWaitTime.DeleteLabelValues(cqName)
 	AdmittedWorkloadsTotal.DeleteLabelValues(cqName)
-	admissionWaitTime.DeleteLabelValues(cqName)
+	AdmissionWaitTime.DeleteLabelValues(cqName)
 	admissionChecksWaitTime.DeleteLabelValues(cqName)
 	EvictedWorkloadsTotal.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
+	PreemptedWorkloadsTotal.DeletePartialMatch(prometheus.Labels{"preempting_cluster_queue": cqName})
+	WorkloadAdmissionAttemptsTotal.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
 }
 
 func ReportClusterQueueStatus(cqName string, cqStatus ClusterQueueStatus) {
@@ -283,9 +443,54 @@ func ReportClusterQueueStatus(cqName string, cqStatus ClusterQueueStatus) {
 	}
 }
 
+// ReportOldestAdmittedWorkload reports the admission time of the oldest
+// active workload of the ClusterQueue, or clears it if there is none.
+func ReportOldestAdmittedWorkload(cqName string, admissionTime *time.Time) {
+	if admissionTime == nil {
+		OldestAdmittedWorkloadTimestamp.DeleteLabelValues(cqName)
+		return
+	}
+	OldestAdmittedWorkloadTimestamp.WithLabelValues(cqName).Set(float64(admissionTime.Unix()))
+}
+
+// UsageTrend is the average rate of change, per second, of the reservation
+// of a ClusterQueue for a flavor and resource.
+type UsageTrend struct {
+	ClusterQueue string
+	Flavor       string
+	Resource     string
+	Value        float64
+}
+
+// usageTrendCollector reports the usage trends of the ClusterQueues when the
+// metrics are scraped, so that they decay when the reservation stops
+// changing.
+type usageTrendCollector struct {
+	trends func() []UsageTrend
+}
+
+func (c *usageTrendCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clusterQueueUsageTrendDesc
+}
+
+func (c *usageTrendCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, t := range c.trends() {
+		ch <- prometheus.MustNewConstMetric(clusterQueueUsageTrendDesc, prometheus.GaugeValue, t.Value, t.ClusterQueue, t.Flavor, t.Resource)
+	}
+}
+
+// RegisterClusterQueueUsageTrend registers the usage trend metric, computed
+// by the given function at scrape time.
+func RegisterClusterQueueUsageTrend(trends func() []UsageTrend) {
+	metrics.Registry.MustRegister(&usageTrendCollector{trends: trends})
+}
+
 func ClearCacheMetrics(cqName string) {
 	ReservingActiveWorkloads.DeleteLabelValues(cqName)
 	AdmittedActiveWorkloads.DeleteLabelValues(cqName)
+	AdmittedButUnschedulableWorkloads.DeleteLabelValues(cqName)
+	EvictingWorkloads.DeleteLabelValues(cqName)
+	OldestAdmittedWorkloadTimestamp.DeleteLabelValues(cqName)
 	for _, status := range CQStatuses {
 		ClusterQueueByStatus.DeleteLabelValues(cqName, string(status))
 	}
@@ -303,10 +508,18 @@ func ReportClusterQueueResourceReservations(cohort, queue, flavor, resource stri
 	ClusterQueueResourceReservations.WithLabelValues(cohort, queue, flavor, resource).Set(usage)
 }
 
+func ReportClusterQueueReservedResources(cohort, queue, flavor, resource string, reserved float64) {
+	ClusterQueueReservedResources.WithLabelValues(cohort, queue, flavor, resource).Set(reserved)
+}
+
 func ReportClusterQueueResourceUsage(cohort, queue, flavor, resource string, usage float64) {
 	ClusterQueueResourceUsage.WithLabelValues(cohort, queue, flavor, resource).Set(usage)
 }
 
+func ReportClusterQueueResourceUsageRatio(queue, flavor, resource string, ratio float64) {
+	ClusterQueueResourceUsageRatio.WithLabelValues(queue, flavor, resource).Set(ratio)
+}
+
 func ReportClusterQueueWeightedShare(cq string, weightedShare int64) {
 	ClusterQueueWeightedShare.WithLabelValues(cq).Set(float64(weightedShare))
 }
@@ -321,7 +534,9 @@ func ClearClusterQueueResourceMetrics(cqName string) {
 		ClusterQueueResourceLendingLimit.DeletePartialMatch(lbls)
 	}
 	ClusterQueueResourceUsage.DeletePartialMatch(lbls)
+	ClusterQueueResourceUsageRatio.DeletePartialMatch(lbls)
 	ClusterQueueResourceReservations.DeletePartialMatch(lbls)
+	ClusterQueueReservedResources.DeletePartialMatch(lbls)
 }
 
 func ClearClusterQueueResourceQuotas(cqName, flavor, resource string) {
@@ -339,6 +554,7 @@ func ClearClusterQueueResourceQuotas(cqName, flavor, resource string) {
 	if features.Enabled(features.LendingLimit) {
 		ClusterQueueResourceLendingLimit.DeletePartialMatch(lbls)
 	}
+	ClusterQueueResourceUsageRatio.DeletePartialMatch(lbls)
 }
 
 func ClearClusterQueueResourceUsage(cqName, flavor, resource string) {
@@ -365,24 +581,34 @@ func ClearClusterQueueResourceReservations(cqName, flavor, resource string) {
 	}
 
 	ClusterQueueResourceReservations.DeletePartialMatch(lbls)
+	ClusterQueueReservedResources.DeletePartialMatch(lbls)
 }
 
 func Register() {
 	metrics.Registry.MustRegister(
 		AdmissionAttemptsTotal,
+		WorkloadAdmissionAttemptsTotal,
+		WorkloadCacheInconsistenciesTotal,
 		admissionAttemptDuration,
 		PendingWorkloads,
+		PendingWorkloadsByPriority,
 		ReservingActiveWorkloads,
 		AdmittedActiveWorkloads,
+		AdmittedButUnschedulableWorkloads,
+		EvictingWorkloads,
+		OldestAdmittedWorkloadTimestamp,
 		QuotaReservedWorkloadsTotal,
 		quotaReservedWaitTime,
 		AdmittedWorkloadsTotal,
 		EvictedWorkloadsTotal,
-		admissionWaitTime,
+		PreemptedWorkloadsTotal,
+		AdmissionWaitTime,
 		admissionChecksWaitTime,
 		ClusterQueueResourceUsage,
+		ClusterQueueResourceUsageRatio,
 		ClusterQueueByStatus,
 		ClusterQueueResourceReservations,
+		ClusterQueueReservedResources,
 		ClusterQueueResourceNominalQuota,
 		ClusterQueueResourceBorrowingLimit,
 		ClusterQueueResourceLendingLimit,
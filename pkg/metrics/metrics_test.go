@@ -158,3 +158,52 @@ func TestReportAndCleanupClusterQueueEvictedNumber(t *testing.T) {
 	ClearQueueSystemMetrics("cluster_queue1")
 	expectFilteredMetricsCount(t, EvictedWorkloadsTotal, 0, "cluster_queue", "cluster_queue1")
 }
+
+func TestReportAndCleanupWorkloadAdmissionAttempts(t *testing.T) {
+	WorkloadAdmissionAttempt("cluster_queue1", WorkloadAdmissionResultAdmitted)
+	WorkloadAdmissionAttempt("cluster_queue1", WorkloadAdmissionResultPending)
+
+	expectFilteredMetricsCount(t, WorkloadAdmissionAttemptsTotal, 2, "cluster_queue", "cluster_queue1")
+	expectFilteredMetricsCount(t, WorkloadAdmissionAttemptsTotal, 1, "cluster_queue", "cluster_queue1", "result", "admitted")
+	expectFilteredMetricsCount(t, WorkloadAdmissionAttemptsTotal, 1, "cluster_queue", "cluster_queue1", "result", "pending")
+	// clear
+	ClearQueueSystemMetrics("cluster_queue1")
+	expectFilteredMetricsCount(t, WorkloadAdmissionAttemptsTotal, 0, "cluster_queue", "cluster_queue1")
+}
+
+func TestPriorityBucket(t *testing.T) {
+	cases := map[int32]string{
+		-1_000:        "<0",
+		-1:            "<0",
+		0:             "0",
+		1:             "1-9",
+		9:             "1-9",
+		10:            "10-99",
+		999:           "100-999",
+		100_000:       "100000-999999",
+		1_000_000:     "1000000+",
+		2_000_000_000: "1000000+",
+	}
+	for priority, want := range cases {
+		if got := PriorityBucket(priority); got != want {
+			t.Errorf("PriorityBucket(%d) = %q, want %q", priority, got, want)
+		}
+	}
+}
+
+func TestReportAndCleanupPendingWorkloadsByPriority(t *testing.T) {
+	ReportPendingWorkloadsByPriority("cluster_queue1", map[string]int{"0": 2, "1-9": 1})
+
+	expectFilteredMetricsCount(t, PendingWorkloadsByPriority, len(PriorityBuckets), "cluster_queue", "cluster_queue1")
+	// clear
+	ClearQueueSystemMetrics("cluster_queue1")
+	expectFilteredMetricsCount(t, PendingWorkloadsByPriority, 0, "cluster_queue", "cluster_queue1")
+}
+
+func TestUsageTrendCollector(t *testing.T) {
+	trends := []UsageTrend{{ClusterQueue: "cq", Flavor: "default", Resource: "cpu", Value: 0.5}}
+	collector := &usageTrendCollector{trends: func() []UsageTrend { return trends }}
+	expectFilteredMetricsCount(t, collector, 1, "cluster_queue", "cq")
+	trends = nil
+	expectFilteredMetricsCount(t, collector, 0, "cluster_queue", "cq")
+}
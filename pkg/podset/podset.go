@@ -21,6 +21,7 @@ import (
 	"slices"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -42,7 +43,19 @@ type PodSetInfo struct {
 	Annotations  map[string]string
 	Labels       map[string]string
 	NodeSelector map[string]string
-	Tolerations  []corev1.Toleration
+	// NodeSelectorRequirements are added to every term of the required
+	// node affinity of the pods.
+	NodeSelectorRequirements []corev1.NodeSelectorRequirement
+	Tolerations              []corev1.Toleration
+	// Affinity is the affinity of the pods, used when restoring them.
+	Affinity *corev1.Affinity
+}
+
+// IsDropped returns whether the info is for an optional pod set, identified
+// by the annotations of its template, that was left out of the admission.
+// The pods of dropped pod sets must not run.
+func IsDropped(meta *metav1.ObjectMeta, info PodSetInfo) bool {
+	return info.Count == 0 && meta.Annotations[kueue.PodSetOptionalAnnotation] == "true"
 }
 
 // FromAssignment returns a PodSetInfo based on the provided assignment and an error if unable
@@ -66,6 +79,9 @@ func FromAssignment(ctx context.Context, client client.Client, assignment *kueue
 			return info, err
 		}
 		info.NodeSelector = utilmaps.MergeKeepFirst(info.NodeSelector, flv.Spec.NodeLabels)
+		if flv.Spec.NodeSelector != nil {
+			info.NodeSelectorRequirements = appendMissingRequirements(info.NodeSelectorRequirements, flv.Spec.NodeSelector.MatchExpressions)
+		}
 		info.Tolerations = append(info.Tolerations, flv.Spec.Tolerations...)
 
 		processedFlvs.Insert(flvRef)
@@ -92,6 +108,7 @@ func FromPodSet(ps *kueue.PodSet) PodSetInfo {
 		Labels:       maps.Clone(ps.Template.Labels),
 		NodeSelector: maps.Clone(ps.Template.Spec.NodeSelector),
 		Tolerations:  slices.Clone(ps.Template.Spec.Tolerations),
+		Affinity:     ps.Template.Spec.Affinity.DeepCopy(),
 	}
 }
 
@@ -108,6 +125,7 @@ func (podSetInfo *PodSetInfo) Merge(o PodSetInfo) error {
 	podSetInfo.Annotations = utilmaps.MergeKeepFirst(podSetInfo.Annotations, o.Annotations)
 	podSetInfo.Labels = utilmaps.MergeKeepFirst(podSetInfo.Labels, o.Labels)
 	podSetInfo.NodeSelector = utilmaps.MergeKeepFirst(podSetInfo.NodeSelector, o.NodeSelector)
+	podSetInfo.NodeSelectorRequirements = appendMissingRequirements(podSetInfo.NodeSelectorRequirements, o.NodeSelectorRequirements)
 
 	// make sure we don't duplicate tolerations
 	for _, t := range o.Tolerations {
@@ -144,9 +162,50 @@ func Merge(meta *metav1.ObjectMeta, spec *corev1.PodSpec, info PodSetInfo) error
 	meta.Labels = tmp.Labels
 	spec.NodeSelector = tmp.NodeSelector
 	spec.Tolerations = tmp.Tolerations
+	addRequiredNodeSelectorRequirements(spec, info.NodeSelectorRequirements)
 	return nil
 }
 
+// addRequiredNodeSelectorRequirements adds the requirements to every term of
+// the required node affinity of the pod spec, creating a term if there is
+// none. Since the terms are ORed, the pods then have to match the
+// requirements in addition to any of the original terms.
+func addRequiredNodeSelectorRequirements(spec *corev1.PodSpec, reqs []corev1.NodeSelectorRequirement) {
+	if len(reqs) == 0 {
+		return
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	required := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range required.NodeSelectorTerms {
+		term := &required.NodeSelectorTerms[i]
+		term.MatchExpressions = appendMissingRequirements(term.MatchExpressions, reqs)
+	}
+}
+
+// appendMissingRequirements appends the requirements that are not in the list yet.
+func appendMissingRequirements(list, reqs []corev1.NodeSelectorRequirement) []corev1.NodeSelectorRequirement {
+	for _, req := range reqs {
+		if !slices.ContainsFunc(list, func(r corev1.NodeSelectorRequirement) bool {
+			return equality.Semantic.DeepEqual(r, req)
+		}) {
+			list = append(list, req)
+		}
+	}
+	return list
+}
+
 // RestorePodSpec sets replica metadata and spec fields based on PodSetInfo.
 // It returns true if there is any change.
 func RestorePodSpec(meta *metav1.ObjectMeta, spec *corev1.PodSpec, info PodSetInfo) bool {
@@ -167,6 +226,10 @@ func RestorePodSpec(meta *metav1.ObjectMeta, spec *corev1.PodSpec, info PodSetIn
 		spec.Tolerations = slices.Clone(info.Tolerations)
 		changed = true
 	}
+	if !equality.Semantic.DeepEqual(spec.Affinity, info.Affinity) {
+		spec.Affinity = info.Affinity.DeepCopy()
+		changed = true
+	}
 	return changed
 }
 
@@ -62,6 +62,16 @@ func TestFromAssignment(t *testing.T) {
 		Toleration(*toleration3.DeepCopy()).
 		Obj()
 
+	genRequirement := corev1.NodeSelectorRequirement{
+		Key:      "example.com/generation",
+		Operator: corev1.NodeSelectorOpGt,
+		Values:   []string{"2"},
+	}
+	flavor3 := utiltesting.MakeResourceFlavor("flavor3").
+		Label("f3l1", "f3v1").
+		NodeSelectorTerm(genRequirement).
+		Obj()
+
 	cases := map[string]struct {
 		assignment   *kueue.PodSetAssignment
 		defaultCount int32
@@ -112,6 +122,26 @@ func TestFromAssignment(t *testing.T) {
 				Tolerations: []corev1.Toleration{*toleration1.DeepCopy(), *toleration2.DeepCopy(), *toleration3.DeepCopy()},
 			},
 		},
+		"flavor with node selector term": {
+			assignment: &kueue.PodSetAssignment{
+				Name: "name",
+				Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+					corev1.ResourceCPU:    kueue.ResourceFlavorReference(flavor3.Name),
+					corev1.ResourceMemory: kueue.ResourceFlavorReference(flavor3.Name),
+				},
+				Count: ptr.To[int32](2),
+			},
+			defaultCount: 4,
+			flavors:      []kueue.ResourceFlavor{*flavor3.DeepCopy()},
+			wantInfo: PodSetInfo{
+				Name:  "name",
+				Count: 2,
+				NodeSelector: map[string]string{
+					"f3l1": "f3v1",
+				},
+				NodeSelectorRequirements: []corev1.NodeSelectorRequirement{genRequirement},
+			},
+		},
 		"duplicate flavor": {
 			assignment: &kueue.PodSetAssignment{
 				Name: "name",
@@ -185,7 +215,7 @@ func TestFromAssignment(t *testing.T) {
 }
 
 func TestMergeRestore(t *testing.T) {
-	basePodSet := utiltesting.MakePodSet("", 1).
+	basePodSetWrapper := utiltesting.MakePodSet("", 1).
 		NodeSelector(map[string]string{"ns0": "ns0v"}).
 		Labels(map[string]string{"l0": "l0v"}).
 		Annotations(map[string]string{"a0": "a0v"}).
@@ -194,8 +224,24 @@ func TestMergeRestore(t *testing.T) {
 			Operator: corev1.TolerationOpEqual,
 			Value:    "t0v",
 			Effect:   corev1.TaintEffectNoSchedule,
-		}).
-		Obj()
+		})
+	basePodSet := basePodSetWrapper.Obj()
+
+	genRequirement := corev1.NodeSelectorRequirement{
+		Key:      "example.com/generation",
+		Operator: corev1.NodeSelectorOpGt,
+		Values:   []string{"2"},
+	}
+	zoneARequirement := corev1.NodeSelectorRequirement{
+		Key:      corev1.LabelTopologyZone,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{"a"},
+	}
+	zoneBRequirement := corev1.NodeSelectorRequirement{
+		Key:      corev1.LabelTopologyZone,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{"b"},
+	}
 
 	cases := map[string]struct {
 		podSet             *kueue.PodSet
@@ -282,6 +328,36 @@ func TestMergeRestore(t *testing.T) {
 				Obj(),
 			wantRestoreChanges: true,
 		},
+		"node selector requirements without node affinity": {
+			podSet: basePodSet.DeepCopy(),
+			info: PodSetInfo{
+				NodeSelectorRequirements: []corev1.NodeSelectorRequirement{genRequirement},
+			},
+			wantPodSet: basePodSetWrapper.Clone().
+				RequiredNodeSelectorTerms(corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{genRequirement},
+				}).
+				Obj(),
+			wantRestoreChanges: true,
+		},
+		"node selector requirements added to every term": {
+			podSet: basePodSetWrapper.Clone().
+				RequiredNodeSelectorTerms(
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zoneARequirement}},
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zoneBRequirement, genRequirement}},
+				).
+				Obj(),
+			info: PodSetInfo{
+				NodeSelectorRequirements: []corev1.NodeSelectorRequirement{genRequirement},
+			},
+			wantPodSet: basePodSetWrapper.Clone().
+				RequiredNodeSelectorTerms(
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zoneARequirement, genRequirement}},
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zoneBRequirement, genRequirement}},
+				).
+				Obj(),
+			wantRestoreChanges: true,
+		},
 		"conflicting label": {
 			podSet: basePodSet.DeepCopy(),
 			info: PodSetInfo{
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/util/heap"
 	utilpriority "sigs.k8s.io/kueue/pkg/util/priority"
 	"sigs.k8s.io/kueue/pkg/workload"
@@ -70,10 +71,17 @@ type ClusterQueue struct {
 	// QueueInadmissibleWorkloads is called.
 	queueInadmissibleCycle int64
 
-	lessFunc func(a, b *workload.Info) bool
+	lessFunc         func(a, b *workload.Info) bool
+	workloadOrdering workload.Ordering
 
 	queueingStrategy kueue.QueueingStrategy
 
+	// pendingBuckets holds the priority bucket of each pending workload, and
+	// pendingByBucket the number of pending workloads in each bucket. They are
+	// maintained as workloads enter and leave the queue.
+	pendingBuckets  map[string]string
+	pendingByBucket map[string]int
+
 	rwm sync.RWMutex
 
 	clock clock.Clock
@@ -98,7 +106,10 @@ func newClusterQueueImpl(wo workload.Ordering, clock clock.Clock) *ClusterQueue
 		heap:                   *heap.New(workloadKey, lessFunc),
 		inadmissibleWorkloads:  make(map[string]*workload.Info),
 		queueInadmissibleCycle: -1,
+		pendingBuckets:         make(map[string]string),
+		pendingByBucket:        make(map[string]int),
 		lessFunc:               lessFunc,
+		workloadOrdering:       wo,
 		rwm:                    sync.RWMutex{},
 		clock:                  clock,
 	}
@@ -116,6 +127,16 @@ func (c *ClusterQueue) Update(apiCQ *kueue.ClusterQueue) error {
 	}
 	c.namespaceSelector = nsSelector
 	c.active = apimeta.IsStatusConditionTrue(apiCQ.Status.Conditions, kueue.ClusterQueueActive)
+	if apiCQ.Spec.WorkloadSizeOrdering != c.workloadOrdering.SizeOrdering {
+		c.workloadOrdering.SizeOrdering = apiCQ.Spec.WorkloadSizeOrdering
+		c.lessFunc = queueOrderingFunc(c.workloadOrdering)
+		// Rebuild the heap so that the pending workloads follow the new order.
+		pending := c.heap.List()
+		c.heap = *heap.New(workloadKey, c.lessFunc)
+		for _, info := range pending {
+			c.heap.PushOrUpdate(info)
+		}
+	}
 	return nil
 }
 
@@ -133,6 +154,7 @@ func (c *ClusterQueue) AddFromLocalQueue(q *LocalQueue) bool {
 	added := false
 	for _, info := range q.items {
 		if c.heap.PushIfNotPresent(info) {
+			c.trackPending(info)
 			added = true
 		}
 	}
@@ -158,6 +180,7 @@ func (c *ClusterQueue) PushOrUpdate(wInfo *workload.Info) {
 			equality.Semantic.DeepEqual(apimeta.FindStatusCondition(oldInfo.Obj.Status.Conditions, kueue.WorkloadRequeued),
 				apimeta.FindStatusCondition(wInfo.Obj.Status.Conditions, kueue.WorkloadRequeued)) {
 			c.inadmissibleWorkloads[key] = wInfo
+			c.trackPending(wInfo)
 			return
 		}
 		// otherwise move or update in place in the queue.
@@ -165,9 +188,11 @@ func (c *ClusterQueue) PushOrUpdate(wInfo *workload.Info) {
 	}
 	if c.heap.GetByKey(key) == nil && !c.backoffWaitingTimeExpired(wInfo) {
 		c.inadmissibleWorkloads[key] = wInfo
+		c.trackPending(wInfo)
 		return
 	}
 	c.heap.PushOrUpdate(wInfo)
+	c.trackPending(wInfo)
 }
 
 // backoffWaitingTimeExpired returns true if the current time is after the requeueAt
@@ -201,6 +226,7 @@ func (c *ClusterQueue) delete(w *kueue.Workload) {
 	delete(c.inadmissibleWorkloads, key)
 	c.heap.Delete(key)
 	c.forgetInflightByKey(key)
+	c.untrackPending(key)
 }
 
 // DeleteFromLocalQueue removes all workloads belonging to this queue from
@@ -237,7 +263,11 @@ func (c *ClusterQueue) requeueIfNotPresent(wInfo *workload.Info, immediate bool)
 			wInfo = inadmissibleWl
 			delete(c.inadmissibleWorkloads, key)
 		}
-		return c.heap.PushIfNotPresent(wInfo)
+		if !c.heap.PushIfNotPresent(wInfo) {
+			return false
+		}
+		c.trackPending(wInfo)
+		return true
 	}
 
 	if c.inadmissibleWorkloads[key] != nil {
@@ -249,10 +279,34 @@ func (c *ClusterQueue) requeueIfNotPresent(wInfo *workload.Info, immediate bool)
 	}
 
 	c.inadmissibleWorkloads[key] = wInfo
+	c.trackPending(wInfo)
 
 	return true
 }
 
+// trackPending records the priority bucket of a workload that entered the
+// queue, or updates it if the workload was already pending.
+func (c *ClusterQueue) trackPending(wInfo *workload.Info) {
+	key := workload.Key(wInfo.Obj)
+	bucket := metrics.PriorityBucket(utilpriority.Priority(wInfo.Obj))
+	if old, found := c.pendingBuckets[key]; found {
+		if old == bucket {
+			return
+		}
+		c.pendingByBucket[old]--
+	}
+	c.pendingBuckets[key] = bucket
+	c.pendingByBucket[bucket]++
+}
+
+// untrackPending forgets a workload that left the queue.
+func (c *ClusterQueue) untrackPending(key string) {
+	if bucket, found := c.pendingBuckets[key]; found {
+		delete(c.pendingBuckets, key)
+		c.pendingByBucket[bucket]--
+	}
+}
+
 func (c *ClusterQueue) forgetInflightByKey(key string) {
 	if c.inflight != nil && workload.Key(c.inflight.Obj) == key {
 		c.inflight = nil
@@ -309,12 +363,33 @@ func (c *ClusterQueue) PendingInadmissible() int {
 	return len(c.inadmissibleWorkloads)
 }
 
+// PendingByPriorityBucket returns the number of pending workloads, both
+// active and inadmissible, per priority bucket.
+func (c *ClusterQueue) PendingByPriorityBucket() map[string]int {
+	c.rwm.RLock()
+	defer c.rwm.RUnlock()
+	counts := make(map[string]int, len(c.pendingByBucket))
+	for bucket, count := range c.pendingByBucket {
+		if count > 0 {
+			counts[bucket] = count
+		}
+	}
+	return counts
+}
+
 // Pop removes the head of the queue and returns it. It returns nil if the
 // queue is empty.
 func (c *ClusterQueue) Pop() *workload.Info {
 	c.rwm.Lock()
 	defer c.rwm.Unlock()
 	c.popCycle++
+	if c.inflight != nil {
+		// The previous head wasn't requeued, so it's no longer pending.
+		key := workload.Key(c.inflight.Obj)
+		if c.heap.GetByKey(key) == nil && c.inadmissibleWorkloads[key] == nil {
+			c.untrackPending(key)
+		}
+	}
 	if c.heap.Len() == 0 {
 		c.inflight = nil
 		return nil
@@ -339,6 +414,18 @@ func (c *ClusterQueue) Dump() ([]string, bool) {
 	return elements, true
 }
 
+// DumpOrdered returns the workloads in the heap of this ClusterQueue, in
+// the order in which the scheduler pops them.
+func (c *ClusterQueue) DumpOrdered() []*workload.Info {
+	c.rwm.RLock()
+	defer c.rwm.RUnlock()
+	elements := c.heap.List()
+	sort.Slice(elements, func(i, j int) bool {
+		return c.lessFunc(elements[i], elements[j])
+	})
+	return elements
+}
+
 func (c *ClusterQueue) DumpInadmissible() ([]string, bool) {
 	c.rwm.RLock()
 	defer c.rwm.RUnlock()
@@ -362,6 +449,24 @@ func (c *ClusterQueue) Snapshot() []*workload.Info {
 	return elements
 }
 
+// Head returns the first pending workload in the order in which the
+// scheduler pops them, including the inadmissible workloads and the one
+// being scheduled, or nil if there is none.
+func (c *ClusterQueue) Head() *workload.Info {
+	c.rwm.RLock()
+	defer c.rwm.RUnlock()
+	head := c.heap.Peek()
+	for _, wInfo := range c.inadmissibleWorkloads {
+		if head == nil || c.lessFunc(wInfo, head) {
+			head = wInfo
+		}
+	}
+	if c.inflight != nil && (head == nil || c.lessFunc(c.inflight, head)) {
+		head = c.inflight
+	}
+	return head
+}
+
 // Info returns workload.Info for the workload key.
 // Users of this method should not modify the returned object.
 func (c *ClusterQueue) Info(key string) *workload.Info {
@@ -423,6 +528,19 @@ func queueOrderingFunc(wo workload.Ordering) func(a, b *workload.Info) bool {
 
 		tA := wo.GetQueueOrderTimestamp(a.Obj)
 		tB := wo.GetQueueOrderTimestamp(b.Obj)
-		return !tB.Before(tA)
+		if !tA.Equal(tB) {
+			return tA.Before(tB)
+		}
+
+		switch wo.SizeOrdering {
+		case kueue.WorkloadSizeOrderingSmallerFirst, kueue.WorkloadSizeOrderingLargerFirst:
+			sA := a.PodCount()
+			sB := b.PodCount()
+			if sA != sB {
+				return (sA < sB) == (wo.SizeOrdering == kueue.WorkloadSizeOrderingSmallerFirst)
+			}
+			return workload.Key(a.Obj) < workload.Key(b.Obj)
+		}
+		return true
 	}
 }
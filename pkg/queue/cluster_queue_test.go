@@ -200,6 +200,102 @@ func Test_Pop(t *testing.T) {
 	}
 }
 
+func Test_Head(t *testing.T) {
+	now := time.Now()
+	cq := newClusterQueueImpl(defaultOrdering, testingclock.NewFakeClock(now))
+	wl1 := workload.NewInfo(utiltesting.MakeWorkload("workload-1", defaultNamespace).Creation(now).Obj())
+	wl2 := workload.NewInfo(utiltesting.MakeWorkload("workload-2", defaultNamespace).Creation(now.Add(time.Second)).Obj())
+	wl3 := workload.NewInfo(utiltesting.MakeWorkload("workload-3", defaultNamespace).Creation(now.Add(2 * time.Second)).Obj())
+	if cq.Head() != nil {
+		t.Error("ClusterQueue should be empty")
+	}
+	cq.PushOrUpdate(wl3)
+	cq.PushOrUpdate(wl2)
+	if head := cq.Head(); head == nil || head.Obj.Name != "workload-2" {
+		t.Errorf("Unexpected head %v, want workload-2", head)
+	}
+	cq.inadmissibleWorkloads[workload.Key(wl1.Obj)] = wl1
+	if head := cq.Head(); head == nil || head.Obj.Name != "workload-1" {
+		t.Errorf("Unexpected head %v, want the inadmissible workload-1", head)
+	}
+	if cq.Pending() != 3 {
+		t.Errorf("Head shouldn't remove workloads, got %d pending, want 3", cq.Pending())
+	}
+}
+
+func TestPendingByPriorityBucket(t *testing.T) {
+	now := time.Now()
+	cq := newClusterQueueImpl(defaultOrdering, testingclock.NewFakeClock(now))
+	wlA := utiltesting.MakeWorkload("a", defaultNamespace).Priority(0).Creation(now).Obj()
+	wlB := utiltesting.MakeWorkload("b", defaultNamespace).Priority(5).Creation(now).Obj()
+	wlC := utiltesting.MakeWorkload("c", defaultNamespace).Priority(1_500).Creation(now).Obj()
+
+	steps := []struct {
+		name string
+		do   func()
+		want map[string]int
+	}{
+		{
+			name: "push",
+			do: func() {
+				cq.PushOrUpdate(workload.NewInfo(wlA))
+				cq.PushOrUpdate(workload.NewInfo(wlB))
+				cq.PushOrUpdate(workload.NewInfo(wlC))
+			},
+			want: map[string]int{"0": 1, "1-9": 1, "1000-9999": 1},
+		},
+		{
+			name: "update the priority of a pending workload",
+			do: func() {
+				cq.PushOrUpdate(workload.NewInfo(utiltesting.MakeWorkload("b", defaultNamespace).Priority(2_000).Creation(now).Obj()))
+			},
+			want: map[string]int{"0": 1, "1000-9999": 2},
+		},
+		{
+			name: "the popped workload is still pending",
+			do: func() {
+				cq.Pop()
+			},
+			want: map[string]int{"0": 1, "1000-9999": 2},
+		},
+		{
+			name: "the previous head is admitted when popping the next one",
+			do: func() {
+				cq.Pop()
+			},
+			want: map[string]int{"0": 1, "1000-9999": 1},
+		},
+		{
+			name: "requeue as inadmissible",
+			do: func() {
+				cq.requeueIfNotPresent(workload.NewInfo(wlC), false)
+			},
+			want: map[string]int{"0": 1, "1000-9999": 1},
+		},
+		{
+			name: "delete",
+			do: func() {
+				cq.Delete(wlC)
+			},
+			want: map[string]int{"0": 1},
+		},
+		{
+			name: "the last head is admitted when the queue is empty",
+			do: func() {
+				cq.Pop()
+				cq.Pop()
+			},
+			want: map[string]int{},
+		},
+	}
+	for _, step := range steps {
+		step.do()
+		if diff := cmp.Diff(step.want, cq.PendingByPriorityBucket()); diff != "" {
+			t.Errorf("Unexpected pending workloads by priority after %q (-want,+got):\n%s", step.name, diff)
+		}
+	}
+}
+
 func Test_Delete(t *testing.T) {
 	cq := newClusterQueueImpl(defaultOrdering, testingclock.NewFakeClock(time.Now()))
 	wl1 := utiltesting.MakeWorkload("workload-1", defaultNamespace).Obj()
@@ -633,6 +729,52 @@ func TestBestEffortFIFORequeueIfNotPresent(t *testing.T) {
 	}
 }
 
+func TestPushOrUpdateInadmissibleWorkload(t *testing.T) {
+	wlBase := utiltesting.MakeWorkload("workload-1", defaultNamespace).Request(corev1.ResourceCPU, "2")
+	cases := map[string]struct {
+		updated          *kueue.Workload
+		wantInadmissible bool
+	}{
+		"unchanged workload stays inadmissible": {
+			updated:          wlBase.Clone().ResourceVersion("1").Obj(),
+			wantInadmissible: true,
+		},
+		"workload with changed requests is re-evaluated": {
+			updated:          wlBase.Clone().ResourceVersion("1").Request(corev1.ResourceCPU, "1").Obj(),
+			wantInadmissible: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cq, _ := newClusterQueue(
+				&kueue.ClusterQueue{
+					Spec: kueue.ClusterQueueSpec{
+						QueueingStrategy: kueue.BestEffortFIFO,
+					},
+				},
+				workload.Ordering{PodsReadyRequeuingTimestamp: config.EvictionTimestamp},
+			)
+			wl := wlBase.Clone().Obj()
+			if ok := cq.RequeueIfNotPresent(workload.NewInfo(wl), RequeueReasonNamespaceMismatch); !ok {
+				t.Fatal("failed to requeue nonexistent workload")
+			}
+			if _, found := cq.inadmissibleWorkloads[workload.Key(wl)]; !found {
+				t.Fatal("workload should be inadmissible")
+			}
+
+			cq.PushOrUpdate(workload.NewInfo(tc.updated))
+
+			_, gotInadmissible := cq.inadmissibleWorkloads[workload.Key(wl)]
+			if diff := cmp.Diff(tc.wantInadmissible, gotInadmissible); diff != "" {
+				t.Errorf("Unexpected inadmissible status (-want,+got):\n%s", diff)
+			}
+			if cq.Pending() != 1 {
+				t.Errorf("unexpected count of pending workloads (want=%d, got=%d)", 1, cq.Pending())
+			}
+		})
+	}
+}
+
 func TestFIFOClusterQueue(t *testing.T) {
 	q, err := newClusterQueue(
 		&kueue.ClusterQueue{
@@ -701,6 +843,61 @@ func TestFIFOClusterQueue(t *testing.T) {
 	}
 }
 
+func TestWorkloadSizeOrdering(t *testing.T) {
+	now := time.Now()
+	makeWorkload := func(name string, count int32) *kueue.Workload {
+		return utiltesting.MakeWorkload(name, "ns").
+			Creation(now).
+			PodSets(*utiltesting.MakePodSet("main", int(count)).Obj()).
+			Obj()
+	}
+	ws := []*kueue.Workload{
+		makeWorkload("medium", 5),
+		makeWorkload("large", 10),
+		makeWorkload("small-b", 1),
+		makeWorkload("small-a", 1),
+		utiltesting.MakeWorkload("older", "ns").
+			Creation(now.Add(-time.Second)).
+			PodSets(*utiltesting.MakePodSet("main", 20).Obj()).
+			Obj(),
+	}
+	cases := map[string]struct {
+		sizeOrdering kueue.WorkloadSizeOrdering
+		want         []string
+	}{
+		"smaller first": {
+			sizeOrdering: kueue.WorkloadSizeOrderingSmallerFirst,
+			want:         []string{"older", "small-a", "small-b", "medium", "large"},
+		},
+		"larger first": {
+			sizeOrdering: kueue.WorkloadSizeOrderingLargerFirst,
+			want:         []string{"older", "large", "medium", "small-a", "small-b"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cq, err := newClusterQueue(utiltesting.MakeClusterQueue("cq").Obj(), workload.Ordering{})
+			if err != nil {
+				t.Fatalf("Failed creating ClusterQueue %v", err)
+			}
+			for _, w := range ws {
+				cq.PushOrUpdate(workload.NewInfo(w))
+			}
+			// Setting the ordering after the workloads are queued reorders them.
+			if err := cq.Update(utiltesting.MakeClusterQueue("cq").WorkloadSizeOrdering(tc.sizeOrdering).Obj()); err != nil {
+				t.Fatalf("Failed updating ClusterQueue %v", err)
+			}
+			var got []string
+			for wl := cq.Pop(); wl != nil; wl = cq.Pop() {
+				got = append(got, wl.Obj.Name)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected order (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestStrictFIFO(t *testing.T) {
 	t1 := time.Now()
 	t2 := t1.Add(time.Second)
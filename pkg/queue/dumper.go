@@ -18,9 +18,20 @@ package queue
 
 import (
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+
+	utilpriority "sigs.k8s.io/kueue/pkg/util/priority"
+	"sigs.k8s.io/kueue/pkg/workload"
 )
 
+// DumpedWorkload is a pending workload, as listed by DumpOrdered.
+type DumpedWorkload struct {
+	Key               string      `json:"key"`
+	Priority          int32       `json:"priority"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+}
+
 // LogDump dumps the pending and inadmissible workloads for each ClusterQueue into the log,
 // one line per ClusterQueue.
 func (m *Manager) LogDump(log logr.Logger) {
@@ -56,6 +67,28 @@ func (m *Manager) Dump() map[string][]string {
 	return dump
 }
 
+// DumpOrdered returns, for each ClusterQueue, its pending workloads in the
+// order in which the scheduler considers them. The inadmissible workloads
+// are not included.
+func (m *Manager) DumpOrdered() map[string][]DumpedWorkload {
+	m.RLock()
+	defer m.RUnlock()
+	dump := make(map[string][]DumpedWorkload, len(m.clusterQueues))
+	for key, cq := range m.clusterQueues {
+		infos := cq.DumpOrdered()
+		elements := make([]DumpedWorkload, len(infos))
+		for i, info := range infos {
+			elements[i] = DumpedWorkload{
+				Key:               workload.Key(info.Obj),
+				Priority:          utilpriority.Priority(info.Obj),
+				CreationTimestamp: info.Obj.CreationTimestamp,
+			}
+		}
+		dump[key] = elements
+	}
+	return dump
+}
+
 // DumpInadmissible is a dump of the inadmissible workloads list.
 // Only use for testing purposes.
 func (m *Manager) DumpInadmissible() map[string][]string {
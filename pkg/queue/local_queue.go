@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	utillocalqueue "sigs.k8s.io/kueue/pkg/util/localqueue"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -32,6 +33,10 @@ func Key(q *kueue.LocalQueue) string {
 type LocalQueue struct {
 	Key          string
 	ClusterQueue string
+	// ClusterQueues are the clusterQueues that can serve the workloads of the
+	// queue, in order of preference, starting with ClusterQueue.
+	ClusterQueues   []string
+	SelectionPolicy kueue.ClusterQueueSelectionPolicy
 
 	items map[string]*workload.Info
 }
@@ -47,6 +52,17 @@ func newLocalQueue(q *kueue.LocalQueue) *LocalQueue {
 
 func (q *LocalQueue) update(apiQueue *kueue.LocalQueue) {
 	q.ClusterQueue = string(apiQueue.Spec.ClusterQueue)
+	q.ClusterQueues = utillocalqueue.ClusterQueueNames(apiQueue)
+	q.SelectionPolicy = ""
+	if apiQueue.Spec.ClusterQueueSelection != nil {
+		q.SelectionPolicy = apiQueue.Spec.ClusterQueueSelection.Policy
+	}
+}
+
+// multipleClusterQueues returns whether the workloads of the queue can be
+// served by more than one clusterQueue.
+func (q *LocalQueue) multipleClusterQueues() bool {
+	return len(q.ClusterQueues) > 1
 }
 
 func (q *LocalQueue) AddOrUpdate(info *workload.Info) {
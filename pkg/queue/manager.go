@@ -17,9 +17,11 @@ limitations under the License.
 package queue
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -29,9 +31,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	config "sigs.k8s.io/kueue/apis/config/v1beta1"
+	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	utilindexer "sigs.k8s.io/kueue/pkg/controller/core/indexer"
 	"sigs.k8s.io/kueue/pkg/metrics"
+	utilcohort "sigs.k8s.io/kueue/pkg/util/cohort"
+	utillocalqueue "sigs.k8s.io/kueue/pkg/util/localqueue"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -83,6 +88,9 @@ type Manager struct {
 
 	// Key is cohort's name. Value is a set of associated ClusterQueue names.
 	cohorts map[string]sets.Set[string]
+	// Key is cohort's name. Value is the name of its parent, as set through
+	// the Cohort object.
+	cohortParents map[string]string
 
 	workloadOrdering workload.Ordering
 
@@ -100,6 +108,7 @@ func NewManager(client client.Client, checker StatusChecker, opts ...Option) *Ma
 		localQueues:    make(map[string]*LocalQueue),
 		clusterQueues:  make(map[string]*ClusterQueue),
 		cohorts:        make(map[string]sets.Set[string]),
+		cohortParents:  make(map[string]string),
 		snapshotsMutex: sync.RWMutex{},
 		snapshots:      make(map[string][]kueue.ClusterQueuePendingWorkload, 0),
 		workloadOrdering: workload.Ordering{
@@ -139,11 +148,19 @@ func (m *Manager) AddClusterQueue(ctx context.Context, cq *kueue.ClusterQueue) e
 	addedWorkloads := false
 	for _, q := range queues.Items {
 		qImpl := m.localQueues[Key(&q)]
-		if qImpl != nil {
+		if qImpl != nil && !qImpl.multipleClusterQueues() {
 			added := cqImpl.AddFromLocalQueue(qImpl)
 			addedWorkloads = addedWorkloads || added
 		}
 	}
+	// The workloads of queues with multiple clusterQueues that couldn't be
+	// queued in any of them can now be queued in this one.
+	for _, qImpl := range m.localQueues {
+		if qImpl.multipleClusterQueues() && slices.Contains(qImpl.ClusterQueues, cq.Name) {
+			added := m.routeWorkloads(qImpl)
+			addedWorkloads = addedWorkloads || added
+		}
+	}
 
 	queued := m.queueAllInadmissibleWorkloadsInCohort(ctx, cqImpl)
 	m.reportPendingWorkloads(cq.Name, cqImpl)
@@ -193,6 +210,14 @@ func (m *Manager) DeleteClusterQueue(cq *kueue.ClusterQueue) {
 
 	cohort := cq.Spec.Cohort
 	m.deleteCohort(cohort, cq.Name)
+
+	// Move the workloads of queues with multiple clusterQueues to the
+	// remaining clusterQueues.
+	for _, qImpl := range m.localQueues {
+		if qImpl.multipleClusterQueues() && slices.Contains(qImpl.ClusterQueues, cq.Name) && m.routeWorkloads(qImpl) {
+			m.Broadcast()
+		}
+	}
 }
 
 func (m *Manager) AddLocalQueue(ctx context.Context, q *kueue.LocalQueue) error {
@@ -218,6 +243,12 @@ func (m *Manager) AddLocalQueue(ctx context.Context, q *kueue.LocalQueue) error
 		workload.AdjustResources(ctx, m.client, &w)
 		qImpl.AddOrUpdate(workload.NewInfo(&w, m.workloadInfoOptions...))
 	}
+	if qImpl.multipleClusterQueues() {
+		if m.routeWorkloads(qImpl) {
+			m.Broadcast()
+		}
+		return nil
+	}
 	cq := m.clusterQueues[qImpl.ClusterQueue]
 	if cq != nil && cq.AddFromLocalQueue(qImpl) {
 		m.Broadcast()
@@ -232,15 +263,27 @@ func (m *Manager) UpdateLocalQueue(q *kueue.LocalQueue) error {
 	if !ok {
 		return ErrQueueDoesNotExist
 	}
-	if qImpl.ClusterQueue != string(q.Spec.ClusterQueue) {
-		oldCQ := m.clusterQueues[qImpl.ClusterQueue]
-		if oldCQ != nil {
-			oldCQ.DeleteFromLocalQueue(qImpl)
+	if !slices.Equal(qImpl.ClusterQueues, utillocalqueue.ClusterQueueNames(q)) {
+		for _, cqName := range qImpl.ClusterQueues {
+			if oldCQ := m.clusterQueues[cqName]; oldCQ != nil {
+				oldCQ.DeleteFromLocalQueue(qImpl)
+			}
 		}
-		newCQ := m.clusterQueues[string(q.Spec.ClusterQueue)]
+		qImpl.update(q)
+		if qImpl.multipleClusterQueues() {
+			for _, info := range qImpl.items {
+				info.ClusterQueue = ""
+			}
+			if m.routeWorkloads(qImpl) {
+				m.Broadcast()
+			}
+			return nil
+		}
+		newCQ := m.clusterQueues[qImpl.ClusterQueue]
 		if newCQ != nil && newCQ.AddFromLocalQueue(qImpl) {
 			m.Broadcast()
 		}
+		return nil
 	}
 	qImpl.update(q)
 	return nil
@@ -254,9 +297,10 @@ func (m *Manager) DeleteLocalQueue(q *kueue.LocalQueue) {
 	if qImpl == nil {
 		return
 	}
-	cq := m.clusterQueues[qImpl.ClusterQueue]
-	if cq != nil {
-		cq.DeleteFromLocalQueue(qImpl)
+	for _, cqName := range qImpl.ClusterQueues {
+		if cq := m.clusterQueues[cqName]; cq != nil {
+			cq.DeleteFromLocalQueue(qImpl)
+		}
 	}
 	delete(m.localQueues, key)
 }
@@ -302,8 +346,101 @@ func (m *Manager) ClusterQueueForWorkload(wl *kueue.Workload) (string, bool) {
 	if !ok {
 		return "", false
 	}
-	_, ok = m.clusterQueues[q.ClusterQueue]
-	return q.ClusterQueue, ok
+	cqName := m.clusterQueueForWorkload(q, wl)
+	_, ok = m.clusterQueues[cqName]
+	return cqName, ok
+}
+
+// clusterQueueForWorkload returns the name of the clusterQueue of the workload
+// among the clusterQueues of the queue: the clusterQueue in which the workload
+// is admitted or queued, or the clusterQueue of the queue otherwise.
+func (m *Manager) clusterQueueForWorkload(q *LocalQueue, wl *kueue.Workload) string {
+	if !q.multipleClusterQueues() {
+		return q.ClusterQueue
+	}
+	if wl.Status.Admission != nil && slices.Contains(q.ClusterQueues, string(wl.Status.Admission.ClusterQueue)) {
+		return string(wl.Status.Admission.ClusterQueue)
+	}
+	if info := q.items[workload.Key(wl)]; info != nil && info.ClusterQueue != "" {
+		return info.ClusterQueue
+	}
+	return q.ClusterQueue
+}
+
+// selectClusterQueue selects the clusterQueue for the workload among the
+// active clusterQueues of the queue, according to its selection policy.
+// Returns an empty string if none of them can be selected.
+func (m *Manager) selectClusterQueue(q *LocalQueue, info *workload.Info) string {
+	checker, ok := m.statusChecker.(CapacityChecker)
+	if !ok {
+		return ""
+	}
+	var candidates []string
+	for _, cqName := range q.ClusterQueues {
+		if _, exists := m.clusterQueues[cqName]; exists && m.statusChecker.ClusterQueueActive(cqName) {
+			candidates = append(candidates, cqName)
+		}
+	}
+	switch q.SelectionPolicy {
+	case kueue.ClusterQueueSelectionLeastLoaded:
+		selected := ""
+		var minLoad float64
+		for _, cqName := range candidates {
+			if !checker.ClusterQueueHasCapacity(cqName, info) {
+				continue
+			}
+			if load := checker.ClusterQueueLoad(cqName); selected == "" || load < minLoad {
+				selected, minLoad = cqName, load
+			}
+		}
+		return selected
+	default:
+		for _, cqName := range candidates {
+			if checker.ClusterQueueHasCapacity(cqName, info) {
+				return cqName
+			}
+		}
+	}
+	return ""
+}
+
+// pushToSelectedClusterQueue pushes the workload of a queue with multiple
+// clusterQueues to the clusterQueue selected for it, or to the clusterQueue
+// of the queue if none can be selected.
+// Returns whether the workload was pushed.
+func (m *Manager) pushToSelectedClusterQueue(q *LocalQueue, info *workload.Info) bool {
+	cqName := cmp.Or(m.selectClusterQueue(q, info), q.ClusterQueue)
+	cq := m.clusterQueues[cqName]
+	if cq == nil {
+		info.ClusterQueue = ""
+		return false
+	}
+	info.ClusterQueue = cqName
+	cq.PushOrUpdate(info)
+	m.reportPendingWorkloads(cqName, cq)
+	return true
+}
+
+// routeWorkloads pushes the workloads of a queue with multiple clusterQueues
+// that aren't queued in any existing clusterQueue to the clusterQueues
+// selected for them. Returns whether any workload was pushed.
+func (m *Manager) routeWorkloads(q *LocalQueue) bool {
+	pushed := false
+	for _, info := range q.items {
+		if _, queued := m.clusterQueues[info.ClusterQueue]; queued {
+			continue
+		}
+		if m.pushToSelectedClusterQueue(q, info) {
+			pushed = true
+		}
+	}
+	return pushed
+}
+
+// NewWorkloadInfo returns the Info of the workload, as it would be queued,
+// without adding it to the queues.
+func (m *Manager) NewWorkloadInfo(wl *kueue.Workload) *workload.Info {
+	return workload.NewInfo(wl, m.workloadInfoOptions...)
 }
 
 // AddOrUpdateWorkload adds or updates workload to the corresponding queue.
@@ -321,13 +458,30 @@ func (m *Manager) AddOrUpdateWorkloadWithoutLock(w *kueue.Workload) bool {
 		return false
 	}
 	wInfo := workload.NewInfo(w, m.workloadInfoOptions...)
-	q.AddOrUpdate(wInfo)
-	cq := m.clusterQueues[q.ClusterQueue]
+	cqName := q.ClusterQueue
+	if q.multipleClusterQueues() {
+		// The workload stays in the clusterQueue where it was queued.
+		if oldInfo := q.items[workload.Key(w)]; oldInfo != nil {
+			wInfo.ClusterQueue = oldInfo.ClusterQueue
+		}
+		q.AddOrUpdate(wInfo)
+		if _, queued := m.clusterQueues[wInfo.ClusterQueue]; !queued {
+			if !m.pushToSelectedClusterQueue(q, wInfo) {
+				return false
+			}
+			m.Broadcast()
+			return true
+		}
+		cqName = wInfo.ClusterQueue
+	} else {
+		q.AddOrUpdate(wInfo)
+	}
+	cq := m.clusterQueues[cqName]
 	if cq == nil {
 		return false
 	}
 	cq.PushOrUpdate(wInfo)
-	m.reportPendingWorkloads(q.ClusterQueue, cq)
+	m.reportPendingWorkloads(cqName, cq)
 	m.Broadcast()
 	return true
 }
@@ -353,13 +507,32 @@ func (m *Manager) RequeueWorkload(ctx context.Context, info *workload.Info, reas
 	}
 	info.Update(&w)
 	q.AddOrUpdate(info)
-	cq := m.clusterQueues[q.ClusterQueue]
+	cqName := q.ClusterQueue
+	if q.multipleClusterQueues() {
+		cqName = cmp.Or(info.ClusterQueue, q.ClusterQueue)
+		// Move the workload if another clusterQueue is selected for it, as
+		// its clusterQueue might not have capacity for it anymore.
+		if selected := m.selectClusterQueue(q, info); selected != "" && selected != cqName {
+			if oldCQ := m.clusterQueues[cqName]; oldCQ != nil {
+				oldCQ.Delete(info.Obj)
+				m.reportPendingWorkloads(cqName, oldCQ)
+			}
+			info.ClusterQueue = selected
+			cq := m.clusterQueues[selected]
+			cq.PushOrUpdate(info)
+			m.reportPendingWorkloads(selected, cq)
+			m.Broadcast()
+			return true
+		}
+		info.ClusterQueue = cqName
+	}
+	cq := m.clusterQueues[cqName]
 	if cq == nil {
 		return false
 	}
 
 	added := cq.RequeueIfNotPresent(info, reason)
-	m.reportPendingWorkloads(q.ClusterQueue, cq)
+	m.reportPendingWorkloads(cqName, cq)
 	if added {
 		m.Broadcast()
 	}
@@ -378,10 +551,11 @@ func (m *Manager) deleteWorkloadFromQueueAndClusterQueue(w *kueue.Workload, qKey
 		return
 	}
 	delete(q.items, workload.Key(w))
-	cq := m.clusterQueues[q.ClusterQueue]
-	if cq != nil {
-		cq.Delete(w)
-		m.reportPendingWorkloads(q.ClusterQueue, cq)
+	for _, cqName := range q.ClusterQueues {
+		if cq := m.clusterQueues[cqName]; cq != nil {
+			cq.Delete(w)
+			m.reportPendingWorkloads(cqName, cq)
+		}
 	}
 }
 
@@ -402,7 +576,7 @@ func (m *Manager) QueueAssociatedInadmissibleWorkloadsAfter(ctx context.Context,
 	if q == nil {
 		return
 	}
-	cq := m.clusterQueues[q.ClusterQueue]
+	cq := m.clusterQueues[m.clusterQueueForWorkload(q, w)]
 	if cq == nil {
 		return
 	}
@@ -454,15 +628,44 @@ func (m *Manager) queueAllInadmissibleWorkloadsInCohort(ctx context.Context, cq
 		return cq.QueueInadmissibleWorkloads(ctx, m.client)
 	}
 
+	// The ClusterQueues in the whole hierarchy of the cohort share quota.
+	root := m.rootCohort(cohort)
 	queued := false
-	for cqName := range m.cohorts[cohort] {
-		if clusterQueue, ok := m.clusterQueues[cqName]; ok {
-			queued = clusterQueue.QueueInadmissibleWorkloads(ctx, m.client) || queued
+	for name, cqNames := range m.cohorts {
+		if name != cohort && m.rootCohort(name) != root {
+			continue
+		}
+		for cqName := range cqNames {
+			if clusterQueue, ok := m.clusterQueues[cqName]; ok {
+				queued = clusterQueue.QueueInadmissibleWorkloads(ctx, m.client) || queued
+			}
 		}
 	}
 	return queued
 }
 
+// AddOrUpdateCohort stores the parent of the cohort.
+func (m *Manager) AddOrUpdateCohort(cohort *kueuealpha.Cohort) {
+	m.Lock()
+	defer m.Unlock()
+	if cohort.Spec.Parent == "" {
+		delete(m.cohortParents, cohort.Name)
+		return
+	}
+	m.cohortParents[cohort.Name] = cohort.Spec.Parent
+}
+
+// DeleteCohort drops the parent of the cohort.
+func (m *Manager) DeleteCohort(cohort *kueuealpha.Cohort) {
+	m.Lock()
+	defer m.Unlock()
+	delete(m.cohortParents, cohort.Name)
+}
+
+func (m *Manager) rootCohort(name string) string {
+	return utilcohort.Root(name, func(n string) string { return m.cohortParents[n] })
+}
+
 // UpdateWorkload updates the workload to the corresponding queue or adds it if
 // it didn't exist. Returns whether the queue existed.
 func (m *Manager) UpdateWorkload(oldW, w *kueue.Workload) bool {
@@ -560,6 +763,7 @@ func (m *Manager) reportPendingWorkloads(cqName string, cq *ClusterQueue) {
 		active = 0
 	}
 	metrics.ReportPendingWorkloads(cqName, active, inadmissible)
+	metrics.ReportPendingWorkloadsByPriority(cqName, cq.PendingByPriorityBucket())
 }
 
 func (m *Manager) GetClusterQueueNames() []string {
@@ -586,6 +790,16 @@ func (m *Manager) PendingWorkloadsInfo(cqName string) []*workload.Info {
 	return cq.Snapshot()
 }
 
+// PendingHead returns the first pending workload of the ClusterQueue, or nil
+// if there is none.
+func (m *Manager) PendingHead(cqName string) *workload.Info {
+	cq := m.getClusterQueue(cqName)
+	if cq == nil {
+		return nil
+	}
+	return cq.Head()
+}
+
 // ClusterQueueFromLocalQueue returns ClusterQueue name and whether it's found,
 // given a QueueKey(namespace/localQueueName) as the parameter
 func (m *Manager) ClusterQueueFromLocalQueue(localQueueKey string) (string, bool) {
@@ -33,7 +33,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/metrics"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	testingmetrics "sigs.k8s.io/kueue/pkg/util/testing/metrics"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -188,6 +190,54 @@ func TestUpdateClusterQueue(t *testing.T) {
 	}
 }
 
+// TestUpdateClusterQueueInCohortHierarchy tests that updating a ClusterQueue
+// makes active the inadmissible workloads in the whole hierarchy of its cohort.
+func TestUpdateClusterQueueInCohortHierarchy(t *testing.T) {
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("cq1").Cohort("team").Obj(),
+		utiltesting.MakeClusterQueue("cq2").Cohort("org").Obj(),
+		utiltesting.MakeClusterQueue("cq3").Cohort("other").Obj(),
+	}
+	ctx := context.Background()
+	cl := utiltesting.NewFakeClient(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: defaultNamespace}},
+	)
+	manager := NewManager(cl, nil)
+	manager.AddOrUpdateCohort(utiltesting.MakeCohort("team").Parent("org").Obj())
+	for _, cq := range clusterQueues {
+		if err := manager.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Failed adding clusterQueue %s: %v", cq.Name, err)
+		}
+		q := utiltesting.MakeLocalQueue(cq.Name, defaultNamespace).ClusterQueue(cq.Name).Obj()
+		if err := manager.AddLocalQueue(ctx, q); err != nil {
+			t.Fatalf("Failed adding queue %s: %v", q.Name, err)
+		}
+		w := utiltesting.MakeWorkload("wl-"+cq.Name, defaultNamespace).Queue(q.Name).Obj()
+		if err := cl.Create(ctx, w); err != nil {
+			t.Fatalf("Failed adding workload to client: %v", err)
+		}
+		manager.AddOrUpdateWorkload(w)
+	}
+	// Make the workloads inadmissible after a scheduling attempt.
+	for _, head := range manager.Heads(ctx) {
+		manager.RequeueWorkload(ctx, &head, RequeueReasonGeneric)
+	}
+	if active := manager.Dump(); len(active) != 0 {
+		t.Fatalf("Unexpected active workloads before the update: %v", active)
+	}
+
+	if err := manager.UpdateClusterQueue(ctx, clusterQueues[0], true); err != nil {
+		t.Fatalf("Failed to update ClusterQueue: %v", err)
+	}
+	wantActiveWorkloads := map[string][]string{
+		"cq1": {"default/wl-cq1"},
+		"cq2": {"default/wl-cq2"},
+	}
+	if diff := cmp.Diff(wantActiveWorkloads, manager.Dump()); diff != "" {
+		t.Errorf("Unexpected active workloads (-want +got):\n%s", diff)
+	}
+}
+
 // TestClusterQueueToActive tests that managers cond gets a broadcast when
 // a cluster queue becomes active.
 func TestClusterQueueToActive(t *testing.T) {
@@ -334,6 +384,89 @@ func TestDeleteLocalQueue(t *testing.T) {
 	}
 }
 
+func TestPendingWorkloadsByPriorityMetric(t *testing.T) {
+	manager := NewManager(utiltesting.NewFakeClient(), nil)
+	cq := utiltesting.MakeClusterQueue("priority-cq").Obj()
+	if err := manager.AddClusterQueue(context.Background(), cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue %s: %v", cq.Name, err)
+	}
+	q := utiltesting.MakeLocalQueue("foo", "earth").ClusterQueue("priority-cq").Obj()
+	if err := manager.AddLocalQueue(context.Background(), q); err != nil {
+		t.Fatalf("Failed adding queue %s: %v", q.Name, err)
+	}
+	defer metrics.ClearQueueSystemMetrics("priority-cq")
+
+	for name, priority := range map[string]int32{
+		"negative": -5,
+		"zero":     0,
+		"low-a":    3,
+		"low-b":    7,
+		"high":     1_500,
+		"critical": 2_000_000_000,
+	} {
+		wl := utiltesting.MakeWorkload(name, "earth").Queue("foo").Priority(priority).Obj()
+		if !manager.AddOrUpdateWorkload(wl) {
+			t.Fatalf("Failed adding workload %s", name)
+		}
+	}
+
+	got := make(map[string]float64)
+	for _, p := range testingmetrics.CollectFilteredGaugeVec(metrics.PendingWorkloadsByPriority, map[string]string{"cluster_queue": "priority-cq"}) {
+		if p.Value != 0 {
+			got[p.Labels["priority_bucket"]] = p.Value
+		}
+	}
+	want := map[string]float64{
+		"<0":        1,
+		"0":         1,
+		"1-9":       2,
+		"1000-9999": 1,
+		"1000000+":  1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected pending workloads by priority (-want,+got):\n%s", diff)
+	}
+}
+
+func TestDumpOrdered(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	manager := NewManager(utiltesting.NewFakeClient(), nil)
+	ctx := context.Background()
+	for _, cq := range []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("cq").Obj(),
+		utiltesting.MakeClusterQueue("empty-cq").Obj(),
+	} {
+		if err := manager.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Failed adding clusterQueue %s: %v", cq.Name, err)
+		}
+	}
+	q := utiltesting.MakeLocalQueue("foo", "earth").ClusterQueue("cq").Obj()
+	if err := manager.AddLocalQueue(ctx, q); err != nil {
+		t.Fatalf("Failed adding queue %s: %v", q.Name, err)
+	}
+	for _, wl := range []*kueue.Workload{
+		utiltesting.MakeWorkload("low-old", "earth").Queue("foo").Creation(now.Add(-time.Minute)).Obj(),
+		utiltesting.MakeWorkload("high", "earth").Queue("foo").Priority(10).Creation(now).Obj(),
+		utiltesting.MakeWorkload("low-new", "earth").Queue("foo").Creation(now).Obj(),
+	} {
+		if !manager.AddOrUpdateWorkload(wl) {
+			t.Fatalf("Failed adding workload %s", wl.Name)
+		}
+	}
+
+	want := map[string][]DumpedWorkload{
+		"cq": {
+			{Key: "earth/high", Priority: 10, CreationTimestamp: metav1.NewTime(now)},
+			{Key: "earth/low-old", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+			{Key: "earth/low-new", CreationTimestamp: metav1.NewTime(now)},
+		},
+		"empty-cq": {},
+	}
+	if diff := cmp.Diff(want, manager.DumpOrdered()); diff != "" {
+		t.Errorf("Unexpected ordered dump (-want,+got):\n%s", diff)
+	}
+}
+
 func TestAddWorkload(t *testing.T) {
 	manager := NewManager(utiltesting.NewFakeClient(), nil)
 	cq := utiltesting.MakeClusterQueue("cq").Obj()
@@ -1118,6 +1251,125 @@ func (c *fakeStatusChecker) ClusterQueueActive(name string) bool {
 	return strings.Contains(name, "active-")
 }
 
+type fakeCapacityChecker struct {
+	full sets.Set[string]
+	load map[string]float64
+}
+
+func (c *fakeCapacityChecker) ClusterQueueActive(string) bool {
+	return true
+}
+
+func (c *fakeCapacityChecker) ClusterQueueHasCapacity(name string, _ *workload.Info) bool {
+	return !c.full.Has(name)
+}
+
+func (c *fakeCapacityChecker) ClusterQueueLoad(name string) float64 {
+	return c.load[name]
+}
+
+func TestMultipleClusterQueues(t *testing.T) {
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("cq-a").Obj(),
+		utiltesting.MakeClusterQueue("cq-b").Obj(),
+		utiltesting.MakeClusterQueue("cq-c").Obj(),
+	}
+	cases := map[string]struct {
+		policy         kueue.ClusterQueueSelectionPolicy
+		full           sets.Set[string]
+		load           map[string]float64
+		wantCQ         string
+		saturateOnPop  bool
+		wantRequeuedCQ string
+	}{
+		"first clusterQueue with capacity is the clusterQueue of the queue": {
+			policy: kueue.ClusterQueueSelectionFirstWithCapacity,
+			wantCQ: "cq-a",
+		},
+		"first clusterQueue with capacity is a candidate": {
+			policy: kueue.ClusterQueueSelectionFirstWithCapacity,
+			full:   sets.New("cq-a"),
+			wantCQ: "cq-b",
+		},
+		"no clusterQueue with capacity": {
+			policy: kueue.ClusterQueueSelectionFirstWithCapacity,
+			full:   sets.New("cq-a", "cq-b", "cq-c"),
+			wantCQ: "cq-a",
+		},
+		"least loaded clusterQueue": {
+			policy: kueue.ClusterQueueSelectionLeastLoaded,
+			load:   map[string]float64{"cq-a": 0.5, "cq-b": 0.8, "cq-c": 0.2},
+			wantCQ: "cq-c",
+		},
+		"least loaded clusterQueue in which the workload fits": {
+			policy: kueue.ClusterQueueSelectionLeastLoaded,
+			full:   sets.New("cq-c"),
+			load:   map[string]float64{"cq-a": 0.5, "cq-b": 0.8, "cq-c": 0.2},
+			wantCQ: "cq-a",
+		},
+		"switch when the clusterQueue saturates": {
+			policy:         kueue.ClusterQueueSelectionFirstWithCapacity,
+			wantCQ:         "cq-a",
+			saturateOnPop:  true,
+			wantRequeuedCQ: "cq-b",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), headsTimeout)
+			defer cancel()
+			wl := utiltesting.MakeWorkload("wl", "").Queue("foo").Request(corev1.ResourceCPU, "1").Obj()
+			cl := utiltesting.NewFakeClient(wl)
+			checker := &fakeCapacityChecker{full: tc.full, load: tc.load}
+			manager := NewManager(cl, checker)
+			for _, cq := range clusterQueues {
+				if err := manager.AddClusterQueue(ctx, cq); err != nil {
+					t.Fatalf("Failed adding clusterQueue %s: %v", cq.Name, err)
+				}
+			}
+			q := utiltesting.MakeLocalQueue("foo", "").
+				ClusterQueue("cq-a").
+				ClusterQueueSelection(tc.policy, "cq-b", "cq-c").
+				Obj()
+			if err := manager.AddLocalQueue(ctx, q); err != nil {
+				t.Fatalf("Failed adding queue: %v", err)
+			}
+
+			if gotCQ, _ := manager.ClusterQueueForWorkload(wl); gotCQ != tc.wantCQ {
+				t.Errorf("Unexpected clusterQueue, want %s, got %s", tc.wantCQ, gotCQ)
+			}
+			for _, cq := range clusterQueues {
+				wantPending := 0
+				if cq.Name == tc.wantCQ {
+					wantPending = 1
+				}
+				if gotPending, _ := manager.Pending(cq); gotPending != wantPending {
+					t.Errorf("Unexpected pending workloads in %s, want %d, got %d", cq.Name, wantPending, gotPending)
+				}
+			}
+			if !tc.saturateOnPop {
+				return
+			}
+
+			go manager.CleanUpOnContext(ctx)
+			heads := manager.Heads(ctx)
+			if len(heads) != 1 || heads[0].ClusterQueue != tc.wantCQ {
+				t.Fatalf("Unexpected heads %v", heads)
+			}
+			checker.full = sets.New(tc.wantCQ)
+			if !manager.RequeueWorkload(ctx, &heads[0], RequeueReasonGeneric) {
+				t.Error("RequeueWorkload returned false, want true")
+			}
+			if gotCQ, _ := manager.ClusterQueueForWorkload(wl); gotCQ != tc.wantRequeuedCQ {
+				t.Errorf("Unexpected clusterQueue after requeue, want %s, got %s", tc.wantRequeuedCQ, gotCQ)
+			}
+			if gotPending, _ := manager.Pending(clusterQueues[0]); gotPending != 0 {
+				t.Errorf("Unexpected pending workloads in %s after requeue, want 0, got %d", clusterQueues[0].Name, gotPending)
+			}
+		})
+	}
+}
+
 func TestGetPendingWorkloadsInfo(t *testing.T) {
 	now := time.Now().Truncate(time.Second)
 
@@ -16,8 +16,21 @@ limitations under the License.
 
 package queue
 
+import "sigs.k8s.io/kueue/pkg/workload"
+
 // StatusChecker checks status of clusterQueue.
 type StatusChecker interface {
 	// ClusterQueueActive returns whether the clusterQueue is active.
 	ClusterQueueActive(name string) bool
 }
+
+// CapacityChecker checks the capacity of clusterQueues. It's used to select the
+// clusterQueue of the workloads of localQueues with multiple clusterQueues.
+type CapacityChecker interface {
+	// ClusterQueueHasCapacity returns whether the workload fits in the quota
+	// that the clusterQueue can still reserve.
+	ClusterQueueHasCapacity(name string, wl *workload.Info) bool
+	// ClusterQueueLoad returns the fraction of the nominal quota of the
+	// clusterQueue in use.
+	ClusterQueueLoad(name string) float64
+}
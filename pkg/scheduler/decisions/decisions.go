@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package decisions provides an extension point to publish the admission
+// decisions taken by the scheduler to a coordination backend, so that they
+// can be aggregated across clusters.
+package decisions
+
+import (
+	"context"
+	"time"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// Decision is the admission of a workload into a ClusterQueue.
+type Decision struct {
+	// Namespace is the namespace of the admitted workload.
+	Namespace string `json:"namespace"`
+	// Name is the name of the admitted workload.
+	Name string `json:"name"`
+	// ClusterQueue is the ClusterQueue the workload was admitted into.
+	ClusterQueue kueue.ClusterQueueReference `json:"clusterQueue"`
+	// PodSetAssignments are the flavors assigned to the podSets of the workload.
+	PodSetAssignments []kueue.PodSetAssignment `json:"podSetAssignments,omitempty"`
+	// Time is when the admission was decided.
+	Time time.Time `json:"time"`
+}
+
+// Publisher publishes admission decisions.
+type Publisher interface {
+	// Publish records d as the latest decision taken for its ClusterQueue.
+	Publish(ctx context.Context, d Decision) error
+}
+
+// Store is a Publisher whose decisions can be observed.
+type Store interface {
+	Publisher
+	// Observe returns the latest decision published for every ClusterQueue,
+	// sorted by ClusterQueue name.
+	Observe(ctx context.Context) ([]Decision, error)
+}
+
+// NoopPublisher discards all the decisions. It is used when publishing
+// is not enabled.
+type NoopPublisher struct{}
+
+var _ Publisher = NoopPublisher{}
+
+func (NoopPublisher) Publish(context.Context, Decision) error {
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decisions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+var now = time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+func makeDecision(cq, name string, t time.Time) Decision {
+	return Decision{
+		Namespace:         "default",
+		Name:              name,
+		ClusterQueue:      kueue.ClusterQueueReference(cq),
+		PodSetAssignments: utiltesting.MakeAdmission(cq).Assignment(corev1.ResourceCPU, "default", "1").Obj().PodSetAssignments,
+		Time:              t,
+	}
+}
+
+func TestStores(t *testing.T) {
+	cases := map[string]struct {
+		publish []Decision
+		want    []Decision
+	}{
+		"nothing published": {
+			want: []Decision{},
+		},
+		"decisions of different ClusterQueues": {
+			publish: []Decision{
+				makeDecision("cq-b", "wl1", now),
+				makeDecision("cq-a", "wl2", now),
+			},
+			want: []Decision{
+				makeDecision("cq-a", "wl2", now),
+				makeDecision("cq-b", "wl1", now),
+			},
+		},
+		"latest decision of a ClusterQueue wins": {
+			publish: []Decision{
+				makeDecision("cq", "wl1", now),
+				makeDecision("cq", "wl2", now.Add(time.Second)),
+			},
+			want: []Decision{
+				makeDecision("cq", "wl2", now.Add(time.Second)),
+			},
+		},
+	}
+	stores := map[string]func() Store{
+		"memory": func() Store {
+			return NewMemoryStore()
+		},
+		"lease": func() Store {
+			cl := utiltesting.NewClientBuilder().Build()
+			return NewLeaseStore(cl, cl, "kueue-system")
+		},
+	}
+	for storeName, newStore := range stores {
+		for name, tc := range cases {
+			t.Run(storeName+"/"+name, func(t *testing.T) {
+				ctx, _ := utiltesting.ContextWithLog(t)
+				store := newStore()
+				for _, d := range tc.publish {
+					if err := store.Publish(ctx, d); err != nil {
+						t.Fatalf("Failed to publish decision: %v", err)
+					}
+				}
+				got, err := store.Observe(ctx)
+				if err != nil {
+					t.Fatalf("Failed to observe decisions: %v", err)
+				}
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("Unexpected decisions (-want,+got):\n%s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestLeaseStore(t *testing.T) {
+	cases := map[string]struct {
+		leases        []coordinationv1.Lease
+		publish       Decision
+		wantErr       bool
+		wantRenewTime *metav1.MicroTime
+		wantObserved  []Decision
+	}{
+		"creates the lease": {
+			publish:       makeDecision("cq", "wl", now),
+			wantRenewTime: &metav1.MicroTime{Time: now},
+			wantObserved:  []Decision{makeDecision("cq", "wl", now)},
+		},
+		"updates the managed lease": {
+			leases: []coordinationv1.Lease{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cq",
+					Namespace: "kueue-system",
+					Labels:    map[string]string{ManagedLabel: "true"},
+				},
+			}},
+			publish:       makeDecision("cq", "wl", now),
+			wantRenewTime: &metav1.MicroTime{Time: now},
+			wantObserved:  []Decision{makeDecision("cq", "wl", now)},
+		},
+		"doesn't take over an unmanaged lease": {
+			leases: []coordinationv1.Lease{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cq",
+					Namespace: "kueue-system",
+				},
+			}},
+			publish:      makeDecision("cq", "wl", now),
+			wantErr:      true,
+			wantObserved: []Decision{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := utiltesting.ContextWithLog(t)
+			cl := utiltesting.NewClientBuilder().
+				WithLists(&coordinationv1.LeaseList{Items: tc.leases}).
+				Build()
+			store := NewLeaseStore(cl, cl, "kueue-system")
+			err := store.Publish(ctx, tc.publish)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Publish returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if !tc.wantErr {
+				var lease coordinationv1.Lease
+				if err := cl.Get(ctx, client.ObjectKey{Namespace: "kueue-system", Name: string(tc.publish.ClusterQueue)}, &lease); err != nil {
+					t.Fatalf("Failed to get lease: %v", err)
+				}
+				if diff := cmp.Diff(tc.wantRenewTime, lease.Spec.RenewTime); diff != "" {
+					t.Errorf("Unexpected renew time (-want,+got):\n%s", diff)
+				}
+			}
+			got, err := store.Observe(ctx)
+			if err != nil {
+				t.Fatalf("Failed to observe decisions: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantObserved, got); diff != "" {
+				t.Errorf("Unexpected decisions (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decisions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ManagedLabel marks the Leases that hold admission decisions.
+	ManagedLabel = "kueue.x-k8s.io/admission-decisions"
+
+	// DecisionAnnotation is the annotation of the Lease that holds the
+	// JSON encoded latest decision for the ClusterQueue.
+	DecisionAnnotation = "kueue.x-k8s.io/admission-decision"
+)
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
+
+// LeaseStore is a Store that keeps the latest decision of every ClusterQueue
+// in a Lease named after the ClusterQueue.
+type LeaseStore struct {
+	client client.Client
+	// reader reads the Leases without going through the informers of the
+	// manager, which would cache the Leases of the whole cluster.
+	reader    client.Reader
+	namespace string
+}
+
+var _ Store = (*LeaseStore)(nil)
+
+// NewLeaseStore returns a LeaseStore that keeps the Leases in namespace.
+// The Leases are written with c and read with r, which is meant to be an
+// uncached reader, like the API reader of the manager.
+func NewLeaseStore(c client.Client, r client.Reader, namespace string) *LeaseStore {
+	return &LeaseStore{
+		client:    c,
+		reader:    r,
+		namespace: namespace,
+	}
+}
+
+func (s *LeaseStore) Publish(ctx context.Context, d Decision) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	var lease coordinationv1.Lease
+	key := types.NamespacedName{Namespace: s.namespace, Name: string(d.ClusterQueue)}
+	if err := s.reader.Get(ctx, key, &lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		lease = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{ManagedLabel: "true"},
+			},
+		}
+		setDecision(&lease, d, data)
+		return s.client.Create(ctx, &lease)
+	}
+	if lease.Labels[ManagedLabel] != "true" {
+		return fmt.Errorf("lease %s is not managed by kueue", key)
+	}
+	setDecision(&lease, d, data)
+	return s.client.Update(ctx, &lease)
+}
+
+func (s *LeaseStore) Observe(ctx context.Context) ([]Decision, error) {
+	var leases coordinationv1.LeaseList
+	if err := s.reader.List(ctx, &leases, client.InNamespace(s.namespace), client.MatchingLabels{ManagedLabel: "true"}); err != nil {
+		return nil, err
+	}
+	ret := make([]Decision, 0, len(leases.Items))
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		data, found := lease.Annotations[DecisionAnnotation]
+		if !found {
+			continue
+		}
+		var d Decision
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			return nil, fmt.Errorf("decoding the decision of lease %s: %w", client.ObjectKeyFromObject(lease), err)
+		}
+		ret = append(ret, d)
+	}
+	sortByClusterQueue(ret)
+	return ret, nil
+}
+
+func setDecision(lease *coordinationv1.Lease, d Decision, data []byte) {
+	if lease.Annotations == nil {
+		lease.Annotations = make(map[string]string, 1)
+	}
+	lease.Annotations[DecisionAnnotation] = string(data)
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: d.Time}
+}
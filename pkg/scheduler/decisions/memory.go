@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decisions
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is a Store that keeps the decisions in memory.
+type MemoryStore struct {
+	sync.RWMutex
+	decisions map[string]Decision
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		decisions: make(map[string]Decision),
+	}
+}
+
+func (s *MemoryStore) Publish(_ context.Context, d Decision) error {
+	s.Lock()
+	defer s.Unlock()
+	s.decisions[string(d.ClusterQueue)] = d
+	return nil
+}
+
+func (s *MemoryStore) Observe(context.Context) ([]Decision, error) {
+	s.RLock()
+	defer s.RUnlock()
+	ret := make([]Decision, 0, len(s.decisions))
+	for _, d := range s.decisions {
+		ret = append(ret, d)
+	}
+	sortByClusterQueue(ret)
+	return ret, nil
+}
+
+func sortByClusterQueue(decisions []Decision) {
+	slices.SortFunc(decisions, func(a, b Decision) int {
+		return strings.Compare(string(a.ClusterQueue), string(b.ClusterQueue))
+	})
+}
@@ -19,6 +19,7 @@ package flavorassigner
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
 	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
@@ -98,6 +100,18 @@ func (a *Assignment) Message() string {
 	return builder.String()
 }
 
+// BorrowsFlavorResource returns whether the assignment borrows quota of the
+// resource in the flavor. Unlike Borrows, it doesn't consider the borrowing
+// in other flavors or resources.
+func (a *Assignment) BorrowsFlavorResource(fName kueue.ResourceFlavorReference, rName corev1.ResourceName) bool {
+	for _, ps := range a.PodSets {
+		if fa, found := ps.Flavors[rName]; found && fa.Name == fName && fa.Borrow {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *Assignment) ToAPI() []kueue.PodSetAssignment {
 	psFlavors := make([]kueue.PodSetAssignment, len(a.PodSets))
 	for i := range psFlavors {
@@ -194,6 +208,15 @@ func (psa *PodSetAssignment) RepresentativeMode() FlavorAssignmentMode {
 
 type ResourceAssignment map[corev1.ResourceName]*FlavorAssignment
 
+func (ra ResourceAssignment) borrows() bool {
+	for _, fa := range ra {
+		if fa.Borrow {
+			return true
+		}
+	}
+	return false
+}
+
 func (psa *PodSetAssignment) toAPI() kueue.PodSetAssignment {
 	flavors := make(map[corev1.ResourceName]kueue.ResourceFlavorReference, len(psa.Flavors))
 	for res, flvAssignment := range psa.Flavors {
@@ -241,7 +264,9 @@ type FlavorAssignment struct {
 	Name           kueue.ResourceFlavorReference
 	Mode           FlavorAssignmentMode
 	TriedFlavorIdx int
-	borrow         bool
+	// Borrow indicates whether the assignment borrows quota of the resource
+	// in the flavor from the cohort.
+	Borrow bool
 }
 
 type FlavorAssigner struct {
@@ -312,7 +337,9 @@ func (a *FlavorAssigner) assignFlavors(log logr.Logger, requests []workload.PodS
 		assignment.LastState.CohortGeneration = a.cq.Cohort.AllocatableResourceGeneration
 	}
 
-	for i, podSet := range requests {
+	psAssignments := make([]*PodSetAssignment, len(requests))
+	for _, i := range placementOrder(a.wl.Obj.Spec.PodSets[:len(requests)]) {
+		podSet := requests[i]
 		if _, found := a.cq.RGByResource[corev1.ResourcePods]; found {
 			podSet.Requests[corev1.ResourcePods] = int64(podSet.Count)
 		}
@@ -340,13 +367,119 @@ func (a *FlavorAssigner) assignFlavors(log logr.Logger, requests []workload.PodS
 		}
 
 		assignment.append(podSet.Requests, &psAssignment)
+		psAssignments[i] = &psAssignment
 		if psAssignment.Status.IsError() || (len(podSet.Requests) > 0 && len(psAssignment.Flavors) == 0) {
-			return assignment
+			break
 		}
 	}
+	assignment.collect(psAssignments)
 	return assignment
 }
 
+// placementOrder returns the indexes of the pod sets in the order in which
+// flavors should be assigned to them. The pod sets declaring a placement order
+// hint go first, in ascending order of the hint, followed by the rest in their
+// original order.
+func placementOrder(podSets []kueue.PodSet) []int {
+	order := make([]int, len(podSets))
+	keys := make([]int64, len(podSets))
+	for i := range podSets {
+		order[i] = i
+		keys[i] = math.MaxInt32 + 1
+		if hint, found := workload.PlacementOrder(&podSets[i]); found {
+			keys[i] = int64(hint)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return keys[order[i]] < keys[order[j]]
+	})
+	return order
+}
+
+// CandidateFlavors returns, for each pod set, the flavors of the ClusterQueue
+// that match the node affinity and tolerations of the pod set and that cover
+// all its requested resources, without considering the available quota.
+// A pod set gets no candidates if any of its requested resources can't be
+// covered by a matching flavor.
+func (a *FlavorAssigner) CandidateFlavors() []kueue.PodSetCandidateFlavors {
+	candidates := make([]kueue.PodSetCandidateFlavors, len(a.wl.TotalRequests))
+	for i, ps := range a.wl.TotalRequests {
+		candidates[i].Name = ps.Name
+		requested := sets.KeySet(ps.Requests)
+		if _, found := a.cq.RGByResource[corev1.ResourcePods]; found {
+			requested.Insert(corev1.ResourcePods)
+		}
+		var flavors []kueue.ResourceFlavorReference
+		covered := sets.New[corev1.ResourceName]()
+		for j := range a.cq.ResourceGroups {
+			rg := &a.cq.ResourceGroups[j]
+			if !rg.CoveredResources.HasAny(requested.UnsortedList()...) {
+				continue
+			}
+			rgFlavors := a.matchingFlavors(i, rg)
+			if len(rgFlavors) == 0 {
+				flavors = nil
+				break
+			}
+			flavors = append(flavors, rgFlavors...)
+			covered.Insert(rg.CoveredResources.UnsortedList()...)
+		}
+		if covered.IsSuperset(requested) {
+			candidates[i].Flavors = flavors
+		}
+	}
+	return candidates
+}
+
+// matchingFlavors returns the flavors in the resource group that are not
+// cordoned, whose taints are tolerated and whose labels match the node
+// affinity and the required topology of the pod set.
+func (a *FlavorAssigner) matchingFlavors(psID int, rg *cache.ResourceGroup) []kueue.ResourceFlavorReference {
+	podSpec := &a.wl.Obj.Spec.PodSets[psID].Template.Spec
+	selector := flavorSelector(podSpec, rg.LabelKeys)
+	topologyKeys, err := workload.RequiredTopology(&a.wl.Obj.Spec.PodSets[psID])
+	if err != nil {
+		return nil
+	}
+	var flavors []kueue.ResourceFlavorReference
+	for _, flvQuotas := range rg.Flavors {
+		flavor, exist := a.resourceFlavors[flvQuotas.Name]
+		if !exist {
+			continue
+		}
+		if reason, err := flavorMismatch(flavor, podSpec, selector, topologyKeys); reason != "" || err != nil {
+			continue
+		}
+		flavors = append(flavors, flvQuotas.Name)
+	}
+	return flavors
+}
+
+// flavorMismatch returns why the pod set can't use the flavor, regardless of
+// its quota, or an empty string if it can.
+func flavorMismatch(flavor *kueue.ResourceFlavor, podSpec *corev1.PodSpec, selector nodeaffinity.RequiredNodeAffinity, topologyKeys []string) (string, error) {
+	if flavor.Spec.Cordoned {
+		return fmt.Sprintf("flavor %s is cordoned", flavor.Name), nil
+	}
+	taint, untolerated := corev1helpers.FindMatchingUntoleratedTaint(flavor.Spec.NodeTaints, podSpec.Tolerations, func(t *corev1.Taint) bool {
+		return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
+	})
+	if untolerated {
+		return fmt.Sprintf("untolerated taint %s in flavor %s", taint, flavor.Name), nil
+	}
+	match, err := selector.Match(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: flavor.Spec.NodeLabels}})
+	if err != nil {
+		return "", err
+	}
+	if !match || !nodeSelectorMatches(flavor, podSpec) {
+		return fmt.Sprintf("flavor %s doesn't match node affinity", flavor.Name), nil
+	}
+	if key, missing := missingTopologyKey(flavor, topologyKeys); missing {
+		return fmt.Sprintf("flavor %s doesn't set the required topology label %s", flavor.Name, key), nil
+	}
+	return "", nil
+}
+
 func (psa *PodSetAssignment) append(flavors ResourceAssignment, status *Status) {
 	for resource, assignment := range flavors {
 		psa.Flavors[resource] = assignment
@@ -359,19 +492,40 @@ func (psa *PodSetAssignment) append(flavors ResourceAssignment, status *Status)
 }
 
 func (a *Assignment) append(requests workload.Requests, psAssignment *PodSetAssignment) {
-	flavorIdx := make(map[corev1.ResourceName]int, len(psAssignment.Flavors))
-	a.PodSets = append(a.PodSets, *psAssignment)
 	for resource, flvAssignment := range psAssignment.Flavors {
-		if flvAssignment.borrow {
+		if flvAssignment.Borrow {
 			a.Borrowing = true
 		}
 		if a.Usage[flvAssignment.Name] == nil {
 			a.Usage[flvAssignment.Name] = make(map[corev1.ResourceName]int64)
 		}
 		a.Usage[flvAssignment.Name][resource] += requests[resource]
-		flavorIdx[resource] = flvAssignment.TriedFlavorIdx
 	}
-	a.LastState.LastTriedFlavorIdx = append(a.LastState.LastTriedFlavorIdx, flavorIdx)
+}
+
+// collect records the computed pod set assignments following the order of the
+// workload pod sets, regardless of the order in which they were computed.
+// The pod sets that were not reached are skipped, keeping the last tried
+// flavor indexes aligned with the workload pod sets.
+func (a *Assignment) collect(psAssignments []*PodSetAssignment) {
+	last := -1
+	for i := range psAssignments {
+		if psAssignments[i] != nil {
+			last = i
+		}
+	}
+	for _, psAssignment := range psAssignments[:last+1] {
+		if psAssignment == nil {
+			a.LastState.LastTriedFlavorIdx = append(a.LastState.LastTriedFlavorIdx, nil)
+			continue
+		}
+		flavorIdx := make(map[corev1.ResourceName]int, len(psAssignment.Flavors))
+		for resource, flvAssignment := range psAssignment.Flavors {
+			flavorIdx[resource] = flvAssignment.TriedFlavorIdx
+		}
+		a.PodSets = append(a.PodSets, *psAssignment)
+		a.LastState.LastTriedFlavorIdx = append(a.LastState.LastTriedFlavorIdx, flavorIdx)
+	}
 }
 
 // findFlavorForPodSetResource finds the flavor which can satisfy the podSet request
@@ -399,6 +553,27 @@ func (a *FlavorAssigner) findFlavorForPodSetResource(
 
 	var bestAssignment ResourceAssignment
 	bestAssignmentMode := NoFit
+	// With the BestFit and LeastContended strategies, all the flavors are
+	// evaluated and, among the ones in which the requests fit, the one with
+	// the lowest score is chosen.
+	var bestFitAssignment ResourceAssignment
+	var bestFitScore float64
+	// With a preferred node selector, the first flavor in which the requests
+	// fit without borrowing, but that doesn't match the selector, is only
+	// chosen if none of the matching flavors fits.
+	preferredSelector, err := workload.PreferredNodeSelector(&a.wl.Obj.Spec.PodSets[psID])
+	if err != nil {
+		log.V(3).Info("Ignoring malformed preferred node selector", "podSet", a.wl.Obj.Spec.PodSets[psID].Name, "error", err)
+	}
+	var fallbackAssignment ResourceAssignment
+	topologyKeys, err := workload.RequiredTopology(&a.wl.Obj.Spec.PodSets[psID])
+	if err != nil {
+		status.err = err
+		return nil, status
+	}
+	// With the PreferPrevious readmission policy, the flavor assigned in the
+	// previous admission is preferred in the same way.
+	previousFlavor := a.previousFlavor(psID, resName, resourceGroup)
 
 	// We will only check against the flavors' labels for the resource.
 	selector := flavorSelector(podSpec, resourceGroup.LabelKeys)
@@ -413,19 +588,13 @@ func (a *FlavorAssigner) findFlavorForPodSetResource(
 			status.append(fmt.Sprintf("flavor %s not found", flvQuotas.Name))
 			continue
 		}
-		taint, untolerated := corev1helpers.FindMatchingUntoleratedTaint(flavor.Spec.NodeTaints, podSpec.Tolerations, func(t *corev1.Taint) bool {
-			return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
-		})
-		if untolerated {
-			status.append(fmt.Sprintf("untolerated taint %s in flavor %s", taint, flvQuotas.Name))
-			continue
+		reason, err := flavorMismatch(flavor, podSpec, selector, topologyKeys)
+		if err != nil {
+			status.err = err
+			return nil, status
 		}
-		if match, err := selector.Match(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: flavor.Spec.NodeLabels}}); !match || err != nil {
-			if err != nil {
-				status.err = err
-				return nil, status
-			}
-			status.append(fmt.Sprintf("flavor %s doesn't match node affinity", flvQuotas.Name))
+		if reason != "" {
+			status.append(reason)
 			continue
 		}
 		needsBorrowing := false
@@ -451,10 +620,28 @@ func (a *FlavorAssigner) findFlavorForPodSetResource(
 			assignments[rName] = &FlavorAssignment{
 				Name:   flvQuotas.Name,
 				Mode:   mode,
-				borrow: borrow,
+				Borrow: borrow,
 			}
 		}
 
+		preferred := (preferredSelector == nil || preferredSelector.Matches(labels.Set(flavor.Spec.NodeLabels))) &&
+			(previousFlavor == "" || flvQuotas.Name == previousFlavor)
+		if !preferred && representativeMode == Fit && !needsBorrowing {
+			if fallbackAssignment == nil {
+				fallbackAssignment = assignments
+			}
+			continue
+		}
+
+		if evaluatesAllFlavors(a.cq.FlavorSelectionStrategy) && representativeMode == Fit && !needsBorrowing {
+			score := flavorScore(a.cq, flvQuotas, requests, assignmentUsage)
+			if bestFitAssignment == nil || score < bestFitScore {
+				bestFitAssignment = assignments
+				bestFitScore = score
+			}
+			continue
+		}
+
 		if features.Enabled(features.FlavorFungibility) {
 			if !shouldTryNextFlavor(representativeMode, a.cq.FlavorFungibility, needsBorrowing) {
 				bestAssignment = assignments
@@ -470,11 +657,19 @@ func (a *FlavorAssigner) findFlavorForPodSetResource(
 			bestAssignmentMode = representativeMode
 			if bestAssignmentMode == Fit {
 				// All the resources fit in the cohort, no need to check more flavors.
-				return bestAssignment, nil
+				break
 			}
 		}
 	}
 
+	if bestFitAssignment != nil {
+		bestAssignment = bestFitAssignment
+		bestAssignmentMode = Fit
+	} else if fallbackAssignment != nil && (bestAssignmentMode != Fit || bestAssignment.borrows()) {
+		bestAssignment = fallbackAssignment
+		bestAssignmentMode = Fit
+	}
+
 	if features.Enabled(features.FlavorFungibility) {
 		for _, assignment := range bestAssignment {
 			if attemptedFlavorIdx == len(resourceGroup.Flavors)-1 {
@@ -484,13 +679,79 @@ func (a *FlavorAssigner) findFlavorForPodSetResource(
 				assignment.TriedFlavorIdx = attemptedFlavorIdx
 			}
 		}
-		if bestAssignmentMode == Fit {
-			return bestAssignment, nil
-		}
+	}
+	if bestAssignmentMode == Fit {
+		return bestAssignment, nil
 	}
 	return bestAssignment, status
 }
 
+// previousFlavor returns the flavor of the resource group that was assigned
+// to the resource of the podSet in the previous admission of the workload,
+// if the ClusterQueue prefers it.
+func (a *FlavorAssigner) previousFlavor(psID int, resName corev1.ResourceName, rg *cache.ResourceGroup) kueue.ResourceFlavorReference {
+	if a.cq.ReadmissionFlavorPolicy != kueue.ReadmissionFlavorPolicyPreferPrevious {
+		return ""
+	}
+	fName := workload.PreviousFlavor(a.wl.Obj, a.wl.Obj.Spec.PodSets[psID].Name, resName)
+	if fName == "" {
+		return ""
+	}
+	for _, flvQuotas := range rg.Flavors {
+		if flvQuotas.Name == fName {
+			return fName
+		}
+	}
+	return ""
+}
+
+// evaluatesAllFlavors returns whether the strategy needs to score every
+// flavor that fits instead of stopping at the first one.
+func evaluatesAllFlavors(strategy kueue.FlavorSelectionStrategy) bool {
+	return strategy == kueue.BestFit || strategy == kueue.LeastContended
+}
+
+// flavorScore returns the score of assigning the flavor to the requests
+// according to the flavor selection strategy of the ClusterQueue. Lower is
+// better.
+func flavorScore(cq *cache.ClusterQueue, flvQuotas cache.FlavorQuotas, requests workload.Requests, assignmentUsage resources.FlavorResourceQuantities) float64 {
+	if cq.FlavorSelectionStrategy == kueue.LeastContended {
+		return peersBorrowedRatio(cq, flvQuotas, requests)
+	}
+	return unusedNominalQuotaRatio(cq, flvQuotas, requests, assignmentUsage)
+}
+
+// peersBorrowedRatio returns how much quota, relative to the nominal quota
+// of the ClusterQueue, the other members of the cohort are borrowing from
+// the flavor for the requested resources.
+func peersBorrowedRatio(cq *cache.ClusterQueue, flvQuotas cache.FlavorQuotas, requests workload.Requests) float64 {
+	var borrowed float64
+	for rName := range requests {
+		nominal := flvQuotas.Resources[rName].Nominal
+		if nominal <= 0 {
+			continue
+		}
+		borrowed += float64(cq.PeersBorrowed(flvQuotas.Name, rName)) / float64(nominal)
+	}
+	return borrowed
+}
+
+// unusedNominalQuotaRatio returns the sum, over the requested resources, of
+// the fraction of the nominal quota of the flavor that would remain unused
+// after assigning the requests.
+func unusedNominalQuotaRatio(cq *cache.ClusterQueue, flvQuotas cache.FlavorQuotas, requests workload.Requests, assignmentUsage resources.FlavorResourceQuantities) float64 {
+	var unused float64
+	for rName, val := range requests {
+		nominal := flvQuotas.Resources[rName].Nominal
+		if nominal <= 0 {
+			continue
+		}
+		used := cq.Usage[flvQuotas.Name][rName] + assignmentUsage[flvQuotas.Name][rName] + val
+		unused += float64(nominal-used) / float64(nominal)
+	}
+	return unused
+}
+
 func shouldTryNextFlavor(representativeMode FlavorAssignmentMode, flavorFungibility kueue.FlavorFungibility, needsBorrowing bool) bool {
 	policyPreempt := flavorFungibility.WhenCanPreempt
 	policyBorrow := flavorFungibility.WhenCanBorrow
@@ -577,6 +838,10 @@ func (a *FlavorAssigner) fitsResourceQuota(fName kueue.ResourceFlavorReference,
 	cohortAvailable := rQuota.Nominal
 	if a.cq.Cohort != nil {
 		cohortAvailable = a.cq.RequestableCohortQuota(fName, rName)
+		if used+val > rQuota.Nominal {
+			// Borrowing can't use the safety margin of the cohort.
+			cohortAvailable = a.cq.BorrowableCohortQuota(fName, rName)
+		}
 	}
 
 	if a.canPreemptWhileBorrowing() {
@@ -591,13 +856,16 @@ func (a *FlavorAssigner) fitsResourceQuota(fName kueue.ResourceFlavorReference,
 		status.append(fmt.Sprintf("borrowing limit for %s in flavor %s exceeded", rName, fName))
 		return mode, borrow, &status
 	}
+	if !a.cq.FitInCohortBorrowingCaps(a.cq.Borrowing(resources.FlavorResourceQuantities{fName: {rName: val}})) {
+		status.append(fmt.Sprintf("cohort borrowing cap for %s in flavor %s exceeded", rName, fName))
+		return mode, borrow, &status
+	}
 
-	cohortUsed := used
+	lack := used + val - rQuota.Nominal
 	if a.cq.Cohort != nil {
-		cohortUsed = a.cq.UsedCohortQuota(fName, rName)
+		// Borrowing can't use the safety margins of the cohorts.
+		lack = val - a.cq.CohortAvailable(fName, rName, used+val > rQuota.Nominal)
 	}
-
-	lack := cohortUsed + val - cohortAvailable
 	if lack <= 0 {
 		return Fit, used+val > rQuota.Nominal, nil
 	}
@@ -629,3 +897,40 @@ func filterRequestedResources(req workload.Requests, allowList sets.Set[corev1.R
 	}
 	return filtered
 }
+
+// nodeSelectorMatches returns whether the node selector term of the flavor is
+// compatible with the node selector of the pod spec. Only the requirements
+// whose keys are in the node selector of the pod spec are checked, as the pods
+// could land on any node matching the term otherwise.
+func nodeSelectorMatches(flavor *kueue.ResourceFlavor, spec *corev1.PodSpec) bool {
+	if flavor.Spec.NodeSelector == nil || len(spec.NodeSelector) == 0 {
+		return true
+	}
+	var reqs []corev1.NodeSelectorRequirement
+	for _, req := range flavor.Spec.NodeSelector.MatchExpressions {
+		if _, found := spec.NodeSelector[req.Key]; found {
+			reqs = append(reqs, req)
+		}
+	}
+	if len(reqs) == 0 {
+		return true
+	}
+	selector, err := nodeaffinity.NewNodeSelector(&corev1.NodeSelector{
+		NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: reqs}},
+	})
+	if err != nil {
+		return false
+	}
+	return selector.Match(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: spec.NodeSelector}})
+}
+
+// missingTopologyKey returns the first of the required topology keys that is
+// not set in the node labels of the flavor, if any.
+func missingTopologyKey(flavor *kueue.ResourceFlavor, keys []string) (string, bool) {
+	for _, key := range keys {
+		if _, found := flavor.Spec.NodeLabels[key]; !found {
+			return key, true
+		}
+	}
+	return "", false
+}
@@ -56,6 +56,7 @@ func TestAssignFlavors(t *testing.T) {
 				Value:  "spot",
 				Effect: corev1.TaintEffectNoSchedule,
 			}).Obj(),
+		"cordoned": utiltesting.MakeResourceFlavor("cordoned").Cordoned(true).Obj(),
 	}
 
 	cases := map[string]struct {
@@ -108,6 +109,50 @@ func TestAssignFlavors(t *testing.T) {
 				}.Unflatten(),
 			},
 		},
+		"skips cordoned flavor": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("main", 1).
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+			},
+			clusterQueue: cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors: []cache.FlavorQuotas{
+						{
+							Name: "cordoned",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 4000},
+							},
+						},
+						{
+							Name: "one",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 4000},
+							},
+						},
+					},
+				}},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{
+					{
+						Name: "main",
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "one", Mode: Fit, TriedFlavorIdx: -1},
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1000m"),
+						},
+						Count: 1,
+					},
+				},
+				Usage: resources.FlavorResourceQuantitiesFlat{
+					{Flavor: "one", Resource: corev1.ResourceCPU}: 1000,
+				}.Unflatten(),
+			},
+		},
 		"single flavor, fits tainted flavor": {
 			wlPods: []kueue.PodSet{
 				*utiltesting.MakePodSet("main", 1).
@@ -952,6 +997,116 @@ func TestAssignFlavors(t *testing.T) {
 				}.Unflatten(),
 			},
 		},
+		"multiple specs, assigned following the placement order": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("worker", 1).
+					Request(corev1.ResourceCPU, "3").
+					Obj(),
+				*utiltesting.MakePodSet("launcher", 1).
+					Annotations(map[string]string{kueue.PodSetPlacementOrderAnnotation: "0"}).
+					Request(corev1.ResourceCPU, "2").
+					Obj(),
+			},
+			clusterQueue: cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{
+					{
+						CoveredResources: sets.New(corev1.ResourceCPU),
+						Flavors: []cache.FlavorQuotas{
+							{
+								Name: "one",
+								Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+									corev1.ResourceCPU: {Nominal: 4000},
+								},
+							},
+							{
+								Name: "two",
+								Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+									corev1.ResourceCPU: {Nominal: 10_000},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{
+					{
+						Name: "worker",
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "two", Mode: Fit, TriedFlavorIdx: -1},
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("3000m"),
+						},
+						Count: 1,
+					},
+					{
+						Name: "launcher",
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "one", Mode: Fit, TriedFlavorIdx: 0},
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("2000m"),
+						},
+						Count: 1,
+					},
+				},
+				Usage: resources.FlavorResourceQuantitiesFlat{
+					{Flavor: "one", Resource: corev1.ResourceCPU}: 2000,
+					{Flavor: "two", Resource: corev1.ResourceCPU}: 3000,
+				}.Unflatten(),
+			},
+		},
+		"multiple specs, first pod set in placement order doesn't fit": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("worker", 1).
+					Request(corev1.ResourceCPU, "3").
+					Obj(),
+				*utiltesting.MakePodSet("launcher", 1).
+					Annotations(map[string]string{kueue.PodSetPlacementOrderAnnotation: "0"}).
+					Request(corev1.ResourceCPU, "20").
+					Obj(),
+			},
+			clusterQueue: cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{
+					{
+						CoveredResources: sets.New(corev1.ResourceCPU),
+						Flavors: []cache.FlavorQuotas{
+							{
+								Name: "one",
+								Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+									corev1.ResourceCPU: {Nominal: 4000},
+								},
+							},
+							{
+								Name: "two",
+								Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+									corev1.ResourceCPU: {Nominal: 10_000},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantRepMode: NoFit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name: "launcher",
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("20"),
+					},
+					Status: &Status{
+						reasons: []string{
+							"insufficient quota for cpu in flavor one in ClusterQueue",
+							"insufficient quota for cpu in flavor two in ClusterQueue",
+						},
+					},
+					Count: 1,
+				}},
+				Usage: resources.FlavorResourceQuantities{},
+			},
+		},
 		"multiple specs, fits borrowing": {
 			wlPods: []kueue.PodSet{
 				*utiltesting.MakePodSet("driver", 1).
@@ -1104,6 +1259,131 @@ func TestAssignFlavors(t *testing.T) {
 				}.Unflatten(),
 			},
 		},
+		"cohort borrowing cap reached, even if the ClusterQueue has no borrowingLimit; fallback to flavor two": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("main", 1).
+					Request(corev1.ResourceCPU, "2").
+					Obj(),
+			},
+			clusterQueue: cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors: []cache.FlavorQuotas{{
+						Name: "one",
+						Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+							corev1.ResourceCPU: {Nominal: 1000},
+						},
+					}, {
+						Name: "two",
+						Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+							corev1.ResourceCPU: {Nominal: 2000},
+						},
+					}},
+				}},
+				Cohort: &cache.Cohort{
+					Members: sets.New(&cache.ClusterQueue{
+						ResourceGroups: []cache.ResourceGroup{{
+							CoveredResources: sets.New(corev1.ResourceCPU),
+							Flavors: []cache.FlavorQuotas{{
+								Name: "one",
+								Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+									corev1.ResourceCPU: {Nominal: 1000},
+								},
+							}},
+						}},
+						Usage: resources.FlavorResourceQuantitiesFlat{
+							{Flavor: "one", Resource: corev1.ResourceCPU}: 4000,
+						}.Unflatten(),
+					}),
+					BorrowingCaps: resources.FlavorResourceQuantitiesFlat{
+						{Flavor: "one", Resource: corev1.ResourceCPU}: 3500,
+					}.Unflatten(),
+					RequestableResources: resources.FlavorResourceQuantitiesFlat{
+						{Flavor: "one", Resource: corev1.ResourceCPU}: 100_000,
+						{Flavor: "two", Resource: corev1.ResourceCPU}: 100_000,
+					}.Unflatten(),
+					Usage: resources.FlavorResourceQuantitiesFlat{
+						{Flavor: "one", Resource: corev1.ResourceCPU}: 4000,
+					}.Unflatten(),
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name: "main",
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "two", Mode: Fit, TriedFlavorIdx: -1},
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("2000m"),
+					},
+					Count: 1,
+				}},
+				Usage: resources.FlavorResourceQuantitiesFlat{
+					{Flavor: "two", Resource: corev1.ResourceCPU}: 2000,
+				}.Unflatten(),
+			},
+		},
+		"cohort borrowing cap not reached": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("main", 1).
+					Request(corev1.ResourceCPU, "2").
+					Obj(),
+			},
+			clusterQueue: cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors: []cache.FlavorQuotas{{
+						Name: "one",
+						Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+							corev1.ResourceCPU: {Nominal: 1000},
+						},
+					}},
+				}},
+				Cohort: &cache.Cohort{
+					Members: sets.New(&cache.ClusterQueue{
+						ResourceGroups: []cache.ResourceGroup{{
+							CoveredResources: sets.New(corev1.ResourceCPU),
+							Flavors: []cache.FlavorQuotas{{
+								Name: "one",
+								Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+									corev1.ResourceCPU: {Nominal: 1000},
+								},
+							}},
+						}},
+						Usage: resources.FlavorResourceQuantitiesFlat{
+							{Flavor: "one", Resource: corev1.ResourceCPU}: 4000,
+						}.Unflatten(),
+					}),
+					BorrowingCaps: resources.FlavorResourceQuantitiesFlat{
+						{Flavor: "one", Resource: corev1.ResourceCPU}: 4000,
+					}.Unflatten(),
+					RequestableResources: resources.FlavorResourceQuantitiesFlat{
+						{Flavor: "one", Resource: corev1.ResourceCPU}: 100_000,
+					}.Unflatten(),
+					Usage: resources.FlavorResourceQuantitiesFlat{
+						{Flavor: "one", Resource: corev1.ResourceCPU}: 4000,
+					}.Unflatten(),
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				Borrowing: true,
+				PodSets: []PodSetAssignment{{
+					Name: "main",
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Fit, TriedFlavorIdx: -1},
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("2000m"),
+					},
+					Count: 1,
+				}},
+				Usage: resources.FlavorResourceQuantitiesFlat{
+					{Flavor: "one", Resource: corev1.ResourceCPU}: 2000,
+				}.Unflatten(),
+			},
+		},
 		"past min, but can preempt in ClusterQueue": {
 			wlPods: []kueue.PodSet{
 				*utiltesting.MakePodSet("main", 1).
@@ -2366,34 +2646,821 @@ func TestAssignFlavors(t *testing.T) {
 				t.Errorf("e.assignFlavors(_).RepresentativeMode()=%s, want %s", repMode, tc.wantRepMode)
 			}
 
-			if diff := cmp.Diff(tc.wantAssignment, assignment, cmpopts.IgnoreUnexported(Assignment{}, FlavorAssignment{}), cmpopts.IgnoreFields(Assignment{}, "LastState")); diff != "" {
+			if diff := cmp.Diff(tc.wantAssignment, assignment, cmpopts.IgnoreUnexported(Assignment{}), cmpopts.IgnoreFields(Assignment{}, "LastState"), cmpopts.IgnoreFields(FlavorAssignment{}, "Borrow")); diff != "" {
 				t.Errorf("Unexpected assignment (-want,+got):\n%s", diff)
 			}
 		})
 	}
 }
 
-func TestLastAssignmentOutdated(t *testing.T) {
-	type args struct {
-		wl *workload.Info
-		cq *cache.ClusterQueue
+func TestFlavorSelectionStrategy(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"large": utiltesting.MakeResourceFlavor("large").Obj(),
+		"small": utiltesting.MakeResourceFlavor("small").Obj(),
 	}
-	tests := []struct {
+	// The workloads are assigned in order, each one using the quota of the
+	// flavor it gets.
+	workloads := []struct {
 		name string
-		args args
-		want bool
+		cpu  string
 	}{
-		{
-			name: "Cluster queue allocatableResourceIncreasedGen increased",
-			args: args{
-				wl: &workload.Info{
-					LastAssignment: &workload.AssignmentClusterQueueState{
-						ClusterQueueGeneration: 0,
-					},
-				},
-				cq: &cache.ClusterQueue{
-					Cohort:                        nil,
-					AllocatableResourceGeneration: 1,
+		{name: "a", cpu: "4"},
+		{name: "b", cpu: "8"},
+		{name: "c", cpu: "2"},
+	}
+	cases := map[string]struct {
+		strategy    kueue.FlavorSelectionStrategy
+		wantFlavors []kueue.ResourceFlavorReference
+	}{
+		"first fit fragments the large flavor": {
+			strategy:    kueue.FirstFit,
+			wantFlavors: []kueue.ResourceFlavorReference{"large", "", "large"},
+		},
+		"default strategy is first fit": {
+			wantFlavors: []kueue.ResourceFlavorReference{"large", "", "large"},
+		},
+		"best fit keeps the large flavor for the big workload": {
+			strategy:    kueue.BestFit,
+			wantFlavors: []kueue.ResourceFlavorReference{"small", "large", ""},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors: []cache.FlavorQuotas{
+						{
+							Name: "large",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 8_000},
+							},
+						},
+						{
+							Name: "small",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 4_000},
+							},
+						},
+					},
+				}},
+				FlavorFungibility: kueue.FlavorFungibility{
+					WhenCanBorrow:  kueue.Borrow,
+					WhenCanPreempt: kueue.TryNextFlavor,
+				},
+				FlavorSelectionStrategy: tc.strategy,
+				Usage:                   resources.FlavorResourceQuantities{},
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			gotFlavors := make([]kueue.ResourceFlavorReference, 0, len(workloads))
+			for _, w := range workloads {
+				wlInfo := workload.NewInfo(utiltesting.MakeWorkload(w.name, "").Request(corev1.ResourceCPU, w.cpu).Obj())
+				assignment := New(wlInfo, &cq, resourceFlavors, false).Assign(log, nil)
+				if assignment.RepresentativeMode() != Fit {
+					gotFlavors = append(gotFlavors, "")
+					continue
+				}
+				gotFlavors = append(gotFlavors, assignment.PodSets[0].Flavors[corev1.ResourceCPU].Name)
+				for fName, fUsage := range assignment.Usage {
+					if cq.Usage[fName] == nil {
+						cq.Usage[fName] = make(map[corev1.ResourceName]int64)
+					}
+					for rName, v := range fUsage {
+						cq.Usage[fName][rName] += v
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.wantFlavors, gotFlavors); diff != "" {
+				t.Errorf("Unexpected assigned flavors (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLeastContendedFlavorSelection(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"one": utiltesting.MakeResourceFlavor("one").Obj(),
+		"two": utiltesting.MakeResourceFlavor("two").Obj(),
+	}
+	cases := map[string]struct {
+		strategy   kueue.FlavorSelectionStrategy
+		peerUsage  resources.FlavorResourceQuantities
+		wantFlavor kueue.ResourceFlavorReference
+	}{
+		"first fit ignores the borrowing of the peers": {
+			strategy: kueue.FirstFit,
+			peerUsage: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: "one", Resource: corev1.ResourceCPU}: 2_000,
+			}.Unflatten(),
+			wantFlavor: "one",
+		},
+		"least contended avoids the flavor the peers are borrowing": {
+			strategy: kueue.LeastContended,
+			peerUsage: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: "one", Resource: corev1.ResourceCPU}: 2_000,
+			}.Unflatten(),
+			wantFlavor: "two",
+		},
+		"least contended prefers the flavor the peers borrow the least": {
+			strategy: kueue.LeastContended,
+			peerUsage: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: "one", Resource: corev1.ResourceCPU}: 2_000,
+				{Flavor: "two", Resource: corev1.ResourceCPU}: 1_000,
+			}.Unflatten(),
+			wantFlavor: "two",
+		},
+		"least contended keeps the order of the flavors when there is no contention": {
+			strategy:   kueue.LeastContended,
+			peerUsage:  resources.FlavorResourceQuantities{},
+			wantFlavor: "one",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			flavors := func(nominal int64) []cache.FlavorQuotas {
+				return []cache.FlavorQuotas{
+					{
+						Name: "one",
+						Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+							corev1.ResourceCPU: {Nominal: nominal},
+						},
+					},
+					{
+						Name: "two",
+						Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+							corev1.ResourceCPU: {Nominal: nominal},
+						},
+					},
+				}
+			}
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors:          flavors(4_000),
+				}},
+				FlavorFungibility: kueue.FlavorFungibility{
+					WhenCanBorrow:  kueue.Borrow,
+					WhenCanPreempt: kueue.TryNextFlavor,
+				},
+				FlavorSelectionStrategy: tc.strategy,
+				Usage:                   resources.FlavorResourceQuantities{},
+			}
+			peer := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors:          flavors(0),
+				}},
+				Usage: tc.peerUsage,
+			}
+			cq.Cohort = &cache.Cohort{
+				Members: sets.New(&cq, &peer),
+				RequestableResources: resources.FlavorResourceQuantitiesFlat{
+					{Flavor: "one", Resource: corev1.ResourceCPU}: 4_000,
+					{Flavor: "two", Resource: corev1.ResourceCPU}: 4_000,
+				}.Unflatten(),
+				Usage: tc.peerUsage,
+			}
+			peer.Cohort = cq.Cohort
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("wl", "").Request(corev1.ResourceCPU, "1").Obj())
+			assignment := New(wlInfo, &cq, resourceFlavors, false).Assign(log, nil)
+			if repMode := assignment.RepresentativeMode(); repMode != Fit {
+				t.Fatalf("Unexpected representative mode %s, want %s", repMode, Fit)
+			}
+			if got := assignment.PodSets[0].Flavors[corev1.ResourceCPU].Name; got != tc.wantFlavor {
+				t.Errorf("Assigned flavor %s, want %s", got, tc.wantFlavor)
+			}
+		})
+	}
+}
+
+func TestAssignFlavorsNodeArchitecture(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"amd64": utiltesting.MakeResourceFlavor("amd64").Label(corev1.LabelArchStable, "amd64").Obj(),
+		"arm64": utiltesting.MakeResourceFlavor("arm64").Label(corev1.LabelArchStable, "arm64").Obj(),
+	}
+	cases := map[string]struct {
+		flavors      []kueue.ResourceFlavorReference
+		nodeSelector map[string]string
+		wantFlavor   kueue.ResourceFlavorReference
+		wantMode     FlavorAssignmentMode
+	}{
+		"arm64 workload skips the amd64 flavor": {
+			flavors:      []kueue.ResourceFlavorReference{"amd64", "arm64"},
+			nodeSelector: map[string]string{corev1.LabelArchStable: "arm64"},
+			wantFlavor:   "arm64",
+			wantMode:     Fit,
+		},
+		"amd64 workload gets the amd64 flavor": {
+			flavors:      []kueue.ResourceFlavorReference{"arm64", "amd64"},
+			nodeSelector: map[string]string{corev1.LabelArchStable: "amd64"},
+			wantFlavor:   "amd64",
+			wantMode:     Fit,
+		},
+		"workload without architecture gets the first flavor": {
+			flavors:    []kueue.ResourceFlavorReference{"amd64", "arm64"},
+			wantFlavor: "amd64",
+			wantMode:   Fit,
+		},
+		"arm64 workload doesn't fit in amd64 only flavors": {
+			flavors:      []kueue.ResourceFlavorReference{"amd64"},
+			nodeSelector: map[string]string{corev1.LabelArchStable: "arm64"},
+			wantMode:     NoFit,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			rg := cache.ResourceGroup{CoveredResources: sets.New(corev1.ResourceCPU)}
+			for _, f := range tc.flavors {
+				rg.Flavors = append(rg.Flavors, cache.FlavorQuotas{
+					Name: f,
+					Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+						corev1.ResourceCPU: {Nominal: 4_000},
+					},
+				})
+			}
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{rg},
+				Usage:          resources.FlavorResourceQuantities{},
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("wl", "").
+				Request(corev1.ResourceCPU, "1").
+				NodeSelector(tc.nodeSelector).
+				Obj())
+			assignment := New(wlInfo, &cq, resourceFlavors, false).Assign(log, nil)
+			if got := assignment.RepresentativeMode(); got != tc.wantMode {
+				t.Fatalf("Unexpected assignment mode, want=%v, got=%v", tc.wantMode, got)
+			}
+			if tc.wantMode != Fit {
+				return
+			}
+			if got := assignment.PodSets[0].Flavors[corev1.ResourceCPU].Name; got != tc.wantFlavor {
+				t.Errorf("Unexpected flavor, want=%s, got=%s", tc.wantFlavor, got)
+			}
+		})
+	}
+}
+
+func TestAssignFlavorsPerFlavorAccounting(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"tight": utiltesting.MakeResourceFlavor("tight").Label("pool", "tight").Obj(),
+		"free":  utiltesting.MakeResourceFlavor("free").Label("pool", "free").Obj(),
+	}
+	cases := map[string]struct {
+		cohortRequestable resources.FlavorResourceQuantities
+		wantMode          FlavorAssignmentMode
+		wantUsage         resources.FlavorResourceQuantities
+		wantBorrowing     sets.Set[kueue.ResourceFlavorReference]
+	}{
+		"tight flavor borrows while the free flavor fits in its nominal quota": {
+			cohortRequestable: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: "tight", Resource: corev1.ResourceCPU}: 10_000,
+				{Flavor: "free", Resource: corev1.ResourceCPU}:  8_000,
+			}.Unflatten(),
+			wantMode: Fit,
+			wantUsage: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: "tight", Resource: corev1.ResourceCPU}: 2_000,
+				{Flavor: "free", Resource: corev1.ResourceCPU}:  4_000,
+			}.Unflatten(),
+			wantBorrowing: sets.New[kueue.ResourceFlavorReference]("tight"),
+		},
+		"unused quota in the free flavor doesn't cover the tight flavor": {
+			cohortRequestable: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: "tight", Resource: corev1.ResourceCPU}: 2_000,
+				{Flavor: "free", Resource: corev1.ResourceCPU}:  8_000,
+			}.Unflatten(),
+			wantMode: Preempt,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors: []cache.FlavorQuotas{
+						{
+							Name: "tight",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 2_000},
+							},
+						},
+						{
+							Name: "free",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 8_000},
+							},
+						},
+					},
+				}},
+				Usage: resources.FlavorResourceQuantitiesFlat{
+					{Flavor: "tight", Resource: corev1.ResourceCPU}: 1_000,
+					{Flavor: "free", Resource: corev1.ResourceCPU}:  2_000,
+				}.Unflatten(),
+				Cohort: &cache.Cohort{
+					RequestableResources: tc.cohortRequestable,
+					Usage: resources.FlavorResourceQuantitiesFlat{
+						{Flavor: "tight", Resource: corev1.ResourceCPU}: 1_000,
+						{Flavor: "free", Resource: corev1.ResourceCPU}:  2_000,
+					}.Unflatten(),
+				},
+				FlavorFungibility: kueue.FlavorFungibility{
+					WhenCanBorrow:  kueue.Borrow,
+					WhenCanPreempt: kueue.TryNextFlavor,
+				},
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("wl", "").
+				PodSets(
+					*utiltesting.MakePodSet("driver", 1).
+						Request(corev1.ResourceCPU, "2").
+						NodeSelector(map[string]string{"pool": "tight"}).
+						Obj(),
+					*utiltesting.MakePodSet("worker", 2).
+						Request(corev1.ResourceCPU, "2").
+						NodeSelector(map[string]string{"pool": "free"}).
+						Obj(),
+				).
+				Obj())
+			assignment := New(wlInfo, &cq, resourceFlavors, false).Assign(log, nil)
+			if got := assignment.RepresentativeMode(); got != tc.wantMode {
+				t.Fatalf("Unexpected assignment mode, want=%v, got=%v", tc.wantMode, got)
+			}
+			if tc.wantMode != Fit {
+				return
+			}
+			if diff := cmp.Diff(tc.wantUsage, assignment.Usage); diff != "" {
+				t.Errorf("Unexpected usage (-want,+got):\n%s", diff)
+			}
+			for fName := range resourceFlavors {
+				if got, want := assignment.BorrowsFlavorResource(fName, corev1.ResourceCPU), tc.wantBorrowing.Has(fName); got != want {
+					t.Errorf("Unexpected borrowing in flavor %s, want=%v, got=%v", fName, want, got)
+				}
+			}
+			if got, want := assignment.Borrowing, tc.wantBorrowing.Len() > 0; got != want {
+				t.Errorf("Unexpected assignment borrowing, want=%v, got=%v", want, got)
+			}
+		})
+	}
+}
+
+func TestAssignFlavorsPreferPreviousFlavor(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"one": utiltesting.MakeResourceFlavor("one").Obj(),
+		"two": utiltesting.MakeResourceFlavor("two").Obj(),
+	}
+	cases := map[string]struct {
+		policy         kueue.ReadmissionFlavorPolicy
+		previousFlavor kueue.ResourceFlavorReference
+		usage          resources.FlavorResourceQuantities
+		wantFlavor     kueue.ResourceFlavorReference
+	}{
+		"previous flavor is reused": {
+			policy:         kueue.ReadmissionFlavorPolicyPreferPrevious,
+			previousFlavor: "two",
+			wantFlavor:     "two",
+		},
+		"another flavor is assigned when the previous one is full": {
+			policy:         kueue.ReadmissionFlavorPolicyPreferPrevious,
+			previousFlavor: "two",
+			usage: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: "two", Resource: corev1.ResourceCPU}: 4_000,
+			}.Unflatten(),
+			wantFlavor: "one",
+		},
+		"another flavor is assigned when the previous one is no longer in the ClusterQueue": {
+			policy:         kueue.ReadmissionFlavorPolicyPreferPrevious,
+			previousFlavor: "three",
+			wantFlavor:     "one",
+		},
+		"previous flavor is ignored without the PreferPrevious policy": {
+			previousFlavor: "two",
+			wantFlavor:     "one",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors: []cache.FlavorQuotas{
+						{
+							Name: "one",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 4_000},
+							},
+						},
+						{
+							Name: "two",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 4_000},
+							},
+						},
+					},
+				}},
+				Usage: tc.usage,
+				FlavorFungibility: kueue.FlavorFungibility{
+					WhenCanBorrow:  kueue.Borrow,
+					WhenCanPreempt: kueue.TryNextFlavor,
+				},
+				ReadmissionFlavorPolicy: tc.policy,
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("wl", "").
+				Request(corev1.ResourceCPU, "2").
+				PreviousFlavors(kueue.PodSetFlavors{
+					Name: kueue.DefaultPodSetName,
+					Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+						corev1.ResourceCPU: tc.previousFlavor,
+					},
+				}).
+				Obj())
+			assignment := New(wlInfo, &cq, resourceFlavors, false).Assign(log, nil)
+			if got := assignment.RepresentativeMode(); got != Fit {
+				t.Fatalf("Unexpected assignment mode, want=%v, got=%v", Fit, got)
+			}
+			if got := assignment.PodSets[0].Flavors[corev1.ResourceCPU].Name; got != tc.wantFlavor {
+				t.Errorf("Unexpected flavor, want=%s, got=%s", tc.wantFlavor, got)
+			}
+		})
+	}
+}
+
+func TestAssignFlavorsNodeSelectorTerm(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"old": utiltesting.MakeResourceFlavor("old").
+			NodeSelectorTerm(corev1.NodeSelectorRequirement{
+				Key:      "example.com/generation",
+				Operator: corev1.NodeSelectorOpLt,
+				Values:   []string{"3"},
+			}).Obj(),
+		"new": utiltesting.MakeResourceFlavor("new").
+			NodeSelectorTerm(corev1.NodeSelectorRequirement{
+				Key:      "example.com/generation",
+				Operator: corev1.NodeSelectorOpGt,
+				Values:   []string{"2"},
+			}).Obj(),
+	}
+	cases := map[string]struct {
+		nodeSelector map[string]string
+		wantFlavor   kueue.ResourceFlavorReference
+	}{
+		"no node selector": {
+			wantFlavor: "old",
+		},
+		"node selector matching the second flavor": {
+			nodeSelector: map[string]string{"example.com/generation": "4"},
+			wantFlavor:   "new",
+		},
+		"node selector with unrelated keys": {
+			nodeSelector: map[string]string{"example.com/zone": "a"},
+			wantFlavor:   "old",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{{
+					CoveredResources: sets.New(corev1.ResourceCPU),
+					Flavors: []cache.FlavorQuotas{
+						{
+							Name: "old",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 4_000},
+							},
+						},
+						{
+							Name: "new",
+							Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+								corev1.ResourceCPU: {Nominal: 4_000},
+							},
+						},
+					},
+				}},
+				FlavorFungibility: kueue.FlavorFungibility{
+					WhenCanBorrow:  kueue.Borrow,
+					WhenCanPreempt: kueue.TryNextFlavor,
+				},
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("wl", "").
+				Request(corev1.ResourceCPU, "2").
+				NodeSelector(tc.nodeSelector).
+				Obj())
+			assignment := New(wlInfo, &cq, resourceFlavors, false).Assign(log, nil)
+			if got := assignment.RepresentativeMode(); got != Fit {
+				t.Fatalf("Unexpected assignment mode, want=%v, got=%v", Fit, got)
+			}
+			if got := assignment.PodSets[0].Flavors[corev1.ResourceCPU].Name; got != tc.wantFlavor {
+				t.Errorf("Unexpected flavor, want=%s, got=%s", tc.wantFlavor, got)
+			}
+		})
+	}
+}
+
+func TestAssignFlavorsMultipleLabelConstraints(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"a100": utiltesting.MakeResourceFlavor("a100").
+			Label("example.com/gpu", "a100").Obj(),
+		"h100-efa": utiltesting.MakeResourceFlavor("h100-efa").
+			Label("example.com/gpu", "h100").
+			Label("example.com/network", "efa").Obj(),
+		"a100-efa": utiltesting.MakeResourceFlavor("a100-efa").
+			Label("example.com/gpu", "a100").
+			Label("example.com/network", "efa").Obj(),
+	}
+	cases := map[string]struct {
+		nodeSelector map[string]string
+		affinity     *corev1.Affinity
+		wantFlavor   kueue.ResourceFlavorReference
+		wantMode     FlavorAssignmentMode
+	}{
+		"only the flavor matching all the node selector labels is selected": {
+			nodeSelector: map[string]string{
+				"example.com/gpu":     "a100",
+				"example.com/network": "efa",
+			},
+			wantFlavor: "a100-efa",
+			wantMode:   Fit,
+		},
+		"only the flavor matching all the affinity requirements is selected": {
+			affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "example.com/gpu", Operator: corev1.NodeSelectorOpIn, Values: []string{"a100"}},
+								{Key: "example.com/network", Operator: corev1.NodeSelectorOpExists},
+							},
+						}},
+					},
+				},
+			},
+			wantFlavor: "a100-efa",
+			wantMode:   Fit,
+		},
+		"no flavor matches all the labels": {
+			nodeSelector: map[string]string{
+				"example.com/gpu":     "h100",
+				"example.com/network": "infiniband",
+			},
+			wantMode: NoFit,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			rg := cache.ResourceGroup{CoveredResources: sets.New(corev1.ResourceCPU)}
+			for _, f := range []kueue.ResourceFlavorReference{"a100", "h100-efa", "a100-efa"} {
+				rg.Flavors = append(rg.Flavors, cache.FlavorQuotas{
+					Name: f,
+					Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+						corev1.ResourceCPU: {Nominal: 4_000},
+					},
+				})
+			}
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{rg},
+				Usage:          resources.FlavorResourceQuantities{},
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			wl := utiltesting.MakeWorkload("wl", "").
+				Request(corev1.ResourceCPU, "1").
+				NodeSelector(tc.nodeSelector).
+				Obj()
+			wl.Spec.PodSets[0].Template.Spec.Affinity = tc.affinity
+			assignment := New(workload.NewInfo(wl), &cq, resourceFlavors, false).Assign(log, nil)
+			if got := assignment.RepresentativeMode(); got != tc.wantMode {
+				t.Fatalf("Unexpected assignment mode, want=%v, got=%v", tc.wantMode, got)
+			}
+			if tc.wantMode != Fit {
+				return
+			}
+			if got := assignment.PodSets[0].Flavors[corev1.ResourceCPU].Name; got != tc.wantFlavor {
+				t.Errorf("Unexpected flavor, want=%s, got=%s", tc.wantFlavor, got)
+			}
+		})
+	}
+}
+
+func TestAssignFlavorsPreferredNodeSelector(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"cold": utiltesting.MakeResourceFlavor("cold").Obj(),
+		"warm": utiltesting.MakeResourceFlavor("warm").Label("example.com/image-cache", "warm").Obj(),
+	}
+	cases := map[string]struct {
+		preferredSelector string
+		usage             resources.FlavorResourceQuantities
+		wantFlavor        kueue.ResourceFlavorReference
+	}{
+		"cache-warm flavor is preferred": {
+			preferredSelector: "example.com/image-cache=warm",
+			wantFlavor:        "warm",
+		},
+		"cold flavor is used when the cache-warm flavor is full": {
+			preferredSelector: "example.com/image-cache=warm",
+			usage: resources.FlavorResourceQuantities{
+				"warm": {corev1.ResourceCPU: 4_000},
+			},
+			wantFlavor: "cold",
+		},
+		"first flavor is used without a preference": {
+			wantFlavor: "cold",
+		},
+		"first flavor is used when no flavor matches the preference": {
+			preferredSelector: "example.com/image-cache=hot",
+			wantFlavor:        "cold",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			rg := cache.ResourceGroup{CoveredResources: sets.New(corev1.ResourceCPU)}
+			for _, f := range []kueue.ResourceFlavorReference{"cold", "warm"} {
+				rg.Flavors = append(rg.Flavors, cache.FlavorQuotas{
+					Name: f,
+					Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+						corev1.ResourceCPU: {Nominal: 4_000},
+					},
+				})
+			}
+			usage := tc.usage
+			if usage == nil {
+				usage = resources.FlavorResourceQuantities{}
+			}
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{rg},
+				Usage:          usage,
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			ps := utiltesting.MakePodSet(kueue.DefaultPodSetName, 1).Request(corev1.ResourceCPU, "1")
+			if tc.preferredSelector != "" {
+				ps.Annotations(map[string]string{kueue.PodSetPreferredNodeSelectorAnnotation: tc.preferredSelector})
+			}
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("wl", "").PodSets(*ps.Obj()).Obj())
+			assignment := New(wlInfo, &cq, resourceFlavors, false).Assign(log, nil)
+			if got := assignment.RepresentativeMode(); got != Fit {
+				t.Fatalf("Unexpected assignment mode, want=%v, got=%v", Fit, got)
+			}
+			if got := assignment.PodSets[0].Flavors[corev1.ResourceCPU].Name; got != tc.wantFlavor {
+				t.Errorf("Unexpected flavor, want=%s, got=%s", tc.wantFlavor, got)
+			}
+		})
+	}
+}
+
+func TestAssignFlavorsRequiredTopology(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"pcie":   utiltesting.MakeResourceFlavor("pcie").Obj(),
+		"nvlink": utiltesting.MakeResourceFlavor("nvlink").Label("nvidia.com/gpu.clique", "a").Obj(),
+	}
+	cases := map[string]struct {
+		requiredTopology string
+		flavors          []kueue.ResourceFlavorReference
+		usage            resources.FlavorResourceQuantities
+		wantMode         FlavorAssignmentMode
+		wantFlavor       kueue.ResourceFlavorReference
+		wantMessage      string
+	}{
+		"first flavor is used without a required topology": {
+			flavors:    []kueue.ResourceFlavorReference{"pcie", "nvlink"},
+			wantMode:   Fit,
+			wantFlavor: "pcie",
+		},
+		"flavor setting the topology label is used": {
+			requiredTopology: "nvidia.com/gpu.clique",
+			flavors:          []kueue.ResourceFlavorReference{"pcie", "nvlink"},
+			wantMode:         Fit,
+			wantFlavor:       "nvlink",
+		},
+		"no flavor sets the topology label": {
+			requiredTopology: "nvidia.com/gpu.clique",
+			flavors:          []kueue.ResourceFlavorReference{"pcie"},
+			wantMode:         NoFit,
+			wantMessage:      "couldn't assign flavors to pod set main: flavor pcie doesn't set the required topology label nvidia.com/gpu.clique",
+		},
+		"no flavor sets all the topology labels": {
+			requiredTopology: "nvidia.com/gpu.clique, example.com/rack",
+			flavors:          []kueue.ResourceFlavorReference{"pcie", "nvlink"},
+			wantMode:         NoFit,
+			wantMessage:      "couldn't assign flavors to pod set main: flavor nvlink doesn't set the required topology label example.com/rack, flavor pcie doesn't set the required topology label nvidia.com/gpu.clique",
+		},
+		"preempting in the compatible flavor when it is full": {
+			requiredTopology: "nvidia.com/gpu.clique",
+			flavors:          []kueue.ResourceFlavorReference{"pcie", "nvlink"},
+			usage: resources.FlavorResourceQuantities{
+				"nvlink": {"example.com/gpu": 4},
+			},
+			wantMode:    Preempt,
+			wantFlavor:  "nvlink",
+			wantMessage: "couldn't assign flavors to pod set main: flavor pcie doesn't set the required topology label nvidia.com/gpu.clique, insufficient unused quota for example.com/gpu in flavor nvlink, 4 more needed",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			log := testr.NewWithOptions(t, testr.Options{
+				Verbosity: 2,
+			})
+			rg := cache.ResourceGroup{CoveredResources: sets.New[corev1.ResourceName]("example.com/gpu")}
+			for _, f := range tc.flavors {
+				rg.Flavors = append(rg.Flavors, cache.FlavorQuotas{
+					Name: f,
+					Resources: map[corev1.ResourceName]*cache.ResourceQuota{
+						"example.com/gpu": {Nominal: 4},
+					},
+				})
+			}
+			usage := tc.usage
+			if usage == nil {
+				usage = resources.FlavorResourceQuantities{}
+			}
+			cq := cache.ClusterQueue{
+				ResourceGroups: []cache.ResourceGroup{rg},
+				Usage:          usage,
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			ps := utiltesting.MakePodSet(kueue.DefaultPodSetName, 1).Request("example.com/gpu", "4")
+			if tc.requiredTopology != "" {
+				ps.Annotations(map[string]string{kueue.PodSetRequiredTopologyAnnotation: tc.requiredTopology})
+			}
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("wl", "").PodSets(*ps.Obj()).Obj())
+			assignment := New(wlInfo, &cq, resourceFlavors, false).Assign(log, nil)
+			if got := assignment.RepresentativeMode(); got != tc.wantMode {
+				t.Fatalf("Unexpected assignment mode, want=%v, got=%v", tc.wantMode, got)
+			}
+			if tc.wantMode != NoFit {
+				if got := assignment.PodSets[0].Flavors["example.com/gpu"].Name; got != tc.wantFlavor {
+					t.Errorf("Unexpected flavor, want=%s, got=%s", tc.wantFlavor, got)
+				}
+			}
+			if got := assignment.Message(); got != tc.wantMessage {
+				t.Errorf("Unexpected message, want=%q, got=%q", tc.wantMessage, got)
+			}
+		})
+	}
+}
+
+func TestLastAssignmentOutdated(t *testing.T) {
+	type args struct {
+		wl *workload.Info
+		cq *cache.ClusterQueue
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Cluster queue allocatableResourceIncreasedGen increased",
+			args: args{
+				wl: &workload.Info{
+					LastAssignment: &workload.AssignmentClusterQueueState{
+						ClusterQueueGeneration: 0,
+					},
+				},
+				cq: &cache.ClusterQueue{
+					Cohort:                        nil,
+					AllocatableResourceGeneration: 1,
 				},
 			},
 			want: true,
@@ -2443,3 +3510,145 @@ func TestLastAssignmentOutdated(t *testing.T) {
 		})
 	}
 }
+
+func TestCandidateFlavors(t *testing.T) {
+	resourceFlavors := map[kueue.ResourceFlavorReference]*kueue.ResourceFlavor{
+		"one": utiltesting.MakeResourceFlavor("one").Label("type", "one").Obj(),
+		"two": utiltesting.MakeResourceFlavor("two").Label("type", "two").Obj(),
+		"tainted": utiltesting.MakeResourceFlavor("tainted").
+			Taint(corev1.Taint{
+				Key:    "instance",
+				Value:  "spot",
+				Effect: corev1.TaintEffectNoSchedule,
+			}).Obj(),
+		"gpu":      utiltesting.MakeResourceFlavor("gpu").Obj(),
+		"cordoned": utiltesting.MakeResourceFlavor("cordoned").Cordoned(true).Obj(),
+	}
+	cpuGroup := cache.ResourceGroup{
+		CoveredResources: sets.New(corev1.ResourceCPU),
+		Flavors: []cache.FlavorQuotas{
+			{Name: "one", Resources: map[corev1.ResourceName]*cache.ResourceQuota{corev1.ResourceCPU: {Nominal: 1000}}},
+			{Name: "two", Resources: map[corev1.ResourceName]*cache.ResourceQuota{corev1.ResourceCPU: {Nominal: 1000}}},
+			{Name: "tainted", Resources: map[corev1.ResourceName]*cache.ResourceQuota{corev1.ResourceCPU: {Nominal: 1000}}},
+		},
+	}
+	gpuGroup := cache.ResourceGroup{
+		CoveredResources: sets.New[corev1.ResourceName]("example.com/gpu"),
+		Flavors: []cache.FlavorQuotas{
+			{Name: "gpu", Resources: map[corev1.ResourceName]*cache.ResourceQuota{"example.com/gpu": {Nominal: 1}}},
+		},
+	}
+
+	cordonedGroup := cache.ResourceGroup{
+		CoveredResources: sets.New(corev1.ResourceCPU),
+		Flavors: []cache.FlavorQuotas{
+			{Name: "cordoned", Resources: map[corev1.ResourceName]*cache.ResourceQuota{corev1.ResourceCPU: {Nominal: 1000}}},
+			{Name: "one", Resources: map[corev1.ResourceName]*cache.ResourceQuota{corev1.ResourceCPU: {Nominal: 1000}}},
+		},
+	}
+
+	cases := map[string]struct {
+		wlPods         []kueue.PodSet
+		resourceGroups []cache.ResourceGroup
+		want           []kueue.PodSetCandidateFlavors
+	}{
+		"all untainted flavors match, regardless of quota": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("main", 10).
+					Request(corev1.ResourceCPU, "10").
+					Obj(),
+			},
+			resourceGroups: []cache.ResourceGroup{cpuGroup},
+			want: []kueue.PodSetCandidateFlavors{
+				{Name: "main", Flavors: []kueue.ResourceFlavorReference{"one", "two"}},
+			},
+		},
+		"node selector and tolerations restrict the flavors": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("driver", 1).
+					Request(corev1.ResourceCPU, "1").
+					NodeSelector(map[string]string{"type": "two"}).
+					Obj(),
+				*utiltesting.MakePodSet("worker", 1).
+					Request(corev1.ResourceCPU, "1").
+					Toleration(corev1.Toleration{
+						Key:      "instance",
+						Operator: corev1.TolerationOpEqual,
+						Value:    "spot",
+						Effect:   corev1.TaintEffectNoSchedule,
+					}).
+					Obj(),
+			},
+			resourceGroups: []cache.ResourceGroup{cpuGroup},
+			want: []kueue.PodSetCandidateFlavors{
+				{Name: "driver", Flavors: []kueue.ResourceFlavorReference{"two"}},
+				{Name: "worker", Flavors: []kueue.ResourceFlavorReference{"one", "two", "tainted"}},
+			},
+		},
+		"flavors from multiple resource groups": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("main", 1).
+					Request(corev1.ResourceCPU, "1").
+					Request("example.com/gpu", "1").
+					NodeSelector(map[string]string{"type": "one"}).
+					Obj(),
+			},
+			resourceGroups: []cache.ResourceGroup{cpuGroup, gpuGroup},
+			want: []kueue.PodSetCandidateFlavors{
+				{Name: "main", Flavors: []kueue.ResourceFlavorReference{"one", "gpu"}},
+			},
+		},
+		"no flavor matches the node selector": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("main", 1).
+					Request(corev1.ResourceCPU, "1").
+					Request("example.com/gpu", "1").
+					NodeSelector(map[string]string{"type": "three"}).
+					Obj(),
+			},
+			resourceGroups: []cache.ResourceGroup{cpuGroup, gpuGroup},
+			want: []kueue.PodSetCandidateFlavors{
+				{Name: "main"},
+			},
+		},
+		"cordoned flavors are skipped": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("main", 1).
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+			},
+			resourceGroups: []cache.ResourceGroup{cordonedGroup},
+			want: []kueue.PodSetCandidateFlavors{
+				{Name: "main", Flavors: []kueue.ResourceFlavorReference{"one"}},
+			},
+		},
+		"requested resource not covered by the ClusterQueue": {
+			wlPods: []kueue.PodSet{
+				*utiltesting.MakePodSet("main", 1).
+					Request(corev1.ResourceCPU, "1").
+					Request("example.com/gpu", "1").
+					Obj(),
+			},
+			resourceGroups: []cache.ResourceGroup{cpuGroup},
+			want: []kueue.PodSetCandidateFlavors{
+				{Name: "main"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cq := cache.ClusterQueue{
+				ResourceGroups: tc.resourceGroups,
+				Usage:          resources.FlavorResourceQuantities{},
+			}
+			cq.UpdateWithFlavors(resourceFlavors)
+			cq.UpdateRGByResource()
+
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("wl", "").PodSets(tc.wlPods...).Obj())
+			got := New(wlInfo, &cq, resourceFlavors, false).CandidateFlavors()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected candidate flavors (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
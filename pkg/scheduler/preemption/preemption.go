@@ -19,7 +19,10 @@ package preemption
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -30,16 +33,20 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	config "sigs.k8s.io/kueue/apis/config/v1beta1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/features"
 	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/resources"
 	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
 	"sigs.k8s.io/kueue/pkg/util/heap"
+	utilmaps "sigs.k8s.io/kueue/pkg/util/maps"
 	"sigs.k8s.io/kueue/pkg/util/priority"
 	"sigs.k8s.io/kueue/pkg/util/routine"
 	"sigs.k8s.io/kueue/pkg/workload"
@@ -47,6 +54,8 @@ import (
 
 const parallelPreemptions = 8
 
+var realClock = clock.RealClock{}
+
 type Preemptor struct {
 	client   client.Client
 	recorder record.EventRecorder
@@ -54,20 +63,35 @@ type Preemptor struct {
 	workloadOrdering  workload.Ordering
 	enableFairSharing bool
 	fsStrategies      []fsStrategy
+	evictionWeights   evictionWeights
+	clock             clock.Clock
+
+	// lastReclaims holds, per ClusterQueue, the last time that its workloads
+	// were preempted to reclaim quota in the cohort.
+	lastReclaimsMu sync.Mutex
+	lastReclaims   map[string]time.Time
 
 	// stubs
 	applyPreemption func(context.Context, *kueue.Workload, string, string) error
+	applyScaleDown  func(context.Context, *kueue.Workload) error
 }
 
-func New(cl client.Client, workloadOrdering workload.Ordering, recorder record.EventRecorder, fs config.FairSharing) *Preemptor {
+func New(cl client.Client, workloadOrdering workload.Ordering, recorder record.EventRecorder, fs config.FairSharing, eo config.EvictionOrdering) *Preemptor {
 	p := &Preemptor{
 		client:            cl,
 		recorder:          recorder,
 		workloadOrdering:  workloadOrdering,
 		enableFairSharing: fs.Enable,
 		fsStrategies:      parseStrategies(fs.PreemptionStrategies),
+		evictionWeights: evictionWeights{
+			priority: int64(ptr.Deref(eo.PriorityWeight, 1)),
+			runtime:  int64(ptr.Deref(eo.RuntimeWeight, 0)),
+		},
+		clock:        realClock,
+		lastReclaims: make(map[string]time.Time),
 	}
 	p.applyPreemption = p.applyPreemptionWithSSA
+	p.applyScaleDown = p.applyScaleDownWithSSA
 	return p
 }
 
@@ -100,11 +124,12 @@ func (p *Preemptor) GetTargets(wl workload.Info, assignment flavorassigner.Assig
 	resPerFlv := resourcesRequiringPreemption(assignment)
 	cq := snapshot.ClusterQueues[wl.ClusterQueue]
 
-	candidates := findCandidates(wl.Obj, p.workloadOrdering, cq, resPerFlv)
+	now := p.clock.Now()
+	candidates := findCandidates(wl.Obj, p.workloadOrdering, cq, resPerFlv, now, p.inReclaimCooldown)
 	if len(candidates) == 0 {
 		return nil
 	}
-	sort.Slice(candidates, candidatesOrdering(candidates, cq.Name, time.Now()))
+	sort.Slice(candidates, candidatesOrdering(candidates, cq.Name, now, p.evictionWeights))
 
 	sameQueueCandidates := candidatesOnlyFromQueue(candidates, wl.ClusterQueue)
 	wlReq := assignment.TotalRequestsFor(&wl)
@@ -163,7 +188,8 @@ func canBorrowWithinCohort(cq *cache.ClusterQueue, wl *kueue.Workload) (bool, *i
 	return true, &threshold
 }
 
-// IssuePreemptions marks the target workloads as evicted.
+// IssuePreemptions marks the target workloads as evicted, or scales down the
+// targets that reclaim quota by scaling them down.
 func (p *Preemptor) IssuePreemptions(ctx context.Context, preemptor *workload.Info, targets []*workload.Info, cq *cache.ClusterQueue) (int, error) {
 	log := ctrl.LoggerFrom(ctx)
 	errCh := routine.NewErrorChannel()
@@ -172,6 +198,18 @@ func (p *Preemptor) IssuePreemptions(ctx context.Context, preemptor *workload.In
 	defer cancel()
 	workqueue.ParallelizeUntil(ctx, parallelPreemptions, len(targets), func(i int) {
 		target := targets[i]
+		if len(target.ScaledDownCounts) > 0 && !meta.IsStatusConditionTrue(target.Obj.Status.Conditions, kueue.WorkloadEvicted) {
+			if err := p.applyScaleDown(ctx, target.Obj); err != nil {
+				errCh.SendErrorWithCancel(err, cancel)
+				return
+			}
+			p.recordReclaim(target.ClusterQueue)
+			message := fmt.Sprintf("Scaled down to %s to accommodate a workload (UID: %s) in the cohort", scaleDownCountsMessage(target.ScaledDownCounts), preemptor.Obj.UID)
+			log.V(3).Info("Scaled down", "targetWorkload", klog.KObj(target.Obj), "message", message)
+			p.recorder.Eventf(target.Obj, corev1.EventTypeNormal, "ScaledDown", message)
+			atomic.AddInt64(&successfullyPreempted, 1)
+			return
+		}
 		if !meta.IsStatusConditionTrue(target.Obj.Status.Conditions, kueue.WorkloadEvicted) {
 			origin := "ClusterQueue"
 			reason := "InClusterQueue"
@@ -187,9 +225,13 @@ func (p *Preemptor) IssuePreemptions(ctx context.Context, preemptor *workload.In
 				return
 			}
 
+			if cq.Name != target.ClusterQueue {
+				p.recordReclaim(target.ClusterQueue)
+			}
 			log.V(3).Info("Preempted", "targetWorkload", klog.KObj(target.Obj), "reason", reason, "message", message)
 			p.recorder.Eventf(target.Obj, corev1.EventTypeNormal, "Preempted", message)
 			metrics.ReportEvictedWorkloads(target.ClusterQueue, kueue.WorkloadEvictedByPreemption)
+			metrics.ReportPreemption(cq.Name, reason)
 		} else {
 			log.V(3).Info("Preemption ongoing", "targetWorkload", klog.KObj(target.Obj))
 		}
@@ -198,6 +240,24 @@ func (p *Preemptor) IssuePreemptions(ctx context.Context, preemptor *workload.In
 	return int(successfullyPreempted), errCh.ReceiveError()
 }
 
+func (p *Preemptor) recordReclaim(cqName string) {
+	p.lastReclaimsMu.Lock()
+	defer p.lastReclaimsMu.Unlock()
+	p.lastReclaims[cqName] = p.clock.Now()
+}
+
+// inReclaimCooldown returns whether the workloads of the ClusterQueue were
+// preempted to reclaim quota within its reclaim cooldown.
+func (p *Preemptor) inReclaimCooldown(cq *cache.ClusterQueue, now time.Time) bool {
+	if cq.Preemption.ReclaimCooldown == nil || cq.Preemption.ReclaimCooldown.Duration <= 0 {
+		return false
+	}
+	p.lastReclaimsMu.Lock()
+	defer p.lastReclaimsMu.Unlock()
+	lastReclaim, found := p.lastReclaims[cq.Name]
+	return found && now.Sub(lastReclaim) < cq.Preemption.ReclaimCooldown.Duration
+}
+
 func (p *Preemptor) applyPreemptionWithSSA(ctx context.Context, w *kueue.Workload, reason, message string) error {
 	w = w.DeepCopy()
 	workload.SetEvictedCondition(w, kueue.WorkloadEvictedByPreemption, message)
@@ -205,6 +265,21 @@ func (p *Preemptor) applyPreemptionWithSSA(ctx context.Context, w *kueue.Workloa
 	return workload.ApplyAdmissionStatus(ctx, p.client, w, true)
 }
 
+func scaleDownCountsMessage(counts map[string]int32) string {
+	names := utilmaps.Keys(counts)
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, counts[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// applyScaleDownWithSSA applies the scaled down admission of the target.
+func (p *Preemptor) applyScaleDownWithSSA(ctx context.Context, w *kueue.Workload) error {
+	return workload.ApplyAdmissionStatus(ctx, p.client, w.DeepCopy(), true)
+}
+
 // minimalPreemptions implements a heuristic to find a minimal set of Workloads
 // to preempt.
 // The heuristic first removes candidates, in the input order, while their
@@ -213,7 +288,13 @@ func (p *Preemptor) applyPreemptionWithSSA(ctx context.Context, w *kueue.Workloa
 // Once the Workload fits, the heuristic tries to add Workloads back, in the
 // reverse order in which they were removed, while the incoming Workload still
 // fits.
+// When the PartialAdmissionScaleDown feature is enabled and the quota is
+// reclaimed without borrowing, the candidates in other ClusterQueues that run
+// more pods than their minCount are scaled down, only as much as needed,
+// instead of removed.
 func minimalPreemptions(wlReq resources.FlavorResourceQuantities, cq *cache.ClusterQueue, snapshot *cache.Snapshot, resPerFlv resourcesPerFlavor, candidates []*workload.Info, allowBorrowing bool, allowBorrowingBelowPriority *int32) []*workload.Info {
+	reclaim := !allowBorrowing && features.Enabled(features.PartialAdmissionScaleDown)
+	scaledDown := make(scaledDownTargets)
 	// Simulate removing all candidates from the ClusterQueue and cohort.
 	var targets []*workload.Info
 	fits := false
@@ -237,40 +318,158 @@ func minimalPreemptions(wlReq resources.FlavorResourceQuantities, cq *cache.Clus
 			// the function.
 			allowBorrowing = false
 		}
-		snapshot.RemoveWorkload(candWl)
-		targets = append(targets, candWl)
+		target := candWl
+		if reclaim && cq != candCQ && !meta.IsStatusConditionTrue(candWl.Obj.Status.Conditions, kueue.WorkloadEvicted) {
+			if scaled := scaleDownToFit(wlReq, cq, snapshot, candWl, allowBorrowing); scaled != nil {
+				scaledDown[scaled] = candWl
+				target = scaled
+			}
+		}
+		if target == candWl {
+			snapshot.RemoveWorkload(candWl)
+		}
+		targets = append(targets, target)
 		if workloadFits(wlReq, cq, allowBorrowing) {
 			fits = true
 			break
 		}
 	}
+	// Scaling down the candidates to their minCounts wasn't enough, so evict
+	// them instead, in order.
+	for i := 0; i < len(targets) && !fits; i++ {
+		original, found := scaledDown[targets[i]]
+		if !found {
+			continue
+		}
+		snapshot.RemoveWorkload(targets[i])
+		delete(scaledDown, targets[i])
+		targets[i] = original
+		fits = workloadFits(wlReq, cq, allowBorrowing)
+	}
 	if !fits {
-		restoreSnapshot(snapshot, targets)
+		restoreSnapshot(snapshot, targets, scaledDown)
 		return nil
 	}
-	targets = fillBackWorkloads(targets, wlReq, cq, snapshot, allowBorrowing)
-	restoreSnapshot(snapshot, targets)
+	targets = fillBackWorkloads(targets, wlReq, cq, snapshot, allowBorrowing, scaledDown)
+	restoreSnapshot(snapshot, targets, scaledDown)
 	return targets
 }
 
-func fillBackWorkloads(targets []*workload.Info, wlReq resources.FlavorResourceQuantities, cq *cache.ClusterQueue, snapshot *cache.Snapshot, allowBorrowing bool) []*workload.Info {
+// scaledDownTargets maps the targets that are scaled down, instead of
+// removed, to the original workloads.
+type scaledDownTargets map[*workload.Info]*workload.Info
+
+// remove simulates the removal of the target from the snapshot, which is
+// the replacement of the original workload by the scaled down one for the
+// targets that are scaled down.
+func (s scaledDownTargets) remove(snapshot *cache.Snapshot, target *workload.Info) {
+	if original, found := s[target]; found {
+		snapshot.RemoveWorkload(original)
+		snapshot.AddWorkload(target)
+		return
+	}
+	snapshot.RemoveWorkload(target)
+}
+
+// addBack reverts the removal of the target from the snapshot.
+func (s scaledDownTargets) addBack(snapshot *cache.Snapshot, target *workload.Info) {
+	if original, found := s[target]; found {
+		snapshot.RemoveWorkload(target)
+		snapshot.AddWorkload(original)
+		return
+	}
+	snapshot.AddWorkload(target)
+}
+
+// scaleDownToFit simulates scaling down the candidate, which uses quota
+// reclaimed from another ClusterQueue, only as much as needed for the
+// incoming workload to fit, given the usage of the minCounts stays. The
+// podSets are scaled down in order, each one to the highest count that lets
+// the incoming workload fit, or to its minCount otherwise.
+// Returns the scaled down workload, which replaces the candidate in the
+// snapshot, or nil, leaving the snapshot unchanged, if the candidate can't be
+// scaled down.
+func scaleDownToFit(wlReq resources.FlavorResourceQuantities, cq *cache.ClusterQueue, snapshot *cache.Snapshot, cand *workload.Info, allowBorrowing bool) *workload.Info {
+	minCounts := workload.ScaleDownCounts(cand.Obj)
+	if len(minCounts) == 0 {
+		return nil
+	}
+	fitsWith := func(counts map[string]int32) bool {
+		scaled := scaledDownInfo(cand, counts)
+		snapshot.AddWorkload(scaled)
+		defer snapshot.RemoveWorkload(scaled)
+		return workloadFits(wlReq, cq, allowBorrowing)
+	}
+	snapshot.RemoveWorkload(cand)
+	counts := make(map[string]int32, len(minCounts))
+	for _, ps := range cand.TotalRequests {
+		minCount, found := minCounts[ps.Name]
+		if !found {
+			continue
+		}
+		counts[ps.Name] = minCount
+		if !fitsWith(counts) {
+			continue
+		}
+		// The incoming workload fits with the minCount but not with the
+		// current count, find the highest count in between that fits.
+		low, high := minCount, ps.Count
+		for high-low > 1 {
+			mid := low + (high-low)/2
+			counts[ps.Name] = mid
+			if fitsWith(counts) {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		counts[ps.Name] = low
+		break
+	}
+	scaled := scaledDownInfo(cand, counts)
+	snapshot.AddWorkload(scaled)
+	return scaled
+}
+
+// scaledDownInfo returns a copy of the workload with the podSets scaled down
+// to the given counts.
+func scaledDownInfo(wl *workload.Info, counts map[string]int32) *workload.Info {
+	obj := wl.Obj.DeepCopy()
+	workload.ScaleDownAdmission(obj, counts)
+	scaled := &workload.Info{
+		Obj:              obj,
+		TotalRequests:    make([]workload.PodSetResources, 0, len(wl.TotalRequests)),
+		ClusterQueue:     wl.ClusterQueue,
+		ScaledDownCounts: maps.Clone(counts),
+	}
+	for i := range wl.TotalRequests {
+		ps := &wl.TotalRequests[i]
+		if count, found := counts[ps.Name]; found {
+			ps = ps.ScaledTo(count)
+		}
+		scaled.TotalRequests = append(scaled.TotalRequests, *ps)
+	}
+	return scaled
+}
+
+func fillBackWorkloads(targets []*workload.Info, wlReq resources.FlavorResourceQuantities, cq *cache.ClusterQueue, snapshot *cache.Snapshot, allowBorrowing bool, scaledDown scaledDownTargets) []*workload.Info {
 	// In the reverse order, check if any of the workloads can be added back.
 	for i := len(targets) - 2; i >= 0; i-- {
-		snapshot.AddWorkload(targets[i])
+		scaledDown.addBack(snapshot, targets[i])
 		if workloadFits(wlReq, cq, allowBorrowing) {
 			// O(1) deletion: copy the last element into index i and reduce size.
 			targets[i] = targets[len(targets)-1]
 			targets = targets[:len(targets)-1]
 		} else {
-			snapshot.RemoveWorkload(targets[i])
+			scaledDown.remove(snapshot, targets[i])
 		}
 	}
 	return targets
 }
 
-func restoreSnapshot(snapshot *cache.Snapshot, targets []*workload.Info) {
+func restoreSnapshot(snapshot *cache.Snapshot, targets []*workload.Info, scaledDown scaledDownTargets) {
 	for _, t := range targets {
-		snapshot.AddWorkload(t)
+		scaledDown.addBack(snapshot, t)
 	}
 }
 
@@ -377,11 +576,11 @@ func (p *Preemptor) fairPreemptions(wl *workload.Info, assignment flavorassigner
 		}
 	}
 	if !fits {
-		restoreSnapshot(snapshot, targets)
+		restoreSnapshot(snapshot, targets, nil)
 		return nil
 	}
-	targets = fillBackWorkloads(targets, wlReq, nominatedCQ, snapshot, true)
-	restoreSnapshot(snapshot, targets)
+	targets = fillBackWorkloads(targets, wlReq, nominatedCQ, snapshot, true, nil)
+	restoreSnapshot(snapshot, targets, nil)
 	return targets
 }
 
@@ -441,7 +640,10 @@ func resourcesRequiringPreemption(assignment flavorassigner.Assignment) resource
 // findCandidates obtains candidates for preemption within the ClusterQueue and
 // cohort that respect the preemption policy and are using a resource that the
 // preempting workload needs.
-func findCandidates(wl *kueue.Workload, wo workload.Ordering, cq *cache.ClusterQueue, resPerFlv resourcesPerFlavor) []*workload.Info {
+// Workloads from other ClusterQueues in the cohort are not candidates during
+// the reclaim delay of the cohort, nor during the reclaim cooldown of their
+// ClusterQueues.
+func findCandidates(wl *kueue.Workload, wo workload.Ordering, cq *cache.ClusterQueue, resPerFlv resourcesPerFlavor, now time.Time, inReclaimCooldown func(*cache.ClusterQueue, time.Time) bool) []*workload.Info {
 	var candidates []*workload.Info
 	wlPriority := priority.Priority(wl)
 
@@ -472,6 +674,9 @@ func findCandidates(wl *kueue.Workload, wo workload.Ordering, cq *cache.ClusterQ
 				// Can't reclaim quota from itself or ClusterQueues that are not borrowing.
 				continue
 			}
+			if inReclaimCooldown(cohortCQ, now) {
+				continue
+			}
 			onlyLowerPrio := true
 			if cq.Preemption.ReclaimWithinCohort == kueue.PreemptionPolicyAny {
 				onlyLowerPrio = false
@@ -483,6 +688,9 @@ func findCandidates(wl *kueue.Workload, wo workload.Ordering, cq *cache.ClusterQ
 				if !workloadUsesResources(candidateWl, resPerFlv) {
 					continue
 				}
+				if delay := cq.ReclaimDelay(cohortCQ); delay > 0 && now.Sub(quotaReservationTime(candidateWl.Obj, now)) < delay {
+					continue
+				}
 				candidates = append(candidates, candidateWl)
 			}
 		}
@@ -547,12 +755,8 @@ func workloadFits(wlReq resources.FlavorResourceQuantities, cq *cache.ClusterQue
 					}
 				}
 
-				if cq.Cohort != nil {
-					cohortResUsage := cq.UsedCohortQuota(flvQuotas.Name, rName)
-					requestableQuota := cq.RequestableCohortQuota(flvQuotas.Name, rName)
-					if cohortResUsage+rReq > requestableQuota {
-						return false
-					}
+				if cq.Cohort != nil && rReq > cq.CohortAvailable(flvQuotas.Name, rName, cqResUsage[rName]+rReq > resource.Nominal) {
+					return false
 				}
 			}
 		}
@@ -579,13 +783,28 @@ func queueUnderNominalInAllRequestedResources(wlReq resources.FlavorResourceQuan
 	return true
 }
 
+// evictionWeights are the weights of the priority and of every minute of
+// elapsed runtime in the eviction score of a candidate.
+type evictionWeights struct {
+	priority int64
+	runtime  int64
+}
+
+// score returns the eviction score of a workload with priority p that
+// reserved quota at reservationTime.
+func (w evictionWeights) score(p int32, reservationTime, now time.Time) int64 {
+	return w.priority*int64(p) + w.runtime*int64(now.Sub(reservationTime)/time.Minute)
+}
+
 // candidatesOrdering criteria:
 // 0. Workloads already marked for preemption first.
 // 1. Workloads from other ClusterQueues in the cohort before the ones in the
 // same ClusterQueue as the preemptor.
-// 2. Workloads with lower priority first.
-// 3. Workloads admitted more recently first.
-func candidatesOrdering(candidates []*workload.Info, cq string, now time.Time) func(int, int) bool {
+// 2. Workloads with lower eviction score, combining priority and elapsed
+// runtime, first.
+// 3. Workloads with lower priority first.
+// 4. Workloads admitted more recently first.
+func candidatesOrdering(candidates []*workload.Info, cq string, now time.Time, weights evictionWeights) func(int, int) bool {
 	return func(i, j int) bool {
 		a := candidates[i]
 		b := candidates[j]
@@ -601,11 +820,16 @@ func candidatesOrdering(candidates []*workload.Info, cq string, now time.Time) f
 		}
 		pa := priority.Priority(a.Obj)
 		pb := priority.Priority(b.Obj)
+		timeA := quotaReservationTime(a.Obj, now)
+		timeB := quotaReservationTime(b.Obj, now)
+		scoreA := weights.score(pa, timeA, now)
+		scoreB := weights.score(pb, timeB, now)
+		if scoreA != scoreB {
+			return scoreA < scoreB
+		}
 		if pa != pb {
 			return pa < pb
 		}
-		timeA := quotaReservationTime(a.Obj, now)
-		timeB := quotaReservationTime(b.Obj, now)
 		if !timeA.Equal(timeB) {
 			return timeA.After(timeB)
 		}
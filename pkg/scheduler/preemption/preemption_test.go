@@ -18,6 +18,7 @@ package preemption
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"sync"
 	"testing"
@@ -32,22 +33,26 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
 
 	config "sigs.k8s.io/kueue/apis/config/v1beta1"
+	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/cache"
 	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/features"
+	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
 	"sigs.k8s.io/kueue/pkg/util/slices"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	testingmetrics "sigs.k8s.io/kueue/pkg/util/testing/metrics"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
 var snapCmpOpts = []cmp.Option{
 	cmpopts.EquateEmpty(),
-	cmpopts.IgnoreUnexported(cache.ClusterQueue{}),
+	cmpopts.IgnoreUnexported(cache.ClusterQueue{}, cache.Cohort{}),
 	cmpopts.IgnoreFields(cache.Cohort{}, "AllocatableResourceGeneration"),
 	cmpopts.IgnoreFields(cache.ClusterQueue{}, "AllocatableResourceGeneration"),
 	cmp.Transformer("Cohort.Members", func(s sets.Set[*cache.ClusterQueue]) sets.Set[string] {
@@ -1404,7 +1409,7 @@ func TestPreemption(t *testing.T) {
 			broadcaster := record.NewBroadcaster()
 			scheme := runtime.NewScheme()
 			recorder := broadcaster.NewRecorder(scheme, corev1.EventSource{Component: constants.AdmissionName})
-			preemptor := New(cl, workload.Ordering{}, recorder, config.FairSharing{})
+			preemptor := New(cl, workload.Ordering{}, recorder, config.FairSharing{}, config.EvictionOrdering{})
 			preemptor.applyPreemption = func(ctx context.Context, w *kueue.Workload, _, _ string) error {
 				lock.Lock()
 				gotPreempted.Insert(workload.Key(w))
@@ -1436,6 +1441,431 @@ func TestPreemption(t *testing.T) {
 	}
 }
 
+func TestReclaimDelay(t *testing.T) {
+	admissionTime := time.Now().Truncate(time.Second)
+	flavors := []*kueue.ResourceFlavor{
+		utiltesting.MakeResourceFlavor("default").Obj(),
+	}
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("lender").
+			Cohort("cohort").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+				Resource(corev1.ResourceCPU, "4").Obj()).
+			Preemption(kueue.ClusterQueuePreemption{
+				ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+			}).
+			Obj(),
+		utiltesting.MakeClusterQueue("borrower").
+			Cohort("cohort").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+				Resource(corev1.ResourceCPU, "0").Obj()).
+			Obj(),
+	}
+	admitted := []kueue.Workload{
+		*utiltesting.MakeWorkload("borrowing", "").
+			Request(corev1.ResourceCPU, "4").
+			ReserveQuotaAt(utiltesting.MakeAdmission("borrower").Assignment(corev1.ResourceCPU, "default", "4").Obj(), admissionTime).
+			Obj(),
+	}
+	cases := map[string]struct {
+		reclaimDelay  *metav1.Duration
+		sinceAdmitted time.Duration
+		wantPreempted sets.Set[string]
+	}{
+		"no reclaim delay": {
+			sinceAdmitted: time.Minute,
+			wantPreempted: sets.New("/borrowing"),
+		},
+		"borrowing workload protected within the delay": {
+			reclaimDelay:  &metav1.Duration{Duration: 5 * time.Minute},
+			sinceAdmitted: time.Minute,
+		},
+		"borrowing workload reclaimable after the delay": {
+			reclaimDelay:  &metav1.Duration{Duration: 5 * time.Minute},
+			sinceAdmitted: 5 * time.Minute,
+			wantPreempted: sets.New("/borrowing"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := utiltesting.ContextWithLog(t)
+			cl := utiltesting.NewClientBuilder().
+				WithLists(&kueue.WorkloadList{Items: admitted}).
+				Build()
+
+			cqCache := cache.New(cl)
+			for _, flv := range flavors {
+				cqCache.AddOrUpdateResourceFlavor(flv)
+			}
+			cqCache.AddOrUpdateCohort(&kueuealpha.Cohort{
+				ObjectMeta: metav1.ObjectMeta{Name: "cohort"},
+				Spec:       kueuealpha.CohortSpec{ReclaimDelay: tc.reclaimDelay},
+			})
+			for _, cq := range clusterQueues {
+				if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+					t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+				}
+			}
+
+			var lock sync.Mutex
+			gotPreempted := sets.New[string]()
+			broadcaster := record.NewBroadcaster()
+			scheme := runtime.NewScheme()
+			recorder := broadcaster.NewRecorder(scheme, corev1.EventSource{Component: constants.AdmissionName})
+			preemptor := New(cl, workload.Ordering{}, recorder, config.FairSharing{}, config.EvictionOrdering{})
+			preemptor.clock = testingclock.NewFakeClock(admissionTime.Add(tc.sinceAdmitted))
+			preemptor.applyPreemption = func(ctx context.Context, w *kueue.Workload, _, _ string) error {
+				lock.Lock()
+				gotPreempted.Insert(workload.Key(w))
+				lock.Unlock()
+				return nil
+			}
+
+			snapshot := cqCache.Snapshot()
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("in", "").
+				Request(corev1.ResourceCPU, "4").
+				Obj())
+			wlInfo.ClusterQueue = "lender"
+			targets := preemptor.GetTargets(*wlInfo, singlePodSetAssignment(flavorassigner.ResourceAssignment{
+				corev1.ResourceCPU: &flavorassigner.FlavorAssignment{
+					Name: "default",
+					Mode: flavorassigner.Preempt,
+				},
+			}), &snapshot)
+			if _, err := preemptor.IssuePreemptions(ctx, wlInfo, targets, snapshot.ClusterQueues["lender"]); err != nil {
+				t.Fatalf("Failed doing preemption")
+			}
+			if diff := cmp.Diff(tc.wantPreempted, gotPreempted, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Issued preemptions (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReclaimCooldown(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	now := time.Now().Truncate(time.Second)
+	borrowerPreemption := kueue.ClusterQueuePreemption{
+		ReclaimCooldown: &metav1.Duration{Duration: 5 * time.Minute},
+	}
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("lender").
+			Cohort("cohort").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+				Resource(corev1.ResourceCPU, "4").Obj()).
+			Preemption(kueue.ClusterQueuePreemption{
+				ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+			}).
+			Obj(),
+		utiltesting.MakeClusterQueue("borrower-a").
+			Cohort("cohort").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+				Resource(corev1.ResourceCPU, "0").Obj()).
+			Preemption(borrowerPreemption).
+			Obj(),
+		utiltesting.MakeClusterQueue("borrower-b").
+			Cohort("cohort").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+				Resource(corev1.ResourceCPU, "0").Obj()).
+			Preemption(borrowerPreemption).
+			Obj(),
+	}
+	admitted := []kueue.Workload{
+		*utiltesting.MakeWorkload("a", "").
+			Priority(-1).
+			Request(corev1.ResourceCPU, "2").
+			ReserveQuotaAt(utiltesting.MakeAdmission("borrower-a").Assignment(corev1.ResourceCPU, "default", "2").Obj(), now).
+			Obj(),
+		*utiltesting.MakeWorkload("b", "").
+			Request(corev1.ResourceCPU, "2").
+			ReserveQuotaAt(utiltesting.MakeAdmission("borrower-b").Assignment(corev1.ResourceCPU, "default", "2").Obj(), now).
+			Obj(),
+	}
+	cl := utiltesting.NewClientBuilder().
+		WithLists(&kueue.WorkloadList{Items: admitted}).
+		Build()
+	cqCache := cache.New(cl)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	for _, cq := range clusterQueues {
+		if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+		}
+	}
+
+	metrics.ClearQueueSystemMetrics("lender")
+
+	fakeClock := testingclock.NewFakeClock(now)
+	preemptor := New(cl, workload.Ordering{}, &utiltesting.EventRecorder{}, config.FairSharing{}, config.EvictionOrdering{})
+	preemptor.clock = fakeClock
+	preemptor.applyPreemption = func(context.Context, *kueue.Workload, string, string) error {
+		return nil
+	}
+	// The preempted workloads are kept in the cache, as if the lender
+	// ClusterQueue got new workloads while they were terminating.
+	preempt := func(name string) []string {
+		t.Helper()
+		snapshot := cqCache.Snapshot()
+		wlInfo := workload.NewInfo(utiltesting.MakeWorkload(name, "").
+			Request(corev1.ResourceCPU, "2").
+			Obj())
+		wlInfo.ClusterQueue = "lender"
+		targets := preemptor.GetTargets(*wlInfo, singlePodSetAssignment(flavorassigner.ResourceAssignment{
+			corev1.ResourceCPU: &flavorassigner.FlavorAssignment{
+				Name: "default",
+				Mode: flavorassigner.Preempt,
+			},
+		}), &snapshot)
+		if _, err := preemptor.IssuePreemptions(ctx, wlInfo, targets, snapshot.ClusterQueues["lender"]); err != nil {
+			t.Fatalf("Failed doing preemption: %v", err)
+		}
+		var got []string
+		for _, target := range targets {
+			got = append(got, workload.Key(target.Obj))
+		}
+		return got
+	}
+
+	if diff := cmp.Diff([]string{"/a"}, preempt("first")); diff != "" {
+		t.Errorf("Unexpected targets of the first preemption (-want,+got):\n%s", diff)
+	}
+	fakeClock.Step(time.Minute)
+	if diff := cmp.Diff([]string{"/b"}, preempt("second")); diff != "" {
+		t.Errorf("Unexpected targets within the cooldown of borrower-a (-want,+got):\n%s", diff)
+	}
+	fakeClock.Step(time.Minute)
+	if diff := cmp.Diff([]string(nil), preempt("third")); diff != "" {
+		t.Errorf("Unexpected targets within the cooldown of both borrowers (-want,+got):\n%s", diff)
+	}
+	fakeClock.Step(3 * time.Minute)
+	if diff := cmp.Diff([]string{"/a"}, preempt("fourth")); diff != "" {
+		t.Errorf("Unexpected targets after the cooldown of borrower-a (-want,+got):\n%s", diff)
+	}
+
+	wantReclaims := []testingmetrics.MetricDataPoint{
+		{Labels: map[string]string{"preempting_cluster_queue": "lender", "reason": "InCohort"}, Value: 3},
+	}
+	gotReclaims := testingmetrics.CollectFilteredGaugeVec(metrics.PreemptedWorkloadsTotal, map[string]string{"preempting_cluster_queue": "lender"})
+	if diff := cmp.Diff(wantReclaims, gotReclaims); diff != "" {
+		t.Errorf("Unexpected reclaims metric (-want,+got):\n%s", diff)
+	}
+}
+
+func TestIssuePreemptionsMetric(t *testing.T) {
+	ctx, _ := utiltesting.ContextWithLog(t)
+	cq := utiltesting.MakeClusterQueue("preemption-metric").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+			Resource(corev1.ResourceCPU, "4").Obj()).
+		Preemption(kueue.ClusterQueuePreemption{
+			WithinClusterQueue: kueue.PreemptionPolicyLowerPriority,
+		}).
+		Obj()
+	admitted := []kueue.Workload{
+		*utiltesting.MakeWorkload("low", "").
+			Priority(-1).
+			Request(corev1.ResourceCPU, "4").
+			ReserveQuota(utiltesting.MakeAdmission(cq.Name).Assignment(corev1.ResourceCPU, "default", "4").Obj()).
+			Obj(),
+	}
+	cl := utiltesting.NewClientBuilder().
+		WithLists(&kueue.WorkloadList{Items: admitted}).
+		Build()
+	cqCache := cache.New(cl)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+	}
+	metrics.ClearQueueSystemMetrics(cq.Name)
+
+	preemptor := New(cl, workload.Ordering{}, &utiltesting.EventRecorder{}, config.FairSharing{}, config.EvictionOrdering{})
+	preemptor.applyPreemption = func(context.Context, *kueue.Workload, string, string) error {
+		return nil
+	}
+	snapshot := cqCache.Snapshot()
+	wlInfo := workload.NewInfo(utiltesting.MakeWorkload("high", "").
+		Priority(1).
+		Request(corev1.ResourceCPU, "4").
+		Obj())
+	wlInfo.ClusterQueue = cq.Name
+	targets := preemptor.GetTargets(*wlInfo, singlePodSetAssignment(flavorassigner.ResourceAssignment{
+		corev1.ResourceCPU: &flavorassigner.FlavorAssignment{
+			Name: "default",
+			Mode: flavorassigner.Preempt,
+		},
+	}), &snapshot)
+	if _, err := preemptor.IssuePreemptions(ctx, wlInfo, targets, snapshot.ClusterQueues[cq.Name]); err != nil {
+		t.Fatalf("Failed doing preemption: %v", err)
+	}
+
+	want := []testingmetrics.MetricDataPoint{
+		{Labels: map[string]string{"cluster_queue": cq.Name, "reason": kueue.WorkloadEvictedByPreemption}, Value: 1},
+	}
+	got := testingmetrics.CollectFilteredGaugeVec(metrics.EvictedWorkloadsTotal, map[string]string{"cluster_queue": cq.Name})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected preemptions metric (-want,+got):\n%s", diff)
+	}
+	want = []testingmetrics.MetricDataPoint{
+		{Labels: map[string]string{"preempting_cluster_queue": cq.Name, "reason": "InClusterQueue"}, Value: 1},
+	}
+	got = testingmetrics.CollectFilteredGaugeVec(metrics.PreemptedWorkloadsTotal, map[string]string{"preempting_cluster_queue": cq.Name})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected preempted workloads metric (-want,+got):\n%s", diff)
+	}
+}
+
+func TestIssuePreemptionsScaleDown(t *testing.T) {
+	elastic := func(name, cq string, count int32) *kueue.Workload {
+		return utiltesting.MakeWorkload(name, "").
+			Priority(-1).
+			PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 4).SetMinimumCount(2).Request(corev1.ResourceCPU, "1").Obj()).
+			ReserveQuota(utiltesting.MakeAdmission(cq).Assignment(corev1.ResourceCPU, "default", fmt.Sprint(count)).AssignmentPodCount(count).Obj()).
+			Obj()
+	}
+	clusterQueue := func(name, nominal string, preemption kueue.ClusterQueuePreemption) *kueue.ClusterQueue {
+		return utiltesting.MakeClusterQueue(name).
+			Cohort("cohort").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+				Resource(corev1.ResourceCPU, nominal).Obj()).
+			Preemption(preemption).
+			Obj()
+	}
+	reclaim := kueue.ClusterQueuePreemption{
+		ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+		WithinClusterQueue:  kueue.PreemptionPolicyLowerPriority,
+	}
+	reclaimingCQs := []*kueue.ClusterQueue{
+		clusterQueue("a", "4", reclaim),
+		clusterQueue("b", "0", kueue.ClusterQueuePreemption{}),
+	}
+	cases := map[string]struct {
+		disableScaleDown bool
+		clusterQueues    []*kueue.ClusterQueue
+		admitted         []*kueue.Workload
+		incomingCPU      string
+		wantScaledDown   map[string]int32
+		wantPreempted    sets.Set[string]
+	}{
+		"feature disabled": {
+			disableScaleDown: true,
+			clusterQueues:    reclaimingCQs,
+			admitted:         []*kueue.Workload{elastic("elastic", "b", 4)},
+			incomingCPU:      "1",
+			wantPreempted:    sets.New("elastic"),
+		},
+		"scale down reclaimed workload only as much as needed": {
+			clusterQueues:  reclaimingCQs,
+			admitted:       []*kueue.Workload{elastic("elastic", "b", 4)},
+			incomingCPU:    "1",
+			wantScaledDown: map[string]int32{"elastic": 3},
+		},
+		"scale down reclaimed workload to its minimum counts": {
+			clusterQueues:  reclaimingCQs,
+			admitted:       []*kueue.Workload{elastic("elastic", "b", 4)},
+			incomingCPU:    "2",
+			wantScaledDown: map[string]int32{"elastic": 2},
+		},
+		"preempt reclaimed workload when scaling down is not enough": {
+			clusterQueues: reclaimingCQs,
+			admitted:      []*kueue.Workload{elastic("elastic", "b", 4)},
+			incomingCPU:   "3",
+			wantPreempted: sets.New("elastic"),
+		},
+		"preempt reclaimed workload already at its minimum counts": {
+			clusterQueues: reclaimingCQs,
+			admitted:      []*kueue.Workload{elastic("elastic-at-min", "b", 2)},
+			incomingCPU:   "3",
+			wantPreempted: sets.New("elastic-at-min"),
+		},
+		"scale down only the workloads needed": {
+			clusterQueues: reclaimingCQs,
+			admitted: []*kueue.Workload{
+				elastic("elastic-at-min", "b", 2),
+				utiltesting.MakeWorkload("elastic", "").
+					Priority(-2).
+					PodSets(*utiltesting.MakePodSet(kueue.DefaultPodSetName, 4).SetMinimumCount(1).Request(corev1.ResourceCPU, "1").Obj()).
+					ReserveQuota(utiltesting.MakeAdmission("b").Assignment(corev1.ResourceCPU, "default", "2").AssignmentPodCount(2).Obj()).
+					Obj(),
+			},
+			incomingCPU:    "1",
+			wantScaledDown: map[string]int32{"elastic": 1},
+		},
+		"preempt elastic workload in the same ClusterQueue": {
+			clusterQueues: reclaimingCQs,
+			admitted:      []*kueue.Workload{elastic("elastic-in-cq", "a", 4)},
+			incomingCPU:   "1",
+			wantPreempted: sets.New("elastic-in-cq"),
+		},
+		"preempt elastic workload in another ClusterQueue while borrowing": {
+			clusterQueues: []*kueue.ClusterQueue{
+				clusterQueue("a", "2", kueue.ClusterQueuePreemption{
+					ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+					BorrowWithinCohort: &kueue.BorrowWithinCohort{
+						Policy: kueue.BorrowWithinCohortPolicyLowerPriority,
+					},
+				}),
+				clusterQueue("b", "2", kueue.ClusterQueuePreemption{}),
+			},
+			admitted:      []*kueue.Workload{elastic("elastic", "b", 4)},
+			incomingCPU:   "3",
+			wantPreempted: sets.New("elastic"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer features.SetFeatureGateDuringTest(t, features.PartialAdmissionScaleDown, !tc.disableScaleDown)()
+			ctx, _ := utiltesting.ContextWithLog(t)
+			cl := utiltesting.NewClientBuilder().
+				WithLists(&kueue.WorkloadList{Items: slices.Map(tc.admitted, func(w **kueue.Workload) kueue.Workload { return **w })}).
+				Build()
+			cqCache := cache.New(cl)
+			cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+			for _, cq := range tc.clusterQueues {
+				if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+					t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+				}
+			}
+
+			var lock sync.Mutex
+			gotScaledDown := make(map[string]int32)
+			gotPreempted := sets.New[string]()
+			preemptor := New(cl, workload.Ordering{}, &utiltesting.EventRecorder{}, config.FairSharing{}, config.EvictionOrdering{})
+			preemptor.applyPreemption = func(_ context.Context, w *kueue.Workload, _, _ string) error {
+				lock.Lock()
+				gotPreempted.Insert(w.Name)
+				lock.Unlock()
+				return nil
+			}
+			preemptor.applyScaleDown = func(_ context.Context, w *kueue.Workload) error {
+				lock.Lock()
+				gotScaledDown[w.Name] = *w.Status.Admission.PodSetAssignments[0].Count
+				lock.Unlock()
+				return nil
+			}
+			startingSnapshot := cqCache.Snapshot()
+			snapshot := cqCache.Snapshot()
+			wlInfo := workload.NewInfo(utiltesting.MakeWorkload("in", "").Request(corev1.ResourceCPU, tc.incomingCPU).Obj())
+			wlInfo.ClusterQueue = "a"
+			targets := preemptor.GetTargets(*wlInfo, singlePodSetAssignment(flavorassigner.ResourceAssignment{
+				corev1.ResourceCPU: &flavorassigner.FlavorAssignment{
+					Name: "default",
+					Mode: flavorassigner.Preempt,
+				},
+			}), &snapshot)
+			if diff := cmp.Diff(startingSnapshot, snapshot, snapCmpOpts...); diff != "" {
+				t.Errorf("Snapshot was modified (-initial,+end):\n%s", diff)
+			}
+			if _, err := preemptor.IssuePreemptions(ctx, wlInfo, targets, snapshot.ClusterQueues["a"]); err != nil {
+				t.Fatalf("Failed doing preemption: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantScaledDown, gotScaledDown, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected scaled down workloads (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantPreempted, gotPreempted, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected preempted workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestFairPreemptions(t *testing.T) {
 	now := time.Now()
 	flavors := []*kueue.ResourceFlavor{
@@ -1899,7 +2329,7 @@ func TestFairPreemptions(t *testing.T) {
 			preemptor := New(cl, workload.Ordering{}, recorder, config.FairSharing{
 				Enable:               true,
 				PreemptionStrategies: tc.strategies,
-			})
+			}, config.EvictionOrdering{})
 
 			snapshot := cqCache.Snapshot()
 			wlInfo := workload.NewInfo(tc.incoming)
@@ -1959,7 +2389,7 @@ func TestCandidatesOrdering(t *testing.T) {
 			}).
 			Obj()),
 	}
-	sort.Slice(candidates, candidatesOrdering(candidates, "self", now))
+	sort.Slice(candidates, candidatesOrdering(candidates, "self", now, evictionWeights{priority: 1}))
 	gotNames := make([]string, len(candidates))
 	for i, c := range candidates {
 		gotNames[i] = workload.Key(c.Obj)
@@ -1970,6 +2400,59 @@ func TestCandidatesOrdering(t *testing.T) {
 	}
 }
 
+func TestCandidatesOrderingWithEvictionWeights(t *testing.T) {
+	now := time.Now()
+	candidate := func(name string, priority int32, runtime time.Duration) *workload.Info {
+		return workload.NewInfo(utiltesting.MakeWorkload(name, "").
+			UID(types.UID(name)).
+			Priority(priority).
+			ReserveQuotaAt(utiltesting.MakeAdmission("self").Obj(), now.Add(-runtime)).
+			Obj())
+	}
+	cases := map[string]struct {
+		weights evictionWeights
+		want    []string
+	}{
+		"priority only": {
+			weights: evictionWeights{priority: 1},
+			want:    []string{"/low-long", "/mid-short", "/mid-long", "/high-short", "/high-long"},
+		},
+		"priority and runtime": {
+			weights: evictionWeights{priority: 1, runtime: 1},
+			// Scores: low-long=70, mid-short=51, mid-long=110, high-short=101, high-long=160.
+			want: []string{"/mid-short", "/low-long", "/high-short", "/mid-long", "/high-long"},
+		},
+		"runtime only": {
+			weights: evictionWeights{runtime: 1},
+			// Ties on runtime are broken by priority.
+			want: []string{"/mid-short", "/high-short", "/low-long", "/mid-long", "/high-long"},
+		},
+		"heavier priority": {
+			weights: evictionWeights{priority: 10, runtime: 1},
+			want:    []string{"/low-long", "/mid-short", "/mid-long", "/high-short", "/high-long"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			candidates := []*workload.Info{
+				candidate("high-long", 100, time.Hour),
+				candidate("high-short", 100, time.Minute),
+				candidate("mid-long", 50, time.Hour),
+				candidate("mid-short", 50, time.Minute),
+				candidate("low-long", 10, time.Hour),
+			}
+			sort.Slice(candidates, candidatesOrdering(candidates, "self", now, tc.weights))
+			gotNames := make([]string, len(candidates))
+			for i, c := range candidates {
+				gotNames[i] = workload.Key(c.Obj)
+			}
+			if diff := cmp.Diff(tc.want, gotNames); diff != "" {
+				t.Errorf("Sorted with wrong order (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func singlePodSetAssignment(assignments flavorassigner.ResourceAssignment) flavorassigner.Assignment {
 	return flavorassigner.Assignment{
 		PodSets: []flavorassigner.PodSetAssignment{{
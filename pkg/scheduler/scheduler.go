@@ -18,15 +18,18 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -43,10 +46,10 @@ import (
 	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
 	"sigs.k8s.io/kueue/pkg/resources"
+	"sigs.k8s.io/kueue/pkg/scheduler/decisions"
 	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
 	"sigs.k8s.io/kueue/pkg/scheduler/preemption"
 	"sigs.k8s.io/kueue/pkg/util/api"
-	"sigs.k8s.io/kueue/pkg/util/limitrange"
 	utilmaps "sigs.k8s.io/kueue/pkg/util/maps"
 	"sigs.k8s.io/kueue/pkg/util/priority"
 	"sigs.k8s.io/kueue/pkg/util/resource"
@@ -68,6 +71,10 @@ type Scheduler struct {
 	preemptor               *preemption.Preemptor
 	workloadOrdering        workload.Ordering
 	fairSharing             config.FairSharing
+	decisionPublisher       decisions.Publisher
+
+	// paused, when true, stops the scheduler from admitting workloads.
+	paused atomic.Bool
 
 	// Stubs.
 	applyAdmission func(context.Context, *kueue.Workload) error
@@ -76,6 +83,8 @@ type Scheduler struct {
 type options struct {
 	podsReadyRequeuingTimestamp config.RequeuingTimestamp
 	fairSharing                 config.FairSharing
+	evictionOrdering            config.EvictionOrdering
+	decisionPublisher           decisions.Publisher
 }
 
 // Option configures the reconciler.
@@ -83,6 +92,7 @@ type Option func(*options)
 
 var defaultOptions = options{
 	podsReadyRequeuingTimestamp: config.EvictionTimestamp,
+	decisionPublisher:           decisions.NoopPublisher{},
 }
 
 // WithPodsReadyRequeuingTimestamp sets the timestamp that is used for ordering
@@ -101,6 +111,26 @@ func WithFairSharing(fs *config.FairSharing) Option {
 	}
 }
 
+// WithEvictionOrdering sets the weights used to order the candidates for
+// preemption.
+func WithEvictionOrdering(eo *config.EvictionOrdering) Option {
+	return func(o *options) {
+		if eo != nil {
+			o.evictionOrdering = *eo
+		}
+	}
+}
+
+// WithDecisionPublisher sets the publisher that receives the admission
+// decisions taken by the scheduler.
+func WithDecisionPublisher(p decisions.Publisher) Option {
+	return func(o *options) {
+		if p != nil {
+			o.decisionPublisher = p
+		}
+	}
+}
+
 func New(queues *queue.Manager, cache *cache.Cache, cl client.Client, recorder record.EventRecorder, opts ...Option) *Scheduler {
 	options := defaultOptions
 	for _, opt := range opts {
@@ -115,9 +145,10 @@ func New(queues *queue.Manager, cache *cache.Cache, cl client.Client, recorder r
 		cache:                   cache,
 		client:                  cl,
 		recorder:                recorder,
-		preemptor:               preemption.New(cl, wo, recorder, options.fairSharing),
+		preemptor:               preemption.New(cl, wo, recorder, options.fairSharing, options.evictionOrdering),
 		admissionRoutineWrapper: routine.DefaultWrapper,
 		workloadOrdering:        wo,
+		decisionPublisher:       options.decisionPublisher,
 	}
 	s.applyAdmission = s.applyAdmissionWithSSA
 	return s
@@ -137,6 +168,59 @@ func (s *Scheduler) NeedLeaderElection() bool {
 	return true
 }
 
+// Pause stops the scheduler from admitting new workloads until Resume is
+// called. Workloads that are already admitted are not affected.
+func (s *Scheduler) Pause() {
+	s.paused.Store(true)
+}
+
+// Resume lets the scheduler admit workloads again after a Pause.
+func (s *Scheduler) Resume() {
+	s.paused.Store(false)
+}
+
+// Paused returns whether the scheduler is paused.
+func (s *Scheduler) Paused() bool {
+	return s.paused.Load()
+}
+
+// CanFit reports whether the workload would get quota reserved in the
+// current state of the cluster, without preempting other workloads. It
+// returns the flavors that would be assigned or the reason why the workload
+// doesn't fit. Neither the workload, the cache nor the queues are modified.
+//
+// The simulation lives in the scheduler rather than in the cache because the
+// flavor assignment and the preemption logic it reuses depend on the cache.
+func (s *Scheduler) CanFit(ctx context.Context, wl *kueue.Workload) (bool, string) {
+	cqName, ok := s.queues.ClusterQueueForWorkload(wl)
+	if !ok {
+		if cqName == "" {
+			return false, fmt.Sprintf("LocalQueue %s doesn't exist", wl.Spec.QueueName)
+		}
+		return false, fmt.Sprintf("ClusterQueue %s not found", cqName)
+	}
+	// The workload is evaluated as a new one, without a name, so that it's
+	// never mistaken for an existing workload with the same key, nor
+	// considered admitted because of its status.
+	wl = wl.DeepCopy()
+	wl.Name = ""
+	wl.UID = ""
+	wl.Status = kueue.WorkloadStatus{}
+	// Adjust the requests as they would be when the workload is queued.
+	workload.AdjustResources(ctx, s.client, wl)
+	wi := s.queues.NewWorkloadInfo(wl)
+	wi.ClusterQueue = cqName
+	snapshot := s.cache.Snapshot()
+	e := s.evaluate(ctx, ctrl.LoggerFrom(ctx), *wi, &snapshot)
+	if e.assignment.RepresentativeMode() == flavorassigner.Fit {
+		return true, fmt.Sprintf("Fits in ClusterQueue %s (%s)", cqName, workload.AssignedFlavorsMessage(e.assignment.ToAPI()))
+	}
+	if len(e.preemptionTargets) > 0 {
+		return false, fmt.Sprintf("%s; it fits by preempting %d workloads", e.inadmissibleMsg, len(e.preemptionTargets))
+	}
+	return false, e.inadmissibleMsg
+}
+
 func (s *Scheduler) setAdmissionRoutineWrapper(wrapper routine.Wrapper) {
 	s.admissionRoutineWrapper = wrapper
 }
@@ -185,6 +269,11 @@ func (cu *cohortsUsage) hasCommonFlavorResources(cohort string, assignment resou
 func (s *Scheduler) schedule(ctx context.Context) wait.SpeedSignal {
 	log := ctrl.LoggerFrom(ctx)
 
+	// While paused, leave the workloads in the queues and check again later.
+	if s.Paused() {
+		return wait.SlowDown
+	}
+
 	// 1. Get the heads from the queues, including their desired clusterQueue.
 	// This operation blocks while the queues are empty.
 	headWorkloads := s.queues.Heads(ctx)
@@ -215,6 +304,7 @@ func (s *Scheduler) schedule(ctx context.Context) wait.SpeedSignal {
 	// of other clusterQueues.
 	cycleCohortsUsage := cohortsUsage{}
 	cycleCohortsSkipPreemption := sets.New[string]()
+	cycleCohortsBorrowing := cohortsUsage{}
 	for i := range entries {
 		e := &entries[i]
 		mode := e.assignment.RepresentativeMode()
@@ -239,6 +329,21 @@ func (s *Scheduler) schedule(ctx context.Context) wait.SpeedSignal {
 				e.LastAssignment = nil
 				continue
 			}
+			borrowing := cq.Borrowing(e.assignment.Usage)
+			if mode == flavorassigner.Fit && len(borrowing) > 0 &&
+				!cq.FitInCohortBorrowingCaps(cycleCohortsBorrowing.totalUsageForCommonFlavorResources(cq.Cohort.Name, borrowing)) {
+				e.status = skipped
+				e.inadmissibleMsg = "other workloads in the cohort borrowed up to the cohort borrowing cap"
+				e.LastAssignment = nil
+				continue
+			}
+			if mode == flavorassigner.Fit && !cq.FitInCohortBorrowableQuota(sum, borrowing) {
+				e.status = skipped
+				e.inadmissibleMsg = "other workloads in the cohort used the quota outside of the borrowing safety margin"
+				e.LastAssignment = nil
+				continue
+			}
+			cycleCohortsBorrowing.add(cq.Cohort.Name, borrowing)
 			// Even if the workload will not be admitted after this point, due to preemption pending or other failures,
 			// we should still account for its usage.
 			cycleCohortsUsage.add(cq.Cohort.Name, resourcesToReserve(e, cq))
@@ -291,6 +396,7 @@ func (s *Scheduler) schedule(ctx context.Context) wait.SpeedSignal {
 	for _, e := range entries {
 		logAdmissionAttemptIfVerbose(log, &e)
 		if e.status != assumed {
+			metrics.WorkloadAdmissionAttempt(e.ClusterQueue, failedAdmissionResult(&e))
 			s.requeueAndUpdate(ctx, e)
 		} else {
 			result = metrics.AdmissionResultSuccess
@@ -303,6 +409,19 @@ func (s *Scheduler) schedule(ctx context.Context) wait.SpeedSignal {
 	return wait.KeepGoing
 }
 
+// failedAdmissionResult returns the result of the admission attempt for an
+// entry that didn't get quota reserved.
+func failedAdmissionResult(e *entry) metrics.WorkloadAdmissionResult {
+	switch {
+	case e.status == nominated:
+		return metrics.WorkloadAdmissionResultError
+	case e.requeueReason == queue.RequeueReasonPendingPreemption:
+		return metrics.WorkloadAdmissionResultPreempted
+	default:
+		return metrics.WorkloadAdmissionResultPending
+	}
+}
+
 type entryStatus string
 
 const (
@@ -326,8 +445,12 @@ type entry struct {
 	assignment            flavorassigner.Assignment
 	status                entryStatus
 	inadmissibleMsg       string
-	requeueReason         queue.RequeueReason
-	preemptionTargets     []*workload.Info
+	// inadmissibleReason overrides the Pending reason of the QuotaReserved
+	// condition when the workload is not nominated.
+	inadmissibleReason string
+	requeueReason      queue.RequeueReason
+	preemptionTargets  []*workload.Info
+	candidateFlavors   []kueue.PodSetCandidateFlavors
 }
 
 // nominate returns the workloads with their requirements (resource flavors, borrowing) if
@@ -337,40 +460,63 @@ func (s *Scheduler) nominate(ctx context.Context, workloads []workload.Info, sna
 	entries := make([]entry, 0, len(workloads))
 	for _, w := range workloads {
 		log := log.WithValues("workload", klog.KObj(w.Obj), "clusterQueue", klog.KRef("", w.ClusterQueue))
-		cq := snap.ClusterQueues[w.ClusterQueue]
-		ns := corev1.Namespace{}
-		e := entry{Info: w}
 		if s.cache.IsAssumedOrAdmittedWorkload(w) {
 			log.Info("Workload skipped from admission because it's already assumed or admitted", "workload", klog.KObj(w.Obj))
 			continue
-		} else if workload.HasRetryChecks(w.Obj) || workload.HasRejectedChecks(w.Obj) {
-			e.inadmissibleMsg = "The workload has failed admission checks"
-		} else if snap.InactiveClusterQueueSets.Has(w.ClusterQueue) {
-			e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s is inactive", w.ClusterQueue)
-		} else if cq == nil {
-			e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s not found", w.ClusterQueue)
-		} else if err := s.client.Get(ctx, types.NamespacedName{Name: w.Obj.Namespace}, &ns); err != nil {
-			e.inadmissibleMsg = fmt.Sprintf("Could not obtain workload namespace: %v", err)
-		} else if !cq.NamespaceSelector.Matches(labels.Set(ns.Labels)) {
-			e.inadmissibleMsg = "Workload namespace doesn't match ClusterQueue selector"
-			e.requeueReason = queue.RequeueReasonNamespaceMismatch
-		} else if err := s.validateResources(&w); err != nil {
-			e.inadmissibleMsg = err.Error()
-		} else if err := s.validateLimitRange(ctx, &w); err != nil {
-			e.inadmissibleMsg = err.Error()
-		} else {
-			e.assignment, e.preemptionTargets = s.getAssignments(log, &e.Info, &snap)
-			e.inadmissibleMsg = e.assignment.Message()
-			e.Info.LastAssignment = &e.assignment.LastState
-			if s.fairSharing.Enable && e.assignment.RepresentativeMode() != flavorassigner.NoFit {
-				e.dominantResourceShare, e.dominantResourceName = cq.DominantResourceShareWith(e.assignment.TotalRequestsFor(&w))
-			}
+		}
+		e := s.evaluate(ctx, log, w, &snap)
+		if len(e.assignment.PodSets) > 0 {
+			e.candidateFlavors = flavorassigner.New(&e.Info, snap.ClusterQueues[w.ClusterQueue], snap.ResourceFlavors, s.fairSharing.Enable).CandidateFlavors()
 		}
 		entries = append(entries, e)
 	}
 	return entries
 }
 
+// evaluate validates the workload against its ClusterQueue and, when it's
+// valid, computes the flavor assignment and the preemption targets of the
+// workload in the snapshot.
+func (s *Scheduler) evaluate(ctx context.Context, log logr.Logger, w workload.Info, snap *cache.Snapshot) entry {
+	cq := snap.ClusterQueues[w.ClusterQueue]
+	ns := corev1.Namespace{}
+	e := entry{Info: w}
+	if workload.HasRetryChecks(w.Obj) || workload.HasRejectedChecks(w.Obj) {
+		e.inadmissibleMsg = "The workload has failed admission checks"
+	} else if snap.InactiveClusterQueueSets.Has(w.ClusterQueue) {
+		e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s is inactive", w.ClusterQueue)
+	} else if cq == nil {
+		e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s not found", w.ClusterQueue)
+	} else if s.cache.LocalQueueMaxConcurrencyReached(&w) {
+		e.inadmissibleMsg = fmt.Sprintf("LocalQueue %s reached its maxConcurrency", w.Obj.Spec.QueueName)
+	} else if err := s.client.Get(ctx, types.NamespacedName{Name: w.Obj.Namespace}, &ns); err != nil {
+		e.inadmissibleMsg = fmt.Sprintf("Could not obtain workload namespace: %v", err)
+	} else if !cq.NamespaceSelector.Matches(labels.Set(ns.Labels)) {
+		e.inadmissibleMsg = "Workload namespace doesn't match ClusterQueue selector"
+		e.requeueReason = queue.RequeueReasonNamespaceMismatch
+	} else if err := s.validateResources(&w); err != nil {
+		e.inadmissibleMsg = err.Error()
+	} else if err := validateIntegerResources(&w, cq); err != nil {
+		e.inadmissibleMsg = err.Error()
+	} else if err := workload.ValidateLimitRange(ctx, s.client, w.Obj); err != nil {
+		e.inadmissibleMsg = err.Error()
+		if errors.Is(err, workload.ErrExceedsLimitRange) {
+			e.inadmissibleReason = kueue.WorkloadExceedsLimitRange
+		}
+	} else if err := cq.ValidateRequestsFitCapacity(cq.RequestsWithAliases(w.TotalRequests)); err != nil {
+		e.inadmissibleMsg = err.Error()
+		e.inadmissibleReason = kueue.WorkloadRequestExceedsCapacity
+	} else {
+		e.Info.TotalRequests = cq.RequestsWithAliases(e.Info.TotalRequests)
+		e.assignment, e.preemptionTargets = s.getAssignments(log, &e.Info, snap)
+		e.inadmissibleMsg = e.assignment.Message()
+		e.Info.LastAssignment = &e.assignment.LastState
+		if s.fairSharing.Enable && e.assignment.RepresentativeMode() != flavorassigner.NoFit {
+			e.dominantResourceShare, e.dominantResourceName = cq.DominantResourceShareWith(e.assignment.TotalRequestsFor(&e.Info))
+		}
+	}
+	return e
+}
+
 // resourcesToReserve calculates how much of the available resources in cq/cohort assignment should be reserved.
 func resourcesToReserve(e *entry, cq *cache.ClusterQueue) resources.FlavorResourceQuantities {
 	if e.assignment.RepresentativeMode() != flavorassigner.Preempt {
@@ -388,7 +534,7 @@ func resourcesToReserve(e *entry, cq *cache.ClusterQueue) resources.FlavorResour
 					break
 				}
 			}
-			if !e.assignment.Borrowing {
+			if !e.assignment.BorrowsFlavorResource(flavor, resource) {
 				reservedUsage[flavor][resource] = max(0, min(usage, cqQuota.Nominal-cq.Usage[flavor][resource]))
 			} else {
 				if cqQuota.BorrowingLimit == nil {
@@ -418,6 +564,25 @@ func (s *Scheduler) getAssignments(log logr.Logger, wl *workload.Info, snap *cac
 		return fullAssignment, nil
 	}
 
+	// Optional PodSets are only admitted with spare capacity, drop them,
+	// starting from the last one, until the workload fits. If it doesn't
+	// fit even with the required PodSets only, continue with that assignment.
+	optionalPodSets := workload.OptionalPodSetIndexes(wl.Obj)
+	if len(optionalPodSets) > 0 {
+		counts := make([]int32, len(wl.Obj.Spec.PodSets))
+		for i := range wl.Obj.Spec.PodSets {
+			counts[i] = wl.Obj.Spec.PodSets[i].Count
+		}
+		for i := len(optionalPodSets) - 1; i >= 0; i-- {
+			counts[optionalPodSets[i]] = 0
+			fullAssignment = flvAssigner.Assign(log, counts)
+			if fullAssignment.RepresentativeMode() == flavorassigner.Fit {
+				return fullAssignment, nil
+			}
+		}
+		arm = fullAssignment.RepresentativeMode()
+	}
+
 	if arm == flavorassigner.Preempt {
 		faPreemtionTargets = s.preemptor.GetTargets(*wl, fullAssignment, snap)
 	}
@@ -429,6 +594,9 @@ func (s *Scheduler) getAssignments(log logr.Logger, wl *workload.Info, snap *cac
 
 	if wl.CanBePartiallyAdmitted() {
 		reducer := flavorassigner.NewPodSetReducer(wl.Obj.Spec.PodSets, func(nextCounts []int32) (*partialAssignment, bool) {
+			for _, i := range optionalPodSets {
+				nextCounts[i] = 0
+			}
 			assignment := flvAssigner.Assign(log, nextCounts)
 			if assignment.RepresentativeMode() == flavorassigner.Fit {
 				return &partialAssignment{assignment: assignment}, true
@@ -479,28 +647,39 @@ func (s *Scheduler) validateResources(wi *workload.Info) error {
 	return nil
 }
 
-// validateLimitRange validates that the requested resources fit into the namespace defined
-// limitRanges.
-func (s *Scheduler) validateLimitRange(ctx context.Context, wi *workload.Info) error {
-	podsetsPath := field.NewPath("podSets")
-	// get the range summary from the namespace.
-	list := corev1.LimitRangeList{}
-	if err := s.client.List(ctx, &list, &client.ListOptions{Namespace: wi.Obj.Namespace}); err != nil {
-		return err
-	}
-	if len(list.Items) == 0 {
+// validateIntegerResources validates that the containers don't request a
+// fractional quantity of the integer resources of the ClusterQueue.
+func validateIntegerResources(wi *workload.Info, cq *cache.ClusterQueue) error {
+	if cq.IntegerResources.Len() == 0 {
 		return nil
 	}
-	summary := limitrange.Summarize(list.Items...)
-
-	// verify
+	podsetsPath := field.NewPath("podSets")
 	allReasons := []string{}
+	validateContainers := func(containers []corev1.Container, path *field.Path) {
+		for i := range containers {
+			requests := containers[i].Resources.Requests
+			for _, name := range sets.List(sets.KeySet(requests)) {
+				quotaName := name
+				if logical, found := cq.ResourceAliases[name]; found {
+					quotaName = logical
+				} else if rs, found := cq.ResourceSlices[name]; found {
+					quotaName = rs.Resource
+				}
+				if q := requests[name]; cq.IntegerResources.Has(quotaName) && q.MilliValue()%1000 != 0 {
+					allReasons = append(allReasons, fmt.Sprintf("%s[%s] request %s is not an integer",
+						path.Index(i).String(), name, q.String()))
+				}
+			}
+		}
+	}
 	for i := range wi.Obj.Spec.PodSets {
 		ps := &wi.Obj.Spec.PodSets[i]
-		allReasons = append(allReasons, summary.ValidatePodSpec(&ps.Template.Spec, podsetsPath.Child(ps.Name))...)
+		psPath := podsetsPath.Child(ps.Name)
+		validateContainers(ps.Template.Spec.InitContainers, psPath.Child("initContainers"))
+		validateContainers(ps.Template.Spec.Containers, psPath.Child("containers"))
 	}
 	if len(allReasons) > 0 {
-		return fmt.Errorf("didn't satisfy LimitRange constraints: %s", strings.Join(allReasons, "; "))
+		return fmt.Errorf("integer resources validation failed: %s", strings.Join(allReasons, "; "))
 	}
 	return nil
 }
@@ -517,6 +696,7 @@ func (s *Scheduler) admit(ctx context.Context, e *entry, cq *cache.ClusterQueue)
 	}
 
 	workload.SetQuotaReservation(newWorkload, admission)
+	newWorkload.Status.CandidateFlavors = nil
 	if workload.HasAllChecks(newWorkload, workload.AdmissionChecksForWorkload(log, newWorkload, cq.AdmissionChecks)) {
 		// sync Admitted, ignore the result since an API update is always done.
 		_ = workload.SyncAdmittedCondition(newWorkload)
@@ -526,38 +706,72 @@ func (s *Scheduler) admit(ctx context.Context, e *entry, cq *cache.ClusterQueue)
 	}
 	e.status = assumed
 	log.V(2).Info("Workload assumed in the cache")
+	reclaimable := e.assignment.Borrowing && canReclaimFrom(cq)
 
 	s.admissionRoutineWrapper.Run(func() {
 		err := s.applyAdmission(ctx, newWorkload)
 		if err == nil {
 			waitTime := workload.QueuedWaitTime(newWorkload)
 			s.recorder.Eventf(newWorkload, corev1.EventTypeNormal, "QuotaReserved", "Quota reserved in ClusterQueue %v, wait time since queued was %.0fs", admission.ClusterQueue, waitTime.Seconds())
+			if reclaimable {
+				s.recorder.Eventf(newWorkload, corev1.EventTypeWarning, "BorrowedQuota", "Quota reserved by borrowing from the cohort; the workload could be preempted if other ClusterQueues in the cohort reclaim their quota")
+			}
 			metrics.QuotaReservedWorkload(admission.ClusterQueue, waitTime)
+			metrics.WorkloadAdmissionAttempt(e.ClusterQueue, metrics.WorkloadAdmissionResultAdmitted)
 			if workload.IsAdmitted(newWorkload) {
-				s.recorder.Eventf(newWorkload, corev1.EventTypeNormal, "Admitted", "Admitted by ClusterQueue %v, wait time since reservation was 0s", admission.ClusterQueue)
+				s.recorder.Event(newWorkload, corev1.EventTypeNormal, "Admitted", workload.AdmittedEventMessage(admission, 0))
 				metrics.AdmittedWorkload(admission.ClusterQueue, waitTime)
 				if len(newWorkload.Status.AdmissionChecks) > 0 {
 					metrics.AdmissionChecksWaitTime(admission.ClusterQueue, 0)
 				}
 			}
 			log.V(2).Info("Workload successfully admitted and assigned flavors", "assignments", admission.PodSetAssignments)
+			s.publishDecision(ctx, newWorkload, admission)
 			return
 		}
 		// Ignore errors because the workload or clusterQueue could have been deleted
 		// by an event.
 		_ = s.cache.ForgetWorkload(newWorkload)
-		if errors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
 			log.V(2).Info("Workload not admitted because it was deleted")
 			return
 		}
 
 		log.Error(err, errCouldNotAdmitWL)
+		metrics.WorkloadAdmissionAttempt(e.ClusterQueue, metrics.WorkloadAdmissionResultError)
 		s.requeueAndUpdate(ctx, *e)
 	})
 
 	return nil
 }
 
+func (s *Scheduler) publishDecision(ctx context.Context, wl *kueue.Workload, admission *kueue.Admission) {
+	d := decisions.Decision{
+		Namespace:         wl.Namespace,
+		Name:              wl.Name,
+		ClusterQueue:      admission.ClusterQueue,
+		PodSetAssignments: admission.PodSetAssignments,
+		Time:              time.Now(),
+	}
+	if err := s.decisionPublisher.Publish(ctx, d); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Could not publish the admission decision")
+	}
+}
+
+// canReclaimFrom returns whether any other ClusterQueue in the cohort of cq
+// can preempt the workloads of cq to reclaim its nominal quota.
+func canReclaimFrom(cq *cache.ClusterQueue) bool {
+	if cq.Cohort == nil {
+		return false
+	}
+	for member := range cq.Cohort.Members {
+		if member != cq && member.Preemption.ReclaimWithinCohort != kueue.PreemptionPolicyNever {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Scheduler) applyAdmissionWithSSA(ctx context.Context, w *kueue.Workload) error {
 	return workload.ApplyAdmissionStatus(ctx, s.client, w, false)
 }
@@ -621,12 +835,21 @@ func (s *Scheduler) requeueAndUpdate(ctx context.Context, e entry) {
 	log.V(2).Info("Workload re-queued", "workload", klog.KObj(e.Obj), "clusterQueue", klog.KRef("", e.ClusterQueue), "queue", klog.KRef(e.Obj.Namespace, e.Obj.Spec.QueueName), "requeueReason", e.requeueReason, "added", added)
 
 	if e.status == notNominated || e.status == skipped {
-		if workload.UnsetQuotaReservationWithCondition(e.Obj, "Pending", e.inadmissibleMsg) {
+		reason := "Pending"
+		if e.inadmissibleReason != "" {
+			reason = e.inadmissibleReason
+		}
+		changed := workload.UnsetQuotaReservationWithCondition(e.Obj, reason, e.inadmissibleMsg)
+		if e.candidateFlavors != nil && !equality.Semantic.DeepEqual(e.Obj.Status.CandidateFlavors, e.candidateFlavors) {
+			e.Obj.Status.CandidateFlavors = e.candidateFlavors
+			changed = true
+		}
+		if changed {
 			err := workload.ApplyAdmissionStatus(ctx, s.client, e.Obj, true)
 			if err != nil {
 				log.Error(err, "Could not update Workload status")
 			}
 		}
-		s.recorder.Eventf(e.Obj, corev1.EventTypeNormal, "Pending", api.TruncateEventMessage(e.inadmissibleMsg))
+		s.recorder.Eventf(e.Obj, corev1.EventTypeNormal, reason, api.TruncateEventMessage(e.inadmissibleMsg))
 	}
 }
@@ -39,15 +39,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	config "sigs.k8s.io/kueue/apis/config/v1beta1"
+	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/cache"
 	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/features"
+	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
 	"sigs.k8s.io/kueue/pkg/resources"
+	"sigs.k8s.io/kueue/pkg/scheduler/decisions"
 	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
 	"sigs.k8s.io/kueue/pkg/util/routine"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	testingmetrics "sigs.k8s.io/kueue/pkg/util/testing/metrics"
+	"sigs.k8s.io/kueue/pkg/util/wait"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -228,9 +233,10 @@ func TestSchedule(t *testing.T) {
 	}
 	cases := map[string]struct {
 		// Features
-		enableLendingLimit      bool
-		disablePartialAdmission bool
-		enableFairSharing       bool
+		enableLendingLimit              bool
+		disablePartialAdmission         bool
+		enableFairSharing               bool
+		enableDynamicResourceAllocation bool
 
 		workloads      []kueue.Workload
 		admissionError error
@@ -238,6 +244,8 @@ func TestSchedule(t *testing.T) {
 		// additional*Queues can hold any extra queues needed by the tc
 		additionalClusterQueues []kueue.ClusterQueue
 		additionalLocalQueues   []kueue.LocalQueue
+		// cohorts hold the configuration of the cohorts
+		cohorts []kueuealpha.Cohort
 
 		// wantAssignments is a summary of all the admissions in the cache after this cycle.
 		wantAssignments map[string]kueue.Admission
@@ -311,6 +319,7 @@ func TestSchedule(t *testing.T) {
 							AssignmentPodCount(10).
 							Obj(),
 					).
+					AdmissionAttempts(1).
 					Generation(1).
 					Condition(metav1.Condition{
 						Type:               kueue.WorkloadQuotaReserved,
@@ -673,6 +682,106 @@ func TestSchedule(t *testing.T) {
 			},
 			wantScheduled: []string{"eng-alpha/new", "eng-beta/new"},
 		},
+		"namespace no longer matching the selector blocks admission but keeps running workloads": {
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("eng", "sales").ClusterQueue("eng-alpha").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("eng").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+				*utiltesting.MakeWorkload("running", "sales").
+					Queue("eng").
+					Request(corev1.ResourceCPU, "1").
+					ReserveQuota(utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj()).
+					Admitted(true).
+					Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"eng-alpha": {"sales/new"},
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/running": *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj(),
+			},
+		},
+		"warn when borrowing quota that can be reclaimed": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "eng-alpha").
+					Queue("main").
+					Request(corev1.ResourceCPU, "60").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"eng-alpha/new": *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "60").Obj(),
+			},
+			wantScheduled: []string{"eng-alpha/new"},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "eng-alpha", Name: "new"},
+					Reason:    "QuotaReserved",
+					EventType: corev1.EventTypeNormal,
+				},
+				{
+					Key:       types.NamespacedName{Namespace: "eng-alpha", Name: "new"},
+					Reason:    "BorrowedQuota",
+					EventType: corev1.EventTypeWarning,
+				},
+				{
+					Key:       types.NamespacedName{Namespace: "eng-alpha", Name: "new"},
+					Reason:    "Admitted",
+					EventType: corev1.EventTypeNormal,
+				},
+			},
+		},
+		"don't warn when admitted within the nominal quota": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "eng-alpha").
+					Queue("main").
+					Request(corev1.ResourceCPU, "40").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"eng-alpha/new": *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "40").Obj(),
+			},
+			wantScheduled: []string{"eng-alpha/new"},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "eng-alpha", Name: "new"},
+					Reason:    "QuotaReserved",
+					EventType: corev1.EventTypeNormal,
+				},
+				{
+					Key:       types.NamespacedName{Namespace: "eng-alpha", Name: "new"},
+					Reason:    "Admitted",
+					EventType: corev1.EventTypeNormal,
+				},
+			},
+		},
+		"don't warn when borrowing quota that can't be reclaimed": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "eng-beta").
+					Queue("main").
+					Request(corev1.ResourceCPU, "55").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"eng-beta/new": *utiltesting.MakeAdmission("eng-beta").Assignment(corev1.ResourceCPU, "on-demand", "55").Obj(),
+			},
+			wantScheduled: []string{"eng-beta/new"},
+			wantEvents: []utiltesting.EventRecord{
+				{
+					Key:       types.NamespacedName{Namespace: "eng-beta", Name: "new"},
+					Reason:    "QuotaReserved",
+					EventType: corev1.EventTypeNormal,
+				},
+				{
+					Key:       types.NamespacedName{Namespace: "eng-beta", Name: "new"},
+					Reason:    "Admitted",
+					EventType: corev1.EventTypeNormal,
+				},
+			},
+		},
 		"can borrow if needs reclaim from cohort in different flavor": {
 			workloads: []kueue.Workload{
 				*utiltesting.MakeWorkload("can-reclaim", "eng-alpha").
@@ -704,6 +813,19 @@ func TestSchedule(t *testing.T) {
 				"eng-beta/needs-to-borrow",
 			},
 		},
+		"workload borrows up to the lending limit of an idle ClusterQueue": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("b", "lend").
+					Queue("lend-b-queue").
+					Request(corev1.ResourceCPU, "4").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"lend/b": *utiltesting.MakeAdmission("lend-b").Assignment(corev1.ResourceCPU, "default", "4000m").Obj(),
+			},
+			wantScheduled:      []string{"lend/b"},
+			enableLendingLimit: true,
+		},
 		"workload exceeds lending limit when borrow in cohort": {
 			workloads: []kueue.Workload{
 				*utiltesting.MakeWorkload("a", "lend").
@@ -723,6 +845,279 @@ func TestSchedule(t *testing.T) {
 			},
 			enableLendingLimit: true,
 		},
+		"non-lendable quota can't be borrowed": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("owner").
+					Cohort("reserve").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "10").
+							NonLendableQuota(corev1.ResourceCPU, "6").Obj(),
+					).
+					Obj(),
+				*utiltesting.MakeClusterQueue("borrower").
+					Cohort("reserve").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "0").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("borrower", "sales").ClusterQueue("borrower").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("borrower").
+					Request(corev1.ResourceCPU, "5").
+					Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"borrower": {"sales/new"},
+			},
+			enableLendingLimit: true,
+		},
+		"non-lendable quota is immediately available to the owner": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("owner").
+					Cohort("reserve").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "10").
+							NonLendableQuota(corev1.ResourceCPU, "6").Obj(),
+					).
+					Obj(),
+				*utiltesting.MakeClusterQueue("borrower").
+					Cohort("reserve").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "0").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("owner", "sales").ClusterQueue("owner").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("borrowing", "sales").
+					Request(corev1.ResourceCPU, "4").
+					ReserveQuota(utiltesting.MakeAdmission("borrower").Assignment(corev1.ResourceCPU, "on-demand", "4").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("owner").
+					Request(corev1.ResourceCPU, "6").
+					Obj(),
+			},
+			wantScheduled: []string{"sales/new"},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/borrowing": *utiltesting.MakeAdmission("borrower").Assignment(corev1.ResourceCPU, "on-demand", "4").Obj(),
+				"sales/new":       *utiltesting.MakeAdmission("owner").Assignment(corev1.ResourceCPU, "on-demand", "6").Obj(),
+			},
+			enableLendingLimit: true,
+		},
+		"borrowing limit accounts for the quota already borrowed": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("capped").
+					Cohort("shared").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "2", "3").Obj(),
+					).
+					Obj(),
+				*utiltesting.MakeClusterQueue("lender").
+					Cohort("shared").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("capped", "sales").ClusterQueue("capped").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("borrowing", "sales").
+					Request(corev1.ResourceCPU, "4").
+					ReserveQuota(utiltesting.MakeAdmission("capped").Assignment(corev1.ResourceCPU, "on-demand", "4").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("capped").
+					Request(corev1.ResourceCPU, "2").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/borrowing": *utiltesting.MakeAdmission("capped").Assignment(corev1.ResourceCPU, "on-demand", "4").Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"capped": {"sales/new"},
+			},
+		},
+		"borrowing up to the borrowing limit": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("capped").
+					Cohort("shared").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "2", "3").Obj(),
+					).
+					Obj(),
+				*utiltesting.MakeClusterQueue("lender").
+					Cohort("shared").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("capped", "sales").ClusterQueue("capped").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("borrowing", "sales").
+					Request(corev1.ResourceCPU, "4").
+					ReserveQuota(utiltesting.MakeAdmission("capped").Assignment(corev1.ResourceCPU, "on-demand", "4").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("capped").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+			},
+			wantScheduled: []string{"sales/new"},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/borrowing": *utiltesting.MakeAdmission("capped").Assignment(corev1.ResourceCPU, "on-demand", "4").Obj(),
+				"sales/new":       *utiltesting.MakeAdmission("capped").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj(),
+			},
+		},
+		"localQueue reached its maxConcurrency": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("limited").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("default").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("limited", "sales").ClusterQueue("limited").MaxConcurrency(1).Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("running", "sales").
+					Queue("limited").
+					Request(corev1.ResourceCPU, "1").
+					ReserveQuota(utiltesting.MakeAdmission("limited").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("limited").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/running": *utiltesting.MakeAdmission("limited").Assignment(corev1.ResourceCPU, "default", "1").Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"limited": {"sales/new"},
+			},
+		},
+		"localQueue below its maxConcurrency": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("limited").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("default").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("limited", "sales").ClusterQueue("limited").MaxConcurrency(2).Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("running", "sales").
+					Queue("limited").
+					Request(corev1.ResourceCPU, "1").
+					ReserveQuota(utiltesting.MakeAdmission("limited").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("limited").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+			},
+			wantScheduled: []string{"sales/new"},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/running": *utiltesting.MakeAdmission("limited").Assignment(corev1.ResourceCPU, "default", "1").Obj(),
+				"sales/new":     *utiltesting.MakeAdmission("limited").Assignment(corev1.ResourceCPU, "default", "1").Obj(),
+			},
+		},
+		"fractional request of an integer resource": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("whole").
+					IntegerResources(corev1.ResourceCPU).
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("default").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("whole", "sales").ClusterQueue("whole").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("whole").
+					Request(corev1.ResourceCPU, "500m").
+					Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"whole": {"sales/new"},
+			},
+		},
+		"pod request exceeds the capacity of every flavor": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("small").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("default").
+							Resource(corev1.ResourceCPU, "4").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("small", "sales").ClusterQueue("small").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("small").
+					PodSets(*utiltesting.MakePodSet("main", 2).
+						Request(corev1.ResourceCPU, "5").
+						Obj()).
+					Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"small": {"sales/new"},
+			},
+		},
+		"integer request of an integer resource": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("whole").
+					IntegerResources(corev1.ResourceCPU).
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("default").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("whole", "sales").ClusterQueue("whole").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("whole").
+					Request(corev1.ResourceCPU, "2").
+					Obj(),
+			},
+			wantScheduled: []string{"sales/new"},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/new": *utiltesting.MakeAdmission("whole").Assignment(corev1.ResourceCPU, "default", "2").Obj(),
+			},
+		},
 		"preempt workloads in ClusterQueue and cohort": {
 			workloads: []kueue.Workload{
 				*utiltesting.MakeWorkload("preemptor", "eng-beta").
@@ -826,6 +1221,131 @@ func TestSchedule(t *testing.T) {
 				"eng-alpha": {"eng-alpha/new"},
 			},
 		},
+		"resource aliases share the quota of the logical resource": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("gpus").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource("example.com/gpu", "4").Obj(),
+					).
+					ResourceAlias("example.com/gpu", "nvidia.com/gpu", "amd.com/gpu").
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("gpus", "sales").ClusterQueue("gpus").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("gpus").
+					Request("amd.com/gpu", "2").
+					Obj(),
+				*utiltesting.MakeWorkload("existing", "sales").
+					Request("nvidia.com/gpu", "2").
+					ReserveQuota(utiltesting.MakeAdmission("gpus").Assignment("example.com/gpu", "on-demand", "2").Obj()).
+					Obj(),
+			},
+			wantScheduled: []string{"sales/new"},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/new":      *utiltesting.MakeAdmission("gpus").Assignment("example.com/gpu", "on-demand", "2").Obj(),
+				"sales/existing": *utiltesting.MakeAdmission("gpus").Assignment("example.com/gpu", "on-demand", "2").Obj(),
+			},
+		},
+		"resource aliases don't fit in the quota of the logical resource": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("gpus").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource("example.com/gpu", "4").Obj(),
+					).
+					ResourceAlias("example.com/gpu", "nvidia.com/gpu", "amd.com/gpu").
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("gpus", "sales").ClusterQueue("gpus").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("gpus").
+					Request("amd.com/gpu", "2").
+					Obj(),
+				*utiltesting.MakeWorkload("existing", "sales").
+					Request("nvidia.com/gpu", "3").
+					ReserveQuota(utiltesting.MakeAdmission("gpus").Assignment("example.com/gpu", "on-demand", "3").Obj()).
+					Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"gpus": {"sales/new"},
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/existing": *utiltesting.MakeAdmission("gpus").Assignment("example.com/gpu", "on-demand", "3").Obj(),
+			},
+		},
+		"resource slices are aggregated in whole units of the sliced resource": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("gpus").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource("nvidia.com/gpu", "2").Obj(),
+					).
+					ResourceSlice("nvidia.com/mig-1g.5gb", "nvidia.com/gpu", 7).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("gpus", "sales").ClusterQueue("gpus").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("gpus").
+					PodSets(*utiltesting.MakePodSet("main", 7).
+						Request("nvidia.com/mig-1g.5gb", "1").
+						Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("existing", "sales").
+					Request("nvidia.com/gpu", "1").
+					ReserveQuota(utiltesting.MakeAdmission("gpus").Assignment("nvidia.com/gpu", "on-demand", "1").Obj()).
+					Obj(),
+			},
+			wantScheduled: []string{"sales/new"},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/new": *utiltesting.MakeAdmission("gpus").
+					Assignment("nvidia.com/gpu", "on-demand", "1").
+					AssignmentPodCount(7).
+					Obj(),
+				"sales/existing": *utiltesting.MakeAdmission("gpus").Assignment("nvidia.com/gpu", "on-demand", "1").Obj(),
+			},
+		},
+		"resource slices rounded up don't fit in the quota of the sliced resource": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("gpus").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource("nvidia.com/gpu", "2").Obj(),
+					).
+					ResourceSlice("nvidia.com/mig-1g.5gb", "nvidia.com/gpu", 7).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("gpus", "sales").ClusterQueue("gpus").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("gpus").
+					PodSets(*utiltesting.MakePodSet("main", 8).
+						Request("nvidia.com/mig-1g.5gb", "1").
+						Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("existing", "sales").
+					Request("nvidia.com/gpu", "1").
+					ReserveQuota(utiltesting.MakeAdmission("gpus").Assignment("nvidia.com/gpu", "on-demand", "1").Obj()).
+					Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"gpus": {"sales/new"},
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/existing": *utiltesting.MakeAdmission("gpus").Assignment("nvidia.com/gpu", "on-demand", "1").Obj(),
+			},
+		},
 		"not enough resources to borrow, fallback to next flavor": {
 			workloads: []kueue.Workload{
 				*utiltesting.MakeWorkload("new", "eng-alpha").
@@ -1039,6 +1559,19 @@ func TestSchedule(t *testing.T) {
 			},
 			wantScheduled: []string{"sales/new"},
 		},
+		"no partial admission without a minimum count": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("main").
+					PodSets(*utiltesting.MakePodSet("one", 50).
+						Request(corev1.ResourceCPU, "2").
+						Obj()).
+					Obj(),
+			},
+			wantLeft: map[string][]string{
+				"sales": {"sales/new"},
+			},
+		},
 		"partial admission single variable pod set, preempt first": {
 			workloads: []kueue.Workload{
 				*utiltesting.MakeWorkload("new", "eng-beta").
@@ -1103,7 +1636,65 @@ func TestSchedule(t *testing.T) {
 					ClusterQueue: "sales",
 					PodSetAssignments: []kueue.PodSetAssignment{
 						{
-							Name: "one",
+							Name: "one",
+							Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+								corev1.ResourceCPU: "default",
+							},
+							ResourceUsage: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("20000m"),
+							},
+							Count: ptr.To[int32](20),
+						},
+						{
+							Name: "two",
+							Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+								corev1.ResourceCPU: "default",
+							},
+							ResourceUsage: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("20000m"),
+							},
+							Count: ptr.To[int32](20),
+						},
+						{
+							Name: "three",
+							Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+								corev1.ResourceCPU: "default",
+							},
+							ResourceUsage: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("10000m"),
+							},
+							Count: ptr.To[int32](10),
+						},
+					},
+				},
+			},
+			wantScheduled: []string{"sales/new"},
+		},
+		"optional pod sets are dropped when they don't fit": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("main").
+					PodSets(
+						*utiltesting.MakePodSet("driver", 20).
+							Request(corev1.ResourceCPU, "1").
+							Obj(),
+						*utiltesting.MakePodSet("workers", 20).
+							Annotations(map[string]string{kueue.PodSetOptionalAnnotation: "true"}).
+							Request(corev1.ResourceCPU, "1").
+							Obj(),
+						*utiltesting.MakePodSet("monitors", 20).
+							Annotations(map[string]string{kueue.PodSetOptionalAnnotation: "true"}).
+							Request(corev1.ResourceCPU, "1").
+							Obj(),
+					).
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/new": {
+					ClusterQueue: "sales",
+					PodSetAssignments: []kueue.PodSetAssignment{
+						{
+							Name: "driver",
 							Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
 								corev1.ResourceCPU: "default",
 							},
@@ -1113,7 +1704,7 @@ func TestSchedule(t *testing.T) {
 							Count: ptr.To[int32](20),
 						},
 						{
-							Name: "two",
+							Name: "workers",
 							Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
 								corev1.ResourceCPU: "default",
 							},
@@ -1123,20 +1714,39 @@ func TestSchedule(t *testing.T) {
 							Count: ptr.To[int32](20),
 						},
 						{
-							Name: "three",
+							Name: "monitors",
 							Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
 								corev1.ResourceCPU: "default",
 							},
 							ResourceUsage: corev1.ResourceList{
-								corev1.ResourceCPU: resource.MustParse("10000m"),
+								corev1.ResourceCPU: resource.MustParse("0"),
 							},
-							Count: ptr.To[int32](10),
+							Count: ptr.To[int32](0),
 						},
 					},
 				},
 			},
 			wantScheduled: []string{"sales/new"},
 		},
+		"optional pod sets don't make the required pod sets fit": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("new", "sales").
+					Queue("main").
+					PodSets(
+						*utiltesting.MakePodSet("driver", 60).
+							Request(corev1.ResourceCPU, "1").
+							Obj(),
+						*utiltesting.MakePodSet("workers", 20).
+							Annotations(map[string]string{kueue.PodSetOptionalAnnotation: "true"}).
+							Request(corev1.ResourceCPU, "1").
+							Obj(),
+					).
+					Obj(),
+			},
+			wantLeft: map[string][]string{
+				"sales": {"sales/new"},
+			},
+		},
 		"partial admission disabled, multiple variable pod sets": {
 			workloads: []kueue.Workload{
 				*utiltesting.MakeWorkload("new", "sales").
@@ -1449,6 +2059,76 @@ func TestSchedule(t *testing.T) {
 				"eng-beta": {"eng-beta/older_new"},
 			},
 		},
+		"with fair sharing: schedule workload from the ClusterQueue with the lowest weighted share first": {
+			enableFairSharing: true,
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("heavy").
+					Cohort("weighted").
+					FairWeight(resource.MustParse("3")).
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+				*utiltesting.MakeClusterQueue("light").
+					Cohort("weighted").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+				*utiltesting.MakeClusterQueue("lender").
+					Cohort("weighted").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "10").Obj(),
+					).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("heavy", "eng-alpha").ClusterQueue("heavy").Obj(),
+				*utiltesting.MakeLocalQueue("light", "eng-beta").ClusterQueue("light").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("borrowing", "eng-alpha").
+					Queue("heavy").
+					PodSets(*utiltesting.MakePodSet("one", 16).
+						Request(corev1.ResourceCPU, "1").
+						Obj()).
+					ReserveQuota(utiltesting.MakeAdmission("heavy", "one").Assignment(corev1.ResourceCPU, "on-demand", "16").AssignmentPodCount(16).Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("borrowing", "eng-beta").
+					Queue("light").
+					PodSets(*utiltesting.MakePodSet("one", 13).
+						Request(corev1.ResourceCPU, "1").
+						Obj()).
+					ReserveQuota(utiltesting.MakeAdmission("light", "one").Assignment(corev1.ResourceCPU, "on-demand", "13").AssignmentPodCount(13).Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("new", "eng-alpha").
+					Queue("heavy").
+					Creation(now).
+					PodSets(*utiltesting.MakePodSet("one", 1).
+						Request(corev1.ResourceCPU, "1").
+						Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("older_new", "eng-beta").
+					Queue("light").
+					Creation(now.Add(-time.Minute)).
+					PodSets(*utiltesting.MakePodSet("one", 1).
+						Request(corev1.ResourceCPU, "1").
+						Obj()).
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"eng-alpha/borrowing": *utiltesting.MakeAdmission("heavy", "one").Assignment(corev1.ResourceCPU, "on-demand", "16").AssignmentPodCount(16).Obj(),
+				"eng-beta/borrowing":  *utiltesting.MakeAdmission("light", "one").Assignment(corev1.ResourceCPU, "on-demand", "13").AssignmentPodCount(13).Obj(),
+				"eng-alpha/new":       *utiltesting.MakeAdmission("heavy", "one").Assignment(corev1.ResourceCPU, "on-demand", "1").AssignmentPodCount(1).Obj(),
+			},
+			wantScheduled: []string{"eng-alpha/new"},
+			wantLeft: map[string][]string{
+				"light": {"eng-beta/older_new"},
+			},
+		},
 		"minimal preemptions when target queue is exhausted": {
 			additionalClusterQueues: []kueue.ClusterQueue{
 				*utiltesting.MakeClusterQueue("other-alpha").
@@ -1582,72 +2262,355 @@ func TestSchedule(t *testing.T) {
 					Request(corev1.ResourceCPU, "3").
 					Obj(),
 			},
-			wantInadmissibleLeft: map[string][]string{
-				"other-alpha": {"eng-alpha/incoming"},
-			},
+			wantInadmissibleLeft: map[string][]string{
+				"other-alpha": {"eng-alpha/incoming"},
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"eng-alpha/a1": *utiltesting.MakeAdmission("other-alpha").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj(),
+				"eng-beta/b1":  *utiltesting.MakeAdmission("other-beta").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj(),
+			},
+		},
+		"with fair sharing: preempt workload from CQ with the highest share": {
+			enableFairSharing: true,
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("eng-gamma").
+					Cohort("eng").
+					ResourceGroup(
+						*utiltesting.MakeFlavorQuotas("on-demand").
+							Resource(corev1.ResourceCPU, "50", "0").Obj(),
+					).
+					Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("all_spot", "eng-alpha").
+					Request(corev1.ResourceCPU, "100").
+					SimpleReserveQuota("eng-alpha", "spot", now).Obj(),
+				*utiltesting.MakeWorkload("alpha1", "eng-alpha").UID("alpha1").
+					Request(corev1.ResourceCPU, "20").
+					SimpleReserveQuota("eng-alpha", "on-demand", now).Obj(),
+				*utiltesting.MakeWorkload("alpha2", "eng-alpha").UID("alpha2").
+					Request(corev1.ResourceCPU, "20").
+					SimpleReserveQuota("eng-alpha", "on-demand", now).Obj(),
+				*utiltesting.MakeWorkload("alpha3", "eng-alpha").UID("alpha3").
+					Request(corev1.ResourceCPU, "20").
+					SimpleReserveQuota("eng-alpha", "on-demand", now).Obj(),
+				*utiltesting.MakeWorkload("alpha4", "eng-alpha").UID("alpha4").
+					Request(corev1.ResourceCPU, "20").
+					SimpleReserveQuota("eng-alpha", "on-demand", now).Obj(),
+				*utiltesting.MakeWorkload("gamma1", "eng-gamma").UID("gamma1").
+					Request(corev1.ResourceCPU, "10").
+					SimpleReserveQuota("eng-gamma", "on-demand", now).Obj(),
+				*utiltesting.MakeWorkload("gamma2", "eng-gamma").UID("gamma2").
+					Request(corev1.ResourceCPU, "20").
+					SimpleReserveQuota("eng-gamma", "on-demand", now).Obj(),
+				*utiltesting.MakeWorkload("gamma3", "eng-gamma").UID("gamma3").
+					Request(corev1.ResourceCPU, "20").
+					SimpleReserveQuota("eng-gamma", "on-demand", now).Obj(),
+				*utiltesting.MakeWorkload("gamma4", "eng-gamma").UID("gamma4").
+					Request(corev1.ResourceCPU, "20").
+					SimpleReserveQuota("eng-gamma", "on-demand", now).Obj(),
+				*utiltesting.MakeWorkload("preemptor", "eng-beta").
+					Queue("main").
+					Request(corev1.ResourceCPU, "30").Obj(),
+			},
+			wantPreempted: sets.New("eng-alpha/alpha1", "eng-gamma/gamma1"),
+			wantLeft: map[string][]string{
+				// Preemptor is not admitted in this cycle.
+				"eng-beta": {"eng-beta/preemptor"},
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"eng-alpha/all_spot": *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "spot", "100").Obj(),
+				"eng-alpha/alpha1":   *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
+				"eng-alpha/alpha2":   *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
+				"eng-alpha/alpha3":   *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
+				"eng-alpha/alpha4":   *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
+				"eng-gamma/gamma1":   *utiltesting.MakeAdmission("eng-gamma").Assignment(corev1.ResourceCPU, "on-demand", "10").Obj(),
+				"eng-gamma/gamma2":   *utiltesting.MakeAdmission("eng-gamma").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
+				"eng-gamma/gamma3":   *utiltesting.MakeAdmission("eng-gamma").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
+				"eng-gamma/gamma4":   *utiltesting.MakeAdmission("eng-gamma").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
+			},
+		},
+		"borrowing stops once the cohort borrowing cap is reached": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("capped-a").
+					Cohort("capped").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("capped-b").
+					Cohort("capped").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("capped-c").
+					Cohort("capped").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "4").Obj()).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("capped-a", "lend").ClusterQueue("capped-a").Obj(),
+				*utiltesting.MakeLocalQueue("capped-b", "lend").ClusterQueue("capped-b").Obj(),
+			},
+			cohorts: []kueuealpha.Cohort{
+				*utiltesting.MakeCohort("capped").BorrowingCap("default", corev1.ResourceCPU, "2").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("admitted", "lend").
+					Request(corev1.ResourceCPU, "3").
+					ReserveQuota(utiltesting.MakeAdmission("capped-a").Assignment(corev1.ResourceCPU, "default", "3").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("a", "lend").
+					Queue("capped-a").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+				*utiltesting.MakeWorkload("b", "lend").
+					Queue("capped-b").
+					Request(corev1.ResourceCPU, "4").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"lend/admitted": *utiltesting.MakeAdmission("capped-a").Assignment(corev1.ResourceCPU, "default", "3").Obj(),
+				"lend/a":        *utiltesting.MakeAdmission("capped-a").Assignment(corev1.ResourceCPU, "default", "1").Obj(),
+			},
+			wantScheduled: []string{"lend/a"},
+			wantInadmissibleLeft: map[string][]string{
+				"capped-b": {"lend/b"},
+			},
+		},
+		"borrowing stops once the cohort borrowing cap is reached in the same cycle": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("capped-a").
+					Cohort("capped").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("capped-b").
+					Cohort("capped").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("capped-c").
+					Cohort("capped").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "4").Obj()).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("capped-a", "lend").ClusterQueue("capped-a").Obj(),
+				*utiltesting.MakeLocalQueue("capped-b", "lend").ClusterQueue("capped-b").Obj(),
+			},
+			cohorts: []kueuealpha.Cohort{
+				*utiltesting.MakeCohort("capped").BorrowingCap("default", corev1.ResourceCPU, "2").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "lend").
+					Queue("capped-a").
+					Priority(1).
+					Request(corev1.ResourceCPU, "3").
+					Obj(),
+				*utiltesting.MakeWorkload("b", "lend").
+					Queue("capped-b").
+					Request(corev1.ResourceCPU, "4").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"lend/a": *utiltesting.MakeAdmission("capped-a").Assignment(corev1.ResourceCPU, "default", "3").Obj(),
+			},
+			wantScheduled: []string{"lend/a"},
+			wantLeft: map[string][]string{
+				"capped-b": {"lend/b"},
+			},
+		},
+		"borrowing can't use the cohort borrowing safety margin": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("margin-a").
+					Cohort("margin").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("margin-b").
+					Cohort("margin").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "8").Obj()).
+					Preemption(kueue.ClusterQueuePreemption{
+						ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+					}).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("margin-a", "lend").ClusterQueue("margin-a").Obj(),
+				*utiltesting.MakeLocalQueue("margin-b", "lend").ClusterQueue("margin-b").Obj(),
+			},
+			cohorts: []kueuealpha.Cohort{
+				*utiltesting.MakeCohort("margin").BorrowingSafetyMarginPercent(20).Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "lend").
+					Queue("margin-a").
+					Request(corev1.ResourceCPU, "9").
+					Obj(),
+			},
+			wantInadmissibleLeft: map[string][]string{
+				"margin-a": {"lend/a"},
+			},
+		},
+		"borrowing up to the cohort borrowing safety margin": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("margin-a").
+					Cohort("margin").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("margin-b").
+					Cohort("margin").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "8").Obj()).
+					Preemption(kueue.ClusterQueuePreemption{
+						ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+					}).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("margin-a", "lend").ClusterQueue("margin-a").Obj(),
+				*utiltesting.MakeLocalQueue("margin-b", "lend").ClusterQueue("margin-b").Obj(),
+			},
+			cohorts: []kueuealpha.Cohort{
+				*utiltesting.MakeCohort("margin").BorrowingSafetyMarginPercent(20).Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("a", "lend").
+					Queue("margin-a").
+					Request(corev1.ResourceCPU, "8").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"lend/a": *utiltesting.MakeAdmission("margin-a").Assignment(corev1.ResourceCPU, "default", "8").Obj(),
+			},
+			wantScheduled: []string{"lend/a"},
+		},
+		"the cohort borrowing safety margin avoids reclaiming quota from borrowers": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("margin-a").
+					Cohort("margin").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("margin-b").
+					Cohort("margin").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "8").Obj()).
+					Preemption(kueue.ClusterQueuePreemption{
+						ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+					}).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("margin-a", "lend").ClusterQueue("margin-a").Obj(),
+				*utiltesting.MakeLocalQueue("margin-b", "lend").ClusterQueue("margin-b").Obj(),
+			},
+			cohorts: []kueuealpha.Cohort{
+				*utiltesting.MakeCohort("margin").BorrowingSafetyMarginPercent(20).Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("borrower", "lend").
+					Request(corev1.ResourceCPU, "8").
+					ReserveQuota(utiltesting.MakeAdmission("margin-a").Assignment(corev1.ResourceCPU, "default", "8").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("b", "lend").
+					Queue("margin-b").
+					Request(corev1.ResourceCPU, "2").
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"lend/borrower": *utiltesting.MakeAdmission("margin-a").Assignment(corev1.ResourceCPU, "default", "8").Obj(),
+				"lend/b":        *utiltesting.MakeAdmission("margin-b").Assignment(corev1.ResourceCPU, "default", "2").Obj(),
+			},
+			wantScheduled: []string{"lend/b"},
+		},
+		"without a cohort borrowing safety margin, quota is reclaimed from borrowers": {
+			additionalClusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("margin-a").
+					Cohort("margin").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("margin-b").
+					Cohort("margin").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "8").Obj()).
+					Preemption(kueue.ClusterQueuePreemption{
+						ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+					}).
+					Obj(),
+			},
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("margin-a", "lend").ClusterQueue("margin-a").Obj(),
+				*utiltesting.MakeLocalQueue("margin-b", "lend").ClusterQueue("margin-b").Obj(),
+			},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("borrower", "lend").
+					Request(corev1.ResourceCPU, "10").
+					ReserveQuota(utiltesting.MakeAdmission("margin-a").Assignment(corev1.ResourceCPU, "default", "10").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("b", "lend").
+					Queue("margin-b").
+					Request(corev1.ResourceCPU, "2").
+					Obj(),
+			},
 			wantAssignments: map[string]kueue.Admission{
-				"eng-alpha/a1": *utiltesting.MakeAdmission("other-alpha").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj(),
-				"eng-beta/b1":  *utiltesting.MakeAdmission("other-beta").Assignment(corev1.ResourceCPU, "on-demand", "1").Obj(),
+				"lend/borrower": *utiltesting.MakeAdmission("margin-a").Assignment(corev1.ResourceCPU, "default", "10").Obj(),
+			},
+			wantPreempted: sets.New("lend/borrower"),
+			wantLeft: map[string][]string{
+				"margin-b": {"lend/b"},
 			},
 		},
-		"with fair sharing: preempt workload from CQ with the highest share": {
-			enableFairSharing: true,
+		"device class quota gates workloads by their resource claims": {
+			enableDynamicResourceAllocation: true,
 			additionalClusterQueues: []kueue.ClusterQueue{
-				*utiltesting.MakeClusterQueue("eng-gamma").
-					Cohort("eng").
-					ResourceGroup(
-						*utiltesting.MakeFlavorQuotas("on-demand").
-							Resource(corev1.ResourceCPU, "50", "0").Obj(),
-					).
+				*utiltesting.MakeClusterQueue("dra-a").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "10").
+						Resource("gpu.example.com", "4").Obj()).
+					Obj(),
+				*utiltesting.MakeClusterQueue("dra-b").
+					ResourceGroup(*utiltesting.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "10").
+						Resource("gpu.example.com", "4").Obj()).
 					Obj(),
 			},
-			workloads: []kueue.Workload{
-				*utiltesting.MakeWorkload("all_spot", "eng-alpha").
-					Request(corev1.ResourceCPU, "100").
-					SimpleReserveQuota("eng-alpha", "spot", now).Obj(),
-				*utiltesting.MakeWorkload("alpha1", "eng-alpha").UID("alpha1").
-					Request(corev1.ResourceCPU, "20").
-					SimpleReserveQuota("eng-alpha", "on-demand", now).Obj(),
-				*utiltesting.MakeWorkload("alpha2", "eng-alpha").UID("alpha2").
-					Request(corev1.ResourceCPU, "20").
-					SimpleReserveQuota("eng-alpha", "on-demand", now).Obj(),
-				*utiltesting.MakeWorkload("alpha3", "eng-alpha").UID("alpha3").
-					Request(corev1.ResourceCPU, "20").
-					SimpleReserveQuota("eng-alpha", "on-demand", now).Obj(),
-				*utiltesting.MakeWorkload("alpha4", "eng-alpha").UID("alpha4").
-					Request(corev1.ResourceCPU, "20").
-					SimpleReserveQuota("eng-alpha", "on-demand", now).Obj(),
-				*utiltesting.MakeWorkload("gamma1", "eng-gamma").UID("gamma1").
-					Request(corev1.ResourceCPU, "10").
-					SimpleReserveQuota("eng-gamma", "on-demand", now).Obj(),
-				*utiltesting.MakeWorkload("gamma2", "eng-gamma").UID("gamma2").
-					Request(corev1.ResourceCPU, "20").
-					SimpleReserveQuota("eng-gamma", "on-demand", now).Obj(),
-				*utiltesting.MakeWorkload("gamma3", "eng-gamma").UID("gamma3").
-					Request(corev1.ResourceCPU, "20").
-					SimpleReserveQuota("eng-gamma", "on-demand", now).Obj(),
-				*utiltesting.MakeWorkload("gamma4", "eng-gamma").UID("gamma4").
-					Request(corev1.ResourceCPU, "20").
-					SimpleReserveQuota("eng-gamma", "on-demand", now).Obj(),
-				*utiltesting.MakeWorkload("preemptor", "eng-beta").
-					Queue("main").
-					Request(corev1.ResourceCPU, "30").Obj(),
+			additionalLocalQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("dra-a", "sales").ClusterQueue("dra-a").Obj(),
+				*utiltesting.MakeLocalQueue("dra-b", "sales").ClusterQueue("dra-b").Obj(),
 			},
-			wantPreempted: sets.New("eng-alpha/alpha1", "eng-gamma/gamma1"),
-			wantLeft: map[string][]string{
-				// Preemptor is not admitted in this cycle.
-				"eng-beta": {"eng-beta/preemptor"},
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("fits", "sales").
+					Queue("dra-a").
+					PodSets(*utiltesting.MakePodSet("main", 2).
+						Request(corev1.ResourceCPU, "1").
+						ResourceClaim("gpu-0", "gpu.example.com").
+						ResourceClaim("gpu-1", "gpu.example.com").
+						Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("too-many-claims", "sales").
+					Queue("dra-b").
+					PodSets(*utiltesting.MakePodSet("main", 5).
+						Request(corev1.ResourceCPU, "1").
+						ResourceClaim("gpu", "gpu.example.com").
+						Obj()).
+					Obj(),
 			},
 			wantAssignments: map[string]kueue.Admission{
-				"eng-alpha/all_spot": *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "spot", "100").Obj(),
-				"eng-alpha/alpha1":   *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
-				"eng-alpha/alpha2":   *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
-				"eng-alpha/alpha3":   *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
-				"eng-alpha/alpha4":   *utiltesting.MakeAdmission("eng-alpha").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
-				"eng-gamma/gamma1":   *utiltesting.MakeAdmission("eng-gamma").Assignment(corev1.ResourceCPU, "on-demand", "10").Obj(),
-				"eng-gamma/gamma2":   *utiltesting.MakeAdmission("eng-gamma").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
-				"eng-gamma/gamma3":   *utiltesting.MakeAdmission("eng-gamma").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
-				"eng-gamma/gamma4":   *utiltesting.MakeAdmission("eng-gamma").Assignment(corev1.ResourceCPU, "on-demand", "20").Obj(),
+				"sales/fits": *utiltesting.MakeAdmission("dra-a").
+					Assignment(corev1.ResourceCPU, "default", "2").
+					Assignment("gpu.example.com", "default", "4").
+					AssignmentPodCount(2).
+					Obj(),
+			},
+			wantScheduled: []string{"sales/fits"},
+			wantInadmissibleLeft: map[string][]string{
+				"dra-b": {"sales/too-many-claims"},
 			},
 		},
 		"not enough resources": {
@@ -1687,6 +2650,9 @@ func TestSchedule(t *testing.T) {
 			if tc.disablePartialAdmission {
 				defer features.SetFeatureGateDuringTest(t, features.PartialAdmission, false)()
 			}
+			if tc.enableDynamicResourceAllocation {
+				defer features.SetFeatureGateDuringTest(t, features.DynamicResourceAllocation, true)()
+			}
 			ctx, _ := utiltesting.ContextWithLog(t)
 
 			allQueues := append(queues, tc.additionalLocalQueues...)
@@ -1714,6 +2680,9 @@ func TestSchedule(t *testing.T) {
 			for i := range resourceFlavors {
 				cqCache.AddOrUpdateResourceFlavor(resourceFlavors[i])
 			}
+			for i := range tc.cohorts {
+				cqCache.AddOrUpdateCohort(&tc.cohorts[i])
+			}
 			for _, cq := range allClusterQueues {
 				if err := cqCache.AddClusterQueue(ctx, &cq); err != nil {
 					t.Fatalf("Inserting clusterQueue %s in cache: %v", cq.Name, err)
@@ -1974,6 +2943,248 @@ func TestEntryOrdering(t *testing.T) {
 	}
 }
 
+// testScheduler is a Scheduler, with the cache and the queues it schedules
+// from, for the tests that exercise a single feature of the scheduler
+// outside of TestSchedule.
+type testScheduler struct {
+	*Scheduler
+	cache  *cache.Cache
+	queues *queue.Manager
+	// wg tracks the admission routines.
+	wg sync.WaitGroup
+
+	mu        sync.Mutex
+	scheduled []string
+}
+
+// newTestScheduler returns a testScheduler, and a context that bounds the
+// test, with the flavors, ClusterQueues and LocalQueues added to the cache
+// and the queues. The admissions are recorded instead of applied.
+func newTestScheduler(t *testing.T, cl client.Client, flavors []*kueue.ResourceFlavor, cqs []*kueue.ClusterQueue, qs []*kueue.LocalQueue, opts ...Option) (context.Context, *testScheduler) {
+	t.Helper()
+	ctx, _ := utiltesting.ContextWithLog(t)
+	s := &testScheduler{cache: cache.New(cl)}
+	s.queues = queue.NewManager(cl, s.cache)
+	for _, rf := range flavors {
+		s.cache.AddOrUpdateResourceFlavor(rf)
+	}
+	for _, cq := range cqs {
+		if err := s.cache.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Inserting clusterQueue %s in cache: %v", cq.Name, err)
+		}
+		if err := s.queues.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Inserting clusterQueue %s in manager: %v", cq.Name, err)
+		}
+	}
+	for _, q := range qs {
+		if err := s.queues.AddLocalQueue(ctx, q); err != nil {
+			t.Fatalf("Inserting queue %s/%s in manager: %v", q.Namespace, q.Name, err)
+		}
+	}
+
+	s.Scheduler = New(s.queues, s.cache, cl, &utiltesting.EventRecorder{}, opts...)
+	s.applyAdmission = func(_ context.Context, w *kueue.Workload) error {
+		s.mu.Lock()
+		s.scheduled = append(s.scheduled, workload.Key(w))
+		s.mu.Unlock()
+		return nil
+	}
+	s.setAdmissionRoutineWrapper(routine.NewWrapper(
+		func() { s.wg.Add(1) },
+		func() { s.wg.Done() },
+	))
+
+	ctx, cancel := context.WithTimeout(ctx, queueingTimeout)
+	t.Cleanup(cancel)
+	go s.queues.CleanUpOnContext(ctx)
+	return ctx, s
+}
+
+// addPending adds the workloads to the queues.
+func (s *testScheduler) addPending(t *testing.T, wls ...*kueue.Workload) {
+	t.Helper()
+	for _, wl := range wls {
+		if !s.queues.AddOrUpdateWorkload(wl) {
+			t.Fatalf("Failed adding workload %s to the queues", workload.Key(wl))
+		}
+	}
+}
+
+// scheduleAndWait runs a scheduling cycle and waits for its admissions.
+func (s *testScheduler) scheduleAndWait(ctx context.Context) wait.SpeedSignal {
+	got := s.schedule(ctx)
+	s.wg.Wait()
+	return got
+}
+
+// gotScheduled returns the keys of the workloads admitted so far.
+func (s *testScheduler) gotScheduled() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scheduled
+}
+
+func TestSchedulePause(t *testing.T) {
+	rf := utiltesting.MakeResourceFlavor("default").Obj()
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+		Obj()
+	q := utiltesting.MakeLocalQueue("q", "ns").ClusterQueue(cq.Name).Obj()
+	wl := utiltesting.MakeWorkload("wl", "ns").Queue(q.Name).Request(corev1.ResourceCPU, "1").Obj()
+
+	cl := utiltesting.NewClientBuilder().
+		WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}, wl).
+		Build()
+	ctx, scheduler := newTestScheduler(t, cl, []*kueue.ResourceFlavor{rf}, []*kueue.ClusterQueue{cq}, []*kueue.LocalQueue{q})
+	scheduler.addPending(t, wl)
+
+	scheduler.Pause()
+	if !scheduler.Paused() {
+		t.Fatal("The scheduler should be paused")
+	}
+	if got := scheduler.scheduleAndWait(ctx); got != wait.SlowDown {
+		t.Errorf("schedule() returned %v while paused, want %v", got, wait.SlowDown)
+	}
+	if got := scheduler.gotScheduled(); len(got) != 0 {
+		t.Errorf("Workloads %v were admitted while the scheduler was paused", got)
+	}
+	if diff := cmp.Diff(map[string][]string{"cq": {"ns/wl"}}, scheduler.queues.Dump(), cmpDump...); diff != "" {
+		t.Errorf("Unexpected elements in the cluster queue while paused (-want,+got):\n%s", diff)
+	}
+
+	scheduler.Resume()
+	if scheduler.Paused() {
+		t.Fatal("The scheduler should not be paused")
+	}
+	scheduler.scheduleAndWait(ctx)
+	if diff := cmp.Diff([]string{"ns/wl"}, scheduler.gotScheduled()); diff != "" {
+		t.Errorf("Unexpected scheduled workloads after resuming (-want,+got):\n%s", diff)
+	}
+}
+
+func TestScheduleDecisionPublisher(t *testing.T) {
+	rf := utiltesting.MakeResourceFlavor("default").Obj()
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "1").Obj()).
+		Obj()
+	q := utiltesting.MakeLocalQueue("q", "ns").ClusterQueue(cq.Name).Obj()
+	wls := []*kueue.Workload{
+		utiltesting.MakeWorkload("admitted", "ns").Queue(q.Name).Request(corev1.ResourceCPU, "1").Obj(),
+		utiltesting.MakeWorkload("pending", "ns").Queue(q.Name).Request(corev1.ResourceCPU, "1").Creation(time.Now().Add(time.Second)).Obj(),
+	}
+
+	cl := utiltesting.NewClientBuilder().
+		WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}).
+		Build()
+	store := decisions.NewMemoryStore()
+	ctx, scheduler := newTestScheduler(t, cl, []*kueue.ResourceFlavor{rf}, []*kueue.ClusterQueue{cq}, []*kueue.LocalQueue{q}, WithDecisionPublisher(store))
+	scheduler.addPending(t, wls...)
+
+	scheduler.scheduleAndWait(ctx)
+	got, err := store.Observe(ctx)
+	if err != nil {
+		t.Fatalf("Failed to observe decisions: %v", err)
+	}
+	want := []decisions.Decision{{
+		Namespace:         "ns",
+		Name:              "admitted",
+		ClusterQueue:      "cq",
+		PodSetAssignments: utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").Obj().PodSetAssignments,
+	}}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(decisions.Decision{}, "Time")); diff != "" {
+		t.Errorf("Unexpected published decisions (-want,+got):\n%s", diff)
+	}
+}
+
+func TestWorkloadAdmissionAttemptsMetric(t *testing.T) {
+	rf := utiltesting.MakeResourceFlavor("default").Obj()
+	cqs := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("attempts-fit").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "10").Obj()).
+			Obj(),
+		utiltesting.MakeClusterQueue("attempts-full").
+			ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "1").Obj()).
+			Obj(),
+	}
+	qs := []*kueue.LocalQueue{
+		utiltesting.MakeLocalQueue("fit", "ns").ClusterQueue("attempts-fit").Obj(),
+		utiltesting.MakeLocalQueue("full", "ns").ClusterQueue("attempts-full").Obj(),
+	}
+	wls := []*kueue.Workload{
+		utiltesting.MakeWorkload("fits", "ns").Queue("fit").Request(corev1.ResourceCPU, "1").Obj(),
+		utiltesting.MakeWorkload("too-big", "ns").Queue("full").Request(corev1.ResourceCPU, "2").Obj(),
+	}
+
+	cl := utiltesting.NewClientBuilder().
+		WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}, wls[0], wls[1]).
+		WithStatusSubresource(wls[0], wls[1]).
+		WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: utiltesting.TreatSSAAsStrategicMerge}).
+		Build()
+	for _, cq := range cqs {
+		metrics.ClearQueueSystemMetrics(cq.Name)
+	}
+	ctx, scheduler := newTestScheduler(t, cl, []*kueue.ResourceFlavor{rf}, cqs, qs)
+	scheduler.addPending(t, wls...)
+
+	scheduler.scheduleAndWait(ctx)
+
+	want := []testingmetrics.MetricDataPoint{
+		{Labels: map[string]string{"cluster_queue": "attempts-fit", "result": "admitted"}, Value: 1},
+		{Labels: map[string]string{"cluster_queue": "attempts-full", "result": "pending"}, Value: 1},
+	}
+	var got []testingmetrics.MetricDataPoint
+	for _, cq := range cqs {
+		got = append(got, testingmetrics.CollectFilteredGaugeVec(metrics.WorkloadAdmissionAttemptsTotal, map[string]string{"cluster_queue": cq.Name})...)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected admission attempts (-want,+got):\n%s", diff)
+	}
+}
+
+func TestScheduleQueueingStrategyHeadOfLineBlocking(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		strategy      kueue.QueueingStrategy
+		wantScheduled []string
+	}{
+		"StrictFIFO blocks the workloads behind a head that doesn't fit": {
+			strategy: kueue.StrictFIFO,
+		},
+		"BestEffortFIFO admits the workloads behind a head that doesn't fit": {
+			strategy:      kueue.BestEffortFIFO,
+			wantScheduled: []string{"ns/small"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rf := utiltesting.MakeResourceFlavor("default").Obj()
+			cq := utiltesting.MakeClusterQueue("cq").
+				QueueingStrategy(tc.strategy).
+				ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "2").Obj()).
+				Obj()
+			q := utiltesting.MakeLocalQueue("q", "ns").ClusterQueue(cq.Name).Obj()
+			wls := []*kueue.Workload{
+				utiltesting.MakeWorkload("big", "ns").Queue(q.Name).Request(corev1.ResourceCPU, "3").Creation(now).Obj(),
+				utiltesting.MakeWorkload("small", "ns").Queue(q.Name).Request(corev1.ResourceCPU, "1").Creation(now.Add(time.Second)).Obj(),
+			}
+
+			cl := utiltesting.NewClientBuilder().
+				WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}, wls[0], wls[1]).
+				WithStatusSubresource(wls[0], wls[1]).
+				Build()
+			ctx, scheduler := newTestScheduler(t, cl, []*kueue.ResourceFlavor{rf}, []*kueue.ClusterQueue{cq}, []*kueue.LocalQueue{q})
+			scheduler.addPending(t, wls...)
+
+			// The first cycle attempts the head, the second one the workload behind it.
+			scheduler.scheduleAndWait(ctx)
+			scheduler.scheduleAndWait(ctx)
+			if diff := cmp.Diff(tc.wantScheduled, scheduler.gotScheduled()); diff != "" {
+				t.Errorf("Unexpected scheduled workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestLastSchedulingContext(t *testing.T) {
 	resourceFlavors := []*kueue.ResourceFlavor{
 		{ObjectMeta: metav1.ObjectMeta{Name: "on-demand"}},
@@ -2425,6 +3636,79 @@ func TestRequeueAndUpdate(t *testing.T) {
 			},
 			wantStatusUpdates: 1,
 		},
+		{
+			name: "workload didn't fit, with candidate flavors",
+			e: entry{
+				inadmissibleMsg: "didn't fit",
+				candidateFlavors: []kueue.PodSetCandidateFlavors{
+					{Name: "main", Flavors: []kueue.ResourceFlavorReference{"on-demand", "spot"}},
+				},
+			},
+			wantStatus: kueue.WorkloadStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    kueue.WorkloadQuotaReserved,
+						Status:  metav1.ConditionFalse,
+						Reason:  "Pending",
+						Message: "didn't fit",
+					},
+				},
+				CandidateFlavors: []kueue.PodSetCandidateFlavors{
+					{Name: "main", Flavors: []kueue.ResourceFlavorReference{"on-demand", "spot"}},
+				},
+			},
+			wantInadmissible: map[string][]string{
+				"cq": {workload.Key(w1)},
+			},
+			wantStatusUpdates: 1,
+		},
+		{
+			name: "workload didn't fit, without matching flavors",
+			e: entry{
+				inadmissibleMsg: "didn't fit",
+				candidateFlavors: []kueue.PodSetCandidateFlavors{
+					{Name: "main"},
+				},
+			},
+			wantStatus: kueue.WorkloadStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    kueue.WorkloadQuotaReserved,
+						Status:  metav1.ConditionFalse,
+						Reason:  "Pending",
+						Message: "didn't fit",
+					},
+				},
+				CandidateFlavors: []kueue.PodSetCandidateFlavors{
+					{Name: "main"},
+				},
+			},
+			wantInadmissible: map[string][]string{
+				"cq": {workload.Key(w1)},
+			},
+			wantStatusUpdates: 1,
+		},
+		{
+			name: "workload requests more than the capacity",
+			e: entry{
+				inadmissibleMsg:    "cpu of podSet main requests 2 per pod, more than the maximum capacity of 1 of any flavor",
+				inadmissibleReason: kueue.WorkloadRequestExceedsCapacity,
+			},
+			wantStatus: kueue.WorkloadStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    kueue.WorkloadQuotaReserved,
+						Status:  metav1.ConditionFalse,
+						Reason:  kueue.WorkloadRequestExceedsCapacity,
+						Message: "cpu of podSet main requests 2 per pod, more than the maximum capacity of 1 of any flavor",
+					},
+				},
+			},
+			wantInadmissible: map[string][]string{
+				"cq": {workload.Key(w1)},
+			},
+			wantStatusUpdates: 1,
+		},
 		{
 			name: "assumed",
 			e: entry{
@@ -2559,7 +3843,7 @@ func TestResourcesToReserve(t *testing.T) {
 	cases := []struct {
 		name            string
 		assignmentMode  flavorassigner.FlavorAssignmentMode
-		borrowing       bool
+		borrowing       sets.Set[kueue.ResourceFlavorReference]
 		assignmentUsage resources.FlavorResourceQuantities
 		cqUsage         resources.FlavorResourceQuantities
 		wantReserved    resources.FlavorResourceQuantities
@@ -2639,7 +3923,7 @@ func TestResourcesToReserve(t *testing.T) {
 		{
 			name:           "Reserved memory cut by nominal+borrowing quota, assignment preempts and borrows",
 			assignmentMode: flavorassigner.Preempt,
-			borrowing:      true,
+			borrowing:      sets.New[kueue.ResourceFlavorReference]("spot", "model-b"),
 			assignmentUsage: resources.FlavorResourceQuantitiesFlat{
 				{Flavor: kueue.ResourceFlavorReference("spot"), Resource: corev1.ResourceMemory}: 50,
 				{Flavor: kueue.ResourceFlavorReference("model-b"), Resource: "gpu"}:              2,
@@ -2658,7 +3942,7 @@ func TestResourcesToReserve(t *testing.T) {
 		{
 			name:           "Reserved memory equal assignment usage, CQ borrowing limit is nil",
 			assignmentMode: flavorassigner.Preempt,
-			borrowing:      true,
+			borrowing:      sets.New[kueue.ResourceFlavorReference]("on-demand", "model-b"),
 			assignmentUsage: resources.FlavorResourceQuantitiesFlat{
 				{Flavor: kueue.ResourceFlavorReference("on-demand"), Resource: corev1.ResourceMemory}: 50,
 				{Flavor: kueue.ResourceFlavorReference("model-b"), Resource: "gpu"}:                   2,
@@ -2674,23 +3958,50 @@ func TestResourcesToReserve(t *testing.T) {
 				{Flavor: kueue.ResourceFlavorReference("model-b"), Resource: "gpu"}:                   2,
 			}.Unflatten(),
 		},
+		{
+			name:           "Reserved usage evaluated per flavor, assignment borrows only in one flavor",
+			assignmentMode: flavorassigner.Preempt,
+			borrowing:      sets.New[kueue.ResourceFlavorReference]("spot"),
+			assignmentUsage: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: kueue.ResourceFlavorReference("spot"), Resource: corev1.ResourceMemory}: 50,
+				{Flavor: kueue.ResourceFlavorReference("model-b"), Resource: "gpu"}:              2,
+			}.Unflatten(),
+			cqUsage: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: kueue.ResourceFlavorReference("on-demand"), Resource: corev1.ResourceMemory}: 60,
+				{Flavor: kueue.ResourceFlavorReference("spot"), Resource: corev1.ResourceMemory}:      60,
+				{Flavor: kueue.ResourceFlavorReference("model-a"), Resource: "gpu"}:                   2,
+				{Flavor: kueue.ResourceFlavorReference("model-b"), Resource: "gpu"}:                   10,
+			}.Unflatten(),
+			wantReserved: resources.FlavorResourceQuantitiesFlat{
+				{Flavor: kueue.ResourceFlavorReference("spot"), Resource: corev1.ResourceMemory}: 40,
+				{Flavor: kueue.ResourceFlavorReference("model-b"), Resource: "gpu"}:              0,
+			}.Unflatten(),
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx, _ := utiltesting.ContextWithLog(t)
+			flavorAssignment := func(rName corev1.ResourceName) *flavorassigner.FlavorAssignment {
+				for fName, usage := range tc.assignmentUsage {
+					if _, found := usage[rName]; found {
+						return &flavorassigner.FlavorAssignment{Name: fName, Mode: tc.assignmentMode, Borrow: tc.borrowing.Has(fName)}
+					}
+				}
+				return &flavorassigner.FlavorAssignment{Mode: tc.assignmentMode}
+			}
 			assignment := flavorassigner.Assignment{
 				PodSets: []flavorassigner.PodSetAssignment{{
 					Name:    "memory",
 					Status:  &flavorassigner.Status{},
-					Flavors: flavorassigner.ResourceAssignment{corev1.ResourceMemory: &flavorassigner.FlavorAssignment{Mode: tc.assignmentMode}},
+					Flavors: flavorassigner.ResourceAssignment{corev1.ResourceMemory: flavorAssignment(corev1.ResourceMemory)},
 				},
 					{
 						Name:    "gpu",
 						Status:  &flavorassigner.Status{},
-						Flavors: flavorassigner.ResourceAssignment{"gpu": &flavorassigner.FlavorAssignment{Mode: tc.assignmentMode}},
+						Flavors: flavorassigner.ResourceAssignment{"gpu": flavorAssignment("gpu")},
 					},
 				},
-				Borrowing: tc.borrowing,
+				Borrowing: tc.borrowing.Len() > 0,
 				Usage:     tc.assignmentUsage,
 			}
 			e := &entry{assignment: assignment}
@@ -2715,3 +4026,83 @@ func TestResourcesToReserve(t *testing.T) {
 		})
 	}
 }
+
+func TestCanFit(t *testing.T) {
+	rf := utiltesting.MakeResourceFlavor("default").Obj()
+	cq := utiltesting.MakeClusterQueue("cq").
+		ResourceGroup(*utiltesting.MakeFlavorQuotas("default").Resource(corev1.ResourceCPU, "2").Obj()).
+		Preemption(kueue.ClusterQueuePreemption{
+			WithinClusterQueue: kueue.PreemptionPolicyLowerPriority,
+		}).
+		Obj()
+	q := utiltesting.MakeLocalQueue("q", "ns").ClusterQueue(cq.Name).Obj()
+	admitted := utiltesting.MakeWorkload("admitted", "ns").
+		Queue(q.Name).
+		Request(corev1.ResourceCPU, "1").
+		ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").Obj()).
+		Obj()
+
+	cl := utiltesting.NewClientBuilder().
+		WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}).
+		Build()
+	ctx, scheduler := newTestScheduler(t, cl, []*kueue.ResourceFlavor{rf}, []*kueue.ClusterQueue{cq}, []*kueue.LocalQueue{q})
+	if !scheduler.cache.AddOrUpdateWorkload(admitted) {
+		t.Fatalf("Failed adding workload %s to the cache", workload.Key(admitted))
+	}
+
+	cases := map[string]struct {
+		wl          *kueue.Workload
+		wantFits    bool
+		wantMessage string
+	}{
+		"fits": {
+			wl:          utiltesting.MakeWorkload("wl", "ns").Queue(q.Name).Request(corev1.ResourceCPU, "1").Obj(),
+			wantFits:    true,
+			wantMessage: "Fits in ClusterQueue cq (main: cpu=default)",
+		},
+		"doesn't fit": {
+			wl:          utiltesting.MakeWorkload("wl", "ns").Queue(q.Name).Request(corev1.ResourceCPU, "2").Obj(),
+			wantMessage: "couldn't assign flavors to pod set main: insufficient unused quota for cpu in flavor default, 1 more needed",
+		},
+		"doesn't fit with the requests defaulted to the limits": {
+			wl:          utiltesting.MakeWorkload("wl", "ns").Queue(q.Name).Limit(corev1.ResourceCPU, "2").Obj(),
+			wantMessage: "couldn't assign flavors to pod set main: insufficient unused quota for cpu in flavor default, 1 more needed",
+		},
+		"fits by preempting": {
+			wl:          utiltesting.MakeWorkload("wl", "ns").Queue(q.Name).Priority(10).Request(corev1.ResourceCPU, "2").Obj(),
+			wantMessage: "couldn't assign flavors to pod set main: insufficient unused quota for cpu in flavor default, 1 more needed; it fits by preempting 1 workloads",
+		},
+		"LocalQueue doesn't exist": {
+			wl:          utiltesting.MakeWorkload("wl", "ns").Queue("missing").Request(corev1.ResourceCPU, "1").Obj(),
+			wantMessage: "LocalQueue missing doesn't exist",
+		},
+		"evaluated as a new workload when it's already admitted": {
+			wl:          admitted,
+			wantFits:    true,
+			wantMessage: "Fits in ClusterQueue cq (main: cpu=default)",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			original := tc.wl.DeepCopy()
+			fits, msg := scheduler.CanFit(ctx, tc.wl)
+			if fits != tc.wantFits {
+				t.Errorf("CanFit returned fits=%t, want %t", fits, tc.wantFits)
+			}
+			if msg != tc.wantMessage {
+				t.Errorf("Unexpected message, want %q, got %q", tc.wantMessage, msg)
+			}
+			if diff := cmp.Diff(original, tc.wl); diff != "" {
+				t.Errorf("Unexpected changes to the workload (-want,+got):\n%s", diff)
+			}
+		})
+	}
+
+	snapshot := scheduler.cache.Snapshot()
+	if diff := cmp.Diff(sets.New("ns/admitted"), sets.KeySet(snapshot.ClusterQueues["cq"].Workloads)); diff != "" {
+		t.Errorf("Unexpected workloads in the cache after the simulations (-want,+got):\n%s", diff)
+	}
+	if dump := scheduler.queues.Dump(); len(dump) != 0 {
+		t.Errorf("Unexpected elements in the queues after the simulations: %v", dump)
+	}
+}
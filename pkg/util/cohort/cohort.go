@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cohort
+
+import "k8s.io/apimachinery/pkg/util/sets"
+
+// Root returns the root of the hierarchy of the cohort, following the
+// parents returned by parentOf, which returns an empty string for a cohort
+// without parent. If the parents of the cohort form a cycle, the cohort is
+// treated as a root.
+func Root(name string, parentOf func(string) string) string {
+	path := Path(name, parentOf)
+	return path[len(path)-1]
+}
+
+// Path returns the cohort followed by its ancestors, up to the root of its
+// hierarchy, following the parents returned by parentOf. If the parents of
+// the cohort form a cycle, the cohort is treated as a root.
+func Path(name string, parentOf func(string) string) []string {
+	visited := sets.New(name)
+	path := []string{name}
+	for {
+		parent := parentOf(path[len(path)-1])
+		if parent == "" {
+			return path
+		}
+		if visited.Has(parent) {
+			return []string{name}
+		}
+		visited.Insert(parent)
+		path = append(path, parent)
+	}
+}
+
+// Cycle returns the cohorts that form the cycle reached by following the
+// parents of the cohort, starting and ending with the same cohort, or nil if
+// the hierarchy of the cohort has a root.
+func Cycle(name string, parentOf func(string) string) []string {
+	index := map[string]int{name: 0}
+	path := []string{name}
+	for {
+		parent := parentOf(path[len(path)-1])
+		if parent == "" {
+			return nil
+		}
+		if i, found := index[parent]; found {
+			return append(path[i:], parent)
+		}
+		index[parent] = len(path)
+		path = append(path, parent)
+	}
+}
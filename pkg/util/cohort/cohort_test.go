@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cohort
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRoot(t *testing.T) {
+	parents := map[string]string{
+		"team-a":  "dept",
+		"dept":    "org",
+		"cycle-a": "cycle-b",
+		"cycle-b": "cycle-a",
+		"below":   "cycle-a",
+		"self":    "self",
+	}
+	parentOf := func(name string) string { return parents[name] }
+	cases := map[string]string{
+		"team-a":  "org",
+		"dept":    "org",
+		"org":     "org",
+		"flat":    "flat",
+		"cycle-a": "cycle-a",
+		"cycle-b": "cycle-b",
+		"below":   "below",
+		"self":    "self",
+	}
+	for name, want := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Root(name, parentOf); got != want {
+				t.Errorf("Unexpected root, want %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	parents := map[string]string{
+		"team-a":  "dept",
+		"dept":    "org",
+		"cycle-a": "cycle-b",
+		"cycle-b": "cycle-a",
+		"below":   "cycle-a",
+	}
+	parentOf := func(name string) string { return parents[name] }
+	cases := map[string][]string{
+		"team-a":  {"team-a", "dept", "org"},
+		"dept":    {"dept", "org"},
+		"flat":    {"flat"},
+		"cycle-a": {"cycle-a"},
+		"below":   {"below"},
+	}
+	for name, want := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(want, Path(name, parentOf)); diff != "" {
+				t.Errorf("Unexpected path (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCycle(t *testing.T) {
+	parents := map[string]string{
+		"team-a":  "dept",
+		"dept":    "org",
+		"cycle-a": "cycle-b",
+		"cycle-b": "cycle-c",
+		"cycle-c": "cycle-b",
+		"below":   "cycle-a",
+		"self":    "self",
+	}
+	parentOf := func(name string) string { return parents[name] }
+	cases := map[string][]string{
+		"team-a":  nil,
+		"flat":    nil,
+		"cycle-a": {"cycle-b", "cycle-c", "cycle-b"},
+		"cycle-b": {"cycle-b", "cycle-c", "cycle-b"},
+		"below":   {"cycle-b", "cycle-c", "cycle-b"},
+		"self":    {"self", "self"},
+	}
+	for name, want := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(want, Cycle(name, parentOf)); diff != "" {
+				t.Errorf("Unexpected cycle (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"0 22 * *",
+		"0 22 * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := Parse(spec); err == nil {
+				t.Errorf("Parse(%q) succeeded, want an error", spec)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// 2024-03-01 is a Friday.
+	from := time.Date(2024, time.March, 1, 12, 30, 45, 0, time.UTC)
+	cases := map[string]struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		"every minute": {
+			spec: "* * * * *",
+			from: from,
+			want: time.Date(2024, time.March, 1, 12, 31, 0, 0, time.UTC),
+		},
+		"later the same day": {
+			spec: "0 22 * * *",
+			from: from,
+			want: time.Date(2024, time.March, 1, 22, 0, 0, 0, time.UTC),
+		},
+		"the next day": {
+			spec: "0 6 * * *",
+			from: from,
+			want: time.Date(2024, time.March, 2, 6, 0, 0, 0, time.UTC),
+		},
+		"not at the same time": {
+			spec: "0 22 * * *",
+			from: time.Date(2024, time.March, 1, 22, 0, 0, 0, time.UTC),
+			want: time.Date(2024, time.March, 2, 22, 0, 0, 0, time.UTC),
+		},
+		"weekdays": {
+			spec: "0 22 * * 1-5",
+			from: time.Date(2024, time.March, 1, 23, 0, 0, 0, time.UTC),
+			want: time.Date(2024, time.March, 4, 22, 0, 0, 0, time.UTC),
+		},
+		"sunday as 7": {
+			spec: "0 0 * * 7",
+			from: from,
+			want: time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC),
+		},
+		"steps and lists": {
+			spec: "15,45 */6 * * *",
+			from: from,
+			want: time.Date(2024, time.March, 1, 12, 45, 0, 0, time.UTC),
+		},
+		"day of the month or of the week": {
+			spec: "0 0 15 * 0",
+			from: from,
+			want: time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC),
+		},
+		"leap day": {
+			spec: "0 0 29 2 *",
+			from: from,
+			want: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		"never": {
+			spec: "0 0 30 2 *",
+			from: from,
+		},
+		"in another time zone": {
+			spec: "0 22 * * *",
+			from: time.Date(2024, time.March, 1, 23, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+			want: time.Date(2024, time.March, 1, 22, 0, 0, 0, time.UTC),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse(tc.spec)
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %v", tc.spec, err)
+			}
+			if got := s.Next(tc.from); !got.Equal(tc.want) {
+				t.Errorf("Unexpected next activation, want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -147,6 +147,15 @@ func (h *Heap[T]) Pop() *T {
 	return heap.Pop(&h.data).(*T)
 }
 
+// Peek returns the head of the heap without removing it, or nil if the heap
+// is empty.
+func (h *Heap[T]) Peek() *T {
+	if h.Len() == 0 {
+		return nil
+	}
+	return h.data.items[h.data.keys[0]].obj
+}
+
 // GetByKey returns the requested item, or sets exists=false.
 func (h *Heap[T]) GetByKey(key string) *T {
 	item, exists := h.data.items[key]
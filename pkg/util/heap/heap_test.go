@@ -63,6 +63,21 @@ func TestHeapBasic(t *testing.T) {
 	}
 }
 
+func TestHeap_Peek(t *testing.T) {
+	h := New(testHeapObjectKeyFunc, compareInts)
+	if h.Peek() != nil {
+		t.Fatal("expected no head in an empty heap")
+	}
+	h.PushOrUpdate(mkHeapObj("foo", 10))
+	h.PushOrUpdate(mkHeapObj("bar", 1))
+	if head := h.Peek(); head.val != 1 {
+		t.Fatalf("expected %d, got %d", 1, head.val)
+	}
+	if h.Len() != 2 {
+		t.Fatalf("expected Peek to keep the items, got %d items", h.Len())
+	}
+}
+
 // Tests Heap.PushOrUpdate and ensures that heap invariant is preserved after adding items.
 func TestHeap_Add(t *testing.T) {
 	h := New(testHeapObjectKeyFunc, compareInts)
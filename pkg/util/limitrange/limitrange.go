@@ -18,6 +18,7 @@ package limitrange
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -53,31 +54,42 @@ func addToSummary(summary, item corev1.LimitRangeItem) corev1.LimitRangeItem {
 	return summary
 }
 
-func violateMaxMessage(path *field.Path, keys ...string) string {
-	return fmt.Sprintf("the requests of %s[%s] exceeds the limits", path.String(), strings.Join(keys, ", "))
-}
 func violateMinMessage(path *field.Path, keys ...string) string {
 	return fmt.Sprintf("the requests of %s[%s] are less than the limits", path.String(), strings.Join(keys, ", "))
 }
 
-func (s Summary) validatePodSpecContainers(containers []corev1.Container, path *field.Path) []string {
+// exceedsMaxMessages returns a message for each of the values over their
+// maximum, including the value and the maximum.
+func exceedsMaxMessages(path *field.Path, values, maxValues corev1.ResourceList) []string {
+	keys := resource.GetGreaterKeys(values, maxValues)
+	slices.Sort(keys)
+	reasons := make([]string, 0, len(keys))
+	for _, k := range keys {
+		name := corev1.ResourceName(k)
+		value, maxValue := values[name], maxValues[name]
+		reasons = append(reasons, fmt.Sprintf("%s of %s is %s, more than the LimitRange maximum of %s", k, path.String(), value.String(), maxValue.String()))
+	}
+	return reasons
+}
+
+func (s Summary) validatePodSpecContainers(containers []corev1.Container, path *field.Path) (reasons []string, exceedsMax bool) {
 	containerRange, found := s[corev1.LimitTypeContainer]
 	if !found {
-		return nil
+		return nil, false
 	}
-	reasons := []string{}
 	for i := range containers {
 		res := &containers[i].Resources
 		cMin := resource.MergeResourceListKeepMin(res.Requests, res.Limits)
 		cMax := resource.MergeResourceListKeepMax(res.Requests, res.Limits)
-		if list := resource.GetGreaterKeys(cMax, containerRange.Max); len(list) > 0 {
-			reasons = append(reasons, violateMaxMessage(path.Index(i), list...))
+		if maxReasons := exceedsMaxMessages(path.Index(i), cMax, containerRange.Max); len(maxReasons) > 0 {
+			reasons = append(reasons, maxReasons...)
+			exceedsMax = true
 		}
 		if list := resource.GetGreaterKeys(containerRange.Min, cMin); len(list) > 0 {
 			reasons = append(reasons, violateMinMessage(path.Index(i), list...))
 		}
 	}
-	return reasons
+	return reasons, exceedsMax
 }
 
 // TotalRequests computes the total resource requests of a pod.
@@ -138,18 +150,24 @@ func isSidecarContainer(container corev1.Container) bool {
 }
 
 // ValidatePodSpec verifies if the provided podSpec (ps) first into the boundaries of the summary (s).
-func (s Summary) ValidatePodSpec(ps *corev1.PodSpec, path *field.Path) []string {
+// It also returns whether any of the pod or its containers exceeds the maximum
+// of the summary, as the API server would reject such pods.
+func (s Summary) ValidatePodSpec(ps *corev1.PodSpec, path *field.Path) ([]string, bool) {
 	reasons := []string{}
-	reasons = append(reasons, s.validatePodSpecContainers(ps.InitContainers, path.Child("initContainers"))...)
-	reasons = append(reasons, s.validatePodSpecContainers(ps.Containers, path.Child("containers"))...)
+	initReasons, initExceedsMax := s.validatePodSpecContainers(ps.InitContainers, path.Child("initContainers"))
+	reasons = append(reasons, initReasons...)
+	containersReasons, containersExceedMax := s.validatePodSpecContainers(ps.Containers, path.Child("containers"))
+	reasons = append(reasons, containersReasons...)
+	exceedsMax := initExceedsMax || containersExceedMax
 	if containerRange, found := s[corev1.LimitTypePod]; found {
 		total := TotalRequests(ps)
-		if list := resource.GetGreaterKeys(total, containerRange.Max); len(list) > 0 {
-			reasons = append(reasons, violateMaxMessage(path, list...))
+		if maxReasons := exceedsMaxMessages(path, total, containerRange.Max); len(maxReasons) > 0 {
+			reasons = append(reasons, maxReasons...)
+			exceedsMax = true
 		}
 		if list := resource.GetGreaterKeys(containerRange.Min, total); len(list) > 0 {
 			reasons = append(reasons, violateMinMessage(path, list...))
 		}
 	}
-	return reasons
+	return reasons, exceedsMax
 }
@@ -392,8 +392,9 @@ func TestValidatePodSpec(t *testing.T) {
 		},
 	}
 	cases := map[string]struct {
-		summary Summary
-		want    []string
+		summary        Summary
+		want           []string
+		wantExceedsMax bool
 	}{
 		"empty": {
 			summary: Summary{},
@@ -405,8 +406,9 @@ func TestValidatePodSpec(t *testing.T) {
 				WithValue("Max", "example.com/initContainerGpu", "1").
 				Obj()),
 			want: []string{
-				violateMaxMessage(field.NewPath("testPodSet", "initContainers").Index(1), "example.com/initContainerGpu"),
+				"example.com/initContainerGpu of testPodSet.initContainers[1] is 2, more than the LimitRange maximum of 1",
 			},
+			wantExceedsMax: true,
 		},
 		"init container under": {
 			summary: Summarize(*testingutil.MakeLimitRange("", "").
@@ -423,8 +425,9 @@ func TestValidatePodSpec(t *testing.T) {
 				WithValue("Max", "example.com/mainContainerGpu", "1").
 				Obj()),
 			want: []string{
-				violateMaxMessage(field.NewPath("testPodSet", "containers").Index(0), "example.com/mainContainerGpu"),
+				"example.com/mainContainerGpu of testPodSet.containers[0] is 2, more than the LimitRange maximum of 1",
 			},
+			wantExceedsMax: true,
 		},
 		"container under": {
 			summary: Summarize(*testingutil.MakeLimitRange("", "").
@@ -441,8 +444,9 @@ func TestValidatePodSpec(t *testing.T) {
 				WithValue("Max", corev1.ResourceCPU, "4").
 				Obj()),
 			want: []string{
-				violateMaxMessage(field.NewPath("testPodSet"), string(corev1.ResourceCPU)),
+				"cpu of testPodSet is 5, more than the LimitRange maximum of 4",
 			},
+			wantExceedsMax: true,
 		},
 		"pod under": {
 			summary: Summarize(*testingutil.MakeLimitRange("", "").
@@ -469,10 +473,11 @@ func TestValidatePodSpec(t *testing.T) {
 					Obj(),
 			),
 			want: []string{
-				violateMaxMessage(field.NewPath("testPodSet", "initContainers").Index(1), "example.com/initContainerGpu"),
+				"example.com/initContainerGpu of testPodSet.initContainers[1] is 2, more than the LimitRange maximum of 1",
 				violateMinMessage(field.NewPath("testPodSet", "containers").Index(0), "example.com/mainContainerGpu"),
-				violateMaxMessage(field.NewPath("testPodSet"), string(corev1.ResourceCPU)),
+				"cpu of testPodSet is 5, more than the LimitRange maximum of 4",
 			},
+			wantExceedsMax: true,
 		},
 		"multiple valid": {
 			summary: Summarize(
@@ -494,10 +499,13 @@ func TestValidatePodSpec(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			result := tc.summary.ValidatePodSpec(podSpec, field.NewPath("testPodSet"))
+			result, exceedsMax := tc.summary.ValidatePodSpec(podSpec, field.NewPath("testPodSet"))
 			if diff := cmp.Diff(tc.want, result); diff != "" {
 				t.Errorf("Unexpected result (-want,+got):\n%s", diff)
 			}
+			if exceedsMax != tc.wantExceedsMax {
+				t.Errorf("Unexpected exceedsMax, want %t, got %t", tc.wantExceedsMax, exceedsMax)
+			}
 		})
 	}
 }
@@ -0,0 +1,32 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localqueue
+
+import kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+
+// ClusterQueueNames returns the names of the clusterQueues that can serve
+// the workloads of the localQueue, in order of preference, starting with
+// spec.clusterQueue.
+func ClusterQueueNames(q *kueue.LocalQueue) []string {
+	names := []string{string(q.Spec.ClusterQueue)}
+	if q.Spec.ClusterQueueSelection != nil {
+		for _, cq := range q.Spec.ClusterQueueSelection.ClusterQueues {
+			names = append(names, string(cq))
+		}
+	}
+	return names
+}
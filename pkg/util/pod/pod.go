@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MaxControllerDepth is the number of controllers walked up from a pod to
+// find the job that owns it, e.g. pod, Job and JobSet, or pod, RayCluster
+// and RayJob.
+const MaxControllerDepth = 3
+
+// Unschedulable returns whether the scheduler couldn't find a Node for the
+// pod.
+func Unschedulable(p *corev1.Pod) bool {
+	for _, cond := range p.Status.Conditions {
+		if cond.Type == corev1.PodScheduled {
+			return cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+	return false
+}
+
+// ControllerUIDs returns the UIDs of the chain of controllers of the object,
+// starting with its direct controller, up to maxDepth controllers.
+// The controllers above the direct one are read as metadata through the
+// reader, so that the reader doesn't need to cache their types. The chain
+// ends at the first controller that is not found.
+func ControllerUIDs(ctx context.Context, r client.Reader, obj client.Object, maxDepth int) ([]types.UID, error) {
+	var uids []types.UID
+	ref := metav1.GetControllerOfNoCopy(obj)
+	for ref != nil {
+		uids = append(uids, ref.UID)
+		if len(uids) >= maxDepth {
+			break
+		}
+		owner := &metav1.PartialObjectMetadata{}
+		owner.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+		if err := r.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name}, owner); err != nil {
+			if apierrors.IsNotFound(err) {
+				break
+			}
+			return nil, err
+		}
+		if owner.UID != ref.UID {
+			// The controller was recreated with the same name.
+			break
+		}
+		ref = metav1.GetControllerOfNoCopy(owner)
+	}
+	return uids, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestUnschedulable(t *testing.T) {
+	cases := map[string]struct {
+		conditions []corev1.PodCondition
+		want       bool
+	}{
+		"no conditions": {},
+		"scheduled": {
+			conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}},
+		},
+		"unschedulable": {
+			conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable}},
+			want:       true,
+		},
+		"scheduling gated": {
+			conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonSchedulingGated}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &corev1.Pod{Status: corev1.PodStatus{Conditions: tc.conditions}}
+			if got := Unschedulable(p); got != tc.want {
+				t.Errorf("Unschedulable() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestControllerUIDs(t *testing.T) {
+	controllerRef := func(apiVersion, kind, name string, uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: uid, Controller: ptr.To(true)}}
+	}
+	cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "cronjob", Namespace: "ns", UID: "cronjob-uid"}}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name: "job", Namespace: "ns", UID: "job-uid",
+		OwnerReferences: controllerRef("batch/v1", "CronJob", "cronjob", "cronjob-uid"),
+	}}
+	recreatedJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "recreated", Namespace: "ns", UID: "new-uid"}}
+	pod := func(ownerReferences []metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", UID: "pod-uid", OwnerReferences: ownerReferences}}
+	}
+	cases := map[string]struct {
+		pod      *corev1.Pod
+		maxDepth int
+		want     []types.UID
+	}{
+		"no controller": {
+			pod:      pod(nil),
+			maxDepth: MaxControllerDepth,
+		},
+		"chain of controllers": {
+			pod:      pod(controllerRef("batch/v1", "Job", "job", "job-uid")),
+			maxDepth: MaxControllerDepth,
+			want:     []types.UID{"job-uid", "cronjob-uid"},
+		},
+		"chain limited by the depth": {
+			pod:      pod(controllerRef("batch/v1", "Job", "job", "job-uid")),
+			maxDepth: 1,
+			want:     []types.UID{"job-uid"},
+		},
+		"missing controller": {
+			pod:      pod(controllerRef("batch/v1", "Job", "missing", "missing-uid")),
+			maxDepth: MaxControllerDepth,
+			want:     []types.UID{"missing-uid"},
+		},
+		"recreated controller": {
+			pod:      pod(controllerRef("batch/v1", "Job", "recreated", "old-uid")),
+			maxDepth: MaxControllerDepth,
+			want:     []types.UID{"old-uid"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cl := utiltesting.NewFakeClient([]client.Object{cronJob, job, recreatedJob}...)
+			got, err := ControllerUIDs(context.Background(), cl, tc.pod, tc.maxDepth)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected controller UIDs (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,8 +38,8 @@ func Priority(w *kueue.Workload) int32 {
 }
 
 // GetPriorityFromPriorityClass returns the priority populated from
-// priority class. If not specified, priority will be default or
-// zero if there is no default.
+// priority class. If not specified or the priority class doesn't exist,
+// priority will be default or zero if there is no default.
 func GetPriorityFromPriorityClass(ctx context.Context, client client.Client,
 	priorityClass string) (string, string, int32, error) {
 	if len(priorityClass) == 0 {
@@ -47,6 +48,9 @@ func GetPriorityFromPriorityClass(ctx context.Context, client client.Client,
 
 	pc := &schedulingv1.PriorityClass{}
 	if err := client.Get(ctx, types.NamespacedName{Name: priorityClass}, pc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return getDefaultPriority(ctx, client)
+		}
 		return "", "", 0, err
 	}
 
@@ -91,8 +91,23 @@ func TestGetPriorityFromPriorityClass(t *testing.T) {
 			priorityClassList: &schedulingv1.PriorityClassList{
 				Items: []schedulingv1.PriorityClass{},
 			},
-			priorityClassName: "test",
-			wantErr:           apierrors.NewNotFound(schedulingv1.Resource("priorityclasses"), "test"),
+			priorityClassName:      "test",
+			wantPriorityClassValue: constants.DefaultPriority,
+		},
+		"priorityClass is specified and it does not exist, but a global default exists": {
+			priorityClassList: &schedulingv1.PriorityClassList{
+				Items: []schedulingv1.PriorityClass{
+					{
+						ObjectMeta:    v1.ObjectMeta{Name: "globalDefault"},
+						GlobalDefault: true,
+						Value:         40,
+					},
+				},
+			},
+			priorityClassName:       "test",
+			wantPriorityClassName:   "globalDefault",
+			wantPriorityClassSource: constants.PodPriorityClassSource,
+			wantPriorityClassValue:  40,
 		},
 		"priorityClass is unspecified and one global default exists": {
 			priorityClassList: &schedulingv1.PriorityClassList{
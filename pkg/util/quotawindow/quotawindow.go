@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotawindow
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/util/cron"
+)
+
+// activeStart returns the earliest start of the window that is still active
+// at the given time, if any.
+func activeStart(w *kueue.QuotaWindow, now time.Time) (time.Time, bool) {
+	schedule, err := cron.Parse(w.Schedule)
+	if err != nil || w.Duration.Duration <= 0 {
+		return time.Time{}, false
+	}
+	// A start is still active if it's within the duration of the window
+	// before now.
+	start := schedule.Next(now.Add(-w.Duration.Duration))
+	if start.IsZero() || start.After(now) {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// Active returns the first of the windows that is active at the given time,
+// or nil if none is.
+func Active(windows []kueue.QuotaWindow, now time.Time) *kueue.QuotaWindow {
+	for i := range windows {
+		if _, active := activeStart(&windows[i], now); active {
+			return &windows[i]
+		}
+	}
+	return nil
+}
+
+// UntilNextBoundary returns the time from now until any of the windows starts
+// or ends, or 0 if there are no windows.
+func UntilNextBoundary(windows []kueue.QuotaWindow, now time.Time) time.Duration {
+	var next time.Duration
+	for i := range windows {
+		w := &windows[i]
+		var boundaries []time.Time
+		if schedule, err := cron.Parse(w.Schedule); err == nil {
+			boundaries = append(boundaries, schedule.Next(now))
+		}
+		if start, active := activeStart(w, now); active {
+			boundaries = append(boundaries, start.Add(w.Duration.Duration))
+		}
+		for _, b := range boundaries {
+			if b.IsZero() {
+				continue
+			}
+			if d := b.Sub(now); next == 0 || d < next {
+				next = d
+			}
+		}
+	}
+	return next
+}
+
+// ApplyQuotas returns the resource groups with the quotas of the flavors and
+// resources replaced by those of the window. The input is not modified.
+func ApplyQuotas(resourceGroups []kueue.ResourceGroup, w *kueue.QuotaWindow) []kueue.ResourceGroup {
+	if w == nil {
+		return resourceGroups
+	}
+	windowQuotas := make(map[kueue.ResourceFlavorReference]map[corev1.ResourceName]*kueue.ResourceQuota, len(w.Flavors))
+	for fi := range w.Flavors {
+		fq := &w.Flavors[fi]
+		quotas := make(map[corev1.ResourceName]*kueue.ResourceQuota, len(fq.Resources))
+		for ri := range fq.Resources {
+			quotas[fq.Resources[ri].Name] = &fq.Resources[ri]
+		}
+		windowQuotas[fq.Name] = quotas
+	}
+	out := make([]kueue.ResourceGroup, len(resourceGroups))
+	for rgi := range resourceGroups {
+		rg := resourceGroups[rgi].DeepCopy()
+		for fi := range rg.Flavors {
+			fq := &rg.Flavors[fi]
+			quotas, found := windowQuotas[fq.Name]
+			if !found {
+				continue
+			}
+			for ri := range fq.Resources {
+				if q, found := quotas[fq.Resources[ri].Name]; found {
+					fq.Resources[ri] = *q.DeepCopy()
+				}
+			}
+		}
+		out[rgi] = *rg
+	}
+	return out
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotawindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2024, time.March, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func TestActive(t *testing.T) {
+	windows := testingutil.MakeClusterQueue("cq").
+		QuotaWindow("night", "0 22 * * *", 8*time.Hour).
+		QuotaWindow("lunch", "0 12 * * *", time.Hour).
+		QuotaWindow("afternoon", "30 12 * * *", 5*time.Hour+30*time.Minute).
+		QuotaWindow("weekend", "0 0 * * 6", 48*time.Hour).
+		Obj().Spec.QuotaWindows
+	cases := map[string]struct {
+		now  time.Time
+		want string
+	}{
+		"before midnight": {
+			now:  at(23, 0),
+			want: "night",
+		},
+		"after midnight": {
+			now:  at(5, 59),
+			want: "night",
+		},
+		"at the end of a window": {
+			now: at(6, 0),
+		},
+		"at the start of a window": {
+			now:  at(12, 0),
+			want: "lunch",
+		},
+		"the first of the overlapping windows": {
+			now:  at(12, 45),
+			want: "lunch",
+		},
+		"the second of the overlapping windows": {
+			now:  at(13, 0),
+			want: "afternoon",
+		},
+		"in another time zone": {
+			now:  time.Date(2024, time.March, 1, 0, 30, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+			want: "night",
+		},
+		"on the second day of a weekly window": {
+			now:  time.Date(2024, time.March, 3, 10, 0, 0, 0, time.UTC),
+			want: "weekend",
+		},
+		"after a weekly window": {
+			now: time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			if w := Active(windows, tc.now); w != nil {
+				got = w.Name
+			}
+			if got != tc.want {
+				t.Errorf("Unexpected active window, want=%q, got=%q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUntilNextBoundary(t *testing.T) {
+	windows := testingutil.MakeClusterQueue("cq").
+		QuotaWindow("night", "0 22 * * *", 8*time.Hour).
+		QuotaWindow("lunch", "0 12 * * *", time.Hour).
+		Obj().Spec.QuotaWindows
+	weekend := testingutil.MakeClusterQueue("cq").
+		QuotaWindow("weekend", "0 0 * * 6", 48*time.Hour).
+		Obj().Spec.QuotaWindows
+	cases := map[string]struct {
+		windows []kueue.QuotaWindow
+		now     time.Time
+		want    time.Duration
+	}{
+		"no windows": {
+			now: at(12, 0),
+		},
+		"before a start": {
+			windows: windows,
+			now:     at(11, 30),
+			want:    30 * time.Minute,
+		},
+		"at a start": {
+			windows: windows,
+			now:     at(12, 0),
+			want:    time.Hour,
+		},
+		"across midnight": {
+			windows: windows,
+			now:     at(23, 0),
+			want:    7 * time.Hour,
+		},
+		"before a weekly window": {
+			windows: weekend,
+			now:     at(12, 0),
+			want:    12 * time.Hour,
+		},
+		"in a weekly window": {
+			windows: weekend,
+			now:     time.Date(2024, time.March, 3, 12, 0, 0, 0, time.UTC),
+			want:    12 * time.Hour,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := UntilNextBoundary(tc.windows, tc.now); got != tc.want {
+				t.Errorf("Unexpected time until the next boundary, want=%v, got=%v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyQuotas(t *testing.T) {
+	cq := testingutil.MakeClusterQueue("cq").
+		ResourceGroup(
+			*testingutil.MakeFlavorQuotas("on-demand").
+				Resource(corev1.ResourceCPU, "10").
+				Resource(corev1.ResourceMemory, "10Gi").
+				Obj(),
+			*testingutil.MakeFlavorQuotas("spot").
+				Resource(corev1.ResourceCPU, "5").
+				Resource(corev1.ResourceMemory, "5Gi").
+				Obj(),
+		).
+		QuotaWindow("night", "0 22 * * *", 8*time.Hour,
+			*testingutil.MakeFlavorQuotas("on-demand").
+				Resource(corev1.ResourceCPU, "20", "5").
+				Obj(),
+		).
+		Obj()
+	want := testingutil.MakeClusterQueue("cq").
+		ResourceGroup(
+			*testingutil.MakeFlavorQuotas("on-demand").
+				Resource(corev1.ResourceCPU, "20", "5").
+				Resource(corev1.ResourceMemory, "10Gi").
+				Obj(),
+			*testingutil.MakeFlavorQuotas("spot").
+				Resource(corev1.ResourceCPU, "5").
+				Resource(corev1.ResourceMemory, "5Gi").
+				Obj(),
+		).
+		Obj().Spec.ResourceGroups
+	original := cq.DeepCopy()
+
+	got := ApplyQuotas(cq.Spec.ResourceGroups, &cq.Spec.QuotaWindows[0])
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected resource groups (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(original, cq); diff != "" {
+		t.Errorf("The ClusterQueue was modified (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(cq.Spec.ResourceGroups, ApplyQuotas(cq.Spec.ResourceGroups, nil)); diff != "" {
+		t.Errorf("Unexpected resource groups without a window (-want,+got):\n%s", diff)
+	}
+}
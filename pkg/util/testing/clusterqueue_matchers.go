@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// BeBorrowing succeeds if the ClusterQueue reports in its status that it's
+// borrowing the given quantity of the flavor and resource from its cohort.
+func BeBorrowing(flavor kueue.ResourceFlavorReference, resourceName corev1.ResourceName, quantity string) types.GomegaMatcher {
+	return gomega.WithTransform(func(cq *kueue.ClusterQueue) resource.Quantity {
+		return BorrowedQuantity(cq, flavor, resourceName)
+	}, gomega.BeComparableTo(resource.MustParse(quantity)))
+}
+
+// BorrowedQuantity returns the quantity of the flavor and resource that the
+// ClusterQueue reports as borrowed from its cohort in its status.
+func BorrowedQuantity(cq *kueue.ClusterQueue, flavor kueue.ResourceFlavorReference, resourceName corev1.ResourceName) resource.Quantity {
+	for _, fr := range cq.Status.FlavorsReservation {
+		if fr.Name != flavor {
+			continue
+		}
+		for _, r := range fr.Resources {
+			if r.Name == resourceName {
+				return r.Borrowed
+			}
+		}
+	}
+	return resource.Quantity{}
+}
@@ -201,6 +201,12 @@ func (w *WorkloadWrapper) Finished() *WorkloadWrapper {
 	return w
 }
 
+// ActiveDeadlineSeconds sets the activeDeadlineSeconds of the workload.
+func (w *WorkloadWrapper) ActiveDeadlineSeconds(s int64) *WorkloadWrapper {
+	w.Spec.ActiveDeadlineSeconds = &s
+	return w
+}
+
 func (w *WorkloadWrapper) Creation(t time.Time) *WorkloadWrapper {
 	w.CreationTimestamp = metav1.NewTime(t)
 	return w
@@ -290,6 +296,12 @@ func (w *WorkloadWrapper) Admission(admission *kueue.Admission) *WorkloadWrapper
 	return w
 }
 
+// PreviousFlavors sets the flavors assigned to the podSets in the previous admission.
+func (w *WorkloadWrapper) PreviousFlavors(flavors ...kueue.PodSetFlavors) *WorkloadWrapper {
+	w.Status.PreviousFlavors = flavors
+	return w
+}
+
 func (w *WorkloadWrapper) Conditions(conditions ...metav1.Condition) *WorkloadWrapper {
 	w.Status.Conditions = conditions
 	return w
@@ -328,6 +340,13 @@ func (w *WorkloadWrapper) DeletionTimestamp(t time.Time) *WorkloadWrapper {
 	return w
 }
 
+// AdmissionAttempts sets the number of times that the workload got a quota
+// reservation.
+func (w *WorkloadWrapper) AdmissionAttempts(n int32) *WorkloadWrapper {
+	w.Status.AdmissionAttempts = n
+	return w
+}
+
 func (w *WorkloadWrapper) RequeueState(count *int32, requeueAt *metav1.Time) *WorkloadWrapper {
 	if count == nil && requeueAt == nil {
 		w.Status.RequeueState = nil
@@ -384,6 +403,10 @@ func (p *PodSetWrapper) RuntimeClass(name string) *PodSetWrapper {
 	return p
 }
 
+func (p *PodSetWrapper) Clone() *PodSetWrapper {
+	return &PodSetWrapper{PodSet: *p.DeepCopy()}
+}
+
 func (p *PodSetWrapper) Obj() *kueue.PodSet {
 	return &p.PodSet
 }
@@ -431,6 +454,17 @@ func (p *PodSetWrapper) NodeSelector(kv map[string]string) *PodSetWrapper {
 	return p
 }
 
+func (p *PodSetWrapper) RequiredNodeSelectorTerms(terms ...corev1.NodeSelectorTerm) *PodSetWrapper {
+	p.Template.Spec.Affinity = &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: terms,
+			},
+		},
+	}
+	return p
+}
+
 func (p *PodSetWrapper) NodeName(name string) *PodSetWrapper {
 	p.Template.Spec.NodeName = name
 	return p
@@ -451,6 +485,26 @@ func (p *PodSetWrapper) SchedulingGates(sg ...corev1.PodSchedulingGate) *PodSetW
 	return p
 }
 
+// ResourceClaim adds a pod resource claim, from a template with the same
+// name, and declares its device class.
+func (p *PodSetWrapper) ResourceClaim(name, deviceClass string) *PodSetWrapper {
+	p.Template.Spec.ResourceClaims = append(p.Template.Spec.ResourceClaims, corev1.PodResourceClaim{
+		Name: name,
+		Source: corev1.ClaimSource{
+			ResourceClaimTemplateName: ptr.To(name),
+		},
+	})
+	if p.Template.Annotations == nil {
+		p.Template.Annotations = make(map[string]string)
+	}
+	classes := fmt.Sprintf("%s=%s", name, deviceClass)
+	if v, found := p.Template.Annotations[kueue.PodSetResourceClaimClassesAnnotation]; found {
+		classes = v + "," + classes
+	}
+	p.Template.Annotations[kueue.PodSetResourceClaimClassesAnnotation] = classes
+	return p
+}
+
 func (p *PodSetWrapper) PodOverHead(resources corev1.ResourceList) *PodSetWrapper {
 	p.Template.Spec.Overhead = resources
 	return p
@@ -554,6 +608,22 @@ func (q *LocalQueueWrapper) StopPolicy(p kueue.StopPolicy) *LocalQueueWrapper {
 	return q
 }
 
+// MaxConcurrency sets the maximum number of workloads reserving quota.
+func (q *LocalQueueWrapper) MaxConcurrency(n int32) *LocalQueueWrapper {
+	q.Spec.MaxConcurrency = &n
+	return q
+}
+
+// ClusterQueueSelection sets additional clusterQueues for the queue and the
+// policy to select among them.
+func (q *LocalQueueWrapper) ClusterQueueSelection(policy kueue.ClusterQueueSelectionPolicy, cqs ...string) *LocalQueueWrapper {
+	q.Spec.ClusterQueueSelection = &kueue.ClusterQueueSelection{Policy: policy}
+	for _, cq := range cqs {
+		q.Spec.ClusterQueueSelection.ClusterQueues = append(q.Spec.ClusterQueueSelection.ClusterQueues, kueue.ClusterQueueReference(cq))
+	}
+	return q
+}
+
 // PendingWorkloads updates the pendingWorkloads in status.
 func (q *LocalQueueWrapper) PendingWorkloads(n int32) *LocalQueueWrapper {
 	q.Status.PendingWorkloads = n
@@ -616,6 +686,64 @@ func (c *ClusterQueueWrapper) Cohort(cohort string) *ClusterQueueWrapper {
 	return c
 }
 
+// CohortWrapper wraps a Cohort.
+type CohortWrapper struct{ kueuealpha.Cohort }
+
+// MakeCohort creates a wrapper for a Cohort.
+func MakeCohort(name string) *CohortWrapper {
+	return &CohortWrapper{kueuealpha.Cohort{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}}
+}
+
+// Obj returns the inner Cohort.
+func (c *CohortWrapper) Obj() *kueuealpha.Cohort {
+	return &c.Cohort
+}
+
+// BorrowingCap caps the quota of the flavor and resource that the
+// ClusterQueues in the cohort can borrow.
+func (c *CohortWrapper) BorrowingCap(flavor kueue.ResourceFlavorReference, resourceName corev1.ResourceName, capacity string) *CohortWrapper {
+	resourceCap := kueuealpha.ResourceBorrowingCap{Name: resourceName, Cap: resource.MustParse(capacity)}
+	for i := range c.Spec.BorrowingCaps {
+		if c.Spec.BorrowingCaps[i].Name == flavor {
+			c.Spec.BorrowingCaps[i].Resources = append(c.Spec.BorrowingCaps[i].Resources, resourceCap)
+			return c
+		}
+	}
+	c.Spec.BorrowingCaps = append(c.Spec.BorrowingCaps, kueuealpha.FlavorBorrowingCaps{
+		Name:      flavor,
+		Resources: []kueuealpha.ResourceBorrowingCap{resourceCap},
+	})
+	return c
+}
+
+// ReclaimDelay sets the reclaimDelay of the Cohort.
+func (c *CohortWrapper) ReclaimDelay(d time.Duration) *CohortWrapper {
+	c.Spec.ReclaimDelay = &metav1.Duration{Duration: d}
+	return c
+}
+
+// BorrowingSafetyMarginPercent sets the borrowingSafetyMarginPercent of the Cohort.
+func (c *CohortWrapper) BorrowingSafetyMarginPercent(p int32) *CohortWrapper {
+	c.Spec.BorrowingSafetyMarginPercent = &p
+	return c
+}
+
+// Parent sets the parent of the Cohort.
+func (c *CohortWrapper) Parent(parent string) *CohortWrapper {
+	c.Spec.Parent = parent
+	return c
+}
+
+// ClusterQueue creates a wrapper for a ClusterQueue that is a member of the
+// cohort.
+func (c *CohortWrapper) ClusterQueue(name string) *ClusterQueueWrapper {
+	return MakeClusterQueue(name).Cohort(c.Name)
+}
+
 func (c *ClusterQueueWrapper) AdmissionCheckStrategy(acs ...kueue.AdmissionCheckStrategyRule) *ClusterQueueWrapper {
 	if c.Spec.AdmissionChecksStrategy == nil {
 		c.Spec.AdmissionChecksStrategy = &kueue.AdmissionChecksStrategy{}
@@ -674,6 +802,84 @@ func (c *ClusterQueueWrapper) Preemption(p kueue.ClusterQueuePreemption) *Cluste
 	return c
 }
 
+// FlavorSelectionStrategy sets the flavorSelectionStrategy.
+func (c *ClusterQueueWrapper) FlavorSelectionStrategy(s kueue.FlavorSelectionStrategy) *ClusterQueueWrapper {
+	c.Spec.FlavorSelectionStrategy = s
+	return c
+}
+
+// ReadmissionFlavorPolicy sets the readmissionFlavorPolicy.
+func (c *ClusterQueueWrapper) ReadmissionFlavorPolicy(p kueue.ReadmissionFlavorPolicy) *ClusterQueueWrapper {
+	c.Spec.ReadmissionFlavorPolicy = p
+	return c
+}
+
+// WorkloadSizeOrdering sets the workloadSizeOrdering.
+func (c *ClusterQueueWrapper) WorkloadSizeOrdering(o kueue.WorkloadSizeOrdering) *ClusterQueueWrapper {
+	c.Spec.WorkloadSizeOrdering = o
+	return c
+}
+
+// ResourceAlias adds a resource alias to the ClusterQueue.
+func (c *ClusterQueueWrapper) ResourceAlias(name corev1.ResourceName, aliases ...corev1.ResourceName) *ClusterQueueWrapper {
+	c.Spec.ResourceAliases = append(c.Spec.ResourceAliases, kueue.ResourceAlias{
+		Name:    name,
+		Aliases: aliases,
+	})
+	return c
+}
+
+// ResourceSlice adds a resource slice to the ClusterQueue.
+func (c *ClusterQueueWrapper) ResourceSlice(name, resource corev1.ResourceName, slicesPerUnit int32) *ClusterQueueWrapper {
+	c.Spec.ResourceSlices = append(c.Spec.ResourceSlices, kueue.ResourceSlice{
+		Name:          name,
+		Resource:      resource,
+		SlicesPerUnit: slicesPerUnit,
+	})
+	return c
+}
+
+// ResourceTransform adds a resource transform to the ClusterQueue. Empty
+// multiplier or minimum are left unset.
+func (c *ClusterQueueWrapper) ResourceTransform(name corev1.ResourceName, multiplier, minimum string) *ClusterQueueWrapper {
+	t := kueue.ResourceTransform{Name: name}
+	if multiplier != "" {
+		t.Multiplier = ptr.To(resource.MustParse(multiplier))
+	}
+	if minimum != "" {
+		t.Minimum = ptr.To(resource.MustParse(minimum))
+	}
+	c.Spec.ResourceTransforms = append(c.Spec.ResourceTransforms, t)
+	return c
+}
+
+// QuotaWindow adds a quota window to the ClusterQueue.
+func (c *ClusterQueueWrapper) QuotaWindow(name, schedule string, duration time.Duration, flavors ...kueue.FlavorQuotas) *ClusterQueueWrapper {
+	c.Spec.QuotaWindows = append(c.Spec.QuotaWindows, kueue.QuotaWindow{
+		Name:     name,
+		Schedule: schedule,
+		Duration: metav1.Duration{Duration: duration},
+		Flavors:  flavors,
+	})
+	return c
+}
+
+// ResourceTransformPodDefault adds a resource transform to the ClusterQueue
+// that only sets the request of the pods that don't request the resource.
+func (c *ClusterQueueWrapper) ResourceTransformPodDefault(name corev1.ResourceName, podDefault string) *ClusterQueueWrapper {
+	c.Spec.ResourceTransforms = append(c.Spec.ResourceTransforms, kueue.ResourceTransform{
+		Name:       name,
+		PodDefault: ptr.To(resource.MustParse(podDefault)),
+	})
+	return c
+}
+
+// IntegerResources sets the resources that can only be requested in whole units.
+func (c *ClusterQueueWrapper) IntegerResources(names ...corev1.ResourceName) *ClusterQueueWrapper {
+	c.Spec.IntegerResources = names
+	return c
+}
+
 // FlavorFungibility sets the flavorFungibility policies.
 func (c *ClusterQueueWrapper) FlavorFungibility(p kueue.FlavorFungibility) *ClusterQueueWrapper {
 	c.Spec.FlavorFungibility = &p
@@ -779,6 +985,23 @@ func (f *FlavorQuotasWrapper) Resource(name corev1.ResourceName, qs ...string) *
 	return f
 }
 
+// NonLendableQuota sets the nonLendableQuota of a resource previously added
+// with Resource.
+func (f *FlavorQuotasWrapper) NonLendableQuota(name corev1.ResourceName, q string) *FlavorQuotasWrapper {
+	for i := range f.Resources {
+		if f.Resources[i].Name == name {
+			f.Resources[i].NonLendableQuota = ptr.To(resource.MustParse(q))
+		}
+	}
+	return f
+}
+
+// WarnThreshold sets the warnThreshold percentage of the flavor.
+func (f *FlavorQuotasWrapper) WarnThreshold(percent int32) *FlavorQuotasWrapper {
+	f.FlavorQuotas.WarnThreshold = &percent
+	return f
+}
+
 // ResourceFlavorWrapper wraps a ResourceFlavor.
 type ResourceFlavorWrapper struct{ kueue.ResourceFlavor }
 
@@ -805,6 +1028,12 @@ func (rf *ResourceFlavorWrapper) Label(k, v string) *ResourceFlavorWrapper {
 	return rf
 }
 
+// NodeSelectorTerm sets the node selector term of the ResourceFlavor.
+func (rf *ResourceFlavorWrapper) NodeSelectorTerm(exprs ...corev1.NodeSelectorRequirement) *ResourceFlavorWrapper {
+	rf.Spec.NodeSelector = &corev1.NodeSelectorTerm{MatchExpressions: exprs}
+	return rf
+}
+
 // Taint adds a taint to the ResourceFlavor.
 func (rf *ResourceFlavorWrapper) Taint(t corev1.Taint) *ResourceFlavorWrapper {
 	rf.Spec.NodeTaints = append(rf.Spec.NodeTaints, t)
@@ -817,6 +1046,18 @@ func (rf *ResourceFlavorWrapper) Toleration(t corev1.Toleration) *ResourceFlavor
 	return rf
 }
 
+// EvictOnNodeUnavailable sets the evictOnNodeUnavailable of the ResourceFlavor.
+func (rf *ResourceFlavorWrapper) EvictOnNodeUnavailable(evict bool) *ResourceFlavorWrapper {
+	rf.Spec.EvictOnNodeUnavailable = evict
+	return rf
+}
+
+// Cordoned sets whether the ResourceFlavor is cordoned.
+func (rf *ResourceFlavorWrapper) Cordoned(cordoned bool) *ResourceFlavorWrapper {
+	rf.Spec.Cordoned = cordoned
+	return rf
+}
+
 // RuntimeClassWrapper wraps a RuntimeClass.
 type RuntimeClassWrapper struct{ nodev1.RuntimeClass }
 
@@ -845,49 +1086,79 @@ func (rc *RuntimeClassWrapper) Obj() *nodev1.RuntimeClass {
 
 type LimitRangeWrapper struct{ corev1.LimitRange }
 
+// MakeLimitRange creates a wrapper for a LimitRange with a single limit of
+// type Container.
 func MakeLimitRange(name, namespace string) *LimitRangeWrapper {
-	return &LimitRangeWrapper{
+	lr := &LimitRangeWrapper{
 		LimitRange: corev1.LimitRange{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      name,
 				Namespace: namespace,
 			},
-			Spec: corev1.LimitRangeSpec{
-				Limits: []corev1.LimitRangeItem{
-					{
-						Type:                 corev1.LimitTypeContainer,
-						Max:                  corev1.ResourceList{},
-						Min:                  corev1.ResourceList{},
-						Default:              corev1.ResourceList{},
-						DefaultRequest:       corev1.ResourceList{},
-						MaxLimitRequestRatio: corev1.ResourceList{},
-					},
-				},
-			},
 		},
 	}
+	return lr.Limit(corev1.LimitTypeContainer)
+}
+
+// Limit adds a limit of the given type. The following calls to the builder
+// methods apply to it.
+func (lr *LimitRangeWrapper) Limit(t corev1.LimitType) *LimitRangeWrapper {
+	lr.Spec.Limits = append(lr.Spec.Limits, corev1.LimitRangeItem{
+		Type:                 t,
+		Max:                  corev1.ResourceList{},
+		Min:                  corev1.ResourceList{},
+		Default:              corev1.ResourceList{},
+		DefaultRequest:       corev1.ResourceList{},
+		MaxLimitRequestRatio: corev1.ResourceList{},
+	})
+	return lr
+}
+
+func (lr *LimitRangeWrapper) lastLimit() *corev1.LimitRangeItem {
+	return &lr.Spec.Limits[len(lr.Spec.Limits)-1]
 }
 
 func (lr *LimitRangeWrapper) WithType(t corev1.LimitType) *LimitRangeWrapper {
-	lr.Spec.Limits[0].Type = t
+	lr.lastLimit().Type = t
 	return lr
 }
 
 func (lr *LimitRangeWrapper) WithValue(member string, t corev1.ResourceName, q string) *LimitRangeWrapper {
-	target := lr.Spec.Limits[0].Max
 	switch member {
 	case "Min":
-		target = lr.Spec.Limits[0].Min
+		return lr.Min(t, q)
 	case "DefaultRequest":
-		target = lr.Spec.Limits[0].DefaultRequest
+		return lr.DefaultRequest(t, q)
 	case "Default":
-		target = lr.Spec.Limits[0].Default
+		return lr.Default(t, q)
 	case "Max":
-	// nothing
+		return lr.Max(t, q)
 	default:
 		panic("Unexpected member " + member)
 	}
-	target[t] = resource.MustParse(q)
+}
+
+// Max sets the max value of the resource in the last limit.
+func (lr *LimitRangeWrapper) Max(r corev1.ResourceName, q string) *LimitRangeWrapper {
+	lr.lastLimit().Max[r] = resource.MustParse(q)
+	return lr
+}
+
+// Min sets the min value of the resource in the last limit.
+func (lr *LimitRangeWrapper) Min(r corev1.ResourceName, q string) *LimitRangeWrapper {
+	lr.lastLimit().Min[r] = resource.MustParse(q)
+	return lr
+}
+
+// Default sets the default limit of the resource in the last limit.
+func (lr *LimitRangeWrapper) Default(r corev1.ResourceName, q string) *LimitRangeWrapper {
+	lr.lastLimit().Default[r] = resource.MustParse(q)
+	return lr
+}
+
+// DefaultRequest sets the default request of the resource in the last limit.
+func (lr *LimitRangeWrapper) DefaultRequest(r corev1.ResourceName, q string) *LimitRangeWrapper {
+	lr.lastLimit().DefaultRequest[r] = resource.MustParse(q)
 	return lr
 }
 
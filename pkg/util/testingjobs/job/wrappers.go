@@ -211,6 +211,12 @@ func (j *JobWrapper) UID(uid string) *JobWrapper {
 	return j
 }
 
+// CreationTimestamp sets the .metadata.creationTimestamp
+func (j *JobWrapper) CreationTimestamp(t time.Time) *JobWrapper {
+	j.ObjectMeta.CreationTimestamp = metav1.NewTime(t)
+	return j
+}
+
 // StartTime sets the .status.startTime
 func (j *JobWrapper) StartTime(t time.Time) *JobWrapper {
 	j.Status.StartTime = &metav1.Time{Time: t}
@@ -223,6 +229,12 @@ func (j *JobWrapper) Active(c int32) *JobWrapper {
 	return j
 }
 
+// Ready sets the .status.ready
+func (j *JobWrapper) Ready(c int32) *JobWrapper {
+	j.Status.Ready = ptr.To(c)
+	return j
+}
+
 // Condition adds a condition
 func (j *JobWrapper) Condition(c batchv1.JobCondition) *JobWrapper {
 	j.Status.Conditions = append(j.Status.Conditions, c)
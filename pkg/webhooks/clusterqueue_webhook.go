@@ -18,6 +18,7 @@ package webhooks
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -34,11 +35,13 @@ import (
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/features"
+	"sigs.k8s.io/kueue/pkg/util/cron"
 )
 
 const (
-	limitIsEmptyErrorMsg string = `must be nil when cohort is empty`
-	lendingLimitErrorMsg string = `must be less than or equal to the nominalQuota`
+	limitIsEmptyErrorMsg  string = `must be nil when cohort is empty`
+	lendingLimitErrorMsg  string = `must be less than or equal to the nominalQuota`
+	integerQuantityErrMsg string = `must be an integer for extended and integer resources`
 )
 
 type ClusterQueueWebhook struct{}
@@ -99,7 +102,8 @@ func ValidateClusterQueue(cq *kueue.ClusterQueue) field.ErrorList {
 	path := field.NewPath("spec")
 
 	var allErrs field.ErrorList
-	allErrs = append(allErrs, validateResourceGroups(cq.Spec.ResourceGroups, cq.Spec.Cohort, path.Child("resourceGroups"))...)
+	integerResources := sets.New(cq.Spec.IntegerResources...)
+	allErrs = append(allErrs, validateResourceGroups(cq.Spec.ResourceGroups, cq.Spec.Cohort, integerResources, path.Child("resourceGroups"))...)
 	allErrs = append(allErrs,
 		validation.ValidateLabelSelector(cq.Spec.NamespaceSelector, validation.LabelSelectorValidationOptions{}, path.Child("namespaceSelector"))...)
 	allErrs = append(allErrs, validateCQAdmissionChecks(&cq.Spec, path)...)
@@ -109,6 +113,11 @@ func ValidateClusterQueue(cq *kueue.ClusterQueue) field.ErrorList {
 	if cq.Spec.FairSharing != nil {
 		allErrs = append(allErrs, validateFairSharing(cq.Spec.FairSharing, path.Child("fairSharing"))...)
 	}
+	allErrs = append(allErrs, validateResourceAliases(&cq.Spec, path.Child("resourceAliases"))...)
+	allErrs = append(allErrs, validateResourceSlices(&cq.Spec, path.Child("resourceSlices"))...)
+	allErrs = append(allErrs, validateResourceTransforms(cq.Spec.ResourceTransforms, integerResources, path.Child("resourceTransforms"))...)
+	allErrs = append(allErrs, validateIntegerResources(cq.Spec.IntegerResources, path.Child("integerResources"))...)
+	allErrs = append(allErrs, validateQuotaWindows(&cq.Spec, integerResources, path.Child("quotaWindows"))...)
 	return allErrs
 }
 
@@ -125,6 +134,9 @@ func validatePreemption(preemption *kueue.ClusterQueuePreemption, path *field.Pa
 		preemption.BorrowWithinCohort.Policy != kueue.BorrowWithinCohortPolicyNever {
 		allErrs = append(allErrs, field.Invalid(path, preemption, "reclaimWithinCohort=Never and borrowWithinCohort.Policy!=Never"))
 	}
+	if preemption.ReclaimCooldown != nil && preemption.ReclaimCooldown.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("reclaimCooldown"), preemption.ReclaimCooldown.Duration.String(), constants.IsNegativeErrorMsg))
+	}
 	return allErrs
 }
 
@@ -137,7 +149,7 @@ func validateCQAdmissionChecks(spec *kueue.ClusterQueueSpec, path *field.Path) f
 	return allErrs
 }
 
-func validateResourceGroups(resourceGroups []kueue.ResourceGroup, cohort string, path *field.Path) field.ErrorList {
+func validateResourceGroups(resourceGroups []kueue.ResourceGroup, cohort string, integerResources sets.Set[corev1.ResourceName], path *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	seenResources := sets.New[corev1.ResourceName]()
 	seenFlavors := sets.New[kueue.ResourceFlavorReference]()
@@ -155,7 +167,7 @@ func validateResourceGroups(resourceGroups []kueue.ResourceGroup, cohort string,
 		}
 		for j, fqs := range rg.Flavors {
 			path := path.Child("flavors").Index(j)
-			allErrs = append(allErrs, validateFlavorQuotas(fqs, rg.CoveredResources, cohort, path)...)
+			allErrs = append(allErrs, validateFlavorQuotas(fqs, rg.CoveredResources, cohort, integerResources, path)...)
 			if seenFlavors.Has(fqs.Name) {
 				allErrs = append(allErrs, field.Duplicate(path.Child("name"), fqs.Name))
 			} else {
@@ -166,7 +178,153 @@ func validateResourceGroups(resourceGroups []kueue.ResourceGroup, cohort string,
 	return allErrs
 }
 
-func validateFlavorQuotas(flavorQuotas kueue.FlavorQuotas, coveredResources []corev1.ResourceName, cohort string, path *field.Path) field.ErrorList {
+func validateResourceAliases(spec *kueue.ClusterQueueSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	covered := sets.New[corev1.ResourceName]()
+	for _, rg := range spec.ResourceGroups {
+		covered.Insert(rg.CoveredResources...)
+	}
+	names := sets.New[corev1.ResourceName]()
+	for _, ra := range spec.ResourceAliases {
+		names.Insert(ra.Name)
+	}
+	seenAliases := sets.New[corev1.ResourceName]()
+	for i, ra := range spec.ResourceAliases {
+		path := path.Index(i)
+		allErrs = append(allErrs, validateResourceName(ra.Name, path.Child("name"))...)
+		for j, alias := range ra.Aliases {
+			path := path.Child("aliases").Index(j)
+			allErrs = append(allErrs, validateResourceName(alias, path)...)
+			switch {
+			case seenAliases.Has(alias):
+				allErrs = append(allErrs, field.Duplicate(path, alias))
+			case covered.Has(alias):
+				allErrs = append(allErrs, field.Invalid(path, alias, "must not be covered by the resourceGroups"))
+			case names.Has(alias):
+				allErrs = append(allErrs, field.Invalid(path, alias, "must not be aliased"))
+			}
+			seenAliases.Insert(alias)
+		}
+	}
+	return allErrs
+}
+
+func validateResourceSlices(spec *kueue.ClusterQueueSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	covered := sets.New[corev1.ResourceName]()
+	for _, rg := range spec.ResourceGroups {
+		covered.Insert(rg.CoveredResources...)
+	}
+	aliases := sets.New[corev1.ResourceName]()
+	for _, ra := range spec.ResourceAliases {
+		aliases.Insert(ra.Aliases...)
+	}
+	names := sets.New[corev1.ResourceName]()
+	for _, rs := range spec.ResourceSlices {
+		names.Insert(rs.Name)
+	}
+	for i, rs := range spec.ResourceSlices {
+		path := path.Index(i)
+		allErrs = append(allErrs, validateResourceName(rs.Name, path.Child("name"))...)
+		switch {
+		case covered.Has(rs.Name):
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), rs.Name, "must not be covered by the resourceGroups"))
+		case aliases.Has(rs.Name):
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), rs.Name, "must not be a resource alias"))
+		}
+		allErrs = append(allErrs, validateResourceName(rs.Resource, path.Child("resource"))...)
+		switch {
+		case names.Has(rs.Resource):
+			allErrs = append(allErrs, field.Invalid(path.Child("resource"), rs.Resource, "must not be sliced"))
+		case aliases.Has(rs.Resource):
+			allErrs = append(allErrs, field.Invalid(path.Child("resource"), rs.Resource, "must not be a resource alias"))
+		}
+		if rs.SlicesPerUnit < 1 {
+			allErrs = append(allErrs, field.Invalid(path.Child("slicesPerUnit"), rs.SlicesPerUnit, "must be greater than 0"))
+		}
+	}
+	return allErrs
+}
+
+func validateResourceTransforms(transforms []kueue.ResourceTransform, integerResources sets.Set[corev1.ResourceName], path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, t := range transforms {
+		path := path.Index(i)
+		allErrs = append(allErrs, validateResourceName(t.Name, path.Child("name"))...)
+		if t.Multiplier == nil && t.Minimum == nil && t.PodDefault == nil {
+			allErrs = append(allErrs, field.Required(path, "either multiplier, minimum or podDefault must be set"))
+		}
+		if t.Multiplier != nil && t.Multiplier.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("multiplier"), t.Multiplier.String(), "must be greater than 0"))
+		}
+		if t.Minimum != nil {
+			allErrs = append(allErrs, validateResourceQuantity(*t.Minimum, path.Child("minimum"))...)
+			allErrs = append(allErrs, validateQuantityUnits(t.Name, *t.Minimum, integerResources, path.Child("minimum"))...)
+		}
+		if t.PodDefault != nil {
+			allErrs = append(allErrs, validateResourceQuantity(*t.PodDefault, path.Child("podDefault"))...)
+			allErrs = append(allErrs, validateQuantityUnits(t.Name, *t.PodDefault, integerResources, path.Child("podDefault"))...)
+		}
+	}
+	return allErrs
+}
+
+func validateQuotaWindows(spec *kueue.ClusterQueueSpec, integerResources sets.Set[corev1.ResourceName], path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	flavorResources := make(map[kueue.ResourceFlavorReference]sets.Set[corev1.ResourceName])
+	for _, rg := range spec.ResourceGroups {
+		for _, fq := range rg.Flavors {
+			flavorResources[fq.Name] = sets.New(rg.CoveredResources...)
+		}
+	}
+	for i, w := range spec.QuotaWindows {
+		path := path.Index(i)
+		if _, err := cron.Parse(w.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("schedule"), w.Schedule, fmt.Sprintf("must be a cron schedule: %v", err)))
+		}
+		if w.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("duration"), w.Duration.Duration.String(), "must be positive"))
+		}
+		for j, fq := range w.Flavors {
+			path := path.Child("flavors").Index(j)
+			resources, found := flavorResources[fq.Name]
+			if !found {
+				allErrs = append(allErrs, field.Invalid(path.Child("name"), fq.Name, "must be a flavor of the resourceGroups"))
+				continue
+			}
+			// The window can replace the quotas of a subset of the resources
+			// of the flavor, in any order.
+			var windowResources []corev1.ResourceName
+			for k, rq := range fq.Resources {
+				if !resources.Has(rq.Name) {
+					allErrs = append(allErrs, field.Invalid(path.Child("resources").Index(k).Child("name"), rq.Name, "must be a resource of the flavor in the resourceGroups"))
+				}
+				if rq.BorrowingLimit != nil {
+					allErrs = append(allErrs, validateLimit(*rq.BorrowingLimit, spec.Cohort, path.Child("resources").Index(k).Child("borrowingLimit"))...)
+				}
+				windowResources = append(windowResources, rq.Name)
+			}
+			allErrs = append(allErrs, validateFlavorQuotas(fq, windowResources, spec.Cohort, integerResources, path)...)
+		}
+	}
+	return allErrs
+}
+
+func validateIntegerResources(names []corev1.ResourceName, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.New[corev1.ResourceName]()
+	for i, name := range names {
+		path := path.Index(i)
+		allErrs = append(allErrs, validateResourceName(name, path)...)
+		if seen.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(path, name))
+		}
+		seen.Insert(name)
+	}
+	return allErrs
+}
+
+func validateFlavorQuotas(flavorQuotas kueue.FlavorQuotas, coveredResources []corev1.ResourceName, cohort string, integerResources sets.Set[corev1.ResourceName], path *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	for i, rq := range flavorQuotas.Resources {
@@ -178,16 +336,26 @@ func validateFlavorQuotas(flavorQuotas kueue.FlavorQuotas, coveredResources []co
 			allErrs = append(allErrs, field.Invalid(path.Child("name"), rq.Name, "must match the name in coveredResources"))
 		}
 		allErrs = append(allErrs, validateResourceQuantity(rq.NominalQuota, path.Child("nominalQuota"))...)
+		allErrs = append(allErrs, validateQuantityUnits(rq.Name, rq.NominalQuota, integerResources, path.Child("nominalQuota"))...)
 		if rq.BorrowingLimit != nil {
 			borrowingLimitPath := path.Child("borrowingLimit")
 			allErrs = append(allErrs, validateResourceQuantity(*rq.BorrowingLimit, borrowingLimitPath)...)
+			allErrs = append(allErrs, validateQuantityUnits(rq.Name, *rq.BorrowingLimit, integerResources, borrowingLimitPath)...)
 		}
 		if features.Enabled(features.LendingLimit) && rq.LendingLimit != nil {
 			lendingLimitPath := path.Child("lendingLimit")
 			allErrs = append(allErrs, validateResourceQuantity(*rq.LendingLimit, lendingLimitPath)...)
+			allErrs = append(allErrs, validateQuantityUnits(rq.Name, *rq.LendingLimit, integerResources, lendingLimitPath)...)
 			allErrs = append(allErrs, validateLimit(*rq.LendingLimit, cohort, lendingLimitPath)...)
 			allErrs = append(allErrs, validateLendingLimit(*rq.LendingLimit, rq.NominalQuota, lendingLimitPath)...)
 		}
+		if features.Enabled(features.LendingLimit) && rq.NonLendableQuota != nil {
+			nonLendablePath := path.Child("nonLendableQuota")
+			allErrs = append(allErrs, validateResourceQuantity(*rq.NonLendableQuota, nonLendablePath)...)
+			allErrs = append(allErrs, validateQuantityUnits(rq.Name, *rq.NonLendableQuota, integerResources, nonLendablePath)...)
+			allErrs = append(allErrs, validateLimit(*rq.NonLendableQuota, cohort, nonLendablePath)...)
+			allErrs = append(allErrs, validateLendingLimit(*rq.NonLendableQuota, rq.NominalQuota, nonLendablePath)...)
+		}
 	}
 	return allErrs
 }
@@ -201,7 +369,18 @@ func validateResourceQuantity(value resource.Quantity, fldPath *field.Path) fiel
 	return allErrs
 }
 
-// validateLimit enforces that BorrowingLimit or LendingLimit must be nil when cohort is empty
+// validateQuantityUnits enforces that the quantity of an extended resource, or
+// of a resource declared as integer, is an integer, as pods can only request
+// whole units of them.
+func validateQuantityUnits(name corev1.ResourceName, value resource.Quantity, integerResources sets.Set[corev1.ResourceName], fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if (isExtendedResourceName(name) || integerResources.Has(name)) && value.MilliValue()%1000 != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, value.String(), integerQuantityErrMsg))
+	}
+	return allErrs
+}
+
+// validateLimit enforces that BorrowingLimit, LendingLimit or NonLendableQuota must be nil when cohort is empty
 func validateLimit(limit resource.Quantity, cohort string, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	if len(cohort) == 0 {
@@ -210,7 +389,7 @@ func validateLimit(limit resource.Quantity, cohort string, fldPath *field.Path)
 	return allErrs
 }
 
-// validateLendingLimit enforces that LendingLimit is not greater than NominalQuota
+// validateLendingLimit enforces that LendingLimit or NonLendableQuota is not greater than NominalQuota
 func validateLendingLimit(lend, nominal resource.Quantity, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	if lend.Cmp(nominal) > 0 {
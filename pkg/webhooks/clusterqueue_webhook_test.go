@@ -18,6 +18,7 @@ package webhooks
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -130,6 +131,29 @@ func TestValidateClusterQueue(t *testing.T) {
 				field.Invalid(resourceGroupsPath.Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("borrowingLimit"), "-1", ""),
 			},
 		},
+		{
+			name: "fractional quota for an extended resource",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("gpu").Resource("example.com/gpu", "1500m", "0.5").Obj()).
+				Cohort("cohort").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(resourceGroupsPath.Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("nominalQuota"), "1500m", integerQuantityErrMsg),
+				field.Invalid(resourceGroupsPath.Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("borrowingLimit"), "500m", integerQuantityErrMsg),
+			},
+		},
+		{
+			name: "integer quota for an extended resource and fractional quota for native resources",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("default").
+						Resource("example.com/gpu", "2").
+						Resource("cpu", "1500m").
+						Resource("kubernetes.io/batch-cpu", "500m").
+						Obj()).
+				Obj(),
+		},
 		{
 			name: "flavor quota with lendingLimit 0",
 			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
@@ -174,6 +198,50 @@ func TestValidateClusterQueue(t *testing.T) {
 			},
 			enableLendingLimit: true,
 		},
+		{
+			name: "flavor quota with nonLendableQuota",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("x86").Resource("cpu", "2", "", "2").NonLendableQuota("cpu", "1").Obj()).
+				Cohort("cohort").
+				Obj(),
+			enableLendingLimit: true,
+		},
+		{
+			name: "flavor quota with negative nonLendableQuota",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("x86").Resource("cpu", "1").NonLendableQuota("cpu", "-1").Obj()).
+				Cohort("cohort").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(resourceGroupsPath.Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("nonLendableQuota"), "-1", ""),
+			},
+			enableLendingLimit: true,
+		},
+		{
+			name: "flavor quota with nonLendableQuota and empty cohort",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("x86").Resource("cpu", "1").NonLendableQuota("cpu", "1").Obj()).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(resourceGroupsPath.Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("nonLendableQuota"), "1", limitIsEmptyErrorMsg),
+			},
+			enableLendingLimit: true,
+		},
+		{
+			name: "flavor quota with nonLendableQuota greater than nominalQuota",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("x86").Resource("cpu", "1").NonLendableQuota("cpu", "2").Obj()).
+				Cohort("cohort").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(resourceGroupsPath.Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("nonLendableQuota"), "2", lendingLimitErrorMsg),
+			},
+			enableLendingLimit: true,
+		},
 		{
 			name: "empty queueing strategy is supported",
 			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
@@ -274,6 +342,19 @@ func TestValidateClusterQueue(t *testing.T) {
 				field.Duplicate(resourceGroupsPath.Index(1).Child("coveredResources").Index(0), nil),
 			},
 		},
+		{
+			name: "flavor listed twice in a resource group",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("alpha").Resource("cpu", "1").Resource("memory", "1Gi").Obj(),
+					*testingutil.MakeFlavorQuotas("beta").Resource("cpu", "1").Resource("memory", "1Gi").Obj(),
+					*testingutil.MakeFlavorQuotas("alpha").Resource("cpu", "2").Resource("memory", "2Gi").Obj(),
+				).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Duplicate(resourceGroupsPath.Index(0).Child("flavors").Index(2).Child("name"), nil),
+			},
+		},
 		{
 			name: "flavor in more than one resource group",
 			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
@@ -306,6 +387,25 @@ func TestValidateClusterQueue(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid preemption with reclaimCooldown",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				Preemption(kueue.ClusterQueuePreemption{
+					ReclaimCooldown: &metav1.Duration{Duration: 5 * time.Minute},
+				}).
+				Obj(),
+		},
+		{
+			name: "negative reclaimCooldown",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				Preemption(kueue.ClusterQueuePreemption{
+					ReclaimCooldown: &metav1.Duration{Duration: -time.Minute},
+				}).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("preemption", "reclaimCooldown"), "-1m0s", ""),
+			},
+		},
 		{
 			name: "existing cluster queue created with older Kueue version that has a nil borrowWithinCohort field",
 			clusterQueue: &kueue.ClusterQueue{
@@ -319,6 +419,176 @@ func TestValidateClusterQueue(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid resource aliases",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(*testingutil.MakeFlavorQuotas("default").Resource("example.com/gpu").Obj()).
+				ResourceAlias("example.com/gpu", "nvidia.com/gpu", "amd.com/gpu").
+				Obj(),
+		},
+		{
+			name: "invalid resource aliases",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(*testingutil.MakeFlavorQuotas("default").Resource("cpu").Resource("example.com/gpu").Obj()).
+				ResourceAlias("example.com/gpu", "nvidia.com/gpu", "cpu", "@gpu").
+				ResourceAlias("example.com/accelerator", "nvidia.com/gpu", "example.com/gpu").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("resourceAliases").Index(0).Child("aliases").Index(1), "cpu", ""),
+				field.Invalid(specPath.Child("resourceAliases").Index(0).Child("aliases").Index(2), "@gpu", ""),
+				field.Duplicate(specPath.Child("resourceAliases").Index(1).Child("aliases").Index(0), nil),
+				field.Invalid(specPath.Child("resourceAliases").Index(1).Child("aliases").Index(1), "example.com/gpu", ""),
+			},
+		},
+		{
+			name: "resource aliases forming a cycle",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(*testingutil.MakeFlavorQuotas("default").Resource("cpu").Obj()).
+				ResourceAlias("example.com/gpu", "nvidia.com/gpu").
+				ResourceAlias("nvidia.com/gpu", "example.com/gpu").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("resourceAliases").Index(0).Child("aliases").Index(0), "nvidia.com/gpu", ""),
+				field.Invalid(specPath.Child("resourceAliases").Index(1).Child("aliases").Index(0), "example.com/gpu", ""),
+			},
+		},
+		{
+			name: "valid resource slices",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(*testingutil.MakeFlavorQuotas("default").Resource("nvidia.com/gpu").Obj()).
+				ResourceSlice("nvidia.com/mig-1g.5gb", "nvidia.com/gpu", 7).
+				ResourceSlice("nvidia.com/mig-3g.20gb", "nvidia.com/gpu", 2).
+				Obj(),
+		},
+		{
+			name: "invalid resource slices",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(*testingutil.MakeFlavorQuotas("default").Resource("cpu").Resource("example.com/gpu").Obj()).
+				ResourceAlias("example.com/gpu", "nvidia.com/gpu").
+				ResourceSlice("cpu", "example.com/gpu", 2).
+				ResourceSlice("nvidia.com/gpu", "example.com/gpu", 7).
+				ResourceSlice("nvidia.com/mig-1g.5gb", "nvidia.com/gpu", 7).
+				ResourceSlice("example.com/slice", "nvidia.com/mig-1g.5gb", 0).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("resourceSlices").Index(0).Child("name"), "cpu", ""),
+				field.Invalid(specPath.Child("resourceSlices").Index(1).Child("name"), "nvidia.com/gpu", ""),
+				field.Invalid(specPath.Child("resourceSlices").Index(2).Child("resource"), "nvidia.com/gpu", ""),
+				field.Invalid(specPath.Child("resourceSlices").Index(3).Child("resource"), "nvidia.com/mig-1g.5gb", ""),
+				field.Invalid(specPath.Child("resourceSlices").Index(3).Child("slicesPerUnit"), int32(0), ""),
+			},
+		},
+		{
+			name: "valid resource transforms",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceTransform(corev1.ResourceMemory, "1.1", "").
+				ResourceTransform(corev1.ResourceCPU, "", "100m").
+				ResourceTransform("example.com/gpu", "2", "1").
+				ResourceTransformPodDefault(corev1.ResourceEphemeralStorage, "1Gi").
+				Obj(),
+		},
+		{
+			name: "invalid resource transforms",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceTransform("@memory", "1.1", "").
+				ResourceTransform(corev1.ResourceCPU, "", "").
+				ResourceTransform(corev1.ResourceMemory, "0", "-1").
+				ResourceTransform("example.com/gpu", "", "500m").
+				ResourceTransformPodDefault(corev1.ResourceEphemeralStorage, "-1Gi").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("resourceTransforms").Index(0).Child("name"), "@memory", ""),
+				field.Required(specPath.Child("resourceTransforms").Index(1), ""),
+				field.Invalid(specPath.Child("resourceTransforms").Index(2).Child("multiplier"), "0", ""),
+				field.Invalid(specPath.Child("resourceTransforms").Index(2).Child("minimum"), "-1", ""),
+				field.Invalid(specPath.Child("resourceTransforms").Index(3).Child("minimum"), "500m", ""),
+				field.Invalid(specPath.Child("resourceTransforms").Index(4).Child("podDefault"), "-1Gi", ""),
+			},
+		},
+		{
+			name: "valid quota windows",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				Cohort("cohort").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "10").
+						Resource(corev1.ResourceMemory, "10Gi").
+						Obj()).
+				QuotaWindow("night", "0 22 * * 1-5", 8*time.Hour,
+					*testingutil.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceMemory, "20Gi").
+						Resource(corev1.ResourceCPU, "20", "5").
+						Obj()).
+				Obj(),
+		},
+		{
+			name: "invalid quota windows",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "10").
+						Obj()).
+				QuotaWindow("night", "0 22 * * *", 0,
+					*testingutil.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceMemory, "20Gi").
+						Resource(corev1.ResourceCPU, "-1", "5").
+						Obj(),
+					*testingutil.MakeFlavorQuotas("spot").
+						Resource(corev1.ResourceCPU, "10").
+						Obj()).
+				QuotaWindow("day", "0 9 * *", 8*time.Hour,
+					*testingutil.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "5").
+						Obj()).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("quotaWindows").Index(0).Child("duration"), "0s", ""),
+				field.Invalid(specPath.Child("quotaWindows").Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("name"), corev1.ResourceMemory, ""),
+				field.Invalid(specPath.Child("quotaWindows").Index(0).Child("flavors").Index(0).Child("resources").Index(1).Child("borrowingLimit"), "5", ""),
+				field.Invalid(specPath.Child("quotaWindows").Index(0).Child("flavors").Index(0).Child("resources").Index(1).Child("nominalQuota"), "-1", ""),
+				field.Invalid(specPath.Child("quotaWindows").Index(0).Child("flavors").Index(1).Child("name"), "spot", ""),
+				field.Invalid(specPath.Child("quotaWindows").Index(1).Child("schedule"), "0 9 * *", ""),
+			},
+		},
+		{
+			name: "integer quotas for integer resources",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				Cohort("cohort").
+				IntegerResources(corev1.ResourceCPU).
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "2", "1").
+						Resource(corev1.ResourceMemory, "1.5Gi").
+						Obj()).
+				Obj(),
+		},
+		{
+			name: "fractional quotas for integer resources",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				Cohort("cohort").
+				IntegerResources(corev1.ResourceCPU).
+				ResourceGroup(
+					*testingutil.MakeFlavorQuotas("default").
+						Resource(corev1.ResourceCPU, "1500m", "500m").
+						Obj()).
+				ResourceTransform(corev1.ResourceCPU, "", "100m").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(resourceGroupsPath.Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("nominalQuota"), "1500m", integerQuantityErrMsg),
+				field.Invalid(resourceGroupsPath.Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("borrowingLimit"), "500m", integerQuantityErrMsg),
+				field.Invalid(specPath.Child("resourceTransforms").Index(0).Child("minimum"), "100m", integerQuantityErrMsg),
+			},
+		},
+		{
+			name: "invalid integer resources",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				IntegerResources("@gpu", "example.com/gpu", "example.com/gpu").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("integerResources").Index(0), "@gpu", ""),
+				field.Duplicate(specPath.Child("integerResources").Index(2), "example.com/gpu"),
+			},
+		},
 	}
 
 	for _, tc := range testcases {
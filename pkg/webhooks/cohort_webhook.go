@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/constants"
+	utilcohort "sigs.k8s.io/kueue/pkg/util/cohort"
+)
+
+type CohortWebhook struct {
+	client client.Client
+}
+
+func setupWebhookForCohort(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&kueuealpha.Cohort{}).
+		WithValidator(&CohortWebhook{client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-kueue-x-k8s-io-v1alpha1-cohort,mutating=false,failurePolicy=fail,sideEffects=None,groups=kueue.x-k8s.io,resources=cohorts,verbs=create;update,versions=v1alpha1,name=vcohort.kb.io,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &CohortWebhook{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *CohortWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cohort := obj.(*kueuealpha.Cohort)
+	log := ctrl.LoggerFrom(ctx).WithName("cohort-webhook")
+	log.V(5).Info("Validating create", "cohort", klog.KObj(cohort))
+	allErrs := ValidateCohort(cohort)
+	allErrs = append(allErrs, w.validateNoCycle(ctx, cohort)...)
+	return nil, allErrs.ToAggregate()
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *CohortWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	newCohort := newObj.(*kueuealpha.Cohort)
+	oldCohort := oldObj.(*kueuealpha.Cohort)
+	log := ctrl.LoggerFrom(ctx).WithName("cohort-webhook")
+	log.V(5).Info("Validating update", "cohort", klog.KObj(newCohort))
+	allErrs := ValidateCohort(newCohort)
+	if newCohort.Spec.Parent != oldCohort.Spec.Parent {
+		allErrs = append(allErrs, w.validateNoCycle(ctx, newCohort)...)
+	}
+	return nil, allErrs.ToAggregate()
+}
+
+// validateNoCycle rejects the cohort if following its parent, and the parents
+// set through the existing Cohort objects, leads to a cycle.
+func (w *CohortWebhook) validateNoCycle(ctx context.Context, cohort *kueuealpha.Cohort) field.ErrorList {
+	parentPath := field.NewPath("spec", "parent")
+	if cohort.Spec.Parent == "" || cohort.Spec.Parent == cohort.Name {
+		// A cohort being its own parent is reported by ValidateCohort.
+		return nil
+	}
+	var getErr error
+	parentOf := func(name string) string {
+		if name == cohort.Name {
+			return cohort.Spec.Parent
+		}
+		var ancestor kueuealpha.Cohort
+		if err := w.client.Get(ctx, client.ObjectKey{Name: name}, &ancestor); err != nil {
+			if !apierrors.IsNotFound(err) {
+				getErr = err
+			}
+			return ""
+		}
+		return ancestor.Spec.Parent
+	}
+	cycle := utilcohort.Cycle(cohort.Name, parentOf)
+	if getErr != nil {
+		return field.ErrorList{field.InternalError(parentPath, getErr)}
+	}
+	if cycle != nil {
+		return field.ErrorList{field.Invalid(parentPath, cohort.Spec.Parent, fmt.Sprintf("must not form a cycle: %s", strings.Join(cycle, " -> ")))}
+	}
+	return nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *CohortWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func ValidateCohort(cohort *kueuealpha.Cohort) field.ErrorList {
+	var allErrs field.ErrorList
+	capsPath := field.NewPath("spec", "borrowingCaps")
+	for i, fCaps := range cohort.Spec.BorrowingCaps {
+		resourcesPath := capsPath.Index(i).Child("resources")
+		for j, rCap := range fCaps.Resources {
+			path := resourcesPath.Index(j)
+			allErrs = append(allErrs, validateResourceName(rCap.Name, path.Child("name"))...)
+			allErrs = append(allErrs, validateResourceQuantity(rCap.Cap, path.Child("cap"))...)
+		}
+	}
+	if cohort.Spec.ReclaimDelay != nil && cohort.Spec.ReclaimDelay.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "reclaimDelay"), cohort.Spec.ReclaimDelay.Duration.String(), constants.IsNegativeErrorMsg))
+	}
+	if cohort.Spec.Parent == cohort.Name {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "parent"), cohort.Spec.Parent, "must not be the cohort itself"))
+	}
+	return allErrs
+}
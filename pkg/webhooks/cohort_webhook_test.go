@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueuealpha "sigs.k8s.io/kueue/apis/kueue/v1alpha1"
+	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestValidateCohort(t *testing.T) {
+	capsPath := field.NewPath("spec", "borrowingCaps")
+	testcases := []struct {
+		name         string
+		caps         []kueuealpha.FlavorBorrowingCaps
+		reclaimDelay *metav1.Duration
+		parent       string
+		wantErr      field.ErrorList
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			caps: []kueuealpha.FlavorBorrowingCaps{{
+				Name: "default",
+				Resources: []kueuealpha.ResourceBorrowingCap{
+					{Name: corev1.ResourceCPU, Cap: resource.MustParse("10")},
+					{Name: corev1.ResourceMemory, Cap: resource.MustParse("0")},
+				},
+			}},
+		},
+		{
+			name: "negative cap",
+			caps: []kueuealpha.FlavorBorrowingCaps{{
+				Name: "default",
+				Resources: []kueuealpha.ResourceBorrowingCap{
+					{Name: corev1.ResourceCPU, Cap: resource.MustParse("-1")},
+				},
+			}},
+			wantErr: field.ErrorList{
+				field.Invalid(capsPath.Index(0).Child("resources").Index(0).Child("cap"), "-1", ""),
+			},
+		},
+		{
+			name: "invalid resource name",
+			caps: []kueuealpha.FlavorBorrowingCaps{{
+				Name: "default",
+				Resources: []kueuealpha.ResourceBorrowingCap{
+					{Name: "@cpu", Cap: resource.MustParse("1")},
+				},
+			}},
+			wantErr: field.ErrorList{
+				field.Invalid(capsPath.Index(0).Child("resources").Index(0).Child("name"), "@cpu", ""),
+			},
+		},
+		{
+			name:         "valid reclaim delay",
+			reclaimDelay: &metav1.Duration{Duration: 5 * time.Minute},
+		},
+		{
+			name:         "negative reclaim delay",
+			reclaimDelay: &metav1.Duration{Duration: -time.Minute},
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "reclaimDelay"), "-1m0s", ""),
+			},
+		},
+		{
+			name:   "valid parent",
+			parent: "org",
+		},
+		{
+			name:   "parent is the cohort itself",
+			parent: "cohort",
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "parent"), "cohort", ""),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cohort := &kueuealpha.Cohort{
+				ObjectMeta: metav1.ObjectMeta{Name: "cohort"},
+				Spec: kueuealpha.CohortSpec{
+					BorrowingCaps: tc.caps,
+					ReclaimDelay:  tc.reclaimDelay,
+					Parent:        tc.parent,
+				},
+			}
+			gotErr := ValidateCohort(cohort)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("ValidateCohort() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCohortCycle(t *testing.T) {
+	existing := []client.Object{
+		testingutil.MakeCohort("org").Obj(),
+		testingutil.MakeCohort("dept").Parent("org").Obj(),
+		testingutil.MakeCohort("team").Parent("dept").Obj(),
+		testingutil.MakeCohort("cycle-a").Parent("cycle-b").Obj(),
+		testingutil.MakeCohort("cycle-b").Parent("cycle-a").Obj(),
+	}
+	parentPath := field.NewPath("spec", "parent")
+	testCases := map[string]struct {
+		old     *kueuealpha.Cohort
+		cohort  *kueuealpha.Cohort
+		wantErr field.ErrorList
+	}{
+		"create with an existing hierarchy": {
+			cohort: testingutil.MakeCohort("group").Parent("team").Obj(),
+		},
+		"create with a parent without Cohort object": {
+			cohort: testingutil.MakeCohort("group").Parent("missing").Obj(),
+		},
+		"create below a cycle": {
+			cohort: testingutil.MakeCohort("group").Parent("cycle-a").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(parentPath, "cycle-a", ""),
+			},
+		},
+		"create with itself as an ancestor": {
+			cohort: testingutil.MakeCohort("org").Parent("team").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(parentPath, "team", ""),
+			},
+		},
+		"update the parent to form a cycle": {
+			old:    testingutil.MakeCohort("org").Obj(),
+			cohort: testingutil.MakeCohort("org").Parent("team").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(parentPath, "team", ""),
+			},
+		},
+		"update a cohort of a cycle without changing its parent": {
+			old:    testingutil.MakeCohort("cycle-a").Parent("cycle-b").Obj(),
+			cohort: testingutil.MakeCohort("cycle-a").Parent("cycle-b").ReclaimDelay(time.Minute).Obj(),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := testingutil.ContextWithLog(t)
+			wh := &CohortWebhook{client: testingutil.NewClientBuilder(kueuealpha.AddToScheme).WithObjects(existing...).Build()}
+			var gotErr error
+			if tc.old == nil {
+				_, gotErr = wh.ValidateCreate(ctx, tc.cohort)
+			} else {
+				_, gotErr = wh.ValidateUpdate(ctx, tc.old, tc.cohort)
+			}
+			if diff := cmp.Diff(tc.wantErr.ToAggregate(), gotErr, cmpopts.IgnoreFields(field.Error{}, "Detail")); diff != "" {
+				t.Errorf("Unexpected error (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package webhooks
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -13,3 +15,14 @@ func validateResourceName(name corev1.ResourceName, fldPath *field.Path) field.E
 	}
 	return allErrs
 }
+
+// isExtendedResourceName returns whether the resource is an extended resource,
+// that is, a resource with a domain-prefixed name outside of the
+// kubernetes.io domain.
+func isExtendedResourceName(name corev1.ResourceName) bool {
+	n := string(name)
+	if !strings.Contains(n, "/") || strings.Contains(n, corev1.ResourceDefaultNamespacePrefix) || strings.HasPrefix(n, corev1.DefaultResourceRequestsPrefix) {
+		return false
+	}
+	return len(validation.IsQualifiedName(corev1.DefaultResourceRequestsPrefix+n)) == 0
+}
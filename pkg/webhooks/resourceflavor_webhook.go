@@ -35,6 +35,10 @@ import (
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 )
 
+// maxNodeSelectorRequirements is the maximum number of matchExpressions in
+// the nodeSelector of a flavor, the same as the maximum number of nodeLabels.
+const maxNodeSelectorRequirements = 8
+
 type ResourceFlavorWebhook struct{}
 
 func setupWebhookForResourceFlavor(mgr ctrl.Manager) error {
@@ -91,12 +95,61 @@ func ValidateResourceFlavor(rf *kueue.ResourceFlavor) field.ErrorList {
 
 	specPath := field.NewPath("spec")
 	allErrs = append(allErrs, metavalidation.ValidateLabels(rf.Spec.NodeLabels, specPath.Child("nodeLabels"))...)
+	if rf.Spec.NodeSelector != nil {
+		allErrs = append(allErrs, validateNodeSelectorTerm(rf.Spec.NodeSelector, specPath.Child("nodeSelector"))...)
+	}
 
 	allErrs = append(allErrs, validateNodeTaints(rf.Spec.NodeTaints, specPath.Child("nodeTaints"))...)
 	allErrs = append(allErrs, validateTolerations(rf.Spec.Tolerations, specPath.Child("tolerations"))...)
 	return allErrs
 }
 
+// validateNodeSelectorTerm validates the node selector term of a flavor,
+// which can only select nodes by their labels.
+func validateNodeSelectorTerm(term *corev1.NodeSelectorTerm, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(term.MatchFields) != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("matchFields"), "nodes can only be selected by their labels"))
+	}
+	exprsPath := fldPath.Child("matchExpressions")
+	if len(term.MatchExpressions) == 0 {
+		allErrs = append(allErrs, field.Required(exprsPath, ""))
+	}
+	if len(term.MatchExpressions) > maxNodeSelectorRequirements {
+		allErrs = append(allErrs, field.TooMany(exprsPath, len(term.MatchExpressions), maxNodeSelectorRequirements))
+	}
+	for i, req := range term.MatchExpressions {
+		idxPath := exprsPath.Index(i)
+		allErrs = append(allErrs, metavalidation.ValidateLabelName(req.Key, idxPath.Child("key"))...)
+		valuesPath := idxPath.Child("values")
+		switch req.Operator {
+		case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn:
+			if len(req.Values) == 0 {
+				allErrs = append(allErrs, field.Required(valuesPath, "must be specified when `operator` is 'In' or 'NotIn'"))
+			}
+		case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
+			if len(req.Values) > 0 {
+				allErrs = append(allErrs, field.Forbidden(valuesPath, "may not be specified when `operator` is 'Exists' or 'DoesNotExist'"))
+			}
+		case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+			if len(req.Values) != 1 {
+				allErrs = append(allErrs, field.Required(valuesPath, "must be specified single value when `operator` is 'Lt' or 'Gt'"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("operator"), req.Operator, []corev1.NodeSelectorOperator{
+				corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn, corev1.NodeSelectorOpExists,
+				corev1.NodeSelectorOpDoesNotExist, corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt,
+			}))
+		}
+		for j, v := range req.Values {
+			for _, msg := range validation.IsValidLabelValue(v) {
+				allErrs = append(allErrs, field.Invalid(valuesPath.Index(j), v, msg))
+			}
+		}
+	}
+	return allErrs
+}
+
 // validateNodeTaints is extracted from git.k8s.io/kubernetes/pkg/apis/core/validation/validation.go
 func validateNodeTaints(taints []corev1.Taint, fldPath *field.Path) field.ErrorList {
 	allErrors := field.ErrorList{}
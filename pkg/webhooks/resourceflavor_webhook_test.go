@@ -63,6 +63,55 @@ func TestValidateResourceFlavor(t *testing.T) {
 				field.Invalid(field.NewPath("spec", "nodeLabels"), "@abc", ""),
 			},
 		},
+		{
+			name: "valid node selector term",
+			rf: utiltesting.MakeResourceFlavor("resource-flavor").
+				NodeSelectorTerm(
+					corev1.NodeSelectorRequirement{
+						Key:      corev1.LabelInstanceTypeStable,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{"n2-standard-8", "n2-standard-16"},
+					},
+					corev1.NodeSelectorRequirement{
+						Key:      "spot",
+						Operator: corev1.NodeSelectorOpDoesNotExist,
+					},
+				).Obj(),
+		},
+		{
+			name: "invalid node selector term",
+			rf: func() *kueue.ResourceFlavor {
+				rf := utiltesting.MakeResourceFlavor("resource-flavor").
+					NodeSelectorTerm(
+						corev1.NodeSelectorRequirement{
+							Key:      "@abc",
+							Operator: corev1.NodeSelectorOpExists,
+						},
+						corev1.NodeSelectorRequirement{
+							Key:      "foo",
+							Operator: corev1.NodeSelectorOpIn,
+						},
+						corev1.NodeSelectorRequirement{
+							Key:      "foo",
+							Operator: "Equals",
+							Values:   []string{"@abc"},
+						},
+					).Obj()
+				rf.Spec.NodeSelector.MatchFields = []corev1.NodeSelectorRequirement{{
+					Key:      "metadata.name",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"node-1"},
+				}}
+				return rf
+			}(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "nodeSelector", "matchFields"), ""),
+				field.Invalid(field.NewPath("spec", "nodeSelector", "matchExpressions").Index(0).Child("key"), "@abc", ""),
+				field.Required(field.NewPath("spec", "nodeSelector", "matchExpressions").Index(1).Child("values"), ""),
+				field.NotSupported(field.NewPath("spec", "nodeSelector", "matchExpressions").Index(2).Child("operator"), corev1.NodeSelectorOperator("Equals"), []corev1.NodeSelectorOperator{}),
+				field.Invalid(field.NewPath("spec", "nodeSelector", "matchExpressions").Index(2).Child("values").Index(0), "@abc", ""),
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -18,10 +18,32 @@ package webhooks
 
 import ctrl "sigs.k8s.io/controller-runtime"
 
+type options struct {
+	allowZeroCountPodSets bool
+}
+
+// Option configures the webhooks.
+type Option func(*options)
+
+// WithAllowZeroCountPodSets allows the creation of Workloads with podSets of
+// count 0.
+func WithAllowZeroCountPodSets(allow bool) Option {
+	return func(o *options) {
+		o.allowZeroCountPodSets = allow
+	}
+}
+
 // Setup sets up the webhooks for core controllers. It returns the name of the
 // webhook that failed to create and an error, if any.
-func Setup(mgr ctrl.Manager) (string, error) {
-	if err := setupWebhookForWorkload(mgr); err != nil {
+func Setup(mgr ctrl.Manager, opts ...Option) (string, error) {
+	options := options{
+		allowZeroCountPodSets: true,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := setupWebhookForWorkload(mgr, options); err != nil {
 		return "Workload", err
 	}
 
@@ -33,5 +55,9 @@ func Setup(mgr ctrl.Manager) (string, error) {
 		return "ClusterQueue", err
 	}
 
+	if err := setupWebhookForCohort(mgr); err != nil {
+		return "Cohort", err
+	}
+
 	return "", nil
 }
@@ -29,22 +29,32 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/controller/constants"
+	"sigs.k8s.io/kueue/pkg/controller/core/indexer"
 	"sigs.k8s.io/kueue/pkg/features"
 	"sigs.k8s.io/kueue/pkg/util/slices"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
-type WorkloadWebhook struct{}
+type WorkloadWebhook struct {
+	client                client.Client
+	allowZeroCountPodSets bool
+}
 
-func setupWebhookForWorkload(mgr ctrl.Manager) error {
+func setupWebhookForWorkload(mgr ctrl.Manager, options options) error {
+	wh := &WorkloadWebhook{
+		client:                mgr.GetClient(),
+		allowZeroCountPodSets: options.allowZeroCountPodSets,
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&kueue.Workload{}).
-		WithDefaulter(&WorkloadWebhook{}).
-		WithValidator(&WorkloadWebhook{}).
+		WithDefaulter(wh).
+		WithValidator(wh).
 		Complete()
 }
 
@@ -65,6 +75,17 @@ func (w *WorkloadWebhook) Default(ctx context.Context, obj runtime.Object) error
 		}
 	}
 
+	if features.Enabled(features.RejectDuplicateWorkloads) {
+		hash, err := workload.PodSetsHash(wl)
+		if err != nil {
+			return err
+		}
+		if wl.Labels == nil {
+			wl.Labels = make(map[string]string, 1)
+		}
+		wl.Labels[constants.PodSetsHashLabel] = hash
+	}
+
 	return nil
 }
 
@@ -77,7 +98,39 @@ func (w *WorkloadWebhook) ValidateCreate(ctx context.Context, obj runtime.Object
 	wl := obj.(*kueue.Workload)
 	log := ctrl.LoggerFrom(ctx).WithName("workload-webhook")
 	log.V(5).Info("Validating create", "workload", klog.KObj(wl))
-	return nil, ValidateWorkload(wl).ToAggregate()
+	allErrs := ValidateWorkload(wl)
+	if !w.allowZeroCountPodSets {
+		allErrs = append(allErrs, validatePodSetCounts(wl, field.NewPath("spec", "podSets"))...)
+	}
+	if features.Enabled(features.RejectDuplicateWorkloads) {
+		allErrs = append(allErrs, w.validateNotDuplicate(ctx, wl)...)
+	}
+	return nil, allErrs.ToAggregate()
+}
+
+// validateNotDuplicate rejects the workload if another workload, that is not
+// being deleted, has the same owner and podSets hash.
+func (w *WorkloadWebhook) validateNotDuplicate(ctx context.Context, wl *kueue.Workload) field.ErrorList {
+	hash, found := wl.Labels[constants.PodSetsHashLabel]
+	if !found {
+		return nil
+	}
+	hashPath := field.NewPath("metadata", "labels").Key(constants.PodSetsHashLabel)
+	for _, owner := range wl.OwnerReferences {
+		var wls kueue.WorkloadList
+		if err := w.client.List(ctx, &wls, client.InNamespace(wl.Namespace),
+			client.MatchingLabels{constants.PodSetsHashLabel: hash},
+			client.MatchingFields{indexer.OwnerReferenceUID: string(owner.UID)}); err != nil {
+			return field.ErrorList{field.InternalError(hashPath, err)}
+		}
+		for i := range wls.Items {
+			existing := &wls.Items[i]
+			if existing.Name != wl.Name && existing.DeletionTimestamp.IsZero() {
+				return field.ErrorList{field.Forbidden(hashPath, fmt.Sprintf("the Workload %s has the same owner %s and podSets", existing.Name, owner.Name))}
+			}
+		}
+	}
+	return nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
@@ -99,18 +152,30 @@ func ValidateWorkload(obj *kueue.Workload) field.ErrorList {
 	specPath := field.NewPath("spec")
 
 	variableCountPosets := 0
+	optionalPodSets := 0
 	for i := range obj.Spec.PodSets {
 		ps := &obj.Spec.PodSets[i]
 		allErrs = append(allErrs, validatePodSet(ps, specPath.Child("podSets").Index(i))...)
 		if ps.MinCount != nil {
 			variableCountPosets++
 		}
+		if workload.IsOptional(ps) {
+			optionalPodSets++
+		}
 	}
 
 	if variableCountPosets > 1 {
 		allErrs = append(allErrs, field.Invalid(specPath.Child("podSets"), variableCountPosets, "at most one podSet can use minCount"))
 	}
 
+	if optionalPodSets > 0 && optionalPodSets == len(obj.Spec.PodSets) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("podSets"), optionalPodSets, "at least one podSet must not be optional"))
+	}
+
+	if obj.Spec.ActiveDeadlineSeconds != nil && *obj.Spec.ActiveDeadlineSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("activeDeadlineSeconds"), *obj.Spec.ActiveDeadlineSeconds, "must be greater than 0"))
+	}
+
 	statusPath := field.NewPath("status")
 	if workload.HasQuotaReservation(obj) {
 		allErrs = append(allErrs, validateAdmission(obj, statusPath.Child("admission"))...)
@@ -123,6 +188,19 @@ func ValidateWorkload(obj *kueue.Workload) field.ErrorList {
 	return allErrs
 }
 
+// validatePodSetCounts rejects the podSets of count 0. It's only enforced on
+// creation, so that Workloads created before the validation, or while it was
+// disabled, can still be updated.
+func validatePodSetCounts(obj *kueue.Workload, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i := range obj.Spec.PodSets {
+		if count := obj.Spec.PodSets[i].Count; count < 1 {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("count"), count, "must be greater than 0"))
+		}
+	}
+	return allErrs
+}
+
 func validatePodSet(ps *kueue.PodSet, path *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -137,6 +215,50 @@ func validatePodSet(ps *kueue.PodSet, path *field.Path) field.ErrorList {
 		allErrs = append(allErrs, validateContainer(&ps.Template.Spec.Containers[ci], cPath.Index(ci))...)
 	}
 
+	if v, found := ps.Template.Annotations[kueue.PodSetPlacementOrderAnnotation]; found {
+		if _, valid := workload.PlacementOrder(ps); !valid {
+			allErrs = append(allErrs, field.Invalid(path.Child("template", "metadata", "annotations").Key(kueue.PodSetPlacementOrderAnnotation), v, "must be a non-negative 32-bit integer"))
+		}
+	}
+
+	if v, found := ps.Template.Annotations[kueue.PodSetOptionalAnnotation]; found && v != "true" && v != "false" {
+		allErrs = append(allErrs, field.NotSupported(path.Child("template", "metadata", "annotations").Key(kueue.PodSetOptionalAnnotation), v, []string{"true", "false"}))
+	}
+
+	if v, found := ps.Template.Annotations[kueue.PodSetPreferredNodeSelectorAnnotation]; found {
+		if _, err := workload.PreferredNodeSelector(ps); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("template", "metadata", "annotations").Key(kueue.PodSetPreferredNodeSelectorAnnotation), v, err.Error()))
+		}
+	}
+
+	if v, found := ps.Template.Annotations[kueue.PodSetRequiredTopologyAnnotation]; found {
+		if _, err := workload.RequiredTopology(ps); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("template", "metadata", "annotations").Key(kueue.PodSetRequiredTopologyAnnotation), v, err.Error()))
+		}
+	}
+
+	if v, found := ps.Template.Annotations[kueue.PodSetResourceClaimClassesAnnotation]; found {
+		allErrs = append(allErrs, validateResourceClaimClasses(ps, v, path.Child("template", "metadata", "annotations").Key(kueue.PodSetResourceClaimClassesAnnotation))...)
+	}
+
+	return allErrs
+}
+
+func validateResourceClaimClasses(ps *kueue.PodSet, value string, path *field.Path) field.ErrorList {
+	classes, err := workload.ResourceClaimClasses(ps)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+	var allErrs field.ErrorList
+	claims := sets.New(slices.Map(ps.Template.Spec.ResourceClaims, func(c *corev1.PodResourceClaim) string {
+		return c.Name
+	})...)
+	for _, claim := range sets.List(sets.KeySet(classes)) {
+		if !claims.Has(claim) {
+			allErrs = append(allErrs, field.Invalid(path, value, fmt.Sprintf("claim %q is not in the pod resource claims", claim)))
+		}
+		allErrs = append(allErrs, validateResourceName(corev1.ResourceName(classes[claim]), path)...)
+	}
 	return allErrs
 }
 
@@ -284,11 +406,22 @@ func ValidateWorkloadUpdate(newObj, oldObj *kueue.Workload) field.ErrorList {
 }
 
 // validateAdmissionUpdate validates that admission can be set or unset, but the
-// fields within can't change.
+// fields within can't change, except for the counts of the podSets, that can be
+// reduced when the workload is scaled down.
 func validateAdmissionUpdate(new, old *kueue.Admission, path *field.Path) field.ErrorList {
 	if old == nil || new == nil {
 		return nil
 	}
+	if features.Enabled(features.PartialAdmissionScaleDown) && len(new.PodSetAssignments) == len(old.PodSetAssignments) {
+		old = old.DeepCopy()
+		for i := range new.PodSetAssignments {
+			newPsa, oldPsa := &new.PodSetAssignments[i], &old.PodSetAssignments[i]
+			if newPsa.Name == oldPsa.Name && newPsa.Count != nil && oldPsa.Count != nil && *newPsa.Count < *oldPsa.Count {
+				oldPsa.Count = newPsa.Count
+				oldPsa.ResourceUsage = newPsa.ResourceUsage
+			}
+		}
+	}
 	return apivalidation.ValidateImmutableField(new, old, path)
 }
 
@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +30,8 @@ import (
 	"k8s.io/utils/ptr"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/controller/constants"
+	"sigs.k8s.io/kueue/pkg/features"
 	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
 )
 
@@ -60,6 +63,14 @@ func TestValidateWorkload(t *testing.T) {
 				},
 			).Obj(),
 		},
+		"should have a positive activeDeadlineSeconds": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				ActiveDeadlineSeconds(0).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("activeDeadlineSeconds"), nil, ""),
+			},
+		},
 		"should have a valid podSet name in status assignment": {
 			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
 				ReserveQuota(testingutil.MakeAdmission("cluster-queue", "@invalid").Obj()).
@@ -116,6 +127,116 @@ func TestValidateWorkload(t *testing.T) {
 				field.Invalid(firstPodSetSpecPath.Child("containers").Index(0).Child("resources", "requests").Key(string(corev1.ResourcePods)), nil, ""),
 			},
 		},
+		"valid podSet placement order": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("launcher", 1).
+					Annotations(map[string]string{kueue.PodSetPlacementOrderAnnotation: "0"}).
+					Obj(),
+				*testingutil.MakePodSet("workers", 10).Obj(),
+			).Obj(),
+		},
+		"invalid podSet placement order": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("launcher", 1).
+					Annotations(map[string]string{kueue.PodSetPlacementOrderAnnotation: "first"}).
+					Obj(),
+				*testingutil.MakePodSet("workers", 10).
+					Annotations(map[string]string{kueue.PodSetPlacementOrderAnnotation: "-1"}).
+					Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsPath.Index(0).Child("template", "metadata", "annotations").Key(kueue.PodSetPlacementOrderAnnotation), nil, ""),
+				field.Invalid(podSetsPath.Index(1).Child("template", "metadata", "annotations").Key(kueue.PodSetPlacementOrderAnnotation), nil, ""),
+			},
+		},
+		"valid optional podSet": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("driver", 1).Obj(),
+				*testingutil.MakePodSet("workers", 10).
+					Annotations(map[string]string{kueue.PodSetOptionalAnnotation: "true"}).
+					Obj(),
+			).Obj(),
+		},
+		"invalid optional podSet annotation": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("driver", 1).Obj(),
+				*testingutil.MakePodSet("workers", 10).
+					Annotations(map[string]string{kueue.PodSetOptionalAnnotation: "yes"}).
+					Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.NotSupported[string](podSetsPath.Index(1).Child("template", "metadata", "annotations").Key(kueue.PodSetOptionalAnnotation), nil, nil),
+			},
+		},
+		"all podSets optional": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("driver", 1).
+					Annotations(map[string]string{kueue.PodSetOptionalAnnotation: "true"}).
+					Obj(),
+				*testingutil.MakePodSet("workers", 10).
+					Annotations(map[string]string{kueue.PodSetOptionalAnnotation: "true"}).
+					Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsPath, nil, ""),
+			},
+		},
+		"valid preferred node selector": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("main", 1).
+					Annotations(map[string]string{kueue.PodSetPreferredNodeSelectorAnnotation: "example.com/image-cache=warm"}).
+					Obj(),
+			).Obj(),
+		},
+		"invalid preferred node selector": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("main", 1).
+					Annotations(map[string]string{kueue.PodSetPreferredNodeSelectorAnnotation: "example.com/image-cache==="}).
+					Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsPath.Index(0).Child("template", "metadata", "annotations").Key(kueue.PodSetPreferredNodeSelectorAnnotation), nil, ""),
+			},
+		},
+		"valid required topology": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("main", 1).
+					Annotations(map[string]string{kueue.PodSetRequiredTopologyAnnotation: "nvidia.com/gpu.clique"}).
+					Obj(),
+			).Obj(),
+		},
+		"invalid required topology": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("main", 1).
+					Annotations(map[string]string{kueue.PodSetRequiredTopologyAnnotation: "nvidia.com/gpu.clique,"}).
+					Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsPath.Index(0).Child("template", "metadata", "annotations").Key(kueue.PodSetRequiredTopologyAnnotation), nil, ""),
+			},
+		},
+		"valid resource claim classes": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("main", 1).
+					ResourceClaim("gpu", "gpu.example.com").
+					ResourceClaim("nic", "nic.example.com").
+					Obj(),
+			).Obj(),
+		},
+		"invalid resource claim classes": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets(
+				*testingutil.MakePodSet("malformed", 1).
+					Annotations(map[string]string{kueue.PodSetResourceClaimClassesAnnotation: "gpu"}).
+					Obj(),
+				*testingutil.MakePodSet("unknown-claim", 1).
+					Annotations(map[string]string{kueue.PodSetResourceClaimClassesAnnotation: "gpu=gpu.example.com"}).
+					Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsPath.Index(0).Child("template", "metadata", "annotations").Key(kueue.PodSetResourceClaimClassesAnnotation), nil, ""),
+				field.Invalid(podSetsPath.Index(1).Child("template", "metadata", "annotations").Key(kueue.PodSetResourceClaimClassesAnnotation), nil, ""),
+			},
+		},
 		"empty podSetUpdates": {
 			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).AdmissionChecks(kueue.AdmissionCheckState{}).Obj(),
 			wantErr:  nil,
@@ -248,10 +369,117 @@ func TestValidateWorkload(t *testing.T) {
 	}
 }
 
+func TestValidateWorkloadCreate(t *testing.T) {
+	podSetsPath := field.NewPath("spec", "podSets")
+	testCases := map[string]struct {
+		workload              *kueue.Workload
+		allowZeroCountPodSets bool
+		wantErr               field.ErrorList
+	}{
+		"podSets with count 0 are rejected when configured": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(
+					*testingutil.MakePodSet("driver", 1).Obj(),
+					*testingutil.MakePodSet("workers", 0).Obj(),
+				).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsPath.Index(1).Child("count"), int32(0), "must be greater than 0"),
+			},
+		},
+		"podSets with count 0 are allowed": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(
+					*testingutil.MakePodSet("driver", 1).Obj(),
+					*testingutil.MakePodSet("workers", 0).Obj(),
+				).
+				Obj(),
+			allowZeroCountPodSets: true,
+		},
+		"podSets with positive counts": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(
+					*testingutil.MakePodSet("driver", 1).Obj(),
+					*testingutil.MakePodSet("workers", 3).Obj(),
+				).
+				Obj(),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := testingutil.ContextWithLog(t)
+			wh := &WorkloadWebhook{allowZeroCountPodSets: tc.allowZeroCountPodSets}
+			_, gotErr := wh.ValidateCreate(ctx, tc.workload)
+			if diff := cmp.Diff(tc.wantErr.ToAggregate(), gotErr); diff != "" {
+				t.Errorf("ValidateCreate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateWorkloadCreateDuplicates(t *testing.T) {
+	jobGVK := batchv1.SchemeGroupVersion.WithKind("Job")
+	hashPath := field.NewPath("metadata", "labels").Key(constants.PodSetsHashLabel)
+	baseWorkload := testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+		OwnerReference(jobGVK, "job", "job-uid").
+		Request(corev1.ResourceCPU, "1")
+	testCases := map[string]struct {
+		existing *kueue.Workload
+		workload *kueue.Workload
+		wantErr  field.ErrorList
+	}{
+		"rejects a workload with the same owner and podSets": {
+			existing: baseWorkload.Clone().Name("existing").Obj(),
+			workload: baseWorkload.Clone().Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(hashPath, "the Workload existing has the same owner job and podSets"),
+			},
+		},
+		"allows a workload with the same owner and different podSets": {
+			existing: baseWorkload.Clone().Name("existing").Obj(),
+			workload: baseWorkload.Clone().Request(corev1.ResourceCPU, "2").Obj(),
+		},
+		"allows a workload with another owner and the same podSets": {
+			existing: baseWorkload.Clone().Name("existing").Obj(),
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				OwnerReference(jobGVK, "other-job", "other-job-uid").
+				Request(corev1.ResourceCPU, "1").
+				Obj(),
+		},
+		"allows a workload when the existing one is being deleted": {
+			existing: baseWorkload.Clone().
+				Name("existing").
+				Finalizers(kueue.ResourceInUseFinalizerName).
+				DeletionTimestamp(time.Now()).
+				Obj(),
+			workload: baseWorkload.Clone().Obj(),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			defer features.SetFeatureGateDuringTest(t, features.RejectDuplicateWorkloads, true)()
+			ctx, _ := testingutil.ContextWithLog(t)
+			wh := &WorkloadWebhook{}
+			if err := wh.Default(ctx, tc.existing); err != nil {
+				t.Fatalf("Failed to default the existing workload: %v", err)
+			}
+			wh.client = testingutil.NewClientBuilder().WithObjects(tc.existing).Build()
+			if err := wh.Default(ctx, tc.workload); err != nil {
+				t.Fatalf("Failed to default the workload: %v", err)
+			}
+			_, gotErr := wh.ValidateCreate(ctx, tc.workload)
+			if diff := cmp.Diff(tc.wantErr.ToAggregate(), gotErr); diff != "" {
+				t.Errorf("ValidateCreate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestValidateWorkloadUpdate(t *testing.T) {
 	testCases := map[string]struct {
-		before, after *kueue.Workload
-		wantErr       field.ErrorList
+		before, after   *kueue.Workload
+		enableScaleDown bool
+		wantErr         field.ErrorList
 	}{
 		"reclaimable pod count can change up": {
 			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
@@ -392,9 +620,48 @@ func TestValidateWorkloadUpdate(t *testing.T) {
 				State:              kueue.CheckStateReady,
 			}).Obj(),
 		},
+		"admission count can be reduced when scale down is enabled": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(*testingutil.MakePodSet("main", 4).SetMinimumCount(2).Obj()).
+				ReserveQuota(testingutil.MakeAdmission("cluster-queue").Assignment(corev1.ResourceCPU, "default", "4").AssignmentPodCount(4).Obj()).
+				Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(*testingutil.MakePodSet("main", 4).SetMinimumCount(2).Obj()).
+				ReserveQuota(testingutil.MakeAdmission("cluster-queue").Assignment(corev1.ResourceCPU, "default", "2").AssignmentPodCount(2).Obj()).
+				Obj(),
+			enableScaleDown: true,
+		},
+		"admission count cannot be reduced when scale down is disabled": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(*testingutil.MakePodSet("main", 4).SetMinimumCount(2).Obj()).
+				ReserveQuota(testingutil.MakeAdmission("cluster-queue").Assignment(corev1.ResourceCPU, "default", "4").AssignmentPodCount(4).Obj()).
+				Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(*testingutil.MakePodSet("main", 4).SetMinimumCount(2).Obj()).
+				ReserveQuota(testingutil.MakeAdmission("cluster-queue").Assignment(corev1.ResourceCPU, "default", "2").AssignmentPodCount(2).Obj()).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("status", "admission"), nil, ""),
+			},
+		},
+		"admission count cannot be increased": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(*testingutil.MakePodSet("main", 4).SetMinimumCount(2).Obj()).
+				ReserveQuota(testingutil.MakeAdmission("cluster-queue").Assignment(corev1.ResourceCPU, "default", "2").AssignmentPodCount(2).Obj()).
+				Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSets(*testingutil.MakePodSet("main", 4).SetMinimumCount(2).Obj()).
+				ReserveQuota(testingutil.MakeAdmission("cluster-queue").Assignment(corev1.ResourceCPU, "default", "4").AssignmentPodCount(4).Obj()).
+				Obj(),
+			enableScaleDown: true,
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("status", "admission"), nil, ""),
+			},
+		},
 	}
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
+			defer features.SetFeatureGateDuringTest(t, features.PartialAdmissionScaleDown, tc.enableScaleDown)()
 			errList := ValidateWorkloadUpdate(tc.after, tc.before)
 			if diff := cmp.Diff(tc.wantErr, errList, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
 				t.Errorf("ValidateWorkloadUpdate() mismatch (-want +got):\n%s", diff)
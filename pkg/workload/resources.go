@@ -18,17 +18,22 @@ package workload
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	nodev1 "k8s.io/api/node/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
 	"sigs.k8s.io/kueue/pkg/controller/core/indexer"
 	"sigs.k8s.io/kueue/pkg/util/limitrange"
+	utillocalqueue "sigs.k8s.io/kueue/pkg/util/localqueue"
 	"sigs.k8s.io/kueue/pkg/util/resource"
 )
 
@@ -105,10 +110,139 @@ func UseLimitsAsMissingRequestsInPod(pod *corev1.PodSpec) {
 	}
 }
 
+// handleResourceTransforms applies the resourceTransforms of the ClusterQueues
+// that can admit the workload to the requests of its containers. That's the
+// ClusterQueue of the admission, or the candidate ClusterQueues of the
+// LocalQueue while the workload is pending. As the workload could be admitted
+// by any of the candidates, each request is the largest result of their
+// transforms.
+func handleResourceTransforms(ctx context.Context, cl client.Client, wl *kueue.Workload) error {
+	var cqNames []string
+	if wl.Status.Admission != nil {
+		cqNames = []string{string(wl.Status.Admission.ClusterQueue)}
+	} else if wl.Spec.QueueName != "" {
+		var lq kueue.LocalQueue
+		if err := cl.Get(ctx, types.NamespacedName{Namespace: wl.Namespace, Name: wl.Spec.QueueName}, &lq); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		cqNames = utillocalqueue.ClusterQueueNames(&lq)
+	}
+	var allTransforms [][]kueue.ResourceTransform
+	for _, cqName := range cqNames {
+		var cq kueue.ClusterQueue
+		if err := cl.Get(ctx, types.NamespacedName{Name: cqName}, &cq); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if len(cq.Spec.ResourceTransforms) > 0 {
+			allTransforms = append(allTransforms, cq.Spec.ResourceTransforms)
+		}
+	}
+	if len(allTransforms) == 0 {
+		return nil
+	}
+	for pi := range wl.Spec.PodSets {
+		pod := &wl.Spec.PodSets[pi].Template.Spec
+		orig := pod.DeepCopy()
+		transformPod(pod, allTransforms[0])
+		for _, transforms := range allTransforms[1:] {
+			other := orig.DeepCopy()
+			transformPod(other, transforms)
+			keepLargestRequests(pod, other)
+		}
+	}
+	return nil
+}
+
+func transformPod(pod *corev1.PodSpec, transforms []kueue.ResourceTransform) {
+	for ci := range pod.InitContainers {
+		transformRequests(&pod.InitContainers[ci].Resources, transforms)
+	}
+	for ci := range pod.Containers {
+		transformRequests(&pod.Containers[ci].Resources, transforms)
+	}
+	applyPodDefaults(pod, transforms)
+}
+
+// keepLargestRequests raises the requests of the containers of pod to the
+// requests of the same containers in other, which only differs in the
+// requests.
+func keepLargestRequests(pod, other *corev1.PodSpec) {
+	keep := func(dst, src []corev1.Container) {
+		for ci := range dst {
+			res := &dst[ci].Resources
+			for name, q := range src[ci].Resources.Requests {
+				if current, found := res.Requests[name]; found && current.Cmp(q) >= 0 {
+					continue
+				}
+				if res.Requests == nil {
+					res.Requests = make(corev1.ResourceList)
+				}
+				res.Requests[name] = q
+			}
+		}
+	}
+	keep(pod.InitContainers, other.InitContainers)
+	keep(pod.Containers, other.Containers)
+}
+
+// applyPodDefaults sets the podDefault of the transforms as the request of
+// the first container of the pod, for the resources that none of the
+// containers of the pod request.
+func applyPodDefaults(pod *corev1.PodSpec, transforms []kueue.ResourceTransform) {
+	if len(pod.Containers) == 0 {
+		return
+	}
+	for _, t := range transforms {
+		if t.PodDefault == nil || podRequestsResource(pod, t.Name) {
+			continue
+		}
+		res := &pod.Containers[0].Resources
+		if res.Requests == nil {
+			res.Requests = make(corev1.ResourceList)
+		}
+		res.Requests[t.Name] = t.PodDefault.DeepCopy()
+	}
+}
+
+func podRequestsResource(pod *corev1.PodSpec, name corev1.ResourceName) bool {
+	for _, containers := range [][]corev1.Container{pod.InitContainers, pod.Containers} {
+		for ci := range containers {
+			if q, found := containers[ci].Resources.Requests[name]; found && !q.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// transformRequests applies the transforms to the requests of a container.
+// The containers not requesting a resource are left without request for it.
+func transformRequests(res *corev1.ResourceRequirements, transforms []kueue.ResourceTransform) {
+	for _, t := range transforms {
+		q, found := res.Requests[t.Name]
+		if !found {
+			continue
+		}
+		if t.Multiplier != nil {
+			// Multiply in thousandths to round up without float errors.
+			v := (ResourceValue(t.Name, q)*t.Multiplier.MilliValue() + 999) / 1000
+			q = ResourceQuantity(t.Name, v)
+		}
+		if t.Minimum != nil && q.Cmp(*t.Minimum) < 0 {
+			q = t.Minimum.DeepCopy()
+		}
+		res.Requests[t.Name] = q
+	}
+}
+
 // AdjustResources adjusts the resource requests of a workload based on:
 // - PodOverhead
 // - LimitRanges
 // - Limits
+// - ResourceTransforms of the ClusterQueue
 func AdjustResources(ctx context.Context, cl client.Client, wl *kueue.Workload) {
 	log := ctrl.LoggerFrom(ctx)
 	for _, err := range handlePodOverhead(ctx, cl, wl) {
@@ -118,4 +252,42 @@ func AdjustResources(ctx context.Context, cl client.Client, wl *kueue.Workload)
 		log.Error(err, "Failed adjusting requests for LimitRanges")
 	}
 	handleLimitsToRequests(wl)
+	if err := handleResourceTransforms(ctx, cl, wl); err != nil {
+		log.Error(err, "Failed adjusting requests for the resource transforms")
+	}
+}
+
+// ErrExceedsLimitRange is returned when the resources of the pods of a workload
+// exceed the maximum of the LimitRanges in its namespace.
+var ErrExceedsLimitRange = errors.New("exceeds the LimitRange maximum")
+
+// ValidateLimitRange validates that the resources of the pods of the workload
+// fit into the LimitRanges of its namespace. It returns an
+// ErrExceedsLimitRange error if any of them exceeds the maximum of the
+// LimitRanges, as the API server would reject such pods.
+func ValidateLimitRange(ctx context.Context, cl client.Client, wl *kueue.Workload) error {
+	var list corev1.LimitRangeList
+	if err := cl.List(ctx, &list, &client.ListOptions{Namespace: wl.Namespace}); err != nil {
+		return err
+	}
+	if len(list.Items) == 0 {
+		return nil
+	}
+	summary := limitrange.Summarize(list.Items...)
+	podSetsPath := field.NewPath("podSets")
+	var reasons []string
+	var exceedsMax bool
+	for i := range wl.Spec.PodSets {
+		ps := &wl.Spec.PodSets[i]
+		psReasons, psExceedsMax := summary.ValidatePodSpec(&ps.Template.Spec, podSetsPath.Child(ps.Name))
+		reasons = append(reasons, psReasons...)
+		exceedsMax = exceedsMax || psExceedsMax
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	if exceedsMax {
+		return fmt.Errorf("%w: %s", ErrExceedsLimitRange, strings.Join(reasons, "; "))
+	}
+	return fmt.Errorf("didn't satisfy LimitRange constraints: %s", strings.Join(reasons, "; "))
 }
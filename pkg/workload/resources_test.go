@@ -30,8 +30,13 @@ func TestAdjustResources(t *testing.T) {
 	cases := map[string]struct {
 		runtimeClasses []nodev1.RuntimeClass
 		limitranges    []corev1.LimitRange
+		clusterQueues  []kueue.ClusterQueue
+		localQueues    []kueue.LocalQueue
 		wl             *kueue.Workload
 		wantWl         *kueue.Workload
+		// wantTotalRequests, when set, are the requests of the podSets
+		// after the adjustment.
+		wantTotalRequests []PodSetResources
 	}{
 		"Handle runtimeClass with podOverHead": {
 			runtimeClasses: []nodev1.RuntimeClass{
@@ -166,6 +171,85 @@ func TestAdjustResources(t *testing.T) {
 				).
 				Obj(),
 		},
+		"Handle runtimeClasses of heterogeneous podSets with container limit range": {
+			runtimeClasses: []nodev1.RuntimeClass{
+				utiltesting.MakeRuntimeClass("runtime-a", "handler-a").
+					PodOverhead(corev1.ResourceList{
+						corev1.ResourceCPU: ResourceQuantity(corev1.ResourceCPU, 1),
+					}).
+					RuntimeClass,
+				utiltesting.MakeRuntimeClass("runtime-b", "handler-b").
+					PodOverhead(corev1.ResourceList{
+						corev1.ResourceCPU:    ResourceQuantity(corev1.ResourceCPU, 2),
+						corev1.ResourceMemory: ResourceQuantity(corev1.ResourceMemory, 1024),
+					}).
+					RuntimeClass,
+			},
+			limitranges: []corev1.LimitRange{
+				utiltesting.MakeLimitRange("foo", "").
+					WithType(corev1.LimitTypeContainer).
+					WithValue(
+						"Default", corev1.ResourceCPU, "4",
+					).
+					WithValue(
+						"DefaultRequest", corev1.ResourceCPU, "3",
+					).
+					LimitRange,
+			},
+			wl: utiltesting.MakeWorkload("foo", "").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						RuntimeClass("runtime-a").
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						RuntimeClass("runtime-b").
+						Obj(),
+					*utiltesting.MakePodSet("c", 1).
+						Obj(),
+					*utiltesting.MakePodSet("d", 1).
+						RuntimeClass("runtime-d").
+						Obj(),
+				).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						RuntimeClass("runtime-a").
+						PodOverHead(
+							corev1.ResourceList{
+								corev1.ResourceCPU: ResourceQuantity(corev1.ResourceCPU, 1),
+							}).
+						Limit(corev1.ResourceCPU, "4").
+						Request(corev1.ResourceCPU, "3").
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						RuntimeClass("runtime-b").
+						PodOverHead(
+							corev1.ResourceList{
+								corev1.ResourceCPU:    ResourceQuantity(corev1.ResourceCPU, 2),
+								corev1.ResourceMemory: ResourceQuantity(corev1.ResourceMemory, 1024),
+							}).
+						Limit(corev1.ResourceCPU, "4").
+						Request(corev1.ResourceCPU, "3").
+						Obj(),
+					*utiltesting.MakePodSet("c", 1).
+						Limit(corev1.ResourceCPU, "4").
+						Request(corev1.ResourceCPU, "3").
+						Obj(),
+					*utiltesting.MakePodSet("d", 1).
+						RuntimeClass("runtime-d").
+						Limit(corev1.ResourceCPU, "4").
+						Request(corev1.ResourceCPU, "3").
+						Obj(),
+				).
+				Obj(),
+			wantTotalRequests: []PodSetResources{
+				{Name: "a", Count: 1, Requests: Requests{corev1.ResourceCPU: 3_001}},
+				{Name: "b", Count: 1, Requests: Requests{corev1.ResourceCPU: 3_002, corev1.ResourceMemory: 1024}},
+				{Name: "c", Count: 1, Requests: Requests{corev1.ResourceCPU: 3_000}},
+				{Name: "d", Count: 1, Requests: Requests{corev1.ResourceCPU: 3_000}},
+			},
+		},
 		"Handle container limit range": {
 			limitranges: []corev1.LimitRange{
 				utiltesting.MakeLimitRange("foo", "").
@@ -285,6 +369,37 @@ func TestAdjustResources(t *testing.T) {
 				).
 				Obj(),
 		},
+		"Handle container and pod limits in the same limit range": {
+			limitranges: []corev1.LimitRange{
+				utiltesting.MakeLimitRange("foo", "").
+					Default(corev1.ResourceCPU, "4").
+					DefaultRequest(corev1.ResourceCPU, "3").
+					Limit(corev1.LimitTypePod).
+					Max(corev1.ResourceCPU, "10").
+					LimitRange,
+			},
+			wl: utiltesting.MakeWorkload("foo", "").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						Request(corev1.ResourceCPU, "1").
+						Obj(),
+				).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Limit(corev1.ResourceCPU, "4").
+						Request(corev1.ResourceCPU, "3").
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						Limit(corev1.ResourceCPU, "4").
+						Request(corev1.ResourceCPU, "1").
+						Obj(),
+				).
+				Obj(),
+		},
 		"Handle empty container limit range": {
 			limitranges: []corev1.LimitRange{
 				utiltesting.MakeLimitRange("foo", "").
@@ -464,12 +579,266 @@ func TestAdjustResources(t *testing.T) {
 				).
 				Obj(),
 		},
+		"Apply a memory multiplier from the ClusterQueue": {
+			clusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("cq").
+					ResourceTransform(corev1.ResourceMemory, "1.1", "").
+					Obj(),
+			},
+			localQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("q", "ns").ClusterQueue("cq").Obj(),
+			},
+			wl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceCPU, "1").
+						Request(corev1.ResourceMemory, "1000Mi").
+						Obj(),
+				).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceCPU, "1").
+						Request(corev1.ResourceMemory, "1100Mi").
+						Obj(),
+				).
+				Obj(),
+		},
+		"Apply a cpu floor from the ClusterQueue": {
+			clusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("cq").
+					ResourceTransform(corev1.ResourceCPU, "", "100m").
+					Obj(),
+			},
+			localQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("q", "ns").ClusterQueue("cq").Obj(),
+			},
+			wl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceCPU, "50m").
+						InitContainers(corev1.Container{}).
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						Request(corev1.ResourceCPU, "1").
+						Obj(),
+				).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceCPU, "100m").
+						InitContainers(corev1.Container{}).
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						Request(corev1.ResourceCPU, "1").
+						Obj(),
+				).
+				Obj(),
+		},
+		"Apply the largest transforms of the candidate ClusterQueues": {
+			clusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("cq-a").
+					ResourceTransform(corev1.ResourceMemory, "1.1", "").
+					Obj(),
+				*utiltesting.MakeClusterQueue("cq-b").
+					ResourceTransform(corev1.ResourceCPU, "", "100m").
+					ResourceTransform(corev1.ResourceMemory, "1.05", "").
+					Obj(),
+			},
+			localQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("q", "ns").
+					ClusterQueue("cq-a").
+					ClusterQueueSelection(kueue.ClusterQueueSelectionFirstWithCapacity, "cq-b").
+					Obj(),
+			},
+			wl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceCPU, "50m").
+						Request(corev1.ResourceMemory, "1000Mi").
+						Obj(),
+				).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceCPU, "100m").
+						Request(corev1.ResourceMemory, "1100Mi").
+						Obj(),
+				).
+				Obj(),
+		},
+		"Apply the transforms of the ClusterQueue of the admission": {
+			clusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("cq-a").
+					ResourceTransform(corev1.ResourceMemory, "1.1", "").
+					Obj(),
+				*utiltesting.MakeClusterQueue("cq-b").
+					ResourceTransform(corev1.ResourceMemory, "1.05", "").
+					Obj(),
+			},
+			localQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("q", "ns").
+					ClusterQueue("cq-a").
+					ClusterQueueSelection(kueue.ClusterQueueSelectionFirstWithCapacity, "cq-b").
+					Obj(),
+			},
+			wl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceMemory, "1000Mi").
+						Obj(),
+				).
+				Admission(utiltesting.MakeAdmission("cq-b").Obj()).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceMemory, "1050Mi").
+						Obj(),
+				).
+				Admission(utiltesting.MakeAdmission("cq-b").Obj()).
+				Obj(),
+		},
+		"Apply a cpu default to the pods not requesting cpu": {
+			clusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("cq").
+					ResourceTransformPodDefault(corev1.ResourceCPU, "500m").
+					Obj(),
+			},
+			localQueues: []kueue.LocalQueue{
+				*utiltesting.MakeLocalQueue("q", "ns").ClusterQueue("cq").Obj(),
+			},
+			wl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Containers(
+							*utiltesting.MakeContainer().Obj(),
+							*utiltesting.MakeContainer().Obj(),
+						).
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						Containers(
+							*utiltesting.MakeContainer().Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "100m").Obj(),
+						).
+						Obj(),
+				).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Containers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "500m").Obj(),
+							*utiltesting.MakeContainer().Obj(),
+						).
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						Containers(
+							*utiltesting.MakeContainer().Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "100m").Obj(),
+						).
+						Obj(),
+				).
+				Obj(),
+		},
+		"Ignore the resource transforms when the queue doesn't exist": {
+			clusterQueues: []kueue.ClusterQueue{
+				*utiltesting.MakeClusterQueue("cq").
+					ResourceTransform(corev1.ResourceCPU, "", "100m").
+					Obj(),
+			},
+			wl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceCPU, "50m").
+						Obj(),
+				).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "ns").
+				Queue("q").
+				PodSets(
+					*utiltesting.MakePodSet("a", 1).
+						Request(corev1.ResourceCPU, "50m").
+						Obj(),
+				).
+				Obj(),
+		},
+		"Init containers requesting more than the containers": {
+			wl: utiltesting.MakeWorkload("foo", "").
+				PodSets(
+					*utiltesting.MakePodSet("a", 2).
+						Containers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "1").Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "2").Obj(),
+						).
+						InitContainers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "5").Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "4").Obj(),
+						).
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						Containers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "1").Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "2").Obj(),
+						).
+						InitContainers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "1").AsSidecar().Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "3").Obj(),
+						).
+						Obj(),
+				).
+				Obj(),
+			wantWl: utiltesting.MakeWorkload("foo", "").
+				PodSets(
+					*utiltesting.MakePodSet("a", 2).
+						Containers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "1").Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "2").Obj(),
+						).
+						InitContainers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "5").Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "4").Obj(),
+						).
+						Obj(),
+					*utiltesting.MakePodSet("b", 1).
+						Containers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "1").Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "2").Obj(),
+						).
+						InitContainers(
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "1").AsSidecar().Obj(),
+							*utiltesting.MakeContainer().WithResourceReq(corev1.ResourceCPU, "3").Obj(),
+						).
+						Obj(),
+				).
+				Obj(),
+			wantTotalRequests: []PodSetResources{
+				{Name: "a", Count: 2, Requests: Requests{corev1.ResourceCPU: 10_000}},
+				{Name: "b", Count: 1, Requests: Requests{corev1.ResourceCPU: 4_000}},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			cl := utiltesting.NewClientBuilder().WithLists(
 				&nodev1.RuntimeClassList{Items: tc.runtimeClasses},
 				&corev1.LimitRangeList{Items: tc.limitranges},
+				&kueue.ClusterQueueList{Items: tc.clusterQueues},
+				&kueue.LocalQueueList{Items: tc.localQueues},
 			).WithIndex(&corev1.LimitRange{}, indexer.LimitRangeHasContainerType, indexer.IndexLimitRangeHasContainerType).
 				Build()
 			ctx, _ := utiltesting.ContextWithLog(t)
@@ -477,6 +846,11 @@ func TestAdjustResources(t *testing.T) {
 			if diff := cmp.Diff(tc.wl, tc.wantWl); diff != "" {
 				t.Errorf("Unexpected resources after adjusting (-want,+got): %s", diff)
 			}
+			if tc.wantTotalRequests != nil {
+				if diff := cmp.Diff(tc.wantTotalRequests, NewInfo(tc.wl).TotalRequests); diff != "" {
+					t.Errorf("Unexpected total requests after adjusting (-want,+got): %s", diff)
+				}
+			}
 		})
 	}
 }
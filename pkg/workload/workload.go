@@ -18,8 +18,12 @@ package workload
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,7 +32,9 @@ import (
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
@@ -149,6 +155,10 @@ type Info struct {
 	// already admitted.
 	ClusterQueue   string
 	LastAssignment *AssignmentClusterQueueState
+	// ScaledDownCounts is set on the preemption targets that are scaled down
+	// instead of evicted, with the new counts of the podSets that are scaled
+	// down. Obj holds the scaled down admission.
+	ScaledDownCounts map[string]int32
 }
 
 type PodSetResources struct {
@@ -199,6 +209,15 @@ func (i *Info) Update(wl *kueue.Workload) {
 	i.Obj = wl
 }
 
+// PodCount returns the total number of pods of the workload.
+func (i *Info) PodCount() int32 {
+	var count int32
+	for _, ps := range i.TotalRequests {
+		count += ps.Count
+	}
+	return count
+}
+
 func (i *Info) CanBePartiallyAdmitted() bool {
 	return CanBePartiallyAdmitted(i.Obj)
 }
@@ -252,6 +271,148 @@ func CanBePartiallyAdmitted(wl *kueue.Workload) bool {
 	return false
 }
 
+// ScaleDownAdmission reduces the counts of the podSet assignments of the
+// workload admission to the given counts, scaling down the resource usage
+// accordingly. The podSets not in counts keep their assignments.
+func ScaleDownAdmission(wl *kueue.Workload, counts map[string]int32) {
+	if wl.Status.Admission == nil || len(counts) == 0 {
+		return
+	}
+	totalCounts := podSetsCounts(wl)
+	for i := range wl.Status.Admission.PodSetAssignments {
+		psa := &wl.Status.Admission.PodSetAssignments[i]
+		newCount, found := counts[psa.Name]
+		if !found {
+			continue
+		}
+		usage := newRequests(psa.ResourceUsage)
+		usage.scaleDown(int64(ptr.Deref(psa.Count, totalCounts[psa.Name])))
+		usage.scaleUp(int64(newCount))
+		psa.ResourceUsage = usage.ToResourceList()
+		psa.Count = ptr.To(newCount)
+	}
+}
+
+// ScaleDownCounts returns the minCounts of the podSets of the workload
+// admission that have more pods than their minCount.
+func ScaleDownCounts(wl *kueue.Workload) map[string]int32 {
+	if wl.Status.Admission == nil {
+		return nil
+	}
+	minCounts := make(map[string]int32, len(wl.Spec.PodSets))
+	for _, ps := range wl.Spec.PodSets {
+		if ps.MinCount != nil {
+			minCounts[ps.Name] = *ps.MinCount
+		}
+	}
+	totalCounts := podSetsCounts(wl)
+	var ret map[string]int32
+	for _, psa := range wl.Status.Admission.PodSetAssignments {
+		minCount, found := minCounts[psa.Name]
+		if !found || ptr.Deref(psa.Count, totalCounts[psa.Name]) <= minCount {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[string]int32)
+		}
+		ret[psa.Name] = minCount
+	}
+	return ret
+}
+
+// PodSetsHash returns a short hash of the podSets of the workload, which is
+// the same for workloads with the same podSets.
+func PodSetsHash(wl *kueue.Workload) (string, error) {
+	data, err := json.Marshal(wl.Spec.PodSets)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16], nil
+}
+
+// IsOptional returns whether the pod set is declared as optional through
+// the kueue.x-k8s.io/podset-optional annotation.
+func IsOptional(ps *kueue.PodSet) bool {
+	return ps.Template.Annotations[kueue.PodSetOptionalAnnotation] == "true"
+}
+
+// OptionalPodSetIndexes returns the indexes of the optional pod sets of the
+// workload, in their original order.
+func OptionalPodSetIndexes(wl *kueue.Workload) []int {
+	var indexes []int
+	for i := range wl.Spec.PodSets {
+		if IsOptional(&wl.Spec.PodSets[i]) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// PlacementOrder returns the placement order hint declared for the pod set
+// through the kueue.x-k8s.io/podset-placement-order annotation. The second
+// value is false if the hint is absent or malformed.
+func PlacementOrder(ps *kueue.PodSet) (int32, bool) {
+	v, found := ps.Template.Annotations[kueue.PodSetPlacementOrderAnnotation]
+	if !found {
+		return 0, false
+	}
+	order, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || order < 0 {
+		return 0, false
+	}
+	return int32(order), true
+}
+
+// PreferredNodeSelector returns the selector declared for the pod set through
+// the kueue.x-k8s.io/preferred-node-selector annotation, or nil if absent.
+func PreferredNodeSelector(ps *kueue.PodSet) (labels.Selector, error) {
+	v, found := ps.Template.Annotations[kueue.PodSetPreferredNodeSelectorAnnotation]
+	if !found {
+		return nil, nil
+	}
+	return labels.Parse(v)
+}
+
+// RequiredTopology returns the node label keys declared for the pod set
+// through the kueue.x-k8s.io/podset-required-topology annotation.
+func RequiredTopology(ps *kueue.PodSet) ([]string, error) {
+	v, found := ps.Template.Annotations[kueue.PodSetRequiredTopologyAnnotation]
+	if !found {
+		return nil, nil
+	}
+	var keys []string
+	for _, key := range strings.Split(v, ",") {
+		key = strings.TrimSpace(key)
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ResourceClaimClasses returns the device classes declared for the resource
+// claims of the pod set through the kueue.x-k8s.io/resource-claim-classes
+// annotation, keyed by claim name.
+func ResourceClaimClasses(ps *kueue.PodSet) (map[string]string, error) {
+	v, found := ps.Template.Annotations[kueue.PodSetResourceClaimClassesAnnotation]
+	if !found {
+		return nil, nil
+	}
+	classes := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		claim, class, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || claim == "" || class == "" {
+			return nil, fmt.Errorf("malformed claim class %q, expected <claim name>=<device class>", pair)
+		}
+		if _, duplicated := classes[claim]; duplicated {
+			return nil, fmt.Errorf("duplicated claim %q", claim)
+		}
+		classes[claim] = class
+	}
+	return classes, nil
+}
+
 func Key(w *kueue.Workload) string {
 	return fmt.Sprintf("%s/%s", w.Namespace, w.Name)
 }
@@ -302,6 +463,9 @@ func totalRequestsFromPodSets(wl *kueue.Workload) []PodSetResources {
 			Count: count,
 		}
 		setRes.Requests = newRequests(limitrange.TotalRequests(&ps.Template.Spec))
+		if features.Enabled(features.DynamicResourceAllocation) {
+			setRes.Requests.addResourceClaims(&ps)
+		}
 		setRes.Requests.scaleUp(int64(count))
 		res = append(res, setRes)
 	}
@@ -323,7 +487,10 @@ func totalRequestsFromAdmission(wl *kueue.Workload) []PodSetResources {
 			Requests: newRequests(psa.ResourceUsage),
 		}
 
-		if count := currentCounts[psa.Name]; count != setRes.Count {
+		// The admitted count can be lower than the spec count, when the pod set
+		// was partially admitted or is an optional pod set that was dropped,
+		// only scale down when additional pods are marked as reclaimable.
+		if count := currentCounts[psa.Name]; count < setRes.Count {
 			setRes.Requests.scaleDown(int64(setRes.Count))
 			setRes.Requests.scaleUp(int64(count))
 			setRes.Count = count
@@ -348,6 +515,20 @@ func newRequests(rl corev1.ResourceList) Requests {
 	return r
 }
 
+// addResourceClaims counts one unit of the device class of each of the pod
+// resource claims with a declared class.
+func (r Requests) addResourceClaims(ps *kueue.PodSet) {
+	classes, err := ResourceClaimClasses(ps)
+	if err != nil {
+		return
+	}
+	for _, claim := range ps.Template.Spec.ResourceClaims {
+		if class, found := classes[claim.Name]; found {
+			r[corev1.ResourceName(class)]++
+		}
+	}
+}
+
 func (r Requests) ToResourceList() corev1.ResourceList {
 	ret := make(corev1.ResourceList, len(r))
 	for k, v := range r {
@@ -428,6 +609,7 @@ func UnsetQuotaReservationWithCondition(wl *kueue.Workload, reason, message stri
 	}
 	changed := apimeta.SetStatusCondition(&wl.Status.Conditions, condition)
 	if wl.Status.Admission != nil {
+		wl.Status.PreviousFlavors = assignedFlavors(wl.Status.Admission)
 		wl.Status.Admission = nil
 		changed = true
 	}
@@ -439,6 +621,33 @@ func UnsetQuotaReservationWithCondition(wl *kueue.Workload, reason, message stri
 	return changed
 }
 
+// assignedFlavors returns the flavors assigned to the resources of every
+// podSet in the admission.
+func assignedFlavors(admission *kueue.Admission) []kueue.PodSetFlavors {
+	var flavors []kueue.PodSetFlavors
+	for _, psa := range admission.PodSetAssignments {
+		if len(psa.Flavors) == 0 {
+			continue
+		}
+		flavors = append(flavors, kueue.PodSetFlavors{
+			Name:    psa.Name,
+			Flavors: maps.Clone(psa.Flavors),
+		})
+	}
+	return flavors
+}
+
+// PreviousFlavor returns the flavor assigned to the resource of the podSet
+// before the workload lost its quota reservation, if any.
+func PreviousFlavor(wl *kueue.Workload, psName string, rName corev1.ResourceName) kueue.ResourceFlavorReference {
+	for _, psf := range wl.Status.PreviousFlavors {
+		if psf.Name == psName {
+			return psf.Flavors[rName]
+		}
+	}
+	return ""
+}
+
 // SetRequeuedCondition sets the WorkloadRequeued condition to true
 func SetRequeuedCondition(wl *kueue.Workload, reason, message string, status bool) {
 	condition := metav1.Condition{
@@ -485,10 +694,41 @@ func BaseSSAWorkload(w *kueue.Workload) *kueue.Workload {
 	return wlCopy
 }
 
-// SetQuotaReservation applies the provided admission to the workload.
+// AdmittedEventMessage returns the message of the event recorded when the
+// workload is admitted, including the flavors assigned to every podSet.
+func AdmittedEventMessage(admission *kueue.Admission, waitTime time.Duration) string {
+	var flavorsMsg string
+	if flavors := AssignedFlavorsMessage(admission.PodSetAssignments); flavors != "" {
+		flavorsMsg = fmt.Sprintf(" (%s)", flavors)
+	}
+	return api.TruncateEventMessage(fmt.Sprintf("Admitted by ClusterQueue %v%s, wait time since reservation was %.0fs", admission.ClusterQueue, flavorsMsg, waitTime.Seconds()))
+}
+
+// AssignedFlavorsMessage returns a human readable list of the flavors
+// assigned to the resources of every podSet.
+func AssignedFlavorsMessage(assignments []kueue.PodSetAssignment) string {
+	var flavors []string
+	for _, psa := range assignments {
+		if len(psa.Flavors) == 0 {
+			continue
+		}
+		resFlavors := make([]string, 0, len(psa.Flavors))
+		resNames := utilmaps.Keys(psa.Flavors)
+		slices.Sort(resNames)
+		for _, r := range resNames {
+			resFlavors = append(resFlavors, fmt.Sprintf("%s=%s", r, psa.Flavors[r]))
+		}
+		flavors = append(flavors, fmt.Sprintf("%s: %s", psa.Name, strings.Join(resFlavors, ", ")))
+	}
+	return strings.Join(flavors, "; ")
+}
+
+// SetQuotaReservation applies the provided admission to the workload and
+// counts the admission attempt.
 // The WorkloadAdmitted and WorkloadEvicted are added or updated if necessary.
 func SetQuotaReservation(w *kueue.Workload, admission *kueue.Admission) {
 	w.Status.Admission = admission
+	w.Status.AdmissionAttempts++
 	message := fmt.Sprintf("Quota reserved in ClusterQueue %s", w.Status.Admission.ClusterQueue)
 	admittedCond := metav1.Condition{
 		Type:               kueue.WorkloadQuotaReserved,
@@ -499,15 +739,16 @@ func SetQuotaReservation(w *kueue.Workload, admission *kueue.Admission) {
 	}
 	apimeta.SetStatusCondition(&w.Status.Conditions, admittedCond)
 
-	// reset Evicted condition if present.
-	if evictedCond := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadEvicted); evictedCond != nil {
+	// reset Evicted condition if present. The conditions that are already
+	// false keep the time of their last transition.
+	if evictedCond := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadEvicted); evictedCond != nil && evictedCond.Status == metav1.ConditionTrue {
 		evictedCond.Status = metav1.ConditionFalse
 		evictedCond.Reason = "QuotaReserved"
 		evictedCond.Message = api.TruncateConditionMessage("Previously: " + evictedCond.Message)
 		evictedCond.LastTransitionTime = metav1.Now()
 	}
 	// reset Preempted condition if present.
-	if preemptedCond := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadPreempted); preemptedCond != nil {
+	if preemptedCond := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadPreempted); preemptedCond != nil && preemptedCond.Status == metav1.ConditionTrue {
 		preemptedCond.Status = metav1.ConditionFalse
 		preemptedCond.Reason = "QuotaReserved"
 		preemptedCond.Message = api.TruncateConditionMessage("Previously: " + preemptedCond.Message)
@@ -554,6 +795,13 @@ func admissionPatch(w *kueue.Workload) *kueue.Workload {
 
 	wlCopy.Status.Admission = w.Status.Admission.DeepCopy()
 	wlCopy.Status.RequeueState = w.Status.RequeueState.DeepCopy()
+	wlCopy.Status.AdmissionAttempts = w.Status.AdmissionAttempts
+	for i := range w.Status.CandidateFlavors {
+		wlCopy.Status.CandidateFlavors = append(wlCopy.Status.CandidateFlavors, *w.Status.CandidateFlavors[i].DeepCopy())
+	}
+	for i := range w.Status.PreviousFlavors {
+		wlCopy.Status.PreviousFlavors = append(wlCopy.Status.PreviousFlavors, *w.Status.PreviousFlavors[i].DeepCopy())
+	}
 	for _, conditionName := range admissionManagedConditions {
 		if existing := apimeta.FindStatusCondition(w.Status.Conditions, conditionName); existing != nil {
 			wlCopy.Status.Conditions = append(wlCopy.Status.Conditions, *existing.DeepCopy())
@@ -575,6 +823,8 @@ func ApplyAdmissionStatus(ctx context.Context, c client.Client, w *kueue.Workloa
 
 type Ordering struct {
 	PodsReadyRequeuingTimestamp config.RequeuingTimestamp
+	// SizeOrdering breaks the ties between workloads with the same timestamp.
+	SizeOrdering kueue.WorkloadSizeOrdering
 }
 
 // GetQueueOrderTimestamp return the timestamp to be used by the scheduler. It could
@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -32,15 +33,17 @@ import (
 
 	config "sigs.k8s.io/kueue/apis/config/v1beta1"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	"sigs.k8s.io/kueue/pkg/features"
 	utilac "sigs.k8s.io/kueue/pkg/util/admissioncheck"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
 )
 
 func TestNewInfo(t *testing.T) {
 	cases := map[string]struct {
-		workload    kueue.Workload
-		infoOptions []InfoOption
-		wantInfo    Info
+		workload                        kueue.Workload
+		infoOptions                     []InfoOption
+		enableDynamicResourceAllocation bool
+		wantInfo                        Info
 	}{
 		"pending": {
 			workload: *utiltesting.MakeWorkload("", "").
@@ -211,9 +214,57 @@ func TestNewInfo(t *testing.T) {
 				},
 			},
 		},
+		"pending with resource claims": {
+			workload: *utiltesting.MakeWorkload("", "").
+				PodSets(
+					*utiltesting.MakePodSet("main", 3).
+						Request(corev1.ResourceCPU, "10m").
+						ResourceClaim("gpu-a", "gpu.example.com").
+						ResourceClaim("gpu-b", "gpu.example.com").
+						ResourceClaim("nic", "nic.example.com").
+						Obj(),
+				).
+				Obj(),
+			enableDynamicResourceAllocation: true,
+			wantInfo: Info{
+				TotalRequests: []PodSetResources{
+					{
+						Name: "main",
+						Requests: Requests{
+							corev1.ResourceCPU: 3 * 10,
+							"gpu.example.com":  3 * 2,
+							"nic.example.com":  3 * 1,
+						},
+						Count: 3,
+					},
+				},
+			},
+		},
+		"pending with resource claims, feature disabled": {
+			workload: *utiltesting.MakeWorkload("", "").
+				PodSets(
+					*utiltesting.MakePodSet("main", 3).
+						Request(corev1.ResourceCPU, "10m").
+						ResourceClaim("gpu", "gpu.example.com").
+						Obj(),
+				).
+				Obj(),
+			wantInfo: Info{
+				TotalRequests: []PodSetResources{
+					{
+						Name: "main",
+						Requests: Requests{
+							corev1.ResourceCPU: 3 * 10,
+						},
+						Count: 3,
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
+			defer features.SetFeatureGateDuringTest(t, features.DynamicResourceAllocation, tc.enableDynamicResourceAllocation)()
 			info := NewInfo(&tc.workload, tc.infoOptions...)
 			if diff := cmp.Diff(info, &tc.wantInfo, cmpopts.IgnoreFields(Info{}, "Obj")); diff != "" {
 				t.Errorf("NewInfo(_) = (-want,+got):\n%s", diff)
@@ -756,3 +807,202 @@ func TestAdmissionCheckStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestUnsetQuotaReservationRecordsPreviousFlavors(t *testing.T) {
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		ReserveQuota(utiltesting.MakeAdmission("cq").
+			PodSets(
+				kueue.PodSetAssignment{
+					Name: "driver",
+					Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+						corev1.ResourceCPU: "on-demand",
+					},
+				},
+				kueue.PodSetAssignment{
+					Name: "workers",
+					Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+						corev1.ResourceCPU: "spot",
+						"example.com/gpu":  "spot",
+					},
+				},
+			).Obj()).
+		Obj()
+	if !UnsetQuotaReservationWithCondition(wl, "Pending", "Evicted") {
+		t.Fatal("UnsetQuotaReservationWithCondition didn't change the workload")
+	}
+	wantFlavors := []kueue.PodSetFlavors{
+		{
+			Name: "driver",
+			Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+				corev1.ResourceCPU: "on-demand",
+			},
+		},
+		{
+			Name: "workers",
+			Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+				corev1.ResourceCPU: "spot",
+				"example.com/gpu":  "spot",
+			},
+		},
+	}
+	if diff := cmp.Diff(wantFlavors, wl.Status.PreviousFlavors); diff != "" {
+		t.Errorf("Unexpected previous flavors (-want,+got):\n%s", diff)
+	}
+	if got := PreviousFlavor(wl, "workers", "example.com/gpu"); got != "spot" {
+		t.Errorf("Unexpected previous flavor for workers, want=spot, got=%s", got)
+	}
+	if got := PreviousFlavor(wl, "launcher", corev1.ResourceCPU); got != "" {
+		t.Errorf("Unexpected previous flavor for an unknown podSet, got=%s", got)
+	}
+}
+
+func TestConditionsBoundedAcrossAdmissionCycles(t *testing.T) {
+	wl := utiltesting.MakeWorkload("wl", "ns").Obj()
+	admission := utiltesting.MakeAdmission("cq").Obj()
+	for i := 0; i < 10; i++ {
+		SetQuotaReservation(wl, admission)
+		SyncAdmittedCondition(wl)
+		SetEvictedCondition(wl, kueue.WorkloadEvictedByPreemption, "Preempted")
+		UnsetQuotaReservationWithCondition(wl, "Pending", "Evicted")
+		SetRequeuedCondition(wl, kueue.WorkloadEvictedByPreemption, "Requeued", true)
+	}
+	SetQuotaReservation(wl, admission)
+
+	wantConditions := []metav1.Condition{
+		{Type: kueue.WorkloadQuotaReserved, Status: metav1.ConditionTrue, Reason: "QuotaReserved", Message: "Quota reserved in ClusterQueue cq"},
+		{Type: kueue.WorkloadAdmitted, Status: metav1.ConditionFalse, Reason: "NoReservation", Message: "The workload has no reservation"},
+		{Type: kueue.WorkloadEvicted, Status: metav1.ConditionFalse, Reason: "QuotaReserved", Message: "Previously: Preempted"},
+		{Type: kueue.WorkloadRequeued, Status: metav1.ConditionTrue, Reason: kueue.WorkloadEvictedByPreemption, Message: "Requeued"},
+	}
+	if diff := cmp.Diff(wantConditions, wl.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"), cmpopts.SortSlices(func(a, b metav1.Condition) bool { return a.Type < b.Type })); diff != "" {
+		t.Errorf("Unexpected conditions after repeated admission cycles (-want,+got):\n%s", diff)
+	}
+}
+
+func TestAdmittedEventMessage(t *testing.T) {
+	cases := map[string]struct {
+		admission *kueue.Admission
+		waitTime  time.Duration
+		want      string
+	}{
+		"no flavors": {
+			admission: utiltesting.MakeAdmission("cq").Obj(),
+			want:      "Admitted by ClusterQueue cq, wait time since reservation was 0s",
+		},
+		"multiple podSets": {
+			admission: utiltesting.MakeAdmission("cq").
+				PodSets(
+					kueue.PodSetAssignment{
+						Name: "driver",
+						Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+							corev1.ResourceMemory: "on-demand",
+							corev1.ResourceCPU:    "on-demand",
+						},
+					},
+					kueue.PodSetAssignment{
+						Name: "workers",
+						Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+							corev1.ResourceCPU: "spot",
+						},
+					},
+				).
+				Obj(),
+			waitTime: 5 * time.Second,
+			want:     "Admitted by ClusterQueue cq (driver: cpu=on-demand, memory=on-demand; workers: cpu=spot), wait time since reservation was 5s",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := AdmittedEventMessage(tc.admission, tc.waitTime); got != tc.want {
+				t.Errorf("Unexpected message, want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestScaleDownAdmission(t *testing.T) {
+	cases := map[string]struct {
+		workload      *kueue.Workload
+		counts        map[string]int32
+		wantCounts    map[string]int32
+		wantAdmission *kueue.Admission
+	}{
+		"no admission": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				PodSets(*utiltesting.MakePodSet("main", 4).SetMinimumCount(2).Request(corev1.ResourceCPU, "1").Obj()).
+				Obj(),
+		},
+		"no minCount": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				PodSets(*utiltesting.MakePodSet("main", 4).Request(corev1.ResourceCPU, "1").Obj()).
+				ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "4").AssignmentPodCount(4).Obj()).
+				Obj(),
+			wantAdmission: utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "4").AssignmentPodCount(4).Obj(),
+		},
+		"already at minCount": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				PodSets(*utiltesting.MakePodSet("main", 4).SetMinimumCount(2).Request(corev1.ResourceCPU, "1").Obj()).
+				ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "2").AssignmentPodCount(2).Obj()).
+				Obj(),
+			wantAdmission: utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "2").AssignmentPodCount(2).Obj(),
+		},
+		"above minCount": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				PodSets(*utiltesting.MakePodSet("main", 4).SetMinimumCount(2).Request(corev1.ResourceCPU, "500m").Obj()).
+				ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1500m").AssignmentPodCount(3).Obj()).
+				Obj(),
+			counts:        map[string]int32{"main": 2},
+			wantCounts:    map[string]int32{"main": 2},
+			wantAdmission: utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1").AssignmentPodCount(2).Obj(),
+		},
+		"above minCount, partially": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				PodSets(*utiltesting.MakePodSet("main", 4).SetMinimumCount(2).Request(corev1.ResourceCPU, "500m").Obj()).
+				ReserveQuota(utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "2").AssignmentPodCount(4).Obj()).
+				Obj(),
+			counts:        map[string]int32{"main": 3},
+			wantCounts:    map[string]int32{"main": 2},
+			wantAdmission: utiltesting.MakeAdmission("cq").Assignment(corev1.ResourceCPU, "default", "1500m").AssignmentPodCount(3).Obj(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.wantCounts, ScaleDownCounts(tc.workload)); diff != "" {
+				t.Errorf("Unexpected scale down counts (-want,+got):\n%s", diff)
+			}
+			ScaleDownAdmission(tc.workload, tc.counts)
+			if diff := cmp.Diff(tc.wantAdmission, tc.workload.Status.Admission, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected admission (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetQuotaReservationAcrossAdmissionCycles(t *testing.T) {
+	wl := utiltesting.MakeWorkload("wl", "ns").Obj()
+	admission := utiltesting.MakeAdmission("cq").Obj()
+	SetQuotaReservation(wl, admission)
+	SetEvictedCondition(wl, kueue.WorkloadEvictedByPreemption, "Preempted")
+	UnsetQuotaReservationWithCondition(wl, "Pending", "Evicted")
+	SetQuotaReservation(wl, admission)
+
+	evictedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadEvicted)
+	past := metav1.NewTime(evictedCond.LastTransitionTime.Add(-time.Hour))
+	evictedCond.LastTransitionTime = past
+	UnsetQuotaReservationWithCondition(wl, "Pending", "Deactivated")
+	SetQuotaReservation(wl, admission)
+
+	if wl.Status.AdmissionAttempts != 3 {
+		t.Errorf("Unexpected admission attempts, want=3, got=%d", wl.Status.AdmissionAttempts)
+	}
+	wantEvictedCond := &metav1.Condition{
+		Type:               kueue.WorkloadEvicted,
+		Status:             metav1.ConditionFalse,
+		Reason:             "QuotaReserved",
+		Message:            "Previously: Preempted",
+		LastTransitionTime: past,
+	}
+	if diff := cmp.Diff(wantEvictedCond, apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadEvicted)); diff != "" {
+		t.Errorf("Unexpected Evicted condition, it shouldn't change when it's already false (-want,+got):\n%s", diff)
+	}
+}
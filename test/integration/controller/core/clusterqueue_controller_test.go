@@ -204,7 +204,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 						Message: "Can't admit new workloads: FlavorNotFound",
 					},
 				},
-			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus))
+			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus, util.IgnoreAvailableQuota))
 			// Workloads are inadmissible because ResourceFlavors don't exist here yet.
 			util.ExpectPendingWorkloadsMetric(clusterQueue, 0, 5)
 			util.ExpectReservingActiveWorkloadsMetric(clusterQueue, 0)
@@ -294,7 +294,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 						Message: "Can admit new workloads",
 					},
 				},
-			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus))
+			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus, util.IgnoreAvailableQuota))
 			util.ExpectPendingWorkloadsMetric(clusterQueue, 1, 0)
 			util.ExpectReservingActiveWorkloadsMetric(clusterQueue, 4)
 
@@ -303,6 +303,11 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 				util.ExpectCQResourceReservations(clusterQueue, flavorSpot, string(corev1.ResourceCPU), 1)
 				util.ExpectCQResourceReservations(clusterQueue, flavorModelA, string(resourceGPU), 5)
 				util.ExpectCQResourceReservations(clusterQueue, flavorModelB, string(resourceGPU), 2)
+
+				util.ExpectReservedResourcesMetric(clusterQueue, flavorOnDemand, string(corev1.ResourceCPU), 6)
+				util.ExpectReservedResourcesMetric(clusterQueue, flavorSpot, string(corev1.ResourceCPU), 1)
+				util.ExpectReservedResourcesMetric(clusterQueue, flavorModelA, string(resourceGPU), 5)
+				util.ExpectReservedResourcesMetric(clusterQueue, flavorModelB, string(resourceGPU), 2)
 			})
 
 			ginkgo.By("Setting the admission check for the first 4 workloads")
@@ -328,7 +333,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 						Message: "Can admit new workloads",
 					},
 				},
-			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus))
+			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus, util.IgnoreAvailableQuota))
 			util.ExpectPendingWorkloadsMetric(clusterQueue, 1, 0)
 			util.ExpectReservingActiveWorkloadsMetric(clusterQueue, 4)
 
@@ -337,6 +342,11 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 				util.ExpectCQResourceReservations(clusterQueue, flavorSpot, string(corev1.ResourceCPU), 1)
 				util.ExpectCQResourceReservations(clusterQueue, flavorModelA, string(resourceGPU), 5)
 				util.ExpectCQResourceReservations(clusterQueue, flavorModelB, string(resourceGPU), 2)
+
+				util.ExpectReservedResourcesMetric(clusterQueue, flavorOnDemand, string(corev1.ResourceCPU), 0)
+				util.ExpectReservedResourcesMetric(clusterQueue, flavorSpot, string(corev1.ResourceCPU), 0)
+				util.ExpectReservedResourcesMetric(clusterQueue, flavorModelA, string(resourceGPU), 0)
+				util.ExpectReservedResourcesMetric(clusterQueue, flavorModelB, string(resourceGPU), 0)
 			})
 
 			ginkgo.By("Finishing workloads")
@@ -356,7 +366,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 						Message: "Can admit new workloads",
 					},
 				},
-			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus))
+			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus, util.IgnoreAvailableQuota))
 			util.ExpectPendingWorkloadsMetric(clusterQueue, 0, 0)
 			util.ExpectReservingActiveWorkloadsMetric(clusterQueue, 0)
 		})
@@ -387,7 +397,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 						Message: "Can't admit new workloads: FlavorNotFound",
 					},
 				},
-			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus))
+			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus, util.IgnoreAvailableQuota))
 
 			util.ExpectPendingWorkloadsMetric(clusterQueue, 0, 1)
 			util.ExpectReservingActiveWorkloadsMetric(clusterQueue, 0)
@@ -413,7 +423,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 						Message: "Can't admit new workloads: FlavorNotFound",
 					},
 				},
-			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus))
+			}, util.IgnoreConditionTimestampsAndObservedGeneration, ignorePendingWorkloadsStatus, util.IgnoreAvailableQuota))
 			util.ExpectPendingWorkloadsMetric(clusterQueue, 0, 0)
 			util.ExpectReservingActiveWorkloadsMetric(clusterQueue, 0)
 		})
@@ -509,7 +519,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 						Name: resourceGPU,
 					}},
 				},
-			}, util.IgnoreConditionTimestamps))
+			}, util.IgnoreConditionTimestamps, util.IgnoreAvailableQuota))
 
 			ginkgo.By("Mark two workers as reclaimable", func() {
 				gomega.Expect(workload.UpdateReclaimablePods(ctx, k8sClient, wl, []kueue.ReclaimablePod{{Name: "workers", Count: 2}})).To(gomega.Succeed())
@@ -546,7 +556,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 							Name: resourceGPU,
 						}},
 					},
-				}, util.IgnoreConditionTimestamps))
+				}, util.IgnoreConditionTimestamps, util.IgnoreAvailableQuota))
 			})
 
 			ginkgo.By("Mark all workers and a driver as reclaimable", func() {
@@ -583,7 +593,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", ginkgo.Ordered, ginkgo.Contin
 							Name: resourceGPU,
 						}},
 					},
-				}, util.IgnoreConditionTimestamps))
+				}, util.IgnoreConditionTimestamps, util.IgnoreAvailableQuota))
 			})
 
 			ginkgo.By("Finishing workload", func() {
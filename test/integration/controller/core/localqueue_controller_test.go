@@ -102,7 +102,7 @@ var _ = ginkgo.Describe("Queue controller", ginkgo.Ordered, ginkgo.ContinueOnFai
 				Type:    kueue.LocalQueueActive,
 				Status:  metav1.ConditionFalse,
 				Reason:  "ClusterQueueDoesNotExist",
-				Message: "Can't submit new workloads to clusterQueue",
+				Message: "ClusterQueue cluster-queue.queue-controller doesn't exist",
 			},
 		}, util.IgnoreConditionTimestampsAndObservedGeneration))
 
@@ -117,7 +117,7 @@ var _ = ginkgo.Describe("Queue controller", ginkgo.Ordered, ginkgo.ContinueOnFai
 				Type:    kueue.LocalQueueActive,
 				Status:  metav1.ConditionFalse,
 				Reason:  "ClusterQueueIsInactive",
-				Message: "Can't submit new workloads to clusterQueue",
+				Message: "ClusterQueue cluster-queue.queue-controller is inactive: Can't admit new workloads: FlavorNotFound",
 			},
 		}, util.IgnoreConditionTimestampsAndObservedGeneration))
 
@@ -161,7 +161,7 @@ var _ = ginkgo.Describe("Queue controller", ginkgo.Ordered, ginkgo.ContinueOnFai
 				Type:    kueue.LocalQueueActive,
 				Status:  metav1.ConditionFalse,
 				Reason:  "ClusterQueueDoesNotExist",
-				Message: "Can't submit new workloads to clusterQueue",
+				Message: "ClusterQueue cluster-queue.queue-controller doesn't exist",
 			},
 		}, util.IgnoreConditionTimestampsAndObservedGeneration))
 	})
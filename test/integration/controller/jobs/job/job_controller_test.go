@@ -1248,6 +1248,70 @@ var _ = ginkgo.Describe("Job controller interacting with scheduler", ginkgo.Orde
 		util.ExpectReservingActiveWorkloadsMetric(prodClusterQ, 1)
 	})
 
+	ginkgo.It("Should only assign a tainted flavor to jobs that tolerate its taint", func() {
+		ginkgo.By("creating localQueues")
+		prodLocalQ = testing.MakeLocalQueue("prod-queue", ns.Name).ClusterQueue(prodClusterQ.Name).Obj()
+		gomega.Expect(k8sClient.Create(ctx, prodLocalQ)).Should(gomega.Succeed())
+		taintedClusterQ := testing.MakeClusterQueue("tainted-cq").
+			ResourceGroup(
+				*testing.MakeFlavorQuotas("spot-tainted").Resource(corev1.ResourceCPU, "5").Obj(),
+			).Obj()
+		gomega.Expect(k8sClient.Create(ctx, taintedClusterQ)).Should(gomega.Succeed())
+		ginkgo.DeferCleanup(func() {
+			util.ExpectClusterQueueToBeDeleted(ctx, k8sClient, taintedClusterQ, true)
+		})
+		taintedLocalQ := testing.MakeLocalQueue("tainted-queue", ns.Name).ClusterQueue(taintedClusterQ.Name).Obj()
+		gomega.Expect(k8sClient.Create(ctx, taintedLocalQ)).Should(gomega.Succeed())
+
+		ginkgo.By("checking a job that tolerates the taint starts in the tainted flavor")
+		tolerantJob := testingjob.MakeJob("tolerant-job", ns.Name).
+			Queue(prodLocalQ.Name).
+			Request(corev1.ResourceCPU, "2").
+			Toleration(corev1.Toleration{
+				Key:      instanceKey,
+				Operator: corev1.TolerationOpEqual,
+				Value:    "spot-tainted",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}).
+			Obj()
+		gomega.Expect(k8sClient.Create(ctx, tolerantJob)).Should(gomega.Succeed())
+		createdTolerantJob := &batchv1.Job{}
+		gomega.Eventually(func() *bool {
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(tolerantJob), createdTolerantJob)).Should(gomega.Succeed())
+			return createdTolerantJob.Spec.Suspend
+		}, util.Timeout, util.Interval).Should(gomega.Equal(ptr.To(false)))
+		gomega.Expect(createdTolerantJob.Spec.Template.Spec.NodeSelector[instanceKey]).Should(gomega.Equal(spotTaintedFlavor.Name))
+
+		ginkgo.By("checking a job that doesn't tolerate the taint skips the tainted flavor")
+		intolerantJob := testingjob.MakeJob("intolerant-job", ns.Name).Queue(prodLocalQ.Name).Request(corev1.ResourceCPU, "2").Obj()
+		gomega.Expect(k8sClient.Create(ctx, intolerantJob)).Should(gomega.Succeed())
+		createdIntolerantJob := &batchv1.Job{}
+		gomega.Eventually(func() *bool {
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(intolerantJob), createdIntolerantJob)).Should(gomega.Succeed())
+			return createdIntolerantJob.Spec.Suspend
+		}, util.Timeout, util.Interval).Should(gomega.Equal(ptr.To(false)))
+		gomega.Expect(createdIntolerantJob.Spec.Template.Spec.NodeSelector[instanceKey]).Should(gomega.Equal(onDemandFlavor.Name))
+
+		ginkgo.By("checking a job that doesn't tolerate the taint of the only flavor stays pending")
+		pendingJob := testingjob.MakeJob("pending-job", ns.Name).Queue(taintedLocalQ.Name).Request(corev1.ResourceCPU, "2").Obj()
+		gomega.Expect(k8sClient.Create(ctx, pendingJob)).Should(gomega.Succeed())
+		wlLookupKey := types.NamespacedName{Name: workloadjob.GetWorkloadNameForJob(pendingJob.Name, pendingJob.UID), Namespace: ns.Name}
+		createdWorkload := &kueue.Workload{}
+		gomega.Eventually(func(g gomega.Gomega) {
+			g.Expect(k8sClient.Get(ctx, wlLookupKey, createdWorkload)).Should(gomega.Succeed())
+			cond := apimeta.FindStatusCondition(createdWorkload.Status.Conditions, kueue.WorkloadQuotaReserved)
+			g.Expect(cond).NotTo(gomega.BeNil())
+			g.Expect(cond.Status).Should(gomega.Equal(metav1.ConditionFalse))
+			g.Expect(cond.Message).Should(gomega.ContainSubstring("untolerated taint"))
+		}, util.Timeout, util.Interval).Should(gomega.Succeed())
+		createdPendingJob := &batchv1.Job{}
+		gomega.Consistently(func() *bool {
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pendingJob), createdPendingJob)).Should(gomega.Succeed())
+			return createdPendingJob.Spec.Suspend
+		}, util.ConsistentDuration, util.Interval).Should(gomega.Equal(ptr.To(true)))
+		util.ExpectPendingWorkloadsMetric(taintedClusterQ, 0, 1)
+	})
+
 	ginkgo.It("Should unsuspend job iff localQueue is in the same namespace", func() {
 		ginkgo.By("create another namespace")
 		ns2 := &corev1.Namespace{
@@ -187,4 +187,52 @@ var _ = ginkgo.Describe("Job Webhook", func() {
 			gomega.Expect(k8sClient.Update(ctx, createdJob)).ShouldNot(gomega.Succeed())
 		})
 	})
+
+	ginkgo.When("with the default queue namespace label", ginkgo.Ordered, ginkgo.ContinueOnFailure, func() {
+		ginkgo.BeforeAll(func() {
+			fwk = &framework.Framework{
+				CRDPath:     crdPath,
+				WebhookPath: webhookPath,
+			}
+			cfg = fwk.Init()
+			ctx, k8sClient = fwk.RunManager(cfg, managerSetup(
+				jobframework.WithManageJobsWithoutQueueName(false),
+				jobframework.WithDefaultQueueNamespaceLabel(constants.DefaultQueueNamespaceLabel),
+			))
+		})
+		ginkgo.BeforeEach(func() {
+			ns = &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "job-",
+					Labels:       map[string]string{constants.DefaultQueueNamespaceLabel: "team-queue"},
+				},
+			}
+			gomega.Expect(k8sClient.Create(ctx, ns)).To(gomega.Succeed())
+		})
+		ginkgo.AfterEach(func() {
+			gomega.Expect(util.DeleteNamespace(ctx, k8sClient, ns)).To(gomega.Succeed())
+		})
+		ginkgo.AfterAll(func() {
+			fwk.Teardown()
+		})
+
+		ginkgo.It("should queue and suspend a Job created without a queue name", func() {
+			job := testingjob.MakeJob("job-without-queue-name", ns.Name).Suspend(false).Obj()
+			gomega.Expect(k8sClient.Create(ctx, job)).Should(gomega.Succeed())
+
+			createdJob := &batchv1.Job{}
+			gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, createdJob)).Should(gomega.Succeed())
+			gomega.Expect(createdJob.Labels).To(gomega.HaveKeyWithValue(constants.QueueLabel, "team-queue"))
+			gomega.Expect(createdJob.Spec.Suspend).To(gomega.Equal(ptr.To(true)))
+		})
+
+		ginkgo.It("should keep the queue name of a Job", func() {
+			job := testingjob.MakeJob("job-with-queue-name", ns.Name).Queue("other-queue").Obj()
+			gomega.Expect(k8sClient.Create(ctx, job)).Should(gomega.Succeed())
+
+			createdJob := &batchv1.Job{}
+			gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, createdJob)).Should(gomega.Succeed())
+			gomega.Expect(createdJob.Labels).To(gomega.HaveKeyWithValue(constants.QueueLabel, "other-queue"))
+		})
+	})
 })
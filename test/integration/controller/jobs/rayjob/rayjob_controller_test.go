@@ -39,6 +39,7 @@ import (
 	workloadrayjob "sigs.k8s.io/kueue/pkg/controller/jobs/rayjob"
 	"sigs.k8s.io/kueue/pkg/util/testing"
 	testingrayjob "sigs.k8s.io/kueue/pkg/util/testingjobs/rayjob"
+	"sigs.k8s.io/kueue/pkg/workload"
 	"sigs.k8s.io/kueue/test/integration/framework"
 	"sigs.k8s.io/kueue/test/util"
 )
@@ -593,6 +594,26 @@ var _ = ginkgo.Describe("Job controller interacting with scheduler", ginkgo.Orde
 		gomega.Expect(createdJob.Spec.RayClusterSpec.WorkerGroupSpecs[0].Template.Spec.NodeSelector[instanceKey]).Should(gomega.Equal(onDemandFlavor.Name))
 		util.ExpectPendingWorkloadsMetric(clusterQueue, 0, 0)
 		util.ExpectReservingActiveWorkloadsMetric(clusterQueue, 1)
+
+		ginkgo.By("checking a second no-fit dev job stays suspended while its workload is pending")
+		job2 := testingrayjob.MakeJob("dev-job2", ns.Name).Queue(localQueue.Name).
+			RequestHead(corev1.ResourceCPU, "3").
+			RequestWorkerGroup(corev1.ResourceCPU, "4").
+			Obj()
+		gomega.Expect(k8sClient.Create(ctx, job2)).Should(gomega.Succeed())
+		setInitStatus(job2.Name, job2.Namespace)
+		createdJob2 := &rayv1.RayJob{}
+		gomega.Consistently(func() bool {
+			gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: job2.Name, Namespace: job2.Namespace}, createdJob2)).
+				Should(gomega.Succeed())
+			return createdJob2.Spec.Suspend
+		}, util.ConsistentDuration, util.Interval).Should(gomega.BeTrue())
+		wl2 := &kueue.Workload{}
+		wl2LookupKey := types.NamespacedName{Name: workloadrayjob.GetWorkloadNameForRayJob(job2.Name, createdJob2.UID), Namespace: ns.Name}
+		gomega.Expect(k8sClient.Get(ctx, wl2LookupKey, wl2)).Should(gomega.Succeed())
+		gomega.Expect(workload.HasQuotaReservation(wl2)).Should(gomega.BeFalse())
+		util.ExpectPendingWorkloadsMetric(clusterQueue, 0, 1)
+		util.ExpectReservingActiveWorkloadsMetric(clusterQueue, 1)
 	})
 })
 
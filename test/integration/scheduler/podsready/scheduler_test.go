@@ -351,8 +351,9 @@ var _ = ginkgo.Describe("SchedulerWithWaitForPodsReady", func() {
 				FlavorsReservation: []kueue.FlavorUsage{{
 					Name: "default",
 					Resources: []kueue.ResourceUsage{{
-						Name:  corev1.ResourceCPU,
-						Total: resource.MustParse("2"),
+						Name:      corev1.ResourceCPU,
+						Total:     resource.MustParse("2"),
+						Available: ptr.To(resource.MustParse("8")),
 					}},
 				}},
 				FlavorsUsage: []kueue.FlavorUsage{{
@@ -377,6 +378,8 @@ var _ = ginkgo.Describe("SchedulerWithWaitForPodsReady", func() {
 			}, util.Timeout, util.Interval).Should(gomega.BeNil())
 
 			ginkgo.By("verify the queue resources are freed")
+			// The 'dev' workload can be admitted at any time now, reducing the
+			// quota that the cohort can lend, so the available quota is ignored.
 			gomega.Eventually(func() kueue.ClusterQueueStatus {
 				var updatedCQ kueue.ClusterQueue
 				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(prodClusterQ), &updatedCQ)).To(gomega.Succeed())
@@ -399,7 +402,7 @@ var _ = ginkgo.Describe("SchedulerWithWaitForPodsReady", func() {
 						Total: resource.MustParse("0"),
 					}},
 				}},
-			}, ignoreCQConditions, ignorePendingWorkloadsStatus))
+			}, ignoreCQConditions, ignorePendingWorkloadsStatus, util.IgnoreAvailableQuota))
 
 			ginkgo.By("verify the active workload metric is decreased for the cluster queue")
 			util.ExpectReservingActiveWorkloadsMetric(prodClusterQ, 0)
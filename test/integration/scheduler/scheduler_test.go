@@ -237,6 +237,7 @@ var _ = ginkgo.Describe("Scheduler", func() {
 			util.ExpectReservingActiveWorkloadsMetric(prodClusterQ, 1)
 			util.ExpectQuotaReservedWorkloadsTotalMetric(prodClusterQ, 1)
 			util.ExpectAdmittedWorkloadsTotalMetric(prodClusterQ, 1)
+			util.ExpectAdmissionWaitTimeMetric(prodClusterQ, 1)
 
 			ginkgo.By("checking a second no-fit workload does not get admitted")
 			prodWl2 := testing.MakeWorkload("prod-wl2", ns.Name).Queue(prodQueue.Name).Request(corev1.ResourceCPU, "5").Obj()
@@ -272,6 +273,7 @@ var _ = ginkgo.Describe("Scheduler", func() {
 				util.ExpectReservingActiveWorkloadsMetric(prodClusterQ, 2)
 				util.ExpectQuotaReservedWorkloadsTotalMetric(prodClusterQ, 2)
 				util.ExpectAdmittedWorkloadsTotalMetric(prodClusterQ, 2)
+				util.ExpectAdmissionWaitTimeMetric(prodClusterQ, 2)
 			})
 
 			ginkgo.By("finishing the empty workload", func() {
@@ -287,6 +289,7 @@ var _ = ginkgo.Describe("Scheduler", func() {
 			util.ExpectReservingActiveWorkloadsMetric(devClusterQ, 1)
 			util.ExpectQuotaReservedWorkloadsTotalMetric(devClusterQ, 1)
 			util.ExpectAdmittedWorkloadsTotalMetric(devClusterQ, 1)
+			util.ExpectAdmissionWaitTimeMetric(devClusterQ, 1)
 
 			ginkgo.By("checking the second workload gets admitted when the first workload finishes")
 			util.FinishWorkloads(ctx, k8sClient, prodWl1)
@@ -298,6 +301,44 @@ var _ = ginkgo.Describe("Scheduler", func() {
 			util.ExpectAdmittedWorkloadsTotalMetric(prodClusterQ, 3)
 		})
 
+		ginkgo.It("Should assign flavors to each podSet independently", func() {
+			wl := testing.MakeWorkload("multi-podset-wl", ns.Name).
+				Queue(devQueue.Name).
+				PodSets(
+					*testing.MakePodSet("driver", 1).Request(corev1.ResourceCPU, "3").Obj(),
+					*testing.MakePodSet("workers", 2).Request(corev1.ResourceCPU, "2").Obj(),
+				).
+				Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl)).Should(gomega.Succeed())
+
+			ginkgo.By("checking the podSets get different flavors", func() {
+				wlAdmission := testing.MakeAdmission(devClusterQ.Name).PodSets(
+					kueue.PodSetAssignment{
+						Name: "driver",
+						Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+							corev1.ResourceCPU: "spot-untainted",
+						},
+						ResourceUsage: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("3"),
+						},
+						Count: ptr.To[int32](1),
+					},
+					kueue.PodSetAssignment{
+						Name: "workers",
+						Flavors: map[corev1.ResourceName]kueue.ResourceFlavorReference{
+							corev1.ResourceCPU: "on-demand",
+						},
+						ResourceUsage: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+						Count: ptr.To[int32](2),
+					}).Obj()
+				util.ExpectWorkloadToBeAdmittedAs(ctx, k8sClient, wl, wlAdmission)
+				util.ExpectPendingWorkloadsMetric(devClusterQ, 0, 0)
+				util.ExpectReservingActiveWorkloadsMetric(devClusterQ, 1)
+			})
+		})
+
 		ginkgo.It("Should admit workloads as number of pods allows it", func() {
 			wl1 := testing.MakeWorkload("wl1", ns.Name).
 				Queue(podsCountQueue.Name).
@@ -930,6 +971,43 @@ var _ = ginkgo.Describe("Scheduler", func() {
 			util.ExpectQuotaReservedWorkloadsTotalMetric(fooCQ, 1)
 			util.ExpectAdmittedWorkloadsTotalMetric(fooCQ, 1)
 		})
+
+		ginkgo.It("Should unfreeze the workloads of all the ClusterQueues using the flavor once it is created", func() {
+			barCQ := testing.MakeClusterQueue("bar-cq").
+				ResourceGroup(*testing.MakeFlavorQuotas("foo-flavor").Resource(corev1.ResourceCPU, "5").Obj()).
+				Obj()
+			gomega.Expect(k8sClient.Create(ctx, barCQ)).Should(gomega.Succeed())
+			defer func() {
+				util.ExpectClusterQueueToBeDeleted(ctx, k8sClient, barCQ, true)
+			}()
+			barQ := testing.MakeLocalQueue("bar-queue", ns.Name).ClusterQueue(barCQ.Name).Obj()
+			gomega.Expect(k8sClient.Create(ctx, barQ)).Should(gomega.Succeed())
+
+			ginkgo.By("Creating one workload in each ClusterQueue")
+			fooWl := testing.MakeWorkload("foo-workload", ns.Name).Queue(fooQ.Name).Request(corev1.ResourceCPU, "1").Obj()
+			gomega.Expect(k8sClient.Create(ctx, fooWl)).Should(gomega.Succeed())
+			barWl := testing.MakeWorkload("bar-workload", ns.Name).Queue(barQ.Name).Request(corev1.ResourceCPU, "1").Obj()
+			gomega.Expect(k8sClient.Create(ctx, barWl)).Should(gomega.Succeed())
+			defer func() {
+				gomega.Expect(util.DeleteWorkload(ctx, k8sClient, barWl)).To(gomega.Succeed())
+			}()
+			util.ExpectWorkloadsToBeFrozen(ctx, k8sClient, fooCQ.Name, fooWl)
+			util.ExpectWorkloadsToBeFrozen(ctx, k8sClient, barCQ.Name, barWl)
+			util.ExpectClusterQueueStatusMetric(barCQ, metrics.CQStatusPending)
+
+			ginkgo.By("Creating foo flavor")
+			fooFlavor := testing.MakeResourceFlavor("foo-flavor").Obj()
+			gomega.Expect(k8sClient.Create(ctx, fooFlavor)).Should(gomega.Succeed())
+			defer func() {
+				gomega.Expect(util.DeleteResourceFlavor(ctx, k8sClient, fooFlavor)).To(gomega.Succeed())
+			}()
+			util.ExpectClusterQueueStatusMetric(fooCQ, metrics.CQStatusActive)
+			util.ExpectClusterQueueStatusMetric(barCQ, metrics.CQStatusActive)
+			util.ExpectWorkloadsToHaveQuotaReservation(ctx, k8sClient, fooCQ.Name, fooWl)
+			util.ExpectWorkloadsToHaveQuotaReservation(ctx, k8sClient, barCQ.Name, barWl)
+			util.ExpectPendingWorkloadsMetric(barCQ, 0, 0)
+			util.ExpectReservingActiveWorkloadsMetric(barCQ, 1)
+		})
 	})
 
 	ginkgo.When("Using taints in resourceFlavors", func() {
@@ -995,6 +1073,37 @@ var _ = ginkgo.Describe("Scheduler", func() {
 			util.ExpectQuotaReservedWorkloadsTotalMetric(cq, 2)
 			util.ExpectAdmittedWorkloadsTotalMetric(cq, 2)
 		})
+
+		ginkgo.It("Should admit pending workloads once the flavor taints are removed", func() {
+			ginkgo.By("checking a workload without toleration starts on the non-tainted flavor")
+			wl1 := testing.MakeWorkload("on-demand-wl1", ns.Name).Queue(queue.Name).Request(corev1.ResourceCPU, "5").Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl1)).Should(gomega.Succeed())
+
+			expectAdmission := testing.MakeAdmission(cq.Name).Assignment(corev1.ResourceCPU, "on-demand", "5").Obj()
+			util.ExpectWorkloadToBeAdmittedAs(ctx, k8sClient, wl1, expectAdmission)
+
+			ginkgo.By("checking a second workload without toleration doesn't start")
+			wl2 := testing.MakeWorkload("on-demand-wl2", ns.Name).Queue(queue.Name).Request(corev1.ResourceCPU, "5").Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl2)).Should(gomega.Succeed())
+			util.ExpectWorkloadsToBePending(ctx, k8sClient, wl2)
+			util.ExpectPendingWorkloadsMetric(cq, 0, 1)
+
+			ginkgo.By("removing the taints from the flavor")
+			gomega.Eventually(func() error {
+				var flavor kueue.ResourceFlavor
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(spotTaintedFlavor), &flavor); err != nil {
+					return err
+				}
+				flavor.Spec.NodeTaints = nil
+				return k8sClient.Update(ctx, &flavor)
+			}, util.Timeout, util.Interval).Should(gomega.Succeed())
+
+			ginkgo.By("checking the second workload starts on the no longer tainted flavor")
+			expectAdmission = testing.MakeAdmission(cq.Name).Assignment(corev1.ResourceCPU, "spot-tainted", "5").Obj()
+			util.ExpectWorkloadToBeAdmittedAs(ctx, k8sClient, wl2, expectAdmission)
+			util.ExpectPendingWorkloadsMetric(cq, 0, 0)
+			util.ExpectReservingActiveWorkloadsMetric(cq, 2)
+		})
 	})
 
 	ginkgo.When("Using affinity in resourceFlavors", func() {
@@ -1171,14 +1280,13 @@ var _ = ginkgo.Describe("Scheduler", func() {
 		})
 
 		ginkgo.It("Should schedule workloads borrowing quota from ClusterQueues in the same Cohort", func() {
-			prodCQ = testing.MakeClusterQueue("prod-cq").
-				Cohort("all").
+			cohort := testing.MakeCohort("all")
+			prodCQ = cohort.ClusterQueue("prod-cq").
 				ResourceGroup(*testing.MakeFlavorQuotas("on-demand").Resource(corev1.ResourceCPU, "5", "10").Obj()).
 				Obj()
 			gomega.Expect(k8sClient.Create(ctx, prodCQ)).Should(gomega.Succeed())
 
-			devCQ = testing.MakeClusterQueue("dev-cq").
-				Cohort("all").
+			devCQ = cohort.ClusterQueue("dev-cq").
 				ResourceGroup(*testing.MakeFlavorQuotas("on-demand").Resource(corev1.ResourceCPU, "5", "10").Obj()).
 				Obj()
 			gomega.Expect(k8sClient.Create(ctx, devCQ)).Should(gomega.Succeed())
@@ -1206,8 +1314,7 @@ var _ = ginkgo.Describe("Scheduler", func() {
 
 			// Delay cluster queue creation to make sure workloads are in the same
 			// scheduling cycle.
-			testCQ := testing.MakeClusterQueue("test-cq").
-				Cohort("all").
+			testCQ := cohort.ClusterQueue("test-cq").
 				ResourceGroup(*testing.MakeFlavorQuotas("on-demand").Resource(corev1.ResourceCPU, "15", "0").Obj()).
 				Obj()
 			gomega.Expect(k8sClient.Create(ctx, testCQ)).Should(gomega.Succeed())
@@ -1225,6 +1332,8 @@ var _ = ginkgo.Describe("Scheduler", func() {
 			util.ExpectAdmittedWorkloadsTotalMetric(prodCQ, 1)
 			util.ExpectQuotaReservedWorkloadsTotalMetric(devCQ, 1)
 			util.ExpectAdmittedWorkloadsTotalMetric(devCQ, 1)
+			util.ExpectClusterQueueBorrowing(ctx, k8sClient, prodCQ, "on-demand", corev1.ResourceCPU, "6")
+			util.ExpectClusterQueueBorrowing(ctx, k8sClient, devCQ, "on-demand", corev1.ResourceCPU, "6")
 		})
 
 		ginkgo.It("Should start workloads that are under min quota before borrowing", func() {
@@ -1360,6 +1469,43 @@ var _ = ginkgo.Describe("Scheduler", func() {
 			util.ExpectAdmittedWorkloadsTotalMetric(devCQ, 1)
 		})
 
+		ginkgo.It("Should borrow before trying next flavor", func() {
+			prodCQ = testing.MakeClusterQueue("prod-cq").
+				Cohort("all").
+				ResourceGroup(*testing.MakeFlavorQuotas("on-demand").Resource(corev1.ResourceCPU, "10", "10").Obj()).
+				Obj()
+			gomega.Expect(k8sClient.Create(ctx, prodCQ)).Should(gomega.Succeed())
+
+			devCQ = testing.MakeClusterQueue("dev-cq").
+				Cohort("all").
+				FlavorFungibility(kueue.FlavorFungibility{WhenCanBorrow: kueue.Borrow}).
+				ResourceGroup(
+					*testing.MakeFlavorQuotas("on-demand").Resource(corev1.ResourceCPU, "10", "10").Obj(),
+					*testing.MakeFlavorQuotas("spot-tainted").Resource(corev1.ResourceCPU, "11").Obj()).
+				Obj()
+			gomega.Expect(k8sClient.Create(ctx, devCQ)).Should(gomega.Succeed())
+
+			prodQueue := testing.MakeLocalQueue("prod-queue", ns.Name).ClusterQueue(prodCQ.Name).Obj()
+			gomega.Expect(k8sClient.Create(ctx, prodQueue)).Should(gomega.Succeed())
+
+			devQueue := testing.MakeLocalQueue("dev-queue", ns.Name).ClusterQueue(devCQ.Name).Obj()
+			gomega.Expect(k8sClient.Create(ctx, devQueue)).Should(gomega.Succeed())
+
+			ginkgo.By("Creating one workload")
+			wl1 := testing.MakeWorkload("wl-1", ns.Name).Queue(prodQueue.Name).Request(corev1.ResourceCPU, "9").Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl1)).Should(gomega.Succeed())
+			util.ExpectWorkloadToBeAdmittedAs(ctx, k8sClient, wl1,
+				testing.MakeAdmission(prodCQ.Name).Assignment(corev1.ResourceCPU, "on-demand", "9").Obj())
+
+			ginkgo.By("Creating another workload that needs to borrow in the first flavor")
+			wl2 := testing.MakeWorkload("wl-2", ns.Name).Queue(devQueue.Name).Request(corev1.ResourceCPU, "11").Toleration(spotToleration).Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl2)).Should(gomega.Succeed())
+			util.ExpectWorkloadToBeAdmittedAs(ctx, k8sClient, wl2,
+				testing.MakeAdmission(devCQ.Name).Assignment(corev1.ResourceCPU, "on-demand", "11").Obj())
+			util.ExpectPendingWorkloadsMetric(devCQ, 0, 0)
+			util.ExpectReservingActiveWorkloadsMetric(devCQ, 1)
+		})
+
 		ginkgo.It("Should preempt before try next flavor", func() {
 			prodCQ = testing.MakeClusterQueue("prod-cq").
 				Cohort("all").
@@ -1772,13 +1918,14 @@ var _ = ginkgo.Describe("Scheduler", func() {
 		})
 
 		type testParams struct {
-			reqCPU           string
-			limitCPU         string
-			minCPU           string
-			maxCPU           string
-			limitType        corev1.LimitType
-			wantedStatus     string
-			shouldBeAdmitted bool
+			reqCPU            string
+			limitCPU          string
+			minCPU            string
+			maxCPU            string
+			defaultRequestCPU string
+			limitType         corev1.LimitType
+			wantedStatus      string
+			shouldBeAdmitted  bool
 		}
 
 		ginkgo.DescribeTable("", func(tp testParams) {
@@ -1787,10 +1934,13 @@ var _ = ginkgo.Describe("Scheduler", func() {
 				lrBuilder.WithType(tp.limitType)
 			}
 			if tp.maxCPU != "" {
-				lrBuilder.WithValue("Max", corev1.ResourceCPU, tp.maxCPU)
+				lrBuilder.Max(corev1.ResourceCPU, tp.maxCPU)
 			}
 			if tp.minCPU != "" {
-				lrBuilder.WithValue("Min", corev1.ResourceCPU, tp.minCPU)
+				lrBuilder.Min(corev1.ResourceCPU, tp.minCPU)
+			}
+			if tp.defaultRequestCPU != "" {
+				lrBuilder.DefaultRequest(corev1.ResourceCPU, tp.defaultRequestCPU)
 			}
 			lr := lrBuilder.Obj()
 			gomega.Expect(k8sClient.Create(ctx, lr)).To(gomega.Succeed())
@@ -1827,11 +1977,13 @@ var _ = ginkgo.Describe("Scheduler", func() {
 			gomega.Expect(k8sClient.Delete(ctx, lr)).To(gomega.Succeed())
 		},
 			ginkgo.Entry("request more that limits", testParams{reqCPU: "3", limitCPU: "2", wantedStatus: "resource validation failed:"}),
-			ginkgo.Entry("request over container limits", testParams{reqCPU: "2", limitCPU: "3", maxCPU: "1", wantedStatus: "didn't satisfy LimitRange constraints:"}),
+			ginkgo.Entry("request over container limits", testParams{reqCPU: "2", limitCPU: "3", maxCPU: "1", wantedStatus: "exceeds the LimitRange maximum:"}),
 			ginkgo.Entry("request under container limits", testParams{reqCPU: "2", limitCPU: "3", minCPU: "3", wantedStatus: "didn't satisfy LimitRange constraints:"}),
-			ginkgo.Entry("request over pod limits", testParams{reqCPU: "2", limitCPU: "3", maxCPU: "1", limitType: corev1.LimitTypePod, wantedStatus: "didn't satisfy LimitRange constraints:"}),
+			ginkgo.Entry("request over pod limits", testParams{reqCPU: "2", limitCPU: "3", maxCPU: "1", limitType: corev1.LimitTypePod, wantedStatus: "exceeds the LimitRange maximum:"}),
 			ginkgo.Entry("request under pod limits", testParams{reqCPU: "2", limitCPU: "3", minCPU: "3", limitType: corev1.LimitTypePod, wantedStatus: "didn't satisfy LimitRange constraints:"}),
 			ginkgo.Entry("valid", testParams{reqCPU: "2", limitCPU: "3", minCPU: "1", maxCPU: "4", shouldBeAdmitted: true}),
+			ginkgo.Entry("request defaulted by the limit range fits", testParams{defaultRequestCPU: "3", shouldBeAdmitted: true}),
+			ginkgo.Entry("request defaulted by the limit range exceeds the capacity", testParams{defaultRequestCPU: "6", wantedStatus: "more than the maximum capacity of 5 of any flavor"}),
 		)
 	})
 
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta1"
@@ -235,8 +236,9 @@ var _ = ginkgo.Describe("Workload controller with scheduler", func() {
 					FlavorsReservation: []kueue.FlavorUsage{{
 						Name: kueue.ResourceFlavorReference(onDemandFlavor.Name),
 						Resources: []kueue.ResourceUsage{{
-							Name:  corev1.ResourceCPU,
-							Total: resource.MustParse("2"),
+							Name:      corev1.ResourceCPU,
+							Total:     resource.MustParse("2"),
+							Available: ptr.To(resource.MustParse("3")),
 						}},
 					}},
 				}, ignoreCqCondition, ignoreInClusterQueueStatus))
@@ -291,8 +293,9 @@ var _ = ginkgo.Describe("Workload controller with scheduler", func() {
 					FlavorsReservation: []kueue.FlavorUsage{{
 						Name: kueue.ResourceFlavorReference(onDemandFlavor.Name),
 						Resources: []kueue.ResourceUsage{{
-							Name:  corev1.ResourceCPU,
-							Total: resource.MustParse("1"),
+							Name:      corev1.ResourceCPU,
+							Total:     resource.MustParse("1"),
+							Available: ptr.To(resource.MustParse("4")),
 						}},
 					}},
 				}, ignoreCqCondition, ignoreInClusterQueueStatus))
@@ -347,8 +350,9 @@ var _ = ginkgo.Describe("Workload controller with scheduler", func() {
 						Name: kueue.ResourceFlavorReference(onDemandFlavor.Name),
 						Resources: []kueue.ResourceUsage{
 							{
-								Name:  corev1.ResourceCPU,
-								Total: resource.MustParse("3"),
+								Name:      corev1.ResourceCPU,
+								Total:     resource.MustParse("3"),
+								Available: ptr.To(resource.MustParse("2")),
 							},
 						},
 					}},
@@ -389,8 +393,9 @@ var _ = ginkgo.Describe("Workload controller with scheduler", func() {
 						Name: kueue.ResourceFlavorReference(onDemandFlavor.Name),
 						Resources: []kueue.ResourceUsage{
 							{
-								Name:  corev1.ResourceCPU,
-								Total: resource.MustParse("1"),
+								Name:      corev1.ResourceCPU,
+								Total:     resource.MustParse("1"),
+								Available: ptr.To(resource.MustParse("4")),
 							},
 						},
 					}},
@@ -451,8 +456,9 @@ var _ = ginkgo.Describe("Workload controller with scheduler", func() {
 					FlavorsReservation: []kueue.FlavorUsage{{
 						Name: kueue.ResourceFlavorReference(onDemandFlavor.Name),
 						Resources: []kueue.ResourceUsage{{
-							Name:  corev1.ResourceCPU,
-							Total: resource.MustParse("1"),
+							Name:      corev1.ResourceCPU,
+							Total:     resource.MustParse("1"),
+							Available: ptr.To(resource.MustParse("4")),
 						}},
 					}},
 				}, ignoreCqCondition, ignoreInClusterQueueStatus))
@@ -555,8 +561,9 @@ var _ = ginkgo.Describe("Workload controller with scheduler", func() {
 					FlavorsReservation: []kueue.FlavorUsage{{
 						Name: kueue.ResourceFlavorReference(onDemandFlavor.Name),
 						Resources: []kueue.ResourceUsage{{
-							Name:  corev1.ResourceCPU,
-							Total: resource.MustParse("5"),
+							Name:      corev1.ResourceCPU,
+							Total:     resource.MustParse("5"),
+							Available: ptr.To(resource.MustParse("0")),
 						}},
 					}},
 				}, ignoreCqCondition, ignoreInClusterQueueStatus))
@@ -646,8 +653,9 @@ var _ = ginkgo.Describe("Workload controller with scheduler", func() {
 					FlavorsReservation: []kueue.FlavorUsage{{
 						Name: kueue.ResourceFlavorReference(onDemandFlavor.Name),
 						Resources: []kueue.ResourceUsage{{
-							Name:  corev1.ResourceCPU,
-							Total: resource.MustParse("5"),
+							Name:      corev1.ResourceCPU,
+							Total:     resource.MustParse("5"),
+							Available: ptr.To(resource.MustParse("0")),
 						}},
 					}},
 				}, ignoreCqCondition, ignoreInClusterQueueStatus))
@@ -681,8 +689,9 @@ var _ = ginkgo.Describe("Workload controller with scheduler", func() {
 					FlavorsReservation: []kueue.FlavorUsage{{
 						Name: kueue.ResourceFlavorReference(onDemandFlavor.Name),
 						Resources: []kueue.ResourceUsage{{
-							Name:  corev1.ResourceCPU,
-							Total: resource.MustParse("0"),
+							Name:      corev1.ResourceCPU,
+							Total:     resource.MustParse("0"),
+							Available: ptr.To(resource.MustParse("5")),
 						}},
 					}},
 				}, ignoreCqCondition, ignoreInClusterQueueStatus))
@@ -495,6 +495,15 @@ func ExpectAdmittedWorkloadsTotalMetric(cq *kueue.ClusterQueue, v int) {
 	}, Timeout, Interval).Should(gomega.Succeed())
 }
 
+func ExpectAdmissionWaitTimeMetric(cq *kueue.ClusterQueue, v int) {
+	metric := metrics.AdmissionWaitTime.WithLabelValues(cq.Name)
+	gomega.EventuallyWithOffset(1, func(g gomega.Gomega) {
+		count, err := testutil.GetHistogramMetricCount(metric)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(int(count)).Should(gomega.Equal(v))
+	}, Timeout, Interval).Should(gomega.Succeed())
+}
+
 func ExpectEvictedWorkloadsTotalMetric(cqName string, reason string, v int) {
 	metric := metrics.EvictedWorkloadsTotal.WithLabelValues(cqName, reason)
 	gomega.EventuallyWithOffset(1, func(g gomega.Gomega) {
@@ -586,6 +595,17 @@ func ExpectResourceFlavorToBeDeleted(ctx context.Context, k8sClient client.Clien
 	}, Timeout, Interval).Should(testing.BeNotFoundError())
 }
 
+// ExpectClusterQueueBorrowing waits until the ClusterQueue reports in its
+// status that it's borrowing the given quantity of the flavor and resource
+// from its cohort.
+func ExpectClusterQueueBorrowing(ctx context.Context, k8sClient client.Client, cq *kueue.ClusterQueue, flavor kueue.ResourceFlavorReference, resourceName corev1.ResourceName, quantity string) {
+	gomega.EventuallyWithOffset(1, func(g gomega.Gomega) {
+		var updatedCQ kueue.ClusterQueue
+		g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cq), &updatedCQ)).To(gomega.Succeed())
+		g.Expect(&updatedCQ).To(testing.BeBorrowing(flavor, resourceName, quantity))
+	}, Timeout, Interval).Should(gomega.Succeed())
+}
+
 func ExpectCQResourceNominalQuota(cq *kueue.ClusterQueue, flavor, resource string, v float64) {
 	metric := metrics.ClusterQueueResourceNominalQuota.WithLabelValues(cq.Spec.Cohort, cq.Name, flavor, resource)
 	gomega.EventuallyWithOffset(1, func() float64 {
@@ -613,6 +633,15 @@ func ExpectCQResourceReservations(cq *kueue.ClusterQueue, flavor, resource strin
 	}, Timeout, Interval).Should(gomega.Equal(v))
 }
 
+func ExpectReservedResourcesMetric(cq *kueue.ClusterQueue, flavor, resource string, v float64) {
+	metric := metrics.ClusterQueueReservedResources.WithLabelValues(cq.Spec.Cohort, cq.Name, flavor, resource)
+	gomega.EventuallyWithOffset(1, func() float64 {
+		v, err := testutil.GetGaugeMetricValue(metric)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		return v
+	}, Timeout, Interval).Should(gomega.Equal(v))
+}
+
 func SetQuotaReservation(ctx context.Context, k8sClient client.Client, wl *kueue.Workload, admission *kueue.Admission) error {
 	wl = wl.DeepCopy()
 	if admission == nil {